@@ -44,6 +44,7 @@ type ConfigIntegration = {
 
 type ConfigNetwork = {
   buddy_url?: string;
+  federation?: string[];
   machine_ip: string;
   peer_flags?: string;
   peer_url?: string;
@@ -93,6 +94,13 @@ type ConfigStores = {
 
 type ConfigTorz = {
   disabled: boolean;
+  metadata_fetch_concurrency?: number;
+  metadata_fetch_max_peers?: number;
+  metadata_fetch_timeout?: string;
+  scrape_interval?: string;
+  scrape_rate_limit?: string;
+  scrape_stale_time?: string;
+  scrape_trackers?: string[];
   torrent_file_cache_size?: string;
   torrent_file_cache_ttl?: string;
   torrent_file_max_size: string;
@@ -21,6 +21,7 @@ export type NZBInfoItem = {
   file_count: number;
   files: null | NZBContentFile[];
   hash: string;
+  health?: NZBInfoHealth;
   id: string;
   inspection_meta?: NZBInfoInspectionMeta;
   name: string;
@@ -33,6 +34,24 @@ export type NZBInfoItem = {
   user: string;
 };
 
+type NZBInfoHealth = {
+  completeness: number;
+  providers: Array<{
+    available: number;
+    checked: number;
+    error?: string;
+    id: string;
+    is_backup: boolean;
+  }>;
+  sample_count: number;
+  segment_count: number;
+  segments?: Array<{
+    a: boolean[];
+    f: number;
+    n: number;
+  }>;
+};
+
 type NZBInfoInspectionMeta = {
   duration_ms: number;
   error?: string;
@@ -0,0 +1,91 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type PeerToken = {
+  created_at: string;
+  expires_at: null | string;
+  id: string;
+  last_used_at: null | string;
+  name: string;
+  rate_limit_config_id: null | string;
+  scopes: PeerTokenScope[];
+  usage_count: number;
+};
+
+export type PeerTokenScope =
+  | "check-magnet-cache"
+  | "push-torrents"
+  | "read-torrents"
+  | "zilean";
+
+type SavePeerTokenParams = Pick<
+  PeerToken,
+  "expires_at" | "name" | "rate_limit_config_id" | "scopes"
+>;
+
+export function usePeerTokenMutation() {
+  const create = useMutation({
+    mutationFn: createPeerToken,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/peer-tokens"],
+      });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: async ({
+      id,
+      ...params
+    }: SavePeerTokenParams & { id: string }) => {
+      return updatePeerToken(id, params);
+    },
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/peer-tokens"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deletePeerToken,
+    onSuccess: async (_, id, __, ctx) => {
+      ctx.client.setQueryData<PeerToken[]>(["/peer-tokens"], (list) =>
+        list?.filter((item) => item.id !== id),
+      );
+    },
+  });
+
+  return { create, remove, update };
+}
+
+export function usePeerTokens() {
+  return useQuery({
+    queryFn: getPeerTokens,
+    queryKey: ["/peer-tokens"],
+  });
+}
+
+async function createPeerToken(params: SavePeerTokenParams) {
+  const { data } = await api<PeerToken>("POST /peer-tokens", {
+    body: params,
+  });
+  return data;
+}
+
+async function deletePeerToken(id: string) {
+  await api(`DELETE /peer-tokens/${id}`);
+}
+
+async function getPeerTokens() {
+  const { data } = await api<PeerToken[]>("/peer-tokens");
+  return data;
+}
+
+async function updatePeerToken(id: string, params: SavePeerTokenParams) {
+  const { data } = await api<PeerToken>(`PATCH /peer-tokens/${id}`, {
+    body: params,
+  });
+  return data;
+}
@@ -11,6 +11,7 @@ type ListsStats = Record<
   | "letterboxd"
   | "mdblist"
   | "serializd"
+  | "simkl"
   | "tmdb"
   | "trakt"
   | "tvdb",
@@ -20,8 +21,31 @@ type ListsStats = Record<
   }
 >;
 
+type PeerHealth = {
+  avg_duration_ms: number;
+  base_trust: number;
+  error_count: number;
+  error_rate: number;
+  is_halted: boolean;
+  is_trusted: boolean;
+  last_error?: string;
+  last_error_at: null | string;
+  last_success_at: null | string;
+  name: string;
+  request_count: number;
+  trust: number;
+};
+
+type PeersStats = {
+  peers: PeerHealth[];
+};
+
 type ServerStats = {
   integration: {
+    anilist: boolean;
+    letterboxd: boolean;
+    mal: boolean;
+    simkl: boolean;
     trakt: boolean;
   };
   started_at: string;
@@ -63,6 +87,7 @@ type TorznabIndexerSyncStats = {
 };
 
 const HOUR = 60 * 60 * 1000;
+const MINUTE = 60 * 1000;
 
 export type StoreStatsEntry = {
   methods: StoreMethodStats[];
@@ -106,6 +131,18 @@ export function useListsStats() {
   });
 }
 
+export function usePeersStats() {
+  return useQuery({
+    queryFn: async () => {
+      const { data } = await api<PeersStats>("/stats/peers");
+      return data;
+    },
+    queryKey: ["/stats/peers"],
+    refetchInterval: 1 * MINUTE,
+    staleTime: 30 * 1000,
+  });
+}
+
 export function useServerStats() {
   return useQuery({
     queryFn: getServerStats,
@@ -136,8 +173,6 @@ export function useTorznabIndexerStats() {
   });
 }
 
-const MINUTE = 60 * 1000;
-
 export function useStoreStats() {
   return useQuery({
     queryFn: async () => {
@@ -0,0 +1,182 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type AddStremThruListItemsParams = {
+  id: string;
+  items: Array<{
+    id: string;
+    type?: StremThruListItemType;
+  }>;
+};
+
+export type CreateStremThruListParams = {
+  description: string;
+  name: string;
+};
+
+export type StremThruList = {
+  created_at: string;
+  description: string;
+  id: string;
+  item_count: number;
+  name: string;
+  owner: string;
+  secret: string;
+  updated_at: string;
+  url: string;
+};
+
+export type StremThruListItem = {
+  created_at: string;
+  id: string;
+  name: string;
+  poster: string;
+  type: StremThruListItemType;
+  year: number;
+};
+
+export type StremThruListItemType = "movie" | "series";
+
+export type UpdateStremThruListParams = CreateStremThruListParams & {
+  id: string;
+};
+
+export function getStremThruListEditableURL(list: StremThruList) {
+  return `${list.url}?secret=${encodeURIComponent(list.secret)}`;
+}
+
+export function useStremThruListItemMutation(listId: string) {
+  const add = useMutation({
+    mutationFn: addStremThruListItems,
+    onSuccess: async (_, __, ___, ctx) => {
+      await Promise.all([
+        ctx.client.invalidateQueries({
+          queryKey: [`/lists/stremthru/${listId}/items`],
+        }),
+        ctx.client.invalidateQueries({ queryKey: ["/lists/stremthru"] }),
+      ]);
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: (itemId: string) => removeStremThruListItem(listId, itemId),
+    onSuccess: async (_, itemId, __, ctx) => {
+      ctx.client.setQueryData<StremThruListItem[]>(
+        [`/lists/stremthru/${listId}/items`],
+        (items) => items?.filter((item) => item.id !== itemId),
+      );
+      await ctx.client.invalidateQueries({ queryKey: ["/lists/stremthru"] });
+    },
+  });
+
+  return { add, remove };
+}
+
+export function useStremThruListItems(listId: string) {
+  return useQuery({
+    enabled: Boolean(listId),
+    queryFn: () => getStremThruListItems(listId),
+    queryKey: [`/lists/stremthru/${listId}/items`],
+  });
+}
+
+export function useStremThruListMutation() {
+  const create = useMutation({
+    mutationFn: createStremThruList,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({ queryKey: ["/lists/stremthru"] });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: updateStremThruList,
+    onSuccess: async (data, __, ___, ctx) => {
+      ctx.client.setQueryData<StremThruList[]>(["/lists/stremthru"], (list) =>
+        list?.map((item) => (item.id === data.id ? data : item)),
+      );
+    },
+  });
+
+  const rotateSecret = useMutation({
+    mutationFn: rotateStremThruListSecret,
+    onSuccess: async (data, __, ___, ctx) => {
+      ctx.client.setQueryData<StremThruList[]>(["/lists/stremthru"], (list) =>
+        list?.map((item) => (item.id === data.id ? data : item)),
+      );
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteStremThruList,
+    onSuccess: async (_, id, __, ctx) => {
+      ctx.client.setQueryData<StremThruList[]>(["/lists/stremthru"], (list) =>
+        list?.filter((item) => item.id !== id),
+      );
+    },
+  });
+
+  return { create, remove, rotateSecret, update };
+}
+
+export function useStremThruLists() {
+  return useQuery({
+    queryFn: getStremThruLists,
+    queryKey: ["/lists/stremthru"],
+  });
+}
+
+async function addStremThruListItems({
+  id,
+  items,
+}: AddStremThruListItemsParams) {
+  await api(`POST /lists/stremthru/${id}/items`, {
+    body: { items },
+  });
+}
+
+async function createStremThruList(params: CreateStremThruListParams) {
+  const { data } = await api<StremThruList>("POST /lists/stremthru", {
+    body: params,
+  });
+  return data;
+}
+
+async function deleteStremThruList(id: string) {
+  await api(`DELETE /lists/stremthru/${id}`);
+}
+
+async function getStremThruListItems(id: string) {
+  const { data } = await api<StremThruListItem[]>(
+    `/lists/stremthru/${id}/items`,
+  );
+  return data;
+}
+
+async function getStremThruLists() {
+  const { data } = await api<StremThruList[]>("/lists/stremthru");
+  return data;
+}
+
+async function removeStremThruListItem(listId: string, itemId: string) {
+  await api(
+    `DELETE /lists/stremthru/${listId}/items/${encodeURIComponent(itemId)}`,
+  );
+}
+
+async function rotateStremThruListSecret(id: string) {
+  const { data } = await api<StremThruList>(
+    `POST /lists/stremthru/${id}/secret`,
+  );
+  return data;
+}
+
+async function updateStremThruList({
+  id,
+  ...params
+}: UpdateStremThruListParams) {
+  const { data } = await api<StremThruList>(`PATCH /lists/stremthru/${id}`, {
+    body: params,
+  });
+  return data;
+}
@@ -0,0 +1,197 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateStremioAniListLinkParams = {
+  anilist_account_id: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+};
+
+export type StremioAniListLink = {
+  anilist_account_id: string;
+  created_at: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+  sync_state: SyncState;
+  updated_at: string;
+};
+
+export type SyncConfig = {
+  watched: SyncConfigWatched;
+};
+
+export type SyncConfigWatched = {
+  dir: SyncDirection;
+};
+
+export type SyncDirection =
+  | "both"
+  | "none"
+  | "stremio_to_anilist"
+  | "anilist_to_stremio";
+
+export type SyncState = {
+  watched: SyncStateWatched;
+};
+
+export type SyncStateWatched = {
+  last_synced_at?: string;
+};
+
+export type UpdateStremioAniListLinkParams = {
+  sync_config: SyncConfig;
+};
+
+export function useStremioAniListLinkMutation() {
+  const create = useMutation({
+    mutationFn: createStremioAniListLink,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-anilist/links"],
+      });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: async ({
+      anilist_account_id,
+      stremio_account_id,
+      ...params
+    }: UpdateStremioAniListLinkParams & {
+      anilist_account_id: string;
+      stremio_account_id: string;
+    }) => {
+      return updateStremioAniListLink(
+        stremio_account_id,
+        anilist_account_id,
+        params,
+      );
+    },
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-anilist/links"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: ({
+      anilist_account_id,
+      stremio_account_id,
+    }: {
+      anilist_account_id: string;
+      stremio_account_id: string;
+    }) => deleteStremioAniListLink(stremio_account_id, anilist_account_id),
+    onSuccess: async (
+      _,
+      { anilist_account_id, stremio_account_id },
+      __,
+      ctx,
+    ) => {
+      ctx.client.setQueryData<StremioAniListLink[]>(
+        ["/sync/stremio-anilist/links"],
+        (list) =>
+          list?.filter(
+            (item) =>
+              item.stremio_account_id !== stremio_account_id ||
+              item.anilist_account_id !== anilist_account_id,
+          ),
+      );
+    },
+  });
+
+  const sync = useMutation({
+    mutationFn: ({
+      anilist_account_id,
+      stremio_account_id,
+    }: {
+      anilist_account_id: string;
+      stremio_account_id: string;
+    }) => syncStremioAniListLink(stremio_account_id, anilist_account_id),
+  });
+
+  const resetSyncState = useMutation({
+    mutationFn: ({
+      anilist_account_id,
+      stremio_account_id,
+    }: {
+      anilist_account_id: string;
+      stremio_account_id: string;
+    }) =>
+      resetStremioAniListLinkSyncState(stremio_account_id, anilist_account_id),
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-anilist/links"],
+      });
+    },
+  });
+
+  return { create, remove, resetSyncState, sync, update };
+}
+
+export function useStremioAniListLinks() {
+  return useQuery({
+    queryFn: getStremioAniListLinks,
+    queryKey: ["/sync/stremio-anilist/links"],
+  });
+}
+
+async function createStremioAniListLink(
+  params: CreateStremioAniListLinkParams,
+) {
+  const { data } = await api<StremioAniListLink>(
+    "POST /sync/stremio-anilist/links",
+    {
+      body: params,
+    },
+  );
+  return data;
+}
+
+async function deleteStremioAniListLink(
+  stremioAccountId: string,
+  anilistAccountId: string,
+) {
+  await api(
+    `DELETE /sync/stremio-anilist/links/${stremioAccountId}:${anilistAccountId}`,
+  );
+}
+
+async function getStremioAniListLinks() {
+  const { data } = await api<StremioAniListLink[]>(
+    "/sync/stremio-anilist/links",
+  );
+  return data;
+}
+
+async function resetStremioAniListLinkSyncState(
+  stremioAccountId: string,
+  anilistAccountId: string,
+) {
+  const { data } = await api<StremioAniListLink>(
+    `POST /sync/stremio-anilist/links/${stremioAccountId}:${anilistAccountId}/reset-sync-state`,
+  );
+  return data;
+}
+
+async function syncStremioAniListLink(
+  stremioAccountId: string,
+  anilistAccountId: string,
+) {
+  await api(
+    `POST /sync/stremio-anilist/links/${stremioAccountId}:${anilistAccountId}/sync`,
+  );
+}
+
+async function updateStremioAniListLink(
+  stremioAccountId: string,
+  anilistAccountId: string,
+  params: UpdateStremioAniListLinkParams,
+) {
+  const { data } = await api<StremioAniListLink>(
+    `PATCH /sync/stremio-anilist/links/${stremioAccountId}:${anilistAccountId}`,
+    { body: params },
+  );
+  return data;
+}
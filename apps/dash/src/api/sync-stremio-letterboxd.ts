@@ -0,0 +1,226 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateStremioLetterboxdLinkParams = {
+  letterboxd_account_id: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+};
+
+export type DiaryEntry = {
+  film_id: string;
+  imdb_id: string;
+  name: string;
+  rewatch: boolean;
+  watched_at: string;
+};
+
+export type StremioLetterboxdLink = {
+  created_at: string;
+  letterboxd_account_id: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+  sync_state: SyncState;
+  updated_at: string;
+};
+
+export type SyncConfig = {
+  watched: SyncConfigWatched;
+};
+
+export type SyncConfigWatched = {
+  dir: SyncDirection;
+};
+
+export type SyncDirection = "none" | "stremio_to_letterboxd";
+
+export type SyncState = {
+  watched: SyncStateWatched;
+};
+
+export type SyncStateWatched = {
+  last_synced_at?: string;
+};
+
+export type UpdateStremioLetterboxdLinkParams = {
+  sync_config: SyncConfig;
+};
+
+export function useStremioLetterboxdLinkMutation() {
+  const create = useMutation({
+    mutationFn: createStremioLetterboxdLink,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-letterboxd/links"],
+      });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: async ({
+      letterboxd_account_id,
+      stremio_account_id,
+      ...params
+    }: UpdateStremioLetterboxdLinkParams & {
+      letterboxd_account_id: string;
+      stremio_account_id: string;
+    }) => {
+      return updateStremioLetterboxdLink(
+        stremio_account_id,
+        letterboxd_account_id,
+        params,
+      );
+    },
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-letterboxd/links"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: ({
+      letterboxd_account_id,
+      stremio_account_id,
+    }: {
+      letterboxd_account_id: string;
+      stremio_account_id: string;
+    }) =>
+      deleteStremioLetterboxdLink(stremio_account_id, letterboxd_account_id),
+    onSuccess: async (
+      _,
+      { letterboxd_account_id, stremio_account_id },
+      __,
+      ctx,
+    ) => {
+      ctx.client.setQueryData<StremioLetterboxdLink[]>(
+        ["/sync/stremio-letterboxd/links"],
+        (list) =>
+          list?.filter(
+            (item) =>
+              item.stremio_account_id !== stremio_account_id ||
+              item.letterboxd_account_id !== letterboxd_account_id,
+          ),
+      );
+    },
+  });
+
+  const sync = useMutation({
+    mutationFn: ({
+      letterboxd_account_id,
+      stremio_account_id,
+    }: {
+      letterboxd_account_id: string;
+      stremio_account_id: string;
+    }) => syncStremioLetterboxdLink(stremio_account_id, letterboxd_account_id),
+  });
+
+  const dryRun = useMutation({
+    mutationFn: ({
+      letterboxd_account_id,
+      stremio_account_id,
+    }: {
+      letterboxd_account_id: string;
+      stremio_account_id: string;
+    }) =>
+      dryRunStremioLetterboxdLink(stremio_account_id, letterboxd_account_id),
+  });
+
+  const resetSyncState = useMutation({
+    mutationFn: ({
+      letterboxd_account_id,
+      stremio_account_id,
+    }: {
+      letterboxd_account_id: string;
+      stremio_account_id: string;
+    }) =>
+      resetStremioLetterboxdLinkSyncState(
+        stremio_account_id,
+        letterboxd_account_id,
+      ),
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-letterboxd/links"],
+      });
+    },
+  });
+
+  return { create, dryRun, remove, resetSyncState, sync, update };
+}
+
+export function useStremioLetterboxdLinks() {
+  return useQuery({
+    queryFn: getStremioLetterboxdLinks,
+    queryKey: ["/sync/stremio-letterboxd/links"],
+  });
+}
+
+async function createStremioLetterboxdLink(
+  params: CreateStremioLetterboxdLinkParams,
+) {
+  const { data } = await api<StremioLetterboxdLink>(
+    "POST /sync/stremio-letterboxd/links",
+    {
+      body: params,
+    },
+  );
+  return data;
+}
+
+async function deleteStremioLetterboxdLink(
+  stremioAccountId: string,
+  letterboxdAccountId: string,
+) {
+  await api(
+    `DELETE /sync/stremio-letterboxd/links/${stremioAccountId}:${letterboxdAccountId}`,
+  );
+}
+
+async function dryRunStremioLetterboxdLink(
+  stremioAccountId: string,
+  letterboxdAccountId: string,
+) {
+  const { data } = await api<{ entries: DiaryEntry[] }>(
+    `POST /sync/stremio-letterboxd/links/${stremioAccountId}:${letterboxdAccountId}/dry-run`,
+  );
+  return data;
+}
+
+async function getStremioLetterboxdLinks() {
+  const { data } = await api<StremioLetterboxdLink[]>(
+    "/sync/stremio-letterboxd/links",
+  );
+  return data;
+}
+
+async function resetStremioLetterboxdLinkSyncState(
+  stremioAccountId: string,
+  letterboxdAccountId: string,
+) {
+  const { data } = await api<StremioLetterboxdLink>(
+    `POST /sync/stremio-letterboxd/links/${stremioAccountId}:${letterboxdAccountId}/reset-sync-state`,
+  );
+  return data;
+}
+
+async function syncStremioLetterboxdLink(
+  stremioAccountId: string,
+  letterboxdAccountId: string,
+) {
+  await api(
+    `POST /sync/stremio-letterboxd/links/${stremioAccountId}:${letterboxdAccountId}/sync`,
+  );
+}
+
+async function updateStremioLetterboxdLink(
+  stremioAccountId: string,
+  letterboxdAccountId: string,
+  params: UpdateStremioLetterboxdLinkParams,
+) {
+  const { data } = await api<StremioLetterboxdLink>(
+    `PATCH /sync/stremio-letterboxd/links/${stremioAccountId}:${letterboxdAccountId}`,
+    { body: params },
+  );
+  return data;
+}
@@ -0,0 +1,187 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateStremioMALLinkParams = {
+  mal_account_id: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+};
+
+export type StremioMALLink = {
+  created_at: string;
+  mal_account_id: string;
+  stremio_account_id: string;
+  sync_config: SyncConfig;
+  sync_state: SyncState;
+  updated_at: string;
+};
+
+export type SyncConfig = {
+  watched: SyncConfigWatched;
+};
+
+export type SyncConfigWatched = {
+  dir: SyncDirection;
+};
+
+export type SyncDirection =
+  | "both"
+  | "none"
+  | "stremio_to_mal"
+  | "mal_to_stremio";
+
+export type SyncState = {
+  watched: SyncStateWatched;
+};
+
+export type SyncStateWatched = {
+  last_synced_at?: string;
+};
+
+export type UpdateStremioMALLinkParams = {
+  sync_config: SyncConfig;
+};
+
+export function useStremioMALLinkMutation() {
+  const create = useMutation({
+    mutationFn: createStremioMALLink,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-mal/links"],
+      });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: async ({
+      mal_account_id,
+      stremio_account_id,
+      ...params
+    }: UpdateStremioMALLinkParams & {
+      mal_account_id: string;
+      stremio_account_id: string;
+    }) => {
+      return updateStremioMALLink(
+        stremio_account_id,
+        mal_account_id,
+        params,
+      );
+    },
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-mal/links"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: ({
+      mal_account_id,
+      stremio_account_id,
+    }: {
+      mal_account_id: string;
+      stremio_account_id: string;
+    }) => deleteStremioMALLink(stremio_account_id, mal_account_id),
+    onSuccess: async (_, { mal_account_id, stremio_account_id }, __, ctx) => {
+      ctx.client.setQueryData<StremioMALLink[]>(
+        ["/sync/stremio-mal/links"],
+        (list) =>
+          list?.filter(
+            (item) =>
+              item.stremio_account_id !== stremio_account_id ||
+              item.mal_account_id !== mal_account_id,
+          ),
+      );
+    },
+  });
+
+  const sync = useMutation({
+    mutationFn: ({
+      mal_account_id,
+      stremio_account_id,
+    }: {
+      mal_account_id: string;
+      stremio_account_id: string;
+    }) => syncStremioMALLink(stremio_account_id, mal_account_id),
+  });
+
+  const resetSyncState = useMutation({
+    mutationFn: ({
+      mal_account_id,
+      stremio_account_id,
+    }: {
+      mal_account_id: string;
+      stremio_account_id: string;
+    }) => resetStremioMALLinkSyncState(stremio_account_id, mal_account_id),
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-mal/links"],
+      });
+    },
+  });
+
+  return { create, remove, resetSyncState, sync, update };
+}
+
+export function useStremioMALLinks() {
+  return useQuery({
+    queryFn: getStremioMALLinks,
+    queryKey: ["/sync/stremio-mal/links"],
+  });
+}
+
+async function createStremioMALLink(params: CreateStremioMALLinkParams) {
+  const { data } = await api<StremioMALLink>(
+    "POST /sync/stremio-mal/links",
+    {
+      body: params,
+    },
+  );
+  return data;
+}
+
+async function deleteStremioMALLink(
+  stremioAccountId: string,
+  malAccountId: string,
+) {
+  await api(
+    `DELETE /sync/stremio-mal/links/${stremioAccountId}:${malAccountId}`,
+  );
+}
+
+async function getStremioMALLinks() {
+  const { data } = await api<StremioMALLink[]>("/sync/stremio-mal/links");
+  return data;
+}
+
+async function resetStremioMALLinkSyncState(
+  stremioAccountId: string,
+  malAccountId: string,
+) {
+  const { data } = await api<StremioMALLink>(
+    `POST /sync/stremio-mal/links/${stremioAccountId}:${malAccountId}/reset-sync-state`,
+  );
+  return data;
+}
+
+async function syncStremioMALLink(
+  stremioAccountId: string,
+  malAccountId: string,
+) {
+  await api(
+    `POST /sync/stremio-mal/links/${stremioAccountId}:${malAccountId}/sync`,
+  );
+}
+
+async function updateStremioMALLink(
+  stremioAccountId: string,
+  malAccountId: string,
+  params: UpdateStremioMALLinkParams,
+) {
+  const { data } = await api<StremioMALLink>(
+    `PATCH /sync/stremio-mal/links/${stremioAccountId}:${malAccountId}`,
+    { body: params },
+  );
+  return data;
+}
@@ -0,0 +1,101 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateAniListAccountParams = {
+  oauth_token_id: string;
+};
+
+export type AniListAccount = {
+  created_at: string;
+  id: string; // anilist user slug
+  is_valid: boolean;
+  updated_at: string;
+  user_name: string;
+};
+
+export type AniListAuthURL = {
+  url: string;
+};
+
+export async function getAniListAuthURL(state: string) {
+  const { data } = await api<AniListAuthURL>(
+    `/vault/anilist/auth/url?state=${state}`,
+  );
+  return data.url;
+}
+
+export function useAniListAccountMutation() {
+  const create = useMutation({
+    mutationFn: createAniListAccount,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/vault/anilist/accounts"],
+      });
+    },
+  });
+
+  const get = useMutation({
+    mutationFn: getAniListAccount,
+    onSuccess: async (data, { id }, __, ctx) => {
+      ctx.client.setQueryData<AniListAccount[]>(
+        ["/vault/anilist/accounts"],
+        (list) =>
+          list?.map((item) => (item.id === id ? { ...item, ...data } : item)),
+      );
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteAniListAccount,
+    onSuccess: async (_, id, __, ctx) => {
+      const list = ctx.client.getQueryData<AniListAccount[]>([
+        "/vault/anilist/accounts",
+      ]);
+      if (list) {
+        ctx.client.setQueryData(
+          ["/vault/anilist/accounts"],
+          list.filter((item) => item.id !== id),
+        );
+      }
+    },
+  });
+
+  return { create, get, remove };
+}
+
+export function useAniListAccounts() {
+  return useQuery({
+    queryFn: getAniListAccounts,
+    queryKey: ["/vault/anilist/accounts"],
+  });
+}
+
+async function createAniListAccount(params: CreateAniListAccountParams) {
+  const { data } = await api<AniListAccount>("POST /vault/anilist/accounts", {
+    body: params,
+  });
+  return data;
+}
+
+async function deleteAniListAccount(id: string) {
+  await api(`DELETE /vault/anilist/accounts/${id}`);
+}
+
+async function getAniListAccount({
+  id,
+  refresh = false,
+}: {
+  id: string;
+  refresh?: boolean;
+}) {
+  const { data } = await api<AniListAccount>(
+    `GET /vault/anilist/accounts/${id}?refresh=${refresh}`,
+  );
+  return data;
+}
+
+async function getAniListAccounts() {
+  const { data } = await api<AniListAccount[]>("/vault/anilist/accounts");
+  return data;
+}
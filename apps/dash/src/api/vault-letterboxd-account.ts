@@ -0,0 +1,104 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateLetterboxdAccountParams = {
+  oauth_token_id: string;
+};
+
+export type LetterboxdAccount = {
+  created_at: string;
+  id: string; // letterboxd user slug
+  is_valid: boolean;
+  updated_at: string;
+  user_name: string;
+};
+
+export type LetterboxdAuthURL = {
+  url: string;
+};
+
+export async function getLetterboxdAuthURL(state: string) {
+  const { data } = await api<LetterboxdAuthURL>(
+    `/vault/letterboxd/auth/url?state=${state}`,
+  );
+  return data.url;
+}
+
+export function useLetterboxdAccountMutation() {
+  const create = useMutation({
+    mutationFn: createLetterboxdAccount,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/vault/letterboxd/accounts"],
+      });
+    },
+  });
+
+  const get = useMutation({
+    mutationFn: getLetterboxdAccount,
+    onSuccess: async (data, { id }, __, ctx) => {
+      ctx.client.setQueryData<LetterboxdAccount[]>(
+        ["/vault/letterboxd/accounts"],
+        (list) =>
+          list?.map((item) => (item.id === id ? { ...item, ...data } : item)),
+      );
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteLetterboxdAccount,
+    onSuccess: async (_, id, __, ctx) => {
+      const list = ctx.client.getQueryData<LetterboxdAccount[]>([
+        "/vault/letterboxd/accounts",
+      ]);
+      if (list) {
+        ctx.client.setQueryData(
+          ["/vault/letterboxd/accounts"],
+          list.filter((item) => item.id !== id),
+        );
+      }
+    },
+  });
+
+  return { create, get, remove };
+}
+
+export function useLetterboxdAccounts() {
+  return useQuery({
+    queryFn: getLetterboxdAccounts,
+    queryKey: ["/vault/letterboxd/accounts"],
+  });
+}
+
+async function createLetterboxdAccount(params: CreateLetterboxdAccountParams) {
+  const { data } = await api<LetterboxdAccount>(
+    "POST /vault/letterboxd/accounts",
+    {
+      body: params,
+    },
+  );
+  return data;
+}
+
+async function deleteLetterboxdAccount(id: string) {
+  await api(`DELETE /vault/letterboxd/accounts/${id}`);
+}
+
+async function getLetterboxdAccount({
+  id,
+  refresh = false,
+}: {
+  id: string;
+  refresh?: boolean;
+}) {
+  const { data } = await api<LetterboxdAccount>(
+    `GET /vault/letterboxd/accounts/${id}?refresh=${refresh}`,
+  );
+  return data;
+}
+
+async function getLetterboxdAccounts() {
+  const { data } = await api<LetterboxdAccount[]>("/vault/letterboxd/accounts");
+  return data;
+}
@@ -0,0 +1,101 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateMALAccountParams = {
+  oauth_token_id: string;
+};
+
+export type MALAccount = {
+  created_at: string;
+  id: string; // mal user slug
+  is_valid: boolean;
+  updated_at: string;
+  user_name: string;
+};
+
+export type MALAuthURL = {
+  url: string;
+};
+
+export async function getMALAuthURL(state: string) {
+  const { data } = await api<MALAuthURL>(
+    `/vault/mal/auth/url?state=${state}`,
+  );
+  return data.url;
+}
+
+export function useMALAccountMutation() {
+  const create = useMutation({
+    mutationFn: createMALAccount,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/vault/mal/accounts"],
+      });
+    },
+  });
+
+  const get = useMutation({
+    mutationFn: getMALAccount,
+    onSuccess: async (data, { id }, __, ctx) => {
+      ctx.client.setQueryData<MALAccount[]>(
+        ["/vault/mal/accounts"],
+        (list) =>
+          list?.map((item) => (item.id === id ? { ...item, ...data } : item)),
+      );
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteMALAccount,
+    onSuccess: async (_, id, __, ctx) => {
+      const list = ctx.client.getQueryData<MALAccount[]>([
+        "/vault/mal/accounts",
+      ]);
+      if (list) {
+        ctx.client.setQueryData(
+          ["/vault/mal/accounts"],
+          list.filter((item) => item.id !== id),
+        );
+      }
+    },
+  });
+
+  return { create, get, remove };
+}
+
+export function useMALAccounts() {
+  return useQuery({
+    queryFn: getMALAccounts,
+    queryKey: ["/vault/mal/accounts"],
+  });
+}
+
+async function createMALAccount(params: CreateMALAccountParams) {
+  const { data } = await api<MALAccount>("POST /vault/mal/accounts", {
+    body: params,
+  });
+  return data;
+}
+
+async function deleteMALAccount(id: string) {
+  await api(`DELETE /vault/mal/accounts/${id}`);
+}
+
+async function getMALAccount({
+  id,
+  refresh = false,
+}: {
+  id: string;
+  refresh?: boolean;
+}) {
+  const { data } = await api<MALAccount>(
+    `GET /vault/mal/accounts/${id}?refresh=${refresh}`,
+  );
+  return data;
+}
+
+async function getMALAccounts() {
+  const { data } = await api<MALAccount[]>("/vault/mal/accounts");
+  return data;
+}
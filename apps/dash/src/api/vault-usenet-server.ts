@@ -12,6 +12,7 @@ export type UsenetServer = {
   name: string;
   port: number;
   priority: number;
+  proxy: string;
   tls: boolean;
   tls_skip_verify: boolean;
   updated_at: string;
@@ -26,6 +27,7 @@ type CreateUsenetServerParams = {
   password: string;
   port: number;
   priority: number;
+  proxy: string;
   tls: boolean;
   tls_skip_verify: boolean;
   username: string;
@@ -36,6 +38,7 @@ type PingUsenetServerParams = {
   id?: string;
   password: string;
   port: number;
+  proxy: string;
   tls: boolean;
   tls_skip_verify: boolean;
   username: string;
@@ -54,6 +57,7 @@ type UpdateUsenetServerParams = Partial<{
   password: string;
   port: number;
   priority: number;
+  proxy: string;
   tls: boolean;
   tls_skip_verify: boolean;
   username: string;
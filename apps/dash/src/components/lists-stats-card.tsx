@@ -33,6 +33,10 @@ const listsChartConfig = {
     color: "var(--chart-5)",
     label: "Serializd",
   },
+  simkl: {
+    color: "var(--chart-2)",
+    label: "Simkl",
+  },
   tmdb: {
     color: "var(--chart-1)",
     label: "TMDB",
@@ -73,6 +77,11 @@ export function ListStatsCard() {
           lists: data?.serializd.total_lists,
           service: "serializd",
         },
+        {
+          fill: "var(--color-simkl)",
+          lists: data?.simkl.total_lists,
+          service: "simkl",
+        },
         {
           fill: "var(--color-tmdb)",
           lists: data?.tmdb.total_lists,
@@ -110,6 +119,11 @@ export function ListStatsCard() {
           items: data?.serializd.total_items,
           service: "serializd",
         },
+        {
+          fill: "var(--color-simkl)",
+          items: data?.simkl.total_items,
+          service: "simkl",
+        },
         {
           fill: "var(--color-tmdb)",
           items: data?.tmdb.total_items,
@@ -265,6 +265,10 @@ function useNavItems(): NavItem[] {
             path: "/dash/lists",
             title: "Stats",
           },
+          {
+            path: "/dash/lists/stremthru",
+            title: "StremThru",
+          },
         ],
         path: "/dash/lists",
         title: "Lists",
@@ -284,6 +288,10 @@ function useNavItems(): NavItem[] {
             path: "/dash/torrent/info",
             title: "Info",
           },
+          {
+            path: "/dash/torrent/peer-tokens",
+            title: "Peer Tokens",
+          },
         ],
         path: "/dash/torrent",
         title: "Torrent",
@@ -363,6 +371,30 @@ function useNavItems(): NavItem[] {
           title: "Trakt Accounts",
         });
       }
+      if (server?.integration.anilist) {
+        vault.items!.push({
+          path: "/dash/vault/anilist-accounts",
+          title: "AniList Accounts",
+        });
+      }
+      if (server?.integration.mal) {
+        vault.items!.push({
+          path: "/dash/vault/mal-accounts",
+          title: "MyAnimeList Accounts",
+        });
+      }
+      if (server?.integration.simkl) {
+        vault.items!.push({
+          path: "/dash/vault/simkl-accounts",
+          title: "Simkl Accounts",
+        });
+      }
+      if (server?.integration.letterboxd) {
+        vault.items!.push({
+          path: "/dash/vault/letterboxd-accounts",
+          title: "Letterboxd Accounts",
+        });
+      }
       items.push(vault);
 
       if (features.get("sync")) {
@@ -387,6 +419,30 @@ function useNavItems(): NavItem[] {
             title: "Stremio ↔ Trakt",
           });
         }
+        if (server?.integration.anilist) {
+          sync.items!.push({
+            path: "/dash/sync/stremio-anilist",
+            title: "Stremio ↔ AniList",
+          });
+        }
+        if (server?.integration.mal) {
+          sync.items!.push({
+            path: "/dash/sync/stremio-mal",
+            title: "Stremio ↔ MyAnimeList",
+          });
+        }
+        if (server?.integration.simkl) {
+          sync.items!.push({
+            path: "/dash/sync/stremio-simkl",
+            title: "Stremio ↔ Simkl",
+          });
+        }
+        if (server?.integration.letterboxd) {
+          sync.items!.push({
+            path: "/dash/sync/stremio-letterboxd",
+            title: "Stremio ↔ Letterboxd",
+          });
+        }
         items.push(sync);
       }
     }
@@ -413,5 +469,12 @@ function useNavItems(): NavItem[] {
     items.push(settings);
 
     return items;
-  }, [features, server?.integration.trakt]);
+  }, [
+    features,
+    server?.integration.anilist,
+    server?.integration.letterboxd,
+    server?.integration.mal,
+    server?.integration.simkl,
+    server?.integration.trakt,
+  ]);
 }
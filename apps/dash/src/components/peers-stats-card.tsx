@@ -0,0 +1,129 @@
+import { usePeersStats } from "@/api/stats";
+import { Badge } from "@/components/ui/badge";
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from "@/components/ui/card";
+import { Skeleton } from "@/components/ui/skeleton";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+
+export function PeersStatsCard() {
+  const stats = usePeersStats();
+
+  const peers = stats.data?.peers ?? [];
+  if (!stats.isLoading && !peers.length) {
+    return null;
+  }
+
+  const totalRequests = peers.reduce((sum, p) => sum + p.request_count, 0);
+  const totalErrors = peers.reduce((sum, p) => sum + p.error_count, 0);
+
+  return (
+    <Card className="py-4 sm:py-0">
+      <CardHeader className="flex flex-col items-stretch border-b !p-0 sm:flex-row">
+        <div className="flex flex-1 flex-col justify-center gap-1 px-6 pb-3 sm:pb-0">
+          <CardTitle>Peer Health</CardTitle>
+          <CardDescription>(since server start)</CardDescription>
+        </div>
+        <div className="flex flex-wrap">
+          {(
+            [
+              ["Peers", peers.length.toLocaleString()],
+              ["Requests", totalRequests.toLocaleString()],
+              ["Errors", totalErrors.toLocaleString()],
+            ] as const
+          ).map(([label, value]) => (
+            <div
+              className="flex flex-1 flex-col justify-center gap-1 border-l border-t px-6 py-4 text-left sm:border-l sm:border-t-0 sm:px-8 sm:py-6"
+              key={label}
+            >
+              <span className="text-muted-foreground text-xs">{label}</span>
+              <span className="text-lg font-bold leading-none sm:text-3xl">
+                {stats.isLoading ? <Skeleton className="h-8 w-24" /> : value}
+              </span>
+            </div>
+          ))}
+        </div>
+      </CardHeader>
+      <CardContent className="px-2 pb-4">
+        {stats.isLoading ? (
+          <div className="space-y-2">
+            <Skeleton className="h-8 w-full" />
+            <Skeleton className="h-8 w-full" />
+          </div>
+        ) : (
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>Name</TableHead>
+                <TableHead>Status</TableHead>
+                <TableHead className="text-right">Trust</TableHead>
+                <TableHead className="text-right">Requests</TableHead>
+                <TableHead className="text-right">Errors</TableHead>
+                <TableHead className="text-right">Error %</TableHead>
+                <TableHead className="text-right">Avg (ms)</TableHead>
+                <TableHead className="text-right">Last Success</TableHead>
+                <TableHead className="text-right">Last Error</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {peers.map((peer) => (
+                <TableRow key={peer.name}>
+                  <TableCell className="font-medium">{peer.name}</TableCell>
+                  <TableCell className="space-x-1">
+                    <Badge variant={peer.is_halted ? "destructive" : "outline"}>
+                      {peer.is_halted ? "Halted" : "Active"}
+                    </Badge>
+                    {!peer.is_trusted && (
+                      <Badge variant="secondary">Untrusted</Badge>
+                    )}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.trust === peer.base_trust
+                      ? peer.trust
+                      : `${peer.trust} / ${peer.base_trust}`}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.request_count.toLocaleString()}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.error_count.toLocaleString()}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.error_rate.toFixed(2)}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.avg_duration_ms.toFixed(2)}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {peer.last_success_at
+                      ? new Date(peer.last_success_at).toLocaleString()
+                      : "-"}
+                  </TableCell>
+                  <TableCell
+                    className="text-right"
+                    title={peer.last_error ?? undefined}
+                  >
+                    {peer.last_error_at
+                      ? new Date(peer.last_error_at).toLocaleString()
+                      : "-"}
+                  </TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        )}
+      </CardContent>
+    </Card>
+  );
+}
@@ -28,6 +28,10 @@ import { Route as DashSettingsIndexRouteImport } from './routes/dash/settings/in
 import { Route as DashListsIndexRouteImport } from './routes/dash/lists/index'
 import { Route as DashVaultTraktAccountsRouteImport } from './routes/dash/vault/trakt-accounts'
 import { Route as DashVaultStremioAccountsRouteImport } from './routes/dash/vault/stremio-accounts'
+import { Route as DashVaultSimklAccountsRouteImport } from './routes/dash/vault/simkl-accounts'
+import { Route as DashVaultMalAccountsRouteImport } from './routes/dash/vault/mal-accounts'
+import { Route as DashVaultLetterboxdAccountsRouteImport } from './routes/dash/vault/letterboxd-accounts'
+import { Route as DashVaultAnilistAccountsRouteImport } from './routes/dash/vault/anilist-accounts'
 import { Route as DashUsenetServersRouteImport } from './routes/dash/usenet/servers'
 import { Route as DashUsenetNzbQueueRouteImport } from './routes/dash/usenet/nzb-queue'
 import { Route as DashUsenetNzbInspectorRouteImport } from './routes/dash/usenet/nzb-inspector'
@@ -35,12 +39,18 @@ import { Route as DashUsenetNzbRouteImport } from './routes/dash/usenet/nzb'
 import { Route as DashUsenetIndexersRouteImport } from './routes/dash/usenet/indexers'
 import { Route as DashTorrentTorznabIndexersRouteImport } from './routes/dash/torrent/torznab-indexers'
 import { Route as DashTorrentSyncInfoRouteImport } from './routes/dash/torrent/sync-info'
+import { Route as DashTorrentPeerTokensRouteImport } from './routes/dash/torrent/peer-tokens'
 import { Route as DashTorrentInfoRouteImport } from './routes/dash/torrent/info'
 import { Route as DashSyncStremioTraktRouteImport } from './routes/dash/sync/stremio-trakt'
 import { Route as DashSyncStremioStremioRouteImport } from './routes/dash/sync/stremio-stremio'
+import { Route as DashSyncStremioSimklRouteImport } from './routes/dash/sync/stremio-simkl'
+import { Route as DashSyncStremioMalRouteImport } from './routes/dash/sync/stremio-mal'
+import { Route as DashSyncStremioLetterboxdRouteImport } from './routes/dash/sync/stremio-letterboxd'
+import { Route as DashSyncStremioAnilistRouteImport } from './routes/dash/sync/stremio-anilist'
 import { Route as DashSettingsRatelimitConfigsRouteImport } from './routes/dash/settings/ratelimit-configs'
 import { Route as DashSettingsMaintenanceRouteImport } from './routes/dash/settings/maintenance'
 import { Route as DashSettingsConfigRouteImport } from './routes/dash/settings/config'
+import { Route as DashListsStremthruRouteImport } from './routes/dash/lists/stremthru'
 
 const DashRoute = DashRouteImport.update({
   id: '/dash',
@@ -138,6 +148,28 @@ const DashVaultStremioAccountsRoute =
     path: '/stremio-accounts',
     getParentRoute: () => DashVaultRoute,
   } as any)
+const DashVaultSimklAccountsRoute = DashVaultSimklAccountsRouteImport.update({
+  id: '/simkl-accounts',
+  path: '/simkl-accounts',
+  getParentRoute: () => DashVaultRoute,
+} as any)
+const DashVaultMalAccountsRoute = DashVaultMalAccountsRouteImport.update({
+  id: '/mal-accounts',
+  path: '/mal-accounts',
+  getParentRoute: () => DashVaultRoute,
+} as any)
+const DashVaultLetterboxdAccountsRoute =
+  DashVaultLetterboxdAccountsRouteImport.update({
+    id: '/letterboxd-accounts',
+    path: '/letterboxd-accounts',
+    getParentRoute: () => DashVaultRoute,
+  } as any)
+const DashVaultAnilistAccountsRoute =
+  DashVaultAnilistAccountsRouteImport.update({
+    id: '/anilist-accounts',
+    path: '/anilist-accounts',
+    getParentRoute: () => DashVaultRoute,
+  } as any)
 const DashUsenetServersRoute = DashUsenetServersRouteImport.update({
   id: '/servers',
   path: '/servers',
@@ -174,6 +206,11 @@ const DashTorrentSyncInfoRoute = DashTorrentSyncInfoRouteImport.update({
   path: '/sync-info',
   getParentRoute: () => DashTorrentRoute,
 } as any)
+const DashTorrentPeerTokensRoute = DashTorrentPeerTokensRouteImport.update({
+  id: '/peer-tokens',
+  path: '/peer-tokens',
+  getParentRoute: () => DashTorrentRoute,
+} as any)
 const DashTorrentInfoRoute = DashTorrentInfoRouteImport.update({
   id: '/info',
   path: '/info',
@@ -189,6 +226,27 @@ const DashSyncStremioStremioRoute = DashSyncStremioStremioRouteImport.update({
   path: '/stremio-stremio',
   getParentRoute: () => DashSyncRoute,
 } as any)
+const DashSyncStremioSimklRoute = DashSyncStremioSimklRouteImport.update({
+  id: '/stremio-simkl',
+  path: '/stremio-simkl',
+  getParentRoute: () => DashSyncRoute,
+} as any)
+const DashSyncStremioMalRoute = DashSyncStremioMalRouteImport.update({
+  id: '/stremio-mal',
+  path: '/stremio-mal',
+  getParentRoute: () => DashSyncRoute,
+} as any)
+const DashSyncStremioLetterboxdRoute =
+  DashSyncStremioLetterboxdRouteImport.update({
+    id: '/stremio-letterboxd',
+    path: '/stremio-letterboxd',
+    getParentRoute: () => DashSyncRoute,
+  } as any)
+const DashSyncStremioAnilistRoute = DashSyncStremioAnilistRouteImport.update({
+  id: '/stremio-anilist',
+  path: '/stremio-anilist',
+  getParentRoute: () => DashSyncRoute,
+} as any)
 const DashSettingsRatelimitConfigsRoute =
   DashSettingsRatelimitConfigsRouteImport.update({
     id: '/ratelimit-configs',
@@ -205,6 +263,11 @@ const DashSettingsConfigRoute = DashSettingsConfigRouteImport.update({
   path: '/config',
   getParentRoute: () => DashSettingsRoute,
 } as any)
+const DashListsStremthruRoute = DashListsStremthruRouteImport.update({
+  id: '/stremthru',
+  path: '/stremthru',
+  getParentRoute: () => DashListsRoute,
+} as any)
 
 export interface FileRoutesByFullPath {
   '/dash': typeof DashRouteWithChildren
@@ -218,12 +281,18 @@ export interface FileRoutesByFullPath {
   '/dash/vault': typeof DashVaultRouteWithChildren
   '/dash/workers': typeof DashWorkersRoute
   '/dash/': typeof DashIndexRoute
+  '/dash/lists/stremthru': typeof DashListsStremthruRoute
   '/dash/settings/config': typeof DashSettingsConfigRoute
   '/dash/settings/maintenance': typeof DashSettingsMaintenanceRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
+  '/dash/sync/stremio-anilist': typeof DashSyncStremioAnilistRoute
+  '/dash/sync/stremio-letterboxd': typeof DashSyncStremioLetterboxdRoute
+  '/dash/sync/stremio-mal': typeof DashSyncStremioMalRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrent/info': typeof DashTorrentInfoRoute
+  '/dash/torrent/peer-tokens': typeof DashTorrentPeerTokensRoute
   '/dash/torrent/sync-info': typeof DashTorrentSyncInfoRoute
   '/dash/torrent/torznab-indexers': typeof DashTorrentTorznabIndexersRoute
   '/dash/usenet/indexers': typeof DashUsenetIndexersRoute
@@ -231,6 +300,10 @@ export interface FileRoutesByFullPath {
   '/dash/usenet/nzb-inspector': typeof DashUsenetNzbInspectorRoute
   '/dash/usenet/nzb-queue': typeof DashUsenetNzbQueueRoute
   '/dash/usenet/servers': typeof DashUsenetServersRoute
+  '/dash/vault/anilist-accounts': typeof DashVaultAnilistAccountsRoute
+  '/dash/vault/letterboxd-accounts': typeof DashVaultLetterboxdAccountsRoute
+  '/dash/vault/mal-accounts': typeof DashVaultMalAccountsRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists/': typeof DashListsIndexRoute
@@ -245,12 +318,18 @@ export interface FileRoutesByTo {
   '/dash/proxy': typeof DashProxyRoute
   '/dash/workers': typeof DashWorkersRoute
   '/dash': typeof DashIndexRoute
+  '/dash/lists/stremthru': typeof DashListsStremthruRoute
   '/dash/settings/config': typeof DashSettingsConfigRoute
   '/dash/settings/maintenance': typeof DashSettingsMaintenanceRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
+  '/dash/sync/stremio-anilist': typeof DashSyncStremioAnilistRoute
+  '/dash/sync/stremio-letterboxd': typeof DashSyncStremioLetterboxdRoute
+  '/dash/sync/stremio-mal': typeof DashSyncStremioMalRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrent/info': typeof DashTorrentInfoRoute
+  '/dash/torrent/peer-tokens': typeof DashTorrentPeerTokensRoute
   '/dash/torrent/sync-info': typeof DashTorrentSyncInfoRoute
   '/dash/torrent/torznab-indexers': typeof DashTorrentTorznabIndexersRoute
   '/dash/usenet/indexers': typeof DashUsenetIndexersRoute
@@ -258,6 +337,10 @@ export interface FileRoutesByTo {
   '/dash/usenet/nzb-inspector': typeof DashUsenetNzbInspectorRoute
   '/dash/usenet/nzb-queue': typeof DashUsenetNzbQueueRoute
   '/dash/usenet/servers': typeof DashUsenetServersRoute
+  '/dash/vault/anilist-accounts': typeof DashVaultAnilistAccountsRoute
+  '/dash/vault/letterboxd-accounts': typeof DashVaultLetterboxdAccountsRoute
+  '/dash/vault/mal-accounts': typeof DashVaultMalAccountsRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists': typeof DashListsIndexRoute
@@ -280,12 +363,18 @@ export interface FileRoutesById {
   '/dash/vault': typeof DashVaultRouteWithChildren
   '/dash/workers': typeof DashWorkersRoute
   '/dash/': typeof DashIndexRoute
+  '/dash/lists/stremthru': typeof DashListsStremthruRoute
   '/dash/settings/config': typeof DashSettingsConfigRoute
   '/dash/settings/maintenance': typeof DashSettingsMaintenanceRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
+  '/dash/sync/stremio-anilist': typeof DashSyncStremioAnilistRoute
+  '/dash/sync/stremio-letterboxd': typeof DashSyncStremioLetterboxdRoute
+  '/dash/sync/stremio-mal': typeof DashSyncStremioMalRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrent/info': typeof DashTorrentInfoRoute
+  '/dash/torrent/peer-tokens': typeof DashTorrentPeerTokensRoute
   '/dash/torrent/sync-info': typeof DashTorrentSyncInfoRoute
   '/dash/torrent/torznab-indexers': typeof DashTorrentTorznabIndexersRoute
   '/dash/usenet/indexers': typeof DashUsenetIndexersRoute
@@ -293,6 +382,10 @@ export interface FileRoutesById {
   '/dash/usenet/nzb-inspector': typeof DashUsenetNzbInspectorRoute
   '/dash/usenet/nzb-queue': typeof DashUsenetNzbQueueRoute
   '/dash/usenet/servers': typeof DashUsenetServersRoute
+  '/dash/vault/anilist-accounts': typeof DashVaultAnilistAccountsRoute
+  '/dash/vault/letterboxd-accounts': typeof DashVaultLetterboxdAccountsRoute
+  '/dash/vault/mal-accounts': typeof DashVaultMalAccountsRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists/': typeof DashListsIndexRoute
@@ -316,12 +409,18 @@ export interface FileRouteTypes {
     | '/dash/vault'
     | '/dash/workers'
     | '/dash/'
+    | '/dash/lists/stremthru'
     | '/dash/settings/config'
     | '/dash/settings/maintenance'
     | '/dash/settings/ratelimit-configs'
+    | '/dash/sync/stremio-anilist'
+    | '/dash/sync/stremio-letterboxd'
+    | '/dash/sync/stremio-mal'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-stremio'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrent/info'
+    | '/dash/torrent/peer-tokens'
     | '/dash/torrent/sync-info'
     | '/dash/torrent/torznab-indexers'
     | '/dash/usenet/indexers'
@@ -329,6 +428,10 @@ export interface FileRouteTypes {
     | '/dash/usenet/nzb-inspector'
     | '/dash/usenet/nzb-queue'
     | '/dash/usenet/servers'
+    | '/dash/vault/anilist-accounts'
+    | '/dash/vault/letterboxd-accounts'
+    | '/dash/vault/mal-accounts'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists/'
@@ -343,12 +446,18 @@ export interface FileRouteTypes {
     | '/dash/proxy'
     | '/dash/workers'
     | '/dash'
+    | '/dash/lists/stremthru'
     | '/dash/settings/config'
     | '/dash/settings/maintenance'
     | '/dash/settings/ratelimit-configs'
+    | '/dash/sync/stremio-anilist'
+    | '/dash/sync/stremio-letterboxd'
+    | '/dash/sync/stremio-mal'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-stremio'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrent/info'
+    | '/dash/torrent/peer-tokens'
     | '/dash/torrent/sync-info'
     | '/dash/torrent/torznab-indexers'
     | '/dash/usenet/indexers'
@@ -356,6 +465,10 @@ export interface FileRouteTypes {
     | '/dash/usenet/nzb-inspector'
     | '/dash/usenet/nzb-queue'
     | '/dash/usenet/servers'
+    | '/dash/vault/anilist-accounts'
+    | '/dash/vault/letterboxd-accounts'
+    | '/dash/vault/mal-accounts'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists'
@@ -377,12 +490,18 @@ export interface FileRouteTypes {
     | '/dash/vault'
     | '/dash/workers'
     | '/dash/'
+    | '/dash/lists/stremthru'
     | '/dash/settings/config'
     | '/dash/settings/maintenance'
     | '/dash/settings/ratelimit-configs'
+    | '/dash/sync/stremio-anilist'
+    | '/dash/sync/stremio-letterboxd'
+    | '/dash/sync/stremio-mal'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-stremio'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrent/info'
+    | '/dash/torrent/peer-tokens'
     | '/dash/torrent/sync-info'
     | '/dash/torrent/torznab-indexers'
     | '/dash/usenet/indexers'
@@ -390,6 +509,10 @@ export interface FileRouteTypes {
     | '/dash/usenet/nzb-inspector'
     | '/dash/usenet/nzb-queue'
     | '/dash/usenet/servers'
+    | '/dash/vault/anilist-accounts'
+    | '/dash/vault/letterboxd-accounts'
+    | '/dash/vault/mal-accounts'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists/'
@@ -539,6 +662,34 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashVaultStremioAccountsRouteImport
       parentRoute: typeof DashVaultRoute
     }
+    '/dash/vault/simkl-accounts': {
+      id: '/dash/vault/simkl-accounts'
+      path: '/simkl-accounts'
+      fullPath: '/dash/vault/simkl-accounts'
+      preLoaderRoute: typeof DashVaultSimklAccountsRouteImport
+      parentRoute: typeof DashVaultRoute
+    }
+    '/dash/vault/mal-accounts': {
+      id: '/dash/vault/mal-accounts'
+      path: '/mal-accounts'
+      fullPath: '/dash/vault/mal-accounts'
+      preLoaderRoute: typeof DashVaultMalAccountsRouteImport
+      parentRoute: typeof DashVaultRoute
+    }
+    '/dash/vault/letterboxd-accounts': {
+      id: '/dash/vault/letterboxd-accounts'
+      path: '/letterboxd-accounts'
+      fullPath: '/dash/vault/letterboxd-accounts'
+      preLoaderRoute: typeof DashVaultLetterboxdAccountsRouteImport
+      parentRoute: typeof DashVaultRoute
+    }
+    '/dash/vault/anilist-accounts': {
+      id: '/dash/vault/anilist-accounts'
+      path: '/anilist-accounts'
+      fullPath: '/dash/vault/anilist-accounts'
+      preLoaderRoute: typeof DashVaultAnilistAccountsRouteImport
+      parentRoute: typeof DashVaultRoute
+    }
     '/dash/usenet/servers': {
       id: '/dash/usenet/servers'
       path: '/servers'
@@ -588,6 +739,13 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashTorrentSyncInfoRouteImport
       parentRoute: typeof DashTorrentRoute
     }
+    '/dash/torrent/peer-tokens': {
+      id: '/dash/torrent/peer-tokens'
+      path: '/peer-tokens'
+      fullPath: '/dash/torrent/peer-tokens'
+      preLoaderRoute: typeof DashTorrentPeerTokensRouteImport
+      parentRoute: typeof DashTorrentRoute
+    }
     '/dash/torrent/info': {
       id: '/dash/torrent/info'
       path: '/info'
@@ -609,6 +767,34 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashSyncStremioStremioRouteImport
       parentRoute: typeof DashSyncRoute
     }
+    '/dash/sync/stremio-simkl': {
+      id: '/dash/sync/stremio-simkl'
+      path: '/stremio-simkl'
+      fullPath: '/dash/sync/stremio-simkl'
+      preLoaderRoute: typeof DashSyncStremioSimklRouteImport
+      parentRoute: typeof DashSyncRoute
+    }
+    '/dash/sync/stremio-mal': {
+      id: '/dash/sync/stremio-mal'
+      path: '/stremio-mal'
+      fullPath: '/dash/sync/stremio-mal'
+      preLoaderRoute: typeof DashSyncStremioMalRouteImport
+      parentRoute: typeof DashSyncRoute
+    }
+    '/dash/sync/stremio-letterboxd': {
+      id: '/dash/sync/stremio-letterboxd'
+      path: '/stremio-letterboxd'
+      fullPath: '/dash/sync/stremio-letterboxd'
+      preLoaderRoute: typeof DashSyncStremioLetterboxdRouteImport
+      parentRoute: typeof DashSyncRoute
+    }
+    '/dash/sync/stremio-anilist': {
+      id: '/dash/sync/stremio-anilist'
+      path: '/stremio-anilist'
+      fullPath: '/dash/sync/stremio-anilist'
+      preLoaderRoute: typeof DashSyncStremioAnilistRouteImport
+      parentRoute: typeof DashSyncRoute
+    }
     '/dash/settings/ratelimit-configs': {
       id: '/dash/settings/ratelimit-configs'
       path: '/ratelimit-configs'
@@ -630,14 +816,23 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashSettingsConfigRouteImport
       parentRoute: typeof DashSettingsRoute
     }
+    '/dash/lists/stremthru': {
+      id: '/dash/lists/stremthru'
+      path: '/stremthru'
+      fullPath: '/dash/lists/stremthru'
+      preLoaderRoute: typeof DashListsStremthruRouteImport
+      parentRoute: typeof DashListsRoute
+    }
   }
 }
 
 interface DashListsRouteChildren {
+  DashListsStremthruRoute: typeof DashListsStremthruRoute
   DashListsIndexRoute: typeof DashListsIndexRoute
 }
 
 const DashListsRouteChildren: DashListsRouteChildren = {
+  DashListsStremthruRoute: DashListsStremthruRoute,
   DashListsIndexRoute: DashListsIndexRoute,
 }
 
@@ -664,12 +859,20 @@ const DashSettingsRouteWithChildren = DashSettingsRoute._addFileChildren(
 )
 
 interface DashSyncRouteChildren {
+  DashSyncStremioAnilistRoute: typeof DashSyncStremioAnilistRoute
+  DashSyncStremioLetterboxdRoute: typeof DashSyncStremioLetterboxdRoute
+  DashSyncStremioMalRoute: typeof DashSyncStremioMalRoute
+  DashSyncStremioSimklRoute: typeof DashSyncStremioSimklRoute
   DashSyncStremioStremioRoute: typeof DashSyncStremioStremioRoute
   DashSyncStremioTraktRoute: typeof DashSyncStremioTraktRoute
   DashSyncIndexRoute: typeof DashSyncIndexRoute
 }
 
 const DashSyncRouteChildren: DashSyncRouteChildren = {
+  DashSyncStremioAnilistRoute: DashSyncStremioAnilistRoute,
+  DashSyncStremioLetterboxdRoute: DashSyncStremioLetterboxdRoute,
+  DashSyncStremioMalRoute: DashSyncStremioMalRoute,
+  DashSyncStremioSimklRoute: DashSyncStremioSimklRoute,
   DashSyncStremioStremioRoute: DashSyncStremioStremioRoute,
   DashSyncStremioTraktRoute: DashSyncStremioTraktRoute,
   DashSyncIndexRoute: DashSyncIndexRoute,
@@ -681,6 +884,7 @@ const DashSyncRouteWithChildren = DashSyncRoute._addFileChildren(
 
 interface DashTorrentRouteChildren {
   DashTorrentInfoRoute: typeof DashTorrentInfoRoute
+  DashTorrentPeerTokensRoute: typeof DashTorrentPeerTokensRoute
   DashTorrentSyncInfoRoute: typeof DashTorrentSyncInfoRoute
   DashTorrentTorznabIndexersRoute: typeof DashTorrentTorznabIndexersRoute
   DashTorrentIndexRoute: typeof DashTorrentIndexRoute
@@ -688,6 +892,7 @@ interface DashTorrentRouteChildren {
 
 const DashTorrentRouteChildren: DashTorrentRouteChildren = {
   DashTorrentInfoRoute: DashTorrentInfoRoute,
+  DashTorrentPeerTokensRoute: DashTorrentPeerTokensRoute,
   DashTorrentSyncInfoRoute: DashTorrentSyncInfoRoute,
   DashTorrentTorznabIndexersRoute: DashTorrentTorznabIndexersRoute,
   DashTorrentIndexRoute: DashTorrentIndexRoute,
@@ -720,12 +925,20 @@ const DashUsenetRouteWithChildren = DashUsenetRoute._addFileChildren(
 )
 
 interface DashVaultRouteChildren {
+  DashVaultAnilistAccountsRoute: typeof DashVaultAnilistAccountsRoute
+  DashVaultLetterboxdAccountsRoute: typeof DashVaultLetterboxdAccountsRoute
+  DashVaultMalAccountsRoute: typeof DashVaultMalAccountsRoute
+  DashVaultSimklAccountsRoute: typeof DashVaultSimklAccountsRoute
   DashVaultStremioAccountsRoute: typeof DashVaultStremioAccountsRoute
   DashVaultTraktAccountsRoute: typeof DashVaultTraktAccountsRoute
   DashVaultIndexRoute: typeof DashVaultIndexRoute
 }
 
 const DashVaultRouteChildren: DashVaultRouteChildren = {
+  DashVaultAnilistAccountsRoute: DashVaultAnilistAccountsRoute,
+  DashVaultLetterboxdAccountsRoute: DashVaultLetterboxdAccountsRoute,
+  DashVaultMalAccountsRoute: DashVaultMalAccountsRoute,
+  DashVaultSimklAccountsRoute: DashVaultSimklAccountsRoute,
   DashVaultStremioAccountsRoute: DashVaultStremioAccountsRoute,
   DashVaultTraktAccountsRoute: DashVaultTraktAccountsRoute,
   DashVaultIndexRoute: DashVaultIndexRoute,
@@ -0,0 +1,554 @@
+import { createFileRoute } from "@tanstack/react-router";
+import { ColumnDef, createColumnHelper } from "@tanstack/react-table";
+import {
+  CopyIcon,
+  KeyRoundIcon,
+  LinkIcon,
+  ListIcon,
+  Pencil,
+  Plus,
+  Trash2,
+} from "lucide-react";
+import { DateTime } from "luxon";
+import { useEffect, useMemo, useState } from "react";
+import { toast } from "sonner";
+import z from "zod";
+
+import {
+  getStremThruListEditableURL,
+  StremThruList,
+  useStremThruListItemMutation,
+  useStremThruListItems,
+  useStremThruListMutation,
+  useStremThruLists,
+} from "@/api/stremthru-list";
+import { DataTable } from "@/components/data-table";
+import { useDataTable } from "@/components/data-table/use-data-table";
+import { Form } from "@/components/form/Form";
+import { useAppForm } from "@/components/form/hook";
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+  AlertDialogTrigger,
+} from "@/components/ui/alert-dialog";
+import { Button } from "@/components/ui/button";
+import { ScrollArea } from "@/components/ui/scroll-area";
+import {
+  Sheet,
+  SheetContent,
+  SheetDescription,
+  SheetFooter,
+  SheetHeader,
+  SheetTitle,
+  SheetTrigger,
+} from "@/components/ui/sheet";
+import {
+  Tooltip,
+  TooltipContent,
+  TooltipTrigger,
+} from "@/components/ui/tooltip";
+import { APIError } from "@/lib/api";
+
+declare module "@/components/data-table" {
+  export interface DataTableMetaCtx {
+    StremThruList: {
+      onEdit: (item: StremThruList) => void;
+      onEditItems: (item: StremThruList) => void;
+      removeList: ReturnType<typeof useStremThruListMutation>["remove"];
+      rotateSecret: ReturnType<
+        typeof useStremThruListMutation
+      >["rotateSecret"];
+    };
+  }
+
+  export interface DataTableMetaCtxKey {
+    StremThruList: StremThruList;
+  }
+}
+
+async function copyToClipboard(text: string, message: string) {
+  await navigator.clipboard.writeText(text);
+  toast.success(message);
+}
+
+const col = createColumnHelper<StremThruList>();
+
+const columns: ColumnDef<StremThruList>[] = [
+  col.accessor("name", {
+    header: "Name",
+  }),
+  col.accessor("owner", {
+    header: "Owner",
+  }),
+  col.accessor("item_count", {
+    header: "Items",
+  }),
+  col.accessor("updated_at", {
+    cell: ({ getValue }) => {
+      const date = DateTime.fromISO(getValue());
+      return date.toLocaleString(DateTime.DATETIME_MED);
+    },
+    header: "Updated At",
+  }),
+  col.display({
+    cell: (c) => {
+      const { onEdit, onEditItems, removeList, rotateSecret } =
+        c.table.options.meta!.ctx;
+      const item = c.row.original;
+      return (
+        <div className="flex gap-1">
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() => copyToClipboard(item.url, "Copied share URL")}
+                size="icon-sm"
+                variant="ghost"
+              >
+                <LinkIcon />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Copy Share URL</TooltipContent>
+          </Tooltip>
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() =>
+                  copyToClipboard(
+                    getStremThruListEditableURL(item),
+                    "Copied editable URL",
+                  )
+                }
+                size="icon-sm"
+                variant="ghost"
+              >
+                <CopyIcon />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Copy Editable URL</TooltipContent>
+          </Tooltip>
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() => onEditItems(item)}
+                size="icon-sm"
+                variant="ghost"
+              >
+                <ListIcon />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Items</TooltipContent>
+          </Tooltip>
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() => onEdit(item)}
+                size="icon-sm"
+                variant="ghost"
+              >
+                <Pencil />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Edit</TooltipContent>
+          </Tooltip>
+          <AlertDialog>
+            <Tooltip>
+              <TooltipTrigger asChild>
+                <AlertDialogTrigger asChild>
+                  <Button size="icon-sm" variant="ghost">
+                    <KeyRoundIcon />
+                  </Button>
+                </AlertDialogTrigger>
+              </TooltipTrigger>
+              <TooltipContent>Rotate Secret</TooltipContent>
+            </Tooltip>
+            <AlertDialogContent>
+              <AlertDialogHeader>
+                <AlertDialogTitle>Rotate List Secret?</AlertDialogTitle>
+                <AlertDialogDescription>
+                  Previously shared editable URLs for{" "}
+                  <strong>{item.name}</strong> will stop working. The share URL
+                  stays the same.
+                </AlertDialogDescription>
+              </AlertDialogHeader>
+              <AlertDialogFooter>
+                <AlertDialogCancel>Cancel</AlertDialogCancel>
+                <AlertDialogAction asChild>
+                  <Button
+                    disabled={rotateSecret.isPending}
+                    onClick={() => {
+                      toast.promise(rotateSecret.mutateAsync(item.id), {
+                        error(err: APIError) {
+                          console.error(err);
+                          return {
+                            closeButton: true,
+                            message: err.message,
+                          };
+                        },
+                        loading: "Rotating...",
+                        success: {
+                          closeButton: true,
+                          message: "Rotated successfully!",
+                        },
+                      });
+                    }}
+                  >
+                    Rotate
+                  </Button>
+                </AlertDialogAction>
+              </AlertDialogFooter>
+            </AlertDialogContent>
+          </AlertDialog>
+          <AlertDialog>
+            <AlertDialogTrigger asChild>
+              <Button size="icon-sm" variant="ghost">
+                <Trash2 className="text-destructive" />
+              </Button>
+            </AlertDialogTrigger>
+            <AlertDialogContent>
+              <AlertDialogHeader>
+                <AlertDialogTitle>Delete StremThru List?</AlertDialogTitle>
+                <AlertDialogDescription>
+                  This will permanently delete the list{" "}
+                  <strong>{item.name}</strong> and all of its items. This
+                  action cannot be undone.
+                </AlertDialogDescription>
+              </AlertDialogHeader>
+              <AlertDialogFooter>
+                <AlertDialogCancel>Cancel</AlertDialogCancel>
+                <AlertDialogAction asChild>
+                  <Button
+                    disabled={removeList.isPending}
+                    onClick={() => {
+                      toast.promise(removeList.mutateAsync(item.id), {
+                        error(err: APIError) {
+                          console.error(err);
+                          return {
+                            closeButton: true,
+                            message: err.message,
+                          };
+                        },
+                        loading: "Deleting...",
+                        success: {
+                          closeButton: true,
+                          message: "Deleted successfully!",
+                        },
+                      });
+                    }}
+                    variant="destructive"
+                  >
+                    Delete
+                  </Button>
+                </AlertDialogAction>
+              </AlertDialogFooter>
+            </AlertDialogContent>
+          </AlertDialog>
+        </div>
+      );
+    },
+    header: "",
+    id: "actions",
+  }),
+];
+
+const listSchema = z.object({
+  description: z.string(),
+  name: z.string().trim().min(1, "Name is required"),
+});
+
+function StremThruListFormSheet({
+  editItem,
+  setEditItem,
+}: {
+  editItem: null | StremThruList;
+  setEditItem: (item: null | StremThruList) => void;
+}) {
+  const [isOpen, setIsOpen] = useState(false);
+  const { create, update } = useStremThruListMutation();
+
+  useEffect(() => {
+    if (editItem) {
+      setIsOpen(true);
+    }
+  }, [editItem]);
+
+  const defaultValues = useMemo(
+    () => ({
+      description: editItem?.description ?? "",
+      name: editItem?.name ?? "",
+    }),
+    [editItem?.description, editItem?.name],
+  );
+
+  const form = useAppForm({
+    canSubmitWhenInvalid: true,
+    defaultValues,
+    onSubmit: async ({ value }) => {
+      value = listSchema.parse(value);
+      if (editItem) {
+        await update.mutateAsync({
+          description: value.description,
+          id: editItem.id,
+          name: value.name,
+        });
+        toast.success("Updated successfully!");
+      } else {
+        await create.mutateAsync({
+          description: value.description,
+          name: value.name,
+        });
+        toast.success("Created successfully!");
+      }
+      setIsOpen(false);
+    },
+    validators: {
+      onChange: listSchema,
+    },
+  });
+
+  useEffect(() => {
+    form.reset(defaultValues);
+  }, [defaultValues, form]);
+
+  return (
+    <Sheet onOpenChange={setIsOpen} open={isOpen}>
+      <SheetTrigger asChild>
+        <Button
+          onClick={() => {
+            setEditItem(null);
+          }}
+          size="sm"
+        >
+          <Plus className="mr-2 size-4" />
+          Create List
+        </Button>
+      </SheetTrigger>
+      <SheetContent asChild>
+        <Form form={form}>
+          <SheetHeader>
+            <SheetTitle>
+              {editItem ? "Edit" : "Create"} StremThru List
+            </SheetTitle>
+            <SheetDescription>
+              {editItem
+                ? "Update the list details."
+                : "Create a new list hosted by StremThru."}
+            </SheetDescription>
+          </SheetHeader>
+
+          <ScrollArea className="overflow-hidden">
+            <div className="flex flex-col gap-4 px-4">
+              <form.AppField name="name">
+                {(field) => <field.Input label="Name" type="text" />}
+              </form.AppField>
+              <form.AppField name="description">
+                {(field) => <field.Textarea label="Description" />}
+              </form.AppField>
+            </div>
+          </ScrollArea>
+
+          <SheetFooter>
+            <form.AppForm>
+              <form.SubmitButton className="w-full">
+                {editItem ? "Update" : "Create"} List
+              </form.SubmitButton>
+            </form.AppForm>
+          </SheetFooter>
+        </Form>
+      </SheetContent>
+    </Sheet>
+  );
+}
+
+const itemSchema = z.object({
+  id: z.string().trim().min(1, "ID is required"),
+  type: z.enum(["", "movie", "series"]),
+});
+
+const itemTypeOptions = [
+  { label: "Movie", value: "movie" },
+  { label: "Series", value: "series" },
+];
+
+function StremThruListItemsSheet({
+  list,
+  setList,
+}: {
+  list: null | StremThruList;
+  setList: (list: null | StremThruList) => void;
+}) {
+  const listId = list?.id ?? "";
+  const items = useStremThruListItems(listId);
+  const { add, remove } = useStremThruListItemMutation(listId);
+
+  const form = useAppForm({
+    canSubmitWhenInvalid: true,
+    defaultValues: {
+      id: "",
+      type: "" as "" | "movie" | "series",
+    },
+    onSubmit: async ({ value }) => {
+      value = itemSchema.parse(value);
+      await add.mutateAsync({
+        id: listId,
+        items: [{ id: value.id, type: value.type || undefined }],
+      });
+      toast.success("Added successfully!");
+      form.reset();
+    },
+    validators: {
+      onChange: itemSchema,
+    },
+  });
+
+  return (
+    <Sheet
+      onOpenChange={(open) => {
+        if (!open) {
+          setList(null);
+        }
+      }}
+      open={Boolean(list)}
+    >
+      <SheetContent>
+        <SheetHeader>
+          <SheetTitle>{list?.name}</SheetTitle>
+          <SheetDescription>
+            Add items by IMDb ID (e.g. tt0903747), TMDB ID (e.g. tmdb:1396) or
+            anime ID (e.g. kitsu:1). Type is required for TMDB IDs.
+          </SheetDescription>
+        </SheetHeader>
+
+        <Form className="flex flex-col gap-4 px-4" form={form}>
+          <form.AppField name="id">
+            {(field) => <field.Input label="ID" type="text" />}
+          </form.AppField>
+          <form.AppField name="type">
+            {(field) => (
+              <field.Select
+                label="Type"
+                options={itemTypeOptions}
+                placeholder="Auto"
+              />
+            )}
+          </form.AppField>
+          <form.AppForm>
+            <form.SubmitButton>Add Item</form.SubmitButton>
+          </form.AppForm>
+        </Form>
+
+        <ScrollArea className="overflow-hidden">
+          <div className="flex flex-col gap-2 px-4 pb-4">
+            {items.isLoading ? (
+              <div className="text-muted-foreground text-sm">Loading...</div>
+            ) : items.isError ? (
+              <div className="text-sm text-red-600">Error loading items</div>
+            ) : items.data?.length ? (
+              items.data.map((item) => (
+                <div
+                  className="flex items-center justify-between gap-2 text-sm"
+                  key={item.id}
+                >
+                  <div className="flex flex-col">
+                    <span>
+                      {item.name || item.id}
+                      {item.year ? ` (${item.year})` : ""}
+                    </span>
+                    <span className="text-muted-foreground text-xs">
+                      {item.id} · {item.type}
+                    </span>
+                  </div>
+                  <Button
+                    disabled={remove.isPending}
+                    onClick={() => {
+                      toast.promise(remove.mutateAsync(item.id), {
+                        error(err: APIError) {
+                          console.error(err);
+                          return {
+                            closeButton: true,
+                            message: err.message,
+                          };
+                        },
+                        loading: "Removing...",
+                        success: {
+                          closeButton: true,
+                          message: "Removed successfully!",
+                        },
+                      });
+                    }}
+                    size="icon-sm"
+                    variant="ghost"
+                  >
+                    <Trash2 className="text-destructive" />
+                  </Button>
+                </div>
+              ))
+            ) : (
+              <div className="text-muted-foreground text-sm">No items</div>
+            )}
+          </div>
+        </ScrollArea>
+      </SheetContent>
+    </Sheet>
+  );
+}
+
+export const Route = createFileRoute("/dash/lists/stremthru")({
+  component: RouteComponent,
+  staticData: {
+    crumb: "StremThru",
+  },
+});
+
+function RouteComponent() {
+  const stremThruLists = useStremThruLists();
+  const { remove: removeList, rotateSecret } = useStremThruListMutation();
+
+  const [editItem, setEditItem] = useState<null | StremThruList>(null);
+  const [itemsList, setItemsList] = useState<null | StremThruList>(null);
+
+  const table = useDataTable({
+    columns,
+    data: stremThruLists.data ?? [],
+    initialState: {
+      columnPinning: { right: ["actions"] },
+    },
+    meta: {
+      ctx: {
+        onEdit: setEditItem,
+        onEditItems: setItemsList,
+        removeList,
+        rotateSecret,
+      },
+    },
+  });
+
+  return (
+    <div className="flex flex-col gap-6">
+      <div className="flex items-center justify-between">
+        <h2 className="text-lg font-semibold">StremThru Lists</h2>
+        <StremThruListFormSheet
+          editItem={editItem}
+          setEditItem={setEditItem}
+        />
+      </div>
+
+      {stremThruLists.isLoading ? (
+        <div className="text-muted-foreground text-sm">Loading...</div>
+      ) : stremThruLists.isError ? (
+        <div className="text-sm text-red-600">
+          Error loading StremThru lists
+        </div>
+      ) : (
+        <DataTable table={table} />
+      )}
+
+      <StremThruListItemsSheet list={itemsList} setList={setItemsList} />
+    </div>
+  );
+}
@@ -222,7 +222,8 @@ function NetworkSection({ network }: { network: ConfigData["network"] }) {
     (network.tunnel_ips ? Object.keys(network.tunnel_ips).length : 0) +
     (network.buddy_url ? 1 : 0) +
     (network.peer_url ? 1 : 0) +
-    (network.pull_peer_url ? 1 : 0);
+    (network.pull_peer_url ? 1 : 0) +
+    (network.federation?.length ?? 0);
   return (
     <CollapsibleConfigSection
       gradient="from-blue-500 to-blue-700"
@@ -251,6 +252,13 @@ function NetworkSection({ network }: { network: ConfigData["network"] }) {
       {network.pull_peer_url && (
         <ConfigEntry label="Pull Peer URL" value={network.pull_peer_url} />
       )}
+      {network.federation?.map((peerUrl, idx) => (
+        <ConfigEntry
+          key={peerUrl}
+          label={`Federation Peer URL [${idx + 1}]`}
+          value={peerUrl}
+        />
+      ))}
     </CollapsibleConfigSection>
   );
 }
@@ -536,6 +544,39 @@ function TorzSection({ torz }: { torz: ConfigData["torz"] }) {
         label="Torrent File Max Size"
         value={torz.torrent_file_max_size}
       />
+      {torz.scrape_trackers && (
+        <>
+          <ConfigEntry
+            label="Scrape Trackers"
+            value={torz.scrape_trackers.join(", ")}
+          />
+          <ConfigEntry label="Scrape Interval" value={torz.scrape_interval} />
+          <ConfigEntry
+            label="Scrape Stale Time"
+            value={torz.scrape_stale_time}
+          />
+          <ConfigEntry
+            label="Scrape Rate Limit"
+            value={torz.scrape_rate_limit}
+          />
+        </>
+      )}
+      {!!torz.metadata_fetch_concurrency && (
+        <>
+          <ConfigEntry
+            label="Metadata Fetch Concurrency"
+            value={torz.metadata_fetch_concurrency}
+          />
+          <ConfigEntry
+            label="Metadata Fetch Timeout"
+            value={torz.metadata_fetch_timeout}
+          />
+          <ConfigEntry
+            label="Metadata Fetch Max Peers"
+            value={torz.metadata_fetch_max_peers}
+          />
+        </>
+      )}
     </CollapsibleConfigSection>
   );
 }
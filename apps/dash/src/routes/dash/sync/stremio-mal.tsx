@@ -0,0 +1,599 @@
+import { createFileRoute, Link } from "@tanstack/react-router";
+import {
+  ArrowLeftRight,
+  ArrowRight,
+  CheckCircle,
+  Link2,
+  Plus,
+  RefreshCw,
+  Trash2,
+  XCircle,
+} from "lucide-react";
+import { DateTime } from "luxon";
+import { useMemo, useState } from "react";
+import { toast } from "sonner";
+
+import {
+  StremioMALLink,
+  SyncDirection,
+  useStremioMALLinkMutation,
+  useStremioMALLinks,
+} from "@/api/sync-stremio-mal";
+import { MALAccount, useMALAccounts } from "@/api/vault-mal-account";
+import {
+  StremioAccount,
+  useStremioAccounts,
+} from "@/api/vault-stremio-account";
+import { Form } from "@/components/form/Form";
+import { useAppForm } from "@/components/form/hook";
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+  AlertDialogTrigger,
+} from "@/components/ui/alert-dialog";
+import { Button } from "@/components/ui/button";
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardFooter,
+  CardHeader,
+  CardTitle,
+} from "@/components/ui/card";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import {
+  Sheet,
+  SheetContent,
+  SheetDescription,
+  SheetHeader,
+  SheetTitle,
+  SheetTrigger,
+} from "@/components/ui/sheet";
+import { APIError } from "@/lib/api";
+
+export const Route = createFileRoute("/dash/sync/stremio-mal")({
+  component: RouteComponent,
+  staticData: {
+    crumb: "Stremio ↔ MyAnimeList",
+  },
+});
+
+const syncDirectionOptions: Array<{
+  icon: typeof ArrowRight;
+  label: string;
+  value: SyncDirection;
+}> = [
+  {
+    icon: XCircle,
+    label: "Disabled",
+    value: "none",
+  },
+  {
+    icon: ArrowRight,
+    label: "Stremio → MyAnimeList",
+    value: "stremio_to_mal",
+  },
+  {
+    icon: ArrowRight,
+    label: "MyAnimeList → Stremio",
+    value: "mal_to_stremio",
+  },
+  {
+    icon: ArrowLeftRight,
+    label: "Bidirectional",
+    value: "both",
+  },
+];
+
+function LinkAccountSheet({
+  malAccounts,
+  onClose,
+  stremioAccounts,
+}: {
+  malAccounts: MALAccount[];
+  onClose: () => void;
+  stremioAccounts: StremioAccount[];
+}) {
+  const { create } = useStremioMALLinkMutation();
+
+  const availableStremioAccounts = stremioAccounts;
+  const availableMALAccounts = malAccounts;
+
+  const form = useAppForm({
+    defaultValues: {
+      mal_account_id: "",
+      stremio_account_id: "",
+    },
+    onSubmit: async ({ value }) => {
+      await create.mutateAsync({
+        mal_account_id: value.mal_account_id,
+        stremio_account_id: value.stremio_account_id,
+        sync_config: { watched: { dir: "none" } },
+      });
+      toast.success("Accounts linked successfully!");
+      onClose();
+    },
+  });
+
+  return (
+    <Form className="flex flex-col gap-4" form={form}>
+      <form.AppField name="stremio_account_id">
+        {(field) => (
+          <div className="flex flex-col gap-2">
+            <label className="text-sm font-medium" htmlFor={field.name}>
+              Stremio Account
+            </label>
+            {availableStremioAccounts.length === 0 ? (
+              <div className="text-muted-foreground text-sm">
+                No available Stremio accounts.{" "}
+                <Link
+                  className="text-primary underline underline-offset-4"
+                  to="/dash/vault/stremio-accounts"
+                >
+                  Add one in Vault
+                </Link>
+                .
+              </div>
+            ) : (
+              <Select
+                onValueChange={(value) => field.handleChange(value)}
+                value={field.state.value}
+              >
+                <SelectTrigger className="w-full">
+                  <SelectValue placeholder="Select Stremio account" />
+                </SelectTrigger>
+                <SelectContent>
+                  {availableStremioAccounts.map((account) => (
+                    <SelectItem key={account.id} value={account.id}>
+                      {account.email}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            )}
+          </div>
+        )}
+      </form.AppField>
+
+      <form.AppField name="mal_account_id">
+        {(field) => (
+          <div className="flex flex-col gap-2">
+            <label className="text-sm font-medium" htmlFor={field.name}>
+              MyAnimeList Account
+            </label>
+            {availableMALAccounts.length === 0 ? (
+              <div className="text-muted-foreground text-sm">
+                No available MyAnimeList accounts.{" "}
+                <Link
+                  className="text-primary underline underline-offset-4"
+                  to="/dash/vault/mal-accounts"
+                >
+                  Add one in Vault
+                </Link>
+                .
+              </div>
+            ) : (
+              <Select
+                onValueChange={(value) => field.handleChange(value)}
+                value={field.state.value}
+              >
+                <SelectTrigger className="w-full">
+                  <SelectValue placeholder="Select MyAnimeList account" />
+                </SelectTrigger>
+                <SelectContent>
+                  {availableMALAccounts.map((account) => (
+                    <SelectItem key={account.id} value={account.id}>
+                      {account.user_name}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            )}
+          </div>
+        )}
+      </form.AppField>
+
+      <form.AppForm>
+        <form.SubmitButton
+          className="w-full"
+          disabled={
+            availableStremioAccounts.length === 0 ||
+            availableMALAccounts.length === 0
+          }
+        >
+          Link Accounts
+        </form.SubmitButton>
+      </form.AppForm>
+    </Form>
+  );
+}
+
+function LinkCard({
+  link,
+  malAccount,
+  stremioAccount,
+}: {
+  link: StremioMALLink;
+  malAccount?: MALAccount;
+  stremioAccount?: StremioAccount;
+}) {
+  const { remove, resetSyncState, sync, update } =
+    useStremioMALLinkMutation();
+
+  const selectedWatchedSyncDirection = syncDirectionOptions.find(
+    (opt) => opt.value === link.sync_config.watched.dir,
+  );
+  const SyncDirectionIcon = selectedWatchedSyncDirection?.icon || XCircle;
+
+  const handleWatchedSyncDirectionChange = (value: string) => {
+    toast.promise(
+      update.mutateAsync({
+        mal_account_id: link.mal_account_id,
+        stremio_account_id: link.stremio_account_id,
+        sync_config: { watched: { dir: value as SyncDirection } },
+      }),
+      {
+        error(err: APIError) {
+          console.error(err);
+          return {
+            closeButton: true,
+            message: err.message,
+          };
+        },
+        loading: "Updating sync direction...",
+        success: {
+          closeButton: true,
+          message: "Sync direction updated!",
+        },
+      },
+    );
+  };
+
+  const handleSync = () => {
+    toast.promise(
+      sync.mutateAsync({
+        mal_account_id: link.mal_account_id,
+        stremio_account_id: link.stremio_account_id,
+      }),
+      {
+        error(err: APIError) {
+          console.error(err);
+          return {
+            closeButton: true,
+            message: err.message,
+          };
+        },
+        loading: "Triggering sync...",
+        success: {
+          closeButton: true,
+          message: "Sync triggered!",
+        },
+      },
+    );
+  };
+
+  const handleUnlink = () => {
+    toast.promise(
+      remove.mutateAsync({
+        mal_account_id: link.mal_account_id,
+        stremio_account_id: link.stremio_account_id,
+      }),
+      {
+        error(err: APIError) {
+          console.error(err);
+          return {
+            closeButton: true,
+            message: err.message,
+          };
+        },
+        loading: "Unlinking...",
+        success: {
+          closeButton: true,
+          message: "Accounts unlinked!",
+        },
+      },
+    );
+  };
+
+  const handleResetSyncState = () => {
+    toast.promise(
+      resetSyncState.mutateAsync({
+        mal_account_id: link.mal_account_id,
+        stremio_account_id: link.stremio_account_id,
+      }),
+      {
+        error(err: APIError) {
+          console.error(err);
+          return {
+            closeButton: true,
+            message: err.message,
+          };
+        },
+        loading: "Resetting sync status...",
+        success: {
+          closeButton: true,
+          message: "Sync status reset! Next sync will be a full sync.",
+        },
+      },
+    );
+  };
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2 text-base">
+          <Link2 className="size-4" />
+          Linked Accounts
+        </CardTitle>
+        <CardDescription>
+          <div className="flex flex-col gap-1">
+            <div>
+              <span className="font-medium">Stremio:</span>{" "}
+              {stremioAccount?.email || link.stremio_account_id}
+            </div>
+            <div>
+              <span className="font-medium">MyAnimeList:</span>{" "}
+              {malAccount?.user_name || link.mal_account_id}
+            </div>
+          </div>
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4">
+        <div className="flex flex-col gap-2">
+          <label className="text-sm font-medium">Watched Sync Direction</label>
+          <Select
+            onValueChange={handleWatchedSyncDirectionChange}
+            value={link.sync_config.watched.dir}
+          >
+            <SelectTrigger className="w-full">
+              <SelectValue>
+                <div className="flex items-center gap-2">
+                  <SyncDirectionIcon className="size-4" />
+                  {selectedWatchedSyncDirection?.label}
+                </div>
+              </SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              {syncDirectionOptions.map((option) => {
+                const OptionIcon = option.icon;
+                return (
+                  <SelectItem key={option.value} value={option.value}>
+                    <div className="flex items-center gap-2">
+                      <OptionIcon className="size-4" />
+                      {option.label}
+                    </div>
+                  </SelectItem>
+                );
+              })}
+            </SelectContent>
+          </Select>
+        </div>
+
+        {link.sync_state.watched.last_synced_at && (
+          <div className="text-muted-foreground flex flex-col gap-1 text-sm">
+            <div className="flex items-center justify-between gap-2">
+              <div className="flex items-center gap-1">
+                <CheckCircle className="size-3.5 text-green-500" />
+                <span>
+                  Last synced:{" "}
+                  {DateTime.fromISO(
+                    link.sync_state.watched.last_synced_at,
+                  ).toLocaleString(DateTime.DATETIME_MED)}
+                </span>
+              </div>
+              <AlertDialog>
+                <AlertDialogTrigger asChild>
+                  <Button size="sm" variant="ghost">
+                    Reset
+                  </Button>
+                </AlertDialogTrigger>
+                <AlertDialogContent>
+                  <AlertDialogHeader>
+                    <AlertDialogTitle>Reset Sync Status?</AlertDialogTitle>
+                    <AlertDialogDescription>
+                      This will clear the last sync timestamp and force a full
+                      re-sync on the next sync operation. This can be useful if
+                      you suspect the sync is incomplete or has missing items.
+                    </AlertDialogDescription>
+                  </AlertDialogHeader>
+                  <AlertDialogFooter>
+                    <AlertDialogCancel>Cancel</AlertDialogCancel>
+                    <AlertDialogAction asChild>
+                      <Button
+                        disabled={resetSyncState.isPending}
+                        onClick={handleResetSyncState}
+                      >
+                        Reset
+                      </Button>
+                    </AlertDialogAction>
+                  </AlertDialogFooter>
+                </AlertDialogContent>
+              </AlertDialog>
+            </div>
+          </div>
+        )}
+      </CardContent>
+      <CardFooter className="mt-auto gap-4">
+        <Button
+          className="hidden flex-1"
+          disabled={link.sync_config.watched.dir === "none" || sync.isPending}
+          onClick={handleSync}
+          size="sm"
+          variant="outline"
+        >
+          <RefreshCw className="mr-2 size-4" />
+          Sync Now
+        </Button>
+        <AlertDialog>
+          <AlertDialogTrigger asChild>
+            <Button size="sm" variant="outline">
+              <Trash2 className="text-destructive mr-2 size-4" />
+              Unlink
+            </Button>
+          </AlertDialogTrigger>
+          <AlertDialogContent>
+            <AlertDialogHeader>
+              <AlertDialogTitle>Unlink Accounts?</AlertDialogTitle>
+              <AlertDialogDescription>
+                This will remove the link between{" "}
+                <strong>
+                  {stremioAccount?.email || "this Stremio account"}
+                </strong>{" "}
+                and{" "}
+                <strong>
+                  {malAccount?.user_name || "this MyAnimeList account"}
+                </strong>
+                . Sync will stop, but your watch history won't be deleted.
+              </AlertDialogDescription>
+            </AlertDialogHeader>
+            <AlertDialogFooter>
+              <AlertDialogCancel>Cancel</AlertDialogCancel>
+              <AlertDialogAction asChild>
+                <Button
+                  disabled={remove.isPending}
+                  onClick={handleUnlink}
+                  variant="destructive"
+                >
+                  Unlink
+                </Button>
+              </AlertDialogAction>
+            </AlertDialogFooter>
+          </AlertDialogContent>
+        </AlertDialog>
+      </CardFooter>
+    </Card>
+  );
+}
+
+function RouteComponent() {
+  const links = useStremioMALLinks();
+  const stremioAccounts = useStremioAccounts();
+  const malAccounts = useMALAccounts();
+
+  const [sheetOpen, setSheetOpen] = useState(false);
+
+  const stremioAccountsById = useMemo(
+    () => new Map(stremioAccounts.data?.map((acc) => [acc.id, acc])),
+    [stremioAccounts.data],
+  );
+  const malAccountsById = useMemo(
+    () => new Map(malAccounts.data?.map((acc) => [acc.id, acc])),
+    [malAccounts.data],
+  );
+
+  const isLoading =
+    links.isLoading || stremioAccounts.isLoading || malAccounts.isLoading;
+  const hasError =
+    links.isError || stremioAccounts.isError || malAccounts.isError;
+
+  return (
+    <div className="flex flex-col gap-6">
+      <div className="flex items-center justify-between">
+        <div>
+          <h2 className="text-lg font-semibold">
+            Stremio ↔ MyAnimeList Sync
+          </h2>
+          <p className="text-muted-foreground text-sm">
+            Link Stremio and MyAnimeList accounts to sync watch history
+          </p>
+        </div>
+        <Sheet onOpenChange={setSheetOpen} open={sheetOpen}>
+          <SheetTrigger asChild>
+            <Button size="sm">
+              <Plus className="mr-2 size-4" />
+              Link Accounts
+            </Button>
+          </SheetTrigger>
+          <SheetContent>
+            <SheetHeader>
+              <SheetTitle>Link Accounts</SheetTitle>
+              <SheetDescription>
+                Choose which Stremio and MyAnimeList accounts to link for sync.
+              </SheetDescription>
+            </SheetHeader>
+            <div className="p-4">
+              {stremioAccounts.data && malAccounts.data && links.data ? (
+                <LinkAccountSheet
+                  malAccounts={malAccounts.data}
+                  onClose={() => setSheetOpen(false)}
+                  stremioAccounts={stremioAccounts.data}
+                />
+              ) : (
+                <div className="text-muted-foreground text-sm">Loading...</div>
+              )}
+            </div>
+          </SheetContent>
+        </Sheet>
+      </div>
+
+      {isLoading ? (
+        <div className="text-muted-foreground text-sm">Loading...</div>
+      ) : hasError ? (
+        <div className="text-sm text-red-600">Error loading data</div>
+      ) : links.data?.length === 0 ? (
+        <Card>
+          <CardContent className="flex flex-col items-center gap-4 py-12">
+            <Link2 className="text-muted-foreground size-12" />
+            <div className="flex flex-col items-center gap-2 text-center">
+              <h3 className="font-semibold">No linked accounts</h3>
+              <p className="text-muted-foreground text-sm">
+                Link your Stremio and MyAnimeList accounts to start syncing
+                watch history
+              </p>
+            </div>
+            {(stremioAccounts.data?.length === 0 ||
+              malAccounts.data?.length === 0) && (
+              <div className="text-muted-foreground flex flex-col gap-1 text-sm">
+                {stremioAccounts.data?.length === 0 && (
+                  <div>
+                    Add a{" "}
+                    <Link
+                      className="text-primary underline underline-offset-4"
+                      to="/dash/vault/stremio-accounts"
+                    >
+                      Stremio account
+                    </Link>
+                  </div>
+                )}
+                {malAccounts.data?.length === 0 && (
+                  <div>
+                    Add a{" "}
+                    <Link
+                      className="text-primary underline underline-offset-4"
+                      to="/dash/vault/mal-accounts"
+                    >
+                      MyAnimeList account
+                    </Link>
+                  </div>
+                )}
+              </div>
+            )}
+          </CardContent>
+        </Card>
+      ) : (
+        <div className="grid gap-4 sm:grid-cols-2">
+          {links.data?.map((link) => (
+            <LinkCard
+              key={`${link.stremio_account_id}:${link.mal_account_id}`}
+              link={link}
+              malAccount={malAccountsById.get(link.mal_account_id)}
+              stremioAccount={stremioAccountsById.get(link.stremio_account_id)}
+            />
+          ))}
+        </div>
+      )}
+    </div>
+  );
+}
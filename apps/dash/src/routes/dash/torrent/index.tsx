@@ -1,5 +1,6 @@
 import { createFileRoute } from "@tanstack/react-router";
 
+import { PeersStatsCard } from "@/components/peers-stats-card";
 import { TorrentCacheStatsCard } from "@/components/torrent-cache-stats-card";
 import { TorrentsStatsCard } from "@/components/torrents-stats-card";
 import { TorznabIndexerStatsCard } from "@/components/torznab-indexer-stats-card";
@@ -19,6 +20,8 @@ function RouteComponent() {
       <TorrentCacheStatsCard />
 
       <TorznabIndexerStatsCard />
+
+      <PeersStatsCard />
     </>
   );
 }
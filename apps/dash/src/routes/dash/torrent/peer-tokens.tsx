@@ -0,0 +1,428 @@
+import { createFileRoute } from "@tanstack/react-router";
+import { ColumnDef, createColumnHelper } from "@tanstack/react-table";
+import { CopyIcon, Pencil, Plus, Trash2 } from "lucide-react";
+import { DateTime } from "luxon";
+import { useEffect, useMemo, useState } from "react";
+import { toast } from "sonner";
+
+import {
+  PeerToken,
+  PeerTokenScope,
+  usePeerTokenMutation,
+  usePeerTokens,
+} from "@/api/peer-token";
+import {
+  useRateLimitConfig,
+  useRateLimitConfigs,
+} from "@/api/ratelimit-config";
+import { DataTable } from "@/components/data-table";
+import { useDataTable } from "@/components/data-table/use-data-table";
+import { Form } from "@/components/form/Form";
+import { useAppForm } from "@/components/form/hook";
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+  AlertDialogTrigger,
+} from "@/components/ui/alert-dialog";
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { ScrollArea } from "@/components/ui/scroll-area";
+import {
+  Sheet,
+  SheetContent,
+  SheetDescription,
+  SheetFooter,
+  SheetHeader,
+  SheetTitle,
+  SheetTrigger,
+} from "@/components/ui/sheet";
+import {
+  Tooltip,
+  TooltipContent,
+  TooltipTrigger,
+} from "@/components/ui/tooltip";
+import { APIError } from "@/lib/api";
+
+declare module "@/components/data-table" {
+  export interface DataTableMetaCtx {
+    PeerToken: {
+      onEdit: (item: PeerToken) => void;
+      removeToken: ReturnType<typeof usePeerTokenMutation>["remove"];
+    };
+  }
+
+  export interface DataTableMetaCtxKey {
+    PeerToken: PeerToken;
+  }
+}
+
+const scopeOptions: Array<{
+  description: string;
+  label: string;
+  value: PeerTokenScope;
+}> = [
+  {
+    description: "List torrent changes",
+    label: "Read Torrents",
+    value: "read-torrents",
+  },
+  {
+    description: "Push torrents to this instance",
+    label: "Push Torrents",
+    value: "push-torrents",
+  },
+  {
+    description: "Check and track magnet cache status",
+    label: "Check Magnet Cache",
+    value: "check-magnet-cache",
+  },
+  {
+    description: "Use the Zilean API",
+    label: "Zilean",
+    value: "zilean",
+  },
+];
+
+function formatOptionalDateTime(value: null | string) {
+  if (!value) {
+    return "-";
+  }
+  return DateTime.fromISO(value).toLocaleString(DateTime.DATETIME_MED);
+}
+
+function RateLimitConfigName({ id }: { id: null | string }) {
+  const conf = useRateLimitConfig(id);
+  return conf ? conf.name : "-";
+}
+
+const col = createColumnHelper<PeerToken>();
+
+const columns: ColumnDef<PeerToken>[] = [
+  col.accessor("name", {
+    header: "Name",
+  }),
+  col.accessor("id", {
+    cell: ({ getValue }) => {
+      const id = getValue();
+      return (
+        <div className="flex items-center gap-1">
+          <span className="font-mono text-xs">{id}</span>
+          <Button
+            onClick={() => {
+              navigator.clipboard.writeText(id);
+              toast.success("Copied to clipboard");
+            }}
+            size="icon-sm"
+            variant="ghost"
+          >
+            <CopyIcon />
+          </Button>
+        </div>
+      );
+    },
+    header: "Token",
+  }),
+  col.accessor("scopes", {
+    cell: ({ getValue }) => {
+      return (
+        <div className="flex flex-wrap gap-1">
+          {getValue().map((scope) => (
+            <Badge key={scope} variant="outline">
+              {scope}
+            </Badge>
+          ))}
+        </div>
+      );
+    },
+    header: "Scopes",
+  }),
+  col.accessor("rate_limit_config_id", {
+    cell: ({ getValue }) => {
+      return <RateLimitConfigName id={getValue()} />;
+    },
+    header: "Rate Limit",
+  }),
+  col.accessor("expires_at", {
+    cell: ({ getValue }) => {
+      const value = getValue();
+      if (value && DateTime.fromISO(value) < DateTime.now()) {
+        return <span className="text-red-500">Expired</span>;
+      }
+      return formatOptionalDateTime(value);
+    },
+    header: "Expires At",
+  }),
+  col.accessor("last_used_at", {
+    cell: ({ getValue }) => formatOptionalDateTime(getValue()),
+    header: "Last Used At",
+  }),
+  col.accessor("usage_count", {
+    header: "Usage",
+  }),
+  col.accessor("created_at", {
+    cell: ({ getValue }) => formatOptionalDateTime(getValue()),
+    header: "Created At",
+  }),
+  col.display({
+    cell: (c) => {
+      const { onEdit, removeToken } = c.table.options.meta!.ctx;
+      const item = c.row.original;
+      return (
+        <div className="flex gap-1">
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() => onEdit(item)}
+                size="icon-sm"
+                variant="ghost"
+              >
+                <Pencil />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Edit</TooltipContent>
+          </Tooltip>
+          <AlertDialog>
+            <AlertDialogTrigger asChild>
+              <Button size="icon-sm" variant="ghost">
+                <Trash2 className="text-destructive" />
+              </Button>
+            </AlertDialogTrigger>
+            <AlertDialogContent>
+              <AlertDialogHeader>
+                <AlertDialogTitle>Revoke Peer Token?</AlertDialogTitle>
+                <AlertDialogDescription>
+                  This will permanently revoke the peer token{" "}
+                  <strong>{item.name}</strong>. Peers using it will lose
+                  access. This action cannot be undone.
+                </AlertDialogDescription>
+              </AlertDialogHeader>
+              <AlertDialogFooter>
+                <AlertDialogCancel>Cancel</AlertDialogCancel>
+                <AlertDialogAction asChild>
+                  <Button
+                    disabled={removeToken.isPending}
+                    onClick={() => {
+                      toast.promise(removeToken.mutateAsync(item.id), {
+                        error(err: APIError) {
+                          console.error(err);
+                          return {
+                            closeButton: true,
+                            message: err.message,
+                          };
+                        },
+                        loading: "Revoking...",
+                        success: {
+                          closeButton: true,
+                          message: "Revoked successfully!",
+                        },
+                      });
+                    }}
+                    variant="destructive"
+                  >
+                    Revoke
+                  </Button>
+                </AlertDialogAction>
+              </AlertDialogFooter>
+            </AlertDialogContent>
+          </AlertDialog>
+        </div>
+      );
+    },
+    header: "",
+    id: "actions",
+  }),
+];
+
+function PeerTokenFormSheet({
+  editItem,
+  setEditItem,
+}: {
+  editItem: null | PeerToken;
+  setEditItem: (item: null | PeerToken) => void;
+}) {
+  const [isOpen, setIsOpen] = useState(false);
+  const rateLimitConfigs = useRateLimitConfigs();
+  const rateLimitConfigOptions = useMemo(() => {
+    return (rateLimitConfigs.data ?? [])?.map((config) => ({
+      label: config.name,
+      value: config.id,
+    }));
+  }, [rateLimitConfigs.data]);
+
+  useEffect(() => {
+    if (editItem) {
+      setIsOpen(true);
+    }
+  }, [editItem]);
+
+  const { create, update } = usePeerTokenMutation();
+
+  const defaultValues = useMemo(
+    () => ({
+      expires_at: editItem?.expires_at
+        ? DateTime.fromISO(editItem.expires_at).toFormat("yyyy-MM-dd'T'HH:mm")
+        : "",
+      name: editItem?.name ?? "",
+      rate_limit_config_id: editItem?.rate_limit_config_id ?? "",
+      scopes: Object.fromEntries(
+        scopeOptions.map((scope) => [
+          scope.value,
+          editItem ? editItem.scopes.includes(scope.value) : true,
+        ]),
+      ) as Record<PeerTokenScope, boolean>,
+    }),
+    [editItem],
+  );
+
+  const form = useAppForm({
+    defaultValues,
+    onSubmit: async ({ value }) => {
+      const params = {
+        expires_at: value.expires_at
+          ? DateTime.fromISO(value.expires_at).toISO({
+              suppressMilliseconds: true,
+            })
+          : null,
+        name: value.name,
+        rate_limit_config_id: value.rate_limit_config_id || null,
+        scopes: scopeOptions
+          .filter((scope) => value.scopes[scope.value] === true)
+          .map((scope) => scope.value),
+      };
+      if (editItem) {
+        await update.mutateAsync({ id: editItem.id, ...params });
+        toast.success("Updated successfully!");
+      } else {
+        await create.mutateAsync(params);
+        toast.success("Created successfully!");
+      }
+      setIsOpen(false);
+    },
+  });
+
+  useEffect(() => {
+    form.reset(defaultValues);
+  }, [defaultValues, form]);
+
+  return (
+    <Sheet onOpenChange={setIsOpen} open={isOpen}>
+      <SheetTrigger asChild>
+        <Button
+          onClick={() => {
+            setEditItem(null);
+          }}
+          size="sm"
+        >
+          <Plus className="mr-2 size-4" />
+          Add Token
+        </Button>
+      </SheetTrigger>
+      <SheetContent asChild>
+        <Form form={form}>
+          <SheetHeader>
+            <SheetTitle>{editItem ? "Edit" : "Add"} Peer Token</SheetTitle>
+            <SheetDescription>
+              {editItem
+                ? "Update the access of this peer token."
+                : "Create a peer token to share this instance's data with a partner."}
+            </SheetDescription>
+          </SheetHeader>
+
+          <ScrollArea className="overflow-hidden">
+            <div className="flex flex-col gap-4 px-4">
+              <form.AppField name="name">
+                {(field) => <field.Input label="Name" required type="text" />}
+              </form.AppField>
+              {scopeOptions.map((scope) => (
+                <form.AppField
+                  key={scope.value}
+                  name={`scopes.${scope.value}`}
+                >
+                  {(field) => (
+                    <field.Checkbox
+                      description={scope.description}
+                      label={scope.label}
+                    />
+                  )}
+                </form.AppField>
+              ))}
+              <form.AppField name="rate_limit_config_id">
+                {(field) => (
+                  <field.Select
+                    label="Rate Limit Config"
+                    options={rateLimitConfigOptions}
+                  />
+                )}
+              </form.AppField>
+              <form.AppField name="expires_at">
+                {(field) => (
+                  <field.Input label="Expires At" type="datetime-local" />
+                )}
+              </form.AppField>
+            </div>
+          </ScrollArea>
+
+          <SheetFooter>
+            <form.SubmitButton className="w-full">
+              {editItem ? "Update" : "Add"} Peer Token
+            </form.SubmitButton>
+          </SheetFooter>
+        </Form>
+      </SheetContent>
+    </Sheet>
+  );
+}
+
+export const Route = createFileRoute("/dash/torrent/peer-tokens")({
+  component: RouteComponent,
+  staticData: {
+    crumb: "Peer Tokens",
+  },
+});
+
+function RouteComponent() {
+  const peerTokens = usePeerTokens();
+  const { remove: removeToken } = usePeerTokenMutation();
+
+  const [editItem, setEditItem] = useState<null | PeerToken>(null);
+  const onEditItem = (item: PeerToken) => {
+    setEditItem(item);
+  };
+
+  const table = useDataTable({
+    columns,
+    data: peerTokens.data ?? [],
+    initialState: {
+      columnPinning: { right: ["actions"] },
+    },
+    meta: {
+      ctx: {
+        onEdit: onEditItem,
+        removeToken,
+      },
+    },
+  });
+
+  return (
+    <div className="flex flex-col gap-6">
+      <div className="flex items-center justify-between">
+        <h2 className="text-lg font-semibold">Peer Tokens</h2>
+        <PeerTokenFormSheet editItem={editItem} setEditItem={setEditItem} />
+      </div>
+
+      {peerTokens.isLoading ? (
+        <div className="text-muted-foreground text-sm">Loading...</div>
+      ) : peerTokens.isError ? (
+        <div className="text-sm text-red-600">Error loading peer tokens</div>
+      ) : (
+        <DataTable table={table} />
+      )}
+    </div>
+  );
+}
@@ -88,6 +88,10 @@ function age(dateString: string): null | string {
     });
 }
 
+function formatCompleteness(completeness: number): string {
+  return `${Math.floor(completeness * 1000) / 10}%`;
+}
+
 function formatDuration(ms: number): string {
   const dur = Duration.fromMillis(ms);
   return dur
@@ -195,6 +199,23 @@ const columns: ColumnDef<NZBInfoItem>[] = [
     },
     header: "Age",
   }),
+  col.accessor("health", {
+    cell: ({ getValue }) => {
+      const health = getValue();
+      if (!health) return <span className="text-muted-foreground">-</span>;
+      return (
+        <Tooltip>
+          <TooltipTrigger>
+            {formatCompleteness(health.completeness)}
+          </TooltipTrigger>
+          <TooltipContent>
+            {health.sample_count} of {health.segment_count} segments checked
+          </TooltipContent>
+        </Tooltip>
+      );
+    },
+    header: "Health",
+  }),
   col.accessor("inspection_meta", {
     cell: ({ getValue }) => {
       const stats = getValue();
@@ -634,6 +655,35 @@ function NzbInfoDetailDialog({
                 </>
               )}
             </div>
+            {item.health && (
+              <div>
+                <div className="text-muted-foreground mb-2 text-sm font-medium">
+                  Health ({formatCompleteness(item.health.completeness)},{" "}
+                  {item.health.sample_count} of {item.health.segment_count}{" "}
+                  segments checked)
+                </div>
+                <div className="flex flex-col gap-1 rounded-md border p-2 text-xs">
+                  {item.health.providers.map((provider) => (
+                    <div
+                      className="flex items-center justify-between gap-2"
+                      key={provider.id}
+                    >
+                      <span className="break-all">
+                        {provider.id}
+                        {provider.is_backup ? " (backup)" : ""}
+                      </span>
+                      {provider.error ? (
+                        <span className="text-red-600">{provider.error}</span>
+                      ) : (
+                        <span>
+                          {provider.available}/{provider.checked}
+                        </span>
+                      )}
+                    </div>
+                  ))}
+                </div>
+              </div>
+            )}
             {item.files && item.files.length > 0 && (
               <div>
                 <div className="text-muted-foreground mb-2 text-sm font-medium">
@@ -43,6 +43,7 @@ export default defineConfig({
           { text: "Docker Compose", link: "/guides/docker-compose" },
           { text: "Stream Filter", link: "/guides/stream-filter" },
           { text: "Usenet Setup", link: "/guides/usenet-setup" },
+          { text: "CLI", link: "/guides/cli" },
         ],
       },
       {
@@ -65,6 +66,7 @@ export default defineConfig({
         items: [
           { text: "Overview", link: "/stremio-addons/" },
           { text: "List", link: "/stremio-addons/list" },
+          { text: "Meta", link: "/stremio-addons/meta" },
           { text: "Store", link: "/stremio-addons/store" },
           { text: "Wrap", link: "/stremio-addons/wrap" },
           { text: "Torz", link: "/stremio-addons/torz" },
@@ -81,6 +83,7 @@ export default defineConfig({
           { text: "Newz", link: "/api/newz" },
           { text: "Torz", link: "/api/torz" },
           { text: "Meta", link: "/api/meta" },
+          { text: "Zilean", link: "/api/zilean" },
         ],
       },
       {
@@ -91,7 +94,9 @@ export default defineConfig({
           { text: "GitHub", link: "/integrations/github" },
           { text: "Letterboxd", link: "/integrations/letterboxd" },
           { text: "MDBList", link: "/integrations/mdblist" },
+          { text: "MyAnimeList", link: "/integrations/myanimelist" },
           { text: "Serializd", link: "/integrations/serializd" },
+          { text: "Simkl", link: "/integrations/simkl" },
           { text: "TMDB", link: "/integrations/tmdb" },
           { text: "TVDB", link: "/integrations/tvdb" },
           { text: "Trakt", link: "/integrations/trakt" },
@@ -48,6 +48,26 @@ func (titles AniDBTitles) GetYear(anidbId string) int {
 	return 0
 }
 
+// GetTitle returns the official title in lang (ISO 639-1), falling back to
+// the main title.
+func (titles AniDBTitles) GetTitle(anidbId string, lang string) string {
+	title := ""
+	for _, t := range titles {
+		if t.TId != anidbId {
+			continue
+		}
+		switch t.TType {
+		case "official":
+			if t.TLang == lang {
+				return t.Value
+			}
+		case "main":
+			title = t.Value
+		}
+	}
+	return title
+}
+
 func (titles AniDBTitles) SeasonBoundary() (int, int) {
 	start, end := 1, 1
 	for _, t := range titles {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/util"
@@ -114,6 +115,25 @@ func DeleteTorrentsByHashes(hashes []string) error {
 }
 
 func UpsertTorrents(items []AniDBTorrent) error {
+	return upsertTorrents(items, query_upsert_torrents_after_values)
+}
+
+var query_upsert_changed_torrents_after_values = query_upsert_torrents_after_values + fmt.Sprintf(
+	" WHERE %s",
+	strings.Join([]string{
+		fmt.Sprintf(`%s.%s != EXCLUDED.%s`, TorrentTableName, TorrentColumn.EpisodeStart, TorrentColumn.EpisodeStart),
+		fmt.Sprintf(`%s.%s != EXCLUDED.%s`, TorrentTableName, TorrentColumn.EpisodeEnd, TorrentColumn.EpisodeEnd),
+		fmt.Sprintf(`%s.%s != EXCLUDED.%s`, TorrentTableName, TorrentColumn.Episodes, TorrentColumn.Episodes),
+	}, " OR "),
+)
+
+// UpsertChangedTorrents is same as UpsertTorrents, except the existing rows
+// are updated only if the episodes are different.
+func UpsertChangedTorrents(items []AniDBTorrent) error {
+	return upsertTorrents(items, query_upsert_changed_torrents_after_values)
+}
+
+func upsertTorrents(items []AniDBTorrent, afterValues string) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -133,7 +153,7 @@ func UpsertTorrents(items []AniDBTorrent) error {
 			args[idx+6] = item.Episodes
 		}
 
-		query := query_upsert_torrents_before_values + util.RepeatJoin(query_upsert_torrents_values_placeholder, count, ",") + query_upsert_torrents_after_values
+		query := query_upsert_torrents_before_values + util.RepeatJoin(query_upsert_torrents_values_placeholder, count, ",") + afterValues
 		_, err := db.Exec(query, args...)
 		if err != nil {
 			log.Error("failed to insert anidb torrent", "error", err)
@@ -421,3 +441,50 @@ func GetMappingsByHashes(hashes []string, cursor string, limit int, includeMappe
 
 	return items, nil
 }
+
+var query_list_changed_torrents = fmt.Sprintf(
+	"SELECT %s FROM %s WHERE %s > ? AND %s <= ? ORDER BY %s",
+	strings.Join(TorrentColumns, ","),
+	TorrentTableName,
+	TorrentColumn.UAt,
+	TorrentColumn.UAt,
+	TorrentColumn.UAt,
+)
+
+// ListChangedTorrents lists the items updated after `after` till `until`,
+// ordered by update time. No limit if `limit` is 0.
+func ListChangedTorrents(after, until time.Time, limit int) ([]AniDBTorrent, error) {
+	query := query_list_changed_torrents
+	args := []any{db.Timestamp{Time: after}, db.Timestamp{Time: until}}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []AniDBTorrent{}
+	for rows.Next() {
+		var item AniDBTorrent
+		if err := rows.Scan(
+			&item.TId,
+			&item.Hash,
+			&item.SeasonType,
+			&item.Season,
+			&item.EpisodeStart,
+			&item.EpisodeEnd,
+			&item.Episodes,
+			&item.UAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -183,6 +183,20 @@ func (m AniDBTVDBEpisodeMap) GetTMDBEpisode(anidbEpisode int) int {
 	return tvdbEpisodes[0]
 }
 
+func (m AniDBTVDBEpisodeMap) toAniDBEpisode(tvdbEpisode int) int {
+	anidbEpisode := tvdbEpisode - m.Offset
+	if anidbEpisode < 1 {
+		return -1
+	}
+	if m.Start != 0 && anidbEpisode < m.Start {
+		return -1
+	}
+	if m.End != 0 && anidbEpisode > m.End {
+		return -1
+	}
+	return anidbEpisode
+}
+
 var TVDBEpisodeMapColumn = struct {
 	AniDBId     string
 	TVDBId      string
@@ -313,6 +327,74 @@ func (ms AniDBTVDBEpisodeMaps) GetByAnidbEpisode(ep int) *AniDBTVDBEpisodeMap {
 	return nil
 }
 
+// GetAniDBEpisode maps a tvdb episode back to the regular anidb episode,
+// -1 if it is not part of the anime. Explicit episode mappings win over
+// offsets, and season mappings win over the absolute order.
+func (ms AniDBTVDBEpisodeMaps) GetAniDBEpisode(tvdbSeason, tvdbEpisode, tvdbAbsoluteEpisode int) int {
+	for i := range ms {
+		m := &ms[i]
+		if !m.IsAniDBRegularSeason() {
+			continue
+		}
+		episode := tvdbEpisode
+		if m.HasAbsoluteOrder() {
+			episode = tvdbAbsoluteEpisode
+		} else if m.TVDBSeason != tvdbSeason {
+			continue
+		}
+		for anidbEpisode, tvdbEpisodes := range m.Map {
+			if slices.Contains(tvdbEpisodes, episode) {
+				return anidbEpisode
+			}
+		}
+	}
+	for i := range ms {
+		m := &ms[i]
+		if m.IsAniDBRegularSeason() && !m.HasAbsoluteOrder() && m.TVDBSeason == tvdbSeason {
+			if ep := m.toAniDBEpisode(tvdbEpisode); ep != -1 {
+				return ep
+			}
+		}
+	}
+	if tvdbAbsoluteEpisode > 0 {
+		for i := range ms {
+			m := &ms[i]
+			if m.IsAniDBRegularSeason() && m.HasAbsoluteOrder() {
+				if ep := m.toAniDBEpisode(tvdbAbsoluteEpisode); ep != -1 {
+					return ep
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// GetTVDBEpisode maps a regular anidb episode to the tvdb episode, the season
+// is -1 when the episode is in the absolute order.
+func (ms AniDBTVDBEpisodeMaps) GetTVDBEpisode(anidbEpisode int) (season, episode int, ok bool) {
+	if anidbEpisode <= 0 {
+		return 0, 0, false
+	}
+	for i := range ms {
+		m := &ms[i]
+		if !m.IsAniDBRegularSeason() || m.HasAbsoluteOrder() {
+			continue
+		}
+		if ep := m.GetTMDBEpisode(anidbEpisode); ep > 0 {
+			return m.TVDBSeason, ep, true
+		}
+	}
+	for i := range ms {
+		m := &ms[i]
+		if m.IsAniDBRegularSeason() && m.HasAbsoluteOrder() {
+			if ep := m.GetTMDBEpisode(anidbEpisode); ep > 0 {
+				return -1, ep, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
 func (ms AniDBTVDBEpisodeMaps) GetTVDBId() string {
 	return ms[0].TVDBId
 }
@@ -550,3 +632,115 @@ func GetTVDBEpisodeMaps(anidbId string, includeRelated bool) (*AniDBTVDBEpisodeM
 	maps.Sort()
 	return &AniDBTVDBEpisodeMapsResult{AniDBTVDBEpisodeMaps: maps}, nil
 }
+
+var query_get_tvdb_episode_maps_by_anidbids = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s IN `,
+	db.JoinColumnNames(TVDBEpisodeMapColumns...),
+	TVDBEpisodeMapTableName,
+	TVDBEpisodeMapColumn.AniDBId,
+)
+
+// GetTVDBEpisodeMapsByAniDBIds returns the episode maps of the anime, grouped
+// by the anidb id.
+func GetTVDBEpisodeMapsByAniDBIds(anidbIds []string) (map[string]AniDBTVDBEpisodeMaps, error) {
+	count := len(anidbIds)
+	if count == 0 {
+		return nil, nil
+	}
+
+	query := query_get_tvdb_episode_maps_by_anidbids + "(" + util.RepeatJoin("?", count, ",") + ")"
+	args := make([]any, count)
+	for i, id := range anidbIds {
+		args[i] = id
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mapsByAniDBId := make(map[string]AniDBTVDBEpisodeMaps, count)
+	for rows.Next() {
+		m := AniDBTVDBEpisodeMap{
+			Before: AniDBTVDBEpisodeMapBefore{},
+			Map:    AniDBTVDBEpisodeMapMap{},
+		}
+		if err := rows.Scan(
+			&m.AniDBId,
+			&m.TVDBId,
+			&m.AniDBSeason,
+			&m.TVDBSeason,
+			&m.Start,
+			&m.End,
+			&m.Offset,
+			&m.Before,
+			&m.Map,
+		); err != nil {
+			return nil, err
+		}
+		mapsByAniDBId[m.AniDBId] = append(mapsByAniDBId[m.AniDBId], m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, maps := range mapsByAniDBId {
+		maps.Sort()
+	}
+	return mapsByAniDBId, nil
+}
+
+var query_get_tvdb_episode_maps_by_tvdbid = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s IN `,
+	db.JoinColumnNames(TVDBEpisodeMapColumns...),
+	TVDBEpisodeMapTableName,
+	TVDBEpisodeMapColumn.TVDBId,
+)
+
+// GetTVDBEpisodeMapsByTVDBIds returns the episode maps of the anime of the
+// tvdb series, grouped by the tvdb id.
+func GetTVDBEpisodeMapsByTVDBIds(tvdbIds []string) (map[string]AniDBTVDBEpisodeMaps, error) {
+	count := len(tvdbIds)
+	if count == 0 {
+		return nil, nil
+	}
+
+	query := query_get_tvdb_episode_maps_by_tvdbid + "(" + util.RepeatJoin("?", count, ",") + ")"
+	args := make([]any, count)
+	for i, id := range tvdbIds {
+		args[i] = id
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mapsByTVDBId := make(map[string]AniDBTVDBEpisodeMaps, count)
+	for rows.Next() {
+		m := AniDBTVDBEpisodeMap{
+			Before: AniDBTVDBEpisodeMapBefore{},
+			Map:    AniDBTVDBEpisodeMapMap{},
+		}
+		if err := rows.Scan(
+			&m.AniDBId,
+			&m.TVDBId,
+			&m.AniDBSeason,
+			&m.TVDBSeason,
+			&m.Start,
+			&m.End,
+			&m.Offset,
+			&m.Before,
+			&m.Map,
+		); err != nil {
+			return nil, err
+		}
+		mapsByTVDBId[m.TVDBId] = append(mapsByTVDBId[m.TVDBId], m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, maps := range mapsByTVDBId {
+		maps.Sort()
+	}
+	return mapsByTVDBId, nil
+}
@@ -0,0 +1,143 @@
+package anidb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAniDBTVDBEpisodeMapsGetAniDBEpisode(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		maps     AniDBTVDBEpisodeMaps
+		season   int
+		episode  int
+		absolute int
+		expected int
+	}{
+		{
+			name:     "same season",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 2}},
+			season:   2,
+			episode:  5,
+			absolute: 30,
+			expected: 5,
+		},
+		{
+			name:     "different season",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 2}},
+			season:   1,
+			episode:  5,
+			absolute: 5,
+			expected: -1,
+		},
+		{
+			name:     "second cour with offset",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Offset: 12}},
+			season:   1,
+			episode:  13,
+			expected: 1,
+		},
+		{
+			name:     "before offset",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Offset: 12}},
+			season:   1,
+			episode:  12,
+			expected: -1,
+		},
+		{
+			name:     "outside range",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Start: 1, End: 12}},
+			season:   1,
+			episode:  13,
+			expected: -1,
+		},
+		{
+			name:     "absolute order",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: -1, Offset: 100}},
+			season:   3,
+			episode:  1,
+			absolute: 101,
+			expected: 1,
+		},
+		{
+			name: "explicit mapping",
+			maps: AniDBTVDBEpisodeMaps{{
+				AniDBSeason: 1,
+				TVDBSeason:  1,
+				Map:         AniDBTVDBEpisodeMapMap{5: {7}},
+			}},
+			season:   1,
+			episode:  7,
+			expected: 5,
+		},
+		{
+			name:     "special season is ignored",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 0, TVDBSeason: 0}},
+			season:   0,
+			episode:  1,
+			expected: -1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.maps.GetAniDBEpisode(tc.season, tc.episode, tc.absolute))
+		})
+	}
+}
+
+func TestAniDBTVDBEpisodeMapsGetTVDBEpisode(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		maps     AniDBTVDBEpisodeMaps
+		episode  int
+		season   int
+		expected int
+		ok       bool
+	}{
+		{
+			name:     "second cour with offset",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Offset: 12}},
+			episode:  1,
+			season:   1,
+			expected: 13,
+			ok:       true,
+		},
+		{
+			name: "explicit mapping",
+			maps: AniDBTVDBEpisodeMaps{{
+				AniDBSeason: 1,
+				TVDBSeason:  2,
+				Map:         AniDBTVDBEpisodeMapMap{5: {7}},
+			}},
+			episode:  5,
+			season:   2,
+			expected: 7,
+			ok:       true,
+		},
+		{
+			name:     "absolute order",
+			maps:     AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: -1, Offset: 100}},
+			episode:  1,
+			season:   -1,
+			expected: 101,
+			ok:       true,
+		},
+		{
+			name:    "outside range",
+			maps:    AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Start: 1, End: 12}},
+			episode: 13,
+		},
+		{
+			name:    "invalid episode",
+			maps:    AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1}},
+			episode: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			season, episode, ok := tc.maps.GetTVDBEpisode(tc.episode)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.season, season)
+			assert.Equal(t, tc.expected, episode)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package anilist
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/hasura/go-graphql-client"
+	"golang.org/x/oauth2"
+)
+
+type APIClient struct {
+	client *graphql.Client
+}
+
+var apiClientCache = cache.NewLRUCache[APIClient](&cache.CacheConfig{
+	Lifetime: 1 * time.Hour,
+	Name:     "anilist:api-client",
+})
+
+func GetAPIClient(tokenId string) *APIClient {
+	if tokenId == "" {
+		panic("tokenId cannot be empty")
+	}
+
+	var cachedClient APIClient
+	if apiClientCache.Get(tokenId, &cachedClient) {
+		return &cachedClient
+	}
+
+	httpClient := config.GetHTTPClient(config.TUNNEL_TYPE_AUTO)
+	if otok, _ := oauth.GetOAuthTokenById(tokenId); otok != nil {
+		httpClient = oauth2.NewClient(
+			context.WithValue(context.Background(), oauth2.HTTPClient, httpClient),
+			oauth.DatabaseTokenSource(&oauth.DatabaseTokenSourceConfig{
+				OAuth:             &oauth.AniListOAuthConfig.Config,
+				TokenSourceConfig: oauth.AniListTokenSourceConfig,
+			}, otok.ToToken()),
+		)
+	}
+
+	c := &APIClient{
+		client: graphql.NewClient(
+			"https://graphql.anilist.co/graphql",
+			httpClient,
+			graphql.WithRetry(3),
+			graphql.WithRetryBaseDelay(2*time.Second),
+			graphql.WithRetryExponentialRate(2),
+			graphql.WithRetryHTTPStatus([]int{http.StatusTooManyRequests}),
+		).WithDebug(config.Environment == config.EnvDev),
+	}
+
+	apiClientCache.Add(tokenId, *c)
+
+	return c
+}
+
+type MediaListStatus string
+
+const (
+	MediaListStatusCurrent   MediaListStatus = "CURRENT"
+	MediaListStatusPlanning  MediaListStatus = "PLANNING"
+	MediaListStatusCompleted MediaListStatus = "COMPLETED"
+	MediaListStatusDropped   MediaListStatus = "DROPPED"
+	MediaListStatusPaused    MediaListStatus = "PAUSED"
+	MediaListStatusRepeating MediaListStatus = "REPEATING"
+)
+
+type Viewer struct {
+	Id   int
+	Name string
+}
+
+type getViewerQuery struct {
+	Viewer Viewer
+}
+
+func (c APIClient) GetViewer() (*Viewer, error) {
+	var q getViewerQuery
+	if err := c.client.Query(context.Background(), &q, nil); err != nil {
+		return nil, err
+	}
+	return &q.Viewer, nil
+}
+
+type MediaListEntry struct {
+	MediaId   int
+	Status    MediaListStatus
+	Progress  int
+	Episodes  int
+	UpdatedAt time.Time
+}
+
+type getAnimeListEntriesQuery struct {
+	MediaListCollection struct {
+		Lists []struct {
+			Entries []struct {
+				MediaId   int
+				Status    MediaListStatus
+				Progress  int
+				UpdatedAt int64
+				Media     struct {
+					Episodes int
+				}
+			}
+		}
+	} `graphql:"MediaListCollection(userId: $userId, type: ANIME, status_in: $statusIn)"`
+}
+
+func (c APIClient) GetAnimeListEntries(userId int, statuses ...MediaListStatus) ([]MediaListEntry, error) {
+	if len(statuses) == 0 {
+		statuses = []MediaListStatus{MediaListStatusCurrent, MediaListStatusCompleted, MediaListStatusRepeating}
+	}
+	var q getAnimeListEntriesQuery
+	err := c.client.Query(context.Background(), &q, map[string]any{
+		"userId":   userId,
+		"statusIn": statuses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]struct{}{}
+	entries := []MediaListEntry{}
+	for i := range q.MediaListCollection.Lists {
+		for _, e := range q.MediaListCollection.Lists[i].Entries {
+			// custom lists repeat the entries from the status lists
+			if _, ok := seen[e.MediaId]; ok {
+				continue
+			}
+			seen[e.MediaId] = struct{}{}
+			entries = append(entries, MediaListEntry{
+				MediaId:   e.MediaId,
+				Status:    e.Status,
+				Progress:  e.Progress,
+				Episodes:  e.Media.Episodes,
+				UpdatedAt: time.Unix(e.UpdatedAt, 0),
+			})
+		}
+	}
+	return entries, nil
+}
+
+type saveMediaListEntryMutation struct {
+	SaveMediaListEntry struct {
+		MediaId   int
+		Status    MediaListStatus
+		Progress  int
+		UpdatedAt int64
+	} `graphql:"SaveMediaListEntry(mediaId: $mediaId, status: $status, progress: $progress)"`
+}
+
+type SaveMediaListEntryParams struct {
+	MediaId  int
+	Status   MediaListStatus
+	Progress int
+}
+
+func (c APIClient) SaveMediaListEntry(params *SaveMediaListEntryParams) (*MediaListEntry, error) {
+	var m saveMediaListEntryMutation
+	err := c.client.Mutate(context.Background(), &m, map[string]any{
+		"mediaId":  params.MediaId,
+		"status":   params.Status,
+		"progress": params.Progress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MediaListEntry{
+		MediaId:   m.SaveMediaListEntry.MediaId,
+		Status:    m.SaveMediaListEntry.Status,
+		Progress:  m.SaveMediaListEntry.Progress,
+		UpdatedAt: time.Unix(m.SaveMediaListEntry.UpdatedAt, 0),
+	}, nil
+}
+
+type fetchAnimeEpisodeCountQuery struct {
+	Page struct {
+		Media []struct {
+			Id       int
+			Episodes int
+		} `graphql:"media(type: ANIME, id_in: $ids)"`
+	} `graphql:"Page(page: $page, perPage: 50)"`
+}
+
+func (c APIClient) FetchAnimeEpisodeCount(mediaIds []int) (map[int]int, error) {
+	countById := make(map[int]int, len(mediaIds))
+	for cIds := range slices.Chunk(mediaIds, 50) {
+		var q fetchAnimeEpisodeCountQuery
+		err := c.client.Query(context.Background(), &q, map[string]any{
+			"page": 1,
+			"ids":  cIds,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range q.Page.Media {
+			countById[m.Id] = m.Episodes
+		}
+	}
+	return countById, nil
+}
@@ -180,18 +180,68 @@ var IdMapColumns = []string{
 }
 
 var query_get_id_map = fmt.Sprintf(
-	"SELECT %s FROM %s WHERE %s IN ",
+	"SELECT %s FROM %s WHERE ",
 	strings.Join(IdMapColumns, ","),
 	IdMapTableName,
-	IdMapColumn.AniList,
 )
 
 func GetIdMapsForAniList(ids []int) ([]AnimeIdMap, error) {
+	strIds := make([]string, len(ids))
+	for i := range ids {
+		strIds[i] = strconv.Itoa(ids[i])
+	}
+	return getIdMapsByColumn(IdMapColumn.AniList, strIds)
+}
+
+func GetIdMapsForMAL(ids []int) ([]AnimeIdMap, error) {
+	strIds := make([]string, len(ids))
+	for i := range ids {
+		strIds[i] = strconv.Itoa(ids[i])
+	}
+	return getIdMapsByColumn(IdMapColumn.MAL, strIds)
+}
+
+func GetIdMapsForKitsu(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.Kitsu, ids)
+}
+
+func GetIdMapsForAniDB(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.AniDB, ids)
+}
+
+func GetIdMapsForAniSearch(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.AniSearch, ids)
+}
+
+func GetIdMapsForAnimePlanet(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.AnimePlanet, ids)
+}
+
+func GetIdMapsForLiveChart(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.LiveChart, ids)
+}
+
+func GetIdMapsForNotifyMoe(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.NotifyMoe, ids)
+}
+
+func GetIdMapsForIMDB(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.IMDB, ids)
+}
+
+func GetIdMapsForTVDB(ids []string) ([]AnimeIdMap, error) {
+	return getIdMapsByColumn(IdMapColumn.TVDB, ids)
+}
+
+func getIdMapsByColumn(column string, ids []string) ([]AnimeIdMap, error) {
 	count := len(ids)
-	query := query_get_id_map + "(" + util.RepeatJoin("?", count, ",") + ")"
+	if count == 0 {
+		return []AnimeIdMap{}, nil
+	}
+	query := query_get_id_map + column + " IN (" + util.RepeatJoin("?", count, ",") + ")"
 	args := make([]any, count)
 	for i := range ids {
-		args[i] = strconv.Itoa(ids[i])
+		args[i] = ids[i]
 	}
 	rows, err := db.Query(query, args...)
 	if err != nil {
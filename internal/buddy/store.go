@@ -2,8 +2,6 @@ package buddy
 
 import (
 	"regexp"
-	"slices"
-	"sync"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/core"
@@ -23,13 +21,6 @@ var Buddy = NewAPIClient(&APIClientConfig{
 
 var buddyLog = logger.Scoped("buddy")
 
-var Peer = peer.NewAPIClient(&peer.APIClientConfig{
-	BaseURL: config.PeerURL,
-	APIKey:  config.PeerAuthToken,
-})
-
-var peerLog = logger.Scoped("buddy:upstream")
-
 func TrackMagnet(s store.Store, hash string, name string, size int64, private bool, files []store.MagnetFile, tInfoCategory torrent_info.TorrentInfoCategory, cacheMiss bool, storeToken string) {
 	storeCode := s.GetName().Code()
 	if storeCode.HasUntrustedData() {
@@ -78,7 +69,7 @@ func TrackMagnet(s store.Store, hash string, name string, size int64, private bo
 		}
 	}
 
-	if config.HasPeer && config.PeerAuthToken != "" {
+	if peer.Federation.CanPush() {
 		params := &peer.TrackMagnetParams{
 			StoreName:           s.GetName(),
 			StoreToken:          storeToken,
@@ -94,14 +85,7 @@ func TrackMagnet(s store.Store, hash string, name string, size int64, private bo
 				},
 			},
 		}
-		go func() {
-			start := time.Now()
-			if _, err := Peer.TrackMagnet(params); err != nil {
-				peerLog.Error("failed to track magnet cache", "store.name", s.GetName(), "hash", hash, "error", core.PackError(err), "duration", time.Since(start))
-			} else {
-				peerLog.Info("track magnet cache", "store.name", s.GetName(), "hash", hash, "duration", time.Since(start))
-			}
-		}()
+		go peer.Federation.TrackMagnet(params)
 	}
 }
 
@@ -142,7 +126,7 @@ func BulkTrackMagnet(s store.Store, tInfos []TorrentInfoInput, cached map[string
 		}
 	}
 
-	if config.HasPeer && config.PeerAuthToken != "" {
+	if peer.Federation.CanPush() {
 		params := &peer.TrackMagnetParams{
 			StoreName:           s.GetName(),
 			StoreToken:          storeToken,
@@ -150,14 +134,7 @@ func BulkTrackMagnet(s store.Store, tInfos []TorrentInfoInput, cached map[string
 			TorrentInfos:        tInfos,
 			Cached:              cached,
 		}
-		go func() {
-			start := time.Now()
-			if _, err := Peer.TrackMagnet(params); err != nil {
-				peerLog.Error("failed to bulk track magnet cache", "error", core.PackError(err), "hash_count", len(tInfos), "store.name", s.GetName(), "duration", time.Since(start))
-			} else {
-				peerLog.Info("bulk track magnet cache", "hash_count", len(tInfos), "store.name", s.GetName(), "duration", time.Since(start))
-			}
-		}()
+		go peer.Federation.TrackMagnet(params)
 	}
 }
 
@@ -273,7 +250,7 @@ func CheckMagnet(s store.Store, hashes []string, storeToken string, clientIp str
 		}
 	}
 
-	if checkPeer && config.HasPeer {
+	if checkPeer && peer.Federation.HasPeers() {
 		if config.PeerFlag.Lazy {
 			storeCode := string(s.GetName().Code())
 			for _, hash := range staleOrMissingHashes {
@@ -288,68 +265,49 @@ func CheckMagnet(s store.Store, hashes []string, storeToken string, clientIp str
 			return data, nil
 		}
 
-		if Peer.IsHaltedCheckMagnet() {
+		if peer.Federation.IsHaltedCheckMagnet() {
 			return data, nil
 		}
 
-		var mu sync.Mutex
-		var wg sync.WaitGroup
+		res := peer.Federation.CheckMagnet(&peer.FederationCheckMagnetParams{
+			StoreName:  s.GetName(),
+			StoreToken: storeToken,
+			Hashes:     staleOrMissingHashes,
+			ClientIP:   clientIp,
+			SId:        sid,
+		})
 		filesByHash := map[string]torrent_stream.Files{}
-		for cHashes := range slices.Chunk(staleOrMissingHashes, 500) {
-			wg.Go(func() {
-
-				params := &peer.CheckMagnetParams{
-					StoreName:  s.GetName(),
-					StoreToken: storeToken,
-				}
-				params.Magnets = cHashes
-				params.ClientIP = clientIp
-				params.SId = sid
-				start := time.Now()
-				res, err := Peer.CheckMagnet(params)
-				duration := time.Since(start)
-				if duration.Seconds() > 10 {
-					Peer.HaltCheckMagnet()
-				}
-				if err != nil {
-					peerLog.Error("failed partially to check magnet", "store.name", s.GetName(), "error", core.PackError(err), "duration", duration)
-				} else {
-					mu.Lock()
-					defer mu.Unlock()
-
-					peerLog.Info("check magnet", "store.name", s.GetName(), "hash_count", len(cHashes), "duration", duration)
-					for _, item := range res.Data.Items {
-						files := torrent_stream.Files{}
-						if item.Status == store.MagnetStatusCached {
-							seenByName := map[string]struct{}{}
-							for _, f := range item.Files {
-								key := f.Path
-								if key == "" {
-									key = f.Name
-								}
-								if _, seen := seenByName[key]; seen {
-									peerLog.Info("found duplicate file", "hash", item.Hash, "filename", f.Name)
-									continue
-								}
-								seenByName[key] = struct{}{}
-								files = append(files, torrent_stream.File{
-									Idx:       f.Idx,
-									Path:      f.Path,
-									Name:      f.Name,
-									Size:      f.Size,
-									Source:    f.Source,
-									VideoHash: f.VideoHash,
-									MediaInfo: f.MediaInfo,
-								})
-							}
-						}
-						filesByHash[item.Hash] = files
-						data.Items = append(data.Items, item)
+		for _, item := range res.Items {
+			data.Items = append(data.Items, item)
+			if res.Untrusted[item.Hash] {
+				continue
+			}
+			files := torrent_stream.Files{}
+			if item.Status == store.MagnetStatusCached {
+				seenByName := map[string]struct{}{}
+				for _, f := range item.Files {
+					key := f.Path
+					if key == "" {
+						key = f.Name
 					}
+					if _, seen := seenByName[key]; seen {
+						buddyLog.Info("found duplicate file", "hash", item.Hash, "filename", f.Name)
+						continue
+					}
+					seenByName[key] = struct{}{}
+					files = append(files, torrent_stream.File{
+						Idx:       f.Idx,
+						Path:      f.Path,
+						Name:      f.Name,
+						Size:      f.Size,
+						Source:    f.Source,
+						VideoHash: f.VideoHash,
+						MediaInfo: f.MediaInfo,
+					})
 				}
-			})
+			}
+			filesByHash[item.Hash] = files
 		}
-		wg.Wait()
 		go magnet_cache.BulkTouch(s.GetName().Code(), filesByHash, nil, false)
 		return data, nil
 	}
@@ -12,57 +12,40 @@ import (
 	tss "github.com/MunifTanjim/stremthru/internal/torrent_stream/torrent_stream_syncinfo"
 )
 
-var PullPeer, pullLocalOnly = func() (*peer.APIClient, bool) {
-	baseUrl := config.PullPeerURL
-	if baseUrl == "" {
-		baseUrl = config.PeerURL
-	}
-	localOnly := baseUrl == config.PullPeerURL
-	if baseUrl == "" {
-		return nil, localOnly
-	}
-	return peer.NewAPIClient(&peer.APIClientConfig{
-		BaseURL: baseUrl,
-	}), localOnly
-}()
-
 var pullPeerLog = logger.Scoped("peer:pull")
 
 var noTorrentInfo = !config.Feature.HasTorz()
 
+var pullLoopDetected = false
+
 // supports imdb or anidb
 func PullTorrentsByStremId(sid string, originInstanceId string) []string {
-	if noTorrentInfo || PullPeer == nil || PullPeer.IsHaltedCheckMagnet() || !tss.ShouldPull(sid) {
+	if noTorrentInfo || !peer.Federation.HasPullPeers() || !tss.ShouldPull(sid) {
 		return nil
 	}
 
 	cleanSId := ts.CleanStremId(sid)
 	start := time.Now()
-	res, err := PullPeer.ListTorrents(&peer.ListTorrentsByStremIdParams{
-		SId:              cleanSId,
-		LocalOnly:        pullLocalOnly,
-		OriginInstanceId: originInstanceId,
-	})
+	res, err := peer.Federation.ListTorrents(cleanSId, originInstanceId)
 	duration := time.Since(start)
 
 	if err != nil {
-		if duration > 25*time.Second {
-			PullPeer.HaltCheckMagnet()
-		}
-
 		pullPeerLog.Error("failed to pull torrents", "error", core.PackError(err), "duration", duration, "sid", cleanSId)
 		return nil
 	}
 
-	count := len(res.Data.Items)
+	count := len(res.Items)
 	pullPeerLog.Info("pulled torrents", "duration", duration, "sid", cleanSId, "count", count)
 
-	hashes := make([]string, count)
-	items := make([]ti.TorrentInfoInsertData, count)
-	for i := range res.Data.Items {
-		data := &res.Data.Items[i]
-		hashes[i] = data.Hash
-		items[i] = ti.TorrentInfoInsertData{
+	hashes := make([]string, 0, count)
+	items := make([]ti.TorrentInfoInsertData, 0, count)
+	for i := range res.Items {
+		data := &res.Items[i]
+		if res.Untrusted[data.Hash] {
+			continue
+		}
+		hashes = append(hashes, data.Hash)
+		items = append(items, ti.TorrentInfoInsertData{
 			Hash:         data.Hash,
 			TorrentTitle: data.TorrentTitle,
 			Size:         data.Size,
@@ -73,7 +56,7 @@ func PullTorrentsByStremId(sid string, originInstanceId string) []string {
 			Seeders:      data.Seeders,
 			Leechers:     data.Leechers,
 			Private:      data.Private,
-		}
+		})
 	}
 	ti.Upsert(items, "", false)
 	go tss.MarkPulled(cleanSId)
@@ -81,9 +64,10 @@ func PullTorrentsByStremId(sid string, originInstanceId string) []string {
 }
 
 func ListTorrentsByStremId(sid string, localOnly bool, originInstanceId string, noMissingSize bool) (*ti.ListTorrentsData, error) {
-	if originInstanceId == config.InstanceId && !pullLocalOnly {
+	if originInstanceId == config.InstanceId && !pullLoopDetected {
 		pullPeerLog.Info("loop detected for list torrents, self-correcting...")
-		pullLocalOnly = true
+		pullLoopDetected = true
+		peer.Federation.SetPullLocalOnly()
 	}
 
 	if !localOnly {
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/redis"
+)
+
+var ErrInvalidName = errors.New("invalid cache name")
+
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+func clearRedis(name string) error {
+	client := redis.GetClient()
+	ctx := context.Background()
+
+	iter := client.Scan(ctx, 0, redisGlobEscaper.Replace(name)+":*", 1000).Iterator()
+	keys := make([]string, 0, 1000)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == cap(keys) {
+			if err := client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// Clear removes the entries of the named cache, from the persistent caches
+// of the current process, the cache directory and redis. In-memory caches of
+// other running processes are not affected, and are written back when they
+// persist, so a running instance needs to clear its own.
+func Clear(name string) error {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+
+	errs := []error{}
+
+	persistentCachesMu.Lock()
+	for _, c := range persistentCaches {
+		if c.GetName() == name {
+			errs = append(errs, c.clear())
+		}
+	}
+	persistentCachesMu.Unlock()
+
+	if err := os.Remove(filepath.Join(cacheDir, name+".gob")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	dir := filepath.Join(cacheDir, name)
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			errs = append(errs, os.RemoveAll(filepath.Join(dir, entry.Name())))
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+
+	if redis.IsAvailable() {
+		if err := clearRedis(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
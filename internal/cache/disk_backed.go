@@ -157,3 +157,17 @@ func (c *diskBackedCache[V]) Has(key string) bool {
 func (c *diskBackedCache[V]) Remove(key string) {
 	c.otter.Invalidate(key)
 }
+
+func (c *diskBackedCache[V]) clear() error {
+	c.otter.InvalidateAll()
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(c.dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,8 +18,10 @@ var persistentCaches []persistentCache
 var persistentCachesMu sync.Mutex
 
 type persistentCache interface {
+	GetName() string
 	load()
 	persist() error
+	clear() error
 }
 
 func persistCaches() {
@@ -78,6 +80,11 @@ func (c *otterCache[V]) persist() error {
 	return otter.SaveCacheToFile(c.otter, c.filePath)
 }
 
+func (c *otterCache[V]) clear() error {
+	c.otter.InvalidateAll()
+	return nil
+}
+
 func (c *otterCache[V]) GetName() string {
 	return c.name
 }
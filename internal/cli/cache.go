@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+)
+
+// getInstanceURL returns the url of the instance on this machine, from the
+// listen address.
+func getInstanceURL() string {
+	host, port, err := net.SplitHostPort(config.ListenAddr)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+var instanceClient = &http.Client{Timeout: 1 * time.Minute}
+
+// clearCacheOnInstance asks the running instance to clear the cache, so that
+// its in-memory cache is cleared too. It returns false if the instance is not
+// running.
+func clearCacheOnInstance(instanceURL string, name string) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(instanceURL, "/")+"/v0/admin/cache/"+url.PathEscape(name)+"/clear", nil)
+	if err != nil {
+		return false, err
+	}
+	req.SetBasicAuth(config.Auth.GetAdmin())
+
+	res, err := instanceClient.Do(req)
+	if err != nil {
+		if opErr := (*net.OpError)(nil); errors.As(err, &opErr) && opErr.Op == "dial" {
+			return false, nil
+		}
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return true, fmt.Errorf("instance responded with %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	return true, nil
+}
+
+func newCacheCommand() *Command {
+	return &Command{
+		Name:    "cache",
+		Summary: "Manage the caches",
+		Commands: []*Command{
+			{
+				Name:    "clear",
+				Args:    "<name> [--url <url>]",
+				Summary: "Clear a persistent or redis cache, through the running instance if any",
+				Run: func(args []string) error {
+					fs := newFlagSet("cache clear")
+					instanceURL := fs.String("url", getInstanceURL(), "url of the running instance")
+					args, err := parseFlags(fs, args)
+					if err != nil {
+						return err
+					}
+					if len(args) != 1 {
+						return errUsage
+					}
+
+					if *instanceURL != "" {
+						ok, err := clearCacheOnInstance(*instanceURL, args[0])
+						if err != nil {
+							return err
+						}
+						if ok {
+							fmt.Fprintln(stdout, "cleared on running instance")
+							return nil
+						}
+					}
+
+					if err := cache.Clear(args[0]); err != nil {
+						return err
+					}
+					fmt.Fprintln(stdout, "cleared")
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+var stdout io.Writer = os.Stdout
+var stderr io.Writer = os.Stderr
+
+var errUsage = errors.New("invalid usage")
+
+// opened for commands with NeedsDB
+var database *db.DB
+
+type Config struct {
+	RunSchemaMigration func(uri db.ConnectionURI, database *db.DB)
+}
+
+type Command struct {
+	Name     string
+	Args     string
+	Summary  string
+	Commands []*Command
+	// NeedsDB opens the database before Run
+	NeedsDB bool
+	Run     func(args []string) error
+}
+
+func (cmd *Command) getCommand(name string) *Command {
+	for _, c := range cmd.Commands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (cmd *Command) printUsage(path string) {
+	if cmd.Run != nil {
+		fmt.Fprintf(stderr, "Usage: %s %s\n", path, cmd.Args)
+	} else {
+		fmt.Fprintf(stderr, "Usage: %s <command>\n", path)
+	}
+	if cmd.Summary != "" {
+		fmt.Fprintf(stderr, "\n%s\n", cmd.Summary)
+	}
+	if len(cmd.Commands) > 0 {
+		fmt.Fprintf(stderr, "\nCommands:\n")
+		for _, c := range cmd.Commands {
+			fmt.Fprintf(stderr, "  %-12s %s\n", c.Name, c.Summary)
+		}
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func newRootCommand(conf *Config) *Command {
+	return &Command{
+		Name:    "stremthru",
+		Summary: "Runs the server when no command is given.",
+		Commands: []*Command{
+			newDBCommand(conf),
+			newVaultCommand(),
+			newPeerTokenCommand(),
+			newWorkerCommand(),
+			newCacheCommand(),
+			newNZBCommand(),
+		},
+	}
+}
+
+// Run runs the command for the args, returns the exit code.
+func Run(args []string, conf *Config) int {
+	cmd := newRootCommand(conf)
+	path := cmd.Name
+	for len(args) > 0 && cmd.Run == nil {
+		if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+			cmd.printUsage(path)
+			return 0
+		}
+		sub := cmd.getCommand(args[0])
+		if sub == nil {
+			fmt.Fprintf(stderr, "unknown command: %s\n\n", strings.TrimSpace(path+" "+args[0]))
+			cmd.printUsage(path)
+			return 2
+		}
+		cmd = sub
+		path += " " + cmd.Name
+		args = args[1:]
+	}
+	if cmd.Run == nil {
+		cmd.printUsage(path)
+		return 2
+	}
+
+	if cmd.NeedsDB {
+		if err := db.CheckDatabaseSwitch(); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		database = db.Open()
+		defer db.Close()
+		db.Ping()
+	}
+
+	if err := cmd.Run(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			cmd.printUsage(path)
+			return 2
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses the flags mixed with the positional args, and returns
+// the positional args.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
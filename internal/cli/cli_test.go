@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlags(t *testing.T) {
+	fs := newFlagSet("test")
+	user := fs.String("user", "", "")
+	name := fs.String("name", "", "")
+
+	positional, err := parseFlags(fs, []string{"--user", "u", "a.nzb", "--name", "n", "b.nzb"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.nzb", "b.nzb"}, positional)
+	assert.Equal(t, "u", *user)
+	assert.Equal(t, "n", *name)
+}
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	defaultStderr := stderr
+	stderr = &out
+	defer func() { stderr = defaultStderr }()
+
+	for _, tc := range []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{"help"}, 0, "Commands:"},
+		{[]string{"foo"}, 2, "unknown command: stremthru foo"},
+		{[]string{"db"}, 2, "Usage: stremthru db <command>"},
+		{[]string{"cache", "clear"}, 2, "Usage: stremthru cache clear <name>"},
+	} {
+		out.Reset()
+		code := Run(tc.args, &Config{})
+		assert.Equal(t, tc.code, code, tc.args)
+		assert.Contains(t, out.String(), tc.output, tc.args)
+	}
+}
+
+func TestClearCacheOnInstance(t *testing.T) {
+	var path string
+	instance := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(204)
+	}))
+
+	ok, err := clearCacheOnInstance(instance.URL, "newz:nzb-health")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "/v0/admin/cache/newz:nzb-health/clear", path)
+
+	instance.Close()
+	ok, err = clearCacheOnInstance(instance.URL, "newz:nzb-health")
+	assert.NoError(t, err)
+	assert.False(t, ok, "not running")
+}
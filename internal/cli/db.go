@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/db_migrator"
+)
+
+func newDBCommand(conf *Config) *Command {
+	return &Command{
+		Name:    "db",
+		Summary: "Manage the database",
+		Commands: []*Command{
+			{
+				Name:    "migrate",
+				Summary: "Run the schema migrations",
+				NeedsDB: true,
+				Run: func(args []string) error {
+					if len(args) != 0 {
+						return errUsage
+					}
+					conf.RunSchemaMigration(database.URI, database)
+					return nil
+				},
+			},
+			{
+				Name:    "backup",
+				Args:    "<file>",
+				Summary: "Write a backup of the SQLite database to the file",
+				NeedsDB: true,
+				Run:     runDBBackup,
+			},
+			{
+				Name:    "copy",
+				Args:    "--to <database-uri> [--tables <name>,...] [--batch-size <n>]",
+				Summary: "Copy the data to another database, e.g. SQLite to PostgreSQL",
+				NeedsDB: true,
+				Run: func(args []string) error {
+					return runDBCopy(conf, args)
+				},
+			},
+			{
+				Name:    "switch",
+				Args:    "--to <database-uri> [--batch-size <n>] | --cancel",
+				Summary: "Copy the SQLite data to another database while the instance is running, and switch to it on restart",
+				Run: func(args []string) error {
+					return runDBSwitch(conf, args)
+				},
+			},
+		},
+	}
+}
+
+func runDBBackup(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if database.URI.Dialect != db.DBDialectSQLite {
+		return fmt.Errorf("backup is not supported for %s, use pg_dump instead", database.URI.Dialect)
+	}
+
+	file := args[0]
+	if _, err := os.Stat(file); err == nil {
+		return fmt.Errorf("file already exists: %s", file)
+	}
+	if _, err := database.Exec("VACUUM INTO ?", file); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "backup written to %s\n", file)
+	return nil
+}
+
+func runDBCopy(conf *Config, args []string) error {
+	fs := newFlagSet("db copy")
+	to := fs.String("to", "", "target database uri")
+	tables := fs.String("tables", "", "comma separated tables to copy, all by default")
+	batchSize := fs.Int("batch-size", 1000, "number of rows inserted at once")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || *to == "" {
+		return errUsage
+	}
+
+	target, err := db_migrator.Open(*to)
+	if err != nil {
+		return errors.Join(errors.New("failed to open target database"), err)
+	}
+	defer target.Close()
+
+	if target.URI.String() == database.URI.String() {
+		return errors.New("target is the same as the current database")
+	}
+
+	conf.RunSchemaMigration(target.URI, &db.DB{DB: target.DB, URI: target.URI})
+
+	copyConf := &db_migrator.CopyConfig{BatchSize: *batchSize}
+	if *tables != "" {
+		copyConf.Tables = strings.Split(*tables, ",")
+	}
+	return copyWithProgress(db_migrator.FromDB(database), target, copyConf)
+}
+
+func copyWithProgress(source, target *db_migrator.Conn, conf *db_migrator.CopyConfig) error {
+	lastTable := ""
+	conf.OnProgress = func(table string, count int64) {
+		if lastTable != "" && lastTable != table {
+			fmt.Fprintln(stderr)
+		}
+		lastTable = table
+		fmt.Fprintf(stderr, "\r%s: %d", table, count)
+	}
+
+	results, err := db_migrator.Copy(source, target, conf)
+	if lastTable != "" {
+		fmt.Fprintln(stderr)
+	}
+	for _, result := range results {
+		fmt.Fprintf(stdout, "%s: copied %d rows, inserted %d\n", result.Table, result.Count, result.InsertedCount)
+	}
+	return err
+}
+
+// writes from the running instance can land between sync and verify
+const maxSwitchVerifyAttempts = 5
+
+func runDBSwitch(conf *Config, args []string) error {
+	fs := newFlagSet("db switch")
+	to := fs.String("to", "", "target database uri")
+	batchSize := fs.Int("batch-size", 1000, "number of rows inserted at once")
+	cancel := fs.Bool("cancel", false, "cancel the pending switch")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || (*to == "") != *cancel {
+		return errUsage
+	}
+
+	s := db.GetDatabaseSwitch()
+	if *cancel {
+		return cancelDBSwitch(s)
+	}
+	if s != nil {
+		if s.IsPending() {
+			return errors.New("database switch is pending, restart the instance to finish it, or cancel it")
+		}
+		return errors.New("database is already switched, update STREMTHRU_DATABASE_URI to the target database first")
+	}
+
+	source, err := db_migrator.Open(config.DatabaseURI)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	if source.Dialect != db.DBDialectSQLite {
+		return fmt.Errorf("switch is not supported from %s, use `db copy` with the instance stopped", source.Dialect)
+	}
+
+	target, err := db_migrator.Open(*to)
+	if err != nil {
+		return errors.Join(errors.New("failed to open target database"), err)
+	}
+	defer target.Close()
+	if target.URI.String() == source.URI.String() {
+		return errors.New("target is the same as the current database")
+	}
+
+	conf.RunSchemaMigration(target.URI, &db.DB{DB: target.DB, URI: target.URI})
+
+	// changes made while copying are synced afterwards
+	if err := db_migrator.StartChangeCapture(source, target); err != nil {
+		return errors.Join(errors.New("failed to start change capture"), err)
+	}
+	if err := copyWithProgress(source, target, &db_migrator.CopyConfig{BatchSize: *batchSize}); err != nil {
+		return err
+	}
+
+	var tables []string
+	for attempt := 1; ; attempt++ {
+		count, err := db_migrator.Sync(source, target, &db_migrator.SyncConfig{BatchSize: *batchSize})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "synced %d changed rows\n", count)
+
+		results, err := db_migrator.Verify(source, target, tables)
+		if err != nil {
+			return err
+		}
+		tables = []string{}
+		for i := range results {
+			if !results[i].IsMatch() {
+				tables = append(tables, results[i].Table)
+			}
+		}
+		if len(tables) == 0 {
+			break
+		}
+		if attempt == maxSwitchVerifyAttempts {
+			for i := range results {
+				if r := &results[i]; !r.IsMatch() {
+					fmt.Fprintf(stdout, "%s: source has %d rows, target has %d rows, checksum mismatch: %v\n", r.Table, r.SourceCount, r.TargetCount, r.SourceChecksum != r.TargetChecksum)
+				}
+			}
+			return fmt.Errorf("verification failed for tables: %s", strings.Join(tables, ", "))
+		}
+		fmt.Fprintf(stderr, "verification failed for %d tables, retrying\n", len(tables))
+	}
+	fmt.Fprintln(stdout, "verified row counts and checksums")
+
+	s = &db.DatabaseSwitch{From: config.DatabaseURI, To: *to}
+	if err := s.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "restart the instance to finish the switch, it switches to the target database on its own")
+	return nil
+}
+
+func cancelDBSwitch(s *db.DatabaseSwitch) error {
+	uri := config.DatabaseURI
+	if s != nil {
+		if !s.IsPending() {
+			return errors.New("database is already switched")
+		}
+		uri = s.From
+	}
+
+	source, err := db_migrator.Open(uri)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	if err := db_migrator.StopChangeCapture(source); err != nil {
+		return err
+	}
+	if err := db.RemoveDatabaseSwitch(); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "database switch is cancelled")
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+)
+
+func newNZBCommand() *Command {
+	return &Command{
+		Name:    "nzb",
+		Summary: "Manage the NZBs",
+		Commands: []*Command{
+			{
+				Name:    "add",
+				Args:    "<file> [--user <user>] [--name <name>]",
+				Summary: "Add a NZB file to the queue",
+				NeedsDB: true,
+				Run:     runNZBAdd,
+			},
+		},
+	}
+}
+
+func runNZBAdd(args []string) error {
+	fs := newFlagSet("nzb add")
+	user := fs.String("user", "", "user to add the nzb for, required if there are multiple users")
+	name := fs.String("name", "", "name of the nzb, file name by default")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	if !config.Feature.HasNewz() || !config.Feature.HasVault() {
+		return errors.New("newz and vault features are required")
+	}
+
+	if *user == "" {
+		users := config.Auth.ListUsers()
+		if len(users) != 1 {
+			return errors.New("--user is required")
+		}
+		for u := range users {
+			*user = u
+		}
+	}
+	if config.Auth.GetPassword(*user) == "" {
+		return fmt.Errorf("unknown user: %s", *user)
+	}
+
+	file := positional[0]
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if info.Size() > config.Newz.NZBFileMaxSize {
+		return fmt.Errorf("file too large: %d bytes (max %d)", info.Size(), config.Newz.NZBFileMaxSize)
+	}
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	queueId, err := nzb_info.AddNZBFile(*user, filepath.Base(file), *name, blob)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, queueId)
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/peer_token"
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
+)
+
+func newPeerTokenCommand() *Command {
+	return &Command{
+		Name:    "peer-token",
+		Summary: "Manage the peer tokens",
+		Commands: []*Command{
+			{
+				Name:    "create",
+				Args:    "<name> [--scopes <scopes>] [--rate-limit <name>] [--expires-in <duration>]",
+				Summary: "Create a peer token",
+				NeedsDB: true,
+				Run:     runPeerTokenCreate,
+			},
+			{
+				Name:    "list",
+				Summary: "List the peer tokens",
+				NeedsDB: true,
+				Run: func(args []string) error {
+					if len(args) != 0 {
+						return errUsage
+					}
+					tokens, err := peer_token.List()
+					if err != nil {
+						return err
+					}
+					w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "ID\tNAME\tSCOPES\tEXPIRES AT\tLAST USED AT\tUSAGE\tCREATED AT")
+					for _, token := range tokens {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", token.Id, token.Name, strings.Join(token.Scopes, ","), formatOptionalTime(token.ExpiresAt), formatOptionalTime(token.LastUsedAt), token.UsageCount, token.CreatedAt.UTC().Format(time.RFC3339))
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:    "revoke",
+				Args:    "<id>",
+				Summary: "Revoke a peer token",
+				NeedsDB: true,
+				Run: func(args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					deleted, err := peer_token.Delete(args[0])
+					if err != nil {
+						return err
+					}
+					if !deleted {
+						return fmt.Errorf("peer token not found: %s", args[0])
+					}
+					fmt.Fprintln(stdout, "revoked")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func runPeerTokenCreate(args []string) error {
+	fs := newFlagSet("peer-token create")
+	scopes := fs.String("scopes", strings.Join(peer_token.Scopes, ","), "comma separated scopes")
+	rateLimit := fs.String("rate-limit", "", "name of the rate limit config")
+	expiresIn := fs.Duration("expires-in", 0, "duration after which the token expires, never by default")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || strings.TrimSpace(positional[0]) == "" || *expiresIn < 0 {
+		return errUsage
+	}
+
+	token := &peer_token.PeerToken{Name: strings.TrimSpace(positional[0])}
+	for scope := range strings.SplitSeq(*scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if !peer_token.IsValidScope(scope) {
+			return fmt.Errorf("invalid scope: %s, expected one of: %s", scope, strings.Join(peer_token.Scopes, ", "))
+		}
+		token.Scopes = append(token.Scopes, scope)
+	}
+	if *rateLimit != "" {
+		conf, err := ratelimit.GetByName(*rateLimit)
+		if err != nil {
+			return err
+		}
+		if conf == nil {
+			return fmt.Errorf("rate limit config not found: %s", *rateLimit)
+		}
+		token.RateLimitConfigId = sql.NullString{String: conf.Id, Valid: true}
+	}
+	if *expiresIn > 0 {
+		token.ExpiresAt = db.Timestamp{Time: time.Now().Add(*expiresIn)}
+	}
+
+	if err := peer_token.Create(token); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, token.Id)
+	return nil
+}
+
+func formatOptionalTime(t db.Timestamp) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
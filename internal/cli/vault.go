@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db_migrator"
+)
+
+// tables with the vault data, referenced tables come first
+var vaultTableNames = []string{
+	"oauth_token",
+	"stremio_account",
+	"trakt_account",
+	"anilist_account",
+	"mal_account",
+	"simkl_account",
+	"letterboxd_account",
+	"torznab_indexer",
+	"newznab_indexer",
+	"newznab_indexer_hostname",
+	"usenet_server",
+	"sync_stremio_stremio_link",
+	"sync_stremio_trakt_link",
+	"sync_stremio_anilist_link",
+	"sync_stremio_mal_link",
+	"sync_stremio_simkl_link",
+	"sync_stremio_letterboxd_link",
+}
+
+const vaultExportVersion = 1
+
+type vaultExport struct {
+	Version int                      `json:"version"`
+	Tables  []*db_migrator.TableDump `json:"tables"`
+}
+
+func newVaultCommand() *Command {
+	return &Command{
+		Name:    "vault",
+		Summary: "Export or import the vault, encrypted with the vault secret",
+		Commands: []*Command{
+			{
+				Name:    "export",
+				Args:    "<file>",
+				Summary: "Export the vault to the file",
+				NeedsDB: true,
+				Run:     runVaultExport,
+			},
+			{
+				Name:    "import",
+				Args:    "<file>",
+				Summary: "Import the vault from the file, existing entries are kept",
+				NeedsDB: true,
+				Run:     runVaultImport,
+			},
+		},
+	}
+}
+
+func requireVault() error {
+	if !config.Feature.HasVault() {
+		return errors.New("vault is not enabled, STREMTHRU_VAULT_SECRET is required")
+	}
+	return nil
+}
+
+func runVaultExport(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := requireVault(); err != nil {
+		return err
+	}
+
+	conn := db_migrator.FromDB(database)
+	export := vaultExport{Version: vaultExportVersion, Tables: []*db_migrator.TableDump{}}
+	for _, name := range vaultTableNames {
+		dump, err := conn.Dump(name)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to dump %s", name), err)
+		}
+		if dump != nil {
+			export.Tables = append(export.Tables, dump)
+		}
+	}
+
+	blob, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+	encrypted, err := core.Encrypt(config.VaultSecret, string(blob))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(args[0], []byte(encrypted), 0600); err != nil {
+		return err
+	}
+
+	for _, dump := range export.Tables {
+		fmt.Fprintf(stdout, "%s: exported %d rows\n", dump.Name, len(dump.Rows))
+	}
+	return nil
+}
+
+func runVaultImport(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := requireVault(); err != nil {
+		return err
+	}
+
+	encrypted, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	blob, err := core.Decrypt(config.VaultSecret, string(bytes.TrimSpace(encrypted)))
+	if err != nil {
+		return errors.Join(errors.New("failed to decrypt, the vault secret does not match"), err)
+	}
+
+	var export vaultExport
+	decoder := json.NewDecoder(bytes.NewReader([]byte(blob)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&export); err != nil {
+		return err
+	}
+	if export.Version != vaultExportVersion {
+		return fmt.Errorf("unsupported vault export version: %d", export.Version)
+	}
+
+	conn := db_migrator.FromDB(database)
+	for _, dump := range export.Tables {
+		count, err := conn.Restore(dump)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to import %s", dump.Name), err)
+		}
+		fmt.Fprintf(stdout, "%s: imported %d of %d rows\n", dump.Name, count, len(dump.Rows))
+	}
+	return nil
+}
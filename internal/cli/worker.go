@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/MunifTanjim/stremthru/internal/worker"
+)
+
+func newWorkerCommand() *Command {
+	return &Command{
+		Name:    "worker",
+		Summary: "Run the workers",
+		Commands: []*Command{
+			{
+				Name:    "list",
+				Summary: "List the workers",
+				Run: func(args []string) error {
+					if len(args) != 0 {
+						return errUsage
+					}
+					ids := make([]string, 0, len(worker.WorkerDetailsById))
+					for id := range worker.WorkerDetailsById {
+						ids = append(ids, id)
+					}
+					slices.Sort(ids)
+					w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "ID\tTITLE")
+					for _, id := range ids {
+						fmt.Fprintf(w, "%s\t%s\n", id, worker.WorkerDetailsById[id].Title)
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:    "run",
+				Args:    "<id>",
+				Summary: "Run a worker once, ignoring its schedule",
+				NeedsDB: true,
+				Run: func(args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					if err := worker.RunWorker(args[0]); err != nil {
+						return err
+					}
+					fmt.Fprintln(stdout, "done")
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -3,9 +3,11 @@ package config
 import (
 	"fmt"
 	"log"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -69,29 +71,49 @@ var defaultValueByEnv = map[string]map[string]string{
 		"STREMTHRU_INTEGRATION_LETTERBOXD_USER_AGENT":      "stremthru",
 		"STREMTHRU_INTEGRATION_MDBLIST_LIST_STALE_TIME":    "12h",
 		"STREMTHRU_INTEGRATION_SERIALIZD_LIST_STALE_TIME":  "24h",
+		"STREMTHRU_INTEGRATION_SIMKL_LIST_STALE_TIME":      "12h",
 		"STREMTHRU_INTEGRATION_TMDB_LIST_STALE_TIME":       "12h",
 		"STREMTHRU_INTEGRATION_TRAKT_LIST_STALE_TIME":      "12h",
 		"STREMTHRU_INTEGRATION_TVDB_LIST_STALE_TIME":       "12h",
 		"STREMTHRU_STREMIO_LIST_PUBLIC_MAX_LIST_COUNT":     "10",
 		"STREMTHRU_STREMIO_NEWZ_INDEXER_MAX_TIMEOUT":       "15s",
 		"STREMTHRU_STREMIO_NEWZ_PLAYBACK_WAIT_TIME":        "15s",
+		"STREMTHRU_STREMIO_NEWZ_PREWARM_SIZE":              "32MB",
 		"STREMTHRU_STREMIO_STORE_CATALOG_ITEM_LIMIT":       "2000",
 		"STREMTHRU_STREMIO_STORE_CATALOG_CACHE_TIME":       "10m",
 		"STREMTHRU_TORZ_TORRENT_FILE_CACHE_SIZE":           "256MB",
 		"STREMTHRU_TORZ_TORRENT_FILE_CACHE_TTL":            "6h",
 		"STREMTHRU_TORZ_TORRENT_FILE_MAX_SIZE":             "1MB",
+		"STREMTHRU_TORZ_SCRAPE_TRACKERS":                   "",
+		"STREMTHRU_TORZ_SCRAPE_INTERVAL":                   "10m",
+		"STREMTHRU_TORZ_SCRAPE_STALE_TIME":                 "6h",
+		"STREMTHRU_TORZ_SCRAPE_RATE_LIMIT":                 "*:10/1m",
+		"STREMTHRU_TORZ_METADATA_FETCH_CONCURRENCY":        "0",
+		"STREMTHRU_TORZ_METADATA_FETCH_TIMEOUT":            "1m",
+		"STREMTHRU_TORZ_METADATA_FETCH_MAX_PEERS":          "50",
 		"STREMTHRU_STREMIO_TORZ_INDEXER_MAX_TIMEOUT":       "10s",
 		"STREMTHRU_STREMIO_TORZ_PUBLIC_MAX_INDEXER_COUNT":  "2",
 		"STREMTHRU_STREMIO_TORZ_PUBLIC_MAX_STORE_COUNT":    "3",
 		"STREMTHRU_STREMIO_WRAP_PUBLIC_MAX_UPSTREAM_COUNT": "5",
 		"STREMTHRU_STREMIO_WRAP_PUBLIC_MAX_STORE_COUNT":    "3",
 		"STREMTHRU_IP_CHECKER":                             "aws,akamai,api.ipify.org",
+		"STREMTHRU_NEWZ_HEALTH_CHECK_SAMPLE_SIZE":          "100",
+		"STREMTHRU_NEWZ_HEALTH_CHECK_SEARCH_RESULTS":       "5",
+		"STREMTHRU_NEWZ_HEALTH_MIN_COMPLETENESS":           "90",
 		"STREMTHRU_NEWZ_MAX_CONNECTION_PER_STREAM":         "8",
+		"STREMTHRU_NEWZ_NNTP_LISTEN_ADDR":                  "",
+		"STREMTHRU_NEWZ_NNTP_PIPELINE_DEPTH":               "0",
 		"STREMTHRU_NEWZ_NZB_FILE_CACHE_SIZE":               "512MB",
 		"STREMTHRU_NEWZ_NZB_FILE_CACHE_TTL":                "24h",
 		"STREMTHRU_NEWZ_NZB_FILE_MAX_SIZE":                 "50MB",
 		"STREMTHRU_NEWZ_SEGMENT_CACHE_SIZE":                "10GB",
 		"STREMTHRU_NEWZ_STREAM_BUFFER_SIZE":                "200MB",
+		"STREMTHRU_NEWZ_STREAM_READ_AHEAD":                 "60s",
+		"STREMTHRU_NEWZ_DECOMPRESS_CHECKPOINTS":            "4",
+		"STREMTHRU_NEWZ_DECOMPRESS_CHECKPOINT_INTERVAL":    "256MB",
+		"STREMTHRU_NEWZ_SCAN_GROUPS":                       "",
+		"STREMTHRU_NEWZ_SCAN_BACKFILL":                     "100000",
+		"STREMTHRU_NEWZ_SCAN_INTERVAL":                     "15m",
 		"STREMTHRU_NEWZ_NZB_LINK_TYPE":                     "*:proxy",
 		"STREMTHRU_WEBDAV_FILE_EXT_FILTER":                 ":video:,:subtitle:",
 	},
@@ -206,6 +228,17 @@ func (m AuthMap) IsAdmin(user string) bool {
 	return ok && isAdmin
 }
 
+// GetAdmin returns the first admin by name, with its password.
+func (m AuthMap) GetAdmin() (user, password string) {
+	users := slices.Sorted(maps.Keys(m.is_admin))
+	for _, user := range users {
+		if password := m.GetPassword(user); m.is_admin[user] && password != "" {
+			return user, password
+		}
+	}
+	return "", ""
+}
+
 func (m AuthMap) ListUsers() map[string]string {
 	users := make(map[string]string, len(m.user_pass))
 	for user := range m.user_pass {
@@ -331,6 +364,41 @@ type configPeerFlag struct {
 	NoSpillTorz bool
 }
 
+type configFederationPeer struct {
+	URL       string
+	AuthToken string
+	Trust     int
+}
+
+func parseFederationPeers(uris string) []configFederationPeer {
+	peers := []configFederationPeer{}
+	for _, uri := range strings.FieldsFunc(uris, func(c rune) bool {
+		return c == ','
+	}) {
+		u, err := url.Parse(strings.TrimSpace(uri))
+		if err != nil || u.Host == "" {
+			log.Fatalf("invalid federation peer uri: %s", uri)
+		}
+		trust := 100
+		query := u.Query()
+		if trustStr := query.Get("trust"); trustStr != "" {
+			trust, err = strconv.Atoi(trustStr)
+			if err != nil || trust < 0 || trust > 100 {
+				log.Fatalf("invalid federation peer trust, expected 0-100, got: %s", trustStr)
+			}
+			query.Del("trust")
+			u.RawQuery = query.Encode()
+		}
+		peerUrl, peerAuthToken := parseUri(u.String())
+		peers = append(peers, configFederationPeer{
+			URL:       peerUrl,
+			AuthToken: peerAuthToken,
+			Trust:     trust,
+		})
+	}
+	return peers
+}
+
 type Config struct {
 	LogLevel  llog.Level
 	LogFormat string
@@ -346,6 +414,7 @@ type Config struct {
 	PeerFlag                    configPeerFlag
 	HasPeer                     bool
 	PullPeerURL                 string
+	FederationPeers             []configFederationPeer
 	RedisURI                    string
 	DatabaseURI                 string
 	DatabaseReplicaURIs         []string
@@ -586,6 +655,7 @@ var config = func() Config {
 		PeerFlag:                    peerFlag,
 		HasPeer:                     len(peerUrl) > 0,
 		PullPeerURL:                 pullPeerUrl,
+		FederationPeers:             parseFederationPeers(getEnv("STREMTHRU_PEER_FEDERATION")),
 		RedisURI:                    redisUri,
 		DatabaseURI:                 databaseUri,
 		DatabaseReplicaURIs:         databaseReplicaUris,
@@ -614,6 +684,7 @@ var PeerAuthToken = config.PeerAuthToken
 var PeerFlag = config.PeerFlag
 var HasPeer = config.HasPeer
 var PullPeerURL = config.PullPeerURL
+var FederationPeers = config.FederationPeers
 var RedisURI = config.RedisURI
 var DatabaseURI = config.DatabaseURI
 var DatabaseReplicaURIs = config.DatabaseReplicaURIs
@@ -816,11 +887,27 @@ func PrintConfig(state *AppState) {
 	if !data.Newz.Disabled {
 		l.Println(" Newz:")
 		l.Println("   max conn. per stream: " + data.Newz.MaxConnectionPerStream)
+		l.Println("    nntp pipeline depth: " + data.Newz.NNTPPipelineDepth)
+		if data.Newz.NNTPListenAddr != "" {
+			l.Println("     nntp server listen: " + data.Newz.NNTPListenAddr)
+		}
+		l.Println("   health check samples: " + data.Newz.HealthCheckSampleSize)
+		l.Println("    health check search: " + data.Newz.HealthCheckSearchResults)
+		l.Println("       min completeness: " + data.Newz.HealthMinCompleteness)
 		l.Println("    nzb file cache size: " + data.Newz.NZBFileCacheSize)
 		l.Println("     nzb file cache ttl: " + data.Newz.NZBFileCacheTTL)
 		l.Println("      nzb file max size: " + data.Newz.NZBFileMaxSize)
 		l.Println("     segment cache size: " + data.Newz.SegmentCacheSize)
 		l.Println("     stream buffer size: " + data.Newz.StreamBufferSize)
+		l.Println("      stream read ahead: " + data.Newz.StreamReadAhead)
+		l.Println(" decompress checkpoints: " + data.Newz.DecompressCheckpoints + " (every " + data.Newz.DecompressCheckpointInterval + ")")
+		if len(data.Newz.ScanGroups) > 0 {
+			l.Println("          scan interval: " + data.Newz.ScanInterval + " (backfill " + data.Newz.ScanBackfill + ")")
+			l.Println("            scan groups:")
+			for _, group := range data.Newz.ScanGroups {
+				l.Println("                    - " + group)
+			}
+		}
 		if len(data.Newz.Flags) > 0 {
 			l.Println("                  flags:")
 			for _, flag := range data.Newz.Flags {
@@ -838,6 +925,17 @@ func PrintConfig(state *AppState) {
 		l.Println("    torrent file cache ttl: " + data.Torz.TorrentFileCacheTTL)
 	}
 	l.Println("     torrent file max size: " + data.Torz.TorrentFileMaxSize)
+	if len(data.Torz.ScrapeTrackers) > 0 {
+		l.Println("           scrape interval: " + data.Torz.ScrapeInterval + " (stale after " + data.Torz.ScrapeStaleTime + ")")
+		l.Println("         scrape rate limit: " + data.Torz.ScrapeRateLimit)
+		l.Println("           scrape trackers:")
+		for _, tracker := range data.Torz.ScrapeTrackers {
+			l.Println("             - " + tracker)
+		}
+	}
+	if data.Torz.MetadataFetchConcurrency > 0 {
+		l.Println("    metadata fetch workers: " + strconv.Itoa(data.Torz.MetadataFetchConcurrency) + " (timeout " + data.Torz.MetadataFetchTimeout + ", max peers " + strconv.Itoa(data.Torz.MetadataFetchMaxPeers) + ")")
+	}
 	l.Println()
 
 	l.Println(" WebDAV:")
@@ -41,6 +41,7 @@ type ConfigDisplayNetwork struct {
 	PeerURL     string            `json:"peer_url,omitempty"`
 	PeerFlags   string            `json:"peer_flags,omitempty"`
 	PullPeerURL string            `json:"pull_peer_url,omitempty"`
+	Federation  []string          `json:"federation,omitempty"`
 }
 
 type ConfigDisplayDatabase struct {
@@ -85,22 +86,41 @@ type ConfigDisplayIntegration struct {
 }
 
 type ConfigDisplayNewz struct {
-	Disabled               bool              `json:"disabled"`
-	Flags                  []string          `json:"flags,omitempty"`
-	MaxConnectionPerStream string            `json:"max_connection_per_stream"`
-	NZBFileCacheSize       string            `json:"nzb_file_cache_size"`
-	NZBFileCacheTTL        string            `json:"nzb_file_cache_ttl"`
-	NZBFileMaxSize         string            `json:"nzb_file_max_size"`
-	SegmentCacheSize       string            `json:"segment_cache_size"`
-	StreamBufferSize       string            `json:"stream_buffer_size"`
-	NZBLinkMode            map[string]string `json:"nzb_link_mode,omitempty"`
+	Disabled                     bool              `json:"disabled"`
+	Flags                        []string          `json:"flags,omitempty"`
+	HealthCheckSampleSize        string            `json:"health_check_sample_size"`
+	HealthCheckSearchResults     string            `json:"health_check_search_results"`
+	HealthMinCompleteness        string            `json:"health_min_completeness"`
+	MaxConnectionPerStream       string            `json:"max_connection_per_stream"`
+	NNTPListenAddr               string            `json:"nntp_listen_addr,omitempty"`
+	NNTPPipelineDepth            string            `json:"nntp_pipeline_depth"`
+	NZBFileCacheSize             string            `json:"nzb_file_cache_size"`
+	NZBFileCacheTTL              string            `json:"nzb_file_cache_ttl"`
+	NZBFileMaxSize               string            `json:"nzb_file_max_size"`
+	SegmentCacheSize             string            `json:"segment_cache_size"`
+	StreamBufferSize             string            `json:"stream_buffer_size"`
+	StreamReadAhead              string            `json:"stream_read_ahead"`
+	DecompressCheckpoints        string            `json:"decompress_checkpoints"`
+	DecompressCheckpointInterval string            `json:"decompress_checkpoint_interval"`
+	ScanGroups                   []string          `json:"scan_groups,omitempty"`
+	ScanBackfill                 string            `json:"scan_backfill,omitempty"`
+	ScanInterval                 string            `json:"scan_interval,omitempty"`
+	NZBLinkMode                  map[string]string `json:"nzb_link_mode,omitempty"`
 }
 
 type ConfigDisplayTorz struct {
-	Disabled             bool   `json:"disabled"`
-	TorrentFileCacheSize string `json:"torrent_file_cache_size,omitempty"`
-	TorrentFileCacheTTL  string `json:"torrent_file_cache_ttl,omitempty"`
-	TorrentFileMaxSize   string `json:"torrent_file_max_size"`
+	Disabled             bool     `json:"disabled"`
+	TorrentFileCacheSize string   `json:"torrent_file_cache_size,omitempty"`
+	TorrentFileCacheTTL  string   `json:"torrent_file_cache_ttl,omitempty"`
+	TorrentFileMaxSize   string   `json:"torrent_file_max_size"`
+	ScrapeTrackers       []string `json:"scrape_trackers,omitempty"`
+	ScrapeInterval       string   `json:"scrape_interval,omitempty"`
+	ScrapeStaleTime      string   `json:"scrape_stale_time,omitempty"`
+	ScrapeRateLimit      string   `json:"scrape_rate_limit,omitempty"`
+
+	MetadataFetchConcurrency int    `json:"metadata_fetch_concurrency,omitempty"`
+	MetadataFetchTimeout     string `json:"metadata_fetch_timeout,omitempty"`
+	MetadataFetchMaxPeers    int    `json:"metadata_fetch_max_peers,omitempty"`
 }
 
 type ConfigDisplayWebDAVFileExtFilter struct {
@@ -203,6 +223,15 @@ func BuildConfigDisplay(storeNames []string) ConfigDisplay {
 	if PullPeerURL != "" {
 		data.Network.PullPeerURL = redactURI(PullPeerURL)
 	}
+	for _, peer := range FederationPeers {
+		peerUrl, err := url.Parse(peer.URL)
+		if err == nil {
+			if peer.AuthToken != "" {
+				peerUrl.User = url.UserPassword("", peer.AuthToken)
+			}
+			data.Network.Federation = append(data.Network.Federation, peerUrl.Redacted()+" (trust: "+strconv.Itoa(peer.Trust)+")")
+		}
+	}
 	if !data.Tunnel.Disabled {
 		if tunnelIPs, err := IP.GetTunnelIPByProxyHost(); err == nil && len(tunnelIPs) > 0 {
 			data.Network.TunnelIPs = tunnelIPs
@@ -283,12 +312,25 @@ func BuildConfigDisplay(storeNames []string) ConfigDisplay {
 
 	data.Newz.Disabled = !Feature.HasNewz()
 	if !data.Newz.Disabled {
+		data.Newz.HealthCheckSampleSize = strconv.Itoa(Newz.HealthCheckSampleSize)
+		data.Newz.HealthCheckSearchResults = strconv.Itoa(Newz.HealthCheckSearchResults)
+		data.Newz.HealthMinCompleteness = strconv.Itoa(int(Newz.HealthMinCompleteness*100)) + "%"
 		data.Newz.MaxConnectionPerStream = strconv.Itoa(Newz.MaxConnectionPerStream)
+		data.Newz.NNTPListenAddr = Newz.NNTPListenAddr
+		data.Newz.NNTPPipelineDepth = strconv.Itoa(Newz.NNTPPipelineDepth)
 		data.Newz.NZBFileCacheSize = util.ToSize(Newz.NZBFileCacheSize)
 		data.Newz.NZBFileCacheTTL = Newz.NZBFileCacheTTL.String()
 		data.Newz.NZBFileMaxSize = util.ToSize(Newz.NZBFileMaxSize)
 		data.Newz.SegmentCacheSize = util.ToSize(Newz.SegmentCacheSize)
 		data.Newz.StreamBufferSize = util.ToSize(Newz.StreamBufferSize)
+		data.Newz.StreamReadAhead = Newz.StreamReadAhead.String()
+		data.Newz.DecompressCheckpoints = strconv.Itoa(Newz.DecompressCheckpoints)
+		data.Newz.DecompressCheckpointInterval = util.ToSize(Newz.DecompressCheckpointInterval)
+		if len(Newz.ScanGroups) > 0 {
+			data.Newz.ScanGroups = Newz.ScanGroups
+			data.Newz.ScanBackfill = strconv.Itoa(Newz.ScanBackfill)
+			data.Newz.ScanInterval = Newz.ScanInterval.String()
+		}
 		data.Newz.NZBLinkMode = make(map[string]string, len(NewzNZBLinkMode))
 		for hostname, mode := range NewzNZBLinkMode {
 			data.Newz.NZBLinkMode[hostname] = string(mode)
@@ -303,6 +345,22 @@ func BuildConfigDisplay(storeNames []string) ConfigDisplay {
 			data.Torz.TorrentFileCacheSize = util.ToSize(Torz.TorrentFileCacheSize)
 			data.Torz.TorrentFileCacheTTL = Torz.TorrentFileCacheTTL.String()
 		}
+		if len(Torz.ScrapeTrackers) > 0 {
+			data.Torz.ScrapeTrackers = make([]string, len(Torz.ScrapeTrackers))
+			for i, tracker := range Torz.ScrapeTrackers {
+				if u, err := url.Parse(tracker); err == nil {
+					data.Torz.ScrapeTrackers[i] = u.Scheme + "://" + u.Host
+				}
+			}
+			data.Torz.ScrapeInterval = Torz.ScrapeInterval.String()
+			data.Torz.ScrapeStaleTime = Torz.ScrapeStaleTime.String()
+			data.Torz.ScrapeRateLimit = Torz.scrapeRateLimitRaw
+		}
+		if Torz.MetadataFetchConcurrency > 0 {
+			data.Torz.MetadataFetchConcurrency = Torz.MetadataFetchConcurrency
+			data.Torz.MetadataFetchTimeout = Torz.MetadataFetchTimeout.String()
+			data.Torz.MetadataFetchMaxPeers = Torz.MetadataFetchMaxPeers
+		}
 	}
 
 	data.WebDAV.FileExtFilter.Video = []string{}
@@ -371,6 +429,7 @@ func buildConfigFeatures() []ConfigDisplayFeature {
 				feature.Settings = map[string]string{
 					"indexer_max_timeout": Stremio.Newz.IndexerMaxTimeout.String(),
 					"playback_wait_time":  Stremio.Newz.PlaybackWaitTime.String(),
+					"prewarm_size":        util.ToSize(Stremio.Newz.PrewarmSize),
 				}
 			case FeatureStremioStore:
 				feature.Settings = map[string]string{
@@ -417,6 +476,10 @@ func buildConfigIntegrations() []ConfigDisplayIntegration {
 		anilist.Settings = map[string]string{
 			"list_stale_time": Integration.AniList.ListStaleTime.String(),
 		}
+		if Integration.AniList.IsEnabled() {
+			anilist.Settings["client_id"] = redactToken(Integration.AniList.ClientId)
+			anilist.Settings["client_secret"] = redactToken(Integration.AniList.ClientSecret)
+		}
 	}
 	items = append(items, anilist)
 
@@ -488,6 +551,19 @@ func buildConfigIntegrations() []ConfigDisplayIntegration {
 	}
 	items = append(items, letterboxd)
 
+	malEnabled := Feature.IsEnabled(FeatureAnime) && Integration.MAL.IsEnabled()
+	mal := ConfigDisplayIntegration{
+		Name:    "myanimelist.net",
+		Enabled: malEnabled,
+	}
+	if malEnabled {
+		mal.Settings = map[string]string{
+			"client_id":     redactToken(Integration.MAL.ClientId),
+			"client_secret": redactToken(Integration.MAL.ClientSecret),
+		}
+	}
+	items = append(items, mal)
+
 	items = append(items, ConfigDisplayIntegration{
 		Name:    "mdblist.com",
 		Enabled: true,
@@ -504,6 +580,20 @@ func buildConfigIntegrations() []ConfigDisplayIntegration {
 		},
 	})
 
+	simklEnabled := Integration.Simkl.IsEnabled()
+	simkl := ConfigDisplayIntegration{
+		Name:    "simkl.com",
+		Enabled: simklEnabled,
+	}
+	if simklEnabled {
+		simkl.Settings = map[string]string{
+			"client_id":       redactToken(Integration.Simkl.ClientId),
+			"client_secret":   redactToken(Integration.Simkl.ClientSecret),
+			"list_stale_time": Integration.Simkl.ListStaleTime.String(),
+		}
+	}
+	items = append(items, simkl)
+
 	tmdbEnabled := Integration.TMDB.IsEnabled()
 	tmdb := ConfigDisplayIntegration{
 		Name:    "themoviedb.org",
@@ -12,18 +12,20 @@ const (
 	FeatureTorz string = "torz"
 	FeatureSync string = "sync"
 
-	FeatureAnime           string = "anime"
-	FeatureDMMHashlist     string = "dmm_hashlist"
-	FeatureIMDBTitle       string = "imdb_title"
-	FeatureStremioList     string = "stremio_list"
-	FeatureStremioNewz     string = "stremio_newz"
-	FeatureStremioP2P      string = "stremio_p2p"
-	FeatureStremioSidekick string = "stremio_sidekick"
-	FeatureStremioStore    string = "stremio_store"
-	FeatureStremioTorz     string = "stremio_torz"
-	FeatureStremioWrap     string = "stremio_wrap"
-	FeatureVault           string = "vault"
-	FeatureProbeMediaInfo  string = "probe_media_info"
+	FeatureAnime            string = "anime"
+	FeatureDMMHashlist      string = "dmm_hashlist"
+	FeatureEmbeddedSubtitle string = "embedded_subtitle"
+	FeatureIMDBTitle        string = "imdb_title"
+	FeatureStremioList      string = "stremio_list"
+	FeatureStremioMeta      string = "stremio_meta"
+	FeatureStremioNewz      string = "stremio_newz"
+	FeatureStremioP2P       string = "stremio_p2p"
+	FeatureStremioSidekick  string = "stremio_sidekick"
+	FeatureStremioStore     string = "stremio_store"
+	FeatureStremioTorz      string = "stremio_torz"
+	FeatureStremioWrap      string = "stremio_wrap"
+	FeatureVault            string = "vault"
+	FeatureProbeMediaInfo   string = "probe_media_info"
 )
 
 var features = []string{
@@ -35,9 +37,11 @@ var features = []string{
 
 	FeatureAnime,
 	FeatureDMMHashlist,
+	FeatureEmbeddedSubtitle,
 	FeatureIMDBTitle,
 
 	FeatureStremioList,
+	FeatureStremioMeta,
 	FeatureStremioNewz,
 	FeatureStremioP2P,
 	FeatureStremioSidekick,
@@ -93,6 +97,10 @@ func (f FeatureConfig) HasDMMHashlist() bool {
 	return f.IsEnabled(FeatureDMMHashlist) && f.HasTorz()
 }
 
+func (f FeatureConfig) HasEmbeddedSubtitle() bool {
+	return f.IsEnabled(FeatureEmbeddedSubtitle)
+}
+
 func (f FeatureConfig) HasIMDBTitle() bool {
 	return f.IsEnabled(FeatureIMDBTitle) && (f.HasNewz() || f.HasTorz())
 }
@@ -101,6 +109,10 @@ func (f FeatureConfig) HasStremioList() bool {
 	return f.IsEnabled(FeatureStremioList)
 }
 
+func (f FeatureConfig) HasStremioMeta() bool {
+	return f.IsEnabled(FeatureStremioMeta) && f.IsEnabled(FeatureIMDBTitle)
+}
+
 func (f FeatureConfig) HasStremioNewz() bool {
 	return f.IsEnabled(FeatureStremioNewz) && f.HasNewz()
 }
@@ -114,7 +126,7 @@ func (f FeatureConfig) HasStremioStore() bool {
 }
 
 func (f FeatureConfig) HasStremioAddon() bool {
-	return f.HasStremioList() || f.HasStremioNewz() || f.HasStremioTorz() || f.HasStremioStore() || f.IsEnabled(FeatureStremioWrap)
+	return f.HasStremioList() || f.HasStremioMeta() || f.HasStremioNewz() || f.HasStremioTorz() || f.HasStremioStore() || f.IsEnabled(FeatureStremioWrap)
 }
 
 func (f FeatureConfig) HasProbeMediaInfo() bool {
@@ -123,7 +135,7 @@ func (f FeatureConfig) HasProbeMediaInfo() bool {
 
 var Feature = func() FeatureConfig {
 	feature := FeatureConfig{
-		disabled: []string{FeatureAnime, FeatureStremioP2P},
+		disabled: []string{FeatureAnime, FeatureEmbeddedSubtitle, FeatureStremioP2P},
 	}
 
 	for _, name := range strings.FieldsFunc(strings.TrimSpace(getEnv("STREMTHRU_FEATURE")), func(c rune) bool {
@@ -82,6 +82,7 @@ type newzConfigFlag struct {
 	is_set bool
 	list   []string
 
+	NNTPCompress          bool
 	ServerPickerRandomize bool
 }
 
@@ -95,6 +96,8 @@ func (flags *newzConfigFlag) fromString(input string) {
 		flag := strings.TrimSpace(part)
 		flags.list = append(flags.list, flag)
 		switch flag {
+		case "nntp_compress":
+			flags.NNTPCompress = true
 		case "server_picker_randomize":
 			flags.ServerPickerRandomize = true
 		default:
@@ -105,14 +108,25 @@ func (flags *newzConfigFlag) fromString(input string) {
 }
 
 type newzConfig struct {
-	IndexerRequestHeader   newzIndexerRequestHeaderMap
-	MaxConnectionPerStream int
-	NZBFileCacheSize       int64
-	NZBFileCacheTTL        time.Duration
-	NZBFileMaxSize         int64
-	SegmentCacheSize       int64
-	StreamBufferSize       int64
-	Flag                   newzConfigFlag
+	IndexerRequestHeader         newzIndexerRequestHeaderMap
+	DecompressCheckpoints        int
+	DecompressCheckpointInterval int64
+	HealthCheckSampleSize        int
+	HealthCheckSearchResults     int
+	HealthMinCompleteness        float64
+	MaxConnectionPerStream       int
+	NNTPListenAddr               string
+	NNTPPipelineDepth            int
+	NZBFileCacheSize             int64
+	NZBFileCacheTTL              time.Duration
+	NZBFileMaxSize               int64
+	ScanBackfill                 int
+	ScanGroups                   []string
+	ScanInterval                 time.Duration
+	SegmentCacheSize             int64
+	StreamBufferSize             int64
+	StreamReadAhead              time.Duration
+	Flag                         newzConfigFlag
 
 	sabnzbdVersion string
 }
@@ -228,13 +242,29 @@ func parseNewzIndexerRequestHeader(queryHeaderBlob, grabHeaderBlob string) newzI
 
 var Newz = func() newzConfig {
 	newz := newzConfig{
-		IndexerRequestHeader:   parseNewzIndexerRequestHeader(getEnv("STREMTHRU_NEWZ_QUERY_HEADER"), getEnv("STREMTHRU_NEWZ_GRAB_HEADER")),
-		MaxConnectionPerStream: util.MustParseInt(getEnv("STREMTHRU_NEWZ_MAX_CONNECTION_PER_STREAM")),
-		NZBFileCacheSize:       util.ToBytes(getEnv("STREMTHRU_NEWZ_NZB_FILE_CACHE_SIZE")),
-		NZBFileCacheTTL:        mustParseDuration("newz nzb file cache ttl", getEnv("STREMTHRU_NEWZ_NZB_FILE_CACHE_TTL"), 6*time.Hour),
-		NZBFileMaxSize:         util.ToBytes(getEnv("STREMTHRU_NEWZ_NZB_FILE_MAX_SIZE")),
-		SegmentCacheSize:       util.ToBytes(getEnv("STREMTHRU_NEWZ_SEGMENT_CACHE_SIZE")),
-		StreamBufferSize:       util.ToBytes(getEnv("STREMTHRU_NEWZ_STREAM_BUFFER_SIZE")),
+		IndexerRequestHeader:         parseNewzIndexerRequestHeader(getEnv("STREMTHRU_NEWZ_QUERY_HEADER"), getEnv("STREMTHRU_NEWZ_GRAB_HEADER")),
+		DecompressCheckpoints:        util.MustParseInt(getEnv("STREMTHRU_NEWZ_DECOMPRESS_CHECKPOINTS")),
+		DecompressCheckpointInterval: util.ToBytes(getEnv("STREMTHRU_NEWZ_DECOMPRESS_CHECKPOINT_INTERVAL")),
+		HealthCheckSampleSize:        util.MustParseInt(getEnv("STREMTHRU_NEWZ_HEALTH_CHECK_SAMPLE_SIZE")),
+		HealthCheckSearchResults:     util.MustParseInt(getEnv("STREMTHRU_NEWZ_HEALTH_CHECK_SEARCH_RESULTS")),
+		HealthMinCompleteness:        float64(util.MustParseInt(getEnv("STREMTHRU_NEWZ_HEALTH_MIN_COMPLETENESS"))) / 100,
+		MaxConnectionPerStream:       util.MustParseInt(getEnv("STREMTHRU_NEWZ_MAX_CONNECTION_PER_STREAM")),
+		NNTPListenAddr:               getEnv("STREMTHRU_NEWZ_NNTP_LISTEN_ADDR"),
+		NNTPPipelineDepth:            util.MustParseInt(getEnv("STREMTHRU_NEWZ_NNTP_PIPELINE_DEPTH")),
+		NZBFileCacheSize:             util.ToBytes(getEnv("STREMTHRU_NEWZ_NZB_FILE_CACHE_SIZE")),
+		NZBFileCacheTTL:              mustParseDuration("newz nzb file cache ttl", getEnv("STREMTHRU_NEWZ_NZB_FILE_CACHE_TTL"), 6*time.Hour),
+		NZBFileMaxSize:               util.ToBytes(getEnv("STREMTHRU_NEWZ_NZB_FILE_MAX_SIZE")),
+		ScanBackfill:                 util.MustParseInt(getEnv("STREMTHRU_NEWZ_SCAN_BACKFILL")),
+		ScanInterval:                 mustParseDuration("newz scan interval", getEnv("STREMTHRU_NEWZ_SCAN_INTERVAL"), 1*time.Minute),
+		SegmentCacheSize:             util.ToBytes(getEnv("STREMTHRU_NEWZ_SEGMENT_CACHE_SIZE")),
+		StreamBufferSize:             util.ToBytes(getEnv("STREMTHRU_NEWZ_STREAM_BUFFER_SIZE")),
+		StreamReadAhead:              mustParseDuration("newz stream read ahead", getEnv("STREMTHRU_NEWZ_STREAM_READ_AHEAD"), 0),
+	}
+
+	for group := range strings.SplitSeq(getEnv("STREMTHRU_NEWZ_SCAN_GROUPS"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			newz.ScanGroups = append(newz.ScanGroups, group)
+		}
 	}
 
 	newz.Flag.fromString(getEnv("STREMTHRU_NEWZ_FLAG"))
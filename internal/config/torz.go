@@ -1,15 +1,79 @@
 package config
 
 import (
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
+type TorzScrapeRateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+type torzScrapeRateLimitMap map[string]TorzScrapeRateLimit
+
+func parseTorzScrapeRateLimit(value string) (torzScrapeRateLimitMap, error) {
+	rateLimitMap := torzScrapeRateLimitMap{}
+	for _, rateLimit := range strings.FieldsFunc(value, func(c rune) bool {
+		return c == ','
+	}) {
+		idx := strings.LastIndex(rateLimit, ":")
+		if idx == -1 {
+			return nil, errors.New("invalid torz scrape rate limit: " + rateLimit)
+		}
+		host := rateLimit[:idx]
+		limitStr, windowStr, ok := strings.Cut(rateLimit[idx+1:], "/")
+		if !ok {
+			return nil, errors.New("invalid torz scrape rate limit: " + rateLimit)
+		}
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, errors.New("invalid torz scrape rate limit: " + rateLimit)
+		}
+		window, err := parseDuration("torz scrape rate limit window", windowStr, time.Second)
+		if err != nil {
+			return nil, err
+		}
+		rateLimitMap[host] = TorzScrapeRateLimit{Limit: limit, Window: window}
+	}
+	if _, ok := rateLimitMap["*"]; !ok {
+		rateLimitMap["*"] = TorzScrapeRateLimit{Limit: 10, Window: 1 * time.Minute}
+	}
+	return rateLimitMap, nil
+}
+
+func (m torzScrapeRateLimitMap) get(hostname string) TorzScrapeRateLimit {
+	if rateLimit, ok := m[hostname]; ok {
+		return rateLimit
+	}
+	return m["*"]
+}
+
 type torzConfig struct {
 	TorrentFileCacheSize int64
 	TorrentFileCacheTTL  time.Duration
 	TorrentFileMaxSize   int64
+
+	ScrapeTrackers     []string
+	ScrapeInterval     time.Duration
+	ScrapeStaleTime    time.Duration
+	scrapeRateLimit    torzScrapeRateLimitMap
+	scrapeRateLimitRaw string
+
+	MetadataFetchConcurrency int
+	MetadataFetchTimeout     time.Duration
+	MetadataFetchMaxPeers    int
+}
+
+// GetScrapeRateLimit returns the rate limit for the tracker hostname.
+func (c torzConfig) GetScrapeRateLimit(hostname string) TorzScrapeRateLimit {
+	return c.scrapeRateLimit.get(hostname)
 }
 
 var Torz = func() torzConfig {
@@ -17,7 +81,40 @@ var Torz = func() torzConfig {
 		TorrentFileCacheSize: util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_SIZE")),
 		TorrentFileCacheTTL:  mustParseDuration("torz torrent file cache ttl", getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_TTL")),
 		TorrentFileMaxSize:   util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_MAX_SIZE")),
+
+		ScrapeTrackers:     []string{},
+		ScrapeInterval:     mustParseDuration("torz scrape interval", getEnv("STREMTHRU_TORZ_SCRAPE_INTERVAL"), 1*time.Minute),
+		ScrapeStaleTime:    mustParseDuration("torz scrape stale time", getEnv("STREMTHRU_TORZ_SCRAPE_STALE_TIME"), 1*time.Hour),
+		scrapeRateLimitRaw: getEnv("STREMTHRU_TORZ_SCRAPE_RATE_LIMIT"),
+
+		MetadataFetchConcurrency: util.MustParseInt(getEnv("STREMTHRU_TORZ_METADATA_FETCH_CONCURRENCY")),
+		MetadataFetchTimeout:     mustParseDuration("torz metadata fetch timeout", getEnv("STREMTHRU_TORZ_METADATA_FETCH_TIMEOUT"), 10*time.Second),
+		MetadataFetchMaxPeers:    util.MustParseInt(getEnv("STREMTHRU_TORZ_METADATA_FETCH_MAX_PEERS")),
+	}
+
+	if torz.MetadataFetchConcurrency < 0 {
+		log.Fatal("Invalid torz metadata fetch concurrency, expected non-negative value")
+	}
+	if torz.MetadataFetchMaxPeers < 1 {
+		log.Fatal("Invalid torz metadata fetch max peers, expected positive value")
+	}
+
+	for _, tracker := range strings.FieldsFunc(getEnv("STREMTHRU_TORZ_SCRAPE_TRACKERS"), func(c rune) bool {
+		return c == ','
+	}) {
+		tracker = strings.TrimSpace(tracker)
+		u, err := url.Parse(tracker)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "udp") || u.Host == "" {
+			log.Fatalf("Invalid torz scrape tracker: %s", tracker)
+		}
+		torz.ScrapeTrackers = append(torz.ScrapeTrackers, tracker)
+	}
+
+	scrapeRateLimit, err := parseTorzScrapeRateLimit(torz.scrapeRateLimitRaw)
+	if err != nil {
+		log.Fatal(err)
 	}
+	torz.scrapeRateLimit = scrapeRateLimit
 
 	return torz
 }()
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TorzScrapeRateLimitTestSuite struct {
+	suite.Suite
+}
+
+func (s *TorzScrapeRateLimitTestSuite) TestDefault() {
+	rateLimit, err := parseTorzScrapeRateLimit("")
+	s.Nil(err)
+	s.Equal(TorzScrapeRateLimit{Limit: 10, Window: time.Minute}, rateLimit.get("tracker.example.com"))
+}
+
+func (s *TorzScrapeRateLimitTestSuite) TestPerHost() {
+	rateLimit, err := parseTorzScrapeRateLimit("*:5/1m,tracker.example.com:30/10s")
+	s.Nil(err)
+	s.Equal(TorzScrapeRateLimit{Limit: 5, Window: time.Minute}, rateLimit.get("other.example.com"))
+	s.Equal(TorzScrapeRateLimit{Limit: 30, Window: 10 * time.Second}, rateLimit.get("tracker.example.com"))
+}
+
+func (s *TorzScrapeRateLimitTestSuite) TestInvalid() {
+	_, err := parseTorzScrapeRateLimit("*:5")
+	s.ErrorContains(err, "invalid")
+
+	_, err = parseTorzScrapeRateLimit("*:0/1m")
+	s.ErrorContains(err, "invalid")
+
+	_, err = parseTorzScrapeRateLimit("*:5/100ms")
+	s.ErrorContains(err, "must be at least 1s")
+}
+
+func TestTorzScrapeRateLimit(t *testing.T) {
+	suite.Run(t, new(TorzScrapeRateLimitTestSuite))
+}
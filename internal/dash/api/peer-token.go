@@ -0,0 +1,238 @@
+package dash_api
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/peer_token"
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
+)
+
+type PeerTokenResponse struct {
+	Id                string   `json:"id"`
+	Name              string   `json:"name"`
+	Scopes            []string `json:"scopes"`
+	RateLimitConfigId *string  `json:"rate_limit_config_id"`
+	ExpiresAt         *string  `json:"expires_at"`
+	LastUsedAt        *string  `json:"last_used_at"`
+	UsageCount        int64    `json:"usage_count"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+func formatOptionalTimestamp(t db.Timestamp) *string {
+	if t.IsZero() {
+		return nil
+	}
+	value := t.Format(time.RFC3339)
+	return &value
+}
+
+func toPeerTokenResponse(item *peer_token.PeerToken) PeerTokenResponse {
+	var rateLimitConfigId *string
+	if item.RateLimitConfigId.Valid {
+		rateLimitConfigId = &item.RateLimitConfigId.String
+	}
+
+	scopes := []string(item.Scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return PeerTokenResponse{
+		Id:                item.Id,
+		Name:              item.Name,
+		Scopes:            scopes,
+		RateLimitConfigId: rateLimitConfigId,
+		ExpiresAt:         formatOptionalTimestamp(item.ExpiresAt),
+		LastUsedAt:        formatOptionalTimestamp(item.LastUsedAt),
+		UsageCount:        item.UsageCount,
+		CreatedAt:         item.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetPeerTokens(w http.ResponseWriter, r *http.Request) {
+	items, err := peer_token.List()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]PeerTokenResponse, len(items))
+	for i := range items {
+		data[i] = toPeerTokenResponse(&items[i])
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type SavePeerTokenRequest struct {
+	Name              string   `json:"name"`
+	Scopes            []string `json:"scopes"`
+	RateLimitConfigId *string  `json:"rate_limit_config_id"`
+	ExpiresAt         *string  `json:"expires_at"`
+}
+
+// applyTo validates the request, and sets the fields of the token.
+func (request *SavePeerTokenRequest) applyTo(token *peer_token.PeerToken) ([]Error, error) {
+	errs := []Error{}
+
+	token.Name = strings.TrimSpace(request.Name)
+	if token.Name == "" {
+		errs = append(errs, Error{
+			Location: "name",
+			Message:  "missing name",
+		})
+	}
+
+	token.Scopes = db.CommaSeperatedString{}
+	for _, scope := range request.Scopes {
+		if !peer_token.IsValidScope(scope) {
+			errs = append(errs, Error{
+				Location: "scopes",
+				Message:  "invalid scope: " + scope,
+			})
+		} else {
+			token.Scopes = append(token.Scopes, scope)
+		}
+	}
+	if len(request.Scopes) == 0 {
+		errs = append(errs, Error{
+			Location: "scopes",
+			Message:  "missing scopes",
+		})
+	}
+
+	if request.RateLimitConfigId == nil || *request.RateLimitConfigId == "" {
+		token.RateLimitConfigId = sql.NullString{Valid: false}
+	} else if config, err := ratelimit.GetById(*request.RateLimitConfigId); err != nil {
+		return nil, err
+	} else if config == nil {
+		errs = append(errs, Error{
+			Location: "rate_limit_config_id",
+			Message:  "rate limit config not found",
+		})
+	} else {
+		token.RateLimitConfigId = sql.NullString{
+			String: *request.RateLimitConfigId,
+			Valid:  true,
+		}
+	}
+
+	if request.ExpiresAt == nil || *request.ExpiresAt == "" {
+		token.ExpiresAt = db.Timestamp{}
+	} else if expiresAt, err := time.Parse(time.RFC3339, *request.ExpiresAt); err != nil {
+		errs = append(errs, Error{
+			Location: "expires_at",
+			Message:  "invalid timestamp, expected RFC3339",
+		})
+	} else {
+		token.ExpiresAt = db.Timestamp{Time: expiresAt}
+	}
+
+	return errs, nil
+}
+
+func handleCreatePeerToken(w http.ResponseWriter, r *http.Request) {
+	request := &SavePeerTokenRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	token := &peer_token.PeerToken{}
+	errs, err := request.applyTo(token)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	if err := peer_token.Create(token); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toPeerTokenResponse(token))
+}
+
+func handleUpdatePeerToken(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	request := &SavePeerTokenRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	token, err := peer_token.Get(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if token == nil {
+		ErrorNotFound(r).WithMessage("peer token not found").Send(w, r)
+		return
+	}
+
+	errs, err := request.applyTo(token)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	if err := peer_token.Update(token); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toPeerTokenResponse(token))
+}
+
+func handleDeletePeerToken(w http.ResponseWriter, r *http.Request) {
+	deleted, err := peer_token.Delete(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if !deleted {
+		ErrorNotFound(r).WithMessage("peer token not found").Send(w, r)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func AddPeerTokenEndpoints(router *http.ServeMux) {
+	authed := EnsureAuthed
+
+	router.HandleFunc("/peer-tokens", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetPeerTokens(w, r)
+		case http.MethodPost:
+			handleCreatePeerToken(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/peer-tokens/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			handleUpdatePeerToken(w, r)
+		case http.MethodDelete:
+			handleDeletePeerToken(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
@@ -13,8 +13,10 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
 	"github.com/MunifTanjim/stremthru/internal/magnet_cache"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
+	"github.com/MunifTanjim/stremthru/internal/peer"
 	"github.com/MunifTanjim/stremthru/internal/serializd"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/torrent_info"
 	"github.com/MunifTanjim/stremthru/internal/torrent_stream"
@@ -121,7 +123,11 @@ type ServerStatsFeature struct {
 }
 
 type ServerStatsIntegration struct {
-	Trakt bool `json:"trakt"`
+	AniList    bool `json:"anilist"`
+	Letterboxd bool `json:"letterboxd"`
+	MAL        bool `json:"mal"`
+	Simkl      bool `json:"simkl"`
+	Trakt      bool `json:"trakt"`
 }
 
 type ServerStats struct {
@@ -140,7 +146,11 @@ func HandleGetServerStats(w http.ResponseWriter, r *http.Request) {
 		Version:   config.Version,
 		StartedAt: config.ServerStartTime,
 		Integration: ServerStatsIntegration{
-			Trakt: config.Integration.Trakt.IsEnabled(),
+			AniList:    config.Integration.AniList.IsEnabled(),
+			Letterboxd: config.Integration.Letterboxd.IsEnabled(),
+			MAL:        config.Integration.MAL.IsEnabled(),
+			Simkl:      config.Integration.Simkl.IsEnabled(),
+			Trakt:      config.Integration.Trakt.IsEnabled(),
 		},
 	}
 	SendData(w, r, 200, data)
@@ -175,6 +185,10 @@ type ListsStats struct {
 		TotalLists int `json:"total_lists"`
 		TotalItems int `json:"total_items"`
 	} `json:"serializd"`
+	Simkl struct {
+		TotalLists int `json:"total_lists"`
+		TotalItems int `json:"total_items"`
+	} `json:"simkl"`
 }
 
 var query_get_lists_stats = fmt.Sprintf(`
@@ -191,6 +205,8 @@ UNION ALL
 SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
 UNION ALL
 SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
+UNION ALL
+SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
 `,
 	anilist.ListTableName, anilist.MediaTableName,
 	letterboxd.ListTableName, letterboxd.ItemTableName,
@@ -199,6 +215,7 @@ SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
 	trakt.ListTableName, trakt.ItemTableName,
 	tvdb.ListTableName, tvdb.ItemTableName,
 	serializd.ListTableName, serializd.ItemTableName,
+	simkl.ListTableName, simkl.ItemTableName,
 )
 
 var cachedListsStats = cache.NewCachedValue(cache.CachedValueConfig[*ListsStats]{
@@ -209,7 +226,7 @@ var cachedListsStats = cache.NewCachedValue(cache.CachedValueConfig[*ListsStats]
 		}
 		defer rows.Close()
 
-		counts := make([]int, 0, 14)
+		counts := make([]int, 0, 16)
 		for rows.Next() {
 			var count int
 			if err := rows.Scan(&count); err != nil {
@@ -233,6 +250,8 @@ var cachedListsStats = cache.NewCachedValue(cache.CachedValueConfig[*ListsStats]
 		stats.TVDB.TotalItems = counts[11]
 		stats.Serializd.TotalLists = counts[12]
 		stats.Serializd.TotalItems = counts[13]
+		stats.Simkl.TotalLists = counts[14]
+		stats.Simkl.TotalItems = counts[15]
 
 		return &stats, nil
 	},
@@ -319,3 +338,16 @@ func HandleGetStoreStats(w http.ResponseWriter, r *http.Request) {
 
 	SendData(w, r, 200, StoreStatsResponse{Stores: stores})
 }
+
+type PeersStatsResponse struct {
+	Peers []peer.FederationPeerHealth `json:"peers"`
+}
+
+func HandleGetPeersStats(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	SendData(w, r, 200, PeersStatsResponse{Peers: peer.Federation.GetHealth()})
+}
@@ -0,0 +1,280 @@
+package dash_api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	meta_stremthru "github.com/MunifTanjim/stremthru/internal/meta/stremthru"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
+)
+
+type StremThruListItemResponse struct {
+	Id        string `json:"id"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Poster    string `json:"poster"`
+	Year      int    `json:"year"`
+	CreatedAt string `json:"created_at"`
+}
+
+func toStremThruListItemResponse(item *stremthru_list.StremThruListItem) StremThruListItemResponse {
+	return StremThruListItemResponse{
+		Id:        item.Id,
+		Type:      string(item.Type),
+		Name:      item.Name,
+		Poster:    item.Poster,
+		Year:      item.Year,
+		CreatedAt: item.CAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetStremThruLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := stremthru_list.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]meta_stremthru.ListData, len(lists))
+	for i := range lists {
+		data[i] = meta_stremthru.ToListData(r, &lists[i])
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type StremThruListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (req *StremThruListRequest) validate(r *http.Request) *server.APIError {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Name == "" {
+		return ErrorBadRequest(r).Append(Error{
+			Location: "name",
+			Message:  "missing name",
+		})
+	}
+	return nil
+}
+
+func handleCreateStremThruList(w http.ResponseWriter, r *http.Request) {
+	request := &StremThruListRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if err := request.validate(r); err != nil {
+		err.Send(w, r)
+		return
+	}
+
+	ctx := GetReqCtx(r)
+	list, err := stremthru_list.Create(request.Name, request.Description, ctx.Session.User)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, meta_stremthru.ToListData(r, list))
+}
+
+func getStremThruList(w http.ResponseWriter, r *http.Request) *stremthru_list.StremThruList {
+	list, err := stremthru_list.GetById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return nil
+	}
+	if list == nil {
+		ErrorNotFound(r).WithMessage("list not found").Send(w, r)
+		return nil
+	}
+	return list
+}
+
+func handleUpdateStremThruList(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	request := &StremThruListRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if err := request.validate(r); err != nil {
+		err.Send(w, r)
+		return
+	}
+
+	if err := stremthru_list.Update(list.Id, request.Name, request.Description); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	list = getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+	SendData(w, r, 200, meta_stremthru.ToListData(r, list))
+}
+
+func handleDeleteStremThruList(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	if err := stremthru_list.Delete(list.Id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleRotateStremThruListSecret(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	secret, err := stremthru_list.RotateSecret(list.Id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	list.Secret = secret
+
+	SendData(w, r, 200, meta_stremthru.ToListData(r, list))
+}
+
+func handleGetStremThruListItems(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	items, err := stremthru_list.GetItems(list.Id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StremThruListItemResponse, len(items))
+	for i := range items {
+		data[i] = toStremThruListItemResponse(&items[i])
+	}
+
+	SendData(w, r, 200, data)
+}
+
+func handleAddStremThruListItems(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	request := &meta_stremthru.AddListItemsPayload{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(request.Items) == 0 {
+		ErrorBadRequest(r).Append(Error{
+			Location: "items",
+			Message:  "missing items",
+		}).Send(w, r)
+		return
+	}
+
+	items, err := stremthru_list.NewItems(request.ToItemInputs())
+	if err != nil {
+		ErrorBadRequest(r).Append(Error{
+			Location: "items",
+			Message:  err.Error(),
+		}).Send(w, r)
+		return
+	}
+	if err := stremthru_list.AddItems(list.Id, items); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleRemoveStremThruListItem(w http.ResponseWriter, r *http.Request) {
+	list := getStremThruList(w, r)
+	if list == nil {
+		return
+	}
+
+	if err := stremthru_list.RemoveItems(list.Id, []string{r.PathValue("item_id")}); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func AddStremThruListEndpoints(router *http.ServeMux) {
+	if !config.Feature.HasMeta() {
+		return
+	}
+
+	authed := EnsureAuthed
+
+	router.HandleFunc("/lists/stremthru", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremThruLists(w, r)
+		case http.MethodPost:
+			handleCreateStremThruList(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/lists/stremthru/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			handleUpdateStremThruList(w, r)
+		case http.MethodDelete:
+			handleDeleteStremThruList(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/lists/stremthru/{id}/secret", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleRotateStremThruListSecret(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/lists/stremthru/{id}/items", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremThruListItems(w, r)
+		case http.MethodPost:
+			handleAddStremThruListItems(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/lists/stremthru/{id}/items/{item_id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			handleRemoveStremThruListItem(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
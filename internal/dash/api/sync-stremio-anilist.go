@@ -0,0 +1,260 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	sync_stremio_anilist "github.com/MunifTanjim/stremthru/internal/sync/stremio_anilist"
+)
+
+type StremioAniListLinkResponse struct {
+	StremioAccountId string                          `json:"stremio_account_id"`
+	AniListAccountId string                          `json:"anilist_account_id"`
+	SyncConfig       sync_stremio_anilist.SyncConfig `json:"sync_config"`
+	SyncState        sync_stremio_anilist.SyncState  `json:"sync_state"`
+	CreatedAt        string                          `json:"created_at"`
+	UpdatedAt        string                          `json:"updated_at"`
+}
+
+func toStremioAniListLinkResponse(item *sync_stremio_anilist.SyncStremioAniListLink) StremioAniListLinkResponse {
+	resp := StremioAniListLinkResponse{
+		StremioAccountId: item.StremioAccountId,
+		AniListAccountId: item.AniListAccountId,
+		SyncConfig:       item.SyncConfig,
+		SyncState:        item.SyncState,
+		CreatedAt:        item.CAt.Format(time.RFC3339),
+		UpdatedAt:        item.UAt.Format(time.RFC3339),
+	}
+	return resp
+}
+
+func handleGetStremioAniListLinks(w http.ResponseWriter, r *http.Request) {
+	items, err := sync_stremio_anilist.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StremioAniListLinkResponse, len(items))
+	for i, item := range items {
+		data[i] = toStremioAniListLinkResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateStremioAniListLinkRequest struct {
+	StremioAccountId string                          `json:"stremio_account_id"`
+	AniListAccountId string                          `json:"anilist_account_id"`
+	SyncConfig       sync_stremio_anilist.SyncConfig `json:"sync_config"`
+}
+
+func handleCreateStremioAniListLink(w http.ResponseWriter, r *http.Request) {
+	request := &CreateStremioAniListLinkRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	errs := []Error{}
+	if request.StremioAccountId == "" {
+		errs = append(errs, Error{
+			Location: "stremio_account_id",
+			Message:  "missing stremio_account_id",
+		})
+	}
+	if request.AniListAccountId == "" {
+		errs = append(errs, Error{
+			Location: "anilist_account_id",
+			Message:  "missing anilist_account_id",
+		})
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	existing, err := sync_stremio_anilist.GetById(request.StremioAccountId, request.AniListAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing != nil {
+		ErrorBadRequest(r).WithMessage("link already exists").Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	link, err := sync_stremio_anilist.Link(request.StremioAccountId, request.AniListAccountId, request.SyncConfig)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toStremioAniListLinkResponse(link))
+}
+
+func handleGetStremioAniListLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, anilistAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_anilist.GetById(stremioAccountId, anilistAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendData(w, r, 200, toStremioAniListLinkResponse(link))
+}
+
+type UpdateStremioAniListAccountRequest struct {
+	SyncConfig sync_stremio_anilist.SyncConfig `json:"sync_config"`
+}
+
+func handleUpdateStremioAniListLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, anilistAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	request := &UpdateStremioAniListAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link, err := sync_stremio_anilist.GetById(stremioAccountId, anilistAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_anilist.SetSyncConfig(stremioAccountId, anilistAccountId, request.SyncConfig); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link.SyncConfig = request.SyncConfig
+	SendData(w, r, 200, toStremioAniListLinkResponse(link))
+}
+
+func handleDeleteStremioAniListLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, anilistAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_anilist.GetById(stremioAccountId, anilistAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_anilist.Unlink(stremioAccountId, anilistAccountId); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleSyncStremioAniListLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, anilistAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_anilist.GetById(stremioAccountId, anilistAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	// TODO: trigger sync immediately
+	SendData(w, r, 202, map[string]string{})
+}
+
+func handleResetStremioAniListLinkSyncState(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, anilistAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_anilist.GetById(stremioAccountId, anilistAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	link.SyncState.Watched.LastSyncedAt = nil
+
+	if err := sync_stremio_anilist.SetSyncState(
+		link.StremioAccountId,
+		link.AniListAccountId,
+		link.SyncState,
+	); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toStremioAniListLinkResponse(link))
+}
+
+func AddSyncStremioAniListEndpoints(router *http.ServeMux) {
+	authed := EnsureAuthed
+
+	router.HandleFunc("/sync/stremio-anilist/links", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioAniListLinks(w, r)
+		case http.MethodPost:
+			handleCreateStremioAniListLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-anilist/links/{account_id_pair}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioAniListLink(w, r)
+		case http.MethodPatch:
+			handleUpdateStremioAniListLink(w, r)
+		case http.MethodDelete:
+			handleDeleteStremioAniListLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-anilist/links/{account_id_pair}/sync", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleSyncStremioAniListLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-anilist/links/{account_id_pair}/reset-sync-state", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleResetStremioAniListLinkSyncState(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
@@ -0,0 +1,317 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	sync_stremio_letterboxd "github.com/MunifTanjim/stremthru/internal/sync/stremio_letterboxd"
+)
+
+type StremioLetterboxdLinkResponse struct {
+	StremioAccountId    string                             `json:"stremio_account_id"`
+	LetterboxdAccountId string                             `json:"letterboxd_account_id"`
+	SyncConfig          sync_stremio_letterboxd.SyncConfig `json:"sync_config"`
+	SyncState           sync_stremio_letterboxd.SyncState  `json:"sync_state"`
+	CreatedAt           string                             `json:"created_at"`
+	UpdatedAt           string                             `json:"updated_at"`
+}
+
+func toStremioLetterboxdLinkResponse(item *sync_stremio_letterboxd.SyncStremioLetterboxdLink) StremioLetterboxdLinkResponse {
+	resp := StremioLetterboxdLinkResponse{
+		StremioAccountId:    item.StremioAccountId,
+		LetterboxdAccountId: item.LetterboxdAccountId,
+		SyncConfig:          item.SyncConfig,
+		SyncState:           item.SyncState,
+		CreatedAt:           item.CAt.Format(time.RFC3339),
+		UpdatedAt:           item.UAt.Format(time.RFC3339),
+	}
+	return resp
+}
+
+func handleGetStremioLetterboxdLinks(w http.ResponseWriter, r *http.Request) {
+	items, err := sync_stremio_letterboxd.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StremioLetterboxdLinkResponse, len(items))
+	for i, item := range items {
+		data[i] = toStremioLetterboxdLinkResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateStremioLetterboxdLinkRequest struct {
+	StremioAccountId    string                             `json:"stremio_account_id"`
+	LetterboxdAccountId string                             `json:"letterboxd_account_id"`
+	SyncConfig          sync_stremio_letterboxd.SyncConfig `json:"sync_config"`
+}
+
+func handleCreateStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	request := &CreateStremioLetterboxdLinkRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	errs := []Error{}
+	if request.StremioAccountId == "" {
+		errs = append(errs, Error{
+			Location: "stremio_account_id",
+			Message:  "missing stremio_account_id",
+		})
+	}
+	if request.LetterboxdAccountId == "" {
+		errs = append(errs, Error{
+			Location: "letterboxd_account_id",
+			Message:  "missing letterboxd_account_id",
+		})
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	existing, err := sync_stremio_letterboxd.GetById(request.StremioAccountId, request.LetterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing != nil {
+		ErrorBadRequest(r).WithMessage("link already exists").Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	link, err := sync_stremio_letterboxd.Link(request.StremioAccountId, request.LetterboxdAccountId, request.SyncConfig)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toStremioLetterboxdLinkResponse(link))
+}
+
+func handleGetStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendData(w, r, 200, toStremioLetterboxdLinkResponse(link))
+}
+
+type UpdateStremioLetterboxdAccountRequest struct {
+	SyncConfig sync_stremio_letterboxd.SyncConfig `json:"sync_config"`
+}
+
+func handleUpdateStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	request := &UpdateStremioLetterboxdAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_letterboxd.SetSyncConfig(stremioAccountId, letterboxdAccountId, request.SyncConfig); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link.SyncConfig = request.SyncConfig
+	SendData(w, r, 200, toStremioLetterboxdLinkResponse(link))
+}
+
+func handleDeleteStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_letterboxd.Unlink(stremioAccountId, letterboxdAccountId); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleSyncStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	// TODO: trigger sync immediately
+	SendData(w, r, 202, map[string]string{})
+}
+
+type StremioLetterboxdLinkDryRunResponse struct {
+	Entries []sync_stremio_letterboxd.DiaryEntry `json:"entries"`
+}
+
+func handleDryRunStremioLetterboxdLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if stremioAccount == nil {
+		ErrorNotFound(r).WithMessage("stremio account not found").Send(w, r)
+		return
+	}
+
+	stremioToken, err := stremioAccount.GetValidToken()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	entries, err := link.GetPendingDiaryEntries(stremioToken)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if entries == nil {
+		entries = []sync_stremio_letterboxd.DiaryEntry{}
+	}
+
+	SendData(w, r, 200, StremioLetterboxdLinkDryRunResponse{
+		Entries: entries,
+	})
+}
+
+func handleResetStremioLetterboxdLinkSyncState(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, letterboxdAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_letterboxd.GetById(stremioAccountId, letterboxdAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	link.SyncState.Watched.LastSyncedAt = nil
+	link.SyncState.Watched.TimesWatched = nil
+
+	if err := sync_stremio_letterboxd.SetSyncState(
+		link.StremioAccountId,
+		link.LetterboxdAccountId,
+		link.SyncState,
+	); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toStremioLetterboxdLinkResponse(link))
+}
+
+func AddSyncStremioLetterboxdEndpoints(router *http.ServeMux) {
+	authed := EnsureAuthed
+
+	router.HandleFunc("/sync/stremio-letterboxd/links", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioLetterboxdLinks(w, r)
+		case http.MethodPost:
+			handleCreateStremioLetterboxdLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-letterboxd/links/{account_id_pair}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioLetterboxdLink(w, r)
+		case http.MethodPatch:
+			handleUpdateStremioLetterboxdLink(w, r)
+		case http.MethodDelete:
+			handleDeleteStremioLetterboxdLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-letterboxd/links/{account_id_pair}/sync", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleSyncStremioLetterboxdLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-letterboxd/links/{account_id_pair}/dry-run", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleDryRunStremioLetterboxdLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-letterboxd/links/{account_id_pair}/reset-sync-state", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleResetStremioLetterboxdLinkSyncState(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
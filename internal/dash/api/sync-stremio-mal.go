@@ -0,0 +1,260 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	sync_stremio_mal "github.com/MunifTanjim/stremthru/internal/sync/stremio_mal"
+)
+
+type StremioMALLinkResponse struct {
+	StremioAccountId string                      `json:"stremio_account_id"`
+	MALAccountId     string                      `json:"mal_account_id"`
+	SyncConfig       sync_stremio_mal.SyncConfig `json:"sync_config"`
+	SyncState        sync_stremio_mal.SyncState  `json:"sync_state"`
+	CreatedAt        string                      `json:"created_at"`
+	UpdatedAt        string                      `json:"updated_at"`
+}
+
+func toStremioMALLinkResponse(item *sync_stremio_mal.SyncStremioMALLink) StremioMALLinkResponse {
+	resp := StremioMALLinkResponse{
+		StremioAccountId: item.StremioAccountId,
+		MALAccountId:     item.MALAccountId,
+		SyncConfig:       item.SyncConfig,
+		SyncState:        item.SyncState,
+		CreatedAt:        item.CAt.Format(time.RFC3339),
+		UpdatedAt:        item.UAt.Format(time.RFC3339),
+	}
+	return resp
+}
+
+func handleGetStremioMALLinks(w http.ResponseWriter, r *http.Request) {
+	items, err := sync_stremio_mal.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StremioMALLinkResponse, len(items))
+	for i, item := range items {
+		data[i] = toStremioMALLinkResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateStremioMALLinkRequest struct {
+	StremioAccountId string                      `json:"stremio_account_id"`
+	MALAccountId     string                      `json:"mal_account_id"`
+	SyncConfig       sync_stremio_mal.SyncConfig `json:"sync_config"`
+}
+
+func handleCreateStremioMALLink(w http.ResponseWriter, r *http.Request) {
+	request := &CreateStremioMALLinkRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	errs := []Error{}
+	if request.StremioAccountId == "" {
+		errs = append(errs, Error{
+			Location: "stremio_account_id",
+			Message:  "missing stremio_account_id",
+		})
+	}
+	if request.MALAccountId == "" {
+		errs = append(errs, Error{
+			Location: "mal_account_id",
+			Message:  "missing mal_account_id",
+		})
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	existing, err := sync_stremio_mal.GetById(request.StremioAccountId, request.MALAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing != nil {
+		ErrorBadRequest(r).WithMessage("link already exists").Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	link, err := sync_stremio_mal.Link(request.StremioAccountId, request.MALAccountId, request.SyncConfig)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toStremioMALLinkResponse(link))
+}
+
+func handleGetStremioMALLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, malAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_mal.GetById(stremioAccountId, malAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendData(w, r, 200, toStremioMALLinkResponse(link))
+}
+
+type UpdateStremioMALAccountRequest struct {
+	SyncConfig sync_stremio_mal.SyncConfig `json:"sync_config"`
+}
+
+func handleUpdateStremioMALLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, malAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	request := &UpdateStremioMALAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link, err := sync_stremio_mal.GetById(stremioAccountId, malAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if !request.SyncConfig.Watched.Direction.IsValid() {
+		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_mal.SetSyncConfig(stremioAccountId, malAccountId, request.SyncConfig); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	link.SyncConfig = request.SyncConfig
+	SendData(w, r, 200, toStremioMALLinkResponse(link))
+}
+
+func handleDeleteStremioMALLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, malAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_mal.GetById(stremioAccountId, malAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_mal.Unlink(stremioAccountId, malAccountId); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleSyncStremioMALLink(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, malAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_mal.GetById(stremioAccountId, malAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	// TODO: trigger sync immediately
+	SendData(w, r, 202, map[string]string{})
+}
+
+func handleResetStremioMALLinkSyncState(w http.ResponseWriter, r *http.Request) {
+	stremioAccountId, malAccountId := parseAccountIdPair(r.PathValue("account_id_pair"))
+
+	link, err := sync_stremio_mal.GetById(stremioAccountId, malAccountId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if link == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	link.SyncState.Watched.LastSyncedAt = nil
+
+	if err := sync_stremio_mal.SetSyncState(
+		link.StremioAccountId,
+		link.MALAccountId,
+		link.SyncState,
+	); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toStremioMALLinkResponse(link))
+}
+
+func AddSyncStremioMALEndpoints(router *http.ServeMux) {
+	authed := EnsureAuthed
+
+	router.HandleFunc("/sync/stremio-mal/links", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioMALLinks(w, r)
+		case http.MethodPost:
+			handleCreateStremioMALLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-mal/links/{account_id_pair}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioMALLink(w, r)
+		case http.MethodPatch:
+			handleUpdateStremioMALLink(w, r)
+		case http.MethodDelete:
+			handleDeleteStremioMALLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-mal/links/{account_id_pair}/sync", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleSyncStremioMALLink(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-mal/links/{account_id_pair}/reset-sync-state", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleResetStremioMALLinkSyncState(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
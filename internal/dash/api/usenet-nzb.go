@@ -2,7 +2,6 @@ package dash_api
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"io"
 	"net/http"
@@ -156,6 +155,7 @@ type NZBResponse struct {
 	Date           string                     `json:"date"`
 	Status         string                     `json:"status"`
 	InspectionMeta *NZBInspectionMetaResponse `json:"inspection_meta,omitempty"`
+	Health         *usenet_pool.NZBHealth     `json:"health,omitempty"`
 	CreatedAt      string                     `json:"created_at"`
 	UpdatedAt      string                     `json:"updated_at"`
 }
@@ -224,6 +224,9 @@ func toNZBResponse(info *nzb_info.NZBInfo) NZBResponse {
 			Error:      info.InspectionMeta.Data.Error,
 		}
 	}
+	if !info.Health.Null {
+		resp.Health = &info.Health.Data
+	}
 	return resp
 }
 
@@ -332,7 +335,7 @@ func handleUploadNZB(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nzbDoc, err := nzb.ParseBytes(blob)
+	queueId, err := nzb_info.AddNZBFile(ctx.Session.User, fileHeader.Filename, r.FormValue("name"), blob)
 	if err != nil {
 		if parseErr, ok := err.(*nzb.ParseError); ok {
 			ErrorBadRequest(r).WithMessage(parseErr.Error()).Send(w, r)
@@ -342,58 +345,6 @@ func handleUploadNZB(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nzbId := nzbDoc.HashByFileBoundarySegmentIds()
-	link := config.BaseURL.JoinPath("/v0/newznab/getnzb/", nzbId)
-	linkQuery := link.Query()
-	apikey := util.Base64Encode(ctx.Session.User + ":" + config.Auth.GetPassword(ctx.Session.User))
-	linkQuery.Set("apikey", apikey)
-	link.RawQuery = linkQuery.Encode()
-
-	filename := fileHeader.Filename
-	if !strings.HasSuffix(filename, ".nzb") {
-		filename += ".nzb"
-	}
-
-	nzbFile := nzb_info.NZBFile{
-		Blob: blob,
-		Name: filename,
-		Link: link.String(),
-		Mod:  time.Now(),
-	}
-
-	hash := util.HashNZBFileLink(nzbFile.Link)
-	if err := nzb_info.CacheNZBFile(hash, nzbFile); err != nil {
-		SendError(w, r, err)
-		return
-	}
-
-	name := r.FormValue("name")
-	if name == "" {
-		name = filename
-	}
-
-	if err := nzb_info.Upsert(&nzb_info.NZBInfo{
-		Id:        nzbId,
-		Hash:      hash,
-		Name:      name,
-		Size:      nzbDoc.TotalSize(),
-		FileCount: nzbDoc.FileCount(),
-		Password:  "",
-		URL:       nzbFile.Link,
-		User:      ctx.Session.User,
-		Status:    "queued",
-		IndexerId: sql.NullInt64{Valid: false},
-	}); err != nil {
-		SendError(w, r, err)
-		return
-	}
-
-	queueId, err := nzb_info.QueueJob(ctx.Session.User, name, nzbFile.Link, "", 0, "", 0)
-	if err != nil {
-		SendError(w, r, err)
-		return
-	}
-
 	queueItem, err := nzb_info.GetJobById(queueId)
 	if err != nil {
 		SendError(w, r, err)
@@ -0,0 +1,172 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anilist"
+	anilist_account "github.com/MunifTanjim/stremthru/internal/anilist/account"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type AniListAccountResponse struct {
+	Id        string `json:"id"`
+	UserName  string `json:"user_name"`
+	IsValid   bool   `json:"is_valid"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func toAniListAccountResponse(item *anilist_account.AniListAccount) AniListAccountResponse {
+	username := ""
+	if otok := item.OAuthToken(); otok != nil {
+		username = otok.UserName
+	}
+	return AniListAccountResponse{
+		Id:        item.Id,
+		UserName:  username,
+		IsValid:   item.IsValid(),
+		CreatedAt: item.CAt.Format(time.RFC3339),
+		UpdatedAt: item.UAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetAniListAccounts(w http.ResponseWriter, r *http.Request) {
+	items, err := anilist_account.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]AniListAccountResponse, len(items))
+	for i, item := range items {
+		data[i] = toAniListAccountResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateAniListAccountRequest struct {
+	OAuthTokenId string `json:"oauth_token_id"`
+}
+
+func handleCreateAniListAccount(w http.ResponseWriter, r *http.Request) {
+	request := &CreateAniListAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if request.OAuthTokenId == "" {
+		ErrorBadRequest(r).Append(Error{
+			Location: "oauth_token_id",
+			Message:  "missing oauth_token_id",
+		}).Send(w, r)
+		return
+	}
+
+	account, err := anilist_account.Insert(request.OAuthTokenId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toAniListAccountResponse(account))
+}
+
+func handleGetAniListAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	account, err := anilist_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if account == nil {
+		ErrorNotFound(r).WithMessage("anilist account not found").Send(w, r)
+		return
+	}
+
+	forceRefresh := util.StringToBool(r.URL.Query().Get("refresh"), false)
+	if forceRefresh {
+		client := anilist.GetAPIClient(account.OAuthTokenId)
+		_, err := client.GetViewer()
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+	}
+
+	SendData(w, r, 200, toAniListAccountResponse(account))
+}
+
+func handleDeleteAniListAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	existing, err := anilist_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing == nil {
+		ErrorNotFound(r).WithMessage("anilist account not found").Send(w, r)
+		return
+	}
+
+	if err := anilist_account.Delete(id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+type AniListAuthURLResponse struct {
+	URL string `json:"url"`
+}
+
+func handleGetAniListAuthURL(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	authURL := oauth.AniListOAuthConfig.AuthCodeURL(state)
+	SendData(w, r, 200, AniListAuthURLResponse{
+		URL: authURL,
+	})
+}
+
+func AddVaultAniListEndpoints(router *http.ServeMux) {
+	if !config.Integration.AniList.IsEnabled() {
+		return
+	}
+
+	authed := EnsureAuthed
+
+	router.HandleFunc("/vault/anilist/accounts", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetAniListAccounts(w, r)
+		case http.MethodPost:
+			handleCreateAniListAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/anilist/accounts/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetAniListAccount(w, r)
+		case http.MethodDelete:
+			handleDeleteAniListAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/anilist/auth/url", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetAniListAuthURL(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
@@ -0,0 +1,172 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	letterboxd_account "github.com/MunifTanjim/stremthru/internal/letterboxd/account"
+	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type LetterboxdAccountResponse struct {
+	Id        string `json:"id"`
+	UserName  string `json:"user_name"`
+	IsValid   bool   `json:"is_valid"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func toLetterboxdAccountResponse(item *letterboxd_account.LetterboxdAccount) LetterboxdAccountResponse {
+	username := ""
+	if otok := item.OAuthToken(); otok != nil {
+		username = otok.UserName
+	}
+	return LetterboxdAccountResponse{
+		Id:        item.Id,
+		UserName:  username,
+		IsValid:   item.IsValid(),
+		CreatedAt: item.CAt.Format(time.RFC3339),
+		UpdatedAt: item.UAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetLetterboxdAccounts(w http.ResponseWriter, r *http.Request) {
+	items, err := letterboxd_account.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]LetterboxdAccountResponse, len(items))
+	for i, item := range items {
+		data[i] = toLetterboxdAccountResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateLetterboxdAccountRequest struct {
+	OAuthTokenId string `json:"oauth_token_id"`
+}
+
+func handleCreateLetterboxdAccount(w http.ResponseWriter, r *http.Request) {
+	request := &CreateLetterboxdAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if request.OAuthTokenId == "" {
+		ErrorBadRequest(r).Append(Error{
+			Location: "oauth_token_id",
+			Message:  "missing oauth_token_id",
+		}).Send(w, r)
+		return
+	}
+
+	account, err := letterboxd_account.Insert(request.OAuthTokenId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toLetterboxdAccountResponse(account))
+}
+
+func handleGetLetterboxdAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	account, err := letterboxd_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if account == nil {
+		ErrorNotFound(r).WithMessage("letterboxd account not found").Send(w, r)
+		return
+	}
+
+	forceRefresh := util.StringToBool(r.URL.Query().Get("refresh"), false)
+	if forceRefresh {
+		client := letterboxd.GetAPIClient(account.OAuthTokenId)
+		_, err := client.FetchMe(&letterboxd.FetchMeParams{})
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+	}
+
+	SendData(w, r, 200, toLetterboxdAccountResponse(account))
+}
+
+func handleDeleteLetterboxdAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	existing, err := letterboxd_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing == nil {
+		ErrorNotFound(r).WithMessage("letterboxd account not found").Send(w, r)
+		return
+	}
+
+	if err := letterboxd_account.Delete(id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+type LetterboxdAuthURLResponse struct {
+	URL string `json:"url"`
+}
+
+func handleGetLetterboxdAuthURL(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	authURL := oauth.LetterboxdOAuthConfig.AuthCodeURL(state)
+	SendData(w, r, 200, LetterboxdAuthURLResponse{
+		URL: authURL,
+	})
+}
+
+func AddVaultLetterboxdEndpoints(router *http.ServeMux) {
+	if !config.Integration.Letterboxd.IsEnabled() {
+		return
+	}
+
+	authed := EnsureAuthed
+
+	router.HandleFunc("/vault/letterboxd/accounts", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetLetterboxdAccounts(w, r)
+		case http.MethodPost:
+			handleCreateLetterboxdAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/letterboxd/accounts/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetLetterboxdAccount(w, r)
+		case http.MethodDelete:
+			handleDeleteLetterboxdAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/letterboxd/auth/url", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetLetterboxdAuthURL(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
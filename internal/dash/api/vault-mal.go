@@ -0,0 +1,172 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/mal"
+	mal_account "github.com/MunifTanjim/stremthru/internal/mal/account"
+	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type MALAccountResponse struct {
+	Id        string `json:"id"`
+	UserName  string `json:"user_name"`
+	IsValid   bool   `json:"is_valid"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func toMALAccountResponse(item *mal_account.MALAccount) MALAccountResponse {
+	username := ""
+	if otok := item.OAuthToken(); otok != nil {
+		username = otok.UserName
+	}
+	return MALAccountResponse{
+		Id:        item.Id,
+		UserName:  username,
+		IsValid:   item.IsValid(),
+		CreatedAt: item.CAt.Format(time.RFC3339),
+		UpdatedAt: item.UAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetMALAccounts(w http.ResponseWriter, r *http.Request) {
+	items, err := mal_account.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]MALAccountResponse, len(items))
+	for i, item := range items {
+		data[i] = toMALAccountResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateMALAccountRequest struct {
+	OAuthTokenId string `json:"oauth_token_id"`
+}
+
+func handleCreateMALAccount(w http.ResponseWriter, r *http.Request) {
+	request := &CreateMALAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if request.OAuthTokenId == "" {
+		ErrorBadRequest(r).Append(Error{
+			Location: "oauth_token_id",
+			Message:  "missing oauth_token_id",
+		}).Send(w, r)
+		return
+	}
+
+	account, err := mal_account.Insert(request.OAuthTokenId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toMALAccountResponse(account))
+}
+
+func handleGetMALAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	account, err := mal_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if account == nil {
+		ErrorNotFound(r).WithMessage("mal account not found").Send(w, r)
+		return
+	}
+
+	forceRefresh := util.StringToBool(r.URL.Query().Get("refresh"), false)
+	if forceRefresh {
+		client := mal.GetAPIClient(account.OAuthTokenId)
+		_, err := client.GetMyUserInfo(&mal.GetMyUserInfoParams{})
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+	}
+
+	SendData(w, r, 200, toMALAccountResponse(account))
+}
+
+func handleDeleteMALAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	existing, err := mal_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing == nil {
+		ErrorNotFound(r).WithMessage("mal account not found").Send(w, r)
+		return
+	}
+
+	if err := mal_account.Delete(id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+type MALAuthURLResponse struct {
+	URL string `json:"url"`
+}
+
+func handleGetMALAuthURL(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	authURL := oauth.MALOAuthConfig.AuthCodeURL(state)
+	SendData(w, r, 200, MALAuthURLResponse{
+		URL: authURL,
+	})
+}
+
+func AddVaultMALEndpoints(router *http.ServeMux) {
+	if !config.Integration.MAL.IsEnabled() {
+		return
+	}
+
+	authed := EnsureAuthed
+
+	router.HandleFunc("/vault/mal/accounts", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetMALAccounts(w, r)
+		case http.MethodPost:
+			handleCreateMALAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/mal/accounts/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetMALAccount(w, r)
+		case http.MethodDelete:
+			handleDeleteMALAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/mal/auth/url", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetMALAuthURL(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
@@ -3,6 +3,7 @@ package dash_api
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/nntp"
@@ -21,11 +22,21 @@ type UsenetServerResponse struct {
 	Priority       int    `json:"priority"`
 	IsBackup       bool   `json:"is_backup"`
 	MaxConnections int    `json:"max_connections"`
+	Proxy          string `json:"proxy"`
 	Disabled       bool   `json:"disabled"`
 	CreatedAt      string `json:"created_at"`
 	UpdatedAt      string `json:"updated_at"`
 }
 
+// getRedactedProxy returns the proxy url with the password masked.
+func getRedactedProxy(item *usenet_server.UsenetServer) string {
+	proxy, err := item.GetProxyURL()
+	if err != nil || proxy == nil {
+		return ""
+	}
+	return proxy.Redacted()
+}
+
 func toUsenetServerResponse(item *usenet_server.UsenetServer) UsenetServerResponse {
 	return UsenetServerResponse{
 		Id:             item.Id,
@@ -38,6 +49,7 @@ func toUsenetServerResponse(item *usenet_server.UsenetServer) UsenetServerRespon
 		Priority:       item.Priority,
 		IsBackup:       item.IsBackup,
 		MaxConnections: item.MaxConnections,
+		Proxy:          getRedactedProxy(item),
 		Disabled:       item.Disabled,
 		CreatedAt:      item.CAt.Format(time.RFC3339),
 		UpdatedAt:      item.UAt.Format(time.RFC3339),
@@ -70,6 +82,7 @@ type CreateUsenetServerRequest struct {
 	Priority       int    `json:"priority"`
 	IsBackup       bool   `json:"is_backup"`
 	MaxConnections int    `json:"max_connections"`
+	Proxy          string `json:"proxy"`
 }
 
 func handleCreateUsenetServer(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +105,14 @@ func handleCreateUsenetServer(w http.ResponseWriter, r *http.Request) {
 			Message:  "missing host",
 		})
 	}
+	if request.Proxy != "" {
+		if _, err := usenet_server.ParseProxy(request.Proxy); err != nil {
+			errs = append(errs, Error{
+				Location: "proxy",
+				Message:  "invalid proxy: " + err.Error(),
+			})
+		}
+	}
 	if len(errs) > 0 {
 		ErrorBadRequest(r).Append(errs...).Send(w, r)
 		return
@@ -120,6 +141,7 @@ func handleCreateUsenetServer(w http.ResponseWriter, r *http.Request) {
 		request.Priority,
 		request.IsBackup,
 		request.MaxConnections,
+		request.Proxy,
 	)
 	if err != nil {
 		SendError(w, r, err)
@@ -152,16 +174,17 @@ func handleGetUsenetServer(w http.ResponseWriter, r *http.Request) {
 }
 
 type UpdateUsenetServerRequest struct {
-	Name           string `json:"name"`
-	Host           string `json:"host"`
-	Port           int    `json:"port"`
-	Username       string `json:"username"`
-	Password       string `json:"password"`
-	TLS            *bool  `json:"tls"`
-	TLSSkipVerify  *bool  `json:"tls_skip_verify"`
-	Priority       *int   `json:"priority"`
-	IsBackup       *bool  `json:"is_backup"`
-	MaxConnections *int   `json:"max_connections"`
+	Name           string  `json:"name"`
+	Host           string  `json:"host"`
+	Port           int     `json:"port"`
+	Username       string  `json:"username"`
+	Password       string  `json:"password"`
+	TLS            *bool   `json:"tls"`
+	TLSSkipVerify  *bool   `json:"tls_skip_verify"`
+	Priority       *int    `json:"priority"`
+	IsBackup       *bool   `json:"is_backup"`
+	MaxConnections *int    `json:"max_connections"`
+	Proxy          *string `json:"proxy"`
 }
 
 func handleUpdateUsenetServer(w http.ResponseWriter, r *http.Request) {
@@ -225,6 +248,22 @@ func handleUpdateUsenetServer(w http.ResponseWriter, r *http.Request) {
 			server.MaxConnections = 10
 		}
 	}
+	// the redacted proxy is sent back unchanged
+	if request.Proxy != nil && *request.Proxy != getRedactedProxy(server) {
+		if *request.Proxy != "" {
+			if _, err := usenet_server.ParseProxy(*request.Proxy); err != nil {
+				ErrorBadRequest(r).Append(Error{
+					Location: "proxy",
+					Message:  "invalid proxy: " + err.Error(),
+				}).Send(w, r)
+				return
+			}
+		}
+		if err := server.SetProxy(*request.Proxy); err != nil {
+			SendError(w, r, err)
+			return
+		}
+	}
 
 	newProviderId := server.ProviderId()
 
@@ -277,6 +316,7 @@ type PingUsenetServerRequest struct {
 	Password      string `json:"password"`
 	TLS           bool   `json:"tls"`
 	TLSSkipVerify bool   `json:"tls_skip_verify"`
+	Proxy         string `json:"proxy"`
 }
 
 type PingUsenetServerResponse struct {
@@ -311,6 +351,27 @@ func handlePingUsenetServer(w http.ResponseWriter, r *http.Request) {
 		if request.Port == 0 {
 			request.Port = existing.Port
 		}
+		if request.Proxy != "" && request.Proxy == getRedactedProxy(existing) {
+			proxy, err := existing.GetProxy()
+			if err != nil {
+				SendError(w, r, err)
+				return
+			}
+			request.Proxy = proxy
+		}
+	}
+
+	var proxy *url.URL
+	if request.Proxy != "" {
+		p, err := usenet_server.ParseProxy(request.Proxy)
+		if err != nil {
+			ErrorBadRequest(r).Append(Error{
+				Location: "proxy",
+				Message:  "invalid proxy: " + err.Error(),
+			}).Send(w, r)
+			return
+		}
+		proxy = p
 	}
 
 	if request.Port == 0 {
@@ -334,6 +395,7 @@ func handlePingUsenetServer(w http.ResponseWriter, r *http.Request) {
 		Password:      request.Password,
 		TLS:           request.TLS,
 		TLSSkipVerify: request.TLSSkipVerify,
+		Proxy:         proxy,
 		Deadline:      time.Now().Add(15 * time.Second),
 		DialTimeout:   10 * time.Second,
 		KeepAliveTime: 20 * time.Second,
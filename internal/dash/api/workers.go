@@ -283,6 +283,8 @@ func handleResetWorkerProgress(w http.ResponseWriter, r *http.Request) {
 		err = worker.ResetSyncBitmagnetCursor()
 	case "sync-dmm-hashlist":
 		err = worker.ResetSyncDMMHashlistProgress()
+	case "sync-peer-torrents":
+		err = worker.ResetSyncPeerTorrentsCursor()
 	default:
 		ErrorBadRequest(r).WithMessage("worker does not support progress reset").Send(w, r)
 		return
@@ -33,6 +33,7 @@ func AddEndpoints(mux *http.ServeMux) {
 	router.HandleFunc("/stats/server", authed(dash_api.HandleGetServerStats))
 	router.HandleFunc("/stats/torznab-indexers", authed(dash_api.HandleGetTorznabIndexerStats))
 	router.HandleFunc("/stats/stores", authed(dash_api.HandleGetStoreStats))
+	router.HandleFunc("/stats/peers", authed(dash_api.HandleGetPeersStats))
 	router.HandleFunc("/stats/usenet-servers/history", authed(dash_api.HandleGetUsenetServerStatsHistory))
 	router.HandleFunc("/stats/usenet-servers/timeseries", authed(dash_api.HandleGetUsenetServerStatsTimeSeries))
 	router.HandleFunc("/stats/newznab-indexers/history", authed(dash_api.HandleGetNewznabIndexerStatsHistory))
@@ -41,18 +42,24 @@ func AddEndpoints(mux *http.ServeMux) {
 	router.HandleFunc("/config", authed(dash_api.HandleGetConfig))
 
 	dash_api.AddIMDBEndpoints(router)
+	dash_api.AddStremThruListEndpoints(router)
 	dash_api.AddAniDBEndpoints(router)
 	dash_api.AddWorkerEndpoints(router)
 	dash_api.AddTorrentInfoEndpoints(router)
 	dash_api.AddTorrentReprocessEndpoint(router)
 	dash_api.AddTorznabIndexerSyncInfoEndpoints(router)
 	dash_api.AddRateLimitEndpoints(router)
+	dash_api.AddPeerTokenEndpoints(router)
 	dash_api.AddMaintenanceEndpoints(router)
 	dash_api.AddProxyEndpoints(router)
 
 	if config.Feature.HasVault() {
 		dash_api.AddVaultStremioEndpoints(router)
 		dash_api.AddVaultTraktEndpoints(router)
+		dash_api.AddVaultAniListEndpoints(router)
+		dash_api.AddVaultMALEndpoints(router)
+		dash_api.AddVaultSimklEndpoints(router)
+		dash_api.AddVaultLetterboxdEndpoints(router)
 		dash_api.AddVaultTorznabEndpoints(router)
 		dash_api.AddUsenetNZBEndpoints(router)
 		dash_api.AddUsenetConfigEndpoints(router)
@@ -64,6 +71,18 @@ func AddEndpoints(mux *http.ServeMux) {
 		if config.Integration.Trakt.IsEnabled() {
 			dash_api.AddSyncStremioTraktEndpoints(router)
 		}
+		if config.Integration.AniList.IsEnabled() {
+			dash_api.AddSyncStremioAniListEndpoints(router)
+		}
+		if config.Integration.MAL.IsEnabled() {
+			dash_api.AddSyncStremioMALEndpoints(router)
+		}
+		if config.Integration.Simkl.IsEnabled() {
+			dash_api.AddSyncStremioSimklEndpoints(router)
+		}
+		if config.Integration.Letterboxd.IsEnabled() {
+			dash_api.AddSyncStremioLetterboxdEndpoints(router)
+		}
 	}
 
 	mux.Handle("/dash/api/", http.StripPrefix("/dash/api", dash_api.WithMiddleware(commonMiddleware)(router.ServeHTTP)))
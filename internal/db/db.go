@@ -47,7 +47,7 @@ var NewAdvisoryLock func(names ...string) AdvisoryLock
 var InStringValues func(values []string) (query string, args []any)
 
 var connUri, dsnModifiers = func() (ConnectionURI, []DSNModifier) {
-	uri, err := ParseConnectionURI(config.DatabaseURI)
+	uri, err := ParseConnectionURI(loadDatabaseSwitch(config.DatabaseURI))
 	if err != nil {
 		log.Fatalf("[db] failed to parse uri: %v\n", err)
 	}
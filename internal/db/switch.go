@@ -0,0 +1,124 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+)
+
+// DatabaseSwitch is written once the data is copied to another database
+// (`stremthru db switch`). The next start on the source database finishes
+// the copy, after that the target database is used in place of the source,
+// till STREMTHRU_DATABASE_URI is updated to the target.
+type DatabaseSwitch struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// set once the start on the source finishes the copy
+	SwitchedAt *time.Time `json:"switched_at,omitempty"`
+}
+
+func (s *DatabaseSwitch) IsPending() bool {
+	return s.SwitchedAt == nil
+}
+
+func getDatabaseSwitchFilePath() string {
+	return filepath.Join(config.DataDir, "database_switch.json")
+}
+
+func LoadDatabaseSwitch() (*DatabaseSwitch, error) {
+	blob, err := os.ReadFile(getDatabaseSwitchFilePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	s := &DatabaseSwitch{}
+	if err := json.Unmarshal(blob, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the switch, it contains the credentials of the databases.
+func (s *DatabaseSwitch) Save() error {
+	blob, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getDatabaseSwitchFilePath(), blob, 0600)
+}
+
+func RemoveDatabaseSwitch() error {
+	err := os.Remove(getDatabaseSwitchFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+var databaseSwitch *DatabaseSwitch
+
+// GetDatabaseSwitch returns the switch of the configured database, if any.
+func GetDatabaseSwitch() *DatabaseSwitch {
+	return databaseSwitch
+}
+
+var databaseURI string
+
+// GetDatabaseURI returns the uri of the database that is used, i.e. the
+// configured one, or the target of its finished switch.
+func GetDatabaseURI() string {
+	return databaseURI
+}
+
+var (
+	ErrDatabaseSwitched          = errors.New("database is switched, restart to use the target database")
+	ErrDatabaseSwitchNotFinished = errors.New("database switch is not finished, start with STREMTHRU_DATABASE_URI set to the source database to finish it")
+)
+
+// CheckDatabaseSwitch returns an error if the database must not be used
+// because of the switch.
+func CheckDatabaseSwitch() error {
+	s := databaseSwitch
+	switch {
+	case s == nil:
+		return nil
+	case s.From == databaseURI && !s.IsPending():
+		// the source is stale
+		return ErrDatabaseSwitched
+	case s.To == databaseURI && s.IsPending():
+		// the changes recorded in the source are not synced yet
+		return ErrDatabaseSwitchNotFinished
+	}
+	return nil
+}
+
+// loadDatabaseSwitch loads the switch of the configured database, and
+// returns the uri of the database to use. Once the switch is finished, the
+// target is used in place of the source.
+func loadDatabaseSwitch(uri string) string {
+	databaseURI = uri
+	s, err := LoadDatabaseSwitch()
+	if err != nil {
+		log.Fatalf("[db] failed to load database switch: %v\n", err)
+	}
+	if s == nil {
+		return uri
+	}
+	if s.From != uri && s.To != uri {
+		log.Printf("[db] ignoring database switch, configured database is neither its source nor its target\n")
+		return uri
+	}
+	databaseSwitch = s
+	if s.From == uri && !s.IsPending() {
+		log.Printf("[db] database is switched, using the target database from database_switch.json till STREMTHRU_DATABASE_URI is updated\n")
+		databaseURI = s.To
+	}
+	return databaseURI
+}
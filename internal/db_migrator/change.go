@@ -0,0 +1,378 @@
+package db_migrator
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+// The changes made while the instance keeps running on the source are
+// captured and replayed, the instance does not write to both databases.
+// The primary keys of the changed rows are recorded by triggers, which adds
+// an insert to every write on the source till the capture is stopped.
+const changeTableName = "db_migrator_change"
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func getChangeTriggerName(table, op string) string {
+	return changeTableName + "_" + table + "_" + op
+}
+
+func getChangeCaptureQueries(table *Table) ([]string, error) {
+	primaryKey := table.getPrimaryKey()
+	if len(primaryKey) == 0 {
+		return nil, fmt.Errorf("table %s has no primary key", table.Name)
+	}
+	for _, name := range primaryKey {
+		if table.getColumn(name).kind == kindBlob {
+			return nil, fmt.Errorf("table %s has blob primary key", table.Name)
+		}
+	}
+
+	pk := func(row string) string {
+		values := make([]string, len(primaryKey))
+		for i, name := range primaryKey {
+			values[i] = row + "." + quoteIdent(name)
+		}
+		return "(" + quoteLiteral(table.Name) + ", json_array(" + strings.Join(values, ",") + "))"
+	}
+	trigger := func(op, values string) string {
+		return "CREATE TRIGGER IF NOT EXISTS " + quoteIdent(getChangeTriggerName(table.Name, op)) +
+			" AFTER " + strings.ToUpper(op) + " ON " + quoteIdent(table.Name) +
+			" BEGIN INSERT INTO " + changeTableName + " (table_name, pk) VALUES " + values + "; END"
+	}
+	return []string{
+		trigger("insert", pk("NEW")),
+		trigger("update", pk("OLD")+","+pk("NEW")),
+		trigger("delete", pk("OLD")),
+	}, nil
+}
+
+// StartChangeCapture records the changes made to the source tables that
+// are also present in target, so that the changes made while copying can
+// be synced afterwards. Only SQLite is supported as source, it relies on
+// SQLite triggers.
+func StartChangeCapture(source, target *Conn) error {
+	if source.Dialect != db.DBDialectSQLite {
+		return fmt.Errorf("change capture is not supported for %s", source.Dialect)
+	}
+
+	names, err := listCommonTables(source, target)
+	if err != nil {
+		return err
+	}
+	queries := []string{
+		"CREATE TABLE IF NOT EXISTS " + changeTableName + " (id integer PRIMARY KEY AUTOINCREMENT, table_name text NOT NULL, pk text NOT NULL)",
+	}
+	for _, name := range names {
+		table, err := source.GetTable(name)
+		if err != nil {
+			return err
+		}
+		tableQueries, err := getChangeCaptureQueries(table)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, tableQueries...)
+	}
+
+	tx, err := source.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// StopChangeCapture removes the triggers and the recorded changes.
+func StopChangeCapture(source *Conn) error {
+	if source.Dialect != db.DBDialectSQLite {
+		return nil
+	}
+
+	rows, err := source.Query("SELECT name FROM sqlite_master WHERE type = 'trigger' AND name LIKE ? ESCAPE '\\'", strings.ReplaceAll(changeTableName, "_", "\\_")+"\\_%")
+	if err != nil {
+		return err
+	}
+	triggers := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		triggers = append(triggers, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := source.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, name := range triggers {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + quoteIdent(name)); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + changeTableName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type syncTable struct {
+	name          string
+	columns       []string
+	sourceColumns []*Column
+	targetColumns []*Column
+	primaryKey    []string
+	target        *Table
+}
+
+func getSyncTable(source, target *Conn, name string) (*syncTable, error) {
+	sourceTable, err := source.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	targetTable, err := target.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	if sourceTable == nil || targetTable == nil {
+		return nil, nil
+	}
+
+	t := &syncTable{
+		name:       name,
+		columns:    getCopyColumns(sourceTable, targetTable),
+		primaryKey: sourceTable.getPrimaryKey(),
+		target:     targetTable,
+	}
+	for _, col := range t.columns {
+		t.sourceColumns = append(t.sourceColumns, sourceTable.getColumn(col))
+		t.targetColumns = append(t.targetColumns, targetTable.getColumn(col))
+	}
+	for _, col := range t.primaryKey {
+		if !slices.Contains(t.columns, col) {
+			return nil, fmt.Errorf("table %s: primary key column %s is missing in target", name, col)
+		}
+	}
+	return t, nil
+}
+
+func (t *syncTable) getColumnIndex(name string) int {
+	return slices.Index(t.columns, name)
+}
+
+func (t *syncTable) getUpsertQuery(target *Conn) string {
+	var query strings.Builder
+	query.WriteString("INSERT INTO " + quoteIdent(t.name) + " (")
+	for i, col := range t.columns {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(quoteIdent(col))
+	}
+	query.WriteString(") VALUES (")
+	for i := range t.columns {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(target.placeholder(i + 1))
+	}
+	query.WriteString(") ON CONFLICT (")
+	for i, col := range t.primaryKey {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(quoteIdent(col))
+	}
+	query.WriteString(")")
+
+	updates := []string{}
+	for _, col := range t.columns {
+		if !slices.Contains(t.primaryKey, col) {
+			updates = append(updates, quoteIdent(col)+" = excluded."+quoteIdent(col))
+		}
+	}
+	if len(updates) == 0 {
+		query.WriteString(" DO NOTHING")
+	} else {
+		query.WriteString(" DO UPDATE SET " + strings.Join(updates, ","))
+	}
+	return query.String()
+}
+
+func (t *syncTable) getPrimaryKeyCondition(conn *Conn) string {
+	conds := make([]string, len(t.primaryKey))
+	for i, col := range t.primaryKey {
+		conds[i] = quoteIdent(col) + " = " + conn.placeholder(i+1)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// syncRow copies the row with the primary key from source to target, or
+// deletes it from target if it no longer exists in source.
+func syncRow(source, target *Conn, t *syncTable, pk string) error {
+	pkValues := []any{}
+	decoder := json.NewDecoder(strings.NewReader(pk))
+	decoder.UseNumber()
+	if err := decoder.Decode(&pkValues); err != nil {
+		return err
+	}
+	if len(pkValues) != len(t.primaryKey) {
+		return fmt.Errorf("invalid primary key: %s", pk)
+	}
+
+	sourceArgs := make([]any, len(pkValues))
+	targetArgs := make([]any, len(pkValues))
+	for i, col := range t.primaryKey {
+		idx := t.getColumnIndex(col)
+		v, err := convertValue(source.Dialect, t.sourceColumns[idx], pkValues[i])
+		if err != nil {
+			return err
+		}
+		sourceArgs[i] = v
+		if v, err = convertValue(target.Dialect, t.targetColumns[idx], pkValues[i]); err != nil {
+			return err
+		}
+		targetArgs[i] = v
+	}
+
+	quotedColumns := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		quotedColumns[i] = quoteIdent(col)
+	}
+	values := make([]any, len(t.columns))
+	ptrs := make([]any, len(t.columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	row := source.QueryRow("SELECT "+strings.Join(quotedColumns, ",")+" FROM "+quoteIdent(t.name)+" WHERE "+t.getPrimaryKeyCondition(source), sourceArgs...)
+	if err := row.Scan(ptrs...); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		_, err := target.Exec("DELETE FROM "+quoteIdent(t.name)+" WHERE "+t.getPrimaryKeyCondition(target), targetArgs...)
+		return err
+	}
+
+	for i, col := range t.targetColumns {
+		v, err := convertValue(target.Dialect, col, values[i])
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.name, col.Name, err)
+		}
+		values[i] = v
+	}
+	_, err := target.Exec(t.getUpsertQuery(target), values...)
+	return err
+}
+
+type SyncConfig struct {
+	BatchSize  int
+	OnProgress func(count int64)
+}
+
+type recordedChange struct {
+	table string
+	pk    string
+}
+
+func listRecordedChanges(source *Conn, limit int) ([]recordedChange, int64, error) {
+	rows, err := source.Query("SELECT id, table_name, pk FROM "+changeTableName+" ORDER BY id LIMIT ?", limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	changes := []recordedChange{}
+	seen := map[recordedChange]struct{}{}
+	lastId := int64(0)
+	for rows.Next() {
+		change := recordedChange{}
+		if err := rows.Scan(&lastId, &change.table, &change.pk); err != nil {
+			return nil, 0, err
+		}
+		if _, found := seen[change]; !found {
+			seen[change] = struct{}{}
+			changes = append(changes, change)
+		}
+	}
+	return changes, lastId, rows.Err()
+}
+
+// Sync applies the changes recorded on source since StartChangeCapture to
+// target, and returns the number of synced rows. The rows are read from
+// source at the time of sync, so repeated changes within a batch are synced
+// once.
+func Sync(source, target *Conn, conf *SyncConfig) (int64, error) {
+	if conf == nil {
+		conf = &SyncConfig{}
+	}
+	batchSize := conf.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	tables := map[string]*syncTable{}
+	count := int64(0)
+	for {
+		changes, lastId, err := listRecordedChanges(source, batchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(changes) == 0 {
+			break
+		}
+
+		for _, change := range changes {
+			t, found := tables[change.table]
+			if !found {
+				if t, err = getSyncTable(source, target, change.table); err != nil {
+					return count, err
+				}
+				tables[change.table] = t
+			}
+			if t == nil {
+				continue
+			}
+			if err := syncRow(source, target, t, change.pk); err != nil {
+				return count, errors.Join(fmt.Errorf("failed to sync table %s", change.table), err)
+			}
+			count++
+		}
+
+		if _, err := source.Exec("DELETE FROM "+changeTableName+" WHERE id <= ?", lastId); err != nil {
+			return count, err
+		}
+		if conf.OnProgress != nil {
+			conf.OnProgress(count)
+		}
+	}
+
+	for _, t := range tables {
+		if t == nil {
+			continue
+		}
+		if err := target.resetSequences(t.target); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,84 @@
+package db_migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSync(t *testing.T) {
+	source := openTestConn(t, "source")
+	target := openTestConn(t, "target")
+
+	_, err := source.Exec("INSERT INTO item (id, name, flag, meta, cat) VALUES (1, 'a', 1, '{\"k\":1}', 1), (2, 'b', 0, NULL, 2), (3, 'c', 0, NULL, 3)")
+	assert.NoError(t, err)
+
+	assert.NoError(t, StartChangeCapture(source, target))
+	// idempotent, for resuming
+	assert.NoError(t, StartChangeCapture(source, target))
+
+	_, err = Copy(source, target, nil)
+	assert.NoError(t, err)
+
+	for _, query := range []string{
+		"INSERT INTO item (id, name, cat) VALUES (4, 'd', 4)",
+		"UPDATE item SET name = 'aa', meta = '{\"k\": 2}' WHERE id = 1",
+		"UPDATE item SET name = 'aaa' WHERE id = 1",
+		"DELETE FROM item WHERE id = 2",
+		"UPDATE item SET id = 5 WHERE id = 3",
+	} {
+		_, err := source.Exec(query)
+		assert.NoError(t, err)
+	}
+
+	results, err := Verify(source, target, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.False(t, results[0].IsMatch())
+
+	count, err := Sync(source, target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	assert.Equal(t, []testItem{
+		{1, "aaa", true, `{"k": 2}`, 1},
+		{4, "d", false, "", 4},
+		{5, "c", false, "", 3},
+	}, listTestItems(t, target))
+
+	results, err = Verify(source, target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []VerifyResult{{
+		Table:          "item",
+		SourceCount:    3,
+		TargetCount:    3,
+		SourceChecksum: results[0].SourceChecksum,
+		TargetChecksum: results[0].SourceChecksum,
+	}}, results)
+
+	count, err = Sync(source, target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	results, err = VerifyCount(source, target, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []VerifyResult{{Table: "item", SourceCount: 3, TargetCount: 3}}, results)
+
+	assert.NoError(t, StopChangeCapture(source))
+	_, err = source.Exec("DELETE FROM item WHERE id = 1")
+	assert.NoError(t, err)
+	tables, err := source.ListTables()
+	assert.NoError(t, err)
+	assert.NotContains(t, tables, changeTableName)
+}
+
+func TestChecksumValue(t *testing.T) {
+	col := &Column{kind: kindJSON}
+	assert.Equal(t, checksumValue(col, `{"b": [1, 2], "a": 1}`), checksumValue(col, []byte(`{"a":1,"b":[1,2]}`)))
+
+	col = &Column{kind: kindFloat}
+	assert.Equal(t, checksumValue(col, 0.1), checksumValue(col, float64(float32(0.1))))
+
+	col = &Column{kind: kindTime}
+	assert.Equal(t, checksumValue(col, int64(1735787045)), checksumValue(col, "2025-01-02T03:04:05Z"))
+}
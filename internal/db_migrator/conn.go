@@ -0,0 +1,83 @@
+package db_migrator
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Conn is a database connection that is independent of the one opened by
+// the db package, so that it can have a different dialect.
+type Conn struct {
+	*sql.DB
+	URI     db.ConnectionURI
+	Dialect db.DBDialect
+	onClose func() error
+}
+
+func Open(connectionUri string) (*Conn, error) {
+	uri, err := db.ParseConnectionURI(connectionUri)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &Conn{URI: uri, Dialect: uri.Dialect}
+	switch uri.Dialect {
+	case db.DBDialectSQLite:
+		database, err := sql.Open(uri.DriverName, uri.DSN(func(u *url.URL, q *url.Values) {
+			u.Scheme = "file"
+		}))
+		if err != nil {
+			return nil, err
+		}
+		conn.DB = database
+		conn.onClose = database.Close
+	case db.DBDialectPostgres:
+		pool, err := pgxpool.New(context.Background(), uri.DSN())
+		if err != nil {
+			return nil, err
+		}
+		database := stdlib.OpenDBFromPool(pool)
+		conn.DB = database
+		conn.onClose = func() error {
+			err := database.Close()
+			pool.Close()
+			return err
+		}
+	}
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// FromDB wraps the connection opened by the db package.
+func FromDB(database *db.DB) *Conn {
+	return &Conn{DB: database.DB, URI: database.URI, Dialect: database.URI.Dialect}
+}
+
+func (c *Conn) Close() error {
+	if c.onClose == nil {
+		return nil
+	}
+	return c.onClose()
+}
+
+func (c *Conn) placeholder(i int) string {
+	if c.Dialect == db.DBDialectPostgres {
+		return "$" + strconv.Itoa(i)
+	}
+	return "?"
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
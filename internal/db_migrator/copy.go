@@ -0,0 +1,254 @@
+package db_migrator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+// keeps the number of bind parameters within the limits of both dialects
+const maxParamsPerStatement = 30000
+
+const defaultBatchSize = 1000
+
+// insertRows inserts the rows, ignoring the ones that already exist, and
+// returns the number of inserted rows.
+func (c *Conn) insertRows(table string, columns []string, rows [][]any) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	var query strings.Builder
+	query.WriteString("INSERT INTO " + quoteIdent(table) + " (")
+	for i, col := range columns {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(quoteIdent(col))
+	}
+	query.WriteString(") VALUES ")
+
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString("(")
+		for j := range row {
+			if j > 0 {
+				query.WriteString(",")
+			}
+			args = append(args, row[j])
+			query.WriteString(c.placeholder(len(args)))
+		}
+		query.WriteString(")")
+	}
+	query.WriteString(" ON CONFLICT DO NOTHING")
+
+	result, err := c.Exec(query.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// resetSequences moves the sequences of the serial columns past the copied
+// values.
+func (c *Conn) resetSequences(table *Table) error {
+	if c.Dialect != db.DBDialectPostgres {
+		return nil
+	}
+	for _, col := range table.Columns {
+		if !col.Serial {
+			continue
+		}
+		query := fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence($1, $2), coalesce(max(%s), 0) + 1, false) FROM %s",
+			quoteIdent(col.Name), quoteIdent(table.Name),
+		)
+		if _, err := c.Exec(query, quoteIdent(table.Name), col.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Conn) batchSize(columnCount, batchSize int) int {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return max(1, min(batchSize, maxParamsPerStatement/max(columnCount, 1)))
+}
+
+type CopyConfig struct {
+	// tables to copy, all tables present in both databases if empty
+	Tables     []string
+	BatchSize  int
+	OnProgress func(table string, count int64)
+}
+
+type CopyResult struct {
+	Table string
+	// number of rows read from source
+	Count int64
+	// number of rows inserted into target, existing rows are skipped
+	InsertedCount int64
+}
+
+func getCopyColumns(source, target *Table) []string {
+	columns := []string{}
+	for _, col := range target.Columns {
+		if source.getColumn(col.Name) != nil {
+			columns = append(columns, col.Name)
+		}
+	}
+	return columns
+}
+
+// listCommonTables returns the tables present in both databases, except the
+// ones managed by the schema migration.
+func listCommonTables(source, target *Conn) ([]string, error) {
+	sourceTables, err := source.ListTables()
+	if err != nil {
+		return nil, err
+	}
+	targetTables, err := target.ListTables()
+	if err != nil {
+		return nil, err
+	}
+	tables := []string{}
+	for _, name := range targetTables {
+		if name != migrationVersionTableName && slices.Contains(sourceTables, name) {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+func copyTable(source, target *Conn, name string, conf *CopyConfig) (*CopyResult, error) {
+	result := &CopyResult{Table: name}
+
+	sourceTable, err := source.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	targetTable, err := target.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	if sourceTable == nil || targetTable == nil {
+		return result, nil
+	}
+
+	columns := getCopyColumns(sourceTable, targetTable)
+	if len(columns) == 0 {
+		return result, nil
+	}
+	targetColumns := make([]*Column, len(columns))
+	for i, col := range columns {
+		targetColumns[i] = targetTable.getColumn(col)
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = quoteIdent(col)
+	}
+	rows, err := source.Query("SELECT " + strings.Join(quotedColumns, ",") + " FROM " + quoteIdent(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batchSize := target.batchSize(len(columns), conf.BatchSize)
+	batch := make([][]any, 0, batchSize)
+	flush := func() error {
+		count, err := target.insertRows(name, columns, batch)
+		if err != nil {
+			return err
+		}
+		result.InsertedCount += count
+		batch = batch[:0]
+		if conf.OnProgress != nil {
+			conf.OnProgress(name, result.Count)
+		}
+		return nil
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i, col := range targetColumns {
+			v, err := convertValue(target.Dialect, col, values[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, col.Name, err)
+			}
+			values[i] = v
+		}
+		batch = append(batch, values)
+		result.Count++
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if err := target.resetSequences(targetTable); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Copy copies the rows of the tables from source to target, converting the
+// values for the target dialect. Rows already present in target are kept
+// as it is. The target schema must be migrated beforehand.
+func Copy(source, target *Conn, conf *CopyConfig) ([]CopyResult, error) {
+	if conf == nil {
+		conf = &CopyConfig{}
+	}
+
+	sourceVersion, err := source.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	targetVersion, err := target.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if sourceVersion != targetVersion {
+		return nil, fmt.Errorf("schema version mismatch: source %d, target %d", sourceVersion, targetVersion)
+	}
+
+	tables := conf.Tables
+	if len(tables) == 0 {
+		tables, err = listCommonTables(source, target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]CopyResult, 0, len(tables))
+	for _, name := range tables {
+		result, err := copyTable(source, target, name, conf)
+		if err != nil {
+			return results, errors.Join(fmt.Errorf("failed to copy table %s", name), err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
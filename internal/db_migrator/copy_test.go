@@ -0,0 +1,110 @@
+package db_migrator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestConn(t *testing.T, name string) *Conn {
+	t.Helper()
+	conn, err := Open("sqlite://" + filepath.Join(t.TempDir(), name+".db"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	for _, query := range []string{
+		"CREATE TABLE db_migration_version (id integer PRIMARY KEY, version_id integer NOT NULL, is_applied integer NOT NULL, tstamp datetime)",
+		"INSERT INTO db_migration_version (version_id, is_applied) VALUES (1, 1)",
+		"CREATE TABLE item (id integer PRIMARY KEY, name varchar NOT NULL, flag bool NOT NULL DEFAULT false, meta json, cat datetime NOT NULL DEFAULT (unixepoch()))",
+	} {
+		_, err := conn.Exec(query)
+		assert.NoError(t, err)
+	}
+	return conn
+}
+
+type testItem struct {
+	id   int
+	name string
+	flag bool
+	meta string
+	cat  int64
+}
+
+func listTestItems(t *testing.T, conn *Conn) []testItem {
+	t.Helper()
+	rows, err := conn.Query("SELECT id, name, flag, coalesce(meta, ''), cast(cat AS integer) FROM item ORDER BY id")
+	assert.NoError(t, err)
+	defer rows.Close()
+	items := []testItem{}
+	for rows.Next() {
+		item := testItem{}
+		assert.NoError(t, rows.Scan(&item.id, &item.name, &item.flag, &item.meta, &item.cat))
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestCopy(t *testing.T) {
+	source := openTestConn(t, "source")
+	target := openTestConn(t, "target")
+
+	cat := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	_, err := source.Exec(
+		"INSERT INTO item (id, name, flag, meta, cat) VALUES (1, 'a', 1, '{\"k\":1}', ?), (2, 'b', 0, NULL, ?), (3, 'c', 1, '[]', ?)",
+		cat, cat+1, cat+2,
+	)
+	assert.NoError(t, err)
+	_, err = target.Exec("INSERT INTO item (id, name) VALUES (3, 'existing')")
+	assert.NoError(t, err)
+
+	results, err := Copy(source, target, &CopyConfig{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []CopyResult{{Table: "item", Count: 3, InsertedCount: 2}}, results)
+
+	items := listTestItems(t, target)
+	assert.Len(t, items, 3)
+	assert.Equal(t, testItem{1, "a", true, `{"k":1}`, cat}, items[0])
+	assert.Equal(t, testItem{2, "b", false, "", cat + 1}, items[1])
+	assert.Equal(t, "existing", items[2].name)
+
+	_, err = target.Exec("UPDATE db_migration_version SET version_id = 2")
+	assert.NoError(t, err)
+	_, err = Copy(source, target, nil)
+	assert.ErrorContains(t, err, "schema version mismatch")
+}
+
+func TestDumpRestore(t *testing.T) {
+	source := openTestConn(t, "source")
+	target := openTestConn(t, "target")
+
+	cat := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	_, err := source.Exec("INSERT INTO item (id, name, flag, meta, cat) VALUES (1, 'a', 1, '{\"k\":[1,2]}', ?)", cat)
+	assert.NoError(t, err)
+
+	dump, err := source.Dump("item")
+	assert.NoError(t, err)
+
+	blob, err := json.Marshal(dump)
+	assert.NoError(t, err)
+	assert.Contains(t, string(blob), `"2025-01-02T03:04:05Z"`)
+
+	var decoded TableDump
+	assert.NoError(t, json.Unmarshal(blob, &decoded))
+
+	count, err := target.Restore(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, []testItem{{1, "a", true, `{"k":[1,2]}`, cat}}, listTestItems(t, target))
+
+	count, err = target.Restore(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	dump, err = source.Dump("missing")
+	assert.NoError(t, err)
+	assert.Nil(t, dump)
+}
@@ -0,0 +1,117 @@
+package db_migrator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableDump holds the rows of a table in a dialect independent form, so
+// that it can be restored into a database of any dialect.
+type TableDump struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// Dump reads all the rows of the table, nil if the table does not exist.
+func (c *Conn) Dump(name string) (*TableDump, error) {
+	table, err := c.GetTable(name)
+	if err != nil || table == nil {
+		return nil, err
+	}
+
+	dump := &TableDump{Name: name, Columns: make([]string, len(table.Columns)), Rows: [][]any{}}
+	quotedColumns := make([]string, len(table.Columns))
+	for i := range table.Columns {
+		dump.Columns[i] = table.Columns[i].Name
+		quotedColumns[i] = quoteIdent(table.Columns[i].Name)
+	}
+
+	rows, err := c.Query("SELECT " + strings.Join(quotedColumns, ",") + " FROM " + quoteIdent(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make([]any, len(table.Columns))
+		ptrs := make([]any, len(table.Columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i] = toPortableValue(&table.Columns[i], values[i])
+		}
+		dump.Rows = append(dump.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+// Restore inserts the rows of the dump, skipping the ones that already
+// exist, and returns the number of inserted rows. Columns missing in the
+// table are ignored.
+func (c *Conn) Restore(dump *TableDump) (int64, error) {
+	table, err := c.GetTable(dump.Name)
+	if err != nil {
+		return 0, err
+	}
+	if table == nil {
+		return 0, fmt.Errorf("table not found: %s", dump.Name)
+	}
+
+	columns := []string{}
+	indices := []int{}
+	targetColumns := []*Column{}
+	for i, name := range dump.Columns {
+		if col := table.getColumn(name); col != nil {
+			columns = append(columns, name)
+			indices = append(indices, i)
+			targetColumns = append(targetColumns, col)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	batchSize := c.batchSize(len(columns), 0)
+	insertedCount := int64(0)
+	batch := make([][]any, 0, batchSize)
+	for _, row := range dump.Rows {
+		if len(row) != len(dump.Columns) {
+			return insertedCount, fmt.Errorf("%s: invalid row, expected %d values, got %d", dump.Name, len(dump.Columns), len(row))
+		}
+		values := make([]any, len(columns))
+		for i, idx := range indices {
+			v, err := convertValue(c.Dialect, targetColumns[i], row[idx])
+			if err != nil {
+				return insertedCount, fmt.Errorf("%s.%s: %w", dump.Name, columns[i], err)
+			}
+			values[i] = v
+		}
+		batch = append(batch, values)
+		if len(batch) == batchSize {
+			count, err := c.insertRows(dump.Name, columns, batch)
+			if err != nil {
+				return insertedCount, err
+			}
+			insertedCount += count
+			batch = batch[:0]
+		}
+	}
+	count, err := c.insertRows(dump.Name, columns, batch)
+	if err != nil {
+		return insertedCount, err
+	}
+	insertedCount += count
+
+	if err := c.resetSequences(table); err != nil {
+		return insertedCount, err
+	}
+	return insertedCount, nil
+}
@@ -0,0 +1,85 @@
+package db_migrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+)
+
+var log = logger.Scoped("db_migrator")
+
+// FinishSwitch finishes the pending database switch, on the start of the
+// instance on the source database, before it starts writing. It syncs the
+// changes made to the source since the copy, and checks the row counts, the
+// data is verified thoroughly by `stremthru db switch` already. If the
+// switch can not be finished, it keeps using the source database.
+//
+// It returns db.ErrDatabaseSwitched once the switch is finished, the
+// instance must be restarted to use the target database.
+func FinishSwitch(database *db.DB) error {
+	s := db.GetDatabaseSwitch()
+	if s == nil {
+		return nil
+	}
+	if err := db.CheckDatabaseSwitch(); err != nil {
+		return err
+	}
+	if db.GetDatabaseURI() == s.To {
+		if config.DatabaseURI == s.To {
+			// STREMTHRU_DATABASE_URI is updated, the switch is not needed anymore
+			return db.RemoveDatabaseSwitch()
+		}
+		return nil
+	}
+
+	if err := finishSwitch(FromDB(database), s); err != nil {
+		log.Error("failed to finish database switch, using the source database, it is retried on next start", "error", err)
+		return nil
+	}
+	now := time.Now()
+	s.SwitchedAt = &now
+	if err := s.Save(); err != nil {
+		return err
+	}
+	if err := StopChangeCapture(FromDB(database)); err != nil {
+		log.Warn("failed to stop change capture on the source database", "error", err)
+	}
+	log.Info("switched database")
+	return db.ErrDatabaseSwitched
+}
+
+func finishSwitch(source *Conn, s *db.DatabaseSwitch) error {
+	target, err := Open(s.To)
+	if err != nil {
+		return errors.Join(errors.New("failed to open target database"), err)
+	}
+	defer target.Close()
+
+	log.Info("syncing changes to target database")
+	count, err := Sync(source, target, nil)
+	if err != nil {
+		return err
+	}
+	log.Info("synced changes to target database", "count", count)
+
+	results, err := VerifyCount(source, target, nil)
+	if err != nil {
+		return err
+	}
+	mismatched := []string{}
+	for i := range results {
+		if r := &results[i]; !r.IsMatch() {
+			log.Error("row count mismatch", "table", r.Table, "source_count", r.SourceCount, "target_count", r.TargetCount)
+			mismatched = append(mismatched, r.Table)
+		}
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("row count mismatch for tables: %s", strings.Join(mismatched, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package db_migrator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+// goose's version table, it is managed by the schema migration
+const migrationVersionTableName = "db_migration_version"
+
+type columnKind int
+
+const (
+	kindOther columnKind = iota
+	kindText
+	kindInt
+	kindFloat
+	kindBool
+	kindTime
+	kindDate
+	kindJSON
+	kindBlob
+)
+
+func toColumnKind(dialect db.DBDialect, typ string) columnKind {
+	typ = strings.ToLower(typ)
+	if dialect == db.DBDialectSQLite {
+		// declared type, e.g. `varchar(255)`
+		typ, _, _ = strings.Cut(typ, "(")
+	}
+	switch strings.TrimSpace(typ) {
+	case "text", "varchar", "char", "character", "character varying":
+		return kindText
+	case "int", "integer", "bigint", "smallint":
+		return kindInt
+	case "real", "double", "double precision", "float", "numeric":
+		return kindFloat
+	case "bool", "boolean":
+		return kindBool
+	case "datetime", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone":
+		return kindTime
+	case "date":
+		return kindDate
+	case "json", "jsonb":
+		return kindJSON
+	case "blob", "bytea":
+		return kindBlob
+	default:
+		return kindOther
+	}
+}
+
+type Column struct {
+	Name string
+	Type string
+	// value is generated from a sequence
+	Serial bool
+	// position in the primary key, 0 if not part of it
+	PrimaryKey int
+
+	kind columnKind
+}
+
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+func (t *Table) getColumn(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+func (t *Table) getPrimaryKey() []string {
+	columns := []Column{}
+	for _, col := range t.Columns {
+		if col.PrimaryKey > 0 {
+			columns = append(columns, col)
+		}
+	}
+	slices.SortFunc(columns, func(a, b Column) int {
+		return a.PrimaryKey - b.PrimaryKey
+	})
+	names := make([]string, len(columns))
+	for i := range columns {
+		names[i] = columns[i].Name
+	}
+	return names
+}
+
+// ListTables returns the names of the tables, sorted.
+func (c *Conn) ListTables() ([]string, error) {
+	query := "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'"
+	if c.Dialect == db.DBDialectSQLite {
+		// skips virtual tables, and their shadow tables
+		query = "SELECT t.name FROM sqlite_master t WHERE t.type = 'table' AND t.name NOT LIKE 'sqlite_%' AND t.sql NOT LIKE 'CREATE VIRTUAL TABLE%' AND NOT EXISTS (SELECT 1 FROM sqlite_master v WHERE v.type = 'table' AND v.sql LIKE 'CREATE VIRTUAL TABLE%' AND t.name LIKE v.name || '\\_%' ESCAPE '\\')"
+	}
+	rows, err := c.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// GetTable returns the table with its columns, or nil if it does not exist.
+func (c *Conn) GetTable(name string) (*Table, error) {
+	query := "SELECT c.column_name, c.data_type, (coalesce(c.column_default, '') LIKE 'nextval(%' OR c.is_identity = 'YES'), coalesce((SELECT k.ordinal_position FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage k ON k.constraint_schema = tc.constraint_schema AND k.constraint_name = tc.constraint_name WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name AND k.column_name = c.column_name), 0) FROM information_schema.columns c WHERE c.table_schema = current_schema() AND c.table_name = $1 ORDER BY c.ordinal_position"
+	if c.Dialect == db.DBDialectSQLite {
+		query = "SELECT name, type, 0, pk FROM pragma_table_info(?) ORDER BY cid"
+	}
+	rows, err := c.Query(query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	table := &Table{Name: name}
+	for rows.Next() {
+		col := Column{}
+		if err := rows.Scan(&col.Name, &col.Type, &col.Serial, &col.PrimaryKey); err != nil {
+			return nil, err
+		}
+		col.kind = toColumnKind(c.Dialect, col.Type)
+		table.Columns = append(table.Columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return nil, nil
+	}
+	return table, nil
+}
+
+// GetSchemaVersion returns the latest applied schema migration version.
+func (c *Conn) GetSchemaVersion() (int64, error) {
+	var version int64
+	row := c.QueryRow("SELECT coalesce(max(version_id), 0) FROM " + migrationVersionTableName + " WHERE is_applied")
+	if err := row.Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
@@ -0,0 +1,225 @@
+package db_migrator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.DateTime,
+	time.DateOnly,
+}
+
+func parseTime(value string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", value)
+}
+
+func toInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		return int64(f), err
+	case time.Time:
+		return v.Unix(), nil
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case []byte:
+		return strconv.ParseInt(strings.TrimSpace(string(v)), 10, 64)
+	}
+	return 0, fmt.Errorf("invalid int: %T", value)
+}
+
+func toFloat64(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	case []byte:
+		return strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+	}
+	return 0, fmt.Errorf("invalid float: %T", value)
+}
+
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(v))
+	case []byte:
+		return strconv.ParseBool(strings.TrimSpace(string(v)))
+	default:
+		i, err := toInt64(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid bool: %T", value)
+		}
+		return i != 0, nil
+	}
+}
+
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		return parseTime(v)
+	case []byte:
+		return parseTime(string(v))
+	default:
+		unix, err := toInt64(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time: %T", value)
+		}
+		return time.Unix(unix, 0).UTC(), nil
+	}
+}
+
+func toJSON(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case json.RawMessage:
+		return string(v), nil
+	default:
+		blob, err := json.Marshal(v)
+		return string(blob), err
+	}
+}
+
+func toText(value any) string {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case time.Time:
+		text = v.UTC().Format(time.RFC3339)
+	case json.Number:
+		text = v.String()
+	default:
+		text = fmt.Sprint(v)
+	}
+	// postgres does not accept NUL in text
+	return strings.ReplaceAll(text, "\x00", "")
+}
+
+// convertValue converts the value read from any dialect, or decoded from
+// JSON, for the column of the dialect.
+func convertValue(dialect db.DBDialect, col *Column, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch col.kind {
+	case kindText:
+		return toText(value), nil
+	case kindInt:
+		return toInt64(value)
+	case kindFloat:
+		return toFloat64(value)
+	case kindBool:
+		v, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if dialect == db.DBDialectSQLite {
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+		return v, nil
+	case kindTime:
+		t, err := toTime(value)
+		if err != nil {
+			return nil, err
+		}
+		if dialect == db.DBDialectSQLite {
+			return t.Unix(), nil
+		}
+		return t, nil
+	case kindDate:
+		if v, ok := value.(time.Time); ok {
+			return v.Format(time.DateOnly), nil
+		}
+		return toText(value), nil
+	case kindJSON:
+		return toJSON(value)
+	case kindBlob:
+		if v, ok := value.(string); ok {
+			// exported as base64
+			if blob, err := base64.StdEncoding.DecodeString(v); err == nil {
+				return blob, nil
+			}
+			return []byte(v), nil
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// toPortableValue converts the value read from the column, so that it
+// survives a round trip through JSON.
+func toPortableValue(col *Column, value any) any {
+	if value == nil {
+		return nil
+	}
+
+	switch col.kind {
+	case kindTime:
+		if t, err := toTime(value); err == nil {
+			return t.Format(time.RFC3339Nano)
+		}
+	case kindBool:
+		if v, err := toBool(value); err == nil {
+			return v
+		}
+	case kindJSON:
+		if v, err := toJSON(value); err == nil && json.Valid([]byte(v)) {
+			return json.RawMessage(v)
+		}
+	case kindText, kindDate:
+		if v, ok := value.([]byte); ok {
+			return string(v)
+		}
+	}
+	return value
+}
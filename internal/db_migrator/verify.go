@@ -0,0 +1,200 @@
+package db_migrator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// checksumValue returns the value in a form that is same for both dialects,
+// the value must be converted for the column beforehand.
+func checksumValue(col *Column, value any) []byte {
+	switch col.kind {
+	case kindInt:
+		if v, err := toInt64(value); err == nil {
+			return strconv.AppendInt(nil, v, 10)
+		}
+	case kindFloat:
+		// postgres `real` has single precision
+		if v, err := toFloat64(value); err == nil {
+			return strconv.AppendFloat(nil, float64(float32(v)), 'g', -1, 32)
+		}
+	case kindBool:
+		if v, err := toBool(value); err == nil {
+			return strconv.AppendBool(nil, v)
+		}
+	case kindTime:
+		if v, err := toTime(value); err == nil {
+			return strconv.AppendInt(nil, v.Unix(), 10)
+		}
+	case kindJSON:
+		// postgres `jsonb` does not keep the formatting and the key order
+		if v, err := toJSON(value); err == nil {
+			var data any
+			decoder := json.NewDecoder(strings.NewReader(v))
+			decoder.UseNumber()
+			if err := decoder.Decode(&data); err == nil {
+				if blob, err := json.Marshal(data); err == nil {
+					return blob
+				}
+			}
+			return []byte(v)
+		}
+	case kindBlob:
+		if v, ok := value.([]byte); ok {
+			return v
+		}
+	}
+	return []byte(toText(value))
+}
+
+// checksumTable returns the row count and the checksum of the columns of
+// the table. The checksum does not depend on the order of the rows.
+func (c *Conn) checksumTable(name string, columns []string, targetColumns []*Column, target *Conn) (int64, uint64, error) {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = quoteIdent(col)
+	}
+	rows, err := c.Query("SELECT " + strings.Join(quotedColumns, ",") + " FROM " + quoteIdent(name))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	count, checksum := int64(0), uint64(0)
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	hash := fnv.New64a()
+	var buf bytes.Buffer
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return 0, 0, err
+		}
+		buf.Reset()
+		for i, col := range targetColumns {
+			v, err := convertValue(target.Dialect, col, values[i])
+			if err != nil {
+				return 0, 0, fmt.Errorf("%s.%s: %w", name, col.Name, err)
+			}
+			if v == nil {
+				buf.WriteByte(0)
+				continue
+			}
+			value := checksumValue(col, v)
+			buf.WriteByte(1)
+			buf.Write(binary.AppendUvarint(nil, uint64(len(value))))
+			buf.Write(value)
+		}
+		hash.Reset()
+		hash.Write(buf.Bytes())
+		checksum += hash.Sum64()
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	return count, checksum, nil
+}
+
+type VerifyResult struct {
+	Table          string
+	SourceCount    int64
+	TargetCount    int64
+	SourceChecksum uint64
+	TargetChecksum uint64
+}
+
+func (r *VerifyResult) IsMatch() bool {
+	return r.SourceCount == r.TargetCount && r.SourceChecksum == r.TargetChecksum
+}
+
+func verifyTable(source, target *Conn, name string) (*VerifyResult, error) {
+	result := &VerifyResult{Table: name}
+
+	sourceTable, err := source.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	targetTable, err := target.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	if sourceTable == nil || targetTable == nil {
+		return nil, fmt.Errorf("table not found: %s", name)
+	}
+
+	columns := getCopyColumns(sourceTable, targetTable)
+	targetColumns := make([]*Column, len(columns))
+	for i, col := range columns {
+		targetColumns[i] = targetTable.getColumn(col)
+	}
+
+	if result.SourceCount, result.SourceChecksum, err = source.checksumTable(name, columns, targetColumns, target); err != nil {
+		return nil, err
+	}
+	if result.TargetCount, result.TargetChecksum, err = target.checksumTable(name, columns, targetColumns, target); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Verify compares the row count and the checksum of the tables in source
+// and target, all tables present in both databases if tables is empty. The
+// values are compared after converting them for the target dialect.
+func Verify(source, target *Conn, tables []string) ([]VerifyResult, error) {
+	if len(tables) == 0 {
+		var err error
+		if tables, err = listCommonTables(source, target); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]VerifyResult, 0, len(tables))
+	for _, name := range tables {
+		result, err := verifyTable(source, target, name)
+		if err != nil {
+			return results, errors.Join(fmt.Errorf("failed to verify table %s", name), err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func (c *Conn) countRows(name string) (int64, error) {
+	var count int64
+	err := c.QueryRow("SELECT COUNT(*) FROM " + quoteIdent(name)).Scan(&count)
+	return count, err
+}
+
+// VerifyCount compares only the row count of the tables in source and
+// target, all tables present in both databases if tables is empty.
+func VerifyCount(source, target *Conn, tables []string) ([]VerifyResult, error) {
+	if len(tables) == 0 {
+		var err error
+		if tables, err = listCommonTables(source, target); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]VerifyResult, 0, len(tables))
+	for _, name := range tables {
+		result := VerifyResult{Table: name}
+		var err error
+		if result.SourceCount, err = source.countRows(name); err != nil {
+			return results, errors.Join(fmt.Errorf("failed to count rows of table %s", name), err)
+		}
+		if result.TargetCount, err = target.countRows(name); err != nil {
+			return results, errors.Join(fmt.Errorf("failed to count rows of table %s", name), err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
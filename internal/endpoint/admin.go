@@ -0,0 +1,28 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+)
+
+func handleAdminCacheClear(w http.ResponseWriter, r *http.Request) {
+	if err := cache.Clear(r.PathValue("name")); err != nil {
+		if errors.Is(err, cache.ErrInvalidName) {
+			shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+			return
+		}
+		SendError(w, r, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func AddAdminEndpoints(mux *http.ServeMux) {
+	withAdminAuth := server.Middleware(server.AdminAuthed)
+
+	mux.HandleFunc("POST /v0/admin/cache/{name}/clear", withAdminAuth(handleAdminCacheClear))
+}
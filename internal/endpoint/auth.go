@@ -42,6 +42,68 @@ var ExecuteAuthCallbackTemplate = func() func(data *AuthCallbackTemplateData) (b
 	}
 }()
 
+func handleAniListAuthCallback(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+
+	td := &AuthCallbackTemplateData{
+		Title:    "StremThru",
+		Version:  config.Version,
+		Provider: "AniList",
+		State:    state,
+	}
+
+	tok, err := oauth.AniListOAuthConfig.Exchange(code, state)
+	if err != nil {
+		td.Error = err.Error()
+	} else {
+		td.Code = tok.Extra("id").(string)
+	}
+
+	buf, err := ExecuteAuthCallbackTemplate(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, buf)
+}
+
+func handleMALAuthCallback(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+
+	td := &AuthCallbackTemplateData{
+		Title:    "StremThru",
+		Version:  config.Version,
+		Provider: "MyAnimeList",
+		State:    state,
+	}
+
+	tok, err := oauth.MALOAuthConfig.Exchange(code, state)
+	if err != nil {
+		td.Error = err.Error()
+	} else {
+		td.Code = tok.Extra("id").(string)
+	}
+
+	buf, err := ExecuteAuthCallbackTemplate(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, buf)
+}
+
 func handleTraktAuthCallback(w http.ResponseWriter, r *http.Request) {
 	if !shared.IsMethod(r, http.MethodGet) {
 		shared.ErrorMethodNotAllowed(r).Send(w, r)
@@ -73,6 +135,68 @@ func handleTraktAuthCallback(w http.ResponseWriter, r *http.Request) {
 	SendHTML(w, 200, buf)
 }
 
+func handleSimklAuthCallback(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+
+	td := &AuthCallbackTemplateData{
+		Title:    "StremThru",
+		Version:  config.Version,
+		Provider: "Simkl",
+		State:    state,
+	}
+
+	tok, err := oauth.SimklOAuthConfig.Exchange(code, state)
+	if err != nil {
+		td.Error = err.Error()
+	} else {
+		td.Code = tok.Extra("id").(string)
+	}
+
+	buf, err := ExecuteAuthCallbackTemplate(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, buf)
+}
+
+func handleLetterboxdAuthCallback(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+
+	td := &AuthCallbackTemplateData{
+		Title:    "StremThru",
+		Version:  config.Version,
+		Provider: "Letterboxd",
+		State:    state,
+	}
+
+	tok, err := oauth.LetterboxdOAuthConfig.Exchange(code, state)
+	if err != nil {
+		td.Error = err.Error()
+	} else {
+		td.Code = tok.Extra("id").(string)
+	}
+
+	buf, err := ExecuteAuthCallbackTemplate(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, buf)
+}
+
 func handleTMDBAuthInit(w http.ResponseWriter, r *http.Request) {
 	if !shared.IsMethod(r, http.MethodGet) {
 		shared.ErrorMethodNotAllowed(r).Send(w, r)
@@ -186,6 +310,18 @@ func handleTVDBAuthToken(w http.ResponseWriter, r *http.Request) {
 }
 
 func AddAuthEndpoints(mux *http.ServeMux) {
+	if config.Integration.AniList.IsEnabled() {
+		mux.HandleFunc("/auth/anilist.co/callback", handleAniListAuthCallback)
+	}
+	if config.Integration.Letterboxd.IsEnabled() {
+		mux.HandleFunc("/auth/letterboxd.com/callback", handleLetterboxdAuthCallback)
+	}
+	if config.Integration.MAL.IsEnabled() {
+		mux.HandleFunc("/auth/myanimelist.net/callback", handleMALAuthCallback)
+	}
+	if config.Integration.Simkl.IsEnabled() {
+		mux.HandleFunc("/auth/simkl.com/callback", handleSimklAuthCallback)
+	}
 	if config.Integration.Trakt.IsEnabled() {
 		mux.HandleFunc("/auth/trakt.tv/callback", handleTraktAuthCallback)
 	}
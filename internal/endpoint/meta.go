@@ -6,6 +6,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	meta_id_map "github.com/MunifTanjim/stremthru/internal/meta/id_map"
 	meta_letterboxd "github.com/MunifTanjim/stremthru/internal/meta/letterboxd"
+	meta_stremthru "github.com/MunifTanjim/stremthru/internal/meta/stremthru"
 )
 
 func AddMetaEndpoints(mux *http.ServeMux) {
@@ -15,4 +16,5 @@ func AddMetaEndpoints(mux *http.ServeMux) {
 
 	meta_id_map.AddEndpoints(mux)
 	meta_letterboxd.AddEndpoints(mux)
+	meta_stremthru.AddEndpoints(mux)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/newznab"
 	newznab_indexer "github.com/MunifTanjim/stremthru/internal/newznab/indexer"
 	"github.com/MunifTanjim/stremthru/internal/server"
+	usenet_indexer "github.com/MunifTanjim/stremthru/internal/usenet/indexer"
 	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/internal/znab"
@@ -245,6 +246,14 @@ func handleNewznabGetNZB(w http.ResponseWriter, r *http.Request) {
 
 	hash := util.HashNZBFileLink(config.BaseURL.JoinPath("/v0/newznab/getnzb", nzbId).String())
 	file := nzb_info.GetCachedNZBFile(hash)
+	if file == nil && len(config.Newz.ScanGroups) > 0 {
+		var err error
+		file, err = usenet_indexer.GetNZBFile(nzbId)
+		if err != nil {
+			server.SendError(w, r, err)
+			return
+		}
+	}
 	if file == nil {
 		server.ErrorNotFound(r).Send(w, r)
 		return
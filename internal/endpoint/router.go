@@ -7,6 +7,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/sabnzbd"
 	"github.com/MunifTanjim/stremthru/internal/torz"
 	usenet_webdav "github.com/MunifTanjim/stremthru/internal/usenet/webdav"
+	"github.com/MunifTanjim/stremthru/internal/zilean"
 )
 
 func AddEndpoints(mux *http.ServeMux) {
@@ -14,6 +15,7 @@ func AddEndpoints(mux *http.ServeMux) {
 	torz.AddEndpoints(mux)
 
 	sabnzbd.AddEndpoints(mux)
+	zilean.AddEndpoints(mux)
 
 	usenet_webdav.AddEndpoints(mux)
 }
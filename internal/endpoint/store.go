@@ -63,7 +63,7 @@ func checkMagnet(r *http.Request, ctx *storecontext.Context, magnets []string, s
 	if ctx.ClientIP != "" {
 		params.ClientIP = ctx.ClientIP
 	}
-	params.IsTrustedRequest, _ = peer_token.IsValid(peer_token.ExtractFromRequest(r))
+	params.IsTrustedRequest = peer_token.IsTrustedRequest(r, peer_token.ScopeCheckMagnetCache)
 	data, err := ctx.Store.CheckMagnet(params)
 	if err != nil {
 		return nil, err
@@ -97,16 +97,8 @@ func hadleStoreMagnetsTrack(w http.ResponseWriter, r *http.Request) {
 
 	ctx := storecontext.Get(r)
 
-	log := server.GetReqCtx(r).Log
-
-	isValidToken, err := peer_token.IsValid(peer_token.ExtractFromRequest(r))
-	if err != nil {
-		log.Error("failed to validate peer token", "error", err)
-		SendError(w, r, err)
-		return
-	}
-	if !isValidToken {
-		shared.ErrorUnauthorized(r).Send(w, r)
+	if err := peer_token.AuthorizeRequest(r, peer_token.ExtractFromRequest(r), peer_token.ScopeCheckMagnetCache); err != nil {
+		err.Send(w, r)
 		return
 	}
 
@@ -132,6 +124,11 @@ func handleStoreMagnetsCheck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := peer_token.AuthorizeOptionalRequest(r, peer_token.ScopeCheckMagnetCache); err != nil {
+		err.Send(w, r)
+		return
+	}
+
 	queryParams := r.URL.Query()
 	magnet, ok := queryParams["magnet"]
 	if !ok {
@@ -6,6 +6,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/stremio/disabled"
 	stremio_list "github.com/MunifTanjim/stremthru/internal/stremio/list"
+	stremio_meta "github.com/MunifTanjim/stremthru/internal/stremio/meta"
 	stremio_newz "github.com/MunifTanjim/stremthru/internal/stremio/newz"
 	"github.com/MunifTanjim/stremthru/internal/stremio/root"
 	"github.com/MunifTanjim/stremthru/internal/stremio/sidekick"
@@ -18,6 +19,9 @@ func AddStremioEndpoints(mux *http.ServeMux) {
 	if config.Feature.HasStremioList() {
 		stremio_list.AddEndpoints(mux)
 	}
+	if config.Feature.HasStremioMeta() {
+		stremio_meta.AddEndpoints(mux)
+	}
 	if config.Feature.HasStremioStore() {
 		stremio_store.AddStremioStoreEndpoints(mux)
 	}
@@ -1,11 +1,13 @@
 package endpoint
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/buddy"
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/config"
@@ -13,6 +15,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/server"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/klauspost/compress/zstd"
 )
 
 type RecordTorrentsPayload struct {
@@ -20,14 +23,8 @@ type RecordTorrentsPayload struct {
 }
 
 func handleRecordTorrents(w http.ResponseWriter, r *http.Request) {
-	peerToken := r.Header.Get("X-StremThru-Peer-Token")
-	isValidToken, err := peer_token.IsValid(peerToken)
-	if err != nil {
-		SendError(w, r, err)
-		return
-	}
-	if !isValidToken {
-		shared.ErrorUnauthorized(r).Send(w, r)
+	if err := peer_token.AuthorizeRequest(r, peer_token.ExtractFromRequest(r), peer_token.ScopePushTorrents); err != nil {
+		err.Send(w, r)
 		return
 	}
 
@@ -47,6 +44,11 @@ func handleListTorrents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := peer_token.AuthorizeOptionalRequest(r, peer_token.ScopeReadTorrents); err != nil {
+		err.Send(w, r)
+		return
+	}
+
 	query := r.URL.Query()
 	sid := query.Get("sid")
 	if sid == "" {
@@ -73,6 +75,66 @@ func handleListTorrents(w http.ResponseWriter, r *http.Request) {
 	SendResponse(w, r, 200, data, err)
 }
 
+const maxTorrentChangesLimit = 10000
+
+func handleTorrentChanges(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	if server.IsMaintenanceActive() {
+		server.ErrorServiceUnavailable(r).Send(w, r)
+		return
+	}
+
+	if err := peer_token.AuthorizeRequest(r, peer_token.ExtractFromRequest(r), peer_token.ScopeReadTorrents); err != nil {
+		err.Send(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	cursor, err := torrent_info.ParseChangesCursor(query.Get("since"))
+	if err != nil {
+		shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+		return
+	}
+	limit := 1000
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxTorrentChangesLimit {
+			shared.ErrorBadRequest(r, "invalid limit, expected 1-"+strconv.Itoa(maxTorrentChangesLimit)).Send(w, r)
+			return
+		}
+	}
+
+	changes, err := torrent_info.ListChanges(cursor, limit)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Vary", "Accept-Encoding")
+
+	var out io.Writer = w
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "zstd") {
+		w.Header().Set("Content-Encoding", "zstd")
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+		defer enc.Close()
+		out = enc
+	}
+
+	w.WriteHeader(200)
+	if err := changes.WriteNDJSON(out); err != nil {
+		core.LogError(r, "failed to write torrent changes", err)
+	}
+}
+
 func handleTorrents(w http.ResponseWriter, r *http.Request) {
 	if shared.IsMethod(r, http.MethodPost) {
 		handleRecordTorrents(w, r)
@@ -118,4 +180,5 @@ func AddTorrentEndpoints(mux *http.ServeMux) {
 
 	mux.HandleFunc("/v0/torrents", handleTorrents)
 	mux.HandleFunc("/v0/torrents/stats", handleTorrentStats)
+	mux.HandleFunc("/v0/torrents/changes", handleTorrentChanges)
 }
@@ -824,3 +824,109 @@ func GetIdMapsByTraktIds(titleType IMDBTitleSimpleType, traktIds []string) (map[
 	}
 	return idMapById, nil
 }
+
+var query_get_id_maps_by_column_before_cond = fmt.Sprintf(
+	`SELECT %s, coalesce(it.%s, '') AS item_type FROM %s itm LEFT JOIN %s it ON itm.%s = it.%s WHERE `,
+	db.JoinPrefixedColumnNames(
+		"itm.",
+		MapColumn.IMDBId,
+		MapColumn.TMDBId,
+		MapColumn.TVDBId,
+		MapColumn.TraktId,
+		MapColumn.LetterboxdId,
+		MapColumn.MALId,
+	),
+	Column.Type,
+	MapTableName,
+	TableName,
+	MapColumn.IMDBId,
+	Column.TId,
+)
+
+var query_get_id_maps_by_column_cond_movie = fmt.Sprintf(
+	`it.%s IN (%s) AND `,
+	Column.Type,
+	db.ToValues(movieTypes, "'%s'"),
+)
+
+var query_get_id_maps_by_column_cond_show = fmt.Sprintf(
+	`it.%s IN (%s) AND `,
+	Column.Type,
+	db.ToValues(showTypes, "'%s'"),
+)
+
+// getIdMapsByColumn returns the id maps keyed by the value of the column.
+// The ids of tmdb and tvdb are only unique within the title type.
+func getIdMapsByColumn(column string, titleType IMDBTitleSimpleType, ids []string) (map[string]IMDBTitleMap, error) {
+	count := len(ids)
+	if count == 0 {
+		return nil, nil
+	}
+
+	var query strings.Builder
+	query.WriteString(query_get_id_maps_by_column_before_cond)
+	switch titleType {
+	case IMDBTitleSimpleTypeMovie:
+		query.WriteString(query_get_id_maps_by_column_cond_movie)
+	case IMDBTitleSimpleTypeShow:
+		query.WriteString(query_get_id_maps_by_column_cond_show)
+	}
+	query.WriteString("itm." + column + " IN (" + util.RepeatJoin("?", count, ",") + ")")
+
+	args := make([]any, count)
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := db.Query(query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	idMapById := make(map[string]IMDBTitleMap, count)
+	for rows.Next() {
+		idMap := IMDBTitleMap{}
+		if err := rows.Scan(
+			&idMap.IMDBId,
+			&idMap.TMDBId,
+			&idMap.TVDBId,
+			&idMap.TraktId,
+			&idMap.LetterboxdId,
+			&idMap.MALId,
+			&idMap.Type,
+		); err != nil {
+			return nil, err
+		}
+
+		switch column {
+		case MapColumn.TMDBId:
+			idMapById[idMap.TMDBId] = idMap
+		case MapColumn.TVDBId:
+			idMapById[idMap.TVDBId] = idMap
+		case MapColumn.MALId:
+			idMapById[idMap.MALId] = idMap
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return idMapById, nil
+}
+
+func GetIdMapsByTMDBIds(titleType IMDBTitleSimpleType, tmdbIds []string) (map[string]IMDBTitleMap, error) {
+	if titleType != IMDBTitleSimpleTypeMovie && titleType != IMDBTitleSimpleTypeShow {
+		return nil, ErrUnexpectedTitleType
+	}
+	return getIdMapsByColumn(MapColumn.TMDBId, titleType, tmdbIds)
+}
+
+func GetIdMapsByTVDBIds(titleType IMDBTitleSimpleType, tvdbIds []string) (map[string]IMDBTitleMap, error) {
+	if titleType != IMDBTitleSimpleTypeMovie && titleType != IMDBTitleSimpleTypeShow {
+		return nil, ErrUnexpectedTitleType
+	}
+	return getIdMapsByColumn(MapColumn.TVDBId, titleType, tvdbIds)
+}
+
+func GetIdMapsByMALIds(malIds []string) (map[string]IMDBTitleMap, error) {
+	return getIdMapsByColumn(MapColumn.MALId, IMDBTitleSimpleTypeUnknown, malIds)
+}
@@ -3,6 +3,7 @@ package imdb_torrent
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/util"
@@ -44,6 +45,21 @@ var query_insert_after_values = fmt.Sprintf(
 )
 
 func Insert(items []IMDBTorrent) error {
+	return insert(items, query_insert_after_values)
+}
+
+var query_insert_missing_after_values = fmt.Sprintf(
+	" ON CONFLICT (%s, %s) DO NOTHING",
+	Column.Hash,
+	Column.TId,
+)
+
+// InsertMissing inserts the items without touching the existing ones.
+func InsertMissing(items []IMDBTorrent) error {
+	return insert(items, query_insert_missing_after_values)
+}
+
+func insert(items []IMDBTorrent, afterValues string) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -56,7 +72,7 @@ func Insert(items []IMDBTorrent) error {
 			args[i*2+1] = item.TId
 		}
 
-		query := query_insert_before_values + util.RepeatJoin(query_insert_values_placeholder, count, ",") + query_insert_after_values
+		query := query_insert_before_values + util.RepeatJoin(query_insert_values_placeholder, count, ",") + afterValues
 		_, err := db.Exec(query, args...)
 		if err != nil {
 			log.Error("failed to insert imdb torrent", "error", err)
@@ -293,3 +309,43 @@ func GetMappingsByHashes(hashes []string, cursor string, limit int, includeMappe
 
 	return items, nil
 }
+
+var query_list_changed = fmt.Sprintf(
+	"SELECT %s, %s, %s FROM %s WHERE %s > ? AND %s <= ? ORDER BY %s",
+	Column.TId,
+	Column.Hash,
+	Column.UAt,
+	TableName,
+	Column.UAt,
+	Column.UAt,
+	Column.UAt,
+)
+
+// ListChanged lists the items updated after `after` till `until`, ordered by
+// update time. No limit if `limit` is 0.
+func ListChanged(after, until time.Time, limit int) ([]IMDBTorrent, error) {
+	query := query_list_changed
+	args := []any{db.Timestamp{Time: after}, db.Timestamp{Time: until}}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []IMDBTorrent{}
+	for rows.Next() {
+		var item IMDBTorrent
+		if err := rows.Scan(&item.TId, &item.Hash, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
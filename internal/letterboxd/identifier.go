@@ -16,6 +16,8 @@ var letterboxdIdentifierCache = cache.NewCache[string](&cache.CacheConfig{
 	Name:     "letterboxd:identifier",
 })
 
+var ErrIdentifierNotFound = errors.New("not found")
+
 func fetchLetterboxdIdentifier(urlPath string) (lid string, err error) {
 	ctx := request.Ctx{}
 	req, err := ctx.NewRequest(SITE_BASE_URL_PARSED, "HEAD", urlPath, nil, nil)
@@ -26,12 +28,15 @@ func fetchLetterboxdIdentifier(urlPath string) (lid string, err error) {
 	if err != nil {
 		return "", err
 	}
+	if res.StatusCode == 404 {
+		return "", ErrIdentifierNotFound
+	}
 	if res.StatusCode >= 400 {
 		return "", fmt.Errorf("status code %d", res.StatusCode)
 	}
 	lid = res.Header.Get("X-Letterboxd-Identifier")
 	if lid == "" {
-		return "", errors.New("not found")
+		return "", ErrIdentifierNotFound
 	}
 	if err := letterboxdIdentifierCache.Add(urlPath, lid); err != nil {
 		return "", err
@@ -80,7 +85,7 @@ func FetchLetterboxdUserIdentifier(userName string) (lid string, err error) {
 		}
 	}
 
-	return "", errors.New("not found")
+	return "", ErrIdentifierNotFound
 }
 
 func FetchLetterboxdListIdentifier(userName, listSlug string) (lid string, err error) {
@@ -98,3 +103,13 @@ func FetchLetterboxdListIdentifier(userName, listSlug string) (lid string, err e
 	}
 	return fetchLetterboxdIdentifier(urlPath)
 }
+
+func FetchLetterboxdFilmIdentifierByIMDBId(imdbId string) (lid string, err error) {
+	urlPath := "/imdb/" + imdbId + "/"
+
+	if letterboxdIdentifierCache.Get(urlPath, &lid) {
+		return lid, nil
+	}
+
+	return fetchLetterboxdIdentifier(urlPath)
+}
@@ -0,0 +1,35 @@
+package letterboxd
+
+import "github.com/MunifTanjim/stremthru/internal/request"
+
+type LogEntryDiaryDetails struct {
+	DiaryDate string `json:"diaryDate"` // YYYY-MM-DD
+	Rewatch   bool   `json:"rewatch"`
+}
+
+type LogEntry struct {
+	Id           string                `json:"id"`
+	Name         string                `json:"name"`
+	Film         FilmSummary           `json:"film"`
+	DiaryDetails *LogEntryDiaryDetails `json:"diaryDetails,omitempty"`
+}
+
+type CreateLogEntryData struct {
+	ResponseError
+	LogEntry
+}
+
+type CreateLogEntryParams struct {
+	Ctx
+	FilmId       string                `json:"filmId"`
+	DiaryDetails *LogEntryDiaryDetails `json:"diaryDetails,omitempty"`
+	Like         bool                  `json:"like"`
+	Tags         []string              `json:"tags"`
+}
+
+func (c *APIClient) CreateLogEntry(params *CreateLogEntryParams) (request.APIResponse[CreateLogEntryData], error) {
+	params.JSON = params
+	response := CreateLogEntryData{}
+	res, err := c.Request("POST", "/v0/log-entries", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
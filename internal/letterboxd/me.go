@@ -0,0 +1,18 @@
+package letterboxd
+
+import "github.com/MunifTanjim/stremthru/internal/request"
+
+type FetchMeData struct {
+	ResponseError
+	Member MemberSummary `json:"member"`
+}
+
+type FetchMeParams struct {
+	Ctx
+}
+
+func (c *APIClient) FetchMe(params *FetchMeParams) (request.APIResponse[FetchMeData], error) {
+	response := FetchMeData{}
+	res, err := c.Request("GET", "/v0/me", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
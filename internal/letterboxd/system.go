@@ -47,3 +47,33 @@ func GetSystemClient() *APIClient {
 
 	return client
 }
+
+func GetAPIClient(tokenId string) *APIClient {
+	if tokenId == "" {
+		panic("tokenId cannot be empty")
+	}
+
+	var cachedClient APIClient
+	if apiClientCache.Get(tokenId, &cachedClient) {
+		return &cachedClient
+	}
+
+	client := NewAPIClient(&APIClientConfig{
+		OAuth: &APIClientConfigOAuth{
+			GetTokenSource: func(oauthConfig oauth2.Config) oauth2.TokenSource {
+				otok, _ := oauth.GetOAuthTokenById(tokenId)
+				if otok == nil {
+					return nil
+				}
+				return oauth.DatabaseTokenSource(&oauth.DatabaseTokenSourceConfig{
+					OAuth:             &oauthConfig,
+					TokenSourceConfig: oauth.LetterboxdTokenSourceConfig,
+				}, otok.ToToken())
+			},
+		},
+	})
+
+	apiClientCache.Add(tokenId, *client)
+
+	return client
+}
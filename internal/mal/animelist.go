@@ -0,0 +1,117 @@
+package mal
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AnimeListStatus string
+
+const (
+	AnimeListStatusWatching    AnimeListStatus = "watching"
+	AnimeListStatusCompleted   AnimeListStatus = "completed"
+	AnimeListStatusOnHold      AnimeListStatus = "on_hold"
+	AnimeListStatusDropped     AnimeListStatus = "dropped"
+	AnimeListStatusPlanToWatch AnimeListStatus = "plan_to_watch"
+)
+
+type MyListStatus struct {
+	ResponseError
+	Status             AnimeListStatus `json:"status"`
+	NumEpisodesWatched int             `json:"num_episodes_watched"`
+	IsRewatching       bool            `json:"is_rewatching"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+type AnimeListItem struct {
+	Node struct {
+		Id          int    `json:"id"`
+		Title       string `json:"title"`
+		NumEpisodes int    `json:"num_episodes"`
+	} `json:"node"`
+	ListStatus MyListStatus `json:"list_status"`
+}
+
+type getAnimeListData struct {
+	ResponseError
+	Data   []AnimeListItem `json:"data"`
+	Paging struct {
+		Next string `json:"next,omitempty"`
+	} `json:"paging"`
+}
+
+type GetMyAnimeListData = []AnimeListItem
+
+type GetMyAnimeListParams struct {
+	Ctx
+	Status AnimeListStatus
+}
+
+func (c APIClient) GetMyAnimeList(params *GetMyAnimeListParams) (APIResponse[GetMyAnimeListData], error) {
+	params.Query = &url.Values{}
+	params.Query.Set("fields", "list_status,num_episodes")
+	params.Query.Set("limit", "1000")
+	params.Query.Set("nsfw", "true")
+	if params.Status != "" {
+		params.Query.Set("status", string(params.Status))
+	}
+
+	items := GetMyAnimeListData{}
+	path := "/users/@me/animelist"
+	var res *http.Response
+	for path != "" {
+		response := getAnimeListData{}
+		r, err := c.Request("GET", path, params, &response)
+		res = r
+		if err != nil {
+			return newAPIResponse(res, items), err
+		}
+		items = append(items, response.Data...)
+		path = response.Paging.Next
+		// next url already carries the query
+		params.Query = nil
+	}
+	return newAPIResponse(res, items), nil
+}
+
+type UpdateMyListStatusParams struct {
+	Ctx
+	AnimeId            int
+	Status             AnimeListStatus
+	NumWatchedEpisodes int
+}
+
+func (c APIClient) UpdateMyListStatus(params *UpdateMyListStatusParams) (APIResponse[MyListStatus], error) {
+	params.Form = &url.Values{}
+	if params.Status != "" {
+		params.Form.Set("status", string(params.Status))
+	}
+	params.Form.Set("num_watched_episodes", strconv.Itoa(params.NumWatchedEpisodes))
+
+	response := MyListStatus{}
+	res, err := c.Request("PATCH", "/anime/"+strconv.Itoa(params.AnimeId)+"/my_list_status", params, &response)
+	return newAPIResponse(res, response), err
+}
+
+type Anime struct {
+	ResponseError
+	Id          int    `json:"id"`
+	Title       string `json:"title"`
+	NumEpisodes int    `json:"num_episodes"`
+}
+
+type GetAnimeParams struct {
+	Ctx
+	Id int
+}
+
+func (c APIClient) GetAnime(params *GetAnimeParams) (APIResponse[Anime], error) {
+	params.Query = &url.Values{}
+	params.Query.Set("fields", "num_episodes")
+
+	response := Anime{}
+	res, err := c.Request("GET", "/anime/"+strconv.Itoa(params.Id), params, &response)
+	return newAPIResponse(res, response), err
+}
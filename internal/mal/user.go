@@ -0,0 +1,17 @@
+package mal
+
+type UserInfo struct {
+	ResponseError
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetMyUserInfoParams struct {
+	Ctx
+}
+
+func (c APIClient) GetMyUserInfo(params *GetMyUserInfoParams) (APIResponse[UserInfo], error) {
+	response := UserInfo{}
+	res, err := c.Request("GET", "/users/@me", params, &response)
+	return newAPIResponse(res, response), err
+}
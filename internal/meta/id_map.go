@@ -2,13 +2,19 @@ package meta
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/anime"
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
 	meta_type "github.com/MunifTanjim/stremthru/internal/meta/type"
+	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
 type IdType = meta_type.IdType
@@ -41,21 +47,85 @@ const (
 type IdMapAnime = meta_type.IdMapAnime
 type IdMap = meta_type.IdMap
 
-func ParseId(idStr string) (provider IdProvider, id string) {
+type IdMapEpisode = meta_type.IdMapEpisode
+type IdMapEpisodeAniDB = meta_type.IdMapEpisodeAniDB
+type IdMapEpisodeTVDB = meta_type.IdMapEpisodeTVDB
+
+type ParsedId struct {
+	Provider IdProvider
+	Type     IdType
+	Id       string
+	Season   int // -1 when missing
+	Episode  int // -1 when missing
+}
+
+func (pid ParsedId) IsValid() bool {
+	return pid.Provider != ""
+}
+
+func (pid ParsedId) HasEpisode() bool {
+	return pid.Episode > 0
+}
+
+// ParseId parses ids in the `{provider}:[{type}:]{id}` format, e.g. `mal:5114`
+// or `tmdb:movie:603`, with an optional episode: `{season}:{episode}` for
+// `tt0944947:1:2` and `tvdb:121361:1:2`, and `{episode}` for the anime ones,
+// e.g. `kitsu:1:5`.
+func ParseId(idStr string) ParsedId {
+	pid := ParsedId{Season: -1, Episode: -1}
+
+	var parts []string
 	if strings.HasPrefix(idStr, "tt") {
-		return IdProviderIMDB, idStr
+		pid.Provider = IdProviderIMDB
+		parts = strings.Split(idStr, ":")
+	} else {
+		provider, rest, ok := strings.Cut(idStr, ":")
+		if !ok || !IdProvider(provider).IsValid() {
+			return ParsedId{}
+		}
+		pid.Provider = IdProvider(provider)
+		parts = strings.Split(rest, ":")
+		if len(parts) > 1 {
+			if idType := IdType(parts[0]); idType != IdTypeUnknown && idType.IsValid() {
+				pid.Type = idType
+				parts = parts[1:]
+			}
+		}
 	}
-	if traktId, ok := strings.CutPrefix(idStr, "trakt:"); ok {
-		return IdProviderTrakt, traktId
+
+	pid.Id = parts[0]
+	if pid.Id == "" || (pid.Provider == IdProviderIMDB && !strings.HasPrefix(pid.Id, "tt")) {
+		return ParsedId{}
 	}
-	if tvdbId, ok := strings.CutPrefix(idStr, "tvdb:"); ok {
-		return IdProviderTVDB, tvdbId
+
+	episodeParts := parts[1:]
+	switch {
+	case len(episodeParts) == 0:
+	case pid.Provider.IsAnime() && len(episodeParts) == 1:
+		pid.Episode = util.SafeParseInt(episodeParts[0], -1)
+		if pid.Episode <= 0 {
+			return ParsedId{}
+		}
+	case !pid.Provider.IsAnime() && len(episodeParts) == 2:
+		pid.Season = util.SafeParseInt(episodeParts[0], -1)
+		pid.Episode = util.SafeParseInt(episodeParts[1], -1)
+		if pid.Season < 0 || pid.Episode <= 0 {
+			return ParsedId{}
+		}
+	default:
+		return ParsedId{}
 	}
-	return "", ""
+
+	return pid
 }
 
 var ErrorUnsupportedId = errors.New("unsupported id")
 var ErrorUnsupportedIdAnchor = errors.New("unsupported id anchor")
+var ErrorMissingIdType = errors.New("missing id type")
+
+// ErrorUnsupportedIdProvider is for the valid ids of the providers without
+// any mapping source, i.e. tvmaze.
+var ErrorUnsupportedIdProvider = errors.New("unsupported id provider")
 
 var idMapCache = cache.NewCache[IdMap](&cache.CacheConfig{
 	Lifetime: 3 * time.Hour,
@@ -63,58 +133,387 @@ var idMapCache = cache.NewCache[IdMap](&cache.CacheConfig{
 	MaxSize:  2048,
 })
 
-func GetIdMap(idType IdType, idStr string) (*IdMap, error) {
-	idProvider, id := ParseId(idStr)
+func fromIMDBTitleMap(idm *imdb_title.IMDBTitleMap) IdMap {
+	idMap := IdMap{
+		Type:       IdType(idm.Type.ToSimple()),
+		IMDB:       idm.IMDBId,
+		TMDB:       idm.TMDBId,
+		TVDB:       idm.TVDBId,
+		Trakt:      idm.TraktId,
+		Letterboxd: idm.LetterboxdId,
+	}
+	if idm.MALId != "" {
+		idMap.Anime = &IdMapAnime{MAL: idm.MALId}
+	}
+	return idMap
+}
+
+func fromAnimeIdMap(aim *anime.AnimeIdMap) IdMap {
+	idMap := IdMap{
+		Type:       IdTypeUnknown,
+		IMDB:       aim.IMDB,
+		TMDB:       aim.TMDB,
+		TVDB:       aim.TVDB,
+		Trakt:      aim.Trakt,
+		Letterboxd: aim.Letterboxd,
+		Anime: &IdMapAnime{
+			AniDB:       aim.AniDB,
+			AniList:     aim.AniList,
+			AniSearch:   aim.AniSearch,
+			AnimePlanet: aim.AnimePlanet,
+			Kitsu:       aim.Kitsu,
+			LiveChart:   aim.LiveChart,
+			MAL:         aim.MAL,
+			NotifyMoe:   aim.NotifyMoe,
+		},
+	}
+	switch aim.Type {
+	case anime.AnimeIdMapTypeMovie:
+		idMap.Type = IdTypeMovie
+	case anime.AnimeIdMapTypeTV, anime.AnimeIdMapTypeTVShort, anime.AnimeIdMapTypeOVA, anime.AnimeIdMapTypeONA, anime.AnimeIdMapTypeSpecial:
+		idMap.Type = IdTypeShow
+	}
+	return idMap
+}
+
+func getAnimeId(idMap *IdMap, provider IdProvider) string {
+	if idMap.Anime == nil {
+		return ""
+	}
+	switch provider {
+	case IdProviderAniDB:
+		return idMap.Anime.AniDB
+	case IdProviderAniList:
+		return idMap.Anime.AniList
+	case IdProviderAniSearch:
+		return idMap.Anime.AniSearch
+	case IdProviderAnimePlanet:
+		return idMap.Anime.AnimePlanet
+	case IdProviderKitsu:
+		return idMap.Anime.Kitsu
+	case IdProviderLiveChart:
+		return idMap.Anime.LiveChart
+	case IdProviderMAL:
+		return idMap.Anime.MAL
+	case IdProviderNotifyMoe:
+		return idMap.Anime.NotifyMoe
+	}
+	return ""
+}
 
-	idMap := IdMap{IMDB: id}
+func setId(idMap *IdMap, provider IdProvider, id string) {
+	switch provider {
+	case IdProviderIMDB:
+		idMap.IMDB = id
+	case IdProviderTMDB:
+		idMap.TMDB = id
+	case IdProviderTVDB:
+		idMap.TVDB = id
+	case IdProviderTVMaze:
+		idMap.TVMaze = id
+	case IdProviderTrakt:
+		idMap.Trakt = id
+	case IdProviderLetterboxd:
+		idMap.Letterboxd = id
+	default:
+		if idMap.Anime == nil {
+			idMap.Anime = &IdMapAnime{}
+		}
+		switch provider {
+		case IdProviderAniDB:
+			idMap.Anime.AniDB = id
+		case IdProviderAniList:
+			idMap.Anime.AniList = id
+		case IdProviderAniSearch:
+			idMap.Anime.AniSearch = id
+		case IdProviderAnimePlanet:
+			idMap.Anime.AnimePlanet = id
+		case IdProviderKitsu:
+			idMap.Anime.Kitsu = id
+		case IdProviderLiveChart:
+			idMap.Anime.LiveChart = id
+		case IdProviderMAL:
+			idMap.Anime.MAL = id
+		case IdProviderNotifyMoe:
+			idMap.Anime.NotifyMoe = id
+		}
+	}
+}
 
-	cacheKey := meta_type.GetIdProviderCacheKey(idProvider, idType, id)
-	if !idMapCache.Get(cacheKey, &idMap) {
-		switch idProvider {
-		case IdProviderIMDB:
-			idm, err := imdb_title.GetIdMapByIMDBId(id)
-			if err != nil || idm == nil {
-				return &idMap, err
+func getAnimeIdMaps(provider IdProvider, ids []string) ([]anime.AnimeIdMap, error) {
+	switch provider {
+	case IdProviderAniDB:
+		return anime.GetIdMapsForAniDB(ids)
+	case IdProviderAniList, IdProviderMAL:
+		intIds := make([]int, 0, len(ids))
+		for _, id := range ids {
+			if intId, err := strconv.Atoi(id); err == nil {
+				intIds = append(intIds, intId)
 			}
-			idMap.Type = IdType(idm.Type.ToSimple())
-			idMap.IMDB = id
-			idMap.TMDB = idm.TMDBId
-			idMap.TVDB = idm.TVDBId
-			idMap.Trakt = idm.TraktId
-			idMap.Letterboxd = idm.LetterboxdId
-		case IdProviderTVDB:
-			idm, err := imdb_title.GetIdMapByTVDBId(imdb_title.IMDBTitleSimpleType(idType), id)
-			if err != nil || idm == nil {
-				return &idMap, err
+		}
+		if provider == IdProviderAniList {
+			return anime.GetIdMapsForAniList(intIds)
+		}
+		return anime.GetIdMapsForMAL(intIds)
+	case IdProviderAniSearch:
+		return anime.GetIdMapsForAniSearch(ids)
+	case IdProviderAnimePlanet:
+		return anime.GetIdMapsForAnimePlanet(ids)
+	case IdProviderKitsu:
+		return anime.GetIdMapsForKitsu(ids)
+	case IdProviderLiveChart:
+		return anime.GetIdMapsForLiveChart(ids)
+	case IdProviderNotifyMoe:
+		return anime.GetIdMapsForNotifyMoe(ids)
+	}
+	return nil, ErrorUnsupportedId
+}
+
+// getIdMapsByProvider returns the id maps keyed by the id of the provider.
+func getIdMapsByProvider(provider IdProvider, idType IdType, ids []string) (map[string]IdMap, error) {
+	idMapById := make(map[string]IdMap, len(ids))
+
+	var idms map[string]imdb_title.IMDBTitleMap
+	var err error
+	switch provider {
+	case IdProviderIMDB:
+		idms, err = imdb_title.GetIdMapsByIMDBId(ids)
+	case IdProviderTMDB:
+		idms, err = imdb_title.GetIdMapsByTMDBIds(imdb_title.IMDBTitleSimpleType(idType), ids)
+	case IdProviderTVDB:
+		idms, err = imdb_title.GetIdMapsByTVDBIds(imdb_title.IMDBTitleSimpleType(idType), ids)
+	case IdProviderTrakt:
+		idms, err = imdb_title.GetIdMapsByTraktIds(imdb_title.IMDBTitleSimpleType(idType), ids)
+	case IdProviderLetterboxd:
+		idms, err = imdb_title.GetIdMapsByLetterboxdId(ids)
+	case IdProviderTVMaze:
+		return nil, fmt.Errorf("%w: %s", ErrorUnsupportedIdProvider, provider)
+	default:
+		aims, err := getAnimeIdMaps(provider, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range aims {
+			idMap := fromAnimeIdMap(&aims[i])
+			idMapById[getAnimeId(&idMap, provider)] = idMap
+		}
+		if provider == IdProviderMAL && len(idMapById) < len(ids) {
+			missingIds := make([]string, 0, len(ids)-len(idMapById))
+			for _, id := range ids {
+				if _, ok := idMapById[id]; !ok {
+					missingIds = append(missingIds, id)
+				}
 			}
-			idMap.Type = IdType(idm.Type.ToSimple())
-			idMap.IMDB = idm.IMDBId
-			idMap.TMDB = idm.TMDBId
-			idMap.TVDB = id
-			idMap.Trakt = idm.TraktId
-			idMap.Letterboxd = idm.LetterboxdId
-		case IdProviderTrakt:
-			idMaps, err := imdb_title.GetIdMapsByTraktIds(imdb_title.IMDBTitleSimpleType(idType), []string{id})
+			idms, err = imdb_title.GetIdMapsByMALIds(missingIds)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for id, idm := range idms {
+		idMapById[id] = fromIMDBTitleMap(&idm)
+	}
+	return idMapById, nil
+}
+
+// GetIdMaps looks up the ids in batch, the result is keyed by the given ids
+// and misses the ones that are invalid or not found. It fails with
+// ErrorUnsupportedIdProvider for the ids of the providers without any
+// mapping source.
+func GetIdMaps(idType IdType, idStrs []string) (map[string]*IdMap, error) {
+	result := make(map[string]*IdMap, len(idStrs))
+
+	type lookup struct {
+		provider   IdProvider
+		idType     IdType
+		ids        []string
+		idStrsById map[string][]string
+	}
+	lookups := map[string]*lookup{}
+	for _, idStr := range idStrs {
+		pid := ParseId(idStr)
+		if !pid.IsValid() {
+			continue
+		}
+		if pid.Type == IdTypeUnknown {
+			pid.Type = idType
+		}
+		if pid.Provider.RequiresIdType() && pid.Type == IdTypeUnknown {
+			continue
+		}
+
+		idMap := IdMap{}
+		if idMapCache.Get(meta_type.GetIdProviderCacheKey(pid.Provider, pid.Type, pid.Id), &idMap) {
+			result[idStr] = &idMap
+			continue
+		}
+
+		lookupKey := string(pid.Provider) + ":" + string(pid.Type)
+		l, ok := lookups[lookupKey]
+		if !ok {
+			l = &lookup{provider: pid.Provider, idType: pid.Type, idStrsById: map[string][]string{}}
+			lookups[lookupKey] = l
+		}
+		if _, ok := l.idStrsById[pid.Id]; !ok {
+			l.ids = append(l.ids, pid.Id)
+		}
+		l.idStrsById[pid.Id] = append(l.idStrsById[pid.Id], idStr)
+	}
+
+	for _, l := range lookups {
+		idMapById, err := getIdMapsByProvider(l.provider, l.idType, l.ids)
+		if err != nil {
+			if errors.Is(err, ErrorUnsupportedId) {
+				continue
+			}
+			return nil, err
+		}
+		for id, idMap := range idMapById {
+			if err := idMapCache.Add(meta_type.GetIdProviderCacheKey(l.provider, l.idType, id), idMap); err != nil {
+				return nil, err
+			}
+			for _, idStr := range l.idStrsById[id] {
+				idMap := idMap
+				result[idStr] = &idMap
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func GetIdMap(idType IdType, idStr string) (*IdMap, error) {
+	pid := ParseId(idStr)
+	if !pid.IsValid() {
+		return nil, ErrorUnsupportedId
+	}
+	if pid.Type == IdTypeUnknown {
+		pid.Type = idType
+	}
+	if pid.Provider.RequiresIdType() && pid.Type == IdTypeUnknown {
+		return nil, ErrorMissingIdType
+	}
+
+	idMaps, err := GetIdMaps(idType, []string{idStr})
+	if err != nil {
+		return nil, err
+	}
+	if idMap, ok := idMaps[idStr]; ok {
+		return idMap, nil
+	}
+
+	idMap := &IdMap{Type: pid.Type}
+	setId(idMap, pid.Provider, pid.Id)
+	return idMap, nil
+}
+
+// GetIdMapEpisode maps the episode of the id between anidb and tvdb, using
+// the anime of the id map, or the tvdb series of the id map for the others.
+func GetIdMapEpisode(idMap *IdMap, pid ParsedId) (*IdMapEpisode, error) {
+	if idMap == nil || !pid.HasEpisode() {
+		return nil, nil
+	}
+
+	mapsByAniDBId := map[string]anidb.AniDBTVDBEpisodeMaps{}
+	mapsByTVDBId := map[string]anidb.AniDBTVDBEpisodeMaps{}
+	if pid.Provider.IsAnime() {
+		if anidbId := getAnimeId(idMap, IdProviderAniDB); anidbId != "" {
+			res, err := anidb.GetTVDBEpisodeMaps(anidbId, false)
 			if err != nil {
-				return &idMap, err
+				return nil, err
 			}
-			idm := idMaps[id]
-			idMap.Type = IdType(idm.Type.ToSimple())
-			idMap.IMDB = idm.IMDBId
-			idMap.TMDB = idm.TMDBId
-			idMap.TVDB = idm.TVDBId
-			idMap.Trakt = id
-			idMap.Letterboxd = idm.LetterboxdId
-		default:
-			return nil, ErrorUnsupportedId
-		}
-
-		if err := idMapCache.Add(cacheKey, idMap); err != nil {
+			mapsByAniDBId[anidbId] = res.Val()
+		}
+	} else if idMap.TVDB != "" {
+		maps, err := anidb.GetTVDBEpisodeMapsByTVDBIds([]string{idMap.TVDB})
+		if err != nil {
 			return nil, err
 		}
+		mapsByTVDBId = maps
+	}
+	return getIdMapEpisode(idMap, pid, mapsByAniDBId, mapsByTVDBId), nil
+}
+
+// GetIdMapEpisodes is the batch version of GetIdMapEpisode, the id maps and
+// the result are keyed by the ids. The episode maps are looked up once for
+// all the ids.
+func GetIdMapEpisodes(idMaps map[string]*IdMap) (map[string]*IdMapEpisode, error) {
+	pids := make(map[string]ParsedId, len(idMaps))
+	anidbIds := []string{}
+	tvdbIds := []string{}
+	for idStr, idMap := range idMaps {
+		pid := ParseId(idStr)
+		if idMap == nil || !pid.HasEpisode() {
+			continue
+		}
+		pids[idStr] = pid
+		if pid.Provider.IsAnime() {
+			if anidbId := getAnimeId(idMap, IdProviderAniDB); anidbId != "" && !slices.Contains(anidbIds, anidbId) {
+				anidbIds = append(anidbIds, anidbId)
+			}
+		} else if idMap.TVDB != "" && !slices.Contains(tvdbIds, idMap.TVDB) {
+			tvdbIds = append(tvdbIds, idMap.TVDB)
+		}
 	}
 
-	return &idMap, nil
+	mapsByAniDBId, err := anidb.GetTVDBEpisodeMapsByAniDBIds(anidbIds)
+	if err != nil {
+		return nil, err
+	}
+	mapsByTVDBId, err := anidb.GetTVDBEpisodeMapsByTVDBIds(tvdbIds)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make(map[string]*IdMapEpisode, len(pids))
+	for idStr, pid := range pids {
+		episodes[idStr] = getIdMapEpisode(idMaps[idStr], pid, mapsByAniDBId, mapsByTVDBId)
+	}
+	return episodes, nil
+}
+
+func getIdMapEpisode(idMap *IdMap, pid ParsedId, mapsByAniDBId, mapsByTVDBId map[string]anidb.AniDBTVDBEpisodeMaps) *IdMapEpisode {
+	if pid.Provider.IsAnime() {
+		anidbId := getAnimeId(idMap, IdProviderAniDB)
+		if anidbId == "" {
+			return nil
+		}
+		episode := &IdMapEpisode{
+			AniDB: &IdMapEpisodeAniDB{Id: anidbId, Episode: pid.Episode},
+		}
+		if season, ep, ok := mapsByAniDBId[anidbId].GetTVDBEpisode(pid.Episode); ok {
+			episode.TVDB = &IdMapEpisodeTVDB{Season: season, Episode: ep}
+		}
+		return episode
+	}
+
+	episode := &IdMapEpisode{
+		TVDB: &IdMapEpisodeTVDB{Season: pid.Season, Episode: pid.Episode},
+	}
+	if idMap.TVDB == "" {
+		return episode
+	}
+	maps := mapsByTVDBId[idMap.TVDB]
+	mapsByAniDBIdOfTVDB := map[string]anidb.AniDBTVDBEpisodeMaps{}
+	anidbIds := []string{}
+	for i := range maps {
+		m := &maps[i]
+		if _, ok := mapsByAniDBIdOfTVDB[m.AniDBId]; !ok {
+			anidbIds = append(anidbIds, m.AniDBId)
+		}
+		mapsByAniDBIdOfTVDB[m.AniDBId] = append(mapsByAniDBIdOfTVDB[m.AniDBId], *m)
+	}
+	absoluteEpisode := -1
+	if maps.HasAbsoluteOrder() {
+		absoluteEpisode = maps.GetTVDBAbsoluteEpisode(pid.Season, pid.Episode)
+	}
+	for _, anidbId := range anidbIds {
+		if ep := mapsByAniDBIdOfTVDB[anidbId].GetAniDBEpisode(pid.Season, pid.Episode, absoluteEpisode); ep > 0 {
+			episode.AniDB = &IdMapEpisodeAniDB{Id: anidbId, Episode: ep}
+			break
+		}
+	}
+	return episode
 }
 
 func SetIdMapsInTrx(tx db.Executor, idMaps []IdMap, anchor IdProvider) error {
@@ -15,6 +15,13 @@ var IsMethod = shared.IsMethod
 var SendError = shared.SendError
 var SendResponse = shared.SendResponse
 
+const maxBatchSize = 500
+
+type idMapResponse struct {
+	*meta.IdMap
+	Episode *meta.IdMapEpisode `json:"episode,omitempty"`
+}
+
 func handleIdMap(w http.ResponseWriter, r *http.Request) {
 	if !IsMethod(r, http.MethodGet) {
 		shared.ErrorMethodNotAllowed(r).Send(w, r)
@@ -30,16 +37,80 @@ func handleIdMap(w http.ResponseWriter, r *http.Request) {
 
 	idMap, err := meta.GetIdMap(idType, id)
 	if err != nil {
-		if errors.Is(err, meta.ErrorUnsupportedId) {
-			shared.ErrorBadRequest(r, meta.ErrorUnsupportedId.Error()).Send(w, r)
+		if errors.Is(err, meta.ErrorUnsupportedId) || errors.Is(err, meta.ErrorUnsupportedIdProvider) || errors.Is(err, meta.ErrorMissingIdType) {
+			shared.ErrorBadRequest(r, err.Error()).Send(w, r)
 			return
 		}
 		shared.ErrorInternalServerError(r, "").WithCause(err).Send(w, r)
 		return
 	}
 
+	episode, err := meta.GetIdMapEpisode(idMap, meta.ParseId(id))
+	if err != nil {
+		shared.ErrorInternalServerError(r, "").WithCause(err).Send(w, r)
+		return
+	}
+
 	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(time.Duration(6*time.Hour).Seconds())))
-	SendResponse(w, r, 200, idMap, nil)
+	SendResponse(w, r, 200, idMapResponse{IdMap: idMap, Episode: episode}, nil)
+}
+
+type BatchIdMapPayload struct {
+	Type meta.IdType `json:"type"`
+	Ids  []string    `json:"ids"`
+}
+
+func handleBatchIdMap(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodPost) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	payload := &BatchIdMapPayload{}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if !payload.Type.IsValid() {
+		shared.ErrorBadRequest(r, "invalid type").Send(w, r)
+		return
+	}
+	if len(payload.Ids) == 0 {
+		shared.ErrorBadRequest(r, "missing ids").Send(w, r)
+		return
+	}
+	if len(payload.Ids) > maxBatchSize {
+		shared.ErrorBadRequest(r, "too many ids, max "+strconv.Itoa(maxBatchSize)).Send(w, r)
+		return
+	}
+
+	idMaps, err := meta.GetIdMaps(payload.Type, payload.Ids)
+	if err != nil {
+		if errors.Is(err, meta.ErrorUnsupportedIdProvider) {
+			shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+			return
+		}
+		shared.ErrorInternalServerError(r, "").WithCause(err).Send(w, r)
+		return
+	}
+
+	episodes, err := meta.GetIdMapEpisodes(idMaps)
+	if err != nil {
+		shared.ErrorInternalServerError(r, "").WithCause(err).Send(w, r)
+		return
+	}
+
+	data := make(map[string]*idMapResponse, len(payload.Ids))
+	for _, id := range payload.Ids {
+		idMap, ok := idMaps[id]
+		if !ok {
+			data[id] = nil
+			continue
+		}
+		data[id] = &idMapResponse{IdMap: idMap, Episode: episodes[id]}
+	}
+
+	SendResponse(w, r, 200, data, nil)
 }
 
 func commonMiddleware(next http.Handler) http.Handler {
@@ -53,6 +124,7 @@ func commonMiddleware(next http.Handler) http.Handler {
 func AddEndpoints(mux *http.ServeMux) {
 	router := http.NewServeMux()
 
+	router.HandleFunc("/{$}", handleBatchIdMap)
 	router.HandleFunc("/{idType}/{id}", handleIdMap)
 
 	mux.Handle("/v0/meta/id-map/", http.StripPrefix("/v0/meta/id-map", commonMiddleware(router)))
@@ -0,0 +1,44 @@
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseId(t *testing.T) {
+	for _, tc := range []struct {
+		idStr  string
+		result ParsedId
+	}{
+		{"tt0133093", ParsedId{Provider: IdProviderIMDB, Id: "tt0133093", Season: -1, Episode: -1}},
+		{"tt0944947:1:2", ParsedId{Provider: IdProviderIMDB, Id: "tt0944947", Season: 1, Episode: 2}},
+		{"imdb:tt0133093", ParsedId{Provider: IdProviderIMDB, Id: "tt0133093", Season: -1, Episode: -1}},
+		{"tmdb:603", ParsedId{Provider: IdProviderTMDB, Id: "603", Season: -1, Episode: -1}},
+		{"tmdb:movie:603", ParsedId{Provider: IdProviderTMDB, Type: IdTypeMovie, Id: "603", Season: -1, Episode: -1}},
+		{"tvdb:show:81797:1:5", ParsedId{Provider: IdProviderTVDB, Type: IdTypeShow, Id: "81797", Season: 1, Episode: 5}},
+		{"tvdb:81797:0:5", ParsedId{Provider: IdProviderTVDB, Id: "81797", Season: 0, Episode: 5}},
+		{"mal:5114", ParsedId{Provider: IdProviderMAL, Id: "5114", Season: -1, Episode: -1}},
+		{"anidb:69:5", ParsedId{Provider: IdProviderAniDB, Id: "69", Season: -1, Episode: 5}},
+		{"lboxd:2bbs", ParsedId{Provider: IdProviderLetterboxd, Id: "2bbs", Season: -1, Episode: -1}},
+		{"imdb:0133093", ParsedId{}},
+		{"foo:123", ParsedId{}},
+		{"mal:", ParsedId{}},
+		{"mal:5114:1:2", ParsedId{}},
+		{"tvdb:81797:5", ParsedId{}},
+		{"tvdb:81797:1:x", ParsedId{}},
+		{"123", ParsedId{}},
+	} {
+		t.Run(tc.idStr, func(t *testing.T) {
+			assert.Equal(t, tc.result, ParseId(tc.idStr))
+		})
+	}
+}
+
+func TestGetIdMapsUnsupportedIdProvider(t *testing.T) {
+	_, err := GetIdMaps(IdTypeShow, []string{"tvmaze:82"})
+	assert.ErrorIs(t, err, ErrorUnsupportedIdProvider)
+
+	_, err = GetIdMap(IdTypeShow, "tvmaze:82")
+	assert.ErrorIs(t, err, ErrorUnsupportedIdProvider)
+}
@@ -0,0 +1,402 @@
+package meta_stremthru
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	meta_type "github.com/MunifTanjim/stremthru/internal/meta/type"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
+)
+
+func toList(l *stremthru_list.StremThruList) *meta_type.List {
+	list := meta_type.List{
+		Provider:    meta_type.ProviderStremThru,
+		Id:          l.Id,
+		Title:       l.GetDisplayName(),
+		Description: l.Description,
+		ItemType:    meta_type.ItemTypeMixed,
+		ItemCount:   l.ItemCount,
+		Version:     l.UAt.Unix(),
+		UpdatedAt:   l.UAt.Time,
+		Items:       []meta_type.ListItem{},
+	}
+
+	hasMovie, hasShow := false, false
+	for i := range l.Items {
+		item := &l.Items[i]
+		listItem := meta_type.ListItem{
+			Id:        item.Id,
+			Title:     item.Name,
+			Year:      item.Year,
+			Poster:    item.Poster,
+			UpdatedAt: item.CAt.Time,
+			Index:     i,
+		}
+		switch item.Type {
+		case stremthru_list.ItemTypeMovie:
+			hasMovie = true
+			listItem.Type = meta_type.ItemTypeMovie
+			listItem.IdMap.Type = meta_type.IdTypeMovie
+		case stremthru_list.ItemTypeSeries:
+			hasShow = true
+			listItem.Type = meta_type.ItemTypeShow
+			listItem.IdMap.Type = meta_type.IdTypeShow
+		}
+		if strings.HasPrefix(item.Id, "tt") {
+			listItem.IdMap.IMDB = item.Id
+		} else if tmdbId, ok := strings.CutPrefix(item.Id, "tmdb:"); ok {
+			listItem.IdMap.TMDB = tmdbId
+		} else if provider, id, ok := strings.Cut(item.Id, ":"); ok {
+			anime := &meta_type.IdMapAnime{}
+			switch provider {
+			case "anidb":
+				anime.AniDB = id
+			case "anilist":
+				anime.AniList = id
+			case "kitsu":
+				anime.Kitsu = id
+			case "mal":
+				anime.MAL = id
+			}
+			listItem.IdMap.Anime = anime
+		}
+		list.Items = append(list.Items, listItem)
+	}
+	if hasMovie && !hasShow {
+		list.ItemType = meta_type.ItemTypeMovie
+	} else if hasShow && !hasMovie {
+		list.ItemType = meta_type.ItemTypeShow
+	}
+
+	return &list
+}
+
+type ListData struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Owner       string    `json:"owner"`
+	Secret      string    `json:"secret"`
+	ItemCount   int       `json:"item_count"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func GetListURL(r *http.Request, id string) string {
+	return shared.ExtractRequestBaseURL(r).JoinPath("/v0/meta/lists/stremthru", id).String()
+}
+
+func ToListData(r *http.Request, l *stremthru_list.StremThruList) ListData {
+	return ListData{
+		Id:          l.Id,
+		Name:        l.Name,
+		Description: l.Description,
+		Owner:       l.Owner,
+		Secret:      l.Secret,
+		ItemCount:   l.ItemCount,
+		URL:         GetListURL(r, l.Id),
+		CreatedAt:   l.CAt.Time,
+		UpdatedAt:   l.UAt.Time,
+	}
+}
+
+type ListItemPayload struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Poster string `json:"poster"`
+}
+
+func (item ListItemPayload) toItemInput() stremthru_list.ItemInput {
+	itemType := stremthru_list.ItemType(item.Type)
+	if item.Type == "show" {
+		itemType = stremthru_list.ItemTypeSeries
+	}
+	return stremthru_list.ItemInput{
+		Id:     item.Id,
+		Type:   itemType,
+		Name:   item.Name,
+		Poster: item.Poster,
+	}
+}
+
+type AddListItemsPayload struct {
+	Items []ListItemPayload `json:"items"`
+}
+
+func (p AddListItemsPayload) ToItemInputs() []stremthru_list.ItemInput {
+	inputs := make([]stremthru_list.ItemInput, len(p.Items))
+	for i, item := range p.Items {
+		inputs[i] = item.toItemInput()
+	}
+	return inputs
+}
+
+type RemoveListItemsPayload struct {
+	Ids   []string          `json:"ids"`
+	Items []ListItemPayload `json:"items"` // for TMDB ids, with type
+}
+
+func (p RemoveListItemsPayload) ToItemInputs() []stremthru_list.ItemInput {
+	inputs := make([]stremthru_list.ItemInput, 0, len(p.Ids)+len(p.Items))
+	for _, id := range p.Ids {
+		inputs = append(inputs, stremthru_list.ItemInput{Id: id})
+	}
+	for _, item := range p.Items {
+		inputs = append(inputs, item.toItemInput())
+	}
+	return inputs
+}
+
+type listPayload struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func getAuthedUser(r *http.Request) (string, bool) {
+	isAuthorized, user, _ := server.GetProxyAuthorization(r, false)
+	return user, isAuthorized
+}
+
+func isOwner(list *stremthru_list.StremThruList, user string, isAuthed bool) bool {
+	return isAuthed && user != "" && user == list.Owner
+}
+
+// isEditor checks if the list can be changed with the secret, or by the
+// user.
+func isEditor(list *stremthru_list.StremThruList, secret string, user string, isAuthed bool) bool {
+	return list.IsSecretValid(secret) || isOwner(list, user, isAuthed)
+}
+
+// canEdit checks if the request is allowed to change the list, either by
+// its secret or by proxy auth of its owner.
+func canEdit(r *http.Request, list *stremthru_list.StremThruList) bool {
+	user, isAuthed := getAuthedUser(r)
+	return isEditor(list, r.Header.Get(server.HEADER_STREMTHRU_LIST_SECRET), user, isAuthed)
+}
+
+func handleGetListById(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	l, err := stremthru_list.GetByIdWithItems(r.PathValue("list_id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if l == nil {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendResponse(w, r, 200, toList(l), nil)
+}
+
+func handleGetLists(w http.ResponseWriter, r *http.Request) {
+	user, ok := getAuthedUser(r)
+	if !ok {
+		shared.ErrorUnauthorized(r).Send(w, r)
+		return
+	}
+
+	lists, err := stremthru_list.GetAllByOwner(user)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]ListData, len(lists))
+	for i := range lists {
+		data[i] = ToListData(r, &lists[i])
+	}
+	SendResponse(w, r, 200, data, nil)
+}
+
+func handleCreateList(w http.ResponseWriter, r *http.Request) {
+	user, ok := getAuthedUser(r)
+	if !ok {
+		shared.ErrorUnauthorized(r).Send(w, r)
+		return
+	}
+
+	payload := &listPayload{}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	if payload.Name == "" {
+		shared.ErrorBadRequest(r, "missing name").Send(w, r)
+		return
+	}
+
+	l, err := stremthru_list.Create(payload.Name, strings.TrimSpace(payload.Description), user)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendResponse(w, r, 201, ToListData(r, l), nil)
+}
+
+func handleLists(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		handleGetLists(w, r)
+	case http.MethodPost:
+		handleCreateList(w, r)
+	default:
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+	}
+}
+
+func getEditableList(w http.ResponseWriter, r *http.Request) *stremthru_list.StremThruList {
+	l, err := stremthru_list.GetById(r.PathValue("list_id"))
+	if err != nil {
+		SendError(w, r, err)
+		return nil
+	}
+	if l == nil {
+		shared.ErrorNotFound(r).Send(w, r)
+		return nil
+	}
+	if !canEdit(r, l) {
+		shared.ErrorForbidden(r).Send(w, r)
+		return nil
+	}
+	return l
+}
+
+func handleUpdateList(w http.ResponseWriter, r *http.Request) {
+	l := getEditableList(w, r)
+	if l == nil {
+		return
+	}
+
+	payload := &listPayload{Name: l.Name, Description: l.Description}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	if payload.Name == "" {
+		shared.ErrorBadRequest(r, "missing name").Send(w, r)
+		return
+	}
+
+	if err := stremthru_list.Update(l.Id, payload.Name, strings.TrimSpace(payload.Description)); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	l, err := stremthru_list.GetById(l.Id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendResponse(w, r, 200, ToListData(r, l), nil)
+}
+
+func handleDeleteList(w http.ResponseWriter, r *http.Request) {
+	l, err := stremthru_list.GetById(r.PathValue("list_id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if l == nil {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+	if user, isAuthed := getAuthedUser(r); !isOwner(l, user, isAuthed) {
+		shared.ErrorForbidden(r).Send(w, r)
+		return
+	}
+
+	if err := stremthru_list.Delete(l.Id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func handleList(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		handleGetListById(w, r)
+	case http.MethodPatch:
+		handleUpdateList(w, r)
+	case http.MethodDelete:
+		handleDeleteList(w, r)
+	default:
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+	}
+}
+
+func handleAddListItems(w http.ResponseWriter, r *http.Request) {
+	l := getEditableList(w, r)
+	if l == nil {
+		return
+	}
+
+	payload := &AddListItemsPayload{}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(payload.Items) == 0 {
+		shared.ErrorBadRequest(r, "missing items").Send(w, r)
+		return
+	}
+
+	items, err := stremthru_list.NewItems(payload.ToItemInputs())
+	if err != nil {
+		shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+		return
+	}
+	if err := stremthru_list.AddItems(l.Id, items); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func handleRemoveListItems(w http.ResponseWriter, r *http.Request) {
+	l := getEditableList(w, r)
+	if l == nil {
+		return
+	}
+
+	payload := &RemoveListItemsPayload{}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	ids, err := stremthru_list.ResolveItemIds(payload.ToItemInputs())
+	if err != nil {
+		shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+		return
+	}
+	if err := stremthru_list.RemoveItems(l.Id, ids); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func handleListItems(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		handleAddListItems(w, r)
+	case http.MethodDelete:
+		handleRemoveListItems(w, r)
+	default:
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+	}
+}
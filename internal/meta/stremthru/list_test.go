@@ -0,0 +1,87 @@
+package meta_stremthru
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEditor(t *testing.T) {
+	list := &stremthru_list.StremThruList{Owner: "alice", Secret: "s3cr3t"}
+
+	for _, tc := range []struct {
+		name     string
+		secret   string
+		user     string
+		isAuthed bool
+		expected bool
+	}{
+		{"valid secret", "s3cr3t", "", false, true},
+		{"valid secret, other user", "s3cr3t", "bob", true, true},
+		{"invalid secret", "secret", "", false, false},
+		{"owner", "", "alice", true, true},
+		{"owner, invalid secret", "secret", "alice", true, true},
+		{"owner, not authed", "", "alice", false, false},
+		{"other user", "", "bob", true, false},
+		{"other user, invalid secret", "secret", "bob", true, false},
+		{"no secret, no user", "", "", false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isEditor(list, tc.secret, tc.user, tc.isAuthed))
+		})
+	}
+
+	t.Run("list without owner", func(t *testing.T) {
+		list := &stremthru_list.StremThruList{Secret: "s3cr3t"}
+		assert.False(t, isEditor(list, "", "", true))
+		assert.False(t, isOwner(list, "", true))
+	})
+
+	t.Run("list without secret", func(t *testing.T) {
+		list := &stremthru_list.StremThruList{Owner: "alice"}
+		assert.False(t, isEditor(list, "", "bob", true))
+	})
+}
+
+func TestCanEdit(t *testing.T) {
+	list := &stremthru_list.StremThruList{Owner: "alice", Secret: "s3cr3t"}
+
+	t.Run("secret header", func(t *testing.T) {
+		r := httptest.NewRequest("PATCH", "/lists/"+list.Id, nil)
+		r.Header.Set(server.HEADER_STREMTHRU_LIST_SECRET, "s3cr3t")
+		assert.True(t, canEdit(r, list))
+	})
+
+	t.Run("invalid secret header", func(t *testing.T) {
+		r := httptest.NewRequest("PATCH", "/lists/"+list.Id, nil)
+		r.Header.Set(server.HEADER_STREMTHRU_LIST_SECRET, "secret")
+		assert.False(t, canEdit(r, list))
+	})
+
+	t.Run("owner without password", func(t *testing.T) {
+		r := httptest.NewRequest("PATCH", "/lists/"+list.Id, nil)
+		r.Header.Set(server.HEADER_STREMTHRU_AUTHORIZATION, "Basic "+base64.StdEncoding.EncodeToString([]byte("alice:")))
+		assert.False(t, canEdit(r, list))
+	})
+
+	t.Run("no auth", func(t *testing.T) {
+		r := httptest.NewRequest("PATCH", "/lists/"+list.Id, nil)
+		assert.False(t, canEdit(r, list))
+	})
+}
+
+func TestRemoveListItemsPayload(t *testing.T) {
+	payload := RemoveListItemsPayload{
+		Ids:   []string{"tt0903747"},
+		Items: []ListItemPayload{{Id: "tmdb:603", Type: "movie"}, {Id: "tmdb:1396", Type: "show"}},
+	}
+	assert.Equal(t, []stremthru_list.ItemInput{
+		{Id: "tt0903747"},
+		{Id: "tmdb:603", Type: stremthru_list.ItemTypeMovie},
+		{Id: "tmdb:1396", Type: stremthru_list.ItemTypeSeries},
+	}, payload.ToItemInputs())
+}
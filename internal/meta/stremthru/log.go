@@ -0,0 +1,5 @@
+package meta_stremthru
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("meta/stremthru")
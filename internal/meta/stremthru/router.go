@@ -0,0 +1,32 @@
+package meta_stremthru
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+)
+
+var IsMethod = shared.IsMethod
+var SendError = shared.SendError
+var SendResponse = shared.SendResponse
+
+func commonMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := server.GetReqCtx(r)
+		ctx.Log = log.WithCtx(r.Context(), "req.id", ctx.RequestId)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func AddEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("/v0/meta/lists/stremthru/{list_id}", handleGetListById)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("/lists", handleLists)
+	router.HandleFunc("/lists/{list_id}", handleList)
+	router.HandleFunc("/lists/{list_id}/items", handleListItems)
+
+	mux.Handle("/v0/meta/stremthru/", http.StripPrefix("/v0/meta/stremthru", commonMiddleware(router)))
+}
@@ -33,3 +33,18 @@ type IdMap struct {
 	Letterboxd string      `json:"lboxd,omitempty"`
 	Anime      *IdMapAnime `json:"anime,omitempty"`
 }
+
+type IdMapEpisodeAniDB struct {
+	Id      string `json:"id"`
+	Episode int    `json:"episode"`
+}
+
+type IdMapEpisodeTVDB struct {
+	Season  int `json:"season"` // -1 for absolute order
+	Episode int `json:"episode"`
+}
+
+type IdMapEpisode struct {
+	AniDB *IdMapEpisodeAniDB `json:"anidb,omitempty"`
+	TVDB  *IdMapEpisodeTVDB  `json:"tvdb,omitempty"`
+}
@@ -4,6 +4,7 @@ type Provider string
 
 const (
 	ProviderLetterboxd Provider = "letterboxd"
+	ProviderStremThru  Provider = "stremthru"
 	ProviderTMDB       Provider = "tmdb"
 	ProviderTVDB       Provider = "tvdb"
 )
@@ -38,14 +39,33 @@ func (ip IdProvider) IsAnime() bool {
 		ip == IdProviderNotifyMoe
 }
 
+func (ip IdProvider) IsValid() bool {
+	switch ip {
+	case IdProviderIMDB,
+		IdProviderTMDB,
+		IdProviderTVDB,
+		IdProviderTVMaze,
+		IdProviderTrakt,
+		IdProviderLetterboxd:
+		return true
+	}
+	return ip.IsAnime()
+}
+
+// RequiresIdType is true for the providers with ids unique only within the
+// type.
+func (ip IdProvider) RequiresIdType() bool {
+	return ip == IdProviderTMDB || ip == IdProviderTVDB || ip == IdProviderTrakt
+}
+
 func GetIdProviderCacheKey(idProvider IdProvider, idType IdType, id string) string {
-	switch idProvider {
-	case IdProviderIMDB:
+	switch {
+	case idProvider == IdProviderIMDB:
 		return id
-	case IdProviderTrakt, IdProviderTVDB:
+	case idProvider.RequiresIdType():
 		return string(idProvider) + ":" + string(idType) + ":" + id
 	default:
-		panic("unsupported id provider: " + string(idProvider))
+		return string(idProvider) + ":" + id
 	}
 }
 
@@ -0,0 +1,163 @@
+package newznab_usenet
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/newznab"
+	newznab_client "github.com/MunifTanjim/stremthru/internal/newznab/client"
+	usenet_indexer "github.com/MunifTanjim/stremthru/internal/usenet/indexer"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/internal/znab"
+)
+
+var (
+	_ newznab_client.Indexer = (*Indexer)(nil)
+)
+
+// Indexer searches the releases assembled from the article headers of the
+// groups scanned by StremThru itself.
+type Indexer struct {
+	user string
+	pass string
+}
+
+func NewIndexer(apiKey string) *Indexer {
+	ba, _ := util.ParseBasicAuth(apiKey)
+	return &Indexer{
+		user: ba.Username,
+		pass: ba.Password,
+	}
+}
+
+var getCaps = sync.OnceValue(func() *znab.Caps {
+	return &znab.Caps{
+		Server: &znab.CapsServer{
+			Title:     "StremThru Usenet",
+			Strapline: "StremThru Usenet Indexer",
+			URL:       config.BaseURL.String(),
+			Version:   "1.0",
+		},
+		Searching: &znab.CapsSearching{
+			Search: &znab.CapsSearchingItem{
+				Available:       true,
+				SupportedParams: []znab.SearchParam{znab.SearchParamQ},
+			},
+			TVSearch: &znab.CapsSearchingItem{
+				Available:       true,
+				SupportedParams: []znab.SearchParam{znab.SearchParamQ, znab.SearchParamIMDBId, znab.SearchParamSeason, znab.SearchParamEp},
+			},
+			MovieSearch: &znab.CapsSearchingItem{
+				Available:       true,
+				SupportedParams: []znab.SearchParam{znab.SearchParamQ, znab.SearchParamIMDBId},
+			},
+		},
+		Categories: []znab.CapsCategory{
+			{Category: newznab.CategoryMovies},
+			{Category: newznab.CategoryTV},
+		},
+	}
+})
+
+func (i *Indexer) GetId() string {
+	return "usenet"
+}
+
+func (i *Indexer) GetHTTPClient() *http.Client {
+	return nil
+}
+
+func (i *Indexer) GetCaps() (znab.Caps, error) {
+	return *getCaps(), nil
+}
+
+func (i *Indexer) isValidAPIKey() bool {
+	password := config.Auth.GetPassword(i.user)
+	return password != "" && password == i.pass
+}
+
+func (i *Indexer) NewSearchQuery(fn func(caps *znab.Caps) newznab_client.Function) (*newznab_client.Query, error) {
+	caps := getCaps()
+	return newznab_client.NewQuery(caps).SetT(fn(caps)), nil
+}
+
+func normalizeNumber(value string) string {
+	if n := util.SafeParseInt(strings.TrimLeft(value, "SsEe"), -1); n >= 0 {
+		return strconv.Itoa(n)
+	}
+	return value
+}
+
+func (i *Indexer) Search(query url.Values, headers http.Header) ([]newznab_client.Newz, int64, error) {
+	if !i.isValidAPIKey() {
+		return nil, 0, fmt.Errorf("invalid credentials")
+	}
+
+	q, err := newznab.ParseQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	params := usenet_indexer.SearchParams{
+		Q:      q.Q,
+		IMDBId: q.IMDBId,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	if q.Season != "" {
+		params.Season = normalizeNumber(q.Season)
+		if q.Ep != "" {
+			params.Episode = normalizeNumber(q.Ep)
+		}
+	}
+	switch {
+	case q.HasMovies() && !q.HasTVShows():
+		params.Categories = []int{newznab.CategoryMovies.ID}
+	case q.HasTVShows() && !q.HasMovies():
+		params.Categories = []int{newznab.CategoryTV.ID}
+	}
+
+	releases, err := usenet_indexer.Search(params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	apikey := util.Base64Encode(i.user + ":" + i.pass)
+	result := make([]newznab_client.Newz, 0, len(releases))
+	for idx := range releases {
+		result = append(result, convertReleaseToNewz(&releases[idx], apikey))
+	}
+	return result, 0, nil
+}
+
+func convertReleaseToNewz(r *usenet_indexer.Release, apikey string) newznab_client.Newz {
+	link := config.BaseURL.JoinPath("/v0/newznab/getnzb", r.Id)
+	linkQuery := link.Query()
+	linkQuery.Set("apikey", apikey)
+	link.RawQuery = linkQuery.Encode()
+
+	return newznab_client.Newz{
+		Title:        r.Name,
+		GUID:         r.Id,
+		PublishDate:  r.Date.Time,
+		Size:         r.Size,
+		Files:        r.Files,
+		Poster:       r.Poster,
+		Group:        r.GroupName,
+		Date:         r.Date.Time,
+		Categories:   []string{strconv.Itoa(r.Category)},
+		IMDB:         r.IMDBId,
+		Season:       r.Season,
+		Episode:      r.Episode,
+		DownloadLink: link.String(),
+		Indexer: newznab_client.ChannelItemIndexer{
+			Host: config.BaseURL.Hostname(),
+			Name: "StremThru Usenet",
+		},
+	}
+}
@@ -2,6 +2,7 @@ package nntp
 
 import (
 	"context"
+	"net/url"
 	"time"
 )
 
@@ -15,6 +16,9 @@ type Client struct {
 	tls           bool
 	tlsSkipVerify bool
 
+	compress bool
+	proxy    *url.URL
+
 	dialTimeout   time.Duration
 	keepAliveTime time.Duration
 
@@ -51,6 +55,9 @@ func NewClient(conf *ClientConfig) *Client {
 		tls:           conf.TLS,
 		tlsSkipVerify: conf.TLSSkipVerify,
 
+		compress: conf.Compress,
+		proxy:    conf.Proxy,
+
 		dialTimeout:   conf.DialTimeout,
 		keepAliveTime: conf.KeepAliveTime,
 	}
@@ -70,6 +77,8 @@ func (c *Client) ConnectContext(ctx context.Context) error {
 		Password:      c.password,
 		TLS:           c.tls,
 		TLSSkipVerify: c.tlsSkipVerify,
+		Compress:      c.compress,
+		Proxy:         c.proxy,
 		DialTimeout:   c.dialTimeout,
 		KeepAliveTime: c.keepAliveTime,
 	})
@@ -87,6 +96,10 @@ func (c *Client) Capabilities() (*Capabilities, error) {
 	return c.conn.Capabilities()
 }
 
+func (c *Client) Compress() error {
+	return c.conn.Compress()
+}
+
 func (c *Client) List(keyword ListKeyword, argument string) ([]string, error) {
 	return c.conn.List(keyword, argument)
 }
@@ -123,10 +136,18 @@ func (c *Client) Body(spec string) (*Article, error) {
 	return c.conn.Body(spec)
 }
 
+func (c *Client) BodyPipelined(specs []string, fn func(idx int, article *Article, err error) error) error {
+	return c.conn.BodyPipelined(specs, fn)
+}
+
 func (c *Client) Stat(spec string) (int64, string, error) {
 	return c.conn.Stat(spec)
 }
 
+func (c *Client) StatPipelined(specs []string) ([]StatResult, error) {
+	return c.conn.StatPipelined(specs)
+}
+
 func (c *Client) Over(rangeSpec string) ([]ArticleOverview, error) {
 	return c.conn.Over(rangeSpec)
 }
@@ -1,6 +1,7 @@
 package nntp_test
 
 import (
+	"net/url"
 	"testing"
 
 	. "github.com/MunifTanjim/stremthru/internal/nntp"
@@ -98,6 +99,116 @@ func TestConnect_AuthenticationFailed(t *testing.T) {
 	assert.Equal(t, ErrorCodeAuthentication, nntpErr.Code, "error code")
 }
 
+func TestConnect_WithCompression(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("CAPABILITIES", "101 Capability list:", []string{
+		"VERSION 2",
+		"READER",
+		"COMPRESS DEFLATE",
+	})
+	server.SetResponse("BODY <a@example.com>", "222 0 <a@example.com>", []string{"first"})
+	server.SetResponse("BODY <b@example.com>", "222 0 <b@example.com>", []string{"second"})
+	server.SetResponse("STAT <a@example.com>", "223 0 <a@example.com>")
+	server.EnableCompression()
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:     server.Host(),
+		Port:     server.Port(),
+		Compress: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.True(t, server.GetRequestCommands().HasCommand("COMPRESS DEFLATE"))
+
+	_, messageId, err := client.Stat("<a@example.com>")
+	assert.NoError(t, err, "Stat()")
+	assert.Equal(t, "<a@example.com>", messageId, "messageId")
+
+	bodies := []string{}
+	err = client.BodyPipelined([]string{"<a@example.com>", "<b@example.com>"}, func(idx int, article *Article, err error) error {
+		assert.NoError(t, err, "err[%d]", idx)
+		content, err := article.Body.ReadAll()
+		assert.NoError(t, err, "ReadAll()")
+		bodies = append(bodies, string(content))
+		return nil
+	})
+	assert.NoError(t, err, "BodyPipelined()")
+	assert.Equal(t, []string{"first\n", "second\n"}, bodies, "bodies")
+}
+
+func TestConnect_CompressionNotAdvertised(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("CAPABILITIES", "101 Capability list:", []string{
+		"VERSION 2",
+		"READER",
+	})
+	server.SetResponse("STAT <a@example.com>", "223 0 <a@example.com>")
+	server.EnableCompression()
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:     server.Host(),
+		Port:     server.Port(),
+		Compress: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.False(t, server.GetRequestCommands().HasCommand("COMPRESS DEFLATE"))
+
+	_, _, err = client.Stat("<a@example.com>")
+	assert.NoError(t, err, "Stat()")
+}
+
+func TestConnect_ThroughProxy(t *testing.T) {
+	for _, proxy := range []*nntptest.Proxy{
+		nntptest.NewHTTPProxy(t),
+		nntptest.NewSOCKS5Proxy(t),
+	} {
+		t.Run(proxy.URL().Scheme, func(t *testing.T) {
+			server := nntptest.NewServer(t, "200 NNTP Service Ready")
+			server.SetResponse("STAT <a@example.com>", "223 0 <a@example.com>")
+			server.Start(t)
+
+			client := NewClient(&ClientConfig{
+				Host:  server.Host(),
+				Port:  server.Port(),
+				Proxy: proxy.URL(),
+			})
+
+			err := client.Connect()
+			assert.NoError(t, err, "Connect()")
+			defer client.Close()
+
+			_, messageId, err := client.Stat("<a@example.com>")
+			assert.NoError(t, err, "Stat()")
+			assert.Equal(t, "<a@example.com>", messageId, "messageId")
+			assert.Equal(t, 1, proxy.TunnelCount(), "proxy.TunnelCount()")
+		})
+	}
+}
+
+func TestConnect_UnsupportedProxy(t *testing.T) {
+	client := NewClient(&ClientConfig{
+		Host:  "127.0.0.1",
+		Port:  119,
+		Proxy: &url.URL{Scheme: "ftp", Host: "127.0.0.1:21"},
+	})
+
+	err := client.Connect()
+	assert.Error(t, err, "Connect()")
+
+	nntpErr, ok := err.(*Error)
+	assert.True(t, ok, "error type should be *Error")
+	assert.Equal(t, ErrorCodeConnection, nntpErr.Code, "error code")
+}
+
 func TestConnect_ConnectionRefused(t *testing.T) {
 	client := NewClient(&ClientConfig{
 		Host: "127.0.0.1",
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"io"
 	"net/textproto"
+	"slices"
 	"time"
 )
 
@@ -16,6 +17,7 @@ const (
 	CommandArticle      Command = "ARTICLE"
 	CommandBody         Command = "BODY"
 	CommandCapabilities Command = "CAPABILITIES"
+	CommandCompress     Command = "COMPRESS"
 	CommandDate         Command = "DATE"
 	CommandGroup        Command = "GROUP"
 	CommandHDR          Command = "HDR"
@@ -83,14 +85,57 @@ type bodyReadCloser struct {
 	textproto.Reader
 	r      *CmdResult
 	closed bool
+
+	pending []byte // rest of the last line, that did not fit in `p`
+	midLine bool   // last line was partially read
+	eof     bool   // dot-terminator line was read
 }
 
 func (r *bodyReadCloser) ReadAll() (body []byte, err error) {
-	return r.Reader.ReadDotBytes()
+	body, err = r.Reader.ReadDotBytes()
+	r.eof = true
+	return body, err
+}
+
+func isDotTerminator(line []byte) bool {
+	return string(line) == ".\r\n" || string(line) == ".\n"
 }
 
+// Read returns the raw body, including the dot-terminator line. It never
+// reads past the terminator, so that the responses of pipelined commands are
+// left untouched.
 func (r *bodyReadCloser) Read(p []byte) (n int, err error) {
-	return r.Reader.R.Read(p)
+	for n < len(p) {
+		if len(r.pending) > 0 {
+			c := copy(p[n:], r.pending)
+			r.pending = r.pending[c:]
+			n += c
+			continue
+		}
+		if r.eof || (n > 0 && r.Reader.R.Buffered() == 0) {
+			break
+		}
+
+		line, err := r.Reader.R.ReadSlice('\n')
+		if len(line) > 0 {
+			if !r.midLine && isDotTerminator(line) {
+				r.eof = true
+			}
+			r.midLine = line[len(line)-1] != '\n'
+			c := copy(p[n:], line)
+			n += c
+			if c < len(line) {
+				r.pending = append(r.pending[:0], line[c:]...)
+			}
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return n, err
+		}
+	}
+	if n == 0 && r.eof {
+		return 0, io.EOF
+	}
+	return n, nil
 }
 
 func (r *bodyReadCloser) Close() error {
@@ -252,6 +297,36 @@ func (c *Connection) Capabilities() (*Capabilities, error) {
 	return parseCapabilities(lines)
 }
 
+// Compress enables DEFLATE compression for both directions of the
+// connection. It should only be used if the server advertises the
+// "COMPRESS DEFLATE" capability, after TLS and authentication.
+//
+// Reference: RFC 8054 Section 2.2 (COMPRESS)
+// https://tools.ietf.org/html/rfc8054#section-2.2
+func (c *Connection) Compress() error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if c.compressed {
+		return nil
+	}
+
+	r := c.cmd(CommandCompress.String(), "DEFLATE")
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	code, message, err := r.readCodeLine(StatusCompressionActive)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	c.conn = textproto.NewConn(newDeflateConn(c.conn))
+	c.compressed = true
+	return nil
+}
+
 // Reference: RFC 3977 Section 7.1 (DATE)
 // https://tools.ietf.org/html/rfc3977#section-7.1
 func (c *Connection) Date() (*time.Time, error) {
@@ -605,6 +680,141 @@ func (c *Connection) Stat(spec string) (int64, string, error) {
 	return parseStatResponseMessage(message)
 }
 
+type StatResult struct {
+	Number    int64
+	MessageId string
+	Err       error
+}
+
+// Maximum commands in flight, so that the server never blocks on writing
+// responses while the client is still writing commands.
+const maxPipelinedCommands = 64
+
+// StatPipelined sends STAT for all `specs` without waiting for the response
+// of the previous one. Per-article failures (e.g. 430) are reported in the
+// matching result, the returned error is only set for connection failures.
+//
+// Reference: RFC 3977 Section 3.5 (Pipelining)
+// https://tools.ietf.org/html/rfc3977#section-3.5
+func (c *Connection) StatPipelined(specs []string) ([]StatResult, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		if err := validateInput(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]StatResult, 0, len(specs))
+	pending := make([]CmdResult, 0, maxPipelinedCommands)
+	for batch := range slices.Chunk(specs, maxPipelinedCommands) {
+		pending = pending[:0]
+		for _, spec := range batch {
+			r := c.cmd(CommandStat.String(), spec)
+			if err := r.Err(); err != nil {
+				return nil, err
+			}
+			pending = append(pending, r)
+		}
+
+		for i := range pending {
+			r := &pending[i]
+			code, message, err := r.readCodeLine(StatusArticleExists)
+			if err != nil {
+				if isConnectionError(err) {
+					return nil, NewCommandError(r.cmd, code, message).WithCause(err)
+				}
+				results = append(results, StatResult{Err: NewCommandError(r.cmd, code, message).WithCause(err)})
+				continue
+			}
+			number, messageId, err := parseStatResponseMessage(message)
+			results = append(results, StatResult{Number: number, MessageId: messageId, Err: err})
+		}
+	}
+
+	return results, nil
+}
+
+// BodyPipelined sends BODY for all `specs` without waiting for the response
+// of the previous one, and calls `fn` with the response of each of them, in
+// the same order. The article body is only valid until `fn` returns, the
+// unread part of it is discarded afterwards.
+//
+// Per-article failures (e.g. 430) are passed to `fn`. If `fn` returns an
+// error, the remaining responses in flight are discarded and no more
+// commands are sent.
+//
+// Reference: RFC 3977 Section 3.5 (Pipelining)
+// https://tools.ietf.org/html/rfc3977#section-3.5
+func (c *Connection) BodyPipelined(specs []string, fn func(idx int, article *Article, err error) error) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		if err := validateInput(spec); err != nil {
+			return err
+		}
+	}
+
+	var fnErr error
+	idx := 0
+	pending := make([]CmdResult, 0, maxPipelinedCommands)
+	for batch := range slices.Chunk(specs, maxPipelinedCommands) {
+		if fnErr != nil {
+			break
+		}
+
+		pending = pending[:0]
+		for _, spec := range batch {
+			r := c.cmd(CommandBody.String(), spec)
+			if err := r.Err(); err != nil {
+				return err
+			}
+			pending = append(pending, r)
+		}
+
+		for i := range pending {
+			r := &pending[i]
+			code, message, body, err := r.readCodeLineAndBody(StatusArticleBody)
+			if err != nil {
+				if isConnectionError(err) {
+					return NewCommandError(r.cmd, code, message).WithCause(err)
+				}
+				if fnErr == nil {
+					fnErr = fn(idx, nil, NewCommandError(r.cmd, code, message).WithCause(err))
+				}
+				idx++
+				continue
+			}
+
+			if fnErr == nil {
+				number, messageId, err := parseArticleResponseMessage(message)
+				if err != nil {
+					fnErr = fn(idx, nil, NewCommandError(r.cmd, code, message).WithCause(err))
+				} else {
+					fnErr = fn(idx, &Article{
+						Number:    number,
+						MessageId: messageId,
+						Body:      body,
+					}, nil)
+				}
+			}
+
+			_, err = io.Copy(io.Discard, body)
+			body.Close()
+			if err != nil {
+				return NewCommandError(r.cmd, code, message).WithCause(err)
+			}
+			idx++
+		}
+	}
+
+	return fnErr
+}
+
 // The `rangeSpec` parameter can be:
 //   - "message-id"
 //   - "range"
@@ -1,6 +1,7 @@
 package nntp_test
 
 import (
+	"io"
 	"testing"
 	"time"
 
@@ -820,6 +821,93 @@ func TestBody_NoSuchNumber(t *testing.T) {
 	assert.Nil(t, article, "article")
 }
 
+func TestBodyPipelined(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("BODY <a@example.com>", "222 0 <a@example.com>", []string{
+		"first article",
+		"..dot-stuffed line",
+	})
+	server.SetResponse("BODY <b@example.com>", "430 No Such Article Found")
+	server.SetResponse("BODY <c@example.com>", "222 0 <c@example.com>", []string{
+		"third article",
+		"left unread",
+	})
+	server.SetResponse("BODY <d@example.com>", "222 0 <d@example.com>", []string{
+		"fourth article",
+	})
+	server.SetResponse("STAT <d@example.com>", "223 0 <d@example.com>")
+	server.ExpectPipelined("BODY", 4)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	bodies := map[int]string{}
+	err = client.BodyPipelined([]string{"<a@example.com>", "<b@example.com>", "<c@example.com>", "<d@example.com>"}, func(idx int, article *Article, err error) error {
+		switch idx {
+		case 1:
+			var nntpErr *Error
+			assert.ErrorAs(t, err, &nntpErr, "err[1]")
+			assert.Equal(t, ErrorCodeNoSuchArticle, nntpErr.Code, "err[1].Code")
+		case 2:
+			assert.NoError(t, err, "err[2]")
+			buf := make([]byte, 5)
+			n, err := article.Body.Read(buf)
+			assert.NoError(t, err, "Read()")
+			bodies[idx] = string(buf[:n])
+		default:
+			assert.NoError(t, err, "err[%d]", idx)
+			content, err := article.Body.ReadAll()
+			assert.NoError(t, err, "ReadAll()")
+			bodies[idx] = string(content)
+		}
+		return nil
+	})
+	assert.NoError(t, err, "BodyPipelined()")
+	assert.Equal(t, map[int]string{
+		0: "first article\n.dot-stuffed line\n",
+		2: "third",
+		3: "fourth article\n",
+	}, bodies, "bodies")
+
+	_, messageId, err := client.Stat("<d@example.com>")
+	assert.NoError(t, err, "Stat() after BodyPipelined()")
+	assert.Equal(t, "<d@example.com>", messageId, "messageId")
+}
+
+func TestBodyPipelined_RawRead(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("BODY <a@example.com>", "222 0 <a@example.com>", []string{"first"})
+	server.SetResponse("BODY <b@example.com>", "222 0 <b@example.com>", []string{"second"})
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	bodies := []string{}
+	err = client.BodyPipelined([]string{"<a@example.com>", "<b@example.com>"}, func(idx int, article *Article, err error) error {
+		assert.NoError(t, err, "err[%d]", idx)
+		content, err := io.ReadAll(article.Body)
+		assert.NoError(t, err, "io.ReadAll()")
+		bodies = append(bodies, string(content))
+		return nil
+	})
+	assert.NoError(t, err, "BodyPipelined()")
+	assert.Equal(t, []string{"first\r\n.\r\n", "second\r\n.\r\n"}, bodies, "bodies")
+}
+
 // TestStat_ByMessageId uses the example from RFC 3977 Section 6.2.4
 func TestStat_ByMessageId(t *testing.T) {
 	server := nntptest.NewServer(t, "200 NNTP Service Ready")
@@ -904,6 +992,38 @@ func TestStat_NoSuchNumber(t *testing.T) {
 	assert.Error(t, err, "Stat()")
 }
 
+func TestStatPipelined(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("STAT <a@example.com>", "223 0 <a@example.com>")
+	server.SetResponse("STAT <b@example.com>", "430 No Such Article Found")
+	server.SetResponse("STAT <c@example.com>", "223 0 <c@example.com>")
+	server.ExpectPipelined("STAT", 3)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	results, err := client.StatPipelined([]string{"<a@example.com>", "<b@example.com>", "<c@example.com>"})
+	assert.NoError(t, err, "StatPipelined()")
+	assert.Len(t, results, 3, "results")
+
+	assert.NoError(t, results[0].Err, "results[0].Err")
+	assert.Equal(t, "<a@example.com>", results[0].MessageId, "results[0].MessageId")
+
+	var nntpErr *Error
+	assert.ErrorAs(t, results[1].Err, &nntpErr, "results[1].Err")
+	assert.Equal(t, ErrorCodeNoSuchArticle, nntpErr.Code, "results[1].Err.Code")
+
+	assert.NoError(t, results[2].Err, "results[2].Err")
+	assert.Equal(t, "<c@example.com>", results[2].MessageId, "results[2].MessageId")
+}
+
 // TestOver uses the example from RFC 3977 Section 8.3
 func TestOver(t *testing.T) {
 	server := nntptest.NewServer(t, "200 NNTP Service Ready")
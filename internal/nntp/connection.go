@@ -5,14 +5,19 @@
 //     https://tools.ietf.org/html/rfc3977
 //   - RFC 4643: Network News Transfer Protocol (NNTP) Extension for Authentication
 //     https://tools.ietf.org/html/rfc4643
+//   - RFC 8054: Network News Transfer Protocol (NNTP) Extension for Compression
+//     https://tools.ietf.org/html/rfc8054
 package nntp
 
 import (
+	"compress/flate"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"net/textproto"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -26,6 +31,12 @@ type ConnectionConfig struct {
 	TLS           bool
 	TLSSkipVerify bool
 
+	// negotiate COMPRESS DEFLATE, if advertised by the server
+	Compress bool
+
+	// socks5://, socks5h://, http:// or https:// proxy to connect through
+	Proxy *url.URL
+
 	Deadline      time.Time
 	DialTimeout   time.Duration
 	KeepAliveTime time.Duration
@@ -46,6 +57,7 @@ type Connection struct {
 
 	connected     bool
 	authenticated bool
+	compressed    bool
 	currentGroup  string
 	staleAt       time.Time
 }
@@ -109,7 +121,12 @@ func (c *Connection) Connect(ctx context.Context, config *ConnectionConfig) erro
 	var conn net.Conn
 	var err error
 
-	if config.TLS {
+	if config.Proxy != nil {
+		conn, err = dialProxy(ctx, config, address)
+		if err != nil {
+			return err
+		}
+	} else if config.TLS {
 		tlsDialer := tls.Dialer{
 			NetDialer: &dialer,
 			Config: &tls.Config{
@@ -166,6 +183,34 @@ func (c *Connection) Connect(ctx context.Context, config *ConnectionConfig) erro
 		}
 	}
 
+	if config.Compress {
+		if err := c.negotiateCompression(); err != nil {
+			c.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// negotiateCompression enables compression if the server advertises it.
+// Failing to enable it is not fatal, unless the connection is broken.
+func (c *Connection) negotiateCompression() error {
+	caps, err := c.Capabilities()
+	if err != nil {
+		if isConnectionError(err) {
+			return err
+		}
+		return nil
+	}
+
+	if !caps.Has("COMPRESS", "DEFLATE") {
+		return nil
+	}
+
+	if err := c.Compress(); err != nil && isConnectionError(err) {
+		return err
+	}
 	return nil
 }
 
@@ -188,6 +233,7 @@ func (c *Connection) Close() error {
 
 	c.connected = false
 	c.authenticated = false
+	c.compressed = false
 	c.currentGroup = ""
 
 	return r.Err()
@@ -217,3 +263,42 @@ func (c *Connection) cmd(cmd string, args ...string) CmdResult {
 	}
 	return result
 }
+
+// deflateConn compresses everything written to, and decompresses everything
+// read from, the underlying connection.
+type deflateConn struct {
+	conn *textproto.Conn
+	r    io.ReadCloser
+	w    *flate.Writer
+}
+
+func newDeflateConn(conn *textproto.Conn) *deflateConn {
+	w, _ := flate.NewWriter(conn.W, flate.DefaultCompression)
+	return &deflateConn{
+		conn: conn,
+		// the buffered reader may already hold compressed data
+		r: flate.NewReader(conn.R),
+		w: w,
+	}
+}
+
+func (c *deflateConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// Write flushes the compressed data right away, as every write is a command.
+func (c *deflateConn) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return n, err
+	}
+	return n, c.conn.W.Flush()
+}
+
+func (c *deflateConn) Close() error {
+	c.r.Close()
+	return c.conn.Close()
+}
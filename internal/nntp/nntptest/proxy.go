@@ -0,0 +1,165 @@
+package nntptest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// Proxy is a minimal HTTP CONNECT or SOCKS5 proxy, without authentication.
+type Proxy struct {
+	listener    net.Listener
+	scheme      string
+	tunnelCount atomic.Int32
+}
+
+func NewHTTPProxy(t *testing.T) *Proxy {
+	return newProxy(t, "http")
+}
+
+func NewSOCKS5Proxy(t *testing.T) *Proxy {
+	return newProxy(t, "socks5")
+}
+
+func newProxy(t *testing.T, scheme string) *Proxy {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create mock proxy: %v", err)
+	}
+
+	p := &Proxy{listener: listener, scheme: scheme}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go p.handleConn(conn)
+		}
+	}()
+
+	return p
+}
+
+func (p *Proxy) URL() *url.URL {
+	return &url.URL{Scheme: p.scheme, Host: p.listener.Addr().String()}
+}
+
+// TunnelCount returns the number of tunnels opened through the proxy.
+func (p *Proxy) TunnelCount() int {
+	return int(p.tunnelCount.Load())
+}
+
+func (p *Proxy) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	var address string
+	var err error
+	if p.scheme == "socks5" {
+		address, err = readSOCKS5Request(reader, conn)
+	} else {
+		address, err = readHTTPConnectRequest(reader, conn)
+	}
+	if err != nil {
+		return
+	}
+
+	target, err := net.Dial("tcp", address)
+	if err != nil {
+		return
+	}
+	defer target.Close()
+
+	p.tunnelCount.Add(1)
+
+	go io.Copy(target, reader)
+	io.Copy(conn, target)
+}
+
+func readHTTPConnectRequest(reader *bufio.Reader, conn net.Conn) (string, error) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return "", err
+	}
+	if req.Method != http.MethodConnect {
+		io.WriteString(conn, "HTTP/1.1 405 Method Not Allowed\r\n\r\n")
+		return "", io.EOF
+	}
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		return "", err
+	}
+	return req.Host, nil
+}
+
+// Reference: RFC 1928 (SOCKS Protocol Version 5)
+func readSOCKS5Request(reader *bufio.Reader, conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return "", err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return "", err
+	}
+	// no authentication required
+	if _, err := conn.Write([]byte{0x05, 0x00}); err != nil {
+		return "", err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return "", err
+	}
+
+	var host string
+	switch request[3] {
+	case 0x01: // IPv4
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 0x03: // domain name
+		length, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		name := make([]byte, length)
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	case 0x04: // IPv6
+		ip := make([]byte, 16)
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return "", err
+	}
+
+	// succeeded, bound to 0.0.0.0:0
+	if _, err := conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0}); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
@@ -2,7 +2,9 @@ package nntptest
 
 import (
 	"bufio"
+	"compress/flate"
 	"fmt"
+	"io"
 	"net"
 	"slices"
 	"strconv"
@@ -31,6 +33,9 @@ type Server struct {
 	responses       map[string][]response
 	responseIndex   map[string]int
 	requestCommands requestCommands
+	compression     bool
+	pipelineCommand string
+	pipelineCount   int
 	mu              sync.RWMutex
 	done            chan struct{}
 }
@@ -128,6 +133,25 @@ func (s *Server) getResponse(command string) (response, bool) {
 	return response{}, false
 }
 
+// EnableCompression makes the server accept "COMPRESS DEFLATE". The
+// "COMPRESS DEFLATE" capability still needs to be set in the response of
+// "CAPABILITIES", if the client checks it.
+func (s *Server) EnableCompression() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compression = true
+}
+
+// ExpectPipelined makes the server hold the responses, starting from the
+// next `command`, until `count` of it are received. So a client waiting for
+// the response of each command before sending the next one never gets any.
+func (s *Server) ExpectPipelined(command string, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pipelineCommand = command
+	s.pipelineCount = count
+}
+
 func (s *Server) GetRequestCommands() requestCommands {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -163,12 +187,28 @@ func (s *Server) Start(t *testing.T) {
 	}()
 }
 
+type flushWriter struct {
+	w *flate.Writer
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, fw.w.Flush()
+}
+
 func (s *Server) handleConn(conn net.Conn) {
 	defer conn.Close()
 
-	fmt.Fprintf(conn, "%s\r\n", s.greeting)
+	var w io.Writer = conn
+
+	fmt.Fprintf(w, "%s\r\n", s.greeting)
 
 	reader := bufio.NewReader(conn)
+	held := []string{}
+	heldCount := 0
 	for {
 		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
 		line, err := reader.ReadString('\n')
@@ -183,24 +223,65 @@ func (s *Server) handleConn(conn net.Conn) {
 
 		s.mu.Lock()
 		s.requestCommands = append(s.requestCommands, line)
+		pipelineCommand, pipelineCount := s.pipelineCommand, s.pipelineCount
+		compression := s.compression
 		s.mu.Unlock()
 
-		if line == "QUIT" {
-			fmt.Fprintf(conn, "205 Connection closing\r\n")
-			return
-		}
-
-		if response, ok := s.getResponse(line); ok {
-			if response.delay > 0 {
-				time.Sleep(response.delay)
+		isPipelineCommand := pipelineCount > 0 && strings.HasPrefix(line, pipelineCommand+" ")
+		if isPipelineCommand || len(held) > 0 {
+			held = append(held, line)
+			if isPipelineCommand {
+				heldCount++
 			}
-			fmt.Fprintf(conn, "%s\r\n", response.statusLine)
-			for _, bodyLine := range response.body {
-				fmt.Fprintf(conn, "%s\r\n", bodyLine)
+			if heldCount < pipelineCount {
+				continue
 			}
-			if response.isMultiLine {
-				fmt.Fprintf(conn, ".\r\n") // dot-terminator
+			s.mu.Lock()
+			s.pipelineCount = 0
+			s.mu.Unlock()
+			heldCount = 0
+			for _, line := range held {
+				if !s.respond(w, line) {
+					return
+				}
 			}
+			held = held[:0]
+			continue
+		}
+
+		if line == "COMPRESS DEFLATE" && compression {
+			fmt.Fprintf(w, "206 Compression active\r\n")
+			reader = bufio.NewReader(flate.NewReader(reader))
+			fw, _ := flate.NewWriter(conn, flate.DefaultCompression)
+			w = flushWriter{fw}
+			continue
+		}
+
+		if !s.respond(w, line) {
+			return
+		}
+	}
+}
+
+// respond writes the response for the command `line`, and returns false if
+// the connection should be closed.
+func (s *Server) respond(w io.Writer, line string) bool {
+	if line == "QUIT" {
+		fmt.Fprintf(w, "205 Connection closing\r\n")
+		return false
+	}
+
+	if response, ok := s.getResponse(line); ok {
+		if response.delay > 0 {
+			time.Sleep(response.delay)
+		}
+		fmt.Fprintf(w, "%s\r\n", response.statusLine)
+		for _, bodyLine := range response.body {
+			fmt.Fprintf(w, "%s\r\n", bodyLine)
+		}
+		if response.isMultiLine {
+			fmt.Fprintf(w, ".\r\n") // dot-terminator
 		}
 	}
+	return true
 }
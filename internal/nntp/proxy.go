@@ -0,0 +1,165 @@
+package nntp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// tcpDialer sets the TCP options on the connection to the proxy, as those
+// can not be set on the tunneled connection.
+type tcpDialer struct {
+	net.Dialer
+	keepAliveTime time.Duration
+}
+
+func (d *tcpDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *tcpDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := setTCPOptions(conn, d.keepAliveTime); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// bufferedConn is a connection with data already read into the buffer.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *bufferedConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// dialProxy connects to the `address` through the proxy, and does the TLS
+// handshake with the NNTP server over the tunnel, if needed.
+func dialProxy(ctx context.Context, config *ConnectionConfig, address string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DialTimeout)
+	defer cancel()
+
+	forward := &tcpDialer{keepAliveTime: config.KeepAliveTime}
+
+	var conn net.Conn
+	var err error
+
+	switch config.Proxy.Scheme {
+	case "socks5", "socks5h":
+		var dialer proxy.Dialer
+		dialer, err = proxy.FromURL(config.Proxy, forward)
+		if err != nil {
+			return nil, NewConnectionError("invalid proxy").WithCause(err)
+		}
+		conn, err = dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	case "http", "https":
+		conn, err = dialHTTPConnect(ctx, forward, config.Proxy, address)
+	default:
+		return nil, NewConnectionError("unsupported proxy scheme: " + config.Proxy.Scheme)
+	}
+	if err != nil {
+		return nil, NewConnectionError("failed to connect through proxy").WithCause(err)
+	}
+
+	if !config.TLS {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         config.Host,
+		InsecureSkipVerify: config.TLSSkipVerify,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, NewConnectionError("failed to connect with TLS through proxy").WithCause(err)
+	}
+	return tlsConn, nil
+}
+
+// dialHTTPConnect opens a tunnel to the `address` with HTTP CONNECT.
+//
+// Reference: RFC 9110 Section 9.3.6 (CONNECT)
+// https://tools.ietf.org/html/rfc9110#section-9.3.6
+func dialHTTPConnect(ctx context.Context, forward *tcpDialer, proxyUrl *url.URL, address string) (net.Conn, error) {
+	proxyAddress := proxyUrl.Host
+	if proxyUrl.Port() == "" {
+		if proxyUrl.Scheme == "https" {
+			proxyAddress = net.JoinHostPort(proxyUrl.Hostname(), "443")
+		} else {
+			proxyAddress = net.JoinHostPort(proxyUrl.Hostname(), "80")
+		}
+	}
+
+	conn, err := forward.DialContext(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyUrl.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: proxyUrl.Hostname(),
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if proxyUrl.User != nil {
+		password, _ := proxyUrl.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyUrl.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// body is not closed, as it would read the tunneled connection
+	if res.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, NewConnectionError("proxy responded with " + res.Status)
+	}
+
+	// the server greeting may already be in the buffer
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
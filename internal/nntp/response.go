@@ -3,6 +3,7 @@ package nntp
 import (
 	"fmt"
 	"net/textproto"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ const (
 	StatusPostingAllowed       = 200 // service available, posting allowed
 	StatusPostingNotAllowed    = 201 // service available, posting prohibited
 	StatusClosingConnection    = 205 // connection closing
+	StatusCompressionActive    = 206 // compression active
 	StatusGroupSelected        = 211 // group selected
 	StatusArticleNumber        = 211 // article numbers follow
 	StatusInformation          = 215 // information follows
@@ -130,6 +132,29 @@ type Capabilities struct {
 	Capabilities []string
 }
 
+// Has checks if the capability `label` is advertised, with all the `args`.
+func (c *Capabilities) Has(label string, args ...string) bool {
+	for _, line := range c.Capabilities {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], label) {
+			continue
+		}
+		hasAll := true
+		for _, arg := range args {
+			if !slices.ContainsFunc(fields[1:], func(f string) bool {
+				return strings.EqualFold(f, arg)
+			}) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			return true
+		}
+	}
+	return false
+}
+
 type NewsGroupActiveTime struct {
 	Name      string
 	CreatedAt time.Time
@@ -0,0 +1,148 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type anilistResponseError struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Status  int    `json:"status"`
+	} `json:"errors,omitempty"`
+}
+
+func (e *anilistResponseError) Error() string {
+	ret, _ := json.Marshal(e)
+	return string(ret)
+}
+
+func (e *anilistResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return core.UnmarshalJSON(res.StatusCode, body, v)
+	default:
+		return fmt.Errorf("unexpected content type: %s", contentType)
+	}
+}
+
+func (r *anilistResponseError) GetError(res *http.Response) error {
+	if r == nil || len(r.Errors) == 0 {
+		return nil
+	}
+	return r
+}
+
+var AniListTokenSourceConfig = TokenSourceConfig{
+	Provider: ProviderAniList,
+	GetUser: func(client *http.Client, oauthConfig *oauth2.Config) (userId, userName string, err error) {
+		body, err := json.Marshal(map[string]string{
+			"query": "query { Viewer { id name } }",
+		})
+		if err != nil {
+			return "", "", err
+		}
+		req, err := http.NewRequest("POST", "https://graphql.anilist.co", bytes.NewReader(body))
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		res, err := client.Do(req)
+		var response struct {
+			anilistResponseError
+			Data struct {
+				Viewer struct {
+					Id   int    `json:"id"`
+					Name string `json:"name"`
+				} `json:"Viewer"`
+			} `json:"data"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return "", "", err
+		}
+		if response.Data.Viewer.Id == 0 {
+			return "", "", errors.New("failed to fetch anilist viewer")
+		}
+
+		return strconv.Itoa(response.Data.Viewer.Id), response.Data.Viewer.Name, nil
+	},
+	PrepareToken: func(tok *oauth2.Token, id, userId, userName string) *oauth2.Token {
+		// anilist tokens are long-lived and do not include scope or created_at
+		return tok.WithExtra(map[string]any{
+			"id":         id,
+			"provider":   ProviderAniList,
+			"user_id":    userId,
+			"user_name":  userName,
+			"scope":      "",
+			"created_at": time.Now(),
+		})
+	},
+}
+
+var anilistOAuthConfig = oauth2.Config{
+	ClientID:     config.Integration.AniList.ClientId,
+	ClientSecret: config.Integration.AniList.ClientSecret,
+	Endpoint: oauth2.Endpoint{
+		AuthURL:   "https://anilist.co/api/v2/oauth/authorize",
+		TokenURL:  "https://anilist.co/api/v2/oauth/token",
+		AuthStyle: oauth2.AuthStyleInParams,
+	},
+	RedirectURL: config.BaseURL.JoinPath("/auth/anilist.co/callback").String(),
+}
+
+var AniListOAuthConfig = OAuthConfig{
+	Config:      anilistOAuthConfig,
+	AuthCodeURL: anilistOAuthConfig.AuthCodeURL,
+	Exchange: func(code, state string) (*oauth2.Token, error) {
+		tok, err := anilistOAuthConfig.Exchange(context.Background(), code)
+		if err != nil {
+			return nil, err
+		}
+
+		anilistLog.Debug("fetching user info for new token")
+		userId, userName, err := AniListTokenSourceConfig.GetUser(
+			oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok)),
+			&anilistOAuthConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existingOTok, err := GetOAuthTokenByUserId(AniListTokenSourceConfig.Provider, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId := uuid.NewString()
+		if existingOTok != nil {
+			tokenId = existingOTok.Id
+		}
+
+		tok = AniListTokenSourceConfig.PrepareToken(tok, tokenId, userId, userName)
+
+		otok := &OAuthToken{}
+		otok = otok.FromToken(tok)
+		err = SaveOAuthToken(otok)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	},
+}
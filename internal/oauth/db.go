@@ -16,8 +16,11 @@ const TableName = "oauth_token"
 type Provider string
 
 const (
+	ProviderAniList    Provider = "anilist.co"
 	ProviderKitsu      Provider = "kitsu.app"
 	ProviderLetterboxd Provider = "letterboxd.com"
+	ProviderMAL        Provider = "myanimelist.net"
+	ProviderSimkl      Provider = "simkl.com"
 	ProviderTMDB       Provider = "themoviedb.org"
 	ProviderTraktTv    Provider = "trakt.tv"
 	ProviderTVDB       Provider = "thetvdb.com"
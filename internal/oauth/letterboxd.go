@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/request"
 	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
@@ -45,10 +47,59 @@ func (r *letterboxdResponseError) GetError(res *http.Response) error {
 	return r
 }
 
+type letterboxdAPIResponseError struct {
+	Err     bool   `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *letterboxdAPIResponseError) Error() string {
+	ret, _ := json.Marshal(e)
+	return string(ret)
+}
+
+func (e *letterboxdAPIResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return core.UnmarshalJSON(res.StatusCode, body, v)
+	default:
+		return fmt.Errorf("unexpected content type: %s", contentType)
+	}
+}
+
+func (r *letterboxdAPIResponseError) GetError(res *http.Response) error {
+	if r == nil || !r.Err {
+		return nil
+	}
+	return r
+}
+
 var LetterboxdTokenSourceConfig = TokenSourceConfig{
 	Provider: ProviderLetterboxd,
 	GetUser: func(client *http.Client, oauthConfig *oauth2.Config) (userId, userName string, err error) {
-		return "", "", errors.New("not implemented")
+		req, err := http.NewRequest("GET", "https://api.letterboxd.com/api/v0/me", nil)
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", config.Integration.Letterboxd.UserAgent)
+		res, err := client.Do(req)
+		var response struct {
+			letterboxdAPIResponseError
+			Member struct {
+				Id       string `json:"id"`
+				Username string `json:"username"`
+			} `json:"member"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return "", "", err
+		}
+		if response.Member.Id == "" {
+			return "", "", errors.New("failed to fetch letterboxd member")
+		}
+
+		return response.Member.Id, response.Member.Username, nil
 	},
 	PrepareToken: func(tok *oauth2.Token, id, userId, userName string) *oauth2.Token {
 		created_at := tok.Extra("created_at")
@@ -74,15 +125,56 @@ var LetterboxdTokenSourceConfig = TokenSourceConfig{
 }
 
 var letterboxdOAuthConfig = oauth2.Config{
+	ClientID:     config.Integration.Letterboxd.ClientId,
+	ClientSecret: config.Integration.Letterboxd.ClientSecret,
 	Endpoint: oauth2.Endpoint{
 		AuthURL:   "https://api.letterboxd.com/api/v0/auth/authorize",
 		TokenURL:  "https://api.letterboxd.com/api/v0/auth/token",
 		AuthStyle: oauth2.AuthStyleInParams,
 	},
+	RedirectURL: config.BaseURL.JoinPath("/auth/letterboxd.com/callback").String(),
+	Scopes:      []string{"content:modify"},
 }
 
 var LetterboxdOAuthConfig = OAuthConfig{
-	Config: letterboxdOAuthConfig,
+	Config:      letterboxdOAuthConfig,
+	AuthCodeURL: letterboxdOAuthConfig.AuthCodeURL,
+	Exchange: func(code, state string) (*oauth2.Token, error) {
+		tok, err := letterboxdOAuthConfig.Exchange(context.Background(), code)
+		if err != nil {
+			return nil, err
+		}
+
+		letterboxdLog.Debug("fetching user info for new token")
+		userId, userName, err := LetterboxdTokenSourceConfig.GetUser(
+			oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok)),
+			&letterboxdOAuthConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existingOTok, err := GetOAuthTokenByUserId(LetterboxdTokenSourceConfig.Provider, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId := uuid.NewString()
+		if existingOTok != nil {
+			tokenId = existingOTok.Id
+		}
+
+		tok = LetterboxdTokenSourceConfig.PrepareToken(tok, tokenId, userId, userName)
+
+		otok := &OAuthToken{}
+		otok = otok.FromToken(tok)
+		err = SaveOAuthToken(otok)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	},
 	ClientCredentialsToken: func(clientId, clientSecret string) (*oauth2.Token, error) {
 		// db level advisory lock to prevent race condition in multi-node deployment
 		if lock := db.NewAdvisoryLock("oauth", "token:client-credentials", clientId); lock == nil {
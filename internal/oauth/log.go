@@ -5,6 +5,9 @@ import (
 )
 
 var log = logger.Scoped("oauth")
+var anilistLog = logger.Scoped("oauth/anilist")
+var malLog = logger.Scoped("oauth/mal")
+var simklLog = logger.Scoped("oauth/simkl")
 var traktLog = logger.Scoped("oauth/trakt")
 var kitsuLog = logger.Scoped("oauth/kitsu")
 var tokenSourceLog = logger.Scoped("oauth/token_source")
@@ -0,0 +1,146 @@
+package oauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type malResponseError struct {
+	Err     string `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *malResponseError) Error() string {
+	ret, _ := json.Marshal(e)
+	return string(ret)
+}
+
+func (e *malResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return core.UnmarshalJSON(res.StatusCode, body, v)
+	default:
+		return fmt.Errorf("unexpected content type: %s", contentType)
+	}
+}
+
+func (r *malResponseError) GetError(res *http.Response) error {
+	if r == nil || r.Err == "" {
+		return nil
+	}
+	return r
+}
+
+var MALTokenSourceConfig = TokenSourceConfig{
+	Provider: ProviderMAL,
+	GetUser: func(client *http.Client, oauthConfig *oauth2.Config) (userId, userName string, err error) {
+		req, err := http.NewRequest("GET", "https://api.myanimelist.net/v2/users/@me", nil)
+		if err != nil {
+			return "", "", err
+		}
+		res, err := client.Do(req)
+		var response struct {
+			malResponseError
+			Id   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return "", "", err
+		}
+
+		return strconv.Itoa(response.Id), response.Name, nil
+	},
+	PrepareToken: func(tok *oauth2.Token, id, userId, userName string) *oauth2.Token {
+		return tok.WithExtra(map[string]any{
+			"id":         id,
+			"provider":   ProviderMAL,
+			"user_id":    userId,
+			"user_name":  userName,
+			"scope":      "",
+			"created_at": time.Now(),
+		})
+	},
+}
+
+var malOAuthConfig = oauth2.Config{
+	ClientID:     config.Integration.MAL.ClientId,
+	ClientSecret: config.Integration.MAL.ClientSecret,
+	Endpoint: oauth2.Endpoint{
+		AuthURL:   "https://myanimelist.net/v1/oauth2/authorize",
+		TokenURL:  "https://myanimelist.net/v1/oauth2/token",
+		AuthStyle: oauth2.AuthStyleInParams,
+	},
+	RedirectURL: config.BaseURL.JoinPath("/auth/myanimelist.net/callback").String(),
+}
+
+// MAL only supports the `plain` PKCE method, so the code verifier is derived
+// from the state instead of being stored between the redirect and callback.
+func getMALCodeVerifier(state string) string {
+	mac := hmac.New(sha256.New, []byte(malOAuthConfig.ClientSecret))
+	mac.Write([]byte(state))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var MALOAuthConfig = OAuthConfig{
+	Config: malOAuthConfig,
+	AuthCodeURL: func(state string, opts ...oauth2.AuthCodeOption) string {
+		opts = append(
+			opts,
+			oauth2.SetAuthURLParam("code_challenge", getMALCodeVerifier(state)),
+			oauth2.SetAuthURLParam("code_challenge_method", "plain"),
+		)
+		return malOAuthConfig.AuthCodeURL(state, opts...)
+	},
+	Exchange: func(code, state string) (*oauth2.Token, error) {
+		tok, err := malOAuthConfig.Exchange(context.Background(), code, oauth2.VerifierOption(getMALCodeVerifier(state)))
+		if err != nil {
+			return nil, err
+		}
+
+		malLog.Debug("fetching user info for new token")
+		userId, userName, err := MALTokenSourceConfig.GetUser(
+			oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok)),
+			&malOAuthConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existingOTok, err := GetOAuthTokenByUserId(MALTokenSourceConfig.Provider, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId := uuid.NewString()
+		if existingOTok != nil {
+			tokenId = existingOTok.Id
+		}
+
+		tok = MALTokenSourceConfig.PrepareToken(tok, tokenId, userId, userName)
+
+		otok := &OAuthToken{}
+		otok = otok.FromToken(tok)
+		err = SaveOAuthToken(otok)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	},
+}
@@ -0,0 +1,139 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type simklResponseError struct {
+	Err     string `json:"error"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *simklResponseError) Error() string {
+	ret, _ := json.Marshal(e)
+	return string(ret)
+}
+
+func (e *simklResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return core.UnmarshalJSON(res.StatusCode, body, v)
+	default:
+		return fmt.Errorf("unexpected content type: %s", contentType)
+	}
+}
+
+func (r *simklResponseError) GetError(res *http.Response) error {
+	if r == nil || r.Err == "" {
+		return nil
+	}
+	return r
+}
+
+var SimklTokenSourceConfig = TokenSourceConfig{
+	Provider: ProviderSimkl,
+	GetUser: func(client *http.Client, oauthConfig *oauth2.Config) (userId, userName string, err error) {
+		req, err := http.NewRequest("POST", "https://api.simkl.com/users/settings", nil)
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("simkl-api-key", oauthConfig.ClientID)
+		res, err := client.Do(req)
+		var response struct {
+			simklResponseError
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+			Account struct {
+				Id int `json:"id"`
+			} `json:"account"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return "", "", err
+		}
+		if response.Account.Id == 0 {
+			return "", "", errors.New("failed to fetch simkl account")
+		}
+
+		return strconv.Itoa(response.Account.Id), response.User.Name, nil
+	},
+	PrepareToken: func(tok *oauth2.Token, id, userId, userName string) *oauth2.Token {
+		// simkl tokens do not expire and do not include created_at
+		return tok.WithExtra(map[string]any{
+			"id":         id,
+			"provider":   ProviderSimkl,
+			"user_id":    userId,
+			"user_name":  userName,
+			"scope":      "",
+			"created_at": time.Now(),
+		})
+	},
+}
+
+var simklOAuthConfig = oauth2.Config{
+	ClientID:     config.Integration.Simkl.ClientId,
+	ClientSecret: config.Integration.Simkl.ClientSecret,
+	Endpoint: oauth2.Endpoint{
+		AuthURL:   "https://simkl.com/oauth/authorize",
+		TokenURL:  "https://api.simkl.com/oauth/token",
+		AuthStyle: oauth2.AuthStyleInParams,
+	},
+	RedirectURL: config.BaseURL.JoinPath("/auth/simkl.com/callback").String(),
+}
+
+var SimklOAuthConfig = OAuthConfig{
+	Config:      simklOAuthConfig,
+	AuthCodeURL: simklOAuthConfig.AuthCodeURL,
+	Exchange: func(code, state string) (*oauth2.Token, error) {
+		tok, err := simklOAuthConfig.Exchange(context.Background(), code)
+		if err != nil {
+			return nil, err
+		}
+
+		simklLog.Debug("fetching user info for new token")
+		userId, userName, err := SimklTokenSourceConfig.GetUser(
+			oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok)),
+			&simklOAuthConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existingOTok, err := GetOAuthTokenByUserId(SimklTokenSourceConfig.Provider, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId := uuid.NewString()
+		if existingOTok != nil {
+			tokenId = existingOTok.Id
+		}
+
+		tok = SimklTokenSourceConfig.PrepareToken(tok, tokenId, userId, userName)
+
+		otok := &OAuthToken{}
+		otok = otok.FromToken(tok)
+		err = SaveOAuthToken(otok)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	},
+}
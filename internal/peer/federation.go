@@ -0,0 +1,558 @@
+package peer
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/MunifTanjim/stremthru/store"
+)
+
+var federationLog = logger.Scoped("peer:federation")
+
+// peers with trust below this are treated like stores with untrusted data,
+// i.e. their data is served but never persisted locally.
+const minTrust = 50
+
+// weight of the latest request in the smoothed error rate of a peer.
+const errorRateSmoothing = 0.1
+
+// after the first successful response, slower peers get this much time to
+// respond before the request is settled without them.
+const hedgeDelay = 2 * time.Second
+
+type FederationPeer struct {
+	Name string
+	// configured trust, see EffectiveTrust
+	Trust int
+
+	client        *APIClient
+	pullClient    *APIClient
+	pullLocalOnly atomic.Bool
+	canPush       bool
+
+	mu            sync.Mutex
+	requestCount  int64
+	errorCount    int64
+	errorRate     float64 // smoothed, for recent requests
+	totalDuration time.Duration
+	lastSuccessAt time.Time
+	lastErrorAt   time.Time
+	lastError     string
+}
+
+// EffectiveTrust is the configured trust, lowered by the recent error rate of
+// the peer, e.g. a peer with trust 100 failing half of the recent requests
+// is not trusted anymore.
+func (p *FederationPeer) EffectiveTrust() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.effectiveTrust()
+}
+
+func (p *FederationPeer) effectiveTrust() int {
+	return int(float64(p.Trust) * (1 - p.errorRate))
+}
+
+func (p *FederationPeer) IsTrusted() bool {
+	return p.EffectiveTrust() >= minTrust
+}
+
+func (p *FederationPeer) record(duration time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requestCount++
+	p.totalDuration += duration
+	failed := 0.0
+	if err != nil {
+		failed = 1
+		p.errorCount++
+		p.lastErrorAt = time.Now()
+		p.lastError = err.Error()
+	} else {
+		p.lastSuccessAt = time.Now()
+	}
+	p.errorRate = errorRateSmoothing*failed + (1-errorRateSmoothing)*p.errorRate
+}
+
+type FederationPeerHealth struct {
+	Name          string     `json:"name"`
+	Trust         int        `json:"trust"`
+	BaseTrust     int        `json:"base_trust"`
+	IsTrusted     bool       `json:"is_trusted"`
+	IsHalted      bool       `json:"is_halted"`
+	RequestCount  int64      `json:"request_count"`
+	ErrorCount    int64      `json:"error_count"`
+	ErrorRate     float64    `json:"error_rate"`
+	AvgDurationMs float64    `json:"avg_duration_ms"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	LastErrorAt   *time.Time `json:"last_error_at"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+func (p *FederationPeer) Health() FederationPeerHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	trust := p.effectiveTrust()
+	health := FederationPeerHealth{
+		Name:         p.Name,
+		Trust:        trust,
+		BaseTrust:    p.Trust,
+		IsTrusted:    trust >= minTrust,
+		IsHalted:     p.client.IsHaltedCheckMagnet(),
+		RequestCount: p.requestCount,
+		ErrorCount:   p.errorCount,
+		LastError:    p.lastError,
+	}
+	if p.requestCount > 0 {
+		health.ErrorRate = 100 * float64(p.errorCount) / float64(p.requestCount)
+		health.AvgDurationMs = float64(p.totalDuration.Milliseconds()) / float64(p.requestCount)
+	}
+	if !p.lastSuccessAt.IsZero() {
+		health.LastSuccessAt = &p.lastSuccessAt
+	}
+	if !p.lastErrorAt.IsZero() {
+		health.LastErrorAt = &p.lastErrorAt
+	}
+	return health
+}
+
+type federation struct {
+	peers []*FederationPeer
+}
+
+func getPeerName(baseUrl string) string {
+	if u, err := url.Parse(baseUrl); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return baseUrl
+}
+
+// Federation contains the upstream peer (`STREMTHRU_PEER_URI`) followed by
+// the peers from `STREMTHRU_PEER_FEDERATION`.
+var Federation = func() *federation {
+	f := &federation{}
+	if config.HasPeer {
+		client := NewAPIClient(&APIClientConfig{
+			BaseURL: config.PeerURL,
+			APIKey:  config.PeerAuthToken,
+		})
+		p := &FederationPeer{
+			Name:    getPeerName(config.PeerURL),
+			Trust:   100,
+			client:  client,
+			canPush: config.PeerAuthToken != "",
+		}
+		if config.PullPeerURL != "" {
+			p.pullClient = NewAPIClient(&APIClientConfig{
+				BaseURL: config.PullPeerURL,
+			})
+			p.pullLocalOnly.Store(true)
+		} else {
+			p.pullClient = NewAPIClient(&APIClientConfig{
+				BaseURL: config.PeerURL,
+			})
+		}
+		f.peers = append(f.peers, p)
+	} else if config.PullPeerURL != "" {
+		p := &FederationPeer{
+			Name:       getPeerName(config.PullPeerURL),
+			Trust:      100,
+			client:     NewAPIClient(&APIClientConfig{}),
+			pullClient: NewAPIClient(&APIClientConfig{BaseURL: config.PullPeerURL}),
+		}
+		p.pullLocalOnly.Store(true)
+		f.peers = append(f.peers, p)
+	}
+	for _, peer := range config.FederationPeers {
+		client := NewAPIClient(&APIClientConfig{
+			BaseURL: peer.URL,
+			APIKey:  peer.AuthToken,
+		})
+		p := &FederationPeer{
+			Name:       getPeerName(peer.URL),
+			Trust:      peer.Trust,
+			client:     client,
+			pullClient: client,
+			canPush:    peer.AuthToken != "",
+		}
+		// federation peers are always queried for their local data,
+		// otherwise instances peering with each other would loop.
+		p.pullLocalOnly.Store(true)
+		f.peers = append(f.peers, p)
+	}
+	return f
+}()
+
+func (f *federation) Peers() []*FederationPeer {
+	return f.peers
+}
+
+// HasPeers reports if there is any peer to check magnets with.
+func (f *federation) HasPeers() bool {
+	return slices.ContainsFunc(f.peers, func(p *FederationPeer) bool {
+		return p.client.BaseURL != nil
+	})
+}
+
+func (f *federation) HasPullPeers() bool {
+	return slices.ContainsFunc(f.peers, func(p *FederationPeer) bool {
+		return p.pullClient != nil
+	})
+}
+
+func (f *federation) CanPush() bool {
+	return slices.ContainsFunc(f.peers, func(p *FederationPeer) bool {
+		return p.canPush
+	})
+}
+
+func (f *federation) IsHaltedCheckMagnet() bool {
+	for _, p := range f.peers {
+		if p.client.BaseURL != nil && !p.client.IsHaltedCheckMagnet() {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPullLocalOnly makes the upstream peer return only its local data, used
+// when a loop is detected.
+func (f *federation) SetPullLocalOnly() {
+	for _, p := range f.peers {
+		p.pullLocalOnly.Store(true)
+	}
+}
+
+type hedgeResult[T any] struct {
+	peer *FederationPeer
+	data T
+	err  error
+	// trust of the peer, after the response is recorded
+	trusted bool
+}
+
+// hedge calls fn for the peers in parallel, and returns when every peer has
+// responded, or hedgeDelay after the first successful response.
+func hedge[T any](peers []*FederationPeer, fn func(p *FederationPeer) (T, error)) []hedgeResult[T] {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	resultCh := make(chan hedgeResult[T], len(peers))
+	for _, p := range peers {
+		go func() {
+			start := time.Now()
+			data, err := fn(p)
+			p.record(time.Since(start), err)
+			resultCh <- hedgeResult[T]{peer: p, data: data, err: err, trusted: p.IsTrusted()}
+		}()
+	}
+
+	results := make([]hedgeResult[T], 0, len(peers))
+	var deadline <-chan time.Time
+	for len(results) < len(peers) {
+		select {
+		case result := <-resultCh:
+			results = append(results, result)
+			if result.err == nil && deadline == nil {
+				deadline = time.After(hedgeDelay)
+			}
+		case <-deadline:
+			return results
+		}
+	}
+	return results
+}
+
+type FederationCheckMagnetParams struct {
+	StoreName  store.StoreName
+	StoreToken string
+	Hashes     []string
+	ClientIP   string
+	SId        string
+}
+
+type FederationCheckMagnetData struct {
+	Items []store.CheckMagnetDataItem
+	// hashes for items with data only from untrusted peers
+	Untrusted map[string]bool
+}
+
+func getCheckMagnetItemRank(item *store.CheckMagnetDataItem, trusted bool) int {
+	rank := 0
+	if item.Status == store.MagnetStatusCached {
+		rank += 2
+	}
+	if trusted {
+		rank += 1
+	}
+	return rank
+}
+
+func (f *federation) CheckMagnet(params *FederationCheckMagnetParams) *FederationCheckMagnetData {
+	peers := []*FederationPeer{}
+	for _, p := range f.peers {
+		if p.client.BaseURL != nil && !p.client.IsHaltedCheckMagnet() {
+			peers = append(peers, p)
+		}
+	}
+
+	results := hedge(peers, func(p *FederationPeer) ([]store.CheckMagnetDataItem, error) {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var errs []error
+		items := []store.CheckMagnetDataItem{}
+		for cHashes := range slices.Chunk(params.Hashes, 500) {
+			wg.Go(func() {
+				cParams := &CheckMagnetParams{
+					StoreName:  params.StoreName,
+					StoreToken: params.StoreToken,
+				}
+				cParams.Magnets = cHashes
+				cParams.ClientIP = params.ClientIP
+				cParams.SId = params.SId
+				start := time.Now()
+				res, err := p.client.CheckMagnet(cParams)
+				duration := time.Since(start)
+				if duration.Seconds() > 10 {
+					p.client.HaltCheckMagnet()
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					federationLog.Error("failed partially to check magnet", "peer.name", p.Name, "store.name", params.StoreName, "error", core.PackError(err), "duration", duration)
+					errs = append(errs, err)
+					return
+				}
+				federationLog.Info("check magnet", "peer.name", p.Name, "store.name", params.StoreName, "hash_count", len(cHashes), "duration", duration)
+				items = append(items, res.Data.Items...)
+			})
+		}
+		wg.Wait()
+		if len(items) == 0 && len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return items, nil
+	})
+
+	return mergeCheckMagnetResults(results)
+}
+
+// mergeCheckMagnetResults merges the items from the peers. For the same
+// hash, cached is preferred over not cached, then trusted over untrusted,
+// then the earlier result.
+func mergeCheckMagnetResults(results []hedgeResult[[]store.CheckMagnetDataItem]) *FederationCheckMagnetData {
+	data := &FederationCheckMagnetData{
+		Items:     []store.CheckMagnetDataItem{},
+		Untrusted: map[string]bool{},
+	}
+	idxByHash := map[string]int{}
+	rankByHash := map[string]int{}
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		trusted := result.trusted
+		for i := range result.data {
+			item := &result.data[i]
+			rank := getCheckMagnetItemRank(item, trusted)
+			if idx, seen := idxByHash[item.Hash]; !seen {
+				idxByHash[item.Hash] = len(data.Items)
+				data.Items = append(data.Items, *item)
+			} else if rank > rankByHash[item.Hash] {
+				data.Items[idx] = *item
+			} else {
+				continue
+			}
+			rankByHash[item.Hash] = rank
+			if trusted {
+				delete(data.Untrusted, item.Hash)
+			} else {
+				data.Untrusted[item.Hash] = true
+			}
+		}
+	}
+	return data
+}
+
+type FederationListTorrentsData struct {
+	Items []torrent_info.TorrentItem
+	// hashes for items only from untrusted peers
+	Untrusted map[string]bool
+}
+
+func (f *federation) ListTorrents(sid string, originInstanceId string) (*FederationListTorrentsData, error) {
+	peers := []*FederationPeer{}
+	for _, p := range f.peers {
+		if p.pullClient != nil && !p.pullClient.IsHaltedCheckMagnet() {
+			peers = append(peers, p)
+		}
+	}
+
+	results := hedge(peers, func(p *FederationPeer) ([]torrent_info.TorrentItem, error) {
+		start := time.Now()
+		res, err := p.pullClient.ListTorrents(&ListTorrentsByStremIdParams{
+			SId:              sid,
+			LocalOnly:        p.pullLocalOnly.Load(),
+			OriginInstanceId: originInstanceId,
+		})
+		duration := time.Since(start)
+		if err != nil {
+			if duration > 25*time.Second {
+				p.pullClient.HaltCheckMagnet()
+			}
+			federationLog.Error("failed to pull torrents", "peer.name", p.Name, "error", core.PackError(err), "duration", duration, "sid", sid)
+			return nil, err
+		}
+		federationLog.Info("pulled torrents", "peer.name", p.Name, "duration", duration, "sid", sid, "count", len(res.Data.Items))
+		return res.Data.Items, nil
+	})
+
+	return mergeListTorrentsResults(results)
+}
+
+// mergeListTorrentsResults merges the items from the peers. For the same
+// hash, trusted is preferred over untrusted, then the earlier result. It
+// fails only if every peer failed.
+func mergeListTorrentsResults(results []hedgeResult[[]torrent_info.TorrentItem]) (*FederationListTorrentsData, error) {
+	data := &FederationListTorrentsData{
+		Items:     []torrent_info.TorrentItem{},
+		Untrusted: map[string]bool{},
+	}
+	var errs []error
+	idxByHash := map[string]int{}
+	for _, result := range results {
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+		trusted := result.trusted
+		for i := range result.data {
+			item := &result.data[i]
+			if idx, seen := idxByHash[item.Hash]; !seen {
+				idxByHash[item.Hash] = len(data.Items)
+				data.Items = append(data.Items, *item)
+			} else if trusted && data.Untrusted[item.Hash] {
+				data.Items[idx] = *item
+			} else {
+				continue
+			}
+			if trusted {
+				delete(data.Untrusted, item.Hash)
+			} else {
+				data.Untrusted[item.Hash] = true
+			}
+		}
+	}
+	if len(errs) == len(results) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return data, nil
+}
+
+func (f *federation) TrackMagnet(params *TrackMagnetParams) {
+	for _, p := range f.peers {
+		if !p.canPush {
+			continue
+		}
+		start := time.Now()
+		_, err := p.client.TrackMagnet(params)
+		duration := time.Since(start)
+		p.record(duration, err)
+		if err != nil {
+			federationLog.Error("failed to track magnet cache", "peer.name", p.Name, "store.name", params.StoreName, "error", core.PackError(err), "hash_count", len(params.TorrentInfos), "duration", duration)
+		} else {
+			federationLog.Info("track magnet cache", "peer.name", p.Name, "store.name", params.StoreName, "hash_count", len(params.TorrentInfos), "duration", duration)
+		}
+	}
+}
+
+var pushedTorrentCache = cache.NewCache[bool](&cache.CacheConfig{
+	Lifetime: 24 * time.Hour,
+	Name:     "peer:federation:pushed-torrent",
+	MaxSize:  100000,
+})
+
+func getPushedTorrentCacheKey(p *FederationPeer, item *torrent_info.TorrentItem) string {
+	return p.Name + ":" + item.Hash + ":" + strconv.Itoa(len(item.Files))
+}
+
+// PushTorrents pushes the items to every peer, skipping the ones that were
+// already pushed to the peer.
+func (f *federation) PushTorrents(items []torrent_info.TorrentItem) error {
+	var errs []error
+	for _, p := range f.peers {
+		if !p.canPush {
+			continue
+		}
+
+		delta := []torrent_info.TorrentItem{}
+		for i := range items {
+			if !pushedTorrentCache.Has(getPushedTorrentCacheKey(p, &items[i])) {
+				delta = append(delta, items[i])
+			}
+		}
+		if len(delta) == 0 {
+			continue
+		}
+
+		start := time.Now()
+		_, err := p.client.PushTorrents(&PushTorrentsParams{Items: delta})
+		duration := time.Since(start)
+		p.record(duration, err)
+		if err != nil {
+			federationLog.Error("failed to push torrents", "peer.name", p.Name, "error", core.PackError(err), "duration", duration, "count", len(delta))
+			errs = append(errs, err)
+			continue
+		}
+		federationLog.Info("pushed torrents", "peer.name", p.Name, "duration", duration, "count", len(delta))
+		for i := range delta {
+			pushedTorrentCache.Add(getPushedTorrentCacheKey(p, &delta[i]), true)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SyncPeers returns the trusted peers with auth token, i.e. the ones allowed
+// to read the torrent change feed from and persist the result locally.
+func (f *federation) SyncPeers() []*FederationPeer {
+	peers := []*FederationPeer{}
+	for _, p := range f.peers {
+		if p.canPush && p.IsTrusted() {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+func (p *FederationPeer) ListTorrentChanges(cursor string, limit int) (*torrent_info.Changes, error) {
+	start := time.Now()
+	changes, err := p.client.ListTorrentChanges(&ListTorrentChangesParams{
+		Cursor: cursor,
+		Limit:  limit,
+	})
+	p.record(time.Since(start), err)
+	return changes, err
+}
+
+func (f *federation) GetHealth() []FederationPeerHealth {
+	health := make([]FederationPeerHealth, len(f.peers))
+	for i, p := range f.peers {
+		health[i] = p.Health()
+	}
+	return health
+}
@@ -0,0 +1,160 @@
+package peer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedge(t *testing.T) {
+	fast := &FederationPeer{Name: "fast"}
+	failing := &FederationPeer{Name: "failing"}
+	slow := &FederationPeer{Name: "slow"}
+
+	start := time.Now()
+	results := hedge([]*FederationPeer{fast, failing, slow}, func(p *FederationPeer) (string, error) {
+		switch p {
+		case failing:
+			return "", errors.New("failed")
+		case slow:
+			time.Sleep(hedgeDelay + time.Second)
+		}
+		return p.Name, nil
+	})
+
+	assert.Less(t, time.Since(start), hedgeDelay+time.Second)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		switch result.peer {
+		case fast:
+			assert.Equal(t, "fast", result.data)
+			assert.NoError(t, result.err)
+		case failing:
+			assert.Error(t, result.err)
+		default:
+			t.Errorf("unexpected result from %s", result.peer.Name)
+		}
+	}
+	assert.Equal(t, int64(1), failing.errorCount)
+}
+
+func TestFederationPeerEffectiveTrust(t *testing.T) {
+	p := &FederationPeer{Name: "peer", Trust: 100}
+	assert.Equal(t, 100, p.EffectiveTrust())
+	assert.True(t, p.IsTrusted())
+
+	for range 10 {
+		p.record(time.Second, errors.New("failed"))
+	}
+	assert.Less(t, p.EffectiveTrust(), minTrust)
+	assert.False(t, p.IsTrusted(), "untrusted while failing")
+
+	for range 20 {
+		p.record(time.Second, nil)
+	}
+	assert.GreaterOrEqual(t, p.EffectiveTrust(), minTrust)
+	assert.True(t, p.IsTrusted(), "trusted again once recovered")
+
+	low := &FederationPeer{Name: "low", Trust: 40}
+	low.record(time.Second, nil)
+	assert.False(t, low.IsTrusted(), "not above the configured trust")
+}
+
+func TestMergeCheckMagnetResults(t *testing.T) {
+	trusted := &FederationPeer{Name: "trusted", Trust: 100}
+	untrusted := &FederationPeer{Name: "untrusted", Trust: 40}
+
+	item := func(hash string, status store.MagnetStatus, name string) store.CheckMagnetDataItem {
+		return store.CheckMagnetDataItem{Hash: hash, Status: status, Name: name}
+	}
+
+	data := mergeCheckMagnetResults([]hedgeResult[[]store.CheckMagnetDataItem]{
+		{peer: untrusted, trusted: false, data: []store.CheckMagnetDataItem{
+			item("a", store.MagnetStatusCached, "untrusted"),
+			item("b", store.MagnetStatusCached, "untrusted"),
+			item("c", store.MagnetStatusUnknown, "untrusted"),
+			item("d", store.MagnetStatusCached, "untrusted"),
+		}},
+		{peer: trusted, trusted: true, err: errors.New("failed"), data: []store.CheckMagnetDataItem{
+			item("d", store.MagnetStatusCached, "failed"),
+		}},
+		{peer: trusted, trusted: true, data: []store.CheckMagnetDataItem{
+			item("a", store.MagnetStatusCached, "trusted"),
+			item("b", store.MagnetStatusUnknown, "trusted"),
+			item("c", store.MagnetStatusUnknown, "trusted"),
+			item("e", store.MagnetStatusUnknown, "trusted"),
+		}},
+	})
+
+	names := map[string]string{}
+	hashes := []string{}
+	for _, item := range data.Items {
+		hashes = append(hashes, item.Hash)
+		names[item.Hash] = item.Name
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, hashes, "in the order first seen")
+	assert.Equal(t, map[string]string{
+		"a": "trusted",   // trusted cached over untrusted cached
+		"b": "untrusted", // cached over trusted not cached
+		"c": "trusted",   // trusted over untrusted
+		"d": "untrusted", // failed results are skipped
+		"e": "trusted",
+	}, names)
+	assert.Equal(t, map[string]bool{"b": true, "d": true}, data.Untrusted)
+}
+
+func TestMergeListTorrentsResults(t *testing.T) {
+	trusted := &FederationPeer{Name: "trusted", Trust: 100}
+	untrusted := &FederationPeer{Name: "untrusted", Trust: 40}
+
+	item := func(hash string, name string) torrent_info.TorrentItem {
+		return torrent_info.TorrentItem{Hash: hash, TorrentTitle: name}
+	}
+
+	t.Run("merged", func(t *testing.T) {
+		data, err := mergeListTorrentsResults([]hedgeResult[[]torrent_info.TorrentItem]{
+			{peer: untrusted, trusted: false, data: []torrent_info.TorrentItem{
+				item("a", "untrusted"),
+				item("b", "untrusted"),
+			}},
+			{peer: trusted, trusted: true, err: errors.New("failed")},
+			{peer: trusted, trusted: true, data: []torrent_info.TorrentItem{
+				item("a", "trusted"),
+				item("c", "trusted"),
+			}},
+			{peer: trusted, trusted: true, data: []torrent_info.TorrentItem{
+				item("c", "trusted again"),
+			}},
+		})
+		assert.NoError(t, err)
+
+		names := map[string]string{}
+		for _, item := range data.Items {
+			names[item.Hash] = item.TorrentTitle
+		}
+		assert.Equal(t, map[string]string{
+			"a": "trusted",
+			"b": "untrusted",
+			"c": "trusted",
+		}, names)
+		assert.Equal(t, map[string]bool{"b": true}, data.Untrusted)
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		_, err := mergeListTorrentsResults([]hedgeResult[[]torrent_info.TorrentItem]{
+			{peer: trusted, trusted: true, err: errors.New("failed")},
+			{peer: untrusted, trusted: false, err: errors.New("failed")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("no peer", func(t *testing.T) {
+		data, err := mergeListTorrentsResults(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, data.Items)
+	})
+}
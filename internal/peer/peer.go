@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/core"
@@ -14,6 +15,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/server"
 	"github.com/MunifTanjim/stremthru/internal/torrent_info"
 	"github.com/MunifTanjim/stremthru/store"
+	"github.com/klauspost/compress/zstd"
 )
 
 var defaultHTTPClient = func() *http.Client {
@@ -270,6 +272,56 @@ func (c APIClient) PushTorrents(params *PushTorrentsParams) (request.APIResponse
 	return request.NewAPIResponse(res, response.Data), err
 }
 
+type ListTorrentChangesParams struct {
+	request.Ctx
+	Cursor string
+	Limit  int
+}
+
+func (c APIClient) ListTorrentChanges(params *ListTorrentChangesParams) (*torrent_info.Changes, error) {
+	params.Query = &url.Values{}
+	if params.Cursor != "" {
+		params.Query.Set("since", params.Cursor)
+	}
+	if params.Limit > 0 {
+		params.Query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	params.Headers = &http.Header{
+		"Accept-Encoding": []string{"zstd"},
+	}
+
+	req, err := params.NewRequest(c.BaseURL, "GET", "/v0/torrents/changes", c.reqHeader, c.reqQuery)
+	if err != nil {
+		error := core.NewAPIError("failed to create request")
+		error.Cause = err
+		return nil, error
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		response := &Response[any]{}
+		if err := processResponseBody(res, nil, response); err != nil {
+			return nil, err
+		}
+		return nil, core.NewUpstreamError("unexpected status: " + res.Status)
+	}
+
+	var body io.Reader = res.Body
+	if res.Header.Get("Content-Encoding") == "zstd" {
+		dec, err := zstd.NewReader(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		body = dec
+	}
+	return torrent_info.ReadChangesNDJSON(body)
+}
+
 type FetchLetterboxdListParams struct {
 	request.Ctx
 	ListId string
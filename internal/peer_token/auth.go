@@ -0,0 +1,83 @@
+package peer_token
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
+)
+
+type Scope = string
+
+const (
+	ScopeReadTorrents     Scope = "read-torrents"
+	ScopePushTorrents     Scope = "push-torrents"
+	ScopeCheckMagnetCache Scope = "check-magnet-cache"
+	ScopeZilean           Scope = "zilean"
+)
+
+var Scopes = []Scope{
+	ScopeReadTorrents,
+	ScopePushTorrents,
+	ScopeCheckMagnetCache,
+	ScopeZilean,
+}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+func (t *PeerToken) HasScope(scope Scope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+var (
+	ErrInvalidToken = errors.New("invalid peer token")
+	ErrMissingScope = errors.New("peer token is missing scope")
+	ErrRateLimited  = errors.New("peer token is rate limited")
+)
+
+// Check checks that the token is valid and has the scope, without using its
+// rate limit or recording its usage.
+func Check(token string, scope Scope) (*PeerToken, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+	t, err := getCached(token)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || t.IsExpired() {
+		return nil, ErrInvalidToken
+	}
+	if !t.HasScope(scope) {
+		return nil, ErrMissingScope
+	}
+	return t, nil
+}
+
+// Authorize checks that the token is valid, has the scope and is within its
+// rate limit, and records its usage.
+func Authorize(token string, scope Scope) (*PeerToken, error) {
+	t, err := Check(token, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.RateLimitConfigId.Valid {
+		limiter, err := ratelimit.NewLimiterById(t.RateLimitConfigId.String)
+		if err != nil {
+			return nil, err
+		}
+		result, err := limiter.Try("peer_token:" + t.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Allowed {
+			return nil, ErrRateLimited
+		}
+	}
+
+	recordUsage(t.Id)
+	return t, nil
+}
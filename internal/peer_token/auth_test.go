@@ -0,0 +1,26 @@
+package peer_token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerToken(t *testing.T) {
+	token := &PeerToken{Scopes: db.CommaSeperatedString{ScopeReadTorrents, ScopeZilean}}
+	assert.True(t, token.HasScope(ScopeReadTorrents))
+	assert.True(t, token.HasScope(ScopeZilean))
+	assert.False(t, token.HasScope(ScopePushTorrents))
+	assert.False(t, token.HasScope(ScopeCheckMagnetCache))
+
+	assert.False(t, token.IsExpired())
+	token.ExpiresAt = db.Timestamp{Time: time.Now().Add(time.Hour)}
+	assert.False(t, token.IsExpired())
+	token.ExpiresAt = db.Timestamp{Time: time.Now().Add(-time.Hour)}
+	assert.True(t, token.IsExpired())
+
+	assert.True(t, IsValidScope("check-magnet-cache"))
+	assert.False(t, IsValidScope("admin"))
+}
@@ -1,47 +1,229 @@
 package peer_token
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"sync"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/logger"
 )
 
+var log = logger.Scoped("peer_token")
+
 const TableName = "peer_token"
 
 type PeerToken struct {
-	Id        string
-	Name      string
-	CreatedAt db.Timestamp
+	Id                string
+	Name              string
+	Scopes            db.CommaSeperatedString
+	RateLimitConfigId sql.NullString
+	ExpiresAt         db.Timestamp
+	LastUsedAt        db.Timestamp
+	UsageCount        int64
+	CreatedAt         db.Timestamp
+}
+
+func (t *PeerToken) IsExpired() bool {
+	return !t.ExpiresAt.IsZero() && t.ExpiresAt.Before(time.Now())
 }
 
-var peerTokenCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+const columns = "id, name, scopes, rate_limit_config_id, expires_at, last_used_at, usage_count, created_at"
+
+func scanPeerToken(row interface{ Scan(dest ...any) error }) (*PeerToken, error) {
+	token := &PeerToken{}
+	if err := row.Scan(&token.Id, &token.Name, &token.Scopes, &token.RateLimitConfigId, &token.ExpiresAt, &token.LastUsedAt, &token.UsageCount, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// cached token has empty Id if it does not exist
+var peerTokenCache = cache.NewLRUCache[PeerToken](&cache.CacheConfig{
 	Lifetime: 15 * time.Minute,
-	Name:     "peer_token_is_valid",
+	Name:     "peer_token",
 	MaxSize:  512,
 })
 
+// Get returns the token, nil if it does not exist.
+func Get(id string) (*PeerToken, error) {
+	token, err := scanPeerToken(db.QueryRow("SELECT "+columns+" FROM "+TableName+" WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func getCached(id string) (*PeerToken, error) {
+	token := &PeerToken{}
+	if peerTokenCache.Get(id, token) {
+		if token.Id == "" {
+			return nil, nil
+		}
+		return token, nil
+	}
+
+	token, err := Get(id)
+	if err != nil {
+		return nil, err
+	}
+	cached := PeerToken{}
+	if token != nil {
+		cached = *token
+	}
+	if err := peerTokenCache.Add(id, cached); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// IsValid returns true if the token exists and is not expired.
 func IsValid(token string) (isValid bool, err error) {
 	if token == "" {
 		return false, nil
 	}
+	t, err := getCached(token)
+	if err != nil {
+		return false, err
+	}
+	return t != nil && !t.IsExpired(), nil
+}
 
-	if peerTokenCache.Get(token, &isValid) {
-		return isValid, nil
+// Create generates the id of the token, and saves it.
+func Create(token *PeerToken) error {
+	token.Id = rand.Text()
+	token.CreatedAt = db.Timestamp{Time: time.Now()}
+	_, err := db.Exec(
+		"INSERT INTO "+TableName+" (id, name, scopes, rate_limit_config_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		token.Id, token.Name, token.Scopes, token.RateLimitConfigId, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return err
 	}
+	peerTokenCache.Remove(token.Id)
+	return nil
+}
 
-	id := ""
-	row := db.QueryRow("SELECT id FROM "+TableName+" WHERE id = ?", token)
-	if err := row.Scan(&id); err != nil && err != sql.ErrNoRows {
-		return false, err
+// Update saves the name, scopes, rate limit and expiry of the token.
+func Update(token *PeerToken) error {
+	_, err := db.Exec(
+		"UPDATE "+TableName+" SET name = ?, scopes = ?, rate_limit_config_id = ?, expires_at = ? WHERE id = ?",
+		token.Name, token.Scopes, token.RateLimitConfigId, token.ExpiresAt, token.Id,
+	)
+	if err != nil {
+		return err
 	}
+	peerTokenCache.Remove(token.Id)
+	return nil
+}
 
-	isValid = id == token
+func List() ([]PeerToken, error) {
+	rows, err := db.Query("SELECT " + columns + " FROM " + TableName + " ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []PeerToken{}
+	for rows.Next() {
+		token, err := scanPeerToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
 
-	if err := peerTokenCache.Add(token, isValid); err != nil {
+// Delete revokes the token, returns false if it does not exist. Other
+// running processes keep accepting the token till their cache expires.
+func Delete(id string) (bool, error) {
+	result, err := db.Exec("DELETE FROM "+TableName+" WHERE id = ?", id)
+	if err != nil {
 		return false, err
 	}
+	peerTokenCache.Remove(id)
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+type usage struct {
+	count      int64
+	lastUsedAt time.Time
+}
+
+var usageById = map[string]*usage{}
+var usageMutex sync.Mutex
+var usageFlushOnce sync.Once
+
+const usageFlushInterval = 1 * time.Minute
+
+// recordUsage counts the usage in memory, it is written to the database
+// periodically.
+func recordUsage(id string) {
+	usageMutex.Lock()
+	u, ok := usageById[id]
+	if !ok {
+		u = &usage{}
+		usageById[id] = u
+	}
+	u.count++
+	u.lastUsedAt = time.Now()
+	usageMutex.Unlock()
+
+	usageFlushOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(usageFlushInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := FlushUsage(); err != nil {
+					log.Error("failed to flush usage", "error", err)
+				}
+			}
+		}()
+	})
+}
 
-	return isValid, nil
+// FlushUsage writes the recorded usage to the database.
+func FlushUsage() error {
+	usageMutex.Lock()
+	pending := usageById
+	usageById = map[string]*usage{}
+	usageMutex.Unlock()
+
+	for id, u := range pending {
+		_, err := db.Exec(
+			"UPDATE "+TableName+" SET usage_count = usage_count + ?, last_used_at = ? WHERE id = ?",
+			u.count, db.Timestamp{Time: u.lastUsedAt}, id,
+		)
+		if err != nil {
+			// retried on next flush
+			usageMutex.Lock()
+			for id, u := range pending {
+				if current, ok := usageById[id]; ok {
+					current.count += u.count
+					if u.lastUsedAt.After(current.lastUsedAt) {
+						current.lastUsedAt = u.lastUsedAt
+					}
+				} else {
+					usageById[id] = u
+				}
+			}
+			usageMutex.Unlock()
+			return err
+		}
+		delete(pending, id)
+	}
+	return nil
 }
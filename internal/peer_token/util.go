@@ -1,11 +1,56 @@
 package peer_token
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
 )
 
 func ExtractFromRequest(r *http.Request) string {
 	return r.Header.Get(server.HEADER_STREMTHRU_PEER_TOKEN)
 }
+
+// AuthorizeRequest authorizes the token for the scope, and returns the error
+// to send if it is not authorized.
+func AuthorizeRequest(r *http.Request, token string, scope Scope) *core.APIError {
+	_, err := Authorize(token, scope)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrInvalidToken):
+		return shared.ErrorUnauthorized(r)
+	case errors.Is(err, ErrMissingScope):
+		return shared.ErrorForbidden(r)
+	case errors.Is(err, ErrRateLimited):
+		return shared.ErrorTooManyRequests(r)
+	default:
+		apiErr := shared.ErrorInternalServerError(r, "failed to check peer token")
+		apiErr.Cause = err
+		return apiErr
+	}
+}
+
+// AuthorizeOptionalRequest is same as AuthorizeRequest, for requests that
+// are allowed without peer token.
+func AuthorizeOptionalRequest(r *http.Request, scope Scope) *core.APIError {
+	token := ExtractFromRequest(r)
+	if token == "" {
+		return nil
+	}
+	return AuthorizeRequest(r, token, scope)
+}
+
+// IsTrustedRequest returns true if the peer token of the request is valid
+// for the scope. It does not use the rate limit of the token, the request is
+// expected to be authorized already.
+func IsTrustedRequest(r *http.Request, scope Scope) bool {
+	token := ExtractFromRequest(r)
+	if token == "" {
+		return false
+	}
+	_, err := Check(token, scope)
+	return err == nil
+}
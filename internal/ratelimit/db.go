@@ -148,6 +148,7 @@ func Update(id string, name string, limit int, window string) (*RateLimitConfig,
 	if err != nil {
 		return nil, err
 	}
+	cachedLimiterById.Delete(id)
 
 	return GetById(id)
 }
@@ -160,7 +161,11 @@ var query_delete = fmt.Sprintf(
 
 func Delete(id string) error {
 	_, err := db.Exec(query_delete, id)
-	return err
+	if err != nil {
+		return err
+	}
+	cachedLimiterById.Delete(id)
+	return nil
 }
 
 func (c *RateLimitConfig) ParseWindow() (time.Duration, error) {
@@ -59,6 +59,8 @@ func NewLimiter(conf *RateLimitConfig) (*Limiter, error) {
 	}, nil
 }
 
+// NewLimiterById returns the limiter of the config, it is created once and
+// cached till the config is updated or deleted.
 func NewLimiterById(id string) (*Limiter, error) {
 	if cached, ok := cachedLimiterById.Load(id); ok {
 		return cached.(*Limiter), nil
@@ -77,6 +79,6 @@ func NewLimiterById(id string) (*Limiter, error) {
 		return nil, err
 	}
 
-	cachedLimiterById.Store(id, limiter)
-	return limiter, nil
+	cached, _ := cachedLimiterById.LoadOrStore(id, limiter)
+	return cached.(*Limiter), nil
 }
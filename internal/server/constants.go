@@ -7,6 +7,7 @@ const (
 	HEADER_STREMTHRU_AUTHENTICATE        = "X-StremThru-Authenticate"
 	HEADER_STREMTHRU_AUTHORIZATION       = "X-StremThru-Authorization"
 	HEADER_STREMTHRU_INSTANCE_ID         = "X-StremThru-Instance-ID"
+	HEADER_STREMTHRU_LIST_SECRET         = "X-StremThru-List-Secret"
 	HEADER_STREMTHRU_ORIGIN_INSTANCE_ID  = "X-StremThru-Origin-Instance-ID"
 	HEADER_STREMTHRU_PEER_TOKEN          = "X-StremThru-Peer-Token"
 	HEADER_STREMTHRU_STORE_AUTHORIZATION = "X-StremThru-Store-Authorization"
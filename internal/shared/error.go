@@ -54,6 +54,14 @@ var ErrorProxyAuthRequired = func(r *http.Request) *core.APIError {
 	return err
 }
 
+var ErrorTooManyRequests = func(r *http.Request) *core.APIError {
+	err := core.NewAPIError("too many requests")
+	err.InjectReq(r)
+	err.Code = core.ErrorCodeTooManyRequests
+	err.StatusCode = http.StatusTooManyRequests
+	return err
+}
+
 var ErrorBadRequest = func(r *http.Request, msg string) *core.APIError {
 	if msg == "" {
 		msg = "bad request"
@@ -0,0 +1,34 @@
+package simkl
+
+import "github.com/MunifTanjim/stremthru/internal/util"
+
+const SITE_BASE_URL = "https://simkl.com"
+
+var SITE_BASE_URL_PARSED = util.MustParseURL(SITE_BASE_URL)
+
+type ItemType string
+
+const (
+	ItemTypeMovies ItemType = "movies"
+	ItemTypeShows  ItemType = "shows"
+	ItemTypeAnime  ItemType = "anime"
+)
+
+type ItemStatus string
+
+const (
+	ItemStatusWatching    ItemStatus = "watching"
+	ItemStatusPlanToWatch ItemStatus = "plantowatch"
+	ItemStatusHold        ItemStatus = "hold"
+	ItemStatusCompleted   ItemStatus = "completed"
+	ItemStatusDropped     ItemStatus = "dropped"
+)
+
+const POSTER_BASE_URL = "https://simkl.in/posters/"
+
+func GetPosterURL(poster string) string {
+	if poster == "" {
+		return ""
+	}
+	return POSTER_BASE_URL + poster + "_m.jpg"
+}
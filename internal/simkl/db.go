@@ -0,0 +1,365 @@
+package simkl
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const ListTableName = "simkl_list"
+
+type SimklList struct {
+	Id        string // {user_id}:{type}:{status}
+	UserId    string
+	Name      string
+	UpdatedAt db.Timestamp
+
+	Items []SimklItem
+}
+
+func (l *SimklList) parseId() (userId string, itemType ItemType, status ItemStatus, ok bool) {
+	parts := strings.Split(l.Id, ":")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], ItemType(parts[1]), ItemStatus(parts[2]), true
+}
+
+func (l *SimklList) GetURL() string {
+	userId, itemType, status, ok := l.parseId()
+	if !ok {
+		return ""
+	}
+	typeSlug := string(itemType)
+	if itemType == ItemTypeShows {
+		typeSlug = "tv"
+	}
+	return SITE_BASE_URL_PARSED.JoinPath(userId, typeSlug, string(status)).String()
+}
+
+func (l *SimklList) IsStale() bool {
+	return time.Now().After(l.UpdatedAt.Add(config.Integration.Simkl.ListStaleTime + util.GetRandomDuration(5*time.Second, 5*time.Minute)))
+}
+
+var ListColumn = struct {
+	Id        string
+	UserId    string
+	Name      string
+	UpdatedAt string
+}{
+	Id:        "id",
+	UserId:    "user_id",
+	Name:      "name",
+	UpdatedAt: "uat",
+}
+
+var ListColumns = []string{
+	ListColumn.Id,
+	ListColumn.UserId,
+	ListColumn.Name,
+	ListColumn.UpdatedAt,
+}
+
+const ItemTableName = "simkl_item"
+
+type SimklItem struct {
+	Id        int // Simkl ID
+	Type      ItemType
+	Title     string
+	Year      int
+	Poster    string
+	IMDBId    string
+	TMDBId    string
+	TVDBId    string
+	UpdatedAt db.Timestamp
+
+	Idx int `json:"-"`
+}
+
+func (item *SimklItem) PosterURL() string {
+	return GetPosterURL(item.Poster)
+}
+
+var ItemColumn = struct {
+	Id        string
+	Type      string
+	Title     string
+	Year      string
+	Poster    string
+	IMDBId    string
+	TMDBId    string
+	TVDBId    string
+	UpdatedAt string
+}{
+	Id:        "id",
+	Type:      "type",
+	Title:     "title",
+	Year:      "year",
+	Poster:    "poster",
+	IMDBId:    "imdb_id",
+	TMDBId:    "tmdb_id",
+	TVDBId:    "tvdb_id",
+	UpdatedAt: "uat",
+}
+
+var ItemColumns = []string{
+	ItemColumn.Id,
+	ItemColumn.Type,
+	ItemColumn.Title,
+	ItemColumn.Year,
+	ItemColumn.Poster,
+	ItemColumn.IMDBId,
+	ItemColumn.TMDBId,
+	ItemColumn.TVDBId,
+	ItemColumn.UpdatedAt,
+}
+
+const ListItemTableName = "simkl_list_item"
+
+var ListItemColumn = struct {
+	ListId string
+	ItemId string
+	Idx    string
+}{
+	ListId: "list_id",
+	ItemId: "item_id",
+	Idx:    "idx",
+}
+
+var query_get_list_by_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	db.JoinColumnNames(ListColumns...),
+	ListTableName,
+	ListColumn.Id,
+)
+
+func GetListById(id string) (*SimklList, error) {
+	row := db.QueryRow(query_get_list_by_id, id)
+	list := &SimklList{}
+	if err := row.Scan(
+		&list.Id,
+		&list.UserId,
+		&list.Name,
+		&list.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	items, err := GetListItems(id)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = items
+	return list, nil
+}
+
+var query_get_list_items = fmt.Sprintf(
+	`SELECT %s, min(li.%s) FROM %s li JOIN %s i ON i.%s = li.%s WHERE li.%s = ? GROUP BY i.%s ORDER BY min(li.%s) ASC`,
+	db.JoinPrefixedColumnNames("i.", ItemColumns...),
+	ListItemColumn.Idx,
+	ListItemTableName,
+	ItemTableName,
+	ItemColumn.Id,
+	ListItemColumn.ItemId,
+	ListItemColumn.ListId,
+	ItemColumn.Id,
+	ListItemColumn.Idx,
+)
+
+func GetListItems(listId string) ([]SimklItem, error) {
+	var items []SimklItem
+	rows, err := db.Query(query_get_list_items, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item SimklItem
+		if err := rows.Scan(
+			&item.Id,
+			&item.Type,
+			&item.Title,
+			&item.Year,
+			&item.Poster,
+			&item.IMDBId,
+			&item.TMDBId,
+			&item.TVDBId,
+			&item.UpdatedAt,
+			&item.Idx,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+var query_upsert_list = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s`,
+	ListTableName,
+	strings.Join(ListColumns[:len(ListColumns)-1], ", "),
+	util.RepeatJoin("?", len(ListColumns)-1, ", "),
+	ListColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.Name, ListColumn.Name),
+		fmt.Sprintf(`%s = %s`, ListColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func UpsertList(list *SimklList) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	_, err = tx.Exec(
+		query_upsert_list,
+		list.Id,
+		list.UserId,
+		list.Name,
+	)
+	if err != nil {
+		return err
+	}
+
+	list.UpdatedAt = db.Timestamp{Time: time.Now()}
+
+	err = UpsertItems(tx, list.Items)
+	if err != nil {
+		return err
+	}
+
+	err = setListItems(tx, list.Id, list.Items)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var query_upsert_items_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	ItemTableName,
+	strings.Join(ItemColumns[:len(ItemColumns)-1], ", "),
+)
+var query_upsert_items_values_placeholder = fmt.Sprintf(
+	`(%s)`,
+	util.RepeatJoin("?", len(ItemColumns)-1, ","),
+)
+var query_upsert_items_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s`,
+	ItemColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Type, ItemColumn.Type),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Title, ItemColumn.Title),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Year, ItemColumn.Year),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Poster, ItemColumn.Poster),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.IMDBId, ItemColumn.IMDBId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.TMDBId, ItemColumn.TMDBId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.TVDBId, ItemColumn.TVDBId),
+		fmt.Sprintf(`%s = %s`, ItemColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func UpsertItems(tx db.Executor, items []SimklItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	columnCount := len(ItemColumns) - 1
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+
+		query := query_upsert_items_before_values +
+			util.RepeatJoin(query_upsert_items_values_placeholder, count, ",") +
+			query_upsert_items_after_values
+
+		args := make([]any, count*columnCount)
+		for i, item := range cItems {
+			args[i*columnCount+0] = item.Id
+			args[i*columnCount+1] = item.Type
+			args[i*columnCount+2] = item.Title
+			args[i*columnCount+3] = item.Year
+			args[i*columnCount+4] = item.Poster
+			args[i*columnCount+5] = item.IMDBId
+			args[i*columnCount+6] = item.TMDBId
+			args[i*columnCount+7] = item.TVDBId
+		}
+
+		_, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var query_set_list_item_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	ListItemTableName,
+	db.JoinColumnNames(
+		ListItemColumn.ListId,
+		ListItemColumn.ItemId,
+		ListItemColumn.Idx,
+	),
+)
+var query_set_list_item_values_placeholder = `(?,?,?)`
+var query_set_list_item_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s,%s) DO UPDATE SET %s = EXCLUDED.%s`,
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+	ListItemColumn.Idx,
+)
+var query_cleanup_list_item = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	ListItemTableName,
+	ListItemColumn.ListId,
+)
+
+func setListItems(tx db.Executor, listId string, items []SimklItem) error {
+	if _, err := tx.Exec(query_cleanup_list_item, listId); err != nil {
+		return err
+	}
+
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+		query := query_set_list_item_before_values +
+			util.RepeatJoin(query_set_list_item_values_placeholder, count, ",") +
+			query_set_list_item_after_values
+		args := make([]any, count*3)
+		for i, item := range cItems {
+			args[i*3+0] = listId
+			args[i*3+1] = item.Id
+			args[i*3+2] = item.Idx
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
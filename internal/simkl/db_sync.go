@@ -0,0 +1,125 @@
+package simkl
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+)
+
+var listCache = cache.NewCache[SimklList](&cache.CacheConfig{
+	Lifetime: 1 * time.Hour,
+	Name:     "simkl:list",
+	MaxSize:  64,
+})
+
+var syncListMutex sync.Mutex
+
+func syncList(l *SimklList, tokenId string) error {
+	syncListMutex.Lock()
+	defer syncListMutex.Unlock()
+
+	userId, itemType, status, ok := l.parseId()
+	if !ok {
+		return errors.New("invalid list id: " + l.Id)
+	}
+	l.UserId = userId
+	l.Name = getListName(itemType, status)
+
+	log.Debug("fetching list", "id", l.Id)
+
+	client := GetAPIClient(tokenId)
+	res, err := client.GetAllItems(&GetAllItemsParams{
+		Type:   itemType,
+		Status: status,
+	})
+	if err != nil {
+		return err
+	}
+
+	var entries []AllItemsItem
+	switch itemType {
+	case ItemTypeMovies:
+		entries = res.Data.Movies
+	case ItemTypeShows:
+		entries = res.Data.Shows
+	case ItemTypeAnime:
+		entries = res.Data.Anime
+	}
+
+	items := make([]SimklItem, 0, len(entries))
+	for i := range entries {
+		media := entries[i].Media()
+		if media == nil || media.Ids.Simkl == 0 {
+			continue
+		}
+		item := SimklItem{
+			Id:     media.Ids.Simkl,
+			Type:   itemType,
+			Title:  media.Title,
+			Year:   media.Year,
+			Poster: media.Poster,
+			IMDBId: media.Ids.IMDB,
+			Idx:    len(items),
+		}
+		if tmdbId := string(media.Ids.TMDB); tmdbId != "0" {
+			item.TMDBId = tmdbId
+		}
+		if tvdbId := string(media.Ids.TVDB); tvdbId != "0" {
+			item.TVDBId = tvdbId
+		}
+		items = append(items, item)
+	}
+
+	l.Items = items
+
+	if err := UpsertList(l); err != nil {
+		return err
+	}
+
+	listCache.Add(l.Id, *l)
+	return nil
+}
+
+// Fetch loads the list using the token of its owner.
+func (l *SimklList) Fetch(tokenId string) error {
+	if l.Id == "" {
+		return errors.New("id must be provided")
+	}
+
+	if !IsValidListId(l.Id) {
+		return errors.New("invalid list id: " + l.Id)
+	}
+
+	isMissing := false
+
+	var cachedL SimklList
+	if !listCache.Get(l.Id, &cachedL) {
+		if list, err := GetListById(l.Id); err != nil {
+			return err
+		} else if list == nil {
+			isMissing = true
+		} else {
+			*l = *list
+			log.Debug("found list by id", "id", l.Id, "is_stale", l.IsStale())
+			listCache.Add(l.Id, *l)
+		}
+	} else {
+		*l = cachedL
+	}
+
+	if !isMissing {
+		if l.IsStale() {
+			staleList := *l
+			go func() {
+				if err := syncList(&staleList, tokenId); err != nil {
+					log.Error("failed to sync stale list", "id", l.Id, "error", err)
+				}
+			}()
+		}
+		return nil
+	}
+
+	return syncList(l, tokenId)
+}
@@ -0,0 +1,60 @@
+package simkl
+
+import "strings"
+
+var itemTypeNames = map[ItemType]string{
+	ItemTypeMovies: "Movies",
+	ItemTypeShows:  "TV Shows",
+	ItemTypeAnime:  "Anime",
+}
+
+var itemStatusNames = map[ItemStatus]string{
+	ItemStatusWatching:    "Watching",
+	ItemStatusPlanToWatch: "Plan to Watch",
+	ItemStatusHold:        "On Hold",
+	ItemStatusCompleted:   "Completed",
+	ItemStatusDropped:     "Dropped",
+}
+
+// ParseListURLPath parses `/{user_id}/{tv,movies,anime}/{status}` into list id.
+func ParseListURLPath(path string) (id string, userId string, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", "", false
+	}
+	userId = parts[0]
+	itemType := ItemType(parts[1])
+	if parts[1] == "tv" {
+		itemType = ItemTypeShows
+	}
+	if _, ok := itemTypeNames[itemType]; !ok {
+		return "", "", false
+	}
+	status := ItemStatus(parts[2])
+	if _, ok := itemStatusNames[status]; !ok {
+		return "", "", false
+	}
+	return userId + ":" + string(itemType) + ":" + string(status), userId, true
+}
+
+func IsValidListId(id string) bool {
+	l := SimklList{Id: id}
+	_, itemType, status, ok := l.parseId()
+	if !ok {
+		return false
+	}
+	if _, ok := itemTypeNames[itemType]; !ok {
+		return false
+	}
+	_, ok = itemStatusNames[status]
+	return ok
+}
+
+func (l *SimklList) GetItemType() ItemType {
+	_, itemType, _, _ := l.parseId()
+	return itemType
+}
+
+func getListName(itemType ItemType, status ItemStatus) string {
+	return itemTypeNames[itemType] + " / " + itemStatusNames[status]
+}
@@ -0,0 +1,5 @@
+package simkl
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("simkl")
@@ -0,0 +1,127 @@
+package simkl
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type ItemIds struct {
+	Simkl int             `json:"simkl,omitempty"`
+	Slug  string          `json:"slug,omitempty"`
+	IMDB  string          `json:"imdb,omitempty"`
+	TMDB  util.JSONNumber `json:"tmdb,omitempty"`
+	TVDB  util.JSONNumber `json:"tvdb,omitempty"`
+}
+
+type ItemMedia struct {
+	Title  string  `json:"title"`
+	Poster string  `json:"poster"`
+	Year   int     `json:"year"`
+	Ids    ItemIds `json:"ids"`
+}
+
+type WatchedEpisode struct {
+	Number    int           `json:"number"`
+	WatchedAt util.JSONTime `json:"watched_at"`
+}
+
+type WatchedSeason struct {
+	Number   int              `json:"number"`
+	Episodes []WatchedEpisode `json:"episodes"`
+}
+
+type AllItemsItem struct {
+	AddedToWatchlistAt util.JSONTime   `json:"added_to_watchlist_at"`
+	LastWatchedAt      util.JSONTime   `json:"last_watched_at"`
+	Status             ItemStatus      `json:"status"`
+	AnimeType          string          `json:"anime_type,omitempty"`
+	Movie              *ItemMedia      `json:"movie,omitempty"`
+	Show               *ItemMedia      `json:"show,omitempty"`
+	Seasons            []WatchedSeason `json:"seasons,omitempty"`
+}
+
+func (item *AllItemsItem) Media() *ItemMedia {
+	if item.Movie != nil {
+		return item.Movie
+	}
+	return item.Show
+}
+
+type GetAllItemsData struct {
+	ResponseError
+	Movies []AllItemsItem `json:"movies"`
+	Shows  []AllItemsItem `json:"shows"`
+	Anime  []AllItemsItem `json:"anime"`
+}
+
+type GetAllItemsParams struct {
+	Ctx
+	Type             ItemType
+	Status           ItemStatus
+	DateFrom         *time.Time
+	EpisodeWatchedAt bool
+}
+
+func (c APIClient) GetAllItems(params *GetAllItemsParams) (APIResponse[GetAllItemsData], error) {
+	path := "/sync/all-items"
+	if params.Type != "" {
+		path += "/" + string(params.Type)
+		if params.Status != "" {
+			path += "/" + string(params.Status)
+		}
+	}
+
+	params.Query = &url.Values{}
+	if params.DateFrom != nil {
+		params.Query.Set("date_from", params.DateFrom.UTC().Format(time.RFC3339))
+	}
+	if params.EpisodeWatchedAt {
+		params.Query.Set("extended", "full")
+		params.Query.Set("episode_watched_at", "yes")
+	}
+
+	// responds with `null` when there are no items
+	response := GetAllItemsData{}
+	res, err := c.Request("GET", path, params, &response)
+	return newAPIResponse(res, response), err
+}
+
+type SyncHistoryEpisode struct {
+	Number    int        `json:"number"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+}
+
+type SyncHistorySeason struct {
+	Number   int                  `json:"number"`
+	Episodes []SyncHistoryEpisode `json:"episodes,omitempty"`
+}
+
+type SyncHistoryItem struct {
+	WatchedAt *time.Time          `json:"watched_at,omitempty"`
+	Ids       ItemIds             `json:"ids"`
+	Seasons   []SyncHistorySeason `json:"seasons,omitempty"`
+}
+
+type AddToHistoryData struct {
+	ResponseError
+	Added struct {
+		Movies   int `json:"movies"`
+		Shows    int `json:"shows"`
+		Episodes int `json:"episodes"`
+	} `json:"added"`
+}
+
+type AddToHistoryParams struct {
+	Ctx
+	Movies []SyncHistoryItem `json:"movies,omitempty"`
+	Shows  []SyncHistoryItem `json:"shows,omitempty"`
+}
+
+func (c APIClient) AddToHistory(params *AddToHistoryParams) (APIResponse[AddToHistoryData], error) {
+	params.JSON = params
+	response := AddToHistoryData{}
+	res, err := c.Request("POST", "/sync/history", params, &response)
+	return newAPIResponse(res, response), err
+}
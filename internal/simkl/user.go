@@ -0,0 +1,22 @@
+package simkl
+
+type UserSettings struct {
+	ResponseError
+	User struct {
+		Name string `json:"name"`
+	} `json:"user"`
+	Account struct {
+		Id       int    `json:"id"`
+		Timezone string `json:"timezone"`
+	} `json:"account"`
+}
+
+type GetUserSettingsParams struct {
+	Ctx
+}
+
+func (c APIClient) GetUserSettings(params *GetUserSettingsParams) (APIResponse[UserSettings], error) {
+	response := UserSettings{}
+	res, err := c.Request("POST", "/users/settings", params, &response)
+	return newAPIResponse(res, response), err
+}
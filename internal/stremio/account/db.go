@@ -11,6 +11,10 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/db"
 	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
 	stremio_userdata_account "github.com/MunifTanjim/stremthru/internal/stremio/userdata/account"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_anilist"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_letterboxd"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_mal"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_simkl"
 	"github.com/MunifTanjim/stremthru/internal/sync/stremio_stremio"
 	"github.com/MunifTanjim/stremthru/internal/sync/stremio_trakt"
 )
@@ -303,6 +307,18 @@ func Delete(id string) error {
 	if err := sync_stremio_trakt.UnlinkByStremioAccount(id); err != nil {
 		return err
 	}
+	if err := sync_stremio_anilist.UnlinkByStremioAccount(id); err != nil {
+		return err
+	}
+	if err := sync_stremio_mal.UnlinkByStremioAccount(id); err != nil {
+		return err
+	}
+	if err := sync_stremio_simkl.UnlinkByStremioAccount(id); err != nil {
+		return err
+	}
+	if err := sync_stremio_letterboxd.UnlinkByStremioAccount(id); err != nil {
+		return err
+	}
 	if err := sync_stremio_stremio.UnlinkByStremioAccount(id); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package stremio_list
 
 import (
+	"errors"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -17,7 +18,9 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/meta"
 	"github.com/MunifTanjim/stremthru/internal/serializd"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
 	"github.com/MunifTanjim/stremthru/internal/tvdb"
@@ -326,39 +329,27 @@ type catalogItem struct {
 	item any
 }
 
-func handleCatalog(w http.ResponseWriter, r *http.Request) {
-	if !IsMethod(r, http.MethodGet) {
-		shared.ErrorMethodNotAllowed(r).Send(w, r)
-		return
+func (ud *UserData) getPosterBaseURL() (baseUrl string, queryParams string) {
+	if ud.RPDBAPIKey != "" {
+		return "https://api.ratingposterdb.com/" + ud.RPDBAPIKey + "/imdb/poster-default/", "?fallback=true"
 	}
-
-	ud, err := getUserData(r, false)
-	if err != nil {
-		SendError(w, r, err)
-		return
+	if ud.TopPostersAPIKey != "" {
+		return "https://api.top-streaming.stream/" + ud.TopPostersAPIKey + "/imdb/poster-default/", ""
 	}
+	return "", ""
+}
 
-	catalogType := GetPathValue(r, "contentType")
-	catalogId := GetPathValue(r, "id")
-
-	service, id := parseCatalogId(catalogId)
+var errInvalidCatalogId = errors.New("invalid catalog id")
 
-	posterBaseUrl := ""
-	posterQueryParams := ""
-	if ud.RPDBAPIKey != "" {
-		posterBaseUrl = "https://api.ratingposterdb.com/" + ud.RPDBAPIKey + "/imdb/poster-default/"
-		posterQueryParams = "?fallback=true"
-	} else if ud.TopPostersAPIKey != "" {
-		posterBaseUrl = "https://api.top-streaming.stream/" + ud.TopPostersAPIKey + "/imdb/poster-default/"
-	}
+func fetchCatalogItems(ud *UserData, service, id, catalogType string) ([]catalogItem, error) {
+	posterBaseUrl, posterQueryParams := ud.getPosterBaseURL()
 
 	catalogItems := []catalogItem{}
 	switch service {
 	case "anilist":
 		list := anilist.AniListList{Id: id}
 		if err := ud.FetchAniListList(&list, false); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Medias {
@@ -383,8 +374,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 	case "letterboxd":
 		list := letterboxd.LetterboxdList{Id: id}
 		if err := ud.FetchLetterboxdList(&list); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Items {
@@ -403,8 +393,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 	case "mdblist":
 		list := mdblist.MDBListList{Id: id}
 		if err := ud.FetchMDBListList(&list); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Items {
@@ -431,8 +420,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 	case "tmdb":
 		list := tmdb.TMDBList{Id: id}
 		if err := ud.FetchTMDBList(&list); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Items {
@@ -466,8 +454,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 	case "trakt":
 		list := trakt.TraktList{Id: id}
 		if err := ud.FetchTraktList(&list); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		isMovieCatalog := catalogType == string(stremio.ContentTypeMovie) || catalogType == "movies"
@@ -520,8 +507,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 	case "serializd":
 		list := serializd.SerializdList{Id: id}
 		if err := list.Fetch(); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Items {
@@ -538,11 +524,75 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			catalogItems = append(catalogItems, catalogItem{meta, item})
 		}
 
+	case "simkl":
+		list := simkl.SimklList{Id: id}
+		if err := ud.FetchSimklList(&list); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			meta := stremio.MetaPreview{
+				Id:          item.IMDBId,
+				Name:        item.Title,
+				Poster:      item.PosterURL(),
+				PosterShape: stremio.MetaPosterShapePoster,
+				Background:  stremio_shared.GetCinemetaBackgroundURL(item.IMDBId),
+			}
+			if item.Year > 0 {
+				meta.ReleaseInfo = strconv.Itoa(item.Year)
+			}
+			switch item.Type {
+			case simkl.ItemTypeMovies:
+				meta.Type = stremio.ContentTypeMovie
+			case simkl.ItemTypeShows, simkl.ItemTypeAnime:
+				meta.Type = stremio.ContentTypeSeries
+			default:
+				continue
+			}
+			catalogItems = append(catalogItems, catalogItem{meta, item})
+		}
+
+	case "stremthru":
+		listItems, err := stremthru_list.GetItems(id)
+		if err != nil {
+			return nil, err
+		}
+
+		isMovieCatalog := catalogType == string(stremio.ContentTypeMovie) || catalogType == "movies"
+		isSeriesCatalog := catalogType == string(stremio.ContentTypeSeries)
+		for i := range listItems {
+			item := &listItems[i]
+			meta := stremio.MetaPreview{
+				Id:          item.Id,
+				Name:        item.Name,
+				Poster:      item.Poster,
+				PosterShape: stremio.MetaPosterShapePoster,
+			}
+			switch item.Type {
+			case stremthru_list.ItemTypeMovie:
+				if isSeriesCatalog {
+					continue
+				}
+				meta.Type = stremio.ContentTypeMovie
+			case stremthru_list.ItemTypeSeries:
+				if isMovieCatalog {
+					continue
+				}
+				meta.Type = stremio.ContentTypeSeries
+			default:
+				continue
+			}
+			if item.Year > 0 {
+				meta.ReleaseInfo = strconv.Itoa(item.Year)
+			}
+			catalogItems = append(catalogItems, catalogItem{meta, item})
+		}
+
 	case "tvdb":
 		list := tvdb.TVDBList{Id: id}
 		if err := ud.FetchTVDBList(&list); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range list.Items {
@@ -583,26 +633,14 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 		}
 
 	default:
-		shared.ErrorBadRequest(r, "invalid id").Send(w, r)
-		return
+		return nil, errInvalidCatalogId
 	}
 
-	extra := getExtra(r)
-
-	if extra.Genre != "" {
-		filteredItems := []catalogItem{}
-		for i := range catalogItems {
-			item := &catalogItems[i]
-			if slices.Contains(item.Genres, extra.Genre) {
-				filteredItems = append(filteredItems, *item)
-			}
-		}
-		catalogItems = filteredItems
-	}
+	return catalogItems, nil
+}
 
-	limit := 100
-	totalItems := len(catalogItems)
-	catalogItems = catalogItems[min(extra.Skip, totalItems):min(extra.Skip+limit, totalItems)]
+func resolveCatalogItems(ud *UserData, service, id string, catalogItems []catalogItem) ([]stremio.MetaPreview, error) {
+	posterBaseUrl, posterQueryParams := ud.getPosterBaseURL()
 
 	items := []stremio.MetaPreview{}
 
@@ -614,8 +652,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			medias[i] = item.item.(anilist.AniListMedia)
 		}
 		if err := anilist.EnsureIdMap(medias, id); err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -663,8 +700,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		idMapByLetterboxdId, err := imdb_title.GetIdMapsByLetterboxdId(letterboxdIds)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -703,8 +739,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		metaById, err := getIMDBMetaFromMDBList(imdbIds, ud.MDBListAPIkey)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -737,8 +772,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		movieImdbIdByTmdbId, showImdbIdByTmdbId, err := getIMDBIdsForTMDBIds(ud.TMDBTokenId, tmdbMovieIds, tmdbShowIds)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -784,8 +818,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		movieImdbIdByTraktId, showImdbIdByTraktId, err := imdb_title.GetIMDBIdByTraktId(traktMovieIds, traktShowIds)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -829,8 +862,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		_, showImdbIdByTmdbId, err := getIMDBIdsForTMDBIds(ud.TMDBTokenId, nil, tmdbShowIds)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -854,6 +886,32 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			items = append(items, item.MetaPreview)
 		}
 
+	case "simkl":
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			if !strings.HasPrefix(item.Id, "tt") {
+				continue
+			}
+			if posterBaseUrl != "" {
+				item.MetaPreview.Poster = posterBaseUrl + item.Id + ".jpg" + posterQueryParams
+			}
+			items = append(items, item.MetaPreview)
+		}
+
+	case "stremthru":
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			if strings.HasPrefix(item.Id, "tt") {
+				if posterBaseUrl != "" {
+					item.MetaPreview.Poster = posterBaseUrl + item.Id + ".jpg" + posterQueryParams
+				} else if item.MetaPreview.Poster == "" {
+					item.MetaPreview.Poster = stremio_shared.GetCinemetaPosterURL(item.Id)
+				}
+				item.MetaPreview.Background = stremio_shared.GetCinemetaBackgroundURL(item.Id)
+			}
+			items = append(items, item.MetaPreview)
+		}
+
 	case "tvdb":
 		tvdbMovieIds := make([]string, 0, len(catalogItems))
 		tvdbShowIds := make([]string, 0, len(catalogItems))
@@ -869,8 +927,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 
 		movieImdbIdByTvdbId, showImdbIdByTvdbId, err := tvdb.GetIMDBIdsForTVDBIds(tvdbMovieIds, tvdbShowIds)
 		if err != nil {
-			SendError(w, r, err)
-			return
+			return nil, err
 		}
 
 		for i := range catalogItems {
@@ -902,6 +959,12 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	return items, nil
+}
+
+// applyPreferredMetaId replaces the imdb ids of the items with the preferred
+// meta ids.
+func (ud *UserData) applyPreferredMetaId(items []stremio.MetaPreview) {
 	imdbIdsToFindTmdbIds := []string{}
 	imdbIdsToFindTvdbIds := []string{}
 	if ud.MetaIdMovie != "" || ud.MetaIdSeries != "" {
@@ -952,6 +1015,64 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+}
+
+func handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r, false)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	catalogType := GetPathValue(r, "contentType")
+	catalogId := GetPathValue(r, "id")
+
+	service, id := parseCatalogId(catalogId)
+
+	if service == "smart" {
+		handleSmartListCatalog(w, r, ud, id, catalogType)
+		return
+	}
+
+	catalogItems, err := fetchCatalogItems(ud, service, id, catalogType)
+	if err != nil {
+		if err == errInvalidCatalogId {
+			shared.ErrorBadRequest(r, "invalid id").Send(w, r)
+			return
+		}
+		SendError(w, r, err)
+		return
+	}
+
+	extra := getExtra(r)
+
+	if extra.Genre != "" {
+		filteredItems := []catalogItem{}
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			if slices.Contains(item.Genres, extra.Genre) {
+				filteredItems = append(filteredItems, *item)
+			}
+		}
+		catalogItems = filteredItems
+	}
+
+	limit := 100
+	totalItems := len(catalogItems)
+	catalogItems = catalogItems[min(extra.Skip, totalItems):min(extra.Skip+limit, totalItems)]
+
+	items, err := resolveCatalogItems(ud, service, id, catalogItems)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	ud.applyPreferredMetaId(items)
 
 	shouldShuffle := ud.Shuffle
 	if !shouldShuffle && len(ud.ListShuffle) > 0 {
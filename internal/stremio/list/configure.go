@@ -3,11 +3,13 @@ package stremio_list
 import (
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
 	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
@@ -18,8 +20,12 @@ func handleConfigure(w http.ResponseWriter, r *http.Request) {
 	}
 
 	isAuthed := false
+	authedUser := ""
 	if cookie, err := stremio_shared.GetAdminCookieValue(w, r); err == nil && !cookie.IsExpired {
 		isAuthed = config.Auth.GetPassword(cookie.User()) == cookie.Pass()
+		if isAuthed {
+			authedUser = cookie.User()
+		}
 	}
 
 	ud, err := getUserData(r, isAuthed)
@@ -76,6 +82,26 @@ func handleConfigure(w http.ResponseWriter, r *http.Request) {
 			if idx != -1 && idx < len(td.Lists) {
 				td.Lists = slices.Delete(td.Lists, idx, idx+1)
 			}
+		case "add-smart-list":
+			if td.IsAuthed || len(td.SmartLists) < MaxPublicInstanceListCount {
+				idx := util.SafeParseInt(r.Header.Get("x-addon-configure-action-data"), -1)
+				if idx == -1 || idx >= len(td.SmartLists) {
+					td.SmartLists = append(td.SmartLists, newTemplateDataSmartList(len(td.SmartLists), nil))
+				} else {
+					td.SmartLists = slices.Insert(td.SmartLists, idx+1, newTemplateDataSmartList(idx+1, nil))
+				}
+				for i := range td.SmartLists {
+					td.SmartLists[i].setIndex(i)
+				}
+			}
+		case "remove-smart-list":
+			idx := util.SafeParseInt(r.Header.Get("x-addon-configure-action-data"), -1)
+			if idx != -1 && idx < len(td.SmartLists) {
+				td.SmartLists = slices.Delete(td.SmartLists, idx, idx+1)
+				for i := range td.SmartLists {
+					td.SmartLists[i].setIndex(i)
+				}
+			}
 		case "move-list-up":
 			id := r.Header.Get("x-addon-configure-action-data")
 			idx := slices.IndexFunc(td.Lists, func(tdl TemplateDataList) bool {
@@ -119,6 +145,31 @@ func handleConfigure(w http.ResponseWriter, r *http.Request) {
 			} else if td.MDBListAPIKey.Error == "" {
 				td.MDBListAPIKey.Error = "Missing API Key"
 			}
+		case "create-stremthru-list":
+			if td.IsAuthed {
+				name := strings.TrimSpace(r.Form.Get("stremthru_list_name"))
+				if name == "" {
+					td.StremThruListName.Error = "Missing Name"
+					break
+				}
+				list, err := stremthru_list.Create(name, "", authedUser)
+				if err != nil {
+					LogError(r, "failed to create list", err)
+					td.StremThruListName.Error = "Failed to Create List"
+					break
+				}
+				if ud.StremThruListSecrets == nil {
+					ud.StremThruListSecrets = map[string]string{}
+				}
+				ud.StremThruListSecrets[list.Id] = list.Secret
+				listUrl, _, _ := td.getListURL(ud, "stremthru:"+list.Id)
+				if len(td.Lists) == 1 && td.Lists[0].URL == "" {
+					td.Lists = td.Lists[:0]
+				}
+				tdl := newTemplateDataList(len(td.Lists))
+				tdl.URL = listUrl
+				td.Lists = append(td.Lists, tdl)
+			}
 		case "set-userdata-key":
 			if td.IsAuthed {
 				key := r.Form.Get("userdata_key")
@@ -195,7 +246,7 @@ func handleConfigure(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if ud.GetEncoded() != "" || IsMethod(r, http.MethodPost) {
-		if len(td.Lists) == 0 {
+		if len(td.Lists) == 0 && len(td.SmartLists) == 0 {
 			list := TemplateDataList{}
 			list.Error.URL = "Missing List URL"
 			td.Lists = append(td.Lists, list)
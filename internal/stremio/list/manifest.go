@@ -11,7 +11,9 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/serializd"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
 	"github.com/MunifTanjim/stremthru/internal/tvdb"
@@ -279,6 +281,71 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 				}
 				catalogs = append(catalogs, catalog)
 
+			case "simkl":
+				list := simkl.SimklList{Id: idStr}
+				if err := ud.FetchSimklList(&list); err != nil {
+					return nil, err
+				}
+				catalog := stremio.Catalog{
+					Type: "Simkl",
+					Id:   "st.list.simkl." + idStr,
+					Name: list.Name,
+					Extra: []stremio.CatalogExtra{
+						{
+							Name: "skip",
+						},
+					},
+				}
+				switch list.GetItemType() {
+				case simkl.ItemTypeMovies:
+					catalog.Type = string(stremio.ContentTypeMovie)
+				case simkl.ItemTypeShows:
+					catalog.Type = string(stremio.ContentTypeSeries)
+				case simkl.ItemTypeAnime:
+					catalog.Type = "anime"
+				}
+				if hasListNames {
+					if name := ud.ListNames[idx]; name != "" {
+						catalog.Name = name
+					}
+				}
+				if hasListTypes {
+					if listType := ud.ListTypes[idx]; listType != "" {
+						catalog.Type = listType
+					}
+				}
+				catalogs = append(catalogs, catalog)
+
+			case "stremthru":
+				list, err := stremthru_list.GetById(idStr)
+				if err != nil {
+					return nil, err
+				}
+				if list == nil {
+					return nil, core.NewError("list not found: " + listId)
+				}
+				catalog := stremio.Catalog{
+					Type: "StremThru",
+					Id:   "st.list.stremthru." + idStr,
+					Name: list.GetDisplayName(),
+					Extra: []stremio.CatalogExtra{
+						{
+							Name: "skip",
+						},
+					},
+				}
+				if hasListNames {
+					if name := ud.ListNames[idx]; name != "" {
+						catalog.Name = name
+					}
+				}
+				if hasListTypes {
+					if listType := ud.ListTypes[idx]; listType != "" {
+						catalog.Type = listType
+					}
+				}
+				catalogs = append(catalogs, catalog)
+
 			case "tvdb":
 				list := tvdb.TVDBList{Id: idStr}
 				if err := list.Fetch(); err != nil {
@@ -312,6 +379,48 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 				catalogs = append(catalogs, catalog)
 			}
 		}
+
+		for i := range ud.SmartLists {
+			sl := &ud.SmartLists[i]
+			catalog := stremio.Catalog{
+				Type: "Smart",
+				Id:   "st.list.smart." + sl.Id,
+				Name: sl.GetDisplayName(),
+				Extra: []stremio.CatalogExtra{
+					{
+						Name: "skip",
+					},
+				},
+			}
+			if sl.Type != "" {
+				catalog.Type = sl.Type
+			}
+			catalogs = append(catalogs, catalog)
+		}
+	}
+
+	resources := []stremio.Resource{
+		{
+			Name: stremio.ResourceNameCatalog,
+			Types: []stremio.ContentType{
+				stremio.ContentTypeMovie,
+				stremio.ContentTypeSeries,
+			},
+		},
+	}
+	if len(ud.StremThruListSecrets) > 0 {
+		resources = append(resources, stremio.Resource{
+			Name: stremio.ResourceNameStream,
+			Types: []stremio.ContentType{
+				stremio.ContentTypeMovie,
+				stremio.ContentTypeSeries,
+			},
+			IDPrefixes: stremThruListStreamIdPrefixes,
+		}, stremio.Resource{
+			Name:       stremio.ResourceNameMeta,
+			Types:      []stremio.ContentType{stremio.ContentTypeOther},
+			IDPrefixes: []string{stremThruListActionIdPrefix},
+		})
 	}
 
 	manifest := &stremio.Manifest{
@@ -319,18 +428,10 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 		Name:        name,
 		Description: description,
 		Version:     config.Version,
-		Resources: []stremio.Resource{
-			{
-				Name: stremio.ResourceNameCatalog,
-				Types: []stremio.ContentType{
-					stremio.ContentTypeMovie,
-					stremio.ContentTypeSeries,
-				},
-			},
-		},
-		Types:    []stremio.ContentType{},
-		Catalogs: catalogs,
-		Logo:     "https://emojiapi.dev/api/v1/sparkles/256.png",
+		Resources:   resources,
+		Types:       []stremio.ContentType{},
+		Catalogs:    catalogs,
+		Logo:        "https://emojiapi.dev/api/v1/sparkles/256.png",
 		BehaviorHints: &stremio.BehaviorHints{
 			Configurable:          true,
 			ConfigurationRequired: !isConfigured,
@@ -40,5 +40,9 @@ func AddEndpoints(mux *http.ServeMux) {
 	router.HandleFunc("/{userData}/catalog/{contentType}/{idJson}", withCors(handleCatalog))
 	router.HandleFunc("/{userData}/catalog/{contentType}/{id}/{extraJson}", withCors(handleCatalog))
 
+	router.HandleFunc("/{userData}/meta/{contentType}/{idJson}", withCors(handleMeta))
+	router.HandleFunc("/{userData}/stream/{contentType}/{idJson}", withCors(handleStream))
+	router.HandleFunc("/{userData}/_/action/{action}/{listId}/{contentType}/{itemId}", withCors(handleAction))
+
 	mux.Handle("/stremio/list/", http.StripPrefix("/stremio/list", commonMiddleware(router)))
 }
@@ -0,0 +1,426 @@
+package stremio_list
+
+import (
+	"cmp"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/google/uuid"
+)
+
+type SmartListOp string
+
+const (
+	SmartListOpUnion        SmartListOp = "union"
+	SmartListOpIntersection SmartListOp = "intersection"
+	SmartListOpDifference   SmartListOp = "difference"
+)
+
+func (op SmartListOp) IsValid() bool {
+	switch op {
+	case SmartListOpUnion, SmartListOpIntersection, SmartListOpDifference:
+		return true
+	}
+	return false
+}
+
+type SmartListSort string
+
+const (
+	SmartListSortDefault     SmartListSort = ""
+	SmartListSortName        SmartListSort = "name"
+	SmartListSortYear        SmartListSort = "year"
+	SmartListSortYearDesc    SmartListSort = "-year"
+	SmartListSortRatingDesc  SmartListSort = "-rating"
+	SmartListSortRuntime     SmartListSort = "runtime"
+	SmartListSortRuntimeDesc SmartListSort = "-runtime"
+)
+
+func (s SmartListSort) IsValid() bool {
+	switch s {
+	case SmartListSortDefault, SmartListSortName, SmartListSortYear, SmartListSortYearDesc, SmartListSortRatingDesc, SmartListSortRuntime, SmartListSortRuntimeDesc:
+		return true
+	}
+	return false
+}
+
+type SmartListFilter struct {
+	Genres     []string `json:"genres,omitempty"`
+	YearMin    int      `json:"year_min,omitempty"`
+	YearMax    int      `json:"year_max,omitempty"`
+	RatingMin  float64  `json:"rating_min,omitempty"`  // 0-10
+	RuntimeMin int      `json:"runtime_min,omitempty"` // minutes
+	RuntimeMax int      `json:"runtime_max,omitempty"` // minutes
+}
+
+func (f SmartListFilter) hasYear() bool {
+	return f.YearMin > 0 || f.YearMax > 0
+}
+
+func (f SmartListFilter) hasRating() bool {
+	return f.RatingMin > 0
+}
+
+func (f SmartListFilter) hasRuntime() bool {
+	return f.RuntimeMin > 0 || f.RuntimeMax > 0
+}
+
+type SmartList struct {
+	Id     string          `json:"id"`
+	Name   string          `json:"name,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	Op     SmartListOp     `json:"op"`
+	Lists  []string        `json:"lists"`
+	Filter SmartListFilter `json:"filter"`
+	Sort   SmartListSort   `json:"sort,omitempty"`
+}
+
+func newSmartListId() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+func (sl *SmartList) validate() string {
+	if !sl.Op.IsValid() {
+		return "Invalid Operation"
+	}
+	if !sl.Sort.IsValid() {
+		return "Invalid Sort"
+	}
+	if len(sl.Lists) == 0 {
+		return "Missing List URL"
+	}
+	if sl.Op != SmartListOpUnion && len(sl.Lists) < 2 {
+		return "At least 2 List URLs are required"
+	}
+	if sl.Filter.YearMin > 0 && sl.Filter.YearMax > 0 && sl.Filter.YearMin > sl.Filter.YearMax {
+		return "Invalid Year range"
+	}
+	if sl.Filter.RatingMin < 0 || sl.Filter.RatingMin > 10 {
+		return "Invalid Rating"
+	}
+	if sl.Filter.RuntimeMin > 0 && sl.Filter.RuntimeMax > 0 && sl.Filter.RuntimeMin > sl.Filter.RuntimeMax {
+		return "Invalid Runtime range"
+	}
+	return ""
+}
+
+func (sl *SmartList) GetDisplayName() string {
+	if sl.Name != "" {
+		return sl.Name
+	}
+	return "Smart List"
+}
+
+func (ud *UserData) getSmartList(id string) *SmartList {
+	for i := range ud.SmartLists {
+		if ud.SmartLists[i].Id == id {
+			return &ud.SmartLists[i]
+		}
+	}
+	return nil
+}
+
+var smartListItemsCache = cache.NewCache[[]stremio.MetaPreview](&cache.CacheConfig{
+	Lifetime: 15 * time.Minute,
+	Name:     "stremio:list:smart-list-items",
+	MaxSize:  256,
+})
+
+// getSmartListItems returns all the items of the smart list, with the
+// operation, filters and sort applied. Items use IMDB ids where available.
+func (ud *UserData) getSmartListItems(sl *SmartList, catalogType string) ([]stremio.MetaPreview, error) {
+	cacheKey := util.MD5Hash(ud.GetEncoded() + ":" + sl.Id + ":" + catalogType)
+	items := []stremio.MetaPreview{}
+	if smartListItemsCache.Get(cacheKey, &items) {
+		return slices.Clone(items), nil
+	}
+
+	// lists are matched by item id, so preferred meta id is applied later
+	oud := *ud
+	oud.MetaIdMovie = ""
+	oud.MetaIdSeries = ""
+
+	itemsByList := make([][]stremio.MetaPreview, len(sl.Lists))
+	for i, listId := range sl.Lists {
+		service, id, err := parseListId(listId)
+		if err != nil {
+			return nil, err
+		}
+		catalogItems, err := fetchCatalogItems(&oud, service, id, catalogType)
+		if err != nil {
+			return nil, err
+		}
+		listItems, err := resolveCatalogItems(&oud, service, id, catalogItems)
+		if err != nil {
+			return nil, err
+		}
+		itemsByList[i] = listItems
+	}
+
+	items = combineSmartListItems(sl.Op, itemsByList)
+
+	if catalogType == string(stremio.ContentTypeMovie) || catalogType == string(stremio.ContentTypeSeries) {
+		items = slices.DeleteFunc(items, func(item stremio.MetaPreview) bool {
+			return string(item.Type) != catalogType
+		})
+	}
+
+	items, err := ud.applySmartListFilterAndSort(items, &sl.Filter, sl.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := smartListItemsCache.Add(cacheKey, items); err != nil {
+		log.Warn("failed to cache smart list items", "error", err, "id", sl.Id)
+	}
+
+	return slices.Clone(items), nil
+}
+
+func combineSmartListItems(op SmartListOp, itemsByList [][]stremio.MetaPreview) []stremio.MetaPreview {
+	if len(itemsByList) == 0 {
+		return []stremio.MetaPreview{}
+	}
+
+	items := []stremio.MetaPreview{}
+	seen := map[string]struct{}{}
+
+	switch op {
+	case SmartListOpUnion:
+		for _, listItems := range itemsByList {
+			for _, item := range listItems {
+				if _, ok := seen[item.Id]; ok {
+					continue
+				}
+				seen[item.Id] = struct{}{}
+				items = append(items, item)
+			}
+		}
+
+	case SmartListOpIntersection, SmartListOpDifference:
+		otherIds := make([]map[string]struct{}, len(itemsByList)-1)
+		for i, listItems := range itemsByList[1:] {
+			otherIds[i] = make(map[string]struct{}, len(listItems))
+			for _, item := range listItems {
+				otherIds[i][item.Id] = struct{}{}
+			}
+		}
+		for _, item := range itemsByList[0] {
+			if _, ok := seen[item.Id]; ok {
+				continue
+			}
+			seen[item.Id] = struct{}{}
+
+			keep := true
+			for _, ids := range otherIds {
+				_, found := ids[item.Id]
+				if op == SmartListOpIntersection && !found {
+					keep = false
+					break
+				}
+				if op == SmartListOpDifference && found {
+					keep = false
+					break
+				}
+			}
+			if keep {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+func parseReleaseYear(releaseInfo string) int {
+	if len(releaseInfo) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(releaseInfo[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+func (ud *UserData) applySmartListFilterAndSort(items []stremio.MetaPreview, filter *SmartListFilter, sort SmartListSort) ([]stremio.MetaPreview, error) {
+	needsMeta := filter.hasRating() || filter.hasRuntime() || len(filter.Genres) > 0 || sort == SmartListSortRatingDesc || sort == SmartListSortRuntime || sort == SmartListSortRuntimeDesc
+	needsYear := filter.hasYear() || sort == SmartListSortYear || sort == SmartListSortYearDesc
+
+	imdbIds := []string{}
+	for i := range items {
+		if strings.HasPrefix(items[i].Id, "tt") {
+			imdbIds = append(imdbIds, items[i].Id)
+		}
+	}
+
+	metaById := map[string]imdb_title.IMDBTitleMeta{}
+	if needsMeta {
+		for cImdbIds := range slices.Chunk(imdbIds, 200) {
+			if ud.MDBListAPIkey != "" {
+				byId, err := getIMDBMetaFromMDBList(cImdbIds, ud.MDBListAPIkey)
+				if err != nil {
+					return nil, err
+				}
+				for id, m := range byId {
+					metaById[id] = m
+				}
+			} else {
+				metas, err := imdb_title.GetMetasByIds(cImdbIds)
+				if err != nil {
+					return nil, err
+				}
+				for _, m := range metas {
+					metaById[m.TId] = m
+				}
+			}
+		}
+	}
+
+	yearById := map[string]int{}
+	if needsYear {
+		missingYearIds := []string{}
+		for i := range items {
+			item := &items[i]
+			if year := parseReleaseYear(item.ReleaseInfo); year > 0 {
+				yearById[item.Id] = year
+			} else if strings.HasPrefix(item.Id, "tt") {
+				missingYearIds = append(missingYearIds, item.Id)
+			}
+		}
+		for cImdbIds := range slices.Chunk(missingYearIds, 500) {
+			titles, err := imdb_title.ListByIds(cImdbIds)
+			if err != nil {
+				return nil, err
+			}
+			for _, title := range titles {
+				if title.Year > 0 {
+					yearById[title.TId] = title.Year
+				}
+			}
+		}
+	}
+
+	return filterAndSortSmartListItems(items, filter, sort, metaById, yearById), nil
+}
+
+// filterAndSortSmartListItems filters and sorts the items, with the metas and
+// the years looked up by item id.
+func filterAndSortSmartListItems(items []stremio.MetaPreview, filter *SmartListFilter, sort SmartListSort, metaById map[string]imdb_title.IMDBTitleMeta, yearById map[string]int) []stremio.MetaPreview {
+	filteredItems := make([]stremio.MetaPreview, 0, len(items))
+	for i := range items {
+		item := items[i]
+		m, hasMeta := metaById[item.Id]
+
+		if len(filter.Genres) > 0 {
+			genres := item.Genres
+			if len(genres) == 0 && hasMeta {
+				genres = m.Genres
+			}
+			if !slices.ContainsFunc(genres, func(genre string) bool {
+				return slices.ContainsFunc(filter.Genres, func(g string) bool {
+					return strings.EqualFold(g, genre)
+				})
+			}) {
+				continue
+			}
+		}
+
+		if filter.hasYear() {
+			year := yearById[item.Id]
+			if year == 0 || (filter.YearMin > 0 && year < filter.YearMin) || (filter.YearMax > 0 && year > filter.YearMax) {
+				continue
+			}
+		}
+
+		if filter.hasRating() && (!hasMeta || float64(m.Rating)/10 < filter.RatingMin) {
+			continue
+		}
+
+		if filter.hasRuntime() {
+			if !hasMeta || m.Runtime == 0 || (filter.RuntimeMin > 0 && m.Runtime < filter.RuntimeMin) || (filter.RuntimeMax > 0 && m.Runtime > filter.RuntimeMax) {
+				continue
+			}
+		}
+
+		if hasMeta && item.IMDBRating == "" && m.Rating > 0 {
+			item.IMDBRating = strconv.FormatFloat(float64(m.Rating)/10, 'f', 1, 32)
+		}
+
+		filteredItems = append(filteredItems, item)
+	}
+
+	// items missing the sort value are kept at the end
+	compareMissingLast := func(a, b int, desc bool) int {
+		if a == 0 || b == 0 {
+			return cmp.Compare(b, a)
+		}
+		if desc {
+			return cmp.Compare(b, a)
+		}
+		return cmp.Compare(a, b)
+	}
+
+	switch sort {
+	case SmartListSortName:
+		slices.SortStableFunc(filteredItems, func(a, b stremio.MetaPreview) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	case SmartListSortYear, SmartListSortYearDesc:
+		slices.SortStableFunc(filteredItems, func(a, b stremio.MetaPreview) int {
+			return compareMissingLast(yearById[a.Id], yearById[b.Id], sort == SmartListSortYearDesc)
+		})
+	case SmartListSortRatingDesc:
+		slices.SortStableFunc(filteredItems, func(a, b stremio.MetaPreview) int {
+			return compareMissingLast(metaById[a.Id].Rating, metaById[b.Id].Rating, true)
+		})
+	case SmartListSortRuntime, SmartListSortRuntimeDesc:
+		slices.SortStableFunc(filteredItems, func(a, b stremio.MetaPreview) int {
+			return compareMissingLast(metaById[a.Id].Runtime, metaById[b.Id].Runtime, sort == SmartListSortRuntimeDesc)
+		})
+	}
+
+	return filteredItems
+}
+
+func handleSmartListCatalog(w http.ResponseWriter, r *http.Request, ud *UserData, id, catalogType string) {
+	sl := ud.getSmartList(id)
+	if sl == nil {
+		shared.ErrorBadRequest(r, "invalid id").Send(w, r)
+		return
+	}
+
+	items, err := ud.getSmartListItems(sl, catalogType)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	extra := getExtra(r)
+
+	limit := 100
+	totalItems := len(items)
+	items = items[min(extra.Skip, totalItems):min(extra.Skip+limit, totalItems)]
+
+	ud.applyPreferredMetaId(items)
+
+	if ud.Shuffle {
+		rand.Shuffle(len(items), func(i, j int) {
+			items[i], items[j] = items[j], items[i]
+		})
+	}
+
+	res := stremio.CatalogHandlerResponse{
+		Metas: items,
+	}
+	SendResponse(w, r, 200, res)
+}
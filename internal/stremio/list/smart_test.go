@@ -0,0 +1,233 @@
+package stremio_list
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func toMetaPreviews(ids ...string) []stremio.MetaPreview {
+	items := make([]stremio.MetaPreview, len(ids))
+	for i, id := range ids {
+		items[i] = stremio.MetaPreview{Id: id}
+	}
+	return items
+}
+
+func toIds(items []stremio.MetaPreview) []string {
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].Id
+	}
+	return ids
+}
+
+func TestCombineSmartListItems(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		op          SmartListOp
+		itemsByList [][]stremio.MetaPreview
+		expected    []string
+	}{
+		{
+			name:     "no lists",
+			op:       SmartListOpUnion,
+			expected: []string{},
+		},
+		{
+			name: "union",
+			op:   SmartListOpUnion,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt2", "tt3"),
+				toMetaPreviews("tt3", "tt4"),
+				toMetaPreviews("tt5", "tt1"),
+			},
+			expected: []string{"tt1", "tt2", "tt3", "tt4", "tt5"},
+		},
+		{
+			name: "union, duplicates in a list",
+			op:   SmartListOpUnion,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt1", "tt2"),
+			},
+			expected: []string{"tt1", "tt2"},
+		},
+		{
+			name: "intersection",
+			op:   SmartListOpIntersection,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt2", "tt3", "tt4"),
+				toMetaPreviews("tt4", "tt2", "tt3"),
+				toMetaPreviews("tt3", "tt2", "tt5"),
+			},
+			expected: []string{"tt2", "tt3"},
+		},
+		{
+			name: "intersection, empty list",
+			op:   SmartListOpIntersection,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt2"),
+				toMetaPreviews(),
+			},
+			expected: []string{},
+		},
+		{
+			name: "difference",
+			op:   SmartListOpDifference,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt2", "tt3", "tt4", "tt1"),
+				toMetaPreviews("tt2"),
+				toMetaPreviews("tt4", "tt5"),
+			},
+			expected: []string{"tt1", "tt3"},
+		},
+		{
+			name: "difference, empty list",
+			op:   SmartListOpDifference,
+			itemsByList: [][]stremio.MetaPreview{
+				toMetaPreviews("tt1", "tt2"),
+				toMetaPreviews(),
+			},
+			expected: []string{"tt1", "tt2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, toIds(combineSmartListItems(tc.op, tc.itemsByList)))
+		})
+	}
+}
+
+func TestFilterAndSortSmartListItems(t *testing.T) {
+	items := []stremio.MetaPreview{
+		{Id: "tt1", Name: "Charlie", Genres: []string{"Drama"}},
+		{Id: "tt2", Name: "alpha", Genres: []string{"Comedy", "Drama"}},
+		{Id: "tt3", Name: "Bravo"},
+		{Id: "tt4", Name: "Delta", Genres: []string{"Horror"}},
+		{Id: "tmdb:5", Name: "Echo", Genres: []string{"Comedy"}},
+	}
+	metaById := map[string]imdb_title.IMDBTitleMeta{
+		"tt1": {TId: "tt1", Rating: 82, Runtime: 130},
+		"tt2": {TId: "tt2", Rating: 65, Runtime: 95},
+		"tt3": {TId: "tt3", Rating: 74, Runtime: 0, Genres: []string{"Comedy"}},
+		"tt4": {TId: "tt4", Rating: 0, Runtime: 110},
+	}
+	yearById := map[string]int{
+		"tt1":    1999,
+		"tt2":    2010,
+		"tt4":    2005,
+		"tmdb:5": 2020,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   SmartListFilter
+		sort     SmartListSort
+		expected []string
+	}{
+		{
+			name:     "no filter, default sort",
+			expected: []string{"tt1", "tt2", "tt3", "tt4", "tmdb:5"},
+		},
+		{
+			name:     "genres",
+			filter:   SmartListFilter{Genres: []string{"comedy"}},
+			expected: []string{"tt2", "tt3", "tmdb:5"},
+		},
+		{
+			name:     "year min",
+			filter:   SmartListFilter{YearMin: 2005},
+			expected: []string{"tt2", "tt4", "tmdb:5"},
+		},
+		{
+			name:     "year range",
+			filter:   SmartListFilter{YearMin: 2000, YearMax: 2010},
+			expected: []string{"tt2", "tt4"},
+		},
+		{
+			name:     "rating min",
+			filter:   SmartListFilter{RatingMin: 7},
+			expected: []string{"tt1", "tt3"},
+		},
+		{
+			name:     "runtime range",
+			filter:   SmartListFilter{RuntimeMin: 90, RuntimeMax: 120},
+			expected: []string{"tt2", "tt4"},
+		},
+		{
+			name:     "combined",
+			filter:   SmartListFilter{Genres: []string{"Drama"}, YearMax: 2000, RatingMin: 8},
+			expected: []string{"tt1"},
+		},
+		{
+			name:     "sort by name",
+			sort:     SmartListSortName,
+			expected: []string{"tt2", "tt3", "tt1", "tt4", "tmdb:5"},
+		},
+		{
+			name:     "sort by year, missing last",
+			sort:     SmartListSortYear,
+			expected: []string{"tt1", "tt4", "tt2", "tmdb:5", "tt3"},
+		},
+		{
+			name:     "sort by year desc, missing last",
+			sort:     SmartListSortYearDesc,
+			expected: []string{"tmdb:5", "tt2", "tt4", "tt1", "tt3"},
+		},
+		{
+			name:     "sort by rating desc, missing last",
+			sort:     SmartListSortRatingDesc,
+			expected: []string{"tt1", "tt3", "tt2", "tt4", "tmdb:5"},
+		},
+		{
+			name:     "sort by runtime, missing last",
+			sort:     SmartListSortRuntime,
+			expected: []string{"tt2", "tt4", "tt1", "tt3", "tmdb:5"},
+		},
+		{
+			name:     "sort by runtime desc, missing last",
+			sort:     SmartListSortRuntimeDesc,
+			expected: []string{"tt1", "tt4", "tt2", "tt3", "tmdb:5"},
+		},
+		{
+			name:     "filter and sort",
+			filter:   SmartListFilter{Genres: []string{"Comedy", "Drama"}},
+			sort:     SmartListSortYearDesc,
+			expected: []string{"tmdb:5", "tt2", "tt1", "tt3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := filterAndSortSmartListItems(items, &tc.filter, tc.sort, metaById, yearById)
+			assert.Equal(t, tc.expected, toIds(result))
+		})
+	}
+
+	t.Run("imdb rating from meta", func(t *testing.T) {
+		result := filterAndSortSmartListItems(items[:1], &SmartListFilter{}, SmartListSortDefault, metaById, yearById)
+		assert.Equal(t, "8.2", result[0].IMDBRating)
+		assert.Equal(t, "", items[0].IMDBRating)
+	})
+}
+
+func TestSmartListValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		sl       SmartList
+		expected string
+	}{
+		{"valid union", SmartList{Op: SmartListOpUnion, Lists: []string{"a"}}, ""},
+		{"valid intersection", SmartList{Op: SmartListOpIntersection, Lists: []string{"a", "b"}}, ""},
+		{"invalid op", SmartList{Op: "xor", Lists: []string{"a", "b"}}, "Invalid Operation"},
+		{"invalid sort", SmartList{Op: SmartListOpUnion, Lists: []string{"a"}, Sort: "random"}, "Invalid Sort"},
+		{"missing lists", SmartList{Op: SmartListOpUnion}, "Missing List URL"},
+		{"single list difference", SmartList{Op: SmartListOpDifference, Lists: []string{"a"}}, "At least 2 List URLs are required"},
+		{"invalid year range", SmartList{Op: SmartListOpUnion, Lists: []string{"a"}, Filter: SmartListFilter{YearMin: 2010, YearMax: 2000}}, "Invalid Year range"},
+		{"invalid rating", SmartList{Op: SmartListOpUnion, Lists: []string{"a"}, Filter: SmartListFilter{RatingMin: 11}}, "Invalid Rating"},
+		{"invalid runtime range", SmartList{Op: SmartListOpUnion, Lists: []string{"a"}, Filter: SmartListFilter{RuntimeMin: 120, RuntimeMax: 90}}, "Invalid Runtime range"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.sl.validate())
+		})
+	}
+}
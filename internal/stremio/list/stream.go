@@ -0,0 +1,233 @@
+package stremio_list
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	store_video "github.com/MunifTanjim/stremthru/internal/store/video"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
+	"github.com/MunifTanjim/stremthru/stremio"
+)
+
+var stremThruListStreamIdPrefixes = []string{"tt", "tmdb:", "anidb:", "anilist:", "kitsu:", "mal:"}
+
+// getEditableStremThruLists returns the StremThru lists in the userdata that
+// have a valid secret, i.e. the ones the user is allowed to change.
+func (ud *UserData) getEditableStremThruLists() ([]stremthru_list.StremThruList, error) {
+	lists := []stremthru_list.StremThruList{}
+	for _, listId := range ud.Lists {
+		service, id, err := parseListId(listId)
+		if err != nil || service != "stremthru" {
+			continue
+		}
+		secret, ok := ud.StremThruListSecrets[id]
+		if !ok {
+			continue
+		}
+		list, err := stremthru_list.GetById(id)
+		if err != nil {
+			return nil, err
+		}
+		if list == nil || !list.IsSecretValid(secret) {
+			continue
+		}
+		lists = append(lists, *list)
+	}
+	return lists, nil
+}
+
+const stremThruListActionIdPrefix = "st.list.action:"
+
+func getStremThruListActionId(contentType stremthru_list.ItemType, itemId string) string {
+	return stremThruListActionIdPrefix + string(contentType) + ":" + itemId
+}
+
+func parseStremThruListActionId(id string) (contentType stremthru_list.ItemType, itemId string, ok bool) {
+	id, ok = strings.CutPrefix(id, stremThruListActionIdPrefix)
+	if !ok {
+		return "", "", false
+	}
+	cType, itemId, ok := strings.Cut(id, ":")
+	contentType = stremthru_list.ItemType(cType)
+	if !ok || !contentType.IsValid() || itemId == "" {
+		return "", "", false
+	}
+	return contentType, itemId, true
+}
+
+// handleStream links the title to its action meta, which lists the actions
+// for the StremThru lists.
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r, false)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	res := stremio.StreamHandlerResponse{
+		Streams: []stremio.Stream{},
+	}
+
+	contentType := stremthru_list.ItemType(GetPathValue(r, "contentType"))
+	if !contentType.IsValid() {
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	itemId, err := stremthru_list.ParseItemId(GetPathValue(r, "id"))
+	if err != nil {
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	lists, err := ud.getEditableStremThruLists()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(lists) > 0 {
+		res.Streams = append(res.Streams, stremio.Stream{
+			ExternalURL: "stremio:///detail/" + string(stremio.ContentTypeOther) + "/" + url.PathEscape(getStremThruListActionId(contentType, itemId)),
+			Name:        "StremThru List",
+			Description: "📋 Add to / Remove from List",
+		})
+	}
+
+	SendResponse(w, r, 200, res)
+}
+
+// handleMeta returns the action meta of the title, with an action for each
+// StremThru list, to add the title to it or to remove the title from it.
+func handleMeta(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r, false)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	id := GetPathValue(r, "id")
+	contentType, itemId, ok := parseStremThruListActionId(id)
+	if GetPathValue(r, "contentType") != string(stremio.ContentTypeOther) || !ok {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	lists, err := ud.getEditableStremThruLists()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	released := time.Now().UTC()
+	meta := stremio.Meta{
+		Id:          id,
+		Type:        stremio.ContentTypeOther,
+		Name:        "StremThru List Actions",
+		Description: "Add the title to, or remove it from, the StremThru Lists",
+		Released:    &released,
+		Videos:      []stremio.MetaVideo{},
+	}
+
+	eud := ud.GetEncoded()
+	for i := range lists {
+		list := &lists[i]
+		hasItem, err := stremthru_list.HasItem(list.Id, itemId)
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+		action, title := "add", "➕ Add to "+list.GetDisplayName()
+		if hasItem {
+			action, title = "remove", "➖ Remove from "+list.GetDisplayName()
+		}
+		meta.Videos = append(meta.Videos, stremio.MetaVideo{
+			Id:       id + ":" + list.Id,
+			Title:    title,
+			Released: released,
+			Streams: []stremio.Stream{
+				{
+					URL:         ExtractRequestBaseURL(r).JoinPath("/stremio/list", eud, "_/action", action, list.Id, string(contentType), itemId).String(),
+					Name:        "StremThru List",
+					Description: title,
+				},
+			},
+		})
+	}
+
+	SendResponse(w, r, 200, stremio.MetaHandlerResponse{Meta: meta})
+}
+
+// handleAction adds the item to, or removes it from, the list. Both are
+// idempotent, repeating one leaves the list as it is.
+func handleAction(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r, false)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	listId := r.PathValue("listId")
+	list, err := stremthru_list.GetById(listId)
+	if err != nil {
+		LogError(r, "failed to fetch list", err)
+		store_video.Redirect("500", w, r)
+		return
+	}
+	if list == nil {
+		store_video.Redirect("500", w, r)
+		return
+	}
+	if !list.IsSecretValid(ud.StremThruListSecrets[list.Id]) {
+		store_video.Redirect("403", w, r)
+		return
+	}
+
+	inputs := []stremthru_list.ItemInput{
+		{Id: r.PathValue("itemId"), Type: stremthru_list.ItemType(r.PathValue("contentType"))},
+	}
+	switch r.PathValue("action") {
+	case "add":
+		items, err := stremthru_list.NewItems(inputs)
+		if err == nil {
+			err = stremthru_list.AddItems(list.Id, items)
+		}
+		if err != nil {
+			LogError(r, "failed to add list item", err)
+			store_video.Redirect("500", w, r)
+			return
+		}
+	case "remove":
+		ids, err := stremthru_list.ResolveItemIds(inputs)
+		if err == nil {
+			err = stremthru_list.RemoveItems(list.Id, ids)
+		}
+		if err != nil {
+			LogError(r, "failed to remove list item", err)
+			store_video.Redirect("500", w, r)
+			return
+		}
+	default:
+		shared.ErrorBadRequest(r, "unsupported action").Send(w, r)
+		return
+	}
+
+	store_video.Redirect("200", w, r)
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"html/template"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/MunifTanjim/stremthru/internal/anilist"
 	"github.com/MunifTanjim/stremthru/internal/config"
@@ -12,10 +14,12 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/oauth"
 	"github.com/MunifTanjim/stremthru/internal/serializd"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	"github.com/MunifTanjim/stremthru/internal/stremio/configure"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_template "github.com/MunifTanjim/stremthru/internal/stremio/template"
 	stremio_userdata "github.com/MunifTanjim/stremthru/internal/stremio/userdata"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
 	"github.com/MunifTanjim/stremthru/internal/tvdb"
@@ -25,6 +29,7 @@ import (
 var IsPublicInstance = config.IsPublicInstance
 var MaxPublicInstanceListCount = config.Stremio.List.PublicMaxListCount
 var TraktEnabled = config.Integration.Trakt.IsEnabled()
+var SimklEnabled = config.Integration.Simkl.IsEnabled()
 var AnimeEnabled = config.Feature.IsEnabled("anime")
 var TMDBEnabled = config.Integration.TMDB.IsEnabled()
 var TVDBEnabled = config.Integration.TVDB.IsEnabled()
@@ -91,6 +96,120 @@ func newTemplateDataList(index int) TemplateDataList {
 	}
 }
 
+type TemplateDataSmartList struct {
+	Id         string
+	Name       string
+	Type       string
+	URLs       configure.Config
+	Op         configure.Config
+	Genres     configure.Config
+	YearMin    configure.Config
+	YearMax    configure.Config
+	RatingMin  configure.Config
+	RuntimeMin configure.Config
+	RuntimeMax configure.Config
+	Sort       configure.Config
+}
+
+func newTemplateDataSmartList(index int, sl *SmartList) TemplateDataSmartList {
+	intStr := func(v int) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+	if sl == nil {
+		sl = &SmartList{Op: SmartListOpUnion}
+	}
+	ratingMin := ""
+	if sl.Filter.RatingMin > 0 {
+		ratingMin = strconv.FormatFloat(sl.Filter.RatingMin, 'f', -1, 64)
+	}
+	tdsl := TemplateDataSmartList{
+		Id:   sl.Id,
+		Name: sl.Name,
+		Type: sl.Type,
+		URLs: configure.Config{
+			Type:        "textarea",
+			Title:       "List URLs",
+			Description: "One URL per line",
+		},
+		Op: configure.Config{
+			Type:    configure.ConfigTypeSelect,
+			Title:   "Operation",
+			Default: string(sl.Op),
+			Options: []configure.ConfigOption{
+				{Value: string(SmartListOpUnion), Label: "Union (in any list)"},
+				{Value: string(SmartListOpIntersection), Label: "Intersection (in all lists)"},
+				{Value: string(SmartListOpDifference), Label: "Difference (in first list, not in others)"},
+			},
+		},
+		Genres: configure.Config{
+			Type:        configure.ConfigTypeText,
+			Title:       "Genres",
+			Default:     strings.Join(sl.Filter.Genres, ", "),
+			Description: "Comma separated, matches any",
+		},
+		YearMin: configure.Config{
+			Type:    configure.ConfigTypeNumber,
+			Title:   "Year (Min)",
+			Default: intStr(sl.Filter.YearMin),
+		},
+		YearMax: configure.Config{
+			Type:    configure.ConfigTypeNumber,
+			Title:   "Year (Max)",
+			Default: intStr(sl.Filter.YearMax),
+		},
+		RatingMin: configure.Config{
+			Type:        configure.ConfigTypeText,
+			Title:       "Rating (Min)",
+			Default:     ratingMin,
+			Description: "0-10",
+		},
+		RuntimeMin: configure.Config{
+			Type:        configure.ConfigTypeNumber,
+			Title:       "Runtime (Min)",
+			Default:     intStr(sl.Filter.RuntimeMin),
+			Description: "Minutes",
+		},
+		RuntimeMax: configure.Config{
+			Type:        configure.ConfigTypeNumber,
+			Title:       "Runtime (Max)",
+			Default:     intStr(sl.Filter.RuntimeMax),
+			Description: "Minutes",
+		},
+		Sort: configure.Config{
+			Type:    configure.ConfigTypeSelect,
+			Title:   "Sort",
+			Default: string(sl.Sort),
+			Options: []configure.ConfigOption{
+				{Value: string(SmartListSortDefault), Label: "Default"},
+				{Value: string(SmartListSortName), Label: "Name"},
+				{Value: string(SmartListSortYearDesc), Label: "Year (Newest)"},
+				{Value: string(SmartListSortYear), Label: "Year (Oldest)"},
+				{Value: string(SmartListSortRatingDesc), Label: "Rating"},
+				{Value: string(SmartListSortRuntime), Label: "Runtime (Shortest)"},
+				{Value: string(SmartListSortRuntimeDesc), Label: "Runtime (Longest)"},
+			},
+		},
+	}
+	tdsl.setIndex(index)
+	return tdsl
+}
+
+func (tdsl *TemplateDataSmartList) setIndex(index int) {
+	key := "smart_lists[" + strconv.Itoa(index) + "]"
+	tdsl.URLs.Key = key + ".urls"
+	tdsl.Op.Key = key + ".op"
+	tdsl.Genres.Key = key + ".genres"
+	tdsl.YearMin.Key = key + ".year_min"
+	tdsl.YearMax.Key = key + ".year_max"
+	tdsl.RatingMin.Key = key + ".rating_min"
+	tdsl.RuntimeMin.Key = key + ".runtime_min"
+	tdsl.RuntimeMax.Key = key + ".runtime_max"
+	tdsl.Sort.Key = key + ".sort"
+}
+
 type supportedServiceUrl struct {
 	Pattern  string
 	Examples []string
@@ -111,6 +230,14 @@ type TemplateData struct {
 	CanAddList    bool
 	CanRemoveList bool
 
+	SmartLists      []TemplateDataSmartList
+	CanAddSmartList bool
+
+	StremThruListName      configure.Config
+	CanCreateStremThruList bool
+
+	baseURL *url.URL
+
 	MDBListAPIKey configure.Config
 
 	RPDBAPIKey       configure.Config
@@ -120,6 +247,8 @@ type TemplateData struct {
 
 	TraktTokenId configure.Config
 
+	SimklTokenId configure.Config
+
 	MetaIdMovie  configure.Config
 	MetaIdSeries configure.Config
 	MetaIdAnime  configure.Config
@@ -140,7 +269,7 @@ type TemplateData struct {
 }
 
 func (td *TemplateData) HasListError() bool {
-	if len(td.Lists) == 0 {
+	if len(td.Lists) == 0 && len(td.SmartLists) == 0 {
 		return true
 	}
 	for i := range td.Lists {
@@ -148,6 +277,11 @@ func (td *TemplateData) HasListError() bool {
 			return true
 		}
 	}
+	for i := range td.SmartLists {
+		if td.SmartLists[i].URLs.Error != "" {
+			return true
+		}
+	}
 	if td.MDBListAPIKey.Error != "" {
 		return true
 	}
@@ -167,6 +301,104 @@ func (td *TemplateData) HasFieldError() bool {
 	return false
 }
 
+func (td *TemplateData) getListURL(ud *UserData, listId string) (string, string, bool) {
+	service, id, err := parseListId(listId)
+	if err != nil {
+		return "", "Failed to Parse List ID: " + listId, false
+	}
+	switch service {
+	case "anilist":
+		l := anilist.AniListList{Id: id}
+		if err := ud.FetchAniListList(&l, false); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "letterboxd":
+		l := letterboxd.LetterboxdList{Id: id}
+		if err := ud.FetchLetterboxdList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "mdblist":
+		l := mdblist.MDBListList{Id: id}
+		if err := ud.FetchMDBListList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "tmdb":
+		if td.TMDBTokenId.Error != "" {
+			return "", "TMDB authorization needed", true
+		}
+		l := tmdb.TMDBList{Id: id}
+		if err := ud.FetchTMDBList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "trakt":
+		if td.TraktTokenId.Error != "" {
+			return "", "Trakt.tv authorization needed", true
+		}
+		l := trakt.TraktList{Id: id}
+		if err := ud.FetchTraktList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "simkl":
+		if td.SimklTokenId.Error != "" {
+			return "", "Simkl authorization needed", true
+		}
+		l := simkl.SimklList{Id: id}
+		if err := ud.FetchSimklList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "serializd":
+		l := serializd.SerializdList{Id: id}
+		if err := ud.FetchSerializdList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	case "stremthru":
+		l, err := stremthru_list.GetById(id)
+		if err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		if l == nil {
+			return "", "List Not Found", false
+		}
+		listUrl := td.baseURL.JoinPath("/v0/meta/lists/stremthru", l.Id)
+		if secret := ud.StremThruListSecrets[l.Id]; secret != "" {
+			listUrl.RawQuery = url.Values{"secret": []string{secret}}.Encode()
+		}
+		return listUrl.String(), "", false
+
+	case "tvdb":
+		l := tvdb.TVDBList{Id: id}
+		if err := ud.FetchTVDBList(&l); err != nil {
+			log.Error("failed to fetch list", "error", err, "id", listId)
+			return "", "Failed to Fetch List: " + err.Error(), false
+		}
+		return l.GetURL(), "", false
+
+	}
+	return "", "", false
+}
+
 func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http.Request) *TemplateData {
 	td := &TemplateData{
 		Base: Base{
@@ -175,6 +407,13 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 			NavTitle:    "List",
 		},
 		Lists: []TemplateDataList{},
+		StremThruListName: configure.Config{
+			Key:         "stremthru_list_name",
+			Type:        configure.ConfigTypeText,
+			Title:       "Name",
+			Description: "Create a new list hosted on this StremThru instance",
+		},
+		baseURL: ExtractRequestBaseURL(r),
 		MDBListAPIKey: configure.Config{
 			Key:          "mdblist_api_key",
 			Type:         "password",
@@ -230,6 +469,20 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 			},
 			Hidden: !TraktEnabled,
 		},
+		SimklTokenId: configure.Config{
+			Key:          "simkl_token_id",
+			Title:        "Auth Code",
+			Type:         configure.ConfigTypePassword,
+			Default:      ud.SimklTokenId,
+			Error:        udError.simkl_token_id,
+			Autocomplete: "off",
+			Action: configure.ConfigAction{
+				Visible: ud.SimklTokenId == "" || udError.simkl_token_id != "",
+				Label:   "Authorize",
+				OnClick: template.JS(`window.open("` + oauth.SimklOAuthConfig.AuthCodeURL(uuid.NewString()) + `", "_blank")`),
+			},
+			Hidden: !SimklEnabled,
+		},
 		MetaIdMovie: configure.Config{
 			Key:     "meta_id_movie",
 			Title:   "Movie",
@@ -273,6 +526,16 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 		}
 	}
 
+	if SimklEnabled && td.SimklTokenId.Error == "" {
+		otok, err := ud.getSimklToken()
+		if err != nil {
+			td.SimklTokenId.Error = err.Error()
+			td.SimklTokenId.Action.Visible = true
+		} else if otok != nil {
+			td.SimklTokenId.Title += " (" + otok.UserName + ")"
+		}
+	}
+
 	if ud.Shuffle {
 		td.Shuffle.Default = "checked"
 	}
@@ -304,85 +567,7 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 				list.Error.URL = "Missing List ID"
 			}
 		} else if list.URL == "" {
-			service, id, err := parseListId(listId)
-			if err != nil {
-				list.Error.URL = "Failed to Parse List ID: " + listId
-			} else {
-				switch service {
-				case "anilist":
-					l := anilist.AniListList{Id: id}
-					if err := ud.FetchAniListList(&l, false); err != nil {
-						log.Error("failed to fetch list", "error", err, "id", listId)
-						list.Error.URL = "Failed to Fetch List: " + err.Error()
-					} else {
-						list.URL = l.GetURL()
-					}
-
-				case "letterboxd":
-					l := letterboxd.LetterboxdList{Id: id}
-					if err := ud.FetchLetterboxdList(&l); err != nil {
-						log.Error("failed to fetch list", "error", err, "id", listId)
-						list.Error.URL = "Failed to Fetch List: " + err.Error()
-					} else {
-						list.URL = l.GetURL()
-					}
-
-				case "mdblist":
-					l := mdblist.MDBListList{Id: id}
-					if err := ud.FetchMDBListList(&l); err != nil {
-						log.Error("failed to fetch list", "error", err, "id", listId)
-						list.Error.URL = "Failed to Fetch List: " + err.Error()
-					} else {
-						list.URL = l.GetURL()
-					}
-
-				case "tmdb":
-					if td.TMDBTokenId.Error == "" {
-						l := tmdb.TMDBList{Id: id}
-						if err := ud.FetchTMDBList(&l); err != nil {
-							log.Error("failed to fetch list", "error", err, "id", listId)
-							list.Error.URL = "Failed to Fetch List: " + err.Error()
-						} else {
-							list.URL = l.GetURL()
-						}
-					} else {
-						list.Disabled.URL = true
-						list.Error.URL = "TMDB authorization needed"
-					}
-
-				case "trakt":
-					if td.TraktTokenId.Error == "" {
-						l := trakt.TraktList{Id: id}
-						if err := ud.FetchTraktList(&l); err != nil {
-							log.Error("failed to fetch list", "error", err, "id", listId)
-							list.Error.URL = "Failed to Fetch List: " + err.Error()
-						} else {
-							list.URL = l.GetURL()
-						}
-					} else {
-						list.Disabled.URL = true
-						list.Error.URL = "Trakt.tv authorization needed"
-					}
-
-				case "serializd":
-					l := serializd.SerializdList{Id: id}
-					if err := ud.FetchSerializdList(&l); err != nil {
-						log.Error("failed to fetch list", "error", err, "id", listId)
-						list.Error.URL = "Failed to Fetch List: " + err.Error()
-					} else {
-						list.URL = l.GetURL()
-					}
-
-				case "tvdb":
-					l := tvdb.TVDBList{Id: id}
-					if err := ud.FetchTVDBList(&l); err != nil {
-						log.Error("failed to fetch list", "error", err, "id", listId)
-						list.Error.URL = "Failed to Fetch List: " + err.Error()
-					} else {
-						list.URL = l.GetURL()
-					}
-				}
-			}
+			list.URL, list.Error.URL, list.Disabled.URL = td.getListURL(ud, listId)
 		}
 		if list.URL == "" && list.Error.URL == "" {
 			list.Error.URL = "Missing List URL"
@@ -390,6 +575,29 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 		td.Lists = append(td.Lists, list)
 	}
 
+	for i := range ud.SmartLists {
+		sl := &ud.SmartLists[i]
+		tdsl := newTemplateDataSmartList(i, sl)
+		if len(ud.smart_list_urls) > i {
+			tdsl.URLs.Default = ud.smart_list_urls[i]
+		} else {
+			urls := make([]string, 0, len(sl.Lists))
+			for _, listId := range sl.Lists {
+				listUrl, errMsg, _ := td.getListURL(ud, listId)
+				if errMsg != "" {
+					tdsl.URLs.Error = errMsg
+					listUrl = listId
+				}
+				urls = append(urls, listUrl)
+			}
+			tdsl.URLs.Default = strings.Join(urls, "\n")
+		}
+		if len(udError.smart_list_urls) > i && udError.smart_list_urls[i] != "" {
+			tdsl.URLs.Error = udError.smart_list_urls[i]
+		}
+		td.SmartLists = append(td.SmartLists, tdsl)
+	}
+
 	td.IsAuthed = isAuthed
 
 	if udManager.IsSaved(ud) {
@@ -422,6 +630,8 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 		td.CanAuthorize = !IsPublicInstance
 		td.CanAddList = td.IsAuthed || len(td.Lists) < MaxPublicInstanceListCount
 		td.CanRemoveList = len(td.Lists) > 1
+		td.CanAddSmartList = td.IsAuthed || len(td.SmartLists) < MaxPublicInstanceListCount
+		td.CanCreateStremThruList = td.IsAuthed && td.CanAddList
 
 		td.SupportedServices = []supportedService{}
 		if AnimeEnabled {
@@ -670,6 +880,23 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 				},
 			})
 		}
+		if SimklEnabled {
+			td.SupportedServices = append(td.SupportedServices, supportedService{
+				Name:     "Simkl",
+				Hostname: "simkl.com",
+				Icon:     "https://simkl.com/favicon.ico",
+				URLs: []supportedServiceUrl{
+					{
+						Pattern: "/{own_user_id}/{movies,tv,anime}/{plantowatch,watching,completed,hold,dropped}",
+						Examples: []string{
+							"/123456/movies/plantowatch",
+							"/123456/tv/watching",
+							"/123456/anime/completed",
+						},
+					},
+				},
+			})
+		}
 		if TMDBEnabled {
 			td.SupportedServices = append(td.SupportedServices, supportedService{
 				Name:     "Serializd",
@@ -732,6 +959,9 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 			if td.TraktTokenId.Default != "" {
 				td.TraktTokenId.Default = redacted
 			}
+			if td.SimklTokenId.Default != "" {
+				td.SimklTokenId.Default = redacted
+			}
 		}
 
 		return td
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -14,8 +15,10 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/oauth"
 	"github.com/MunifTanjim/stremthru/internal/serializd"
 	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_userdata "github.com/MunifTanjim/stremthru/internal/stremio/userdata"
+	"github.com/MunifTanjim/stremthru/internal/stremthru_list"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
 	"github.com/MunifTanjim/stremthru/internal/tvdb"
@@ -30,6 +33,11 @@ type UserData struct {
 	list_urls    []string `json:"-"`
 	MDBListLists []int    `json:"mdblist_lists,omitempty"` // deprecated
 
+	StremThruListSecrets map[string]string `json:"stremthru_list_secrets,omitempty"`
+
+	SmartLists      []SmartList `json:"smart_lists,omitempty"`
+	smart_list_urls []string    `json:"-"`
+
 	MDBListAPIkey string                   `json:"mdblist_api_key,omitempty"`
 	mdblistUser   *mdblist.GetMyLimitsData `json:"-"`
 
@@ -39,6 +47,9 @@ type UserData struct {
 	TraktTokenId string            `json:"trakt_token_id,omitempty"`
 	traktToken   *oauth.OAuthToken `json:"-"`
 
+	SimklTokenId string            `json:"simkl_token_id,omitempty"`
+	simklToken   *oauth.OAuthToken `json:"-"`
+
 	RPDBAPIKey       string `json:"rpdb_api_key,omitempty"`
 	TopPostersAPIKey string `json:"top_posters_api_key,omitempty"`
 
@@ -57,12 +68,14 @@ type UserData struct {
 	tvdbById       map[string]tvdb.TVDBList             `json:"-"`
 	letterboxdById map[string]letterboxd.LetterboxdList `json:"-"`
 	serializdById  map[string]serializd.SerializdList   `json:"-"`
+	simklById      map[string]simkl.SimklList           `json:"-"`
 }
 
 func (ud UserData) StripSecrets() UserData {
 	ud.MDBListAPIkey = ""
 	ud.TMDBTokenId = ""
 	ud.TraktTokenId = ""
+	ud.SimklTokenId = ""
 	ud.RPDBAPIKey = ""
 	ud.TopPostersAPIKey = ""
 	return ud
@@ -73,7 +86,7 @@ var udManager = stremio_userdata.NewManager[UserData](&stremio_userdata.ManagerC
 })
 
 func (ud UserData) HasRequiredValues() bool {
-	return len(ud.Lists) != 0
+	return len(ud.Lists) != 0 || len(ud.SmartLists) != 0
 }
 
 func (ud UserData) GetEncoded() string {
@@ -93,8 +106,10 @@ type userDataError struct {
 		api_key string
 	}
 	list_urls           []string
+	smart_list_urls     []string
 	tmdb_token_id       string
 	trakt_token_id      string
+	simkl_token_id      string
 	meta_id_movie       string
 	meta_id_series      string
 	meta_id_anime       string
@@ -111,6 +126,11 @@ func (uderr userDataError) HasError() bool {
 			return true
 		}
 	}
+	for i := range uderr.smart_list_urls {
+		if uderr.smart_list_urls[i] != "" {
+			return true
+		}
+	}
 	if uderr.rpdb_api_key != "" {
 		return true
 	}
@@ -130,6 +150,11 @@ func (uderr userDataError) Error() string {
 			str.WriteString("mdblist.list[" + strconv.Itoa(i) + "].url: " + err + "\n")
 		}
 	}
+	for i, err := range uderr.smart_list_urls {
+		if err != "" {
+			str.WriteString("smart_list[" + strconv.Itoa(i) + "].urls: " + err + "\n")
+		}
+	}
 	return str.String()
 }
 
@@ -174,6 +199,7 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 		ud.MDBListAPIkey = r.Form.Get("mdblist_api_key")
 		ud.TMDBTokenId = r.Form.Get("tmdb_token_id")
 		ud.TraktTokenId = r.Form.Get("trakt_token_id")
+		ud.SimklTokenId = r.Form.Get("simkl_token_id")
 
 		ud.RPDBAPIKey = r.Form.Get("rpdb_api_key")
 		ud.TopPostersAPIKey = r.Form.Get("top_posters_api_key")
@@ -190,6 +216,8 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 
 		ud.Shuffle = r.Form.Get("shuffle") == "on"
 
+		ud.StremThruListSecrets = nil
+
 		lists_length := 0
 		if v := r.Form.Get("lists_length"); v != "" {
 			if lists_length, err = strconv.Atoi(v); err != nil {
@@ -203,11 +231,10 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 			return ud, err
 		}
 
-		isLetterboxdEnabled := LetterboxdEnabled
 		isMDBListEnabled := ud.MDBListAPIkey != ""
 		isTMDBConfigured := TMDBEnabled && ud.TMDBTokenId != ""
 		isTraktTvConfigured := TraktEnabled && ud.TraktTokenId != ""
-		isTVDBConfigured := TVDBEnabled
+		isSimklConfigured := SimklEnabled && ud.SimklTokenId != ""
 
 		if isMDBListEnabled {
 			if _, err := ud.getMDBListUser(); err != nil {
@@ -237,6 +264,14 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 			isTraktTvConfigured = ud.TraktTokenId != ""
 		}
 
+		if isSimklConfigured {
+			ud.simklToken, err = ud.getSimklToken()
+			if err != nil {
+				udErr.simkl_token_id = err.Error()
+			}
+			isSimklConfigured = ud.SimklTokenId != ""
+		}
+
 		ud.Lists = make([]string, 0, lists_length)
 		ud.ListNames = make([]string, 0, lists_length)
 		ud.ListTypes = make([]string, 0, lists_length)
@@ -271,391 +306,467 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 				continue
 			}
 
-			listUrl, err := url.Parse(listUrlStr)
-			if err != nil {
-				udErr.list_urls[idx] = "Invalid List URL: " + err.Error()
+			if listId, errMsg := ud.parseListURL(listUrlStr); errMsg != "" {
+				udErr.list_urls[idx] = errMsg
+			} else if listId != "" {
+				ud.Lists[idx] = listId
+			}
+		}
+
+		smart_lists_length := util.SafeParseInt(r.Form.Get("smart_lists_length"), 0)
+
+		ud.SmartLists = make([]SmartList, 0, smart_lists_length)
+		ud.smart_list_urls = make([]string, 0, smart_lists_length)
+		udErr.smart_list_urls = make([]string, 0, smart_lists_length)
+
+		for i := range smart_lists_length {
+			key := "smart_lists[" + strconv.Itoa(i) + "]"
+			urlsStr := strings.TrimSpace(r.Form.Get(key + ".urls"))
+			if !isExecutingAction && urlsStr == "" {
 				continue
 			}
 
-			switch hostname := listUrl.Hostname(); hostname {
-			case "anilist.co":
-				if !AnimeEnabled {
-					udErr.list_urls[idx] = "Unsupported List URL"
-					continue
+			sl := SmartList{
+				Id:   r.Form.Get(key + ".id"),
+				Name: r.Form.Get(key + ".name"),
+				Type: r.Form.Get(key + ".type"),
+				Op:   SmartListOp(r.Form.Get(key + ".op")),
+				Sort: SmartListSort(r.Form.Get(key + ".sort")),
+				Filter: SmartListFilter{
+					YearMin:    util.SafeParseInt(r.Form.Get(key+".year_min"), 0),
+					YearMax:    util.SafeParseInt(r.Form.Get(key+".year_max"), 0),
+					RatingMin:  util.SafeParseFloat(r.Form.Get(key+".rating_min"), 0),
+					RuntimeMin: util.SafeParseInt(r.Form.Get(key+".runtime_min"), 0),
+					RuntimeMax: util.SafeParseInt(r.Form.Get(key+".runtime_max"), 0),
+				},
+			}
+			if sl.Id == "" {
+				sl.Id = newSmartListId()
+			}
+			for genre := range strings.SplitSeq(r.Form.Get(key+".genres"), ",") {
+				if genre = strings.TrimSpace(genre); genre != "" {
+					sl.Filter.Genres = append(sl.Filter.Genres, genre)
 				}
+			}
 
-				list := anilist.AniListList{}
-				if restPath, ok := strings.CutPrefix(listUrl.Path, "/user/"); ok {
-					parts := strings.SplitN(restPath, "/", 3)
-					if len(parts) != 3 || parts[1] != "animelist" {
-						udErr.list_urls[idx] = "Invalid AniList URL"
-						continue
-					}
-					userName, listName := parts[0], parts[2]
-					if userName == "" || listName == "" {
-						udErr.list_urls[idx] = "Invalid AniList URL"
-						continue
-					}
-					list.Id = userName + ":" + listName
-				} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/search/anime/"); ok {
-					name := restPath
-					if !anilist.IsValidSearchList(name) {
-						udErr.list_urls[idx] = "Unsupported AniList URL"
-						continue
-					}
-					list.Id = "~:" + name
-				} else {
-					udErr.list_urls[idx] = "Unsupported AniList URL"
+			errMsg := ""
+			for listUrlStr := range strings.SplitSeq(urlsStr, "\n") {
+				listUrlStr = strings.TrimSpace(listUrlStr)
+				if listUrlStr == "" {
 					continue
 				}
-
-				err := ud.FetchAniListList(&list, true)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
+				listId, lErrMsg := ud.parseListURL(listUrlStr)
+				if lErrMsg == "" && listId == "" {
+					lErrMsg = "Unsupported List URL"
 				}
-				ud.Lists[idx] = "anilist:" + list.Id
-
-			case "boxd.it", "letterboxd.com":
-				if !isLetterboxdEnabled {
-					udErr.list_urls[idx] = "Unsupported List URL"
-					continue
+				if lErrMsg != "" {
+					errMsg = listUrlStr + ": " + lErrMsg
+					break
 				}
-
-				list := letterboxd.LetterboxdList{}
-
-				switch hostname {
-				case "boxd.it":
-					parts := strings.Split(strings.Trim(listUrl.Path, "/"), "/")
-					switch {
-					case len(parts) == 1:
-						list.Id = parts[0]
-					default:
-						udErr.list_urls[idx] = "Invalid List URL"
-						continue
-					}
-				case "letterboxd.com":
-					parts := strings.Split(strings.Trim(listUrl.Path, "/"), "/")
-					switch {
-					case len(parts) == 3 && parts[1] == "list":
-						username, slug := parts[0], parts[2]
-						if username == "" || slug == "" {
-							udErr.list_urls[idx] = "Invalid List URL"
-							continue
-						}
-						listId, err := letterboxd.FetchLetterboxdListIdentifier(username, slug)
-						if err != nil {
-							udErr.list_urls[idx] = "Failed to fetch list identifier: " + err.Error()
-							continue
-						}
-						userId, err := letterboxd.FetchLetterboxdUserIdentifier(username)
-						if err != nil {
-							udErr.list_urls[idx] = "Failed to fetch user identifier: " + err.Error()
-							continue
-						}
-						list.Id = listId
-						list.UserId = userId
-						list.UserName = username
-						list.Slug = slug
-					case len(parts) == 2 && parts[1] == "watchlist":
-						username, slug := parts[0], parts[1]
-						if username == "" || slug == "" {
-							udErr.list_urls[idx] = "Invalid List URL"
-							continue
-						}
-						userId, err := letterboxd.FetchLetterboxdUserIdentifier(username)
-						if err != nil {
-							udErr.list_urls[idx] = "Failed to fetch user identifier: " + err.Error()
-							continue
-						}
-						list.Id = letterboxd.ID_PREFIX_USER_WATCHLIST + userId
-						list.UserId = userId
-						list.UserName = username
-						list.Slug = slug
-					default:
-						udErr.list_urls[idx] = "Invalid List URL"
-						continue
-					}
+				if !slices.Contains(sl.Lists, listId) {
+					sl.Lists = append(sl.Lists, listId)
 				}
+			}
+			if errMsg == "" && (urlsStr != "" || !isExecutingAction) {
+				errMsg = sl.validate()
+			}
 
-				err := ud.FetchLetterboxdList(&list)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
-				}
-				ud.Lists[idx] = "letterboxd:" + list.Id
+			ud.SmartLists = append(ud.SmartLists, sl)
+			ud.smart_list_urls = append(ud.smart_list_urls, urlsStr)
+			udErr.smart_list_urls = append(udErr.smart_list_urls, errMsg)
+		}
 
-			case "mdblist.com":
-				if !isMDBListEnabled {
-					udErr.list_urls[idx] = "MDBList API Key is required"
-					continue
-				}
+		if udErr.HasError() {
+			return ud, udErr
+		}
+	}
 
-				query := listUrl.Query()
-				list := mdblist.MDBListList{}
-				if idStr := query.Get("list"); idStr != "" {
-					list.Id = idStr
-				} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/lists/"); ok {
-					username, slug, _ := strings.Cut(restPath, "/")
-					if username != "" && slug != "" && !strings.Contains(slug, "/") {
-						list.UserName = username
-						list.Slug = slug
-					} else if username != "" && strings.HasPrefix(slug, "external/") {
-						id := strings.TrimPrefix(slug, "external/")
-						if !util.IsNumericString(id) {
-							udErr.list_urls[idx] = "Invalid List URL"
-							continue
-						}
-						list.Id = mdblist.ID_PREFIX_USER_EXTERNAL + id
-						list.UserName = username
-						list.Slug = slug
-					} else {
-						udErr.list_urls[idx] = "Invalid List URL"
-						continue
-					}
-				} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/watchlist/"); ok {
-					username := restPath
-					if user, err := ud.getMDBListUser(); err != nil {
-						udErr.list_urls[idx] = "Failed to fetch user: " + err.Error()
-						continue
-					} else {
-						if !strings.EqualFold(username, user.Username) {
-							udErr.list_urls[idx] = "Invalid URL: not own list"
-							continue
-						}
-					}
-					list.Id = mdblist.ID_PREFIX_USER_WATCHLIST + username
-					list.UserName = username
-					list.Slug = "watchlist/" + username
-				} else {
-					udErr.list_urls[idx] = "Invalid List URL"
-					continue
-				}
+	if IsPublicInstance && len(ud.Lists) > MaxPublicInstanceListCount {
+		ud.Lists = ud.Lists[0:MaxPublicInstanceListCount]
+	}
+	if IsPublicInstance && len(ud.SmartLists) > MaxPublicInstanceListCount {
+		ud.SmartLists = ud.SmartLists[0:MaxPublicInstanceListCount]
+	}
 
-				err := ud.FetchMDBListList(&list)
+	return ud, nil
+}
+
+func (ud *UserData) parseListURL(listUrlStr string) (string, string) {
+	isLetterboxdEnabled := LetterboxdEnabled
+	isMDBListEnabled := ud.MDBListAPIkey != ""
+	isTMDBConfigured := TMDBEnabled && ud.TMDBTokenId != ""
+	isTraktTvConfigured := TraktEnabled && ud.TraktTokenId != ""
+	isTVDBConfigured := TVDBEnabled
+	isSimklConfigured := SimklEnabled && ud.SimklTokenId != ""
+
+	listUrl, err := url.Parse(listUrlStr)
+	if err != nil {
+		return "", "Invalid List URL: " + err.Error()
+	}
+
+	if id, ok := strings.CutPrefix(listUrl.Path, "/v0/meta/lists/stremthru/"); ok {
+		list, err := stremthru_list.GetById(id)
+		if err != nil {
+			log.Error("failed to fetch list", "error", err, "id", id)
+			return "", "Failed to Fetch List: " + err.Error()
+		}
+		if list == nil {
+			return "", "List Not Found"
+		}
+		if secret := listUrl.Query().Get("secret"); secret != "" {
+			if !list.IsSecretValid(secret) {
+				return "", "Invalid List Secret"
+			}
+			if ud.StremThruListSecrets == nil {
+				ud.StremThruListSecrets = map[string]string{}
+			}
+			ud.StremThruListSecrets[list.Id] = secret
+		}
+		return "stremthru:" + list.Id, ""
+	}
+
+	switch hostname := listUrl.Hostname(); hostname {
+	case "anilist.co":
+		if !AnimeEnabled {
+			return "", "Unsupported List URL"
+		}
+
+		list := anilist.AniListList{}
+		if restPath, ok := strings.CutPrefix(listUrl.Path, "/user/"); ok {
+			parts := strings.SplitN(restPath, "/", 3)
+			if len(parts) != 3 || parts[1] != "animelist" {
+				return "", "Invalid AniList URL"
+			}
+			userName, listName := parts[0], parts[2]
+			if userName == "" || listName == "" {
+				return "", "Invalid AniList URL"
+			}
+			list.Id = userName + ":" + listName
+		} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/search/anime/"); ok {
+			name := restPath
+			if !anilist.IsValidSearchList(name) {
+				return "", "Unsupported AniList URL"
+			}
+			list.Id = "~:" + name
+		} else {
+			return "", "Unsupported AniList URL"
+		}
+
+		err := ud.FetchAniListList(&list, true)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "anilist:" + list.Id, ""
+
+	case "boxd.it", "letterboxd.com":
+		if !isLetterboxdEnabled {
+			return "", "Unsupported List URL"
+		}
+
+		list := letterboxd.LetterboxdList{}
+
+		switch hostname {
+		case "boxd.it":
+			parts := strings.Split(strings.Trim(listUrl.Path, "/"), "/")
+			switch {
+			case len(parts) == 1:
+				list.Id = parts[0]
+			default:
+				return "", "Invalid List URL"
+			}
+		case "letterboxd.com":
+			parts := strings.Split(strings.Trim(listUrl.Path, "/"), "/")
+			switch {
+			case len(parts) == 3 && parts[1] == "list":
+				username, slug := parts[0], parts[2]
+				if username == "" || slug == "" {
+					return "", "Invalid List URL"
+				}
+				listId, err := letterboxd.FetchLetterboxdListIdentifier(username, slug)
 				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
+					return "", "Failed to fetch list identifier: " + err.Error()
 				}
-				ud.Lists[idx] = "mdblist:" + list.Id
-
-			case "www.themoviedb.org", "themoviedb.org":
-				if !isTMDBConfigured {
-					if TMDBEnabled {
-						udErr.list_urls[idx] = "TMDB Auth Code is required"
-					} else {
-						udErr.list_urls[idx] = "Unsupported List URL"
-					}
-					continue
+				userId, err := letterboxd.FetchLetterboxdUserIdentifier(username)
+				if err != nil {
+					return "", "Failed to fetch user identifier: " + err.Error()
+				}
+				list.Id = listId
+				list.UserId = userId
+				list.UserName = username
+				list.Slug = slug
+			case len(parts) == 2 && parts[1] == "watchlist":
+				username, slug := parts[0], parts[1]
+				if username == "" || slug == "" {
+					return "", "Invalid List URL"
 				}
+				userId, err := letterboxd.FetchLetterboxdUserIdentifier(username)
+				if err != nil {
+					return "", "Failed to fetch user identifier: " + err.Error()
+				}
+				list.Id = letterboxd.ID_PREFIX_USER_WATCHLIST + userId
+				list.UserId = userId
+				list.UserName = username
+				list.Slug = slug
+			default:
+				return "", "Invalid List URL"
+			}
+		}
 
-				list := tmdb.TMDBList{}
-				switch {
-				case strings.HasPrefix(listUrl.Path, "/company/"):
-					parts := strings.SplitN(strings.Trim(strings.TrimPrefix(listUrl.Path, "/company/"), "/"), "/", 2)
-					if len(parts) != 2 {
-						udErr.list_urls[idx] = "Invalid TMDB URL"
-						continue
-					}
-					companyId, _, _ := strings.Cut(parts[0], "-")
-					if !util.IsNumericString(companyId) {
-						udErr.list_urls[idx] = "Invalid TMDB URL"
-						continue
-					}
-					listType := parts[1]
-					list.Id = tmdb.ID_PREFIX_DYNAMIC_COMPANY + companyId + ":" + listType
-				case strings.HasPrefix(listUrl.Path, "/network/"):
-					identifier := strings.Trim(strings.TrimPrefix(listUrl.Path, "/network/"), "/")
-					if strings.Contains(identifier, "/") {
-						udErr.list_urls[idx] = "Invalid TMDB URL"
-						continue
-					}
-					networkId, _, _ := strings.Cut(identifier, "-")
-					if !util.IsNumericString(networkId) {
-						udErr.list_urls[idx] = "Invalid TMDB URL"
-						continue
-					}
-					list.Id = tmdb.ID_PREFIX_DYNAMIC_NETWORK + networkId
-				case strings.HasPrefix(listUrl.Path, "/list/"):
-					parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/list/"), "-", 2)
-					if !util.IsNumericString(parts[0]) {
-						udErr.list_urls[idx] = "Invalid TMDB URL"
-						continue
-					}
-					list.Id = parts[0]
-				case strings.HasPrefix(listUrl.Path, "/movie") || strings.HasPrefix(listUrl.Path, "/tv"):
-					meta := tmdb.GetDynamicListMeta(listUrl.Path)
-					if meta == nil {
-						udErr.list_urls[idx] = "Unsupported TMDB URL"
-						continue
-					}
+		err := ud.FetchLetterboxdList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "letterboxd:" + list.Id, ""
 
-					list.Id = "~:" + strings.TrimPrefix(listUrl.Path, "/")
-				case strings.HasPrefix(listUrl.Path, "/u/"):
-					parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/u/"), "/", 3)
-					username := parts[0]
-					if strings.ToLower(username) != strings.ToLower(ud.tmdbToken.UserName) {
-						udErr.list_urls[idx] = "Invalid URL: not own list"
-						continue
-					}
-					switch parts[1] {
-					case "favorites", "recommendations", "ratings", "watchlist":
-						listType := "movie"
-						if len(parts) == 3 {
-							listType = parts[2]
-						}
-						list.Id = "~:u:" + parts[1] + "/" + listType
-						list.Username = username
-					default:
-						udErr.list_urls[idx] = "Unsupported TMDB URL"
-						continue
-					}
-				default:
-					udErr.list_urls[idx] = "Unsupported TMDB URL"
-					continue
-				}
+	case "mdblist.com":
+		if !isMDBListEnabled {
+			return "", "MDBList API Key is required"
+		}
 
-				err := ud.FetchTMDBList(&list)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
+		query := listUrl.Query()
+		list := mdblist.MDBListList{}
+		if idStr := query.Get("list"); idStr != "" {
+			list.Id = idStr
+		} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/lists/"); ok {
+			username, slug, _ := strings.Cut(restPath, "/")
+			if username != "" && slug != "" && !strings.Contains(slug, "/") {
+				list.UserName = username
+				list.Slug = slug
+			} else if username != "" && strings.HasPrefix(slug, "external/") {
+				id := strings.TrimPrefix(slug, "external/")
+				if !util.IsNumericString(id) {
+					return "", "Invalid List URL"
 				}
-				ud.Lists[idx] = "tmdb:" + list.Id
-
-			case "trakt.tv", "app.trakt.tv":
-				if !isTraktTvConfigured {
-					if TraktEnabled {
-						udErr.list_urls[idx] = "Trakt.tv Auth Code is required"
-					} else {
-						udErr.list_urls[idx] = "Unsupported List URL"
-					}
-					continue
+				list.Id = mdblist.ID_PREFIX_USER_EXTERNAL + id
+				list.UserName = username
+				list.Slug = slug
+			} else {
+				return "", "Invalid List URL"
+			}
+		} else if restPath, ok := strings.CutPrefix(listUrl.Path, "/watchlist/"); ok {
+			username := restPath
+			if user, err := ud.getMDBListUser(); err != nil {
+				return "", "Failed to fetch user: " + err.Error()
+			} else {
+				if !strings.EqualFold(username, user.Username) {
+					return "", "Invalid URL: not own list"
 				}
+			}
+			list.Id = mdblist.ID_PREFIX_USER_WATCHLIST + username
+			list.UserName = username
+			list.Slug = "watchlist/" + username
+		} else {
+			return "", "Invalid List URL"
+		}
 
-				list := trakt.TraktList{}
-				switch {
-				case strings.HasPrefix(listUrl.Path, "/users/"):
-					parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/users/"), "/", 3)
-					switch {
-					case len(parts) == 3 && parts[1] == "lists":
-						userSlug, listSlug := parts[0], parts[2]
-						if userSlug == "" || listSlug == "" {
-							udErr.list_urls[idx] = "Invalid Trakt.tv URL"
-							continue
-						}
-						list.UserId = userSlug
-						list.Slug = listSlug
-
-					case len(parts) == 2:
-						switch parts[1] {
-						case "collection", "favorites", "watchlist":
-							list.Id = "~:" + parts[1] + ":" + parts[0]
-							list.UserId = parts[0]
-						case "progress":
-							list.Id = trakt.ID_PREFIX_DYNAMIC_USER_SPECIFIC + "users/" + parts[0] + "/" + parts[1]
-							list.UserId = parts[0]
-							if list.UserId != ud.traktToken.UserId {
-								udErr.list_urls[idx] = "Invalid URL: not own list"
-								continue
-							}
-						default:
-							udErr.list_urls[idx] = "Unsupported Trakt.tv URL"
-							continue
-						}
-					default:
-						udErr.list_urls[idx] = "Unsupported Trakt.tv URL"
-						continue
-					}
+		err := ud.FetchMDBListList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "mdblist:" + list.Id, ""
 
-				default:
-					meta := trakt.GetDynamicListMeta(listUrl.Path)
-					if meta == nil {
-						udErr.list_urls[idx] = "Unsupported Trakt.tv URL"
-						continue
-					}
+	case "www.themoviedb.org", "themoviedb.org":
+		if !isTMDBConfigured {
+			if TMDBEnabled {
+				return "", "TMDB Auth Code is required"
+			}
+			return "", "Unsupported List URL"
+		}
 
-					list.Id = meta.Id
-					if list.Id == "" {
-						list.Id = "~:" + strings.TrimPrefix(listUrl.Path, "/")
-					}
-					list.Slug = strings.TrimPrefix(listUrl.Path, "/")
-				}
+		list := tmdb.TMDBList{}
+		switch {
+		case strings.HasPrefix(listUrl.Path, "/company/"):
+			parts := strings.SplitN(strings.Trim(strings.TrimPrefix(listUrl.Path, "/company/"), "/"), "/", 2)
+			if len(parts) != 2 {
+				return "", "Invalid TMDB URL"
+			}
+			companyId, _, _ := strings.Cut(parts[0], "-")
+			if !util.IsNumericString(companyId) {
+				return "", "Invalid TMDB URL"
+			}
+			listType := parts[1]
+			list.Id = tmdb.ID_PREFIX_DYNAMIC_COMPANY + companyId + ":" + listType
+		case strings.HasPrefix(listUrl.Path, "/network/"):
+			identifier := strings.Trim(strings.TrimPrefix(listUrl.Path, "/network/"), "/")
+			if strings.Contains(identifier, "/") {
+				return "", "Invalid TMDB URL"
+			}
+			networkId, _, _ := strings.Cut(identifier, "-")
+			if !util.IsNumericString(networkId) {
+				return "", "Invalid TMDB URL"
+			}
+			list.Id = tmdb.ID_PREFIX_DYNAMIC_NETWORK + networkId
+		case strings.HasPrefix(listUrl.Path, "/list/"):
+			parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/list/"), "-", 2)
+			if !util.IsNumericString(parts[0]) {
+				return "", "Invalid TMDB URL"
+			}
+			list.Id = parts[0]
+		case strings.HasPrefix(listUrl.Path, "/movie") || strings.HasPrefix(listUrl.Path, "/tv"):
+			meta := tmdb.GetDynamicListMeta(listUrl.Path)
+			if meta == nil {
+				return "", "Unsupported TMDB URL"
+			}
 
-				err := ud.FetchTraktList(&list)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
-				}
-				if util.IsNumericString(list.Id) && list.UserId != "" && list.Slug != "" {
-					ud.Lists[idx] = "trakt:" + list.UserId + "." + list.Slug
-				} else {
-					ud.Lists[idx] = "trakt:" + list.Id
+			list.Id = "~:" + strings.TrimPrefix(listUrl.Path, "/")
+		case strings.HasPrefix(listUrl.Path, "/u/"):
+			parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/u/"), "/", 3)
+			username := parts[0]
+			if strings.ToLower(username) != strings.ToLower(ud.tmdbToken.UserName) {
+				return "", "Invalid URL: not own list"
+			}
+			switch parts[1] {
+			case "favorites", "recommendations", "ratings", "watchlist":
+				listType := "movie"
+				if len(parts) == 3 {
+					listType = parts[2]
 				}
+				list.Id = "~:u:" + parts[1] + "/" + listType
+				list.Username = username
+			default:
+				return "", "Unsupported TMDB URL"
+			}
+		default:
+			return "", "Unsupported TMDB URL"
+		}
 
-			case "www.thetvdb.com", "thetvdb.com":
-				if !isTVDBConfigured {
-					udErr.list_urls[idx] = "Unsupported List URL"
-					continue
-				}
+		err := ud.FetchTMDBList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "tmdb:" + list.Id, ""
 
-				list := tvdb.TVDBList{}
-				switch {
-				case strings.HasPrefix(listUrl.Path, "/lists/"):
-					idOrSlug := strings.TrimPrefix(listUrl.Path, "/lists/")
-					if util.IsNumericString(idOrSlug) {
-						list.Id = idOrSlug
-					} else {
-						list.Slug = idOrSlug
-					}
-				default:
-					udErr.list_urls[idx] = "Unsupported TVDB URL"
-					continue
-				}
+	case "trakt.tv", "app.trakt.tv":
+		if !isTraktTvConfigured {
+			if TraktEnabled {
+				return "", "Trakt.tv Auth Code is required"
+			}
+			return "", "Unsupported List URL"
+		}
 
-				err := ud.FetchTVDBList(&list)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
+		list := trakt.TraktList{}
+		switch {
+		case strings.HasPrefix(listUrl.Path, "/users/"):
+			parts := strings.SplitN(strings.TrimPrefix(listUrl.Path, "/users/"), "/", 3)
+			switch {
+			case len(parts) == 3 && parts[1] == "lists":
+				userSlug, listSlug := parts[0], parts[2]
+				if userSlug == "" || listSlug == "" {
+					return "", "Invalid Trakt.tv URL"
 				}
-				ud.Lists[idx] = "tvdb:" + list.Id
-
-			case "www.serializd.com", "serializd.com":
-				if !isTMDBConfigured {
-					if TMDBEnabled {
-						udErr.list_urls[idx] = "TMDB Auth Code is required"
-					} else {
-						udErr.list_urls[idx] = "Unsupported List URL"
+				list.UserId = userSlug
+				list.Slug = listSlug
+
+			case len(parts) == 2:
+				switch parts[1] {
+				case "collection", "favorites", "watchlist":
+					list.Id = "~:" + parts[1] + ":" + parts[0]
+					list.UserId = parts[0]
+				case "progress":
+					list.Id = trakt.ID_PREFIX_DYNAMIC_USER_SPECIFIC + "users/" + parts[0] + "/" + parts[1]
+					list.UserId = parts[0]
+					if list.UserId != ud.traktToken.UserId {
+						return "", "Invalid URL: not own list"
 					}
-					continue
+				default:
+					return "", "Unsupported Trakt.tv URL"
 				}
+			default:
+				return "", "Unsupported Trakt.tv URL"
+			}
 
-				list := serializd.SerializdList{}
-				path := strings.Trim(listUrl.Path, "/")
-				listId := serializd.ID_PREFIX_DYNAMIC + path
-				if !serializd.IsValidListId(listId) {
-					udErr.list_urls[idx] = "Unsupported Serializd URL"
-					continue
-				}
-				list.Id = listId
-				err := ud.FetchSerializdList(&list)
-				if err != nil {
-					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
-					continue
-				}
-				ud.Lists[idx] = "serializd:" + list.Id
+		default:
+			meta := trakt.GetDynamicListMeta(listUrl.Path)
+			if meta == nil {
+				return "", "Unsupported Trakt.tv URL"
 			}
+
+			list.Id = meta.Id
+			if list.Id == "" {
+				list.Id = "~:" + strings.TrimPrefix(listUrl.Path, "/")
+			}
+			list.Slug = strings.TrimPrefix(listUrl.Path, "/")
 		}
 
-		if udErr.HasError() {
-			return ud, udErr
+		err := ud.FetchTraktList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		if util.IsNumericString(list.Id) && list.UserId != "" && list.Slug != "" {
+			return "trakt:" + list.UserId + "." + list.Slug, ""
+		} else {
+			return "trakt:" + list.Id, ""
 		}
-	}
 
-	if IsPublicInstance && len(ud.Lists) > MaxPublicInstanceListCount {
-		ud.Lists = ud.Lists[0:MaxPublicInstanceListCount]
-	}
+	case "www.thetvdb.com", "thetvdb.com":
+		if !isTVDBConfigured {
+			return "", "Unsupported List URL"
+		}
 
-	return ud, nil
+		list := tvdb.TVDBList{}
+		switch {
+		case strings.HasPrefix(listUrl.Path, "/lists/"):
+			idOrSlug := strings.TrimPrefix(listUrl.Path, "/lists/")
+			if util.IsNumericString(idOrSlug) {
+				list.Id = idOrSlug
+			} else {
+				list.Slug = idOrSlug
+			}
+		default:
+			return "", "Unsupported TVDB URL"
+		}
+
+		err := ud.FetchTVDBList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "tvdb:" + list.Id, ""
+
+	case "www.serializd.com", "serializd.com":
+		if !isTMDBConfigured {
+			if TMDBEnabled {
+				return "", "TMDB Auth Code is required"
+			}
+			return "", "Unsupported List URL"
+		}
+
+		list := serializd.SerializdList{}
+		path := strings.Trim(listUrl.Path, "/")
+		listId := serializd.ID_PREFIX_DYNAMIC + path
+		if !serializd.IsValidListId(listId) {
+			return "", "Unsupported Serializd URL"
+		}
+		list.Id = listId
+		err := ud.FetchSerializdList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "serializd:" + list.Id, ""
+
+	case "simkl.com", "www.simkl.com":
+		if !isSimklConfigured {
+			if SimklEnabled {
+				return "", "Simkl Auth Code is required"
+			}
+			return "", "Unsupported List URL"
+		}
+
+		listId, userId, ok := simkl.ParseListURLPath(listUrl.Path)
+		if !ok {
+			return "", "Unsupported Simkl URL"
+		}
+		if userId != ud.simklToken.UserId {
+			return "", "Invalid URL: not own list"
+		}
+
+		list := simkl.SimklList{Id: listId}
+		err := ud.FetchSimklList(&list)
+		if err != nil {
+			return "", "Failed to fetch List: " + err.Error()
+		}
+		return "simkl:" + list.Id, ""
+	}
+	return "", ""
 }
 
 func (ud *UserData) getTraktToken() (*oauth.OAuthToken, error) {
@@ -693,6 +804,32 @@ func (ud *UserData) getTraktToken() (*oauth.OAuthToken, error) {
 	return ud.traktToken, nil
 }
 
+func (ud *UserData) getSimklToken() (*oauth.OAuthToken, error) {
+	if ud.SimklTokenId == "" {
+		return nil, nil
+	}
+
+	if ud.simklToken != nil {
+		return ud.simklToken, nil
+	}
+
+	otok, err := oauth.GetOAuthTokenById(ud.SimklTokenId)
+	if err != nil {
+		ud.SimklTokenId = ""
+		return nil, errors.New("failed to retrieve token: " + err.Error())
+	} else if otok != nil && otok.AccessToken == "" {
+		// simkl tokens do not expire, empty access token means it was revoked
+		otok = nil
+	}
+	if otok == nil {
+		ud.SimklTokenId = ""
+		return nil, errors.New("Invalid or Revoked")
+	}
+
+	ud.simklToken = otok
+	return ud.simklToken, nil
+}
+
 func (ud *UserData) getTMDBToken() (*oauth.OAuthToken, error) {
 	if ud.TMDBTokenId == "" {
 		return nil, nil
@@ -908,3 +1045,24 @@ func (ud *UserData) FetchSerializdList(list *serializd.SerializdList) error {
 	ud.serializdById[list.Id] = *list
 	return nil
 }
+
+func (ud *UserData) FetchSimklList(list *simkl.SimklList) error {
+	if ud.SimklTokenId == "" {
+		return errors.New("Simkl Auth Code missing")
+	}
+	if ud.simklById == nil {
+		ud.simklById = map[string]simkl.SimklList{}
+	}
+	if list.Id != "" {
+		if l, ok := ud.simklById[list.Id]; ok {
+			*list = l
+			return nil
+		}
+	}
+	if err := list.Fetch(ud.SimklTokenId); err != nil {
+		return err
+	}
+
+	ud.simklById[list.Id] = *list
+	return nil
+}
@@ -0,0 +1,312 @@
+package stremio_meta
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/tvdb"
+	"github.com/MunifTanjim/stremthru/stremio"
+)
+
+const searchLimit = 50
+
+type ExtraData struct {
+	Search string
+}
+
+func getExtra(r *http.Request) *ExtraData {
+	extra := &ExtraData{}
+	if extraParams := GetPathValue(r, "extra"); extraParams != "" {
+		if q, err := url.ParseQuery(extraParams); err == nil {
+			extra.Search = strings.TrimSpace(q.Get("search"))
+		}
+	}
+	return extra
+}
+
+func getTrailers(trailerUrl string) []stremio.MetaTrailer {
+	if trailerUrl == "" {
+		return nil
+	}
+	trailer, err := url.Parse(trailerUrl)
+	if err != nil || !strings.HasSuffix(trailer.Host, "youtube.com") {
+		return nil
+	}
+	return []stremio.MetaTrailer{
+		{
+			Source: trailer.Query().Get("v"),
+			Type:   "Trailer",
+		},
+	}
+}
+
+func formatRating(rating int) string {
+	if rating == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(rating)/10, 'f', 1, 32)
+}
+
+// getTVDBItems returns the locally stored tvdb items for the imdb ids, with
+// name and overview translated to lang.
+func getTVDBItems(itemType tvdb.TVDBItemType, imdbIds []string, lang *language) (map[string]*tvdb.TVDBItem, error) {
+	itemByImdbId := map[string]*tvdb.TVDBItem{}
+	if !config.Integration.TVDB.IsEnabled() || len(imdbIds) == 0 {
+		return itemByImdbId, nil
+	}
+
+	tvdbIdByImdbId, err := imdb_title.GetTVDBIdByIMDBId(imdbIds)
+	if err != nil {
+		return nil, err
+	}
+
+	imdbIdByTvdbId := make(map[int]string, len(tvdbIdByImdbId))
+	tvdbIds := make([]int, 0, len(tvdbIdByImdbId))
+	for imdbId, tvdbIdStr := range tvdbIdByImdbId {
+		if tvdbId, err := strconv.Atoi(tvdbIdStr); err == nil && tvdbId > 0 {
+			imdbIdByTvdbId[tvdbId] = imdbId
+			tvdbIds = append(tvdbIds, tvdbId)
+		}
+	}
+	if len(tvdbIds) == 0 {
+		return itemByImdbId, nil
+	}
+
+	itemById, err := tvdb.GetItemsById(itemType, tvdbIds...)
+	if err != nil {
+		return nil, err
+	}
+	translationById, err := tvdb.GetTranslations(itemType, lang.Code, tvdbIds...)
+	if err != nil {
+		return nil, err
+	}
+	for tvdbId, item := range itemById {
+		if t, ok := translationById[tvdbId]; ok {
+			if t.Name != "" {
+				item.Name = t.Name
+			}
+			if t.Overview != "" {
+				item.Overview = t.Overview
+			}
+		}
+		itemByImdbId[imdbIdByTvdbId[tvdbId]] = item
+	}
+	return itemByImdbId, nil
+}
+
+func searchIMDBTitles(contentType stremio.ContentType, query string, lang *language) ([]stremio.MetaPreview, error) {
+	titleType := imdb_title.SearchTitleTypeMovie
+	itemType := tvdb.TVDBItemTypeMovie
+	if contentType == stremio.ContentTypeSeries {
+		titleType = imdb_title.SearchTitleTypeShow
+		itemType = tvdb.TVDBItemTypeSeries
+	}
+
+	ids, err := imdb_title.SearchIds(query, titleType, 0, false, searchLimit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	titles, err := imdb_title.ListByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	titleById := make(map[string]*imdb_title.IMDBTitle, len(titles))
+	for i := range titles {
+		titleById[titles[i].TId] = &titles[i]
+	}
+
+	metas, err := imdb_title.GetMetasByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	metaById := make(map[string]*imdb_title.IMDBTitleMeta, len(metas))
+	for i := range metas {
+		metaById[metas[i].TId] = &metas[i]
+	}
+
+	tvdbItemByImdbId, err := getTVDBItems(itemType, ids, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]stremio.MetaPreview, 0, len(ids))
+	for _, id := range ids {
+		title, ok := titleById[id]
+		if !ok || title.IsAdult {
+			continue
+		}
+		item := stremio.MetaPreview{
+			Id:          id,
+			Type:        contentType,
+			Name:        title.Title,
+			PosterShape: stremio.MetaPosterShapePoster,
+		}
+		if title.Year > 0 {
+			item.ReleaseInfo = strconv.Itoa(title.Year)
+		}
+		if m, ok := metaById[id]; ok {
+			item.Description = m.Description
+			item.Poster = m.Poster
+			item.Background = m.Backdrop
+			item.Genres = m.Genres
+			item.IMDBRating = formatRating(m.Rating)
+			item.Trailers = getTrailers(m.Trailer)
+		}
+		if tvdbItem, ok := tvdbItemByImdbId[id]; ok {
+			if tvdbItem.Name != "" {
+				item.Name = tvdbItem.Name
+			}
+			if tvdbItem.Overview != "" {
+				item.Description = tvdbItem.Overview
+			}
+			if item.Poster == "" {
+				item.Poster = tvdbItem.Poster
+			}
+			if item.Background == "" {
+				item.Background = tvdbItem.Background
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func getAnimeId(idMap *anime.AnimeIdMap) string {
+	if idMap != nil {
+		if idMap.Kitsu != "" {
+			return "kitsu:" + idMap.Kitsu
+		}
+		if idMap.MAL != "" {
+			return "mal:" + idMap.MAL
+		}
+	}
+	return ""
+}
+
+func searchAnimeTitles(query string, lang *language) ([]stremio.MetaPreview, error) {
+	ids, err := anidb.SearchIdsByTitle(query, nil, 0, searchLimit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	titles, err := anidb.GetTitlesByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	idMaps, err := anime.GetIdMapsForAniDB(ids)
+	if err != nil {
+		return nil, err
+	}
+	idMapByAniDBId := make(map[string]*anime.AnimeIdMap, len(idMaps))
+	tvdbIds := []int{}
+	for i := range idMaps {
+		idMap := &idMaps[i]
+		idMapByAniDBId[idMap.AniDB] = idMap
+		if tvdbId, err := strconv.Atoi(idMap.TVDB); err == nil && tvdbId > 0 {
+			tvdbIds = append(tvdbIds, tvdbId)
+		}
+	}
+
+	tvdbItemById := map[int]*tvdb.TVDBItem{}
+	if config.Integration.TVDB.IsEnabled() && len(tvdbIds) > 0 {
+		if tvdbItemById, err = tvdb.GetItemsById(tvdb.TVDBItemTypeSeries, tvdbIds...); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]stremio.MetaPreview, 0, len(ids))
+	for _, anidbId := range ids {
+		idMap := idMapByAniDBId[anidbId]
+		id := getAnimeId(idMap)
+		if id == "" {
+			id = "anidb:" + anidbId
+		}
+		item := stremio.MetaPreview{
+			Id:          id,
+			Type:        stremio.ContentTypeAnime,
+			Name:        titles.GetTitle(anidbId, lang.ShortCode),
+			PosterShape: stremio.MetaPosterShapePoster,
+		}
+		if item.Name == "" {
+			continue
+		}
+		if year := titles.GetYear(anidbId); year > 0 {
+			item.ReleaseInfo = strconv.Itoa(year)
+		}
+		if idMap != nil {
+			if tvdbId, err := strconv.Atoi(idMap.TVDB); err == nil {
+				if tvdbItem, ok := tvdbItemById[tvdbId]; ok {
+					item.Poster = tvdbItem.Poster
+					item.Background = tvdbItem.Background
+				}
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	contentType := stremio.ContentType(GetPathValue(r, "contentType"))
+	catalogId := GetPathValue(r, "id")
+	extra := getExtra(r)
+
+	res := stremio.CatalogHandlerResponse{
+		Metas: []stremio.MetaPreview{},
+	}
+
+	if extra.Search == "" {
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	var items []stremio.MetaPreview
+	switch catalogId {
+	case catalogIdSearch:
+		if contentType != stremio.ContentTypeMovie && contentType != stremio.ContentTypeSeries {
+			shared.ErrorBadRequest(r, "invalid type").Send(w, r)
+			return
+		}
+		items, err = searchIMDBTitles(contentType, extra.Search, ud.GetLanguage())
+	case catalogIdAnimeSearch:
+		if !config.Feature.IsEnabled(config.FeatureAnime) {
+			shared.ErrorNotFound(r).Send(w, r)
+			return
+		}
+		items, err = searchAnimeTitles(extra.Search, ud.GetLanguage())
+	default:
+		shared.ErrorBadRequest(r, "invalid id").Send(w, r)
+		return
+	}
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if items != nil {
+		res.Metas = items
+	}
+
+	SendResponse(w, r, 200, res)
+}
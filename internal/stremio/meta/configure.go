@@ -0,0 +1,103 @@
+package stremio_meta
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/stremio/configure"
+	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+)
+
+func getTemplateData(ud *UserData) *configure.TemplateData {
+	lang := ud.Lang
+	if lang == "" {
+		lang = defaultLanguageCode
+	}
+
+	langOptions := make([]configure.ConfigOption, len(languages))
+	for i := range languages {
+		langOptions[i] = configure.ConfigOption{
+			Value: languages[i].Code,
+			Label: languages[i].Name,
+		}
+	}
+
+	return &configure.TemplateData{
+		Base: configure.Base{
+			Title:           "StremThru Meta",
+			Description:     "Metadata from Local Datasets",
+			NavTitle:        "Meta",
+			StremThruAddons: stremio_shared.GetStremThruAddons(),
+		},
+		Configs: []configure.Config{
+			{
+				Key:         "lang",
+				Type:        configure.ConfigTypeSelect,
+				Default:     lang,
+				Title:       "Language",
+				Description: "Preferred language for titles and overviews",
+				Options:     langOptions,
+			},
+		},
+	}
+}
+
+func sendPage(w http.ResponseWriter, r *http.Request, td *configure.TemplateData) {
+	page, err := configure.GetPage(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, page)
+}
+
+func isAuthed(w http.ResponseWriter, r *http.Request) bool {
+	cookie, err := stremio_shared.GetAdminCookieValue(w, r)
+	return err == nil && !cookie.IsExpired && config.Auth.GetPassword(cookie.User()) == cookie.Pass()
+}
+
+func handleConfigure(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) && !IsMethod(r, http.MethodPost) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	td := getTemplateData(ud)
+
+	if IsMethod(r, http.MethodPost) {
+		isSaved := udManager.IsSaved(ud)
+		if config.Stremio.Locked || isSaved {
+			if !isAuthed(w, r) {
+				td.Configs[0].Error = "Locked, authorize from the Store addon to make changes"
+				sendPage(w, r, td)
+				return
+			}
+		}
+
+		if !isSaved && config.Stremio.Locked {
+			err = udManager.Save(ud, "")
+		} else {
+			err = udManager.Sync(ud)
+		}
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+
+		stremio_shared.RedirectToConfigurePage(w, r, "meta", ud.GetEncoded(), true)
+		return
+	}
+
+	if ud.HasRequiredValues() {
+		td.ManifestURL = ExtractRequestBaseURL(r).JoinPath("/stremio/meta/" + ud.GetEncoded() + "/manifest.json").String()
+	}
+
+	sendPage(w, r, td)
+}
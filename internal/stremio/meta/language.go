@@ -0,0 +1,49 @@
+package stremio_meta
+
+type language struct {
+	Code      string // ISO 639-2, used by tvdb
+	ShortCode string // ISO 639-1, used by anidb
+	Name      string
+}
+
+const defaultLanguageCode = "eng"
+
+var languages = []language{
+	{Code: "eng", ShortCode: "en", Name: "English"},
+	{Code: "ara", ShortCode: "ar", Name: "Arabic"},
+	{Code: "zho", ShortCode: "zh", Name: "Chinese"},
+	{Code: "ces", ShortCode: "cs", Name: "Czech"},
+	{Code: "dan", ShortCode: "da", Name: "Danish"},
+	{Code: "nld", ShortCode: "nl", Name: "Dutch"},
+	{Code: "fin", ShortCode: "fi", Name: "Finnish"},
+	{Code: "fra", ShortCode: "fr", Name: "French"},
+	{Code: "deu", ShortCode: "de", Name: "German"},
+	{Code: "ell", ShortCode: "el", Name: "Greek"},
+	{Code: "heb", ShortCode: "he", Name: "Hebrew"},
+	{Code: "hin", ShortCode: "hi", Name: "Hindi"},
+	{Code: "hun", ShortCode: "hu", Name: "Hungarian"},
+	{Code: "ind", ShortCode: "id", Name: "Indonesian"},
+	{Code: "ita", ShortCode: "it", Name: "Italian"},
+	{Code: "jpn", ShortCode: "ja", Name: "Japanese"},
+	{Code: "kor", ShortCode: "ko", Name: "Korean"},
+	{Code: "nor", ShortCode: "no", Name: "Norwegian"},
+	{Code: "pol", ShortCode: "pl", Name: "Polish"},
+	{Code: "por", ShortCode: "pt", Name: "Portuguese"},
+	{Code: "ron", ShortCode: "ro", Name: "Romanian"},
+	{Code: "rus", ShortCode: "ru", Name: "Russian"},
+	{Code: "spa", ShortCode: "es", Name: "Spanish"},
+	{Code: "swe", ShortCode: "sv", Name: "Swedish"},
+	{Code: "tha", ShortCode: "th", Name: "Thai"},
+	{Code: "tur", ShortCode: "tr", Name: "Turkish"},
+	{Code: "ukr", ShortCode: "uk", Name: "Ukrainian"},
+	{Code: "vie", ShortCode: "vi", Name: "Vietnamese"},
+}
+
+func getLanguage(code string) *language {
+	for i := range languages {
+		if languages[i].Code == code {
+			return &languages[i]
+		}
+	}
+	return nil
+}
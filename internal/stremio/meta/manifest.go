@@ -0,0 +1,99 @@
+package stremio_meta
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/stremio"
+)
+
+const (
+	catalogIdSearch      = "st.meta.search"
+	catalogIdAnimeSearch = "st.meta.anime.search"
+)
+
+func getSearchCatalog(contentType stremio.ContentType, id, name string) stremio.Catalog {
+	return stremio.Catalog{
+		Type: string(contentType),
+		Id:   id,
+		Name: name,
+		Extra: []stremio.CatalogExtra{
+			{
+				Name:       "search",
+				IsRequired: true,
+			},
+		},
+	}
+}
+
+func GetManifest(r *http.Request, ud *UserData) *stremio.Manifest {
+	isConfigured := ud.HasRequiredValues()
+	hasAnime := config.Feature.IsEnabled(config.FeatureAnime)
+
+	id := shared.GetReversedHostname(r) + ".meta"
+	name := "StremThru Meta"
+	description := "Stremio Addon for Metadata from Local Datasets"
+
+	types := []stremio.ContentType{stremio.ContentTypeMovie, stremio.ContentTypeSeries}
+	idPrefixes := []string{"tt", "tvdb:"}
+	if hasAnime {
+		types = append(types, stremio.ContentTypeAnime)
+		idPrefixes = append(idPrefixes, "kitsu:", "mal:", "anidb:")
+	}
+
+	catalogs := []stremio.Catalog{}
+	if isConfigured {
+		catalogs = append(catalogs,
+			getSearchCatalog(stremio.ContentTypeMovie, catalogIdSearch, "Search"),
+			getSearchCatalog(stremio.ContentTypeSeries, catalogIdSearch, "Search"),
+		)
+		if hasAnime {
+			catalogs = append(catalogs, getSearchCatalog(stremio.ContentTypeAnime, catalogIdAnimeSearch, "Search"))
+		}
+	}
+
+	manifest := &stremio.Manifest{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Version:     config.Version,
+		Logo:        "https://emojiapi.dev/api/v1/sparkles/256.png",
+		Resources: []stremio.Resource{
+			{
+				Name:  stremio.ResourceNameCatalog,
+				Types: types,
+			},
+			{
+				Name:       stremio.ResourceNameMeta,
+				Types:      types,
+				IDPrefixes: idPrefixes,
+			},
+		},
+		Types:    types,
+		Catalogs: catalogs,
+		BehaviorHints: &stremio.BehaviorHints{
+			Configurable:          true,
+			ConfigurationRequired: !isConfigured,
+		},
+	}
+
+	return manifest
+}
+
+func handleManifest(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	manifest := GetManifest(r, ud)
+
+	SendResponse(w, r, 200, manifest)
+}
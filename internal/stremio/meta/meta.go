@@ -0,0 +1,491 @@
+package stremio_meta
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/tmdb"
+	"github.com/MunifTanjim/stremthru/internal/tvdb"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"golang.org/x/sync/singleflight"
+)
+
+const metaCacheLifetime = 6 * time.Hour
+
+var metaCache = cache.NewCache[stremio.Meta](&cache.CacheConfig{
+	Name:     "stremio:meta:meta",
+	Lifetime: metaCacheLifetime,
+})
+
+var metaGroup singleflight.Group
+
+// seriesEpisode is the episode from tvdb, or from tmdb for the series
+// without tvdb episodes.
+type seriesEpisode struct {
+	Season         int
+	Number         int
+	AbsoluteNumber int
+	Name           string
+	Overview       string
+	Aired          string // YYYY-MM-DD
+	Thumbnail      string
+	TVDBId         int
+}
+
+func toVideo(id string, season, episode int, ep *seriesEpisode) stremio.MetaVideo {
+	video := stremio.MetaVideo{
+		Id:        id,
+		Title:     ep.Name,
+		Season:    stremio.ZeroIndexedInt(season),
+		Episode:   stremio.ZeroIndexedInt(episode),
+		Overview:  ep.Overview,
+		Thumbnail: ep.Thumbnail,
+		TVDBId:    ep.TVDBId,
+	}
+	if released, err := time.Parse(time.DateOnly, ep.Aired); err == nil {
+		video.Released = released
+	}
+	if video.Title == "" {
+		video.Title = "Episode " + strconv.Itoa(episode)
+	}
+	return video
+}
+
+func applyIMDBMeta(meta *stremio.Meta, imdbId string) error {
+	metas, err := imdb_title.GetMetasByIds([]string{imdbId})
+	if err != nil {
+		return err
+	}
+	if len(metas) == 0 {
+		return nil
+	}
+	m := &metas[0]
+	meta.Description = m.Description
+	meta.Poster = m.Poster
+	meta.Background = m.Backdrop
+	meta.Genres = m.Genres
+	meta.IMDBRating = formatRating(m.Rating)
+	meta.Trailers = getTrailers(m.Trailer)
+	if m.Runtime > 0 {
+		meta.Runtime = strconv.Itoa(m.Runtime) + " min"
+	}
+	return nil
+}
+
+// applyTVDBItem fills the missing fields from the stored tvdb item, and
+// prefers its translated name and overview.
+func applyTVDBItem(meta *stremio.Meta, itemType tvdb.TVDBItemType, tvdbId int, lang *language) error {
+	item, err := tvdb.GetStoredItem(itemType, tvdbId)
+	if err != nil {
+		return err
+	}
+	if item == nil || !item.HasBasicMeta() {
+		return nil
+	}
+
+	meta.TVDBId = stremio.Number(strconv.Itoa(tvdbId))
+
+	if meta.Name == "" {
+		meta.Name = item.Name
+	}
+	if meta.Description == "" {
+		meta.Description = item.Overview
+	}
+	if meta.Poster == "" {
+		meta.Poster = item.Poster
+	}
+	if meta.Background == "" {
+		meta.Background = item.Background
+	}
+	if len(meta.Trailers) == 0 {
+		meta.Trailers = getTrailers(item.Trailer)
+	}
+	if meta.Runtime == "" && item.Runtime > 0 {
+		meta.Runtime = strconv.Itoa(item.Runtime) + " min"
+	}
+	if meta.ReleaseInfo == "" && item.Year > 0 {
+		meta.ReleaseInfo = strconv.Itoa(item.Year)
+	}
+
+	t, err := tvdb.GetTranslation(itemType, tvdbId, lang.Code)
+	if err != nil {
+		return err
+	}
+	if t != nil {
+		if t.Name != "" {
+			meta.Name = t.Name
+		}
+		if t.Overview != "" {
+			meta.Description = t.Overview
+		}
+	}
+	return nil
+}
+
+// getSeriesEpisodes returns the stored episodes of the series, from tvdb,
+// or from tmdb if tvdb has none.
+func getSeriesEpisodes(tvdbId, tmdbId int, lang *language) ([]seriesEpisode, error) {
+	if tvdbId > 0 {
+		tvdbEpisodes, err := tvdb.GetStoredSeriesEpisodes(tvdbId, lang.Code)
+		if err != nil {
+			return nil, err
+		}
+		if len(tvdbEpisodes) > 0 {
+			episodes := make([]seriesEpisode, len(tvdbEpisodes))
+			for i := range tvdbEpisodes {
+				ep := &tvdbEpisodes[i]
+				episodes[i] = seriesEpisode{
+					Season:         ep.Season,
+					Number:         ep.Number,
+					AbsoluteNumber: ep.AbsoluteNumber,
+					Name:           ep.Name,
+					Overview:       ep.Overview,
+					Aired:          ep.Aired,
+					Thumbnail:      tvdb.GetArtworkURL(ep.Image),
+					TVDBId:         ep.Id,
+				}
+			}
+			return episodes, nil
+		}
+	}
+
+	if tmdbId > 0 {
+		tmdbEpisodes, err := tmdb.GetStoredSeriesEpisodes(tmdbId)
+		if err != nil {
+			return nil, err
+		}
+		episodes := make([]seriesEpisode, len(tmdbEpisodes))
+		absoluteNumber := 0
+		for i := range tmdbEpisodes {
+			ep := &tmdbEpisodes[i]
+			episodes[i] = seriesEpisode{
+				Season:    ep.Season,
+				Number:    ep.Number,
+				Name:      ep.Name,
+				Overview:  ep.Overview,
+				Aired:     ep.AirDate,
+				Thumbnail: ep.StillURL(tmdb.StillSizeW300),
+			}
+			// tmdb has no absolute numbers, the episodes are ordered by season
+			if ep.Season > 0 {
+				absoluteNumber++
+				episodes[i].AbsoluteNumber = absoluteNumber
+			}
+		}
+		return episodes, nil
+	}
+
+	return []seriesEpisode{}, nil
+}
+
+func getSeriesVideos(idPrefix string, tvdbId, tmdbId int, lang *language) ([]stremio.MetaVideo, error) {
+	episodes, err := getSeriesEpisodes(tvdbId, tmdbId, lang)
+	if err != nil {
+		return nil, err
+	}
+	videos := make([]stremio.MetaVideo, 0, len(episodes))
+	for i := range episodes {
+		ep := &episodes[i]
+		if ep.Number <= 0 {
+			continue
+		}
+		id := idPrefix + ":" + strconv.Itoa(ep.Season) + ":" + strconv.Itoa(ep.Number)
+		videos = append(videos, toVideo(id, ep.Season, ep.Number, ep))
+	}
+	return videos, nil
+}
+
+func getIMDBMeta(r *http.Request, imdbId string, lang *language) (*stremio.Meta, error) {
+	title, err := imdb_title.Get(imdbId)
+	if err != nil || title == nil {
+		return nil, err
+	}
+
+	meta := &stremio.Meta{
+		Id:          imdbId,
+		Type:        stremio.ContentTypeMovie,
+		Name:        title.Title,
+		PosterShape: stremio.MetaPosterShapePoster,
+		IMDBId:      imdbId,
+	}
+	itemType := tvdb.TVDBItemTypeMovie
+	if imdb_title.IMDBTitleType(title.Type).IsShow() {
+		meta.Type = stremio.ContentTypeSeries
+		itemType = tvdb.TVDBItemTypeSeries
+	}
+	if title.Year > 0 {
+		meta.ReleaseInfo = strconv.Itoa(title.Year)
+		meta.Year = meta.ReleaseInfo
+	}
+
+	if err := applyIMDBMeta(meta, imdbId); err != nil {
+		return nil, err
+	}
+
+	tvdbId := 0
+	if tvdbIdByImdbId, err := imdb_title.GetTVDBIdByIMDBId([]string{imdbId}); err != nil {
+		LogError(r, "failed to get tvdb id", err)
+	} else {
+		tvdbId = util.SafeParseInt(tvdbIdByImdbId[imdbId], 0)
+	}
+	if tvdbId > 0 {
+		if err := applyTVDBItem(meta, itemType, tvdbId, lang); err != nil {
+			LogError(r, "failed to apply tvdb item", err)
+		}
+	}
+
+	if itemType == tvdb.TVDBItemTypeSeries {
+		tmdbId := 0
+		if tmdbIdByImdbId, err := imdb_title.GetTMDBIdByIMDBId([]string{imdbId}); err != nil {
+			LogError(r, "failed to get tmdb id", err)
+		} else {
+			tmdbId = util.SafeParseInt(tmdbIdByImdbId[imdbId], 0)
+		}
+		if meta.Videos, err = getSeriesVideos(imdbId, tvdbId, tmdbId, lang); err != nil {
+			LogError(r, "failed to get series episodes", err)
+		}
+	}
+
+	if meta.Type == stremio.ContentTypeMovie {
+		meta.BehaviorHints = &stremio.MetaBehaviorHints{DefaultVideoId: imdbId}
+	}
+
+	return meta, nil
+}
+
+func getTVDBMeta(r *http.Request, contentType stremio.ContentType, tvdbIdStr string, lang *language) (*stremio.Meta, error) {
+	tvdbId, err := strconv.Atoi(tvdbIdStr)
+	if err != nil {
+		return nil, nil
+	}
+
+	id := "tvdb:" + tvdbIdStr
+	meta := &stremio.Meta{
+		Id:          id,
+		Type:        contentType,
+		PosterShape: stremio.MetaPosterShapePoster,
+	}
+	itemType := tvdb.TVDBItemTypeSeries
+	if contentType == stremio.ContentTypeMovie {
+		itemType = tvdb.TVDBItemTypeMovie
+	}
+
+	if err := applyTVDBItem(meta, itemType, tvdbId, lang); err != nil {
+		return nil, err
+	}
+	if meta.Name == "" {
+		return nil, nil
+	}
+
+	if itemType == tvdb.TVDBItemTypeSeries {
+		tmdbId := 0
+		if _, imdbIdByTvdbId, err := imdb_title.GetIMDBIdByTVDBId(nil, []string{tvdbIdStr}); err != nil {
+			LogError(r, "failed to get imdb id", err)
+		} else if imdbId := imdbIdByTvdbId[tvdbIdStr]; imdbId != "" {
+			if tmdbIdByImdbId, err := imdb_title.GetTMDBIdByIMDBId([]string{imdbId}); err != nil {
+				LogError(r, "failed to get tmdb id", err)
+			} else {
+				tmdbId = util.SafeParseInt(tmdbIdByImdbId[imdbId], 0)
+			}
+		}
+		if meta.Videos, err = getSeriesVideos(id, tvdbId, tmdbId, lang); err != nil {
+			LogError(r, "failed to get series episodes", err)
+		}
+	} else {
+		meta.BehaviorHints = &stremio.MetaBehaviorHints{DefaultVideoId: id}
+	}
+
+	return meta, nil
+}
+
+// getAnimeVideos lists the tvdb episodes renumbered as the anidb episodes of
+// the anime, so that every anime gets its own single season. The tmdb
+// episodes, used if tvdb has none, are assumed to be numbered as in tvdb.
+func getAnimeVideos(idPrefix string, maps anidb.AniDBTVDBEpisodeMaps, tvdbId, tmdbId int, lang *language) ([]stremio.MetaVideo, error) {
+	episodes, err := getSeriesEpisodes(tvdbId, tmdbId, lang)
+	if err != nil {
+		return nil, err
+	}
+	videos := []stremio.MetaVideo{}
+	seen := map[int]struct{}{}
+	for i := range episodes {
+		ep := &episodes[i]
+		number := maps.GetAniDBEpisode(ep.Season, ep.Number, ep.AbsoluteNumber)
+		if number <= 0 {
+			continue
+		}
+		if _, ok := seen[number]; ok {
+			continue
+		}
+		seen[number] = struct{}{}
+		videos = append(videos, toVideo(idPrefix+":"+strconv.Itoa(number), 1, number, ep))
+	}
+	slices.SortFunc(videos, func(a, b stremio.MetaVideo) int {
+		return int(a.Episode) - int(b.Episode)
+	})
+	return videos, nil
+}
+
+func getAnimeMeta(r *http.Request, contentType stremio.ContentType, idType, id string, lang *language) (*stremio.Meta, error) {
+	if !config.Feature.IsEnabled(config.FeatureAnime) {
+		return nil, nil
+	}
+
+	var anidbId string
+	var err error
+	switch idType {
+	case "kitsu":
+		anidbId, _, err = anime.GetAniDBIdByKitsuId(id)
+	case "mal":
+		anidbId, _, err = anime.GetAniDBIdByMALId(id)
+	case "anidb":
+		anidbId = id
+	}
+	if err != nil || anidbId == "" {
+		return nil, err
+	}
+
+	titles, err := anidb.GetTitlesByIds([]string{anidbId})
+	if err != nil {
+		return nil, err
+	}
+	name := titles.GetTitle(anidbId, lang.ShortCode)
+	if name == "" {
+		return nil, nil
+	}
+
+	metaId := idType + ":" + id
+	meta := &stremio.Meta{
+		Id:          metaId,
+		Type:        contentType,
+		Name:        name,
+		PosterShape: stremio.MetaPosterShapePoster,
+	}
+	if year := titles.GetYear(anidbId); year > 0 {
+		meta.ReleaseInfo = strconv.Itoa(year)
+		meta.Year = meta.ReleaseInfo
+	}
+
+	isMovie := false
+	tmdbId := 0
+	if idMaps, err := anime.GetIdMapsForAniDB([]string{anidbId}); err != nil {
+		return nil, err
+	} else if len(idMaps) > 0 {
+		idMap := &idMaps[0]
+		isMovie = idMap.Type == anime.AnimeIdMapTypeMovie
+		tmdbId = util.SafeParseInt(idMap.TMDB, 0)
+		if idMap.IMDB != "" {
+			meta.IMDBId = idMap.IMDB
+			if err := applyIMDBMeta(meta, idMap.IMDB); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res, err := anidb.GetTVDBEpisodeMaps(anidbId, false)
+	if err != nil {
+		return nil, err
+	}
+	if res.Len() > 0 {
+		maps := res.Val()
+		if tvdbId, err := strconv.Atoi(maps.GetTVDBId()); err == nil && tvdbId > 0 {
+			// the tvdb series spans all the seasons, only the anidb title
+			// is specific to this one.
+			if err := applyTVDBItem(meta, tvdb.TVDBItemTypeSeries, tvdbId, lang); err != nil {
+				LogError(r, "failed to apply tvdb item", err)
+			}
+			meta.Name = name
+			if !isMovie {
+				if meta.Videos, err = getAnimeVideos(metaId, maps, tvdbId, tmdbId, lang); err != nil {
+					LogError(r, "failed to get anime episodes", err)
+				}
+			}
+		}
+	}
+
+	if isMovie || len(meta.Videos) == 0 {
+		meta.BehaviorHints = &stremio.MetaBehaviorHints{DefaultVideoId: metaId}
+	}
+
+	return meta, nil
+}
+
+func getMeta(r *http.Request, contentType stremio.ContentType, id string, lang *language) (*stremio.Meta, error) {
+	cacheKey := string(contentType) + ":" + id + ":" + lang.Code
+
+	meta := stremio.Meta{}
+	if metaCache.Get(cacheKey, &meta) {
+		return &meta, nil
+	}
+
+	result, err, _ := metaGroup.Do(cacheKey, func() (any, error) {
+		var meta *stremio.Meta
+		var err error
+		if strings.HasPrefix(id, "tt") {
+			meta, err = getIMDBMeta(r, id, lang)
+		} else if idType, idValue, ok := strings.Cut(id, ":"); ok {
+			switch idType {
+			case "tvdb":
+				meta, err = getTVDBMeta(r, contentType, idValue, lang)
+			case "kitsu", "mal", "anidb":
+				meta, err = getAnimeMeta(r, contentType, idType, idValue, lang)
+			}
+		}
+		if err != nil || meta == nil {
+			return meta, err
+		}
+		lifetime := metaCacheLifetime
+		if meta.Type == stremio.ContentTypeSeries && len(meta.Videos) == 0 {
+			// the episodes can be fetched in background meanwhile
+			lifetime = 5 * time.Minute
+		}
+		if err := metaCache.AddWithLifetime(cacheKey, *meta, lifetime); err != nil {
+			log.Error("failed to cache meta", "error", err, "id", id)
+		}
+		return meta, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*stremio.Meta), nil
+}
+
+func handleMeta(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	contentType := stremio.ContentType(GetPathValue(r, "contentType"))
+	id := GetPathValue(r, "id")
+
+	meta, err := getMeta(r, contentType, id, ud.GetLanguage())
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if meta == nil {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendResponse(w, r, 200, stremio.MetaHandlerResponse{
+		Meta: *meta,
+	})
+}
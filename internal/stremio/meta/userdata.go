@@ -0,0 +1,73 @@
+package stremio_meta
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	stremio_userdata "github.com/MunifTanjim/stremthru/internal/stremio/userdata"
+)
+
+type UserData struct {
+	Lang string `json:"lang,omitempty"`
+
+	encoded string `json:"-"`
+}
+
+func (ud UserData) StripSecrets() UserData {
+	return ud
+}
+
+var udManager = stremio_userdata.NewManager[UserData](&stremio_userdata.ManagerConfig{
+	AddonName: "meta",
+})
+
+func (ud UserData) HasRequiredValues() bool {
+	return ud.Lang != ""
+}
+
+func (ud UserData) GetEncoded() string {
+	return ud.encoded
+}
+
+func (ud *UserData) SetEncoded(encoded string) {
+	ud.encoded = encoded
+}
+
+func (ud *UserData) Ptr() *UserData {
+	return ud
+}
+
+func (ud UserData) GetLanguage() *language {
+	if lang := getLanguage(ud.Lang); lang != nil {
+		return lang
+	}
+	return getLanguage(defaultLanguageCode)
+}
+
+func getUserData(r *http.Request) (*UserData, error) {
+	ud := &UserData{}
+	ud.SetEncoded(r.PathValue("userData"))
+
+	if IsMethod(r, http.MethodGet) || IsMethod(r, http.MethodHead) {
+		if err := udManager.Resolve(ud); err != nil {
+			if errors.Is(err, stremio_userdata.ErrUnsupportedUserdataFormat) {
+				return nil, server.ErrorBadRequest(r).WithMessage(err.Error())
+			}
+			return nil, err
+		}
+	}
+
+	if IsMethod(r, http.MethodPost) {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		ud.Lang = r.Form.Get("lang")
+		if getLanguage(ud.Lang) == nil {
+			ud.Lang = defaultLanguageCode
+		}
+	}
+
+	return ud, nil
+}
@@ -0,0 +1,92 @@
+package stremio_newz
+
+import (
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+// maxPlaybackFallbacks is the number of alternate releases tried, after the
+// selected one fails to play from usenet.
+const maxPlaybackFallbacks = 3
+
+const playbackCandidatesQueryParam = "fallback"
+
+type playbackCandidate struct {
+	Link string `json:"link"`
+	Name string `json:"name"`
+}
+
+var playbackCandidatesCache = cache.NewCache[[]playbackCandidate](&cache.CacheConfig{
+	Name:     "stremio:newz:playback-candidates",
+	Lifetime: 3 * time.Hour,
+})
+
+var badReleaseCache = cache.NewCache[string](&cache.CacheConfig{
+	Name:     "stremio:newz:bad-release",
+	Lifetime: 24 * time.Hour,
+	MaxSize:  4096,
+	Persist:  true,
+})
+
+// bad releases are keyed by the hash of the nzb link, the same on every
+// path, not by the hash from the indexer or the store.
+func markBadRelease(nzbUrl string, reason string) {
+	badReleaseCache.Add(util.HashNZBFileLink(nzbUrl), reason)
+}
+
+func isBadRelease(nzbUrl string) bool {
+	return badReleaseCache.Has(util.HashNZBFileLink(nzbUrl))
+}
+
+// savePlaybackCandidates stores the ranked releases for the stremId, and
+// returns the token for retrieving them during playback.
+func savePlaybackCandidates(sid string, candidates []playbackCandidate) string {
+	if len(candidates) < 2 {
+		return ""
+	}
+	links := make([]string, len(candidates))
+	for i := range candidates {
+		links[i] = candidates[i].Link
+	}
+	token := util.MD5Hash(sid + "\n" + strings.Join(links, "\n"))
+	playbackCandidatesCache.Add(token, candidates)
+	return token
+}
+
+// getPlaybackCandidates returns the selected release, followed by the ones
+// ranked below it, skipping the ones known to be bad.
+func getPlaybackCandidates(token string, nzbUrl string, fileName string) []playbackCandidate {
+	candidates := []playbackCandidate{{Link: nzbUrl, Name: fileName}}
+
+	ranked := []playbackCandidate{}
+	if token == "" || !playbackCandidatesCache.Get(token, &ranked) {
+		return candidates
+	}
+
+	for i := range ranked {
+		if ranked[i].Link == nzbUrl {
+			ranked = ranked[i+1:]
+			break
+		}
+	}
+
+	for i := range ranked {
+		if len(candidates) > maxPlaybackFallbacks {
+			break
+		}
+		c := ranked[i]
+		if c.Link == nzbUrl || isBadRelease(c.Link) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+
+	if len(candidates) > 1 && isBadRelease(nzbUrl) {
+		candidates = candidates[1:]
+	}
+
+	return candidates
+}
@@ -0,0 +1,126 @@
+package stremio_newz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func toPlaybackCandidates(prefix string, names ...string) []playbackCandidate {
+	candidates := make([]playbackCandidate, len(names))
+	for i, name := range names {
+		candidates[i] = playbackCandidate{
+			Link: "https://indexer.test/" + prefix + "/" + name + ".nzb",
+			Name: name,
+		}
+	}
+	return candidates
+}
+
+func toCandidateNames(candidates []playbackCandidate) []string {
+	names := make([]string, len(candidates))
+	for i := range candidates {
+		names[i] = candidates[i].Name
+	}
+	return names
+}
+
+func TestSavePlaybackCandidates(t *testing.T) {
+	t.Run("single candidate", func(t *testing.T) {
+		candidates := toPlaybackCandidates("save-single", "a")
+		assert.Equal(t, "", savePlaybackCandidates("tt0000001", candidates))
+	})
+
+	t.Run("multiple candidates", func(t *testing.T) {
+		candidates := toPlaybackCandidates("save-multiple", "a", "b", "c")
+		token := savePlaybackCandidates("tt0000001", candidates)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, token, savePlaybackCandidates("tt0000001", candidates))
+		assert.NotEqual(t, token, savePlaybackCandidates("tt0000002", candidates))
+
+		saved := []playbackCandidate{}
+		assert.True(t, playbackCandidatesCache.Get(token, &saved))
+		assert.Equal(t, candidates, saved)
+	})
+}
+
+func TestGetPlaybackCandidates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		ranked   []string
+		selected string
+		bad      []string
+		noToken  bool
+		expected []string
+	}{
+		{
+			name:     "no token",
+			ranked:   []string{"a", "b", "c"},
+			selected: "a",
+			noToken:  true,
+			expected: []string{"a"},
+		},
+		{
+			name:     "ranked below selected",
+			ranked:   []string{"a", "b", "c"},
+			selected: "b",
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "capped fallbacks",
+			ranked:   []string{"a", "b", "c", "d", "e", "f"},
+			selected: "a",
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "skip bad release",
+			ranked:   []string{"a", "b", "c", "d"},
+			selected: "a",
+			bad:      []string{"b"},
+			expected: []string{"a", "c", "d"},
+		},
+		{
+			name:     "selected is bad",
+			ranked:   []string{"a", "b", "c"},
+			selected: "a",
+			bad:      []string{"a"},
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "selected is bad without alternative",
+			ranked:   []string{"a", "b"},
+			selected: "a",
+			bad:      []string{"a", "b"},
+			expected: []string{"a"},
+		},
+		{
+			name:     "selected not ranked",
+			ranked:   []string{"a", "b"},
+			selected: "z",
+			expected: []string{"z", "a", "b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix := "get/" + tc.name
+			ranked := toPlaybackCandidates(prefix, tc.ranked...)
+			selected := toPlaybackCandidates(prefix, tc.selected)[0]
+			for _, c := range toPlaybackCandidates(prefix, tc.bad...) {
+				markBadRelease(c.Link, "test")
+			}
+
+			token := savePlaybackCandidates("tt0000001", ranked)
+			if tc.noToken {
+				token = ""
+			}
+
+			candidates := getPlaybackCandidates(token, selected.Link, selected.Name)
+			assert.Equal(t, tc.expected, toCandidateNames(candidates))
+		})
+	}
+}
+
+func TestIsBadRelease(t *testing.T) {
+	markBadRelease("https://indexer.test/api?t=get&id=bad&apikey=a", "test")
+	assert.True(t, isBadRelease("https://indexer.test/api?t=get&id=bad&apikey=b"), "same release, other api key")
+	assert.False(t, isBadRelease("https://indexer.test/api?t=get&id=good&apikey=a"))
+}
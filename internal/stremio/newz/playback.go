@@ -3,6 +3,7 @@ package stremio_newz
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -59,6 +60,10 @@ type usenetStremResult struct {
 	error_level  logger.Level
 	error_log    string
 	error_video  string
+	// failed because of the release itself, e.g. dead nzb link, worth trying
+	// another one and marked bad. transient failures, e.g. store or network
+	// errors, end the fallback without marking the release.
+	is_bad_release bool
 }
 
 func handlePlaybackFromStore(w http.ResponseWriter, r *http.Request, ud *UserData, ctx *Ctx, sid string, storeCode store.StoreCode, nzbUrl string, isLockedDownload bool) {
@@ -241,6 +246,7 @@ func handlePlaybackFromStore(w http.ResponseWriter, r *http.Request, ud *UserDat
 		}
 
 		streamLinkCache.Add(cacheKey, link)
+		stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), sid, "", stremio_shared.NewEmbeddedSubtitleHTTPSource(stremio_shared.GetEmbeddedSubtitleHash(newz.Hash, file.GetPath()), link))
 
 		return &stremResult{
 			link: link,
@@ -289,8 +295,100 @@ func handleStreamFromUsenet(w http.ResponseWriter, r *http.Request, ud *UserData
 		return
 	}
 
+	candidates := getPlaybackCandidates(r.URL.Query().Get(playbackCandidatesQueryParam), nzbUrl, r.PathValue("fileName"))
+
+	var strem *usenetStremResult
+	var stream *usenet_pool.Stream
+	for i, candidate := range candidates {
+		if i > 0 {
+			log.Info("falling back to alternate release", "attempt", i, "name", candidate.Name)
+		}
+
+		strem, err = prepareUsenetStream(ctx, newzStore, sid, candidate.Link, candidate.Name, log)
+		if strem.error_log != "" {
+			log.Log(strem.error_level, strem.error_log, "error", err)
+			if !strem.is_bad_release {
+				break
+			}
+			markBadRelease(candidate.Link, strem.error_log)
+			continue
+		}
+
+		streamCtx := context.WithValue(r.Context(), usenet_pool.NZBHashContextKey, strem.hash)
+		stream, err = pool.StreamByContentPath(streamCtx, strem.nzbDoc, strem.contentPath, strem.streamConfig)
+		if err != nil {
+			strem = &usenetStremResult{
+				error_level: logger.LevelError,
+				error_log:   "failed to create usenet stream",
+				error_video: store_video.StoreVideoName500,
+			}
+			if errors.Is(err, usenet_pool.ErrArticleNotFound) {
+				strem.error_level = logger.LevelWarn
+				strem.error_log = "missing articles"
+				strem.error_video = store_video.StoreVideoNameDownloadFailed
+				strem.is_bad_release = true
+			}
+			log.Log(strem.error_level, strem.error_log, "error", err)
+			if !strem.is_bad_release {
+				break
+			}
+			markBadRelease(candidate.Link, strem.error_log)
+			continue
+		}
+		break
+	}
+
+	if stream == nil {
+		redirectToStaticVideo(w, r, "", strem.error_video)
+		return
+	}
+	defer stream.Close()
+
+	stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), sid, "", newUsenetEmbeddedSubtitleSource(pool, strem))
+
+	w.Header().Set("Content-Type", stream.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(stream.Size, 10))
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	var content io.ReadSeeker = stream
+	if config.Stremio.Newz.PrewarmSize > 0 && len(getNextEpisodeStremIds(sid)) > 0 {
+		playedKey := util.MD5Hash(ctx.StoreAuthToken + ":" + sid + ":" + strem.hash)
+		content = newPrewarmingStream(stream, playedKey, func() {
+			prewarmNextEpisode(ud, ctx, sid)
+		})
+	}
+
+	http.ServeContent(w, r, stream.Name, strem.nzbFileMod, content)
+}
+
+// matchUsenetFile returns the video file for the sid, or nil if none matches.
+func matchUsenetFile(name string, files []store.NewzFile, sid string, storeCode store.StoreCode, log *logger.Logger) store.File {
+	videoFiles := []store.File{}
+	for i := range files {
+		f := &files[i]
+		if core.HasVideoExtension(f.Name) {
+			videoFiles = append(videoFiles, f)
+		}
+	}
+
+	var file store.File
+	isIMDBId := strings.HasPrefix(sid, "tt")
+
+	if strings.Contains(sid, ":") {
+		if file = stremio_shared.MatchFileByStremId(name, videoFiles, sid, "", storeCode); file != nil {
+			log.Debug("matched file using strem id", "sid", sid, "filename", file.GetName())
+		}
+	}
+	if file == nil && isIMDBId && (!strings.Contains(sid, ":") || len(videoFiles) == 1) {
+		if file = stremio_shared.MatchFileByLargestSize(videoFiles); file != nil {
+			log.Debug("matched file using largest size", "filename", file.GetName())
+		}
+	}
+	return file
+}
+
+func prepareUsenetStream(ctx *Ctx, newzStore store.NewzStore, sid string, nzbUrl string, fileName string, log *logger.Logger) (*usenetStremResult, error) {
 	cacheKey := util.HashNZBFileLink(nzbUrl)
-	fileName := r.PathValue("fileName")
 
 	result, err, _ := usenetStremGroup.Do(cacheKey, func() (any, error) {
 		addParams := &store.AddNewzParams{
@@ -325,41 +423,28 @@ func handleStreamFromUsenet(w http.ResponseWriter, r *http.Request, ud *UserData
 				strem.error_level = logger.LevelWarn
 				strem.error_log = "newz not ready"
 				strem.error_video = store_video.StoreVideoNameDownloading
+				if fetchErr := nzb_info.GetFetchError(nzbUrl); nzb_info.IsPermanentFetchError(fetchErr) {
+					strem.error_log = "nzb link is dead"
+					strem.error_video = store_video.StoreVideoNameDownloadFailed
+					strem.is_bad_release = true
+					err = fetchErr
+				}
 			case store.NewzStatusFailed, store.NewzStatusInvalid, store.NewzStatusUnknown:
 				strem.error_level = logger.LevelWarn
 				strem.error_log = "newz failed"
+				strem.is_bad_release = true
 				strem.error_video = store_video.StoreVideoNameDownloadFailed
 			}
 			return strem, err
 		}
 
-		videoFiles := []store.File{}
-		for i := range newz.Files {
-			f := &newz.Files[i]
-			if core.HasVideoExtension(f.Name) {
-				videoFiles = append(videoFiles, f)
-			}
-		}
-
-		var file store.File
-		isIMDBId := strings.HasPrefix(sid, "tt")
-
-		if strings.Contains(sid, ":") {
-			if file = stremio_shared.MatchFileByStremId(newz.Name, videoFiles, sid, "", ctx.Store.GetName().Code()); file != nil {
-				log.Debug("matched file using strem id", "sid", sid, "filename", file.GetName())
-			}
-		}
-		if file == nil && isIMDBId && (!strings.Contains(sid, ":") || len(videoFiles) == 1) {
-			if file = stremio_shared.MatchFileByLargestSize(videoFiles); file != nil {
-				log.Debug("matched file using largest size", "filename", file.GetName())
-			}
-		}
-
+		file := matchUsenetFile(newz.Name, newz.Files, sid, ctx.Store.GetName().Code(), log)
 		if file == nil {
 			return &usenetStremResult{
-				error_level: logger.LevelWarn,
-				error_log:   "no matching file found for (" + sid + " - " + newz.Hash + ")",
-				error_video: store_video.StoreVideoNameNoMatchingFile,
+				error_level:    logger.LevelWarn,
+				error_log:      "no matching file found for (" + sid + " - " + newz.Hash + ")",
+				error_video:    store_video.StoreVideoNameNoMatchingFile,
+				is_bad_release: true,
 			}, nil
 		}
 
@@ -374,26 +459,29 @@ func handleStreamFromUsenet(w http.ResponseWriter, r *http.Request, ud *UserData
 		}
 		if info == nil || !info.Streamable {
 			return &usenetStremResult{
-				error_level: logger.LevelWarn,
-				error_log:   "nzb is not streamable",
-				error_video: store_video.StoreVideoNameDownloadFailed,
+				error_level:    logger.LevelWarn,
+				error_log:      "nzb is not streamable",
+				error_video:    store_video.StoreVideoNameDownloadFailed,
+				is_bad_release: true,
 			}, nil
 		}
 
 		nzbFile, err := nzb_info.FetchNZBFile(nzbUrl, fileName, log)
 		if err != nil {
 			return &usenetStremResult{
-				error_level: logger.LevelError,
-				error_log:   "failed to fetch nzb",
-				error_video: store_video.StoreVideoNameDownloadFailed,
+				error_level:    logger.LevelError,
+				error_log:      "failed to fetch nzb",
+				error_video:    store_video.StoreVideoNameDownloadFailed,
+				is_bad_release: nzb_info.IsPermanentFetchError(err),
 			}, err
 		}
 		nzbDoc, err := nzb.ParseBytes(nzbFile.Blob)
 		if err != nil {
 			return &usenetStremResult{
-				error_level: logger.LevelError,
-				error_log:   "failed to parse nzb",
-				error_video: store_video.StoreVideoName500,
+				error_level:    logger.LevelError,
+				error_log:      "failed to parse nzb",
+				error_video:    store_video.StoreVideoName500,
+				is_bad_release: true,
 			}, err
 		}
 
@@ -409,28 +497,7 @@ func handleStreamFromUsenet(w http.ResponseWriter, r *http.Request, ud *UserData
 		}, nil
 	})
 
-	strem := result.(*usenetStremResult)
-
-	if strem.error_log != "" {
-		log.Log(strem.error_level, strem.error_log, "error", err)
-		redirectToStaticVideo(w, r, "", strem.error_video)
-		return
-	}
-
-	streamCtx := context.WithValue(r.Context(), usenet_pool.NZBHashContextKey, strem.hash)
-	stream, err := pool.StreamByContentPath(streamCtx, strem.nzbDoc, strem.contentPath, strem.streamConfig)
-	if err != nil {
-		log.Error("failed to create usenet stream", "error", err)
-		redirectToStaticVideo(w, r, "", store_video.StoreVideoName500)
-		return
-	}
-	defer stream.Close()
-
-	w.Header().Set("Content-Type", stream.ContentType)
-	w.Header().Set("Content-Length", strconv.FormatInt(stream.Size, 10))
-	w.Header().Set("Accept-Ranges", "bytes")
-
-	http.ServeContent(w, r, stream.Name, strem.nzbFileMod, stream)
+	return result.(*usenetStremResult), err
 }
 
 func handlePlayback(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,292 @@
+package stremio_newz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	stremio_transformer "github.com/MunifTanjim/stremthru/internal/stremio/transformer"
+	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/MunifTanjim/stremthru/store/stremthru"
+	"github.com/MunifTanjim/stremthru/stremio"
+)
+
+// prewarmThreshold is the fraction of the current episode to be played,
+// before the next one is prewarmed.
+const prewarmThreshold = 0.8
+
+const prewarmTimeout = 5 * time.Minute
+
+var prewarmedCache = cache.NewCache[bool](&cache.CacheConfig{
+	Name:     "stremio:newz:prewarmed",
+	Lifetime: 3 * time.Hour,
+})
+
+// getNextEpisodeStremIds returns the stremIds of the episode after the one
+// in sid, in the order to try, or nil if sid is not of an episode. With
+// season, it is the next episode of the season followed by the first one of
+// the next season, e.g. `tt0903747:1:2` -> `tt0903747:1:3`, `tt0903747:2:1`.
+// Without season, e.g. `kitsu:1376:2` -> `kitsu:1376:3`.
+func getNextEpisodeStremIds(sid string) []string {
+	parts := strings.Split(sid, ":")
+	if len(parts) < 3 {
+		return nil
+	}
+	ep, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return nil
+	}
+	prefix := strings.Join(parts[:len(parts)-1], ":")
+	nextSids := []string{prefix + ":" + strconv.Itoa(ep+1)}
+	if strings.HasPrefix(parts[0], "tt") || len(parts) > 3 {
+		season, err := strconv.Atoi(parts[len(parts)-2])
+		if err != nil {
+			return nil
+		}
+		nextSids = append(nextSids, strings.Join(parts[:len(parts)-2], ":")+":"+strconv.Itoa(season+1)+":1")
+	}
+	return nextSids
+}
+
+// prewarmMinRead is the length a run of reads needs to count as playback,
+// so that the short reads near the end of the file, e.g. for MKV Cues or MP4
+// moov, do not trigger the prewarm.
+const prewarmMinRead = 16 * 1024 * 1024
+
+// playedPosition is the playback progress of a session.
+type playedPosition struct {
+	// highest position reached by playback
+	Played int64 `json:"p"`
+	// start and end of the latest run of reads, continued by the next range
+	// request starting near its end
+	RunStart int64 `json:"rs"`
+	RunEnd   int64 `json:"re"`
+}
+
+// playedPositionCache keeps the playback progress across the requests of a
+// session, keyed by user and stream.
+var playedPositionCache = cache.NewCache[playedPosition](&cache.CacheConfig{
+	Name:     "stremio:newz:played-position",
+	Lifetime: 3 * time.Hour,
+})
+
+// prewarmingStream triggers the prewarm once playback reaches the threshold
+// of the stream.
+type prewarmingStream struct {
+	*usenet_pool.Stream
+	key       string
+	threshold int64
+	minRead   int64
+	played    playedPosition
+	position  int64
+	once      sync.Once
+	prewarm   func()
+}
+
+func newPrewarmingStream(stream *usenet_pool.Stream, key string, prewarm func()) *prewarmingStream {
+	s := &prewarmingStream{
+		Stream:    stream,
+		key:       key,
+		threshold: int64(float64(stream.Size) * prewarmThreshold),
+		minRead:   min(prewarmMinRead, stream.Size/20),
+		prewarm:   prewarm,
+	}
+	s.startRun(0)
+	return s
+}
+
+// startRun starts a run of reads at pos, or continues the latest run of the
+// session if it ended near pos.
+func (s *prewarmingStream) startRun(pos int64) {
+	playedPositionCache.Get(s.key, &s.played)
+	if max(pos-s.played.RunEnd, s.played.RunEnd-pos) >= s.minRead {
+		s.played.RunStart, s.played.RunEnd = pos, pos
+	}
+}
+
+func (s *prewarmingStream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	s.position += int64(n)
+	// saved in steps, to not write the cache on every read
+	if s.position-s.played.RunEnd >= s.minRead/4 {
+		s.played.RunEnd = s.position
+		if s.position-s.played.RunStart >= s.minRead {
+			s.played.Played = max(s.played.Played, s.position)
+		}
+		playedPositionCache.Add(s.key, s.played)
+	}
+	if s.played.Played >= s.threshold {
+		s.once.Do(func() {
+			go s.prewarm()
+		})
+	}
+	return n, err
+}
+
+func (s *prewarmingStream) Seek(offset int64, whence int) (int64, error) {
+	pos, err := s.Stream.Seek(offset, whence)
+	if err == nil && pos != s.position {
+		s.position = pos
+		s.startRun(pos)
+	}
+	return pos, err
+}
+
+// prewarmNextEpisode fetches the first few MBs of the top ranked release of
+// the next episode, along with the archive headers, so that it is already in
+// the segment cache when the player asks for it. The release is streamed
+// straight from the NZB, it is not added to the store of the user. The first
+// episode of the next season is tried, if no release is found for the next
+// episode of the season.
+func prewarmNextEpisode(ud *UserData, ctx *Ctx, sid string) {
+	pool, err := usenetmanager.GetPool()
+	if err != nil || pool == nil {
+		return
+	}
+
+	filter, err := ud.GetFilter()
+	if err != nil {
+		return
+	}
+
+	for _, nextSid := range getNextEpisodeStremIds(sid) {
+		if prewarmEpisode(ud, ctx, pool, filter, nextSid) {
+			return
+		}
+	}
+}
+
+// prewarmEpisode prewarms the episode, it returns false if no release is
+// found for it.
+func prewarmEpisode(ud *UserData, ctx *Ctx, pool *usenet_pool.Pool, filter *stremio_transformer.StreamFilter, nextSid string) bool {
+	log := ctx.Log.With("prewarm_sid", nextSid)
+
+	prewarmKey := util.MD5Hash(ctx.StoreAuthToken + ":" + nextSid)
+	if prewarmedCache.Has(prewarmKey) {
+		return true
+	}
+	prewarmedCache.Add(prewarmKey, true)
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), prewarmTimeout)
+	defer cancel()
+
+	searchCtx, searchCancel := context.WithTimeout(timeoutCtx, config.Stremio.Newz.IndexerMaxTimeout)
+	wrappedStreams, err := GetStreamsFromIndexers(searchCtx, ctx, string(stremio.ContentTypeSeries), nextSid)
+	searchCancel()
+	if err != nil {
+		log.Warn("prewarm: failed to get streams from indexers", "error", err)
+		return true
+	}
+
+	wrappedStreams = filterStreams(wrappedStreams, filter)
+	stremio_transformer.SortStreams(wrappedStreams, ud.Sort)
+	wrappedStreams = applyStreamsHealth(wrappedStreams, log)
+
+	var candidate *WrappedStream
+	for i := range wrappedStreams {
+		wStream := &wrappedStreams[i]
+		if !wStream.lockedDownload && !isBadRelease(wStream.nzbURL) {
+			candidate = wStream
+			break
+		}
+	}
+	if candidate == nil {
+		log.Debug("prewarm: no release found")
+		return false
+	}
+
+	strem, err := prepareUsenetPrewarmStream(timeoutCtx, pool, nextSid, candidate.nzbURL, candidate.R.TTitle, log)
+	if strem.error_log != "" {
+		log.Debug("prewarm: "+strem.error_log, "error", err)
+		// e.g. season pack of the current season, without the episode
+		return !errors.Is(err, errPrewarmNoMatchingFile)
+	}
+
+	streamCtx := context.WithValue(timeoutCtx, usenet_pool.NZBHashContextKey, strem.hash)
+	stream, err := pool.StreamByContentPath(streamCtx, strem.nzbDoc, strem.contentPath, strem.streamConfig)
+	if err != nil {
+		log.Debug("prewarm: failed to create usenet stream", "error", err)
+		return true
+	}
+	defer stream.Close()
+
+	n, err := io.CopyN(io.Discard, stream, config.Stremio.Newz.PrewarmSize)
+	if err != nil && err != io.EOF {
+		log.Debug("prewarm: failed to read usenet stream", "error", err, "bytes", n)
+		return true
+	}
+	log.Debug("prewarmed next episode", "name", stream.Name, "bytes", n)
+	return true
+}
+
+var errPrewarmNoMatchingFile = errors.New("no matching file")
+
+// prepareUsenetPrewarmStream is prepareUsenetStream without the store, the
+// content is inspected from the NZB, unless it is inspected already.
+func prepareUsenetPrewarmStream(ctx context.Context, pool *usenet_pool.Pool, sid string, nzbUrl string, fileName string, log *logger.Logger) (*usenetStremResult, error) {
+	nzbFile, err := nzb_info.FetchNZBFile(nzbUrl, fileName, log)
+	if err != nil {
+		return &usenetStremResult{error_log: "failed to fetch nzb"}, err
+	}
+	nzbDoc, err := nzb.ParseBytes(nzbFile.Blob)
+	if err != nil {
+		return &usenetStremResult{error_log: "failed to parse nzb"}, err
+	}
+
+	hash := util.HashNZBFileLink(nzbUrl)
+	info, err := nzb_info.GetByHash(hash)
+	if err != nil {
+		return &usenetStremResult{error_log: "failed to get nzb info"}, err
+	}
+
+	var streamConfig *usenet_pool.StreamConfig
+	if info != nil {
+		if !info.Streamable {
+			return &usenetStremResult{error_log: "nzb is not streamable"}, nil
+		}
+		streamConfig = &usenet_pool.StreamConfig{
+			Password:     info.Password,
+			ContentFiles: info.ContentFiles.Data,
+		}
+	} else {
+		password := nzbDoc.GetMeta("password")
+		inspectCtx := context.WithValue(ctx, usenet_pool.NZBHashContextKey, hash)
+		content, err := pool.InspectNZBContent(inspectCtx, nzbDoc, password)
+		if err != nil {
+			return &usenetStremResult{error_log: "failed to inspect nzb content"}, err
+		}
+		if !content.Streamable {
+			return &usenetStremResult{error_log: "nzb is not streamable"}, nil
+		}
+		streamConfig = &usenet_pool.StreamConfig{
+			Password:     password,
+			ContentFiles: content.Files,
+		}
+	}
+
+	files := stremthru.FlattenContentFiles(streamConfig.ContentFiles, "")
+	file := matchUsenetFile(nzbFile.Name, files, sid, store.StoreNameStremThru.Code(), log)
+	if file == nil {
+		return &usenetStremResult{error_log: "no matching file found for (" + sid + " - " + hash + ")"}, errPrewarmNoMatchingFile
+	}
+
+	return &usenetStremResult{
+		hash:         hash,
+		contentPath:  file.GetPath(),
+		streamConfig: streamConfig,
+		nzbDoc:       nzbDoc,
+		nzbFileMod:   nzbFile.Mod,
+	}, nil
+}
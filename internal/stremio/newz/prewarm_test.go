@@ -0,0 +1,108 @@
+package stremio_newz
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNextEpisodeStremIds(t *testing.T) {
+	for _, tc := range []struct {
+		sid    string
+		result []string
+	}{
+		{"tt0903747", nil},
+		{"tt0903747:1", nil},
+		{"tt0903747:1:x", nil},
+		{"tt0903747:1:2", []string{"tt0903747:1:3", "tt0903747:2:1"}},
+		{"tt0903747:5:16", []string{"tt0903747:5:17", "tt0903747:6:1"}},
+		{"kitsu:1376:2", []string{"kitsu:1376:3"}},
+		{"tmdb:1396:1:2", []string{"tmdb:1396:1:3", "tmdb:1396:2:1"}},
+	} {
+		t.Run(tc.sid, func(t *testing.T) {
+			assert.Equal(t, tc.result, getNextEpisodeStremIds(tc.sid))
+		})
+	}
+}
+
+type nopCloseReadSeeker struct {
+	io.ReadSeeker
+}
+
+func (nopCloseReadSeeker) Close() error {
+	return nil
+}
+
+func newTestPrewarmingStream(key string, size int64, prewarmed *atomic.Int32) *prewarmingStream {
+	stream := &usenet_pool.Stream{
+		ReadSeekCloser: nopCloseReadSeeker{bytes.NewReader(make([]byte, size))},
+		Size:           size,
+	}
+	return newPrewarmingStream(stream, key, func() {
+		prewarmed.Add(1)
+	})
+}
+
+func readPrewarmingStream(t *testing.T, s *prewarmingStream, start, n int64) {
+	t.Helper()
+	_, err := s.Seek(start, io.SeekStart)
+	assert.NoError(t, err)
+	_, err = io.CopyN(io.Discard, s, n)
+	assert.NoError(t, err)
+}
+
+func TestPrewarmingStream(t *testing.T) {
+	const size = 100 * 1024 * 1024
+	const minRead = size / 20
+
+	waitPrewarmed := func(prewarmed *atomic.Int32) int32 {
+		time.Sleep(10 * time.Millisecond)
+		return prewarmed.Load()
+	}
+
+	t.Run("sequential playback", func(t *testing.T) {
+		prewarmed := atomic.Int32{}
+		s := newTestPrewarmingStream(t.Name(), size, &prewarmed)
+		readPrewarmingStream(t, s, 0, size*7/10)
+		assert.Equal(t, int32(0), waitPrewarmed(&prewarmed), "below threshold")
+		readPrewarmingStream(t, s, size*7/10, size/20)
+		assert.Equal(t, int32(0), waitPrewarmed(&prewarmed), "below threshold")
+		readPrewarmingStream(t, s, size*75/100, minRead)
+		assert.Equal(t, int32(1), waitPrewarmed(&prewarmed))
+		readPrewarmingStream(t, s, size*9/10, minRead)
+		assert.Equal(t, int32(1), waitPrewarmed(&prewarmed), "once per stream")
+	})
+
+	t.Run("short read near end", func(t *testing.T) {
+		prewarmed := atomic.Int32{}
+		s := newTestPrewarmingStream(t.Name(), size, &prewarmed)
+		readPrewarmingStream(t, s, size-minRead/2, minRead/2)
+		assert.Equal(t, int32(0), waitPrewarmed(&prewarmed))
+		assert.Equal(t, int64(0), s.played.Played)
+	})
+
+	t.Run("played after seek past threshold", func(t *testing.T) {
+		prewarmed := atomic.Int32{}
+		s := newTestPrewarmingStream(t.Name(), size, &prewarmed)
+		readPrewarmingStream(t, s, size*85/100, minRead)
+		assert.Equal(t, int32(1), waitPrewarmed(&prewarmed), "playback continued past threshold")
+	})
+
+	t.Run("run continued across requests", func(t *testing.T) {
+		prewarmed := atomic.Int32{}
+		key := t.Name()
+		s := newTestPrewarmingStream(key, size, &prewarmed)
+		readPrewarmingStream(t, s, size*8/10, minRead/2)
+		assert.Equal(t, int32(0), waitPrewarmed(&prewarmed), "run too short")
+
+		// next range request of the session continues the run
+		s = newTestPrewarmingStream(key, size, &prewarmed)
+		readPrewarmingStream(t, s, size*8/10+minRead/2, minRead/2)
+		assert.Equal(t, int32(1), waitPrewarmed(&prewarmed))
+	})
+}
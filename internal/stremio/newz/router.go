@@ -40,8 +40,13 @@ func AddStremioNewzEndpoints(mux *http.ServeMux) {
 
 	router.HandleFunc("/{userData}/stream/{contentType}/{idJson}", withCors(handleStream))
 
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{idJson}", withCors(handleSubtitles))
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{id}/{extraJson}", withCors(handleSubtitles))
+
 	router.HandleFunc("/{userData}/playback/{stremId}/{mode}/{storeCode}/{nzbUrl}/{$}", withCors(handlePlayback))
 	router.HandleFunc("/{userData}/playback/{stremId}/{mode}/{storeCode}/{nzbUrl}/{fileName}", withCors(handlePlayback))
 
+	router.HandleFunc("/{userData}/_/subtitle/{fileHash}/{trackFile}", withCors(handleSubtitle))
+
 	mux.Handle("/stremio/newz/", http.StripPrefix("/stremio/newz", commonMiddleware(router)))
 }
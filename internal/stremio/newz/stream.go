@@ -8,8 +8,11 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
 	newznabcache "github.com/MunifTanjim/stremthru/internal/newznab/cache"
 	newznab_client "github.com/MunifTanjim/stremthru/internal/newznab/client"
 	"github.com/MunifTanjim/stremthru/internal/server"
@@ -32,6 +35,8 @@ type WrappedStream struct {
 	nzbURL         string
 	lockedDownload bool
 	lockedProvider string
+	isIncomplete   bool
+	isBadRelease   bool
 }
 
 func (s WrappedStream) IsSortable() bool {
@@ -320,10 +325,34 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 
 	stremio_transformer.SortStreams(wrappedStreams, ud.Sort)
 
+	checkStreamsHealth(wrappedStreams, ctx.Log)
+	wrappedStreams = applyStreamsHealth(wrappedStreams, ctx.Log)
+
 	streamBaseUrl := ExtractRequestBaseURL(r).JoinPath("/stremio/newz", eud, "playback", id)
 
+	playbackCandidatesToken := ""
+	if includeStream {
+		candidates := []playbackCandidate{}
+		badCandidates := []playbackCandidate{}
+		for i := range wrappedStreams {
+			wStream := &wrappedStreams[i]
+			if wStream.lockedDownload {
+				continue
+			}
+			candidate := playbackCandidate{Link: wStream.nzbURL, Name: wStream.R.TTitle}
+			if isBadRelease(wStream.nzbURL) {
+				wStream.isBadRelease = true
+				badCandidates = append(badCandidates, candidate)
+			} else {
+				candidates = append(candidates, candidate)
+			}
+		}
+		playbackCandidatesToken = savePlaybackCandidates(id, append(candidates, badCandidates...))
+	}
+
 	cachedStreams := []stremio.Stream{}
 	uncachedStreams := []stremio.Stream{}
+	badReleaseStreams := []stremio.Stream{}
 
 	for _, wStream := range wrappedStreams {
 		hash := wStream.R.Hash
@@ -343,12 +372,20 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 			if wasPreviouslySelected {
 				stream.Name = "📎 " + stream.Name
 			}
+			if wStream.isIncomplete {
+				stream.Name = "⚠️ " + stream.Name
+			}
 			steamUrl := streamBaseUrl.JoinPath(string(UserDataModeStream), "st", url.PathEscape(nzbUrl), "/")
 			if wStream.R.TTitle != "" {
 				steamUrl = steamUrl.JoinPath(url.PathEscape(wStream.R.TTitle))
 			}
+			if playbackCandidatesToken != "" {
+				steamUrl.RawQuery = url.Values{playbackCandidatesQueryParam: {playbackCandidatesToken}}.Encode()
+			}
 			stream.URL = steamUrl.String()
-			if wasPreviouslySelected {
+			if wStream.isBadRelease {
+				badReleaseStreams = append(badReleaseStreams, *stream)
+			} else if wasPreviouslySelected {
 				cachedStreams = append(cachedStreams, *stream)
 			} else {
 				uncachedStreams = append(uncachedStreams, *stream)
@@ -428,7 +465,7 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	streams := make([]stremio.Stream, len(cachedStreams)+len(uncachedStreams))
+	streams := make([]stremio.Stream, len(cachedStreams)+len(uncachedStreams)+len(badReleaseStreams))
 	idx := 0
 	for i := range cachedStreams {
 		streams[idx] = cachedStreams[i]
@@ -438,6 +475,10 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 		streams[idx] = uncachedStreams[i]
 		idx++
 	}
+	for i := range badReleaseStreams {
+		streams[idx] = badReleaseStreams[i]
+		idx++
+	}
 
 	SendResponse(w, r, 200, &stremio.StreamHandlerResponse{
 		Streams: streams,
@@ -457,3 +498,96 @@ func filterStreams(streams []WrappedStream, filter *stremio_transformer.StreamFi
 	}
 	return result
 }
+
+// streamsHealthCheckWait is how long the stream listing waits for the health
+// checks of the search results.
+const streamsHealthCheckWait = 5 * time.Second
+
+// checkStreamsHealth checks the health of the top ranked streams not checked
+// yet, so that the unhealthy ones are de-ranked before they are ever added.
+// The checks not finished within the wait continue in the background, and
+// are used by the next request.
+func checkStreamsHealth(streams []WrappedStream, log *logger.Logger) {
+	limit := config.Newz.HealthCheckSearchResults
+	if limit <= 0 || config.Newz.HealthCheckSampleSize <= 0 || len(streams) == 0 {
+		return
+	}
+
+	hashes := make([]string, len(streams))
+	for i := range streams {
+		hashes[i] = streams[i].R.Hash
+	}
+	healthByHash, err := nzb_info.GetHealthByHashes(hashes)
+	if err != nil {
+		log.Warn("failed to get nzb health", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := range streams {
+		if limit == 0 {
+			break
+		}
+		stream := &streams[i]
+		if stream.lockedDownload || stream.nzbURL == "" || isBadRelease(stream.nzbURL) {
+			continue
+		}
+		if _, ok := healthByHash[stream.R.Hash]; ok {
+			continue
+		}
+		limit--
+		nzbURL, name := stream.nzbURL, stream.R.TTitle
+		wg.Go(func() {
+			if _, err := nzb_info.CheckHealth(nzbURL, name, log); err != nil {
+				log.Debug("failed to check nzb health", "error", err, "name", name)
+			}
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(streamsHealthCheckWait):
+		log.Debug("nzb health checks continue in background")
+	}
+}
+
+// applyStreamsHealth drops the streams whose NZB health check estimated
+// completeness below the configured minimum, and moves the rest of the
+// incomplete ones after the complete ones, preserving the sort order.
+func applyStreamsHealth(streams []WrappedStream, log *logger.Logger) []WrappedStream {
+	if len(streams) == 0 {
+		return streams
+	}
+
+	hashes := make([]string, len(streams))
+	for i := range streams {
+		hashes[i] = streams[i].R.Hash
+	}
+	healthByHash, err := nzb_info.GetHealthByHashes(hashes)
+	if err != nil {
+		log.Warn("failed to get nzb health", "error", err)
+		return streams
+	}
+
+	complete := make([]WrappedStream, 0, len(streams))
+	incomplete := []WrappedStream{}
+	for i := range streams {
+		stream := streams[i]
+		health, ok := healthByHash[stream.R.Hash]
+		if !ok || health.IsComplete() {
+			complete = append(complete, stream)
+			continue
+		}
+		if health.Completeness < config.Newz.HealthMinCompleteness {
+			continue
+		}
+		stream.isIncomplete = true
+		incomplete = append(incomplete, stream)
+	}
+	return append(complete, incomplete...)
+}
@@ -0,0 +1,58 @@
+package stremio_newz
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+	"github.com/MunifTanjim/stremthru/internal/subtitle"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+)
+
+func handleSubtitles(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	eud := ud.GetEncoded()
+	subtitleBaseUrl := ExtractRequestBaseURL(r).JoinPath("/stremio/newz", eud, "_/subtitle")
+	stremio_shared.SendEmbeddedSubtitles(w, r, eud, subtitleBaseUrl)
+}
+
+func handleSubtitle(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) && !IsMethod(r, http.MethodHead) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	if _, err := getUserData(r); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	stremio_shared.ServeEmbeddedSubtitle(w, r)
+}
+
+func newUsenetEmbeddedSubtitleSource(pool *usenet_pool.Pool, strem *usenetStremResult) *stremio_shared.EmbeddedSubtitleSource {
+	return &stremio_shared.EmbeddedSubtitleSource{
+		Source: subtitle.Source{
+			Hash: stremio_shared.GetEmbeddedSubtitleHash(strem.hash, strem.contentPath),
+			Open: func(ctx context.Context) (subtitle.Reader, error) {
+				ctx = context.WithValue(ctx, usenet_pool.NZBHashContextKey, strem.hash)
+				stream, err := pool.StreamByContentPath(ctx, strem.nzbDoc, strem.contentPath, strem.streamConfig)
+				if err != nil {
+					return nil, err
+				}
+				return subtitle.NewReadSeekerReader(stream, stream.Size), nil
+			},
+		},
+	}
+}
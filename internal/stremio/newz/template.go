@@ -105,6 +105,12 @@ var indexerTypeOptions = func() []configure.ConfigOption {
 				Label: "StremThru",
 			},
 		)
+		if len(config.Newz.ScanGroups) > 0 {
+			options = append(options, configure.ConfigOption{
+				Value: string(stremio_userdata.NewzIndexerTypeUsenet),
+				Label: "StremThru Usenet",
+			})
+		}
 	}
 	options = append(options, configure.ConfigOption{
 		Value: string(stremio_userdata.NewzIndexerTypeTorbox),
@@ -118,7 +124,7 @@ func newTemplateDataIndexer(index int, indexerType, name, url, apiKey string) Te
 		indexerType = indexerTypeOptions[0].Value
 	}
 	idx := strconv.Itoa(index)
-	isURLDisabled := indexerType == string(stremio_userdata.NewzIndexerTypeTorbox) || indexerType == string(stremio_userdata.NewzIndexerTypeStremThru)
+	isURLDisabled := indexerType == string(stremio_userdata.NewzIndexerTypeTorbox) || indexerType == string(stremio_userdata.NewzIndexerTypeStremThru) || indexerType == string(stremio_userdata.NewzIndexerTypeUsenet)
 	return TemplateDataIndexer{
 		Type: configure.Config{
 			Key:      "indexers[" + idx + "].type",
@@ -6,6 +6,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	stremio_list "github.com/MunifTanjim/stremthru/internal/stremio/list"
+	stremio_meta "github.com/MunifTanjim/stremthru/internal/stremio/meta"
 	stremio_newz "github.com/MunifTanjim/stremthru/internal/stremio/newz"
 	stremio_sidekick "github.com/MunifTanjim/stremthru/internal/stremio/sidekick"
 	stremio_store "github.com/MunifTanjim/stremthru/internal/stremio/store"
@@ -25,6 +26,14 @@ func getAddonCatalog(r *http.Request) *stremio.AddonCatalogHandlerResponse {
 			TransportUrl:  shared.ExtractRequestBaseURL(r).JoinPath("stremio/list/manifest.json").String(),
 		})
 	}
+	if config.Feature.HasStremioMeta() {
+		manifest := stremio_meta.GetManifest(r, &stremio_meta.UserData{})
+		addons = append(addons, stremio.Addon{
+			Manifest:      *manifest,
+			TransportName: "http",
+			TransportUrl:  shared.ExtractRequestBaseURL(r).JoinPath("stremio/meta/manifest.json").String(),
+		})
+	}
 	if config.Feature.IsEnabled(config.FeatureStremioWrap) {
 		addons = append(addons, stremio.Addon{
 			Manifest:      *stremio_wrap.GetManifest(r, []stremio.Manifest{}, &stremio_wrap.UserData{}),
@@ -0,0 +1,220 @@
+package stremio_shared
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/subtitle"
+	"github.com/MunifTanjim/stremthru/internal/torrent_stream"
+	"github.com/MunifTanjim/stremthru/internal/torrent_stream/media_info"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/stremio"
+)
+
+// how long the subtitles request waits for the playback request to resolve
+// the file, stremio fires both at the same time.
+const embeddedSubtitleSourceWaitTime = 5 * time.Second
+
+type EmbeddedSubtitleSource struct {
+	subtitle.Source
+	GetMediaInfo func() *media_info.MediaInfo
+}
+
+var embeddedSubtitleSourceCache = cache.NewLRUCache[*EmbeddedSubtitleSource](&cache.CacheConfig{
+	Name:     "stremio:embedded-subtitle:source",
+	Lifetime: 3 * time.Hour,
+	MaxSize:  256,
+})
+
+var embeddedSubtitleSourceHashCache = cache.NewLRUCache[string](&cache.CacheConfig{
+	Name:     "stremio:embedded-subtitle:source-hash",
+	Lifetime: 3 * time.Hour,
+	MaxSize:  4096,
+})
+
+func GetEmbeddedSubtitleHash(parts ...string) string {
+	return util.MD5Hash(strings.Join(parts, ":"))
+}
+
+func NewEmbeddedSubtitleHTTPSource(hash, link string) *EmbeddedSubtitleSource {
+	return &EmbeddedSubtitleSource{
+		Source: subtitle.Source{
+			Hash: hash,
+			Open: func(ctx context.Context) (subtitle.Reader, error) {
+				return subtitle.NewHTTPReader(ctx, config.DefaultHTTPClient, link)
+			},
+		},
+	}
+}
+
+func NewEmbeddedSubtitleTorrentSource(hash, path, link string) *EmbeddedSubtitleSource {
+	source := NewEmbeddedSubtitleHTTPSource(GetEmbeddedSubtitleHash(hash, path), link)
+	source.GetMediaInfo = func() *media_info.MediaInfo {
+		return torrent_stream.GetMediaInfo(hash, path)
+	}
+	return source
+}
+
+func getEmbeddedSubtitleSourceKey(eud, kind, value string) string {
+	return eud + ":" + kind + ":" + value
+}
+
+// SetEmbeddedSubtitleSource records the file being played, so that the
+// subtitles resource can find it by the video id or by the filename.
+func SetEmbeddedSubtitleSource(eud, videoId, filename string, source *EmbeddedSubtitleSource) {
+	if !config.Feature.HasEmbeddedSubtitle() {
+		return
+	}
+	embeddedSubtitleSourceCache.Add(source.Hash, source)
+	if videoId != "" {
+		embeddedSubtitleSourceHashCache.Add(getEmbeddedSubtitleSourceKey(eud, "id", videoId), source.Hash)
+	}
+	if filename != "" {
+		embeddedSubtitleSourceHashCache.Add(getEmbeddedSubtitleSourceKey(eud, "filename", filename), source.Hash)
+	}
+}
+
+func findEmbeddedSubtitleSource(r *http.Request, eud, videoId, filename string) *EmbeddedSubtitleSource {
+	keys := []string{}
+	if filename != "" {
+		keys = append(keys, getEmbeddedSubtitleSourceKey(eud, "filename", filename))
+	}
+	if videoId != "" {
+		keys = append(keys, getEmbeddedSubtitleSourceKey(eud, "id", videoId))
+	}
+
+	deadline := time.Now().Add(embeddedSubtitleSourceWaitTime)
+	for {
+		for _, key := range keys {
+			hash := ""
+			source := &EmbeddedSubtitleSource{}
+			if embeddedSubtitleSourceHashCache.Get(key, &hash) && embeddedSubtitleSourceCache.Get(hash, &source) {
+				return source
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// SendEmbeddedSubtitles responds to the subtitles resource with the text
+// subtitle tracks of the file being played for the video.
+func SendEmbeddedSubtitles(w http.ResponseWriter, r *http.Request, eud string, subtitleBaseUrl *url.URL) {
+	res := &stremio.SubtitlesHandlerResponse{
+		Subtitles: []stremio.Subtitle{},
+	}
+
+	if !config.Feature.HasEmbeddedSubtitle() {
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	log := server.GetReqCtx(r).Log
+
+	videoId := GetPathValue(r, "id")
+	filename := ""
+	if extra, err := url.ParseQuery(GetPathValue(r, "extra")); err == nil {
+		filename = extra.Get("filename")
+	}
+
+	source := findEmbeddedSubtitleSource(r, eud, videoId, filename)
+	if source == nil {
+		log.Debug("no embedded subtitle source found", "id", videoId, "filename", filename)
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	tracks, err := subtitle.GetTracks(r.Context(), &source.Source)
+	if err != nil {
+		LogError(r, "failed to get embedded subtitle tracks", err)
+		SendResponse(w, r, 200, res)
+		return
+	}
+
+	if len(tracks) > 0 && !subtitle.IsExtracted(source.Hash) {
+		go func() {
+			if _, err := subtitle.GetFile(&source.Source); err != nil {
+				log.Error("failed to extract embedded subtitles", "error", err, "hash", source.Hash)
+			}
+		}()
+	}
+
+	var mi *media_info.MediaInfo
+	if len(tracks) > 0 && source.GetMediaInfo != nil {
+		mi = source.GetMediaInfo()
+	}
+
+	for i := range tracks {
+		t := &tracks[i]
+		lang := t.Language
+		if mi != nil && t.Index < len(mi.Subtitle) && mi.Subtitle[t.Index].Language != "" {
+			lang = mi.Subtitle[t.Index].Language
+		}
+		trackId := strconv.Itoa(t.Number)
+		res.Subtitles = append(res.Subtitles, stremio.Subtitle{
+			Id:   "embedded:" + source.Hash + ":" + trackId,
+			Url:  subtitleBaseUrl.JoinPath(source.Hash, trackId+"."+string(subtitle.FormatSRT)).String(),
+			Lang: lang,
+		})
+	}
+
+	SendResponse(w, r, 200, res)
+}
+
+// ServeEmbeddedSubtitle serves an extracted track, as `{number}.srt` or
+// `{number}.vtt`.
+func ServeEmbeddedSubtitle(w http.ResponseWriter, r *http.Request) {
+	if !config.Feature.HasEmbeddedSubtitle() {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	hash := r.PathValue("fileHash")
+	trackId, ext, _ := strings.Cut(r.PathValue("trackFile"), ".")
+	number, err := strconv.Atoi(trackId)
+	format := subtitle.Format(ext)
+	if err != nil || !format.IsValid() {
+		shared.ErrorBadRequest(r, "invalid subtitle track").Send(w, r)
+		return
+	}
+
+	file := subtitle.GetCachedFile(hash)
+	if file == nil {
+		source := &EmbeddedSubtitleSource{}
+		if !embeddedSubtitleSourceCache.Get(hash, &source) {
+			shared.ErrorNotFound(r).Send(w, r)
+			return
+		}
+		file, err = subtitle.GetFile(&source.Source)
+		if err != nil {
+			LogError(r, "failed to extract embedded subtitles", err)
+			shared.ErrorInternalServerError(r, "failed to extract subtitles").Send(w, r)
+			return
+		}
+	}
+
+	track := file.GetTrack(number)
+	if track == nil {
+		shared.ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(track.Render(format))
+}
@@ -17,6 +17,12 @@ func GetStremThruAddons() []stremio_template.BaseDataStremThruAddon {
 			URL:  "/stremio/list",
 		})
 	}
+	if config.Feature.HasStremioMeta() {
+		addons = append(addons, stremio_template.BaseDataStremThruAddon{
+			Name: "Meta",
+			URL:  "/stremio/meta",
+		})
+	}
 	if config.Feature.IsEnabled(config.FeatureStremioWrap) {
 		addons = append(addons, stremio_template.BaseDataStremThruAddon{
 			Name: "Wrap",
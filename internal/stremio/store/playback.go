@@ -9,6 +9,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	store_video "github.com/MunifTanjim/stremthru/internal/store/video"
+	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_store_webdl "github.com/MunifTanjim/stremthru/internal/stremio/store/webdl"
 	"github.com/MunifTanjim/stremthru/internal/torz"
 	"github.com/MunifTanjim/stremthru/store"
@@ -113,6 +114,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 		}
 
 		stremLinkCache.Add(cacheKey, data.Link)
+		stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), "", fileName, stremio_shared.NewEmbeddedSubtitleHTTPSource(stremio_shared.GetEmbeddedSubtitleHash(idr.getStoreCode(), url), data.Link))
 		http.Redirect(w, r, data.Link, http.StatusFound)
 	} else if idr.isWebDL || videoId == WEBDL_META_ID_INDICATOR {
 		storeName := ctx.Store.GetName()
@@ -172,6 +174,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 		}
 
 		stremLinkCache.Add(cacheKey, stLink)
+		stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), "", fileName, stremio_shared.NewEmbeddedSubtitleHTTPSource(stremio_shared.GetEmbeddedSubtitleHash(idr.getStoreCode(), url), stLink))
 		http.Redirect(w, r, stLink, http.StatusFound)
 	} else {
 		stLink, err := shared.GenerateStremThruLink(r, &ctx.Context, url, fileName)
@@ -184,6 +187,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 		go torz.TryQueueMediaInfoProbe(&ctx.Context, url, stLink)
 
 		stremLinkCache.Add(cacheKey, stLink.Link)
+		stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), "", fileName, stremio_shared.NewEmbeddedSubtitleHTTPSource(stremio_shared.GetEmbeddedSubtitleHash(idr.getStoreCode(), url), stLink.Link))
 		http.Redirect(w, r, stLink.Link, http.StatusFound)
 	}
 }
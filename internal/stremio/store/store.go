@@ -44,9 +44,14 @@ func AddStremioStoreEndpoints(mux *http.ServeMux) {
 
 	router.HandleFunc("/{userData}/stream/{contentType}/{idJson}", withCors(handleStream))
 
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{idJson}", withCors(handleSubtitles))
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{id}/{extraJson}", withCors(handleSubtitles))
+
 	router.HandleFunc("/{userData}/_/action/{actionId}", withCors(handleAction))
 	router.HandleFunc("/{userData}/_/strem/{videoId}/{$}", withCors(handleStrem))
 	router.HandleFunc("/{userData}/_/strem/{videoId}/{fileName}", withCors(handleStrem))
 
+	router.HandleFunc("/{userData}/_/subtitle/{fileHash}/{trackFile}", withCors(handleSubtitle))
+
 	mux.Handle("/stremio/store/", http.StripPrefix("/stremio/store", commonMiddleware(router)))
 }
@@ -0,0 +1,39 @@
+package stremio_store
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+)
+
+func handleSubtitles(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	ud, err := getUserData(r)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	eud := ud.GetEncoded()
+	subtitleBaseUrl := ExtractRequestBaseURL(r).JoinPath("/stremio/store", eud, "_/subtitle")
+	stremio_shared.SendEmbeddedSubtitles(w, r, eud, subtitleBaseUrl)
+}
+
+func handleSubtitle(w http.ResponseWriter, r *http.Request) {
+	if !IsMethod(r, http.MethodGet) && !IsMethod(r, http.MethodHead) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	if _, err := getUserData(r); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	stremio_shared.ServeEmbeddedSubtitle(w, r)
+}
@@ -67,6 +67,14 @@ func GetManifest(r *http.Request, ud *UserData) *stremio.Manifest {
 		},
 	}
 
+	if isConfigured && config.Feature.HasEmbeddedSubtitle() {
+		manifest.Resources = append(manifest.Resources, stremio.Resource{
+			Name:       stremio.ResourceNameSubtitles,
+			Types:      streamResource.Types,
+			IDPrefixes: streamResource.IDPrefixes,
+		})
+	}
+
 	return manifest
 }
 
@@ -263,6 +263,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 		}
 
 		stremLinkCache.Add(cacheKey, glRes.Link)
+		stremio_shared.SetEmbeddedSubtitleSource(ud.GetEncoded(), sid, fileName, stremio_shared.NewEmbeddedSubtitleTorrentSource(magnet.Hash, file.GetPath(), glRes.Link))
 
 		if storeCode == store.StoreCodeTorBox {
 			torrent_stream.QueueMediaInfoProbe(magnet.Hash, file.GetPath(), glRes.Link)
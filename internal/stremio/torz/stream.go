@@ -24,6 +24,7 @@ import (
 	torznab_indexer_syncinfo "github.com/MunifTanjim/stremthru/internal/torznab/indexer/syncinfo"
 	"github.com/MunifTanjim/stremthru/internal/torznab/jackett"
 	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
 	"github.com/MunifTanjim/stremthru/store"
 	"github.com/MunifTanjim/stremthru/stremio"
 	"github.com/alitto/pond/v2"
@@ -538,6 +539,14 @@ func GetStreamsForHashes(stremType, stremId string, hashes []string, nsid *torre
 			continue
 		}
 
+		if !tInfo.Private {
+			worker_queue.TrackerScraperQueue.Queue(worker_queue.TrackerScraperQueueItem{Hash: hash})
+		}
+
+		if _, ok := filesByHashes[hash]; !ok && !tInfo.Private {
+			worker_queue.MetadataFetcherQueue.Queue(worker_queue.MetadataFetcherQueueItem{Hash: hash})
+		}
+
 		var file *torrent_stream.File
 		if files, ok := filesByHashes[hash]; ok {
 			idToMatch := stremId
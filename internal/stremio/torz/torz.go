@@ -44,8 +44,13 @@ func AddStremioTorzEndpoints(mux *http.ServeMux) {
 
 	router.HandleFunc("/{userData}/stream/{contentType}/{idJson}", withCors(handleStream))
 
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{idJson}", withCors(handleSubtitles))
+	router.HandleFunc("/{userData}/subtitles/{contentType}/{id}/{extraJson}", withCors(handleSubtitles))
+
 	router.HandleFunc("/{userData}/_/strem/{stremId}/{storeCode}/{magnetHash}/{fileIdx}/{$}", withCors(handleStrem))
 	router.HandleFunc("/{userData}/_/strem/{stremId}/{storeCode}/{magnetHash}/{fileIdx}/{fileName}", withCors(handleStrem))
 
+	router.HandleFunc("/{userData}/_/subtitle/{fileHash}/{trackFile}", withCors(handleSubtitle))
+
 	mux.Handle("/stremio/torz/", http.StripPrefix("/stremio/torz", commonMiddleware(router)))
 }
@@ -9,6 +9,7 @@ import (
 	newznab_client "github.com/MunifTanjim/stremthru/internal/newznab/client"
 	newznab_stremthru "github.com/MunifTanjim/stremthru/internal/newznab/stremthru"
 	newznab_torbox "github.com/MunifTanjim/stremthru/internal/newznab/torbox"
+	newznab_usenet "github.com/MunifTanjim/stremthru/internal/newznab/usenet"
 	"github.com/MunifTanjim/stremthru/store/torbox"
 )
 
@@ -18,6 +19,7 @@ const (
 	NewzIndexerTypeGeneric   NewzIndexerType = "generic"
 	NewzIndexerTypeStremThru NewzIndexerType = "stremthru"
 	NewzIndexerTypeTorbox    NewzIndexerType = "torbox"
+	NewzIndexerTypeUsenet    NewzIndexerType = "usenet"
 )
 
 type NewzIndexer struct {
@@ -34,7 +36,7 @@ func (i NewzIndexer) Validate() (string, error) {
 	if i.Name == "" {
 		return "name", fmt.Errorf("indexer name is required")
 	}
-	if i.Type != NewzIndexerTypeTorbox && i.Type != NewzIndexerTypeStremThru && i.URL == "" {
+	if i.Type != NewzIndexerTypeTorbox && i.Type != NewzIndexerTypeStremThru && i.Type != NewzIndexerTypeUsenet && i.URL == "" {
 		return "url", fmt.Errorf("indexer url is required")
 	}
 	return "", nil
@@ -123,6 +125,18 @@ func (ud *UserDataNewzIndexers) Prepare() ([]newznab_client.Indexer, error) {
 			}
 			clients = append(clients, client)
 
+		case NewzIndexerTypeUsenet:
+			key := "usenet:" + apiKey
+			var client newznab_client.Indexer
+			if !newznabIndexerCache.Get(key, &client) {
+				client = newznab_usenet.NewIndexer(apiKey)
+				err := newznabIndexerCache.Add(key, client)
+				if err != nil {
+					return clients, err
+				}
+			}
+			clients = append(clients, client)
+
 		default:
 			return clients, fmt.Errorf("unsupported newz indexer type: %s", indexer.Type)
 		}
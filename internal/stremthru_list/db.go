@@ -0,0 +1,498 @@
+package stremthru_list
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const TableName = "stremthru_list"
+
+type StremThruList struct {
+	Id          string
+	Name        string
+	Description string
+	Owner       string
+	Secret      string
+	CAt         db.Timestamp
+	UAt         db.Timestamp
+
+	ItemCount int
+	Items     []StremThruListItem
+}
+
+func (l *StremThruList) IsSecretValid(secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(l.Secret), []byte(secret)) == 1
+}
+
+func (l *StremThruList) GetDisplayName() string {
+	if l.Name == "" {
+		return "StremThru List"
+	}
+	return l.Name
+}
+
+var Column = struct {
+	Id          string
+	Name        string
+	Description string
+	Owner       string
+	Secret      string
+	CAt         string
+	UAt         string
+}{
+	Id:          "id",
+	Name:        "name",
+	Description: "description",
+	Owner:       "owner",
+	Secret:      "secret",
+	CAt:         "cat",
+	UAt:         "uat",
+}
+
+var columns = []string{
+	Column.Id,
+	Column.Name,
+	Column.Description,
+	Column.Owner,
+	Column.Secret,
+	Column.CAt,
+	Column.UAt,
+}
+
+const ItemTableName = "stremthru_list_item"
+
+type StremThruListItem struct {
+	ListId string
+	Id     string // stremio meta id, e.g. `tt0111161`, `tmdb:278`, `kitsu:1`
+	Type   ItemType
+	Name   string
+	Poster string
+	Year   int
+	Idx    int
+	CAt    db.Timestamp
+}
+
+var ItemColumn = struct {
+	ListId string
+	Id     string
+	Type   string
+	Name   string
+	Poster string
+	Year   string
+	Idx    string
+	CAt    string
+}{
+	ListId: "list_id",
+	Id:     "id",
+	Type:   "type",
+	Name:   "name",
+	Poster: "poster",
+	Year:   "year",
+	Idx:    "idx",
+	CAt:    "cat",
+}
+
+var itemColumns = []string{
+	ItemColumn.ListId,
+	ItemColumn.Id,
+	ItemColumn.Type,
+	ItemColumn.Name,
+	ItemColumn.Poster,
+	ItemColumn.Year,
+	ItemColumn.Idx,
+	ItemColumn.CAt,
+}
+
+func scanList(row interface{ Scan(dest ...any) error }) (*StremThruList, error) {
+	list := StremThruList{}
+	if err := row.Scan(
+		&list.Id,
+		&list.Name,
+		&list.Description,
+		&list.Owner,
+		&list.Secret,
+		&list.CAt,
+		&list.UAt,
+		&list.ItemCount,
+	); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+var query_get_all = fmt.Sprintf(
+	`SELECT %s, (SELECT count(*) FROM %s i WHERE i.%s = l.%s) FROM %s l`,
+	db.JoinPrefixedColumnNames("l.", columns...),
+	ItemTableName,
+	ItemColumn.ListId,
+	Column.Id,
+	TableName,
+)
+
+var query_get_all_order_by = fmt.Sprintf(
+	` ORDER BY l.%s DESC`,
+	Column.CAt,
+)
+
+var query_get_all_by_owner = query_get_all + fmt.Sprintf(
+	` WHERE l.%s = ?`,
+	Column.Owner,
+)
+
+func getAll(query string, args ...any) ([]StremThruList, error) {
+	rows, err := db.Query(query+query_get_all_order_by, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []StremThruList{}
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, *list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func GetAll() ([]StremThruList, error) {
+	return getAll(query_get_all)
+}
+
+func GetAllByOwner(owner string) ([]StremThruList, error) {
+	return getAll(query_get_all_by_owner, owner)
+}
+
+var query_get_by_id = query_get_all + fmt.Sprintf(
+	` WHERE l.%s = ?`,
+	Column.Id,
+)
+
+func GetById(id string) (*StremThruList, error) {
+	list, err := scanList(db.QueryRow(query_get_by_id, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+func GetByIdWithItems(id string) (*StremThruList, error) {
+	list, err := GetById(id)
+	if err != nil || list == nil {
+		return list, err
+	}
+	items, err := GetItems(id)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = items
+	return list, nil
+}
+
+var query_insert = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (?,?,?,?,?)`,
+	TableName,
+	db.JoinColumnNames(
+		Column.Id,
+		Column.Name,
+		Column.Description,
+		Column.Owner,
+		Column.Secret,
+	),
+)
+
+func newId() string {
+	return util.GenerateRandomString(12, util.CharSet.AlphaNumeric)
+}
+
+func Create(name, description, owner string) (*StremThruList, error) {
+	list := &StremThruList{
+		Id:          newId(),
+		Name:        name,
+		Description: description,
+		Owner:       owner,
+		Secret:      rand.Text(),
+		CAt:         db.Timestamp{Time: time.Now()},
+		UAt:         db.Timestamp{Time: time.Now()},
+	}
+	if _, err := db.Exec(
+		query_insert,
+		list.Id,
+		list.Name,
+		list.Description,
+		list.Owner,
+		list.Secret,
+	); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+var query_update = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.Name,
+	Column.Description,
+	Column.UAt,
+	db.CurrentTimestamp,
+	Column.Id,
+)
+
+func Update(id, name, description string) error {
+	_, err := db.Exec(query_update, name, description, id)
+	return err
+}
+
+var query_update_secret = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.Secret,
+	Column.UAt,
+	db.CurrentTimestamp,
+	Column.Id,
+)
+
+func RotateSecret(id string) (string, error) {
+	secret := rand.Text()
+	if _, err := db.Exec(query_update_secret, secret, id); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+var query_delete = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.Id,
+)
+
+var query_delete_items_by_list_id = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	ItemTableName,
+	ItemColumn.ListId,
+)
+
+func Delete(id string) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	if _, err = tx.Exec(query_delete_items_by_list_id, id); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(query_delete, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+var query_get_items = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s ASC`,
+	db.JoinColumnNames(itemColumns...),
+	ItemTableName,
+	ItemColumn.ListId,
+	ItemColumn.Idx,
+)
+
+func GetItems(listId string) ([]StremThruListItem, error) {
+	rows, err := db.Query(query_get_items, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []StremThruListItem{}
+	for rows.Next() {
+		item := StremThruListItem{}
+		if err := rows.Scan(
+			&item.ListId,
+			&item.Id,
+			&item.Type,
+			&item.Name,
+			&item.Poster,
+			&item.Year,
+			&item.Idx,
+			&item.CAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+var query_has_item = fmt.Sprintf(
+	`SELECT 1 FROM %s WHERE %s = ? AND %s = ?`,
+	ItemTableName,
+	ItemColumn.ListId,
+	ItemColumn.Id,
+)
+
+func HasItem(listId, itemId string) (bool, error) {
+	var exists int
+	if err := db.QueryRow(query_has_item, listId, itemId).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+var query_get_next_item_idx = fmt.Sprintf(
+	`SELECT coalesce(max(%s), -1) + 1 FROM %s WHERE %s = ?`,
+	ItemColumn.Idx,
+	ItemTableName,
+	ItemColumn.ListId,
+)
+
+var query_insert_items_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	ItemTableName,
+	db.JoinColumnNames(itemColumns[:len(itemColumns)-1]...),
+)
+var query_insert_items_values_placeholder = fmt.Sprintf(
+	`(%s)`,
+	util.RepeatJoin("?", len(itemColumns)-1, ","),
+)
+var query_insert_items_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s, %s) DO NOTHING`,
+	ItemColumn.ListId,
+	ItemColumn.Id,
+)
+
+var query_touch = fmt.Sprintf(
+	`UPDATE %s SET %s = %s WHERE %s = ?`,
+	TableName,
+	Column.UAt,
+	db.CurrentTimestamp,
+	Column.Id,
+)
+
+// AddItems appends the items at the end of the list, skipping the ones
+// already in it.
+func AddItems(listId string, items []StremThruListItem) (err error) {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	nextIdx := 0
+	if err = tx.QueryRow(query_get_next_item_idx, listId).Scan(&nextIdx); err != nil {
+		return err
+	}
+
+	added := int64(0)
+	for cItems := range slices.Chunk(items, 200) {
+		query := query_insert_items_before_values +
+			util.RepeatJoin(query_insert_items_values_placeholder, len(cItems), ",") +
+			query_insert_items_after_values
+		args := make([]any, 0, len(cItems)*(len(itemColumns)-1))
+		for i := range cItems {
+			item := &cItems[i]
+			args = append(args, listId, item.Id, item.Type, item.Name, item.Poster, item.Year, nextIdx)
+			nextIdx++
+		}
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		added += count
+	}
+
+	// already in the list
+	if added == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(query_touch, listId)
+	return err
+}
+
+var query_remove_items = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s `,
+	ItemTableName,
+	ItemColumn.ListId,
+	ItemColumn.Id,
+)
+
+func RemoveItems(listId string, itemIds []string) (err error) {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	query_in_ids, args := db.InStringValues(itemIds)
+	result, err := tx.Exec(query_remove_items+query_in_ids, append([]any{listId}, args...)...)
+	if err != nil {
+		return err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// not in the list
+	if removed == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(query_touch, listId)
+	return err
+}
@@ -0,0 +1,195 @@
+package stremthru_list
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+)
+
+type ItemType string
+
+const (
+	ItemTypeMovie  ItemType = "movie"
+	ItemTypeSeries ItemType = "series"
+)
+
+func (t ItemType) IsValid() bool {
+	return t == ItemTypeMovie || t == ItemTypeSeries
+}
+
+var animeIdPrefixes = []string{"anidb:", "anilist:", "kitsu:", "mal:"}
+
+var ErrUnsupportedId = errors.New("unsupported id")
+
+// ParseItemId normalizes a stremio meta/stream id (e.g. `tt0903747:1:2`,
+// `tmdb:1396`, `kitsu:1:3`) into the id stored for a list item.
+func ParseItemId(idStr string) (string, error) {
+	idStr = strings.TrimSpace(idStr)
+	if strings.HasPrefix(idStr, "tt") {
+		id, _, _ := strings.Cut(idStr, ":")
+		return id, nil
+	}
+	if rest, ok := strings.CutPrefix(idStr, "tmdb:"); ok {
+		id, _, _ := strings.Cut(rest, ":")
+		if id == "" {
+			return "", ErrUnsupportedId
+		}
+		return "tmdb:" + id, nil
+	}
+	for _, prefix := range animeIdPrefixes {
+		if rest, ok := strings.CutPrefix(idStr, prefix); ok {
+			id, _, _ := strings.Cut(rest, ":")
+			if id == "" {
+				return "", ErrUnsupportedId
+			}
+			return prefix + id, nil
+		}
+	}
+	return "", ErrUnsupportedId
+}
+
+func IsAnimeItemId(id string) bool {
+	for _, prefix := range animeIdPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type ItemInput struct {
+	Id     string
+	Type   ItemType
+	Name   string
+	Poster string
+}
+
+var getIMDBIdByTMDBId = imdb_title.GetIMDBIdByTMDBId
+var listIMDBTitlesByIds = imdb_title.ListByIds
+
+// resolveItemIds replaces the ids of the inputs with the ids stored for the
+// list items. TMDB ids are converted to IMDb ids when the mapping is known.
+func resolveItemIds(inputs []ItemInput) error {
+	tmdbMovieIds, tmdbShowIds := []string{}, []string{}
+	for i := range inputs {
+		input := &inputs[i]
+		id, err := ParseItemId(input.Id)
+		if err != nil {
+			return errors.New(ErrUnsupportedId.Error() + ": " + input.Id)
+		}
+		input.Id = id
+		if input.Type != "" && !input.Type.IsValid() {
+			return errors.New("invalid type: " + string(input.Type))
+		}
+		if tmdbId, ok := strings.CutPrefix(id, "tmdb:"); ok {
+			switch input.Type {
+			case ItemTypeMovie:
+				tmdbMovieIds = append(tmdbMovieIds, tmdbId)
+			case ItemTypeSeries:
+				tmdbShowIds = append(tmdbShowIds, tmdbId)
+			default:
+				return errors.New("missing type for id: " + input.Id)
+			}
+		}
+	}
+
+	imdbIdByTmdbMovieId, imdbIdByTmdbShowId, err := getIMDBIdByTMDBId(tmdbMovieIds, tmdbShowIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range inputs {
+		input := &inputs[i]
+		if tmdbId, ok := strings.CutPrefix(input.Id, "tmdb:"); ok {
+			imdbIdByTmdbId := imdbIdByTmdbMovieId
+			if input.Type == ItemTypeSeries {
+				imdbIdByTmdbId = imdbIdByTmdbShowId
+			}
+			if imdbId, ok := imdbIdByTmdbId[tmdbId]; ok && imdbId != "" {
+				input.Id = imdbId
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveItemIds returns the ids stored for the inputs by NewItems, e.g. to
+// remove them from a list.
+func ResolveItemIds(inputs []ItemInput) ([]string, error) {
+	if err := resolveItemIds(inputs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(inputs))
+	for i := range inputs {
+		ids[i] = inputs[i].Id
+	}
+	return ids, nil
+}
+
+// NewItems resolves the inputs into list items. TMDB ids are converted to
+// IMDb ids when the mapping is known, and IMDb titles are used to fill in
+// missing name, year and type.
+func NewItems(inputs []ItemInput) ([]StremThruListItem, error) {
+	items := make([]StremThruListItem, 0, len(inputs))
+	seen := map[string]struct{}{}
+
+	if err := resolveItemIds(inputs); err != nil {
+		return nil, err
+	}
+
+	imdbIds := []string{}
+	for i := range inputs {
+		if strings.HasPrefix(inputs[i].Id, "tt") {
+			imdbIds = append(imdbIds, inputs[i].Id)
+		}
+	}
+
+	titleById := map[string]imdb_title.IMDBTitle{}
+	if len(imdbIds) > 0 {
+		titles, err := listIMDBTitlesByIds(imdbIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, title := range titles {
+			titleById[title.TId] = title
+		}
+	}
+
+	for i := range inputs {
+		input := &inputs[i]
+		if _, ok := seen[input.Id]; ok {
+			continue
+		}
+		seen[input.Id] = struct{}{}
+
+		item := StremThruListItem{
+			Id:     input.Id,
+			Type:   input.Type,
+			Name:   strings.TrimSpace(input.Name),
+			Poster: strings.TrimSpace(input.Poster),
+		}
+		if title, ok := titleById[item.Id]; ok {
+			if item.Name == "" {
+				item.Name = title.Title
+			}
+			item.Year = title.Year
+			switch imdb_title.IMDBTitleType(title.Type).ToSimple() {
+			case imdb_title.IMDBTitleSimpleTypeMovie:
+				item.Type = ItemTypeMovie
+			case imdb_title.IMDBTitleSimpleTypeShow:
+				item.Type = ItemTypeSeries
+			}
+		}
+		if item.Type == "" {
+			if IsAnimeItemId(item.Id) {
+				item.Type = ItemTypeSeries
+			} else {
+				return nil, errors.New("missing type for id: " + item.Id)
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,49 @@
+package stremthru_list
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveItemIds(t *testing.T) {
+	defaultGetIMDBIdByTMDBId, defaultListIMDBTitlesByIds := getIMDBIdByTMDBId, listIMDBTitlesByIds
+	defer func() {
+		getIMDBIdByTMDBId, listIMDBTitlesByIds = defaultGetIMDBIdByTMDBId, defaultListIMDBTitlesByIds
+	}()
+	getIMDBIdByTMDBId = func(tmdbMovieIds, tmdbShowIds []string) (map[string]string, map[string]string, error) {
+		return map[string]string{"603": "tt0133093"}, map[string]string{}, nil
+	}
+	listIMDBTitlesByIds = func(tids []string) ([]imdb_title.IMDBTitle, error) {
+		return []imdb_title.IMDBTitle{{TId: "tt0133093", Title: "The Matrix", Year: 1999, Type: string(imdb_title.IMDBTitleTypeMovie)}}, nil
+	}
+
+	items, err := NewItems([]ItemInput{{Id: "tmdb:603", Type: ItemTypeMovie}})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "tt0133093", items[0].Id)
+
+	for _, tc := range []struct {
+		name   string
+		inputs []ItemInput
+		ids    []string
+		err    bool
+	}{
+		{"tmdb id", []ItemInput{{Id: "tmdb:603", Type: ItemTypeMovie}}, []string{items[0].Id}, false},
+		{"imdb id", []ItemInput{{Id: "tt0133093:1:2"}}, []string{items[0].Id}, false},
+		{"unmapped tmdb id", []ItemInput{{Id: "tmdb:1396", Type: ItemTypeSeries}}, []string{"tmdb:1396"}, false},
+		{"tmdb id without type", []ItemInput{{Id: "tmdb:603"}}, nil, true},
+		{"unsupported id", []ItemInput{{Id: "foo:1"}}, nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ids, err := ResolveItemIds(tc.inputs)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ids, ids)
+		})
+	}
+}
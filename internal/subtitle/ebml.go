@@ -0,0 +1,206 @@
+package subtitle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	idEBML    uint32 = 0x1A45DFA3
+	idDocType uint32 = 0x4282
+
+	idSegment uint32 = 0x18538067
+
+	idSeekHead     uint32 = 0x114D9B74
+	idSeek         uint32 = 0x4DBB
+	idSeekID       uint32 = 0x53AB
+	idSeekPosition uint32 = 0x53AC
+
+	idInfo           uint32 = 0x1549A966
+	idTimestampScale uint32 = 0x2AD7B1
+
+	idTracks              uint32 = 0x1654AE6B
+	idTrackEntry          uint32 = 0xAE
+	idTrackNumber         uint32 = 0xD7
+	idTrackType           uint32 = 0x83
+	idCodecID             uint32 = 0x86
+	idCodecPrivate        uint32 = 0x63A2
+	idLanguage            uint32 = 0x22B59C
+	idName                uint32 = 0x536E
+	idFlagDefault         uint32 = 0x88
+	idFlagForced          uint32 = 0x55AA
+	idFlagHearingImpaired uint32 = 0x55AB
+	idContentEncodings    uint32 = 0x6D80
+	idContentEncoding     uint32 = 0x6240
+	idContentEncodingType uint32 = 0x5033
+	idContentCompression  uint32 = 0x5034
+	idContentCompAlgo     uint32 = 0x4254
+	idContentCompSettings uint32 = 0x4255
+
+	idCues                uint32 = 0x1C53BB6B
+	idCuePoint            uint32 = 0xBB
+	idCueTime             uint32 = 0xB3
+	idCueTrackPositions   uint32 = 0xB7
+	idCueTrack            uint32 = 0xF7
+	idCueClusterPosition  uint32 = 0xF1
+	idCueRelativePosition uint32 = 0xF0
+	idCueDuration         uint32 = 0xB2
+
+	idCluster       uint32 = 0x1F43B675
+	idSimpleBlock   uint32 = 0xA3
+	idBlockGroup    uint32 = 0xA0
+	idBlock         uint32 = 0xA1
+	idBlockDuration uint32 = 0x9B
+)
+
+const (
+	maxElementHeaderSize = 12
+	maxMasterElementSize = 64 * 1024 * 1024
+	maxBlockElementSize  = 1 * 1024 * 1024
+)
+
+var errInvalidVint = errors.New("invalid ebml variable size integer")
+
+type elementHeader struct {
+	id         uint32
+	size       int64 // -1 when unknown
+	dataOffset int64
+}
+
+func (h *elementHeader) end() int64 {
+	return h.dataOffset + h.size
+}
+
+// parseVint decodes an EBML variable size integer. The length marker is kept
+// for element ids and stripped for element sizes.
+func parseVint(b []byte, keepMarker bool) (value uint64, length int, err error) {
+	if len(b) == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	first := b[0]
+	if first == 0 {
+		return 0, 0, errInvalidVint
+	}
+	length = 1
+	for mask := byte(0x80); first&mask == 0; mask >>= 1 {
+		length++
+	}
+	if len(b) < length {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	value = uint64(first)
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(b[i])
+	}
+	return value, length, nil
+}
+
+func isUnknownSize(value uint64, length int) bool {
+	return value == 1<<(7*length)-1
+}
+
+func parseElementHeader(b []byte, offset int64) (*elementHeader, int, error) {
+	id, idLen, err := parseVint(b, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	if idLen > 4 {
+		return nil, 0, errInvalidVint
+	}
+	size, sizeLen, err := parseVint(b[idLen:], false)
+	if err != nil {
+		return nil, 0, err
+	}
+	headerLen := idLen + sizeLen
+	h := &elementHeader{
+		id:         uint32(id),
+		size:       int64(size),
+		dataOffset: offset + int64(headerLen),
+	}
+	if isUnknownSize(size, sizeLen) {
+		h.size = -1
+	}
+	return h, headerLen, nil
+}
+
+func readElementHeader(r io.ReaderAt, offset int64) (*elementHeader, error) {
+	var buf [maxElementHeaderSize]byte
+	n, err := r.ReadAt(buf[:], offset)
+	if n == 0 {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	h, _, err := parseElementHeader(buf[:n], offset)
+	return h, err
+}
+
+func readElementData(r io.ReaderAt, h *elementHeader, maxSize int64) ([]byte, error) {
+	if h.size < 0 {
+		return nil, fmt.Errorf("element 0x%X has unknown size", h.id)
+	}
+	if h.size > maxSize {
+		return nil, fmt.Errorf("element 0x%X too large: %d", h.id, h.size)
+	}
+	data := make([]byte, h.size)
+	if _, err := r.ReadAt(data, h.dataOffset); err != nil && !(err == io.EOF && h.size > 0) {
+		return nil, err
+	}
+	return data, nil
+}
+
+// elementIterator walks the children of a master element held in memory.
+type elementIterator struct {
+	b   []byte
+	pos int
+	err error
+
+	id   uint32
+	data []byte
+}
+
+func newElementIterator(b []byte) *elementIterator {
+	return &elementIterator{b: b}
+}
+
+func (it *elementIterator) next() bool {
+	if it.err != nil || it.pos >= len(it.b) {
+		return false
+	}
+	h, headerLen, err := parseElementHeader(it.b[it.pos:], 0)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	start := it.pos + headerLen
+	end := len(it.b)
+	if h.size >= 0 && int64(start)+h.size <= int64(len(it.b)) {
+		end = start + int(h.size)
+	}
+	it.id = h.id
+	it.data = it.b[start:end]
+	it.pos = end
+	return true
+}
+
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func readString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
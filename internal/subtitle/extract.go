@@ -0,0 +1,155 @@
+package subtitle
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const extractConcurrency = 8
+
+const defaultCueDuration = 2 * time.Second
+
+type block struct {
+	track    int
+	payload  []byte
+	duration uint64
+	laced    bool
+}
+
+func parseBlock(data []byte) (*block, error) {
+	track, n, err := parseVint(data, false)
+	if err != nil {
+		return nil, err
+	}
+	// timestamp (int16) and flags
+	if len(data) < n+3 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	flags := data[n+2]
+	return &block{
+		track:   int(track),
+		payload: data[n+3:],
+		laced:   flags&0x06 != 0,
+	}, nil
+}
+
+func readBlock(r io.ReaderAt, offset int64) (*block, error) {
+	h, err := readElementHeader(r, offset)
+	if err != nil {
+		return nil, err
+	}
+	switch h.id {
+	case idSimpleBlock:
+		data, err := readElementData(r, h, maxBlockElementSize)
+		if err != nil {
+			return nil, err
+		}
+		return parseBlock(data)
+	case idBlockGroup:
+		data, err := readElementData(r, h, maxBlockElementSize)
+		if err != nil {
+			return nil, err
+		}
+		var b *block
+		duration := uint64(0)
+		for it := newElementIterator(data); it.next(); {
+			switch it.id {
+			case idBlock:
+				if b, err = parseBlock(it.data); err != nil {
+					return nil, err
+				}
+			case idBlockDuration:
+				duration = readUint(it.data)
+			}
+		}
+		if b == nil {
+			return nil, fmt.Errorf("missing block in block group at %d", offset)
+		}
+		b.duration = duration
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unexpected element 0x%X at %d", h.id, offset)
+	}
+}
+
+func (idx *Index) extractCue(r io.ReaderAt, t *Track, cp cuePoint) (*Cue, error) {
+	cluster, err := readElementHeader(r, idx.segmentOffset+int64(cp.cluster))
+	if err != nil {
+		return nil, err
+	}
+	if cluster.id != idCluster {
+		return nil, fmt.Errorf("unexpected element 0x%X at cluster position %d", cluster.id, cp.cluster)
+	}
+	b, err := readBlock(r, cluster.dataOffset+int64(cp.relative))
+	if err != nil {
+		return nil, err
+	}
+	if b.track != t.Number || b.laced {
+		return nil, nil
+	}
+	payload, err := t.decode(b.payload)
+	if err != nil {
+		return nil, err
+	}
+	scale := time.Duration(idx.timestampScale)
+	cue := &Cue{
+		Start: time.Duration(cp.time) * scale,
+		Text:  cueText(t.Codec, payload),
+	}
+	duration := b.duration
+	if duration == 0 {
+		duration = cp.duration
+	}
+	if duration > 0 {
+		cue.End = cue.Start + time.Duration(duration)*scale
+	} else {
+		cue.End = cue.Start + defaultCueDuration
+	}
+	return cue, nil
+}
+
+// Extract reads the blocks referenced by the cues of every track in the
+// index and fills their Cues.
+func (idx *Index) Extract(ctx context.Context, r io.ReaderAt) error {
+	for i := range idx.Tracks {
+		t := &idx.Tracks[i]
+
+		cues := make([]*Cue, len(t.cuePoints))
+
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(extractConcurrency)
+		for j, cp := range t.cuePoints {
+			g.Go(func() error {
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+				cue, err := idx.extractCue(r, t, cp)
+				if err != nil {
+					return fmt.Errorf("failed to extract cue for track %d at %d: %w", t.Number, cp.time, err)
+				}
+				cues[j] = cue
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+
+		t.Cues = make([]Cue, 0, len(cues))
+		for _, cue := range cues {
+			if cue != nil && cue.Text != "" {
+				t.Cues = append(t.Cues, *cue)
+			}
+		}
+		slices.SortStableFunc(t.Cues, func(a, b Cue) int {
+			return cmp.Compare(a.Start, b.Start)
+		})
+	}
+	return nil
+}
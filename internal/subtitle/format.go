@@ -0,0 +1,133 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatSRT Format = "srt"
+	FormatVTT Format = "vtt"
+)
+
+func (f Format) IsValid() bool {
+	return f == FormatSRT || f == FormatVTT
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatVTT:
+		return "text/vtt; charset=utf-8"
+	default:
+		return "application/x-subrip; charset=utf-8"
+	}
+}
+
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+var assOverrideTagRegex = regexp.MustCompile(`\{[^}]*\}`)
+
+func convertASSOverrideTag(tag string) string {
+	out := ""
+	for t := range strings.SplitSeq(strings.Trim(tag, "{}"), `\`) {
+		switch t {
+		case "i1":
+			out += "<i>"
+		case "i0":
+			out += "</i>"
+		case "b1":
+			out += "<b>"
+		case "b0":
+			out += "</b>"
+		case "u1":
+			out += "<u>"
+		case "u0":
+			out += "</u>"
+		}
+	}
+	return out
+}
+
+// parseASSText extracts the text from a Matroska ASS/SSA block, which stores
+// the Dialogue line without its Start and End fields:
+// ReadOrder, Layer, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+func parseASSText(payload string) string {
+	fields := strings.SplitN(payload, ",", 9)
+	if len(fields) < 9 {
+		return ""
+	}
+	text := fields[8]
+	if strings.Contains(text, `\p1`) {
+		// vector drawing
+		return ""
+	}
+	text = assOverrideTagRegex.ReplaceAllStringFunc(text, convertASSOverrideTag)
+	text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+	return text
+}
+
+func cueText(codec CodecId, payload []byte) string {
+	text := string(payload)
+	switch codec {
+	case CodecIdASS, CodecIdSSA:
+		text = parseASSText(text)
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.TrimSpace(text)
+}
+
+func formatTimestamp(d time.Duration, fractionSep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, fractionSep, ms%1000)
+}
+
+// blank lines end a cue in both SRT and WebVTT
+func sanitizeCueText(text string) string {
+	lines := slices.DeleteFunc(strings.Split(text, "\n"), func(line string) bool {
+		return strings.TrimSpace(line) == ""
+	})
+	return strings.Join(lines, "\n")
+}
+
+func (t *Track) Render(format Format) []byte {
+	var buf bytes.Buffer
+	fractionSep := byte(',')
+	if format == FormatVTT {
+		fractionSep = '.'
+		buf.WriteString("WEBVTT\n\n")
+	}
+	n := 0
+	for i := range t.Cues {
+		cue := &t.Cues[i]
+		text := sanitizeCueText(cue.Text)
+		if text == "" {
+			continue
+		}
+		n++
+		if format == FormatSRT {
+			buf.WriteString(strconv.Itoa(n))
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(formatTimestamp(cue.Start, fractionSep))
+		buf.WriteString(" --> ")
+		buf.WriteString(formatTimestamp(cue.End, fractionSep))
+		buf.WriteByte('\n')
+		buf.WriteString(text)
+		buf.WriteString("\n\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,5 @@
+package subtitle
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("subtitle")
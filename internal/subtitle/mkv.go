@@ -0,0 +1,381 @@
+package subtitle
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+var (
+	ErrUnsupportedContainer = errors.New("unsupported container")
+	ErrNoCues               = errors.New("missing cues")
+)
+
+const (
+	trackTypeSubtitle = 0x11
+
+	compAlgoZlib            = 0
+	compAlgoHeaderStripping = 3
+)
+
+type CodecId string
+
+const (
+	CodecIdUTF8   CodecId = "S_TEXT/UTF8"
+	CodecIdASS    CodecId = "S_TEXT/ASS"
+	CodecIdSSA    CodecId = "S_TEXT/SSA"
+	CodecIdWebVTT CodecId = "S_TEXT/WEBVTT"
+)
+
+func (c CodecId) IsText() bool {
+	switch c {
+	case CodecIdUTF8, CodecIdASS, CodecIdSSA, CodecIdWebVTT:
+		return true
+	default:
+		return false
+	}
+}
+
+type cuePoint struct {
+	time     uint64
+	cluster  uint64
+	relative uint64
+	duration uint64
+}
+
+type Track struct {
+	Number int
+	// position among all the subtitle tracks, same as ffprobe's stream order
+	Index           int
+	Codec           CodecId
+	Language        string
+	Name            string
+	Default         bool
+	Forced          bool
+	HearingImpaired bool
+	Cues            []Cue
+
+	compAlgo     int
+	compSettings []byte
+	encrypted    bool
+	cuePoints    []cuePoint
+}
+
+func (t *Track) decode(payload []byte) ([]byte, error) {
+	switch t.compAlgo {
+	case -1:
+		return payload, nil
+	case compAlgoHeaderStripping:
+		return append(slices.Clone(t.compSettings), payload...), nil
+	case compAlgoZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm: %d", t.compAlgo)
+	}
+}
+
+type Index struct {
+	Tracks []Track
+
+	segmentOffset  int64
+	timestampScale uint64
+}
+
+// Probe reads the headers of a Matroska file and returns the text subtitle
+// tracks that can be extracted using the cues. Tracks without cue points,
+// common in ffmpeg-muxed files, are skipped: finding their blocks needs a
+// scan of every cluster, i.e. reading the whole file.
+func Probe(r io.ReaderAt) (*Index, error) {
+	header, err := readElementHeader(r, 0)
+	if err != nil {
+		if errors.Is(err, errInvalidVint) {
+			return nil, ErrUnsupportedContainer
+		}
+		return nil, err
+	}
+	if header.id != idEBML {
+		return nil, ErrUnsupportedContainer
+	}
+	data, err := readElementData(r, header, 4096)
+	if err != nil {
+		return nil, err
+	}
+	docType := ""
+	for it := newElementIterator(data); it.next(); {
+		if it.id == idDocType {
+			docType = readString(it.data)
+		}
+	}
+	if docType != "matroska" && docType != "webm" {
+		return nil, ErrUnsupportedContainer
+	}
+
+	segment, err := readElementHeader(r, header.end())
+	if err != nil {
+		return nil, err
+	}
+	if segment.id != idSegment {
+		return nil, ErrUnsupportedContainer
+	}
+
+	idx := &Index{
+		segmentOffset:  segment.dataOffset,
+		timestampScale: 1000000,
+	}
+
+	var tracks, cues []byte
+	seekPositions := map[uint32]int64{}
+
+	readMaster := func(h *elementHeader) ([]byte, error) {
+		return readElementData(r, h, maxMasterElementSize)
+	}
+
+	offset := segment.dataOffset
+	for {
+		h, err := readElementHeader(r, offset)
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, err
+		}
+		if h.id == idCluster || h.size < 0 {
+			break
+		}
+		switch h.id {
+		case idSeekHead:
+			data, err := readMaster(h)
+			if err != nil {
+				return nil, err
+			}
+			parseSeekHead(data, seekPositions)
+		case idInfo:
+			data, err := readMaster(h)
+			if err != nil {
+				return nil, err
+			}
+			idx.parseInfo(data)
+		case idTracks:
+			if tracks, err = readMaster(h); err != nil {
+				return nil, err
+			}
+		case idCues:
+			if cues, err = readMaster(h); err != nil {
+				return nil, err
+			}
+		}
+		offset = h.end()
+	}
+
+	readSeeked := func(id uint32) ([]byte, error) {
+		pos, ok := seekPositions[id]
+		if !ok {
+			return nil, nil
+		}
+		h, err := readElementHeader(r, idx.segmentOffset+pos)
+		if err != nil {
+			return nil, err
+		}
+		if h.id != id {
+			return nil, fmt.Errorf("unexpected element 0x%X at seek position for 0x%X", h.id, id)
+		}
+		return readMaster(h)
+	}
+
+	if tracks == nil {
+		if tracks, err = readSeeked(idTracks); err != nil {
+			return nil, err
+		}
+	}
+	if tracks == nil {
+		return nil, errors.New("missing tracks")
+	}
+	idx.parseTracks(tracks)
+
+	if len(idx.Tracks) == 0 {
+		return idx, nil
+	}
+
+	if cues == nil {
+		if cues, err = readSeeked(idCues); err != nil {
+			return nil, err
+		}
+	}
+	if cues == nil {
+		return nil, ErrNoCues
+	}
+	idx.parseCues(cues)
+
+	idx.Tracks = slices.DeleteFunc(idx.Tracks, func(t Track) bool {
+		if len(t.cuePoints) > 0 {
+			return false
+		}
+		log.Warn("skipping subtitle track without cues", "track", t.Number, "codec", t.Codec, "language", t.Language)
+		return true
+	})
+
+	return idx, nil
+}
+
+func parseSeekHead(data []byte, positions map[uint32]int64) {
+	for it := newElementIterator(data); it.next(); {
+		if it.id != idSeek {
+			continue
+		}
+		var id uint32
+		pos := int64(-1)
+		for sit := newElementIterator(it.data); sit.next(); {
+			switch sit.id {
+			case idSeekID:
+				id = uint32(readUint(sit.data))
+			case idSeekPosition:
+				pos = int64(readUint(sit.data))
+			}
+		}
+		if _, seen := positions[id]; id != 0 && pos >= 0 && !seen {
+			positions[id] = pos
+		}
+	}
+}
+
+func (idx *Index) parseInfo(data []byte) {
+	for it := newElementIterator(data); it.next(); {
+		if it.id == idTimestampScale {
+			if scale := readUint(it.data); scale > 0 {
+				idx.timestampScale = scale
+			}
+		}
+	}
+}
+
+func (idx *Index) parseTracks(data []byte) {
+	subtitleIndex := 0
+	for it := newElementIterator(data); it.next(); {
+		if it.id != idTrackEntry {
+			continue
+		}
+		t := Track{
+			Language: "eng",
+			Default:  true,
+			compAlgo: -1,
+		}
+		trackType := uint64(0)
+		for tit := newElementIterator(it.data); tit.next(); {
+			switch tit.id {
+			case idTrackNumber:
+				t.Number = int(readUint(tit.data))
+			case idTrackType:
+				trackType = readUint(tit.data)
+			case idCodecID:
+				t.Codec = CodecId(readString(tit.data))
+			case idLanguage:
+				t.Language = readString(tit.data)
+			case idName:
+				t.Name = readString(tit.data)
+			case idFlagDefault:
+				t.Default = readUint(tit.data) == 1
+			case idFlagForced:
+				t.Forced = readUint(tit.data) == 1
+			case idFlagHearingImpaired:
+				t.HearingImpaired = readUint(tit.data) == 1
+			case idContentEncodings:
+				t.parseContentEncodings(tit.data)
+			}
+		}
+		if trackType != trackTypeSubtitle {
+			continue
+		}
+		t.Index = subtitleIndex
+		subtitleIndex++
+		if t.Codec.IsText() && !t.encrypted {
+			idx.Tracks = append(idx.Tracks, t)
+		}
+	}
+}
+
+func (t *Track) parseContentEncodings(data []byte) {
+	for it := newElementIterator(data); it.next(); {
+		if it.id != idContentEncoding {
+			continue
+		}
+		encodingType := uint64(0)
+		compAlgo := compAlgoZlib
+		var compSettings []byte
+		for eit := newElementIterator(it.data); eit.next(); {
+			switch eit.id {
+			case idContentEncodingType:
+				encodingType = readUint(eit.data)
+			case idContentCompression:
+				for cit := newElementIterator(eit.data); cit.next(); {
+					switch cit.id {
+					case idContentCompAlgo:
+						compAlgo = int(readUint(cit.data))
+					case idContentCompSettings:
+						compSettings = slices.Clone(cit.data)
+					}
+				}
+			}
+		}
+		if encodingType != 0 {
+			t.encrypted = true
+			continue
+		}
+		t.compAlgo = compAlgo
+		t.compSettings = compSettings
+	}
+}
+
+func (idx *Index) parseCues(data []byte) {
+	trackByNumber := map[int]*Track{}
+	for i := range idx.Tracks {
+		trackByNumber[idx.Tracks[i].Number] = &idx.Tracks[i]
+	}
+	for it := newElementIterator(data); it.next(); {
+		if it.id != idCuePoint {
+			continue
+		}
+		cueTime := uint64(0)
+		positions := []cuePoint{}
+		tracks := []int{}
+		for cit := newElementIterator(it.data); cit.next(); {
+			switch cit.id {
+			case idCueTime:
+				cueTime = readUint(cit.data)
+			case idCueTrackPositions:
+				cp := cuePoint{}
+				track := 0
+				hasRelative := false
+				for pit := newElementIterator(cit.data); pit.next(); {
+					switch pit.id {
+					case idCueTrack:
+						track = int(readUint(pit.data))
+					case idCueClusterPosition:
+						cp.cluster = readUint(pit.data)
+					case idCueRelativePosition:
+						cp.relative = readUint(pit.data)
+						hasRelative = true
+					case idCueDuration:
+						cp.duration = readUint(pit.data)
+					}
+				}
+				if _, ok := trackByNumber[track]; ok && hasRelative {
+					positions = append(positions, cp)
+					tracks = append(tracks, track)
+				}
+			}
+		}
+		for i, cp := range positions {
+			cp.time = cueTime
+			t := trackByNumber[tracks[i]]
+			t.cuePoints = append(t.cuePoints, cp)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package subtitle
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const idClusterTimestamp uint32 = 0xE7
+
+func ebmlElement(id uint32, children ...[]byte) []byte {
+	data := bytes.Join(children, nil)
+	var idBytes [4]byte
+	binary.BigEndian.PutUint32(idBytes[:], id)
+	out := bytes.TrimLeft(idBytes[:], "\x00")
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(data)))
+	size[0] = 0x01
+	return append(append(append([]byte{}, out...), size[:]...), data...)
+}
+
+func ebmlUint(id uint32, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return ebmlElement(id, b[:])
+}
+
+func ebmlString(id uint32, v string) []byte {
+	return ebmlElement(id, []byte(v))
+}
+
+func mkvBlock(track int, timestamp int16, payload string) []byte {
+	var tc [2]byte
+	binary.BigEndian.PutUint16(tc[:], uint16(timestamp))
+	return append([]byte{0x80 | byte(track), tc[0], tc[1], 0x80}, payload...)
+}
+
+func zlibCompress(t *testing.T, s string) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func buildTestMKV(t *testing.T) []byte {
+	info := ebmlElement(idInfo, ebmlUint(idTimestampScale, 1000000))
+	tracks := ebmlElement(idTracks,
+		ebmlElement(idTrackEntry,
+			ebmlUint(idTrackNumber, 1),
+			ebmlUint(idTrackType, 1),
+			ebmlString(idCodecID, "V_MPEG4/ISO/AVC"),
+		),
+		ebmlElement(idTrackEntry,
+			ebmlUint(idTrackNumber, 2),
+			ebmlUint(idTrackType, trackTypeSubtitle),
+			ebmlString(idCodecID, string(CodecIdUTF8)),
+		),
+		ebmlElement(idTrackEntry,
+			ebmlUint(idTrackNumber, 3),
+			ebmlUint(idTrackType, trackTypeSubtitle),
+			ebmlString(idCodecID, "S_HDMV/PGS"),
+		),
+		ebmlElement(idTrackEntry,
+			ebmlUint(idTrackNumber, 4),
+			ebmlUint(idTrackType, trackTypeSubtitle),
+			ebmlString(idCodecID, string(CodecIdASS)),
+			ebmlString(idLanguage, "fre"),
+			ebmlString(idName, "French"),
+			ebmlUint(idFlagDefault, 0),
+			ebmlElement(idContentEncodings,
+				ebmlElement(idContentEncoding,
+					ebmlElement(idContentCompression,
+						ebmlUint(idContentCompAlgo, compAlgoZlib),
+					),
+				),
+			),
+		),
+	)
+
+	videoBlock := ebmlElement(idSimpleBlock, mkvBlock(1, 0, "video frame"))
+	textBlock := ebmlElement(idBlockGroup,
+		ebmlElement(idBlock, mkvBlock(2, 1000, "Hello\r\n\r\nworld")),
+		ebmlUint(idBlockDuration, 1500),
+	)
+	assBlock := ebmlElement(idSimpleBlock, mkvBlock(4, 2000, zlibCompress(t, `0,0,Default,,0,0,0,,{\i1}Bonjour{\i0}\Nle {\pos(1,2)}monde`)))
+	clusterTimestamp := ebmlUint(idClusterTimestamp, 0)
+	cluster := ebmlElement(idCluster, clusterTimestamp, videoBlock, textBlock, assBlock)
+
+	textBlockRelative := uint64(len(clusterTimestamp) + len(videoBlock))
+	assBlockRelative := textBlockRelative + uint64(len(textBlock))
+
+	seekHeadSize := len(ebmlElement(idSeekHead, ebmlElement(idSeek, ebmlUint(idSeekID, uint64(idCues)), ebmlUint(idSeekPosition, 0))))
+	clusterPosition := uint64(seekHeadSize + len(info) + len(tracks))
+	cuesPosition := clusterPosition + uint64(len(cluster))
+
+	seekHead := ebmlElement(idSeekHead, ebmlElement(idSeek, ebmlUint(idSeekID, uint64(idCues)), ebmlUint(idSeekPosition, cuesPosition)))
+	cues := ebmlElement(idCues,
+		ebmlElement(idCuePoint,
+			ebmlUint(idCueTime, 0),
+			ebmlElement(idCueTrackPositions, ebmlUint(idCueTrack, 1), ebmlUint(idCueClusterPosition, clusterPosition)),
+		),
+		ebmlElement(idCuePoint,
+			ebmlUint(idCueTime, 2000),
+			ebmlElement(idCueTrackPositions, ebmlUint(idCueTrack, 4), ebmlUint(idCueClusterPosition, clusterPosition), ebmlUint(idCueRelativePosition, assBlockRelative), ebmlUint(idCueDuration, 500)),
+		),
+		ebmlElement(idCuePoint,
+			ebmlUint(idCueTime, 1000),
+			ebmlElement(idCueTrackPositions, ebmlUint(idCueTrack, 2), ebmlUint(idCueClusterPosition, clusterPosition), ebmlUint(idCueRelativePosition, textBlockRelative)),
+		),
+	)
+
+	return bytes.Join([][]byte{
+		ebmlElement(idEBML, ebmlString(idDocType, "matroska")),
+		ebmlElement(idSegment, seekHead, info, tracks, cluster, cues),
+	}, nil)
+}
+
+func TestProbeAndExtract(t *testing.T) {
+	r := bytes.NewReader(buildTestMKV(t))
+
+	idx, err := Probe(r)
+	require.NoError(t, err)
+	require.Len(t, idx.Tracks, 2)
+
+	assert.Equal(t, 2, idx.Tracks[0].Number)
+	assert.Equal(t, 0, idx.Tracks[0].Index)
+	assert.Equal(t, "eng", idx.Tracks[0].Language)
+	assert.True(t, idx.Tracks[0].Default)
+
+	assert.Equal(t, 4, idx.Tracks[1].Number)
+	assert.Equal(t, 2, idx.Tracks[1].Index)
+	assert.Equal(t, "fre", idx.Tracks[1].Language)
+	assert.Equal(t, "French", idx.Tracks[1].Name)
+	assert.False(t, idx.Tracks[1].Default)
+
+	require.NoError(t, idx.Extract(context.Background(), r))
+
+	assert.Equal(t, []Cue{
+		{Start: 1 * time.Second, End: 2500 * time.Millisecond, Text: "Hello\n\nworld"},
+	}, idx.Tracks[0].Cues)
+	assert.Equal(t, []Cue{
+		{Start: 2 * time.Second, End: 2500 * time.Millisecond, Text: "<i>Bonjour</i>\nle monde"},
+	}, idx.Tracks[1].Cues)
+
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,500\nHello\nworld\n\n", string(idx.Tracks[0].Render(FormatSRT)))
+	assert.Equal(t, "WEBVTT\n\n00:00:02.000 --> 00:00:02.500\n<i>Bonjour</i>\nle monde\n\n", string(idx.Tracks[1].Render(FormatVTT)))
+}
+
+func TestProbeUnsupportedContainer(t *testing.T) {
+	_, err := Probe(bytes.NewReader([]byte("\x00\x00\x00\x20ftypisom")))
+	assert.ErrorIs(t, err, ErrUnsupportedContainer)
+}
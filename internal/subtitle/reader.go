@@ -0,0 +1,172 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+)
+
+type Reader interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+const httpReaderChunkSize = 64 * 1024
+
+// httpReader reads a remote file with range requests, keeping recently read
+// chunks around since cluster headers and the blocks that follow them are
+// usually close to each other.
+type httpReader struct {
+	ctx    context.Context
+	client *http.Client
+	link   string
+	size   int64
+	chunks *cache.LRUCache[[]byte]
+}
+
+func NewHTTPReader(ctx context.Context, client *http.Client, link string) (Reader, error) {
+	r := &httpReader{
+		ctx:    ctx,
+		client: client,
+		link:   link,
+		chunks: cache.NewLRUCache[[]byte](&cache.CacheConfig{
+			Name:    "subtitle:http-reader:chunk",
+			MaxSize: 256,
+		}),
+	}
+	res, err := r.request(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	_, total, ok := strings.Cut(res.Header.Get("Content-Range"), "/")
+	if !ok {
+		return nil, errors.New("range requests not supported")
+	}
+	if r.size, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid content range: %w", err)
+	}
+	return r, nil
+}
+
+func (r *httpReader) request(start, end int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10))
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusPartialContent {
+		res.Body.Close()
+		return nil, fmt.Errorf("unexpected status for range request: %d", res.StatusCode)
+	}
+	return res, nil
+}
+
+func (r *httpReader) readRange(p []byte, off int64) (int, error) {
+	res, err := r.request(off, off+int64(len(p))-1)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return io.ReadFull(res.Body, p)
+}
+
+func (r *httpReader) getChunk(idx int64) ([]byte, error) {
+	key := strconv.FormatInt(idx, 10)
+	var chunk []byte
+	if r.chunks.Get(key, &chunk) {
+		return chunk, nil
+	}
+	start := idx * httpReaderChunkSize
+	chunk = make([]byte, min(httpReaderChunkSize, r.size-start))
+	if _, err := r.readRange(chunk, start); err != nil {
+		return nil, err
+	}
+	r.chunks.Add(key, chunk)
+	return chunk, nil
+}
+
+func (r *httpReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	var err error
+	if remaining := r.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		err = io.EOF
+	}
+	if len(p) > httpReaderChunkSize {
+		n, rerr := r.readRange(p, off)
+		if rerr != nil {
+			return n, rerr
+		}
+		return n, err
+	}
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		chunk, cerr := r.getChunk(pos / httpReaderChunkSize)
+		if cerr != nil {
+			return n, cerr
+		}
+		n += copy(p[n:], chunk[pos%httpReaderChunkSize:])
+	}
+	return n, err
+}
+
+func (r *httpReader) Size() int64 {
+	return r.size
+}
+
+func (r *httpReader) Close() error {
+	return nil
+}
+
+type readSeekerReader struct {
+	rs   io.ReadSeekCloser
+	size int64
+	m    sync.Mutex
+}
+
+// NewReadSeekerReader adapts a seekable stream, e.g. a usenet stream, for
+// random access.
+func NewReadSeekerReader(rs io.ReadSeekCloser, size int64) Reader {
+	return &readSeekerReader{rs: rs, size: size}
+}
+
+func (r *readSeekerReader) ReadAt(p []byte, off int64) (int, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (r *readSeekerReader) Size() int64 {
+	return r.size
+}
+
+func (r *readSeekerReader) Close() error {
+	return r.rs.Close()
+}
@@ -0,0 +1,162 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+const extractTimeout = 5 * time.Minute
+
+type Source struct {
+	// identifies the file, extracted tracks are cached by it
+	Hash string
+	Open func(ctx context.Context) (Reader, error)
+}
+
+type File struct {
+	Tracks []Track
+}
+
+func (f File) CacheSize() int64 {
+	size := int64(0)
+	for i := range f.Tracks {
+		t := &f.Tracks[i]
+		size += int64(len(t.Language) + len(t.Name) + 64)
+		for j := range t.Cues {
+			size += int64(len(t.Cues[j].Text) + 16)
+		}
+	}
+	return size
+}
+
+func (f *File) GetTrack(number int) *Track {
+	for i := range f.Tracks {
+		if f.Tracks[i].Number == number {
+			return &f.Tracks[i]
+		}
+	}
+	return nil
+}
+
+var indexCache = cache.NewLRUCache[*Index](&cache.CacheConfig{
+	Name:     "subtitle:index",
+	Lifetime: 6 * time.Hour,
+	MaxSize:  1024,
+})
+
+var fileCache = cache.NewCache[File](&cache.CacheConfig{
+	Name:       "subtitle_file",
+	Lifetime:   7 * 24 * time.Hour,
+	DiskBacked: true,
+	MaxSize:    256 * 1024 * 1024,
+})
+
+var indexSG, fileSG singleflight.Group
+
+func getIndex(ctx context.Context, src *Source) (*Index, error) {
+	idx := &Index{}
+	if indexCache.Get(src.Hash, &idx) {
+		return idx, nil
+	}
+
+	result, err, _ := indexSG.Do(src.Hash, func() (any, error) {
+		r, err := src.Open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		idx, err := Probe(r)
+		if err != nil {
+			if !errors.Is(err, ErrUnsupportedContainer) && !errors.Is(err, ErrNoCues) {
+				return nil, err
+			}
+			log.Debug("no extractable subtitles", "hash", src.Hash, "error", err)
+			idx = &Index{}
+		}
+		indexCache.Add(src.Hash, idx)
+		return idx, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Index), nil
+}
+
+// GetTracks returns the text subtitle tracks of the file, without cues
+// unless they were already extracted.
+func GetTracks(ctx context.Context, src *Source) ([]Track, error) {
+	file := File{}
+	if fileCache.Get(src.Hash, &file) {
+		return file.Tracks, nil
+	}
+	idx, err := getIndex(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Tracks, nil
+}
+
+func IsExtracted(hash string) bool {
+	return fileCache.Has(hash)
+}
+
+func GetCachedFile(hash string) *File {
+	file := File{}
+	if fileCache.Get(hash, &file) {
+		return &file
+	}
+	return nil
+}
+
+// GetFile extracts the text subtitle tracks of the file. The extraction
+// keeps going even if the caller goes away, so that it can be cached.
+func GetFile(src *Source) (*File, error) {
+	if file := GetCachedFile(src.Hash); file != nil {
+		return file, nil
+	}
+
+	result, err, _ := fileSG.Do(src.Hash, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), extractTimeout)
+		defer cancel()
+
+		idx, err := getIndex(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &File{}
+		if len(idx.Tracks) > 0 {
+			start := time.Now()
+
+			r, err := src.Open(ctx)
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+
+			extracted := *idx
+			extracted.Tracks = slices.Clone(idx.Tracks)
+			if err := extracted.Extract(ctx, r); err != nil {
+				return nil, err
+			}
+			file.Tracks = extracted.Tracks
+
+			log.Debug("extracted subtitles", "hash", src.Hash, "tracks", len(file.Tracks), "duration", time.Since(start))
+		}
+
+		if err := fileCache.Add(src.Hash, *file); err != nil {
+			log.Error("failed to cache subtitles", "error", err, "hash", src.Hash)
+		}
+		return file, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*File), nil
+}
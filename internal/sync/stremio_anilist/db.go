@@ -0,0 +1,252 @@
+package sync_stremio_anilist
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+const TableName = "sync_stremio_anilist_link"
+
+type SyncDirection string
+
+const (
+	SyncDirectionNone             SyncDirection = "none"
+	SyncDirectionStremioToAniList SyncDirection = "stremio_to_anilist"
+	SyncDirectionAniListToStremio SyncDirection = "anilist_to_stremio"
+	SyncDirectionBoth             SyncDirection = "both"
+)
+
+func (d SyncDirection) IsValid() bool {
+	switch d {
+	case SyncDirectionNone, SyncDirectionStremioToAniList, SyncDirectionAniListToStremio, SyncDirectionBoth:
+		return true
+	}
+	return false
+}
+
+func (d SyncDirection) ShouldSyncToAniList() bool {
+	return d == SyncDirectionStremioToAniList || d == SyncDirectionBoth
+}
+
+func (d SyncDirection) ShouldSyncToStremio() bool {
+	return d == SyncDirectionAniListToStremio || d == SyncDirectionBoth
+}
+
+func (d SyncDirection) IsDisabled() bool {
+	return d == SyncDirectionNone
+}
+
+type SyncConfigWatched struct {
+	Direction SyncDirection `json:"dir"`
+}
+
+type SyncConfig struct {
+	Watched SyncConfigWatched `json:"watched"`
+}
+
+func (sc SyncConfig) Value() (driver.Value, error) {
+	return db.JSONValue(sc)
+}
+
+func (sc *SyncConfig) Scan(value any) error {
+	return db.JSONScan(value, sc)
+}
+
+type SyncStateWatched struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+}
+
+type SyncState struct {
+	Watched SyncStateWatched `json:"watched"`
+}
+
+func (ss SyncState) Value() (driver.Value, error) {
+	return db.JSONValue(ss)
+}
+
+func (ss *SyncState) Scan(value any) error {
+	return db.JSONScan(value, ss)
+}
+
+type SyncStremioAniListLink struct {
+	StremioAccountId string
+	AniListAccountId string
+	SyncConfig       SyncConfig
+	SyncState        SyncState
+	CAt              db.Timestamp
+	UAt              db.Timestamp
+}
+
+var Column = struct {
+	StremioAccountId string
+	AniListAccountId string
+	SyncConfig       string
+	SyncState        string
+	CAt              string
+	UAt              string
+}{
+	StremioAccountId: "stremio_account_id",
+	AniListAccountId: "anilist_account_id",
+	SyncConfig:       "sync_config",
+	SyncState:        "sync_state",
+	CAt:              "cat",
+	UAt:              "uat",
+}
+
+var columns = []string{
+	Column.StremioAccountId,
+	Column.AniListAccountId,
+	Column.SyncConfig,
+	Column.SyncState,
+	Column.CAt,
+	Column.UAt,
+}
+
+var query_get_all = fmt.Sprintf(
+	`SELECT %s FROM %s`,
+	strings.Join(columns, ", "),
+	TableName,
+)
+
+func GetAll() ([]SyncStremioAniListLink, error) {
+	rows, err := db.Query(query_get_all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []SyncStremioAniListLink{}
+	for rows.Next() {
+		item := SyncStremioAniListLink{}
+		if err := rows.Scan(&item.StremioAccountId, &item.AniListAccountId, &item.SyncConfig, &item.SyncState, &item.CAt, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var query_get_by_account_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s = ?`,
+	strings.Join(columns, ", "),
+	TableName,
+	Column.StremioAccountId,
+	Column.AniListAccountId,
+)
+
+func GetById(stremioAccountId, anilistAccountId string) (*SyncStremioAniListLink, error) {
+	row := db.QueryRow(query_get_by_account_id, stremioAccountId, anilistAccountId)
+	item := SyncStremioAniListLink{}
+	if err := row.Scan(
+		&item.StremioAccountId,
+		&item.AniListAccountId,
+		&item.SyncConfig,
+		&item.SyncState,
+		&item.CAt,
+		&item.UAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+var query_insert = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (?,?,?)`,
+	TableName,
+	db.JoinColumnNames(
+		Column.StremioAccountId,
+		Column.AniListAccountId,
+		Column.SyncConfig,
+	),
+)
+
+func Link(stremioAccountId, anilistAccountId string, syncConfig SyncConfig) (*SyncStremioAniListLink, error) {
+	_, err := db.Exec(query_insert, stremioAccountId, anilistAccountId, syncConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SyncStremioAniListLink{
+		StremioAccountId: stremioAccountId,
+		AniListAccountId: anilistAccountId,
+		SyncConfig:       syncConfig,
+		SyncState:        SyncState{},
+		CAt:              db.Timestamp{Time: now},
+		UAt:              db.Timestamp{Time: now},
+	}, nil
+}
+
+var query_unlink = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+	Column.AniListAccountId,
+)
+
+func Unlink(stremioAccountId, anilistAccountId string) error {
+	_, err := db.Exec(query_unlink, stremioAccountId, anilistAccountId)
+	return err
+}
+
+var query_unlink_by_stremio_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+)
+
+func UnlinkByStremioAccount(stremioAccountId string) error {
+	_, err := db.Exec(query_unlink_by_stremio_account, stremioAccountId)
+	return err
+}
+
+var query_unlink_by_anilist_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.AniListAccountId,
+)
+
+func UnlinkByAniListAccount(anilistAccountId string) error {
+	_, err := db.Exec(query_unlink_by_anilist_account, anilistAccountId)
+	return err
+}
+
+var query_set_sync_config = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncConfig,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.AniListAccountId,
+)
+
+func SetSyncConfig(stremioAccountId, anilistAccontId string, syncConfig SyncConfig) error {
+	_, err := db.Exec(query_set_sync_config, syncConfig, stremioAccountId, anilistAccontId)
+	return err
+}
+
+var query_set_sync_state = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncState,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.AniListAccountId,
+)
+
+func SetSyncState(stremioAccountId, anilistAccountId string, syncState SyncState) error {
+	_, err := db.Exec(query_set_sync_state,
+		syncState,
+		stremioAccountId,
+		anilistAccountId,
+	)
+	return err
+}
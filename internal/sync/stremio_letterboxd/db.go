@@ -0,0 +1,250 @@
+package sync_stremio_letterboxd
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+const TableName = "sync_stremio_letterboxd_link"
+
+type SyncDirection string
+
+const (
+	SyncDirectionNone                SyncDirection = "none"
+	SyncDirectionStremioToLetterboxd SyncDirection = "stremio_to_letterboxd"
+)
+
+func (d SyncDirection) IsValid() bool {
+	switch d {
+	case SyncDirectionNone, SyncDirectionStremioToLetterboxd:
+		return true
+	}
+	return false
+}
+
+func (d SyncDirection) ShouldSyncToLetterboxd() bool {
+	return d == SyncDirectionStremioToLetterboxd
+}
+
+func (d SyncDirection) IsDisabled() bool {
+	return d == SyncDirectionNone
+}
+
+type SyncConfigWatched struct {
+	Direction SyncDirection `json:"dir"`
+}
+
+type SyncConfig struct {
+	Watched SyncConfigWatched `json:"watched"`
+}
+
+func (sc SyncConfig) Value() (driver.Value, error) {
+	return db.JSONValue(sc)
+}
+
+func (sc *SyncConfig) Scan(value any) error {
+	return db.JSONScan(value, sc)
+}
+
+type SyncStateWatched struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	// stremio timesWatched of the movies, by imdb id
+	TimesWatched map[string]int `json:"times_watched,omitempty"`
+	// imdb ids of the movies failed to fetch the letterboxd film id for
+	RetryIMDBIds []string `json:"retry_imdb_ids,omitempty"`
+}
+
+type SyncState struct {
+	Watched SyncStateWatched `json:"watched"`
+}
+
+func (ss SyncState) Value() (driver.Value, error) {
+	return db.JSONValue(ss)
+}
+
+func (ss *SyncState) Scan(value any) error {
+	return db.JSONScan(value, ss)
+}
+
+type SyncStremioLetterboxdLink struct {
+	StremioAccountId    string
+	LetterboxdAccountId string
+	SyncConfig          SyncConfig
+	SyncState           SyncState
+	CAt                 db.Timestamp
+	UAt                 db.Timestamp
+}
+
+var Column = struct {
+	StremioAccountId    string
+	LetterboxdAccountId string
+	SyncConfig          string
+	SyncState           string
+	CAt                 string
+	UAt                 string
+}{
+	StremioAccountId:    "stremio_account_id",
+	LetterboxdAccountId: "letterboxd_account_id",
+	SyncConfig:          "sync_config",
+	SyncState:           "sync_state",
+	CAt:                 "cat",
+	UAt:                 "uat",
+}
+
+var columns = []string{
+	Column.StremioAccountId,
+	Column.LetterboxdAccountId,
+	Column.SyncConfig,
+	Column.SyncState,
+	Column.CAt,
+	Column.UAt,
+}
+
+var query_get_all = fmt.Sprintf(
+	`SELECT %s FROM %s`,
+	strings.Join(columns, ", "),
+	TableName,
+)
+
+func GetAll() ([]SyncStremioLetterboxdLink, error) {
+	rows, err := db.Query(query_get_all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []SyncStremioLetterboxdLink{}
+	for rows.Next() {
+		item := SyncStremioLetterboxdLink{}
+		if err := rows.Scan(&item.StremioAccountId, &item.LetterboxdAccountId, &item.SyncConfig, &item.SyncState, &item.CAt, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var query_get_by_account_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s = ?`,
+	strings.Join(columns, ", "),
+	TableName,
+	Column.StremioAccountId,
+	Column.LetterboxdAccountId,
+)
+
+func GetById(stremioAccountId, letterboxdAccountId string) (*SyncStremioLetterboxdLink, error) {
+	row := db.QueryRow(query_get_by_account_id, stremioAccountId, letterboxdAccountId)
+	item := SyncStremioLetterboxdLink{}
+	if err := row.Scan(
+		&item.StremioAccountId,
+		&item.LetterboxdAccountId,
+		&item.SyncConfig,
+		&item.SyncState,
+		&item.CAt,
+		&item.UAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+var query_insert = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (?,?,?)`,
+	TableName,
+	db.JoinColumnNames(
+		Column.StremioAccountId,
+		Column.LetterboxdAccountId,
+		Column.SyncConfig,
+	),
+)
+
+func Link(stremioAccountId, letterboxdAccountId string, syncConfig SyncConfig) (*SyncStremioLetterboxdLink, error) {
+	_, err := db.Exec(query_insert, stremioAccountId, letterboxdAccountId, syncConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SyncStremioLetterboxdLink{
+		StremioAccountId:    stremioAccountId,
+		LetterboxdAccountId: letterboxdAccountId,
+		SyncConfig:          syncConfig,
+		SyncState:           SyncState{},
+		CAt:                 db.Timestamp{Time: now},
+		UAt:                 db.Timestamp{Time: now},
+	}, nil
+}
+
+var query_unlink = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+	Column.LetterboxdAccountId,
+)
+
+func Unlink(stremioAccountId, letterboxdAccountId string) error {
+	_, err := db.Exec(query_unlink, stremioAccountId, letterboxdAccountId)
+	return err
+}
+
+var query_unlink_by_stremio_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+)
+
+func UnlinkByStremioAccount(stremioAccountId string) error {
+	_, err := db.Exec(query_unlink_by_stremio_account, stremioAccountId)
+	return err
+}
+
+var query_unlink_by_letterboxd_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.LetterboxdAccountId,
+)
+
+func UnlinkByLetterboxdAccount(letterboxdAccountId string) error {
+	_, err := db.Exec(query_unlink_by_letterboxd_account, letterboxdAccountId)
+	return err
+}
+
+var query_set_sync_config = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncConfig,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.LetterboxdAccountId,
+)
+
+func SetSyncConfig(stremioAccountId, letterboxdAccontId string, syncConfig SyncConfig) error {
+	_, err := db.Exec(query_set_sync_config, syncConfig, stremioAccountId, letterboxdAccontId)
+	return err
+}
+
+var query_set_sync_state = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncState,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.LetterboxdAccountId,
+)
+
+func SetSyncState(stremioAccountId, letterboxdAccountId string, syncState SyncState) error {
+	_, err := db.Exec(query_set_sync_state,
+		syncState,
+		stremioAccountId,
+		letterboxdAccountId,
+	)
+	return err
+}
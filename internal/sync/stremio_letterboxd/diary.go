@@ -0,0 +1,175 @@
+package sync_stremio_letterboxd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+)
+
+var stremioClient = stremio_api.NewClient(&stremio_api.ClientConfig{})
+
+type DiaryEntry struct {
+	IMDBId       string    `json:"imdb_id"`
+	FilmId       string    `json:"film_id"`
+	Name         string    `json:"name"`
+	WatchedAt    time.Time `json:"watched_at"`
+	Rewatch      bool      `json:"rewatch"`
+	TimesWatched int       `json:"times_watched"`
+}
+
+func (e DiaryEntry) DiaryDate() string {
+	return e.WatchedAt.Format(time.DateOnly)
+}
+
+// GetPendingDiaryEntries returns the movies with timesWatched gone up on
+// stremio since the last sync, which are not logged to letterboxd diary yet.
+// Stremio updates lastWatched on partial playback too, so it is not enough
+// by itself. On the first sync, the timesWatched of all the movies are
+// recorded, and the ones watched since the link was created are logged.
+//
+// Movies not on letterboxd are skipped. The ones failed to fetch the film id
+// for are left out without recording their timesWatched, and retried on the
+// next sync. Entries are sorted by watch time. The sync state is updated for all but the pending entries,
+// use SetTimesWatched for those once logged.
+func (link *SyncStremioLetterboxdLink) GetPendingDiaryEntries(stremioToken string) ([]DiaryEntry, error) {
+	startAt := link.CAt.Time
+	if link.SyncState.Watched.LastSyncedAt != nil {
+		startAt = *link.SyncState.Watched.LastSyncedAt
+	}
+
+	timesWatched := link.SyncState.Watched.TimesWatched
+	retryIds := link.SyncState.Watched.RetryIMDBIds
+	link.SyncState.Watched.RetryIMDBIds = nil
+	isFirstSync := timesWatched == nil
+	if isFirstSync {
+		timesWatched = map[string]int{}
+	}
+
+	tsRes, err := stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{
+		Ctx: stremio_api.Ctx{APIKey: stremioToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itemIds := []string{}
+	for _, ts := range tsRes.Data {
+		if strings.HasPrefix(ts.Id, "tt") && (isFirstSync || ts.ModifiedAt.After(startAt) || slices.Contains(retryIds, ts.Id)) {
+			itemIds = append(itemIds, ts.Id)
+		}
+	}
+	if len(itemIds) == 0 {
+		link.SyncState.Watched.TimesWatched = timesWatched
+		return nil, nil
+	}
+
+	itemsRes, err := stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+		Ctx: stremio_api.Ctx{APIKey: stremioToken},
+		Ids: itemIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []DiaryEntry{}
+	imdbIds := []string{}
+	for _, item := range itemsRes.Data {
+		if item.Type != "movie" || item.Removed {
+			continue
+		}
+		prevCount, count := timesWatched[item.Id], item.State.TimesWatched
+		if count <= prevCount || (isFirstSync && !item.State.LastWatched.After(startAt)) {
+			setTimesWatched(timesWatched, item.Id, count)
+			continue
+		}
+		entries = append(entries, DiaryEntry{
+			IMDBId:       item.Id,
+			Name:         item.Name,
+			WatchedAt:    item.State.LastWatched,
+			Rewatch:      prevCount > 0 || count > 1,
+			TimesWatched: count,
+		})
+		imdbIds = append(imdbIds, item.Id)
+	}
+	link.SyncState.Watched.TimesWatched = timesWatched
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	idMapByImdbId, err := imdb_title.GetIdMapsByIMDBId(imdbIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DiaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		if idMap, ok := idMapByImdbId[entry.IMDBId]; ok && idMap.LetterboxdId != "" {
+			entry.FilmId = idMap.LetterboxdId
+		} else if lid, err := letterboxd.FetchLetterboxdFilmIdentifierByIMDBId(entry.IMDBId); err != nil {
+			if errors.Is(err, letterboxd.ErrIdentifierNotFound) {
+				log.Debug("letterboxd film not found", "imdb_id", entry.IMDBId)
+				setTimesWatched(timesWatched, entry.IMDBId, entry.TimesWatched)
+				continue
+			}
+			log.Warn("failed to fetch letterboxd film identifier", "error", err, "imdb_id", entry.IMDBId)
+			link.SyncState.Watched.RetryIMDBIds = append(link.SyncState.Watched.RetryIMDBIds, entry.IMDBId)
+			continue
+		} else {
+			entry.FilmId = lid
+		}
+		result = append(result, entry)
+	}
+	slices.SortFunc(result, func(a, b DiaryEntry) int {
+		return a.WatchedAt.Compare(b.WatchedAt)
+	})
+	return result, nil
+}
+
+func setTimesWatched(timesWatched map[string]int, imdbId string, count int) {
+	if count > 0 {
+		timesWatched[imdbId] = count
+	} else {
+		delete(timesWatched, imdbId)
+	}
+}
+
+// SetTimesWatched records the timesWatched of the logged entries in the sync
+// state, so that they are not logged again.
+func (link *SyncStremioLetterboxdLink) SetTimesWatched(entries []DiaryEntry) {
+	if link.SyncState.Watched.TimesWatched == nil {
+		link.SyncState.Watched.TimesWatched = map[string]int{}
+	}
+	for _, entry := range entries {
+		setTimesWatched(link.SyncState.Watched.TimesWatched, entry.IMDBId, entry.TimesWatched)
+	}
+}
+
+// LogDiaryEntries logs the entries in order, and stops at the first one
+// failed to log. It returns the number of entries logged.
+func LogDiaryEntries(tokenId string, entries []DiaryEntry) (int, error) {
+	client := letterboxd.GetAPIClient(tokenId)
+	count := 0
+	for _, entry := range entries {
+		if entry.FilmId == "" {
+			return count, errors.New("missing letterboxd film id for " + entry.IMDBId)
+		}
+		_, err := client.CreateLogEntry(&letterboxd.CreateLogEntryParams{
+			FilmId: entry.FilmId,
+			DiaryDetails: &letterboxd.LogEntryDiaryDetails{
+				DiaryDate: entry.DiaryDate(),
+				Rewatch:   entry.Rewatch,
+			},
+			Tags: []string{},
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
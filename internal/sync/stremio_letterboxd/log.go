@@ -0,0 +1,5 @@
+package sync_stremio_letterboxd
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("sync/stremio_letterboxd")
@@ -0,0 +1,252 @@
+package sync_stremio_mal
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+const TableName = "sync_stremio_mal_link"
+
+type SyncDirection string
+
+const (
+	SyncDirectionNone         SyncDirection = "none"
+	SyncDirectionStremioToMAL SyncDirection = "stremio_to_mal"
+	SyncDirectionMALToStremio SyncDirection = "mal_to_stremio"
+	SyncDirectionBoth         SyncDirection = "both"
+)
+
+func (d SyncDirection) IsValid() bool {
+	switch d {
+	case SyncDirectionNone, SyncDirectionStremioToMAL, SyncDirectionMALToStremio, SyncDirectionBoth:
+		return true
+	}
+	return false
+}
+
+func (d SyncDirection) ShouldSyncToMAL() bool {
+	return d == SyncDirectionStremioToMAL || d == SyncDirectionBoth
+}
+
+func (d SyncDirection) ShouldSyncToStremio() bool {
+	return d == SyncDirectionMALToStremio || d == SyncDirectionBoth
+}
+
+func (d SyncDirection) IsDisabled() bool {
+	return d == SyncDirectionNone
+}
+
+type SyncConfigWatched struct {
+	Direction SyncDirection `json:"dir"`
+}
+
+type SyncConfig struct {
+	Watched SyncConfigWatched `json:"watched"`
+}
+
+func (sc SyncConfig) Value() (driver.Value, error) {
+	return db.JSONValue(sc)
+}
+
+func (sc *SyncConfig) Scan(value any) error {
+	return db.JSONScan(value, sc)
+}
+
+type SyncStateWatched struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+}
+
+type SyncState struct {
+	Watched SyncStateWatched `json:"watched"`
+}
+
+func (ss SyncState) Value() (driver.Value, error) {
+	return db.JSONValue(ss)
+}
+
+func (ss *SyncState) Scan(value any) error {
+	return db.JSONScan(value, ss)
+}
+
+type SyncStremioMALLink struct {
+	StremioAccountId string
+	MALAccountId     string
+	SyncConfig       SyncConfig
+	SyncState        SyncState
+	CAt              db.Timestamp
+	UAt              db.Timestamp
+}
+
+var Column = struct {
+	StremioAccountId string
+	MALAccountId     string
+	SyncConfig       string
+	SyncState        string
+	CAt              string
+	UAt              string
+}{
+	StremioAccountId: "stremio_account_id",
+	MALAccountId:     "mal_account_id",
+	SyncConfig:       "sync_config",
+	SyncState:        "sync_state",
+	CAt:              "cat",
+	UAt:              "uat",
+}
+
+var columns = []string{
+	Column.StremioAccountId,
+	Column.MALAccountId,
+	Column.SyncConfig,
+	Column.SyncState,
+	Column.CAt,
+	Column.UAt,
+}
+
+var query_get_all = fmt.Sprintf(
+	`SELECT %s FROM %s`,
+	strings.Join(columns, ", "),
+	TableName,
+)
+
+func GetAll() ([]SyncStremioMALLink, error) {
+	rows, err := db.Query(query_get_all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []SyncStremioMALLink{}
+	for rows.Next() {
+		item := SyncStremioMALLink{}
+		if err := rows.Scan(&item.StremioAccountId, &item.MALAccountId, &item.SyncConfig, &item.SyncState, &item.CAt, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var query_get_by_account_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s = ?`,
+	strings.Join(columns, ", "),
+	TableName,
+	Column.StremioAccountId,
+	Column.MALAccountId,
+)
+
+func GetById(stremioAccountId, malAccountId string) (*SyncStremioMALLink, error) {
+	row := db.QueryRow(query_get_by_account_id, stremioAccountId, malAccountId)
+	item := SyncStremioMALLink{}
+	if err := row.Scan(
+		&item.StremioAccountId,
+		&item.MALAccountId,
+		&item.SyncConfig,
+		&item.SyncState,
+		&item.CAt,
+		&item.UAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+var query_insert = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (?,?,?)`,
+	TableName,
+	db.JoinColumnNames(
+		Column.StremioAccountId,
+		Column.MALAccountId,
+		Column.SyncConfig,
+	),
+)
+
+func Link(stremioAccountId, malAccountId string, syncConfig SyncConfig) (*SyncStremioMALLink, error) {
+	_, err := db.Exec(query_insert, stremioAccountId, malAccountId, syncConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SyncStremioMALLink{
+		StremioAccountId: stremioAccountId,
+		MALAccountId:     malAccountId,
+		SyncConfig:       syncConfig,
+		SyncState:        SyncState{},
+		CAt:              db.Timestamp{Time: now},
+		UAt:              db.Timestamp{Time: now},
+	}, nil
+}
+
+var query_unlink = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+	Column.MALAccountId,
+)
+
+func Unlink(stremioAccountId, malAccountId string) error {
+	_, err := db.Exec(query_unlink, stremioAccountId, malAccountId)
+	return err
+}
+
+var query_unlink_by_stremio_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.StremioAccountId,
+)
+
+func UnlinkByStremioAccount(stremioAccountId string) error {
+	_, err := db.Exec(query_unlink_by_stremio_account, stremioAccountId)
+	return err
+}
+
+var query_unlink_by_mal_account = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	TableName,
+	Column.MALAccountId,
+)
+
+func UnlinkByMALAccount(malAccountId string) error {
+	_, err := db.Exec(query_unlink_by_mal_account, malAccountId)
+	return err
+}
+
+var query_set_sync_config = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncConfig,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.MALAccountId,
+)
+
+func SetSyncConfig(stremioAccountId, malAccontId string, syncConfig SyncConfig) error {
+	_, err := db.Exec(query_set_sync_config, syncConfig, stremioAccountId, malAccontId)
+	return err
+}
+
+var query_set_sync_state = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.SyncState,
+	Column.UAt, db.CurrentTimestamp,
+	Column.StremioAccountId,
+	Column.MALAccountId,
+)
+
+func SetSyncState(stremioAccountId, malAccountId string, syncState SyncState) error {
+	_, err := db.Exec(query_set_sync_state,
+		syncState,
+		stremioAccountId,
+		malAccountId,
+	)
+	return err
+}
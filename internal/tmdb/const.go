@@ -25,6 +25,15 @@ const (
 	PosterSizeOriginal PosterSize = "original"
 )
 
+type StillSize string
+
+const (
+	StillSizeW92      StillSize = "w92"
+	StillSizeW185     StillSize = "w185"
+	StillSizeW300     StillSize = "w300"
+	StillSizeOriginal StillSize = "original"
+)
+
 var movieGenreMap = map[int]string{
 	12:    "Adventure",
 	14:    "Fantasy",
@@ -0,0 +1,203 @@
+package tmdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const EpisodeTableName = "tmdb_episode"
+
+type TMDBEpisode struct {
+	Id        int
+	SeriesId  int
+	Season    int
+	Number    int
+	Name      string
+	Overview  string
+	AirDate   string // YYYY-MM-DD
+	Runtime   int
+	Still     string
+	UpdatedAt db.Timestamp
+}
+
+func (ep *TMDBEpisode) StillURL(size StillSize) string {
+	if ep.Still == "" {
+		return ""
+	}
+	return IMAGE_BASE_URL + string(size) + ep.Still
+}
+
+var EpisodeColumn = struct {
+	Id        string
+	SeriesId  string
+	Season    string
+	Number    string
+	Name      string
+	Overview  string
+	AirDate   string
+	Runtime   string
+	Still     string
+	UpdatedAt string
+}{
+	Id:        "id",
+	SeriesId:  "series_id",
+	Season:    "season",
+	Number:    "number",
+	Name:      "name",
+	Overview:  "overview",
+	AirDate:   "air_date",
+	Runtime:   "runtime",
+	Still:     "still",
+	UpdatedAt: "uat",
+}
+
+var EpisodeColumns = []string{
+	EpisodeColumn.Id,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Season,
+	EpisodeColumn.Number,
+	EpisodeColumn.Name,
+	EpisodeColumn.Overview,
+	EpisodeColumn.AirDate,
+	EpisodeColumn.Runtime,
+	EpisodeColumn.Still,
+	EpisodeColumn.UpdatedAt,
+}
+
+func toTMDBEpisodes(seriesId int, episodes []TVEpisode) []TMDBEpisode {
+	items := make([]TMDBEpisode, len(episodes))
+	for i := range episodes {
+		ep := &episodes[i]
+		items[i] = TMDBEpisode{
+			Id:       ep.Id,
+			SeriesId: seriesId,
+			Season:   ep.SeasonNumber,
+			Number:   ep.EpisodeNumber,
+			Name:     ep.Name,
+			Overview: ep.Overview,
+			AirDate:  ep.AirDate,
+			Runtime:  ep.Runtime,
+			Still:    ep.StillPath,
+		}
+	}
+	return items
+}
+
+var query_upsert_episodes_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	EpisodeTableName,
+	db.JoinColumnNames(EpisodeColumns[:len(EpisodeColumns)-1]...),
+)
+var query_upsert_episodes_values_placeholder = "(" + util.RepeatJoin("?", len(EpisodeColumns)-1, ",") + ")"
+var query_upsert_episodes_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s`,
+	EpisodeColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.SeriesId, EpisodeColumn.SeriesId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Season, EpisodeColumn.Season),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Number, EpisodeColumn.Number),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Name, EpisodeColumn.Name),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Overview, EpisodeColumn.Overview),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.AirDate, EpisodeColumn.AirDate),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Runtime, EpisodeColumn.Runtime),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Still, EpisodeColumn.Still),
+		fmt.Sprintf(`%s = %s`, EpisodeColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+var query_cleanup_episodes = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s NOT IN `,
+	EpisodeTableName,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Id,
+)
+
+// SetEpisodes replaces the stored episodes of the series.
+func SetEpisodes(tx db.Executor, seriesId int, episodes []TMDBEpisode) error {
+	count := len(episodes)
+	if count == 0 {
+		return nil
+	}
+
+	cleanupArgs := make([]any, 1+count)
+	cleanupArgs[0] = seriesId
+	for i := range episodes {
+		cleanupArgs[1+i] = episodes[i].Id
+	}
+	cleanupQuery := query_cleanup_episodes + "(" + util.RepeatJoin("?", count, ",") + ")"
+	if _, err := tx.Exec(cleanupQuery, cleanupArgs...); err != nil {
+		return err
+	}
+
+	columnCount := len(EpisodeColumns) - 1
+	for cEpisodes := range slices.Chunk(episodes, 500) {
+		count := len(cEpisodes)
+
+		query := query_upsert_episodes_before_values +
+			util.RepeatJoin(query_upsert_episodes_values_placeholder, count, ",") +
+			query_upsert_episodes_after_values
+
+		args := make([]any, count*columnCount)
+		for i, ep := range cEpisodes {
+			args[i*columnCount+0] = ep.Id
+			args[i*columnCount+1] = ep.SeriesId
+			args[i*columnCount+2] = ep.Season
+			args[i*columnCount+3] = ep.Number
+			args[i*columnCount+4] = ep.Name
+			args[i*columnCount+5] = ep.Overview
+			args[i*columnCount+6] = ep.AirDate
+			args[i*columnCount+7] = ep.Runtime
+			args[i*columnCount+8] = ep.Still
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var query_get_episodes_by_series_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s, %s`,
+	db.JoinColumnNames(EpisodeColumns...),
+	EpisodeTableName,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Season,
+	EpisodeColumn.Number,
+)
+
+func GetEpisodesBySeriesId(seriesId int) ([]TMDBEpisode, error) {
+	rows, err := db.Query(query_get_episodes_by_series_id, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	episodes := []TMDBEpisode{}
+	for rows.Next() {
+		ep := TMDBEpisode{}
+		if err := rows.Scan(
+			&ep.Id,
+			&ep.SeriesId,
+			&ep.Season,
+			&ep.Number,
+			&ep.Name,
+			&ep.Overview,
+			&ep.AirDate,
+			&ep.Runtime,
+			&ep.Still,
+			&ep.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		episodes = append(episodes, ep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
@@ -0,0 +1,88 @@
+package tmdb
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/alitto/pond/v2"
+	"golang.org/x/oauth2"
+)
+
+// getAppAPIClient returns the client authenticated with the access token of
+// the integration, for the requests not made on behalf of a user.
+var getAppAPIClient = sync.OnceValue(func() *APIClient {
+	return NewAPIClient(&APIClientConfig{
+		OAuth: APIClientConfigOAuth{
+			GetTokenSource: func(oauthConfig oauth2.Config) oauth2.TokenSource {
+				return oauth2.StaticTokenSource(&oauth2.Token{
+					AccessToken: config.Integration.TMDB.AccessToken,
+					TokenType:   "Bearer",
+				})
+			},
+		},
+	})
+})
+
+var seriesEpisodesSyncPool = pond.NewPool(2)
+
+// series are synced at most once in a while, whether it succeeds or not.
+var seriesEpisodesSyncAttemptCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+	Lifetime: 12 * time.Hour,
+	Name:     "tmdb:series-episodes:sync-attempt",
+	MaxSize:  4096,
+})
+
+func syncSeriesEpisodes(seriesId int) error {
+	client := getAppAPIClient()
+
+	log.Debug("fetching series episodes", "series_id", seriesId)
+
+	res, err := client.FetchTV(&FetchTVParams{SeriesId: seriesId})
+	if err != nil {
+		return err
+	}
+
+	episodes := []TMDBEpisode{}
+	for i := range res.Data.Seasons {
+		season := &res.Data.Seasons[i]
+		if season.EpisodeCount == 0 {
+			continue
+		}
+		res, err := client.FetchTVSeason(&FetchTVSeasonParams{
+			SeriesId:     seriesId,
+			SeasonNumber: season.SeasonNumber,
+		})
+		if err != nil {
+			return err
+		}
+		episodes = append(episodes, toTMDBEpisodes(seriesId, res.Data.Episodes)...)
+	}
+
+	return SetEpisodes(db.GetDB(), seriesId, episodes)
+}
+
+// GetStoredSeriesEpisodes returns the stored episodes of the series, without
+// waiting for the api. The episodes of the series that are not stored yet,
+// are fetched in background for the later calls.
+func GetStoredSeriesEpisodes(seriesId int) ([]TMDBEpisode, error) {
+	episodes, err := GetEpisodesBySeriesId(seriesId)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strconv.Itoa(seriesId)
+	if len(episodes) == 0 && config.Integration.TMDB.IsEnabled() && !seriesEpisodesSyncAttemptCache.Has(key) {
+		seriesEpisodesSyncAttemptCache.Add(key, true)
+		seriesEpisodesSyncPool.Submit(func() {
+			if err := syncSeriesEpisodes(seriesId); err != nil {
+				log.Error("failed to sync series episodes", "error", err, "series_id", seriesId)
+			}
+		})
+	}
+
+	return episodes, nil
+}
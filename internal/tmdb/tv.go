@@ -0,0 +1,61 @@
+package tmdb
+
+import (
+	"strconv"
+)
+
+type TVSeason struct {
+	Id           int    `json:"id"`
+	AirDate      string `json:"air_date"`
+	EpisodeCount int    `json:"episode_count"`
+	Name         string `json:"name"`
+	SeasonNumber int    `json:"season_number"`
+}
+
+type FetchTVData struct {
+	ResponseError
+	Id      int        `json:"id"`
+	Name    string     `json:"name"`
+	Seasons []TVSeason `json:"seasons"`
+}
+
+type FetchTVParams struct {
+	Ctx
+	SeriesId int
+}
+
+func (c APIClient) FetchTV(params *FetchTVParams) (APIResponse[FetchTVData], error) {
+	response := FetchTVData{}
+	res, err := c.Request("GET", "/3/tv/"+strconv.Itoa(params.SeriesId), params, &response)
+	return newAPIResponse(res, response), err
+}
+
+type TVEpisode struct {
+	Id            int    `json:"id"`
+	AirDate       string `json:"air_date"`
+	EpisodeNumber int    `json:"episode_number"`
+	Name          string `json:"name"`
+	Overview      string `json:"overview"`
+	Runtime       int    `json:"runtime"`
+	SeasonNumber  int    `json:"season_number"`
+	StillPath     string `json:"still_path"`
+}
+
+type FetchTVSeasonData struct {
+	ResponseError
+	Id           int         `json:"id"`
+	SeasonNumber int         `json:"season_number"`
+	Episodes     []TVEpisode `json:"episodes"`
+}
+
+type FetchTVSeasonParams struct {
+	Ctx
+	SeriesId     int
+	SeasonNumber int
+}
+
+func (c APIClient) FetchTVSeason(params *FetchTVSeasonParams) (APIResponse[FetchTVSeasonData], error) {
+	response := FetchTVSeasonData{}
+	res, err := c.Request("GET", "/3/tv/"+strconv.Itoa(params.SeriesId)+"/season/"+strconv.Itoa(params.SeasonNumber), params, &response)
+	return newAPIResponse(res, response), err
+}
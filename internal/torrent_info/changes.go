@@ -0,0 +1,296 @@
+package torrent_info
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/imdb_torrent"
+)
+
+// rows updated within this duration are not listed yet, so that a timestamp
+// is not passed by the cursor while rows with it are still being written.
+const changesSettleDelay = 30 * time.Second
+
+// ChangesCursor holds the last listed update time (unix microseconds) for
+// each of the tables.
+type ChangesCursor struct {
+	TorrentInfo  int64 `json:"ti"`
+	IMDBTorrent  int64 `json:"it"`
+	AniDBTorrent int64 `json:"at"`
+}
+
+var ErrInvalidChangesCursor = errors.New("invalid cursor")
+
+func ParseChangesCursor(cursor string) (ChangesCursor, error) {
+	c := ChangesCursor{}
+	if cursor == "" {
+		return c, nil
+	}
+	blob, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidChangesCursor
+	}
+	if err := json.Unmarshal(blob, &c); err != nil {
+		return c, ErrInvalidChangesCursor
+	}
+	return c, nil
+}
+
+func (c ChangesCursor) String() string {
+	blob, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(blob)
+}
+
+func cursorToTime(cursor int64) time.Time {
+	// zero timestamp is stored as NULL, and nothing is updated before 1970.
+	return time.UnixMicro(max(cursor, time.Second.Microseconds()))
+}
+
+// smallest difference between two stored timestamps
+func getTimestampPrecision() time.Duration {
+	if db.Dialect == db.DBDialectPostgres {
+		return time.Microsecond
+	}
+	return time.Second
+}
+
+type changedTorrentItem struct {
+	TorrentItem
+	UpdatedAt db.Timestamp
+}
+
+var query_list_changed = "SELECT " + list_query_columns + ", ti." + Column.UpdatedAt + ", " + query_list_files_column +
+	query_list_by_stremid_after_select +
+	fmt.Sprintf(
+		" WHERE ti.%s > ? AND ti.%s <= ? GROUP BY ti.%s ORDER BY ti.%s",
+		Column.UpdatedAt,
+		Column.UpdatedAt,
+		Column.Hash,
+		Column.UpdatedAt,
+	)
+
+func listChanged(after, until time.Time, limit int) ([]changedTorrentItem, error) {
+	query := query_list_changed
+	args := []any{db.Timestamp{Time: after}, db.Timestamp{Time: until}}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []changedTorrentItem{}
+	for rows.Next() {
+		var item changedTorrentItem
+		if err := rows.Scan(&item.Hash, &item.TorrentTitle, &item.Size, &item.Indexer, &item.Source, &item.Category, &item.Seeders, &item.Leechers, &item.Private, &item.UpdatedAt, &item.Files); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// listChangesPage lists upto `limit` items updated after the cursor. Items
+// sharing the update time of the first item past the page are left for the
+// next page, so the cursor never lands in the middle of them.
+func listChangesPage[T any](
+	cursor int64,
+	until time.Time,
+	limit int,
+	list func(after, until time.Time, limit int) ([]T, error),
+	getUpdatedAt func(item *T) time.Time,
+) (items []T, nextCursor int64, hasMore bool, err error) {
+	items, err = list(cursorToTime(cursor), until, limit+1)
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	if len(items) <= limit {
+		if len(items) > 0 {
+			cursor = getUpdatedAt(&items[len(items)-1]).UnixMicro()
+		}
+		return items, cursor, false, nil
+	}
+
+	boundary := getUpdatedAt(&items[limit])
+	items = items[:limit]
+	for len(items) > 0 && getUpdatedAt(&items[len(items)-1]).Equal(boundary) {
+		items = items[:len(items)-1]
+	}
+	if len(items) == 0 {
+		// every item in the page has the same update time
+		items, err = list(boundary.Add(-getTimestampPrecision()), boundary, 0)
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		return items, boundary.UnixMicro(), true, nil
+	}
+	return items, getUpdatedAt(&items[len(items)-1]).UnixMicro(), true, nil
+}
+
+type Changes struct {
+	Torrents      []TorrentItem
+	IMDBTorrents  []imdb_torrent.IMDBTorrent
+	AniDBTorrents []anidb.AniDBTorrent
+	Cursor        ChangesCursor
+	HasMore       bool
+}
+
+// ListChanges lists the torrents and their imdb/anidb mappings inserted or
+// updated after the cursor, upto `limit` items for each of them.
+func ListChanges(cursor ChangesCursor, limit int) (*Changes, error) {
+	until := time.Now().Add(-changesSettleDelay)
+	changes := &Changes{}
+
+	torrents, tiCursor, tiHasMore, err := listChangesPage(cursor.TorrentInfo, until, limit, listChanged, func(item *changedTorrentItem) time.Time {
+		return item.UpdatedAt.Time
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes.Torrents = make([]TorrentItem, len(torrents))
+	for i := range torrents {
+		changes.Torrents[i] = torrents[i].TorrentItem
+	}
+
+	imdbTorrents, itCursor, itHasMore, err := listChangesPage(cursor.IMDBTorrent, until, limit, imdb_torrent.ListChanged, func(item *imdb_torrent.IMDBTorrent) time.Time {
+		return item.UAt.Time
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes.IMDBTorrents = imdbTorrents
+
+	anidbTorrents, atCursor, atHasMore, err := listChangesPage(cursor.AniDBTorrent, until, limit, anidb.ListChangedTorrents, func(item *anidb.AniDBTorrent) time.Time {
+		return item.UAt.Time
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes.AniDBTorrents = anidbTorrents
+
+	changes.Cursor = ChangesCursor{
+		TorrentInfo:  tiCursor,
+		IMDBTorrent:  itCursor,
+		AniDBTorrent: atCursor,
+	}
+	changes.HasMore = tiHasMore || itHasMore || atHasMore
+	return changes, nil
+}
+
+type ChangesLineType string
+
+const (
+	ChangesLineTypeTorrent      ChangesLineType = "torrent"
+	ChangesLineTypeIMDBTorrent  ChangesLineType = "imdb"
+	ChangesLineTypeAniDBTorrent ChangesLineType = "anidb"
+	ChangesLineTypeCursor       ChangesLineType = "cursor"
+)
+
+type changesLine struct {
+	Type ChangesLineType `json:"type"`
+	Data any             `json:"data"`
+}
+
+type changesCursorLine struct {
+	Cursor  string `json:"cursor"`
+	HasMore bool   `json:"has_more"`
+}
+
+// WriteNDJSON writes a line for each item, followed by the cursor line.
+func (c *Changes) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i := range c.Torrents {
+		if err := enc.Encode(changesLine{Type: ChangesLineTypeTorrent, Data: &c.Torrents[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range c.IMDBTorrents {
+		if err := enc.Encode(changesLine{Type: ChangesLineTypeIMDBTorrent, Data: &c.IMDBTorrents[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range c.AniDBTorrents {
+		if err := enc.Encode(changesLine{Type: ChangesLineTypeAniDBTorrent, Data: &c.AniDBTorrents[i]}); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(changesLine{Type: ChangesLineTypeCursor, Data: changesCursorLine{
+		Cursor:  c.Cursor.String(),
+		HasMore: c.HasMore,
+	}})
+}
+
+var ErrIncompleteChanges = errors.New("incomplete changes, missing cursor")
+
+func ReadChangesNDJSON(r io.Reader) (*Changes, error) {
+	changes := &Changes{
+		Torrents:      []TorrentItem{},
+		IMDBTorrents:  []imdb_torrent.IMDBTorrent{},
+		AniDBTorrents: []anidb.AniDBTorrent{},
+	}
+	hasCursor := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			Type ChangesLineType `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, err
+		}
+		switch line.Type {
+		case ChangesLineTypeTorrent:
+			var item TorrentItem
+			if err := json.Unmarshal(line.Data, &item); err != nil {
+				return nil, err
+			}
+			changes.Torrents = append(changes.Torrents, item)
+		case ChangesLineTypeIMDBTorrent:
+			var item imdb_torrent.IMDBTorrent
+			if err := json.Unmarshal(line.Data, &item); err != nil {
+				return nil, err
+			}
+			changes.IMDBTorrents = append(changes.IMDBTorrents, item)
+		case ChangesLineTypeAniDBTorrent:
+			var item anidb.AniDBTorrent
+			if err := json.Unmarshal(line.Data, &item); err != nil {
+				return nil, err
+			}
+			changes.AniDBTorrents = append(changes.AniDBTorrents, item)
+		case ChangesLineTypeCursor:
+			var cursorLine changesCursorLine
+			if err := json.Unmarshal(line.Data, &cursorLine); err != nil {
+				return nil, err
+			}
+			cursor, err := ParseChangesCursor(cursorLine.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			changes.Cursor = cursor
+			changes.HasMore = cursorLine.HasMore
+			hasCursor = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !hasCursor {
+		return nil, ErrIncompleteChanges
+	}
+	return changes, nil
+}
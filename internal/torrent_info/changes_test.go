@@ -0,0 +1,125 @@
+package torrent_info
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/imdb_torrent"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChangedItem struct {
+	id  string
+	uat time.Time
+}
+
+func TestListChangesPage(t *testing.T) {
+	at := func(sec int64) time.Time {
+		return time.Unix(sec, 0)
+	}
+	rows := []testChangedItem{
+		{"a", at(10)},
+		{"b", at(11)},
+		{"c", at(11)},
+		{"d", at(12)},
+		{"e", at(12)},
+		{"f", at(12)},
+		{"g", at(13)},
+	}
+	list := func(after, until time.Time, limit int) ([]testChangedItem, error) {
+		items := []testChangedItem{}
+		for _, row := range rows {
+			if row.uat.After(after) && !row.uat.After(until) {
+				items = append(items, row)
+			}
+		}
+		if limit > 0 && len(items) > limit {
+			items = items[:limit]
+		}
+		return items, nil
+	}
+	getUpdatedAt := func(item *testChangedItem) time.Time {
+		return item.uat
+	}
+	ids := func(items []testChangedItem) []string {
+		result := make([]string, len(items))
+		for i := range items {
+			result[i] = items[i].id
+		}
+		return result
+	}
+	until := at(100)
+
+	items, cursor, hasMore, err := listChangesPage(0, until, 2, list, getUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, ids(items))
+	assert.Equal(t, at(10).UnixMicro(), cursor)
+	assert.True(t, hasMore)
+
+	items, cursor, hasMore, err = listChangesPage(cursor, until, 2, list, getUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, ids(items))
+	assert.Equal(t, at(11).UnixMicro(), cursor)
+	assert.True(t, hasMore)
+
+	items, cursor, hasMore, err = listChangesPage(cursor, until, 2, list, getUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d", "e", "f"}, ids(items))
+	assert.Equal(t, at(12).UnixMicro(), cursor)
+	assert.True(t, hasMore)
+
+	items, cursor, hasMore, err = listChangesPage(cursor, until, 2, list, getUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"g"}, ids(items))
+	assert.Equal(t, at(13).UnixMicro(), cursor)
+	assert.False(t, hasMore)
+
+	items, cursor, hasMore, err = listChangesPage(cursor, until, 2, list, getUpdatedAt)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Equal(t, at(13).UnixMicro(), cursor)
+	assert.False(t, hasMore)
+}
+
+func TestChangesNDJSON(t *testing.T) {
+	changes := &Changes{
+		Torrents: []TorrentItem{
+			{Hash: "a", TorrentTitle: "A", Size: 1, Source: TorrentInfoSourceDHT},
+		},
+		IMDBTorrents:  []imdb_torrent.IMDBTorrent{{TId: "tt0000001", Hash: "a"}},
+		AniDBTorrents: []anidb.AniDBTorrent{},
+		Cursor:        ChangesCursor{TorrentInfo: 1, IMDBTorrent: 2, AniDBTorrent: 3},
+		HasMore:       true,
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, changes.WriteNDJSON(&buf))
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	result, err := ReadChangesNDJSON(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, changes.Cursor, result.Cursor)
+	assert.True(t, result.HasMore)
+	assert.Equal(t, "a", result.Torrents[0].Hash)
+	assert.Equal(t, "tt0000001", result.IMDBTorrents[0].TId)
+	assert.Empty(t, result.AniDBTorrents)
+
+	_, err = ReadChangesNDJSON(bytes.NewBufferString(`{"type":"torrent","data":{"hash":"a"}}` + "\n"))
+	assert.ErrorIs(t, err, ErrIncompleteChanges)
+}
+
+func TestChangesCursor(t *testing.T) {
+	cursor := ChangesCursor{TorrentInfo: 1718000000000000, IMDBTorrent: 2}
+	parsed, err := ParseChangesCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	parsed, err = ParseChangesCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, ChangesCursor{}, parsed)
+
+	_, err = ParseChangesCursor("not-a-cursor")
+	assert.ErrorIs(t, err, ErrInvalidChangesCursor)
+}
@@ -350,9 +350,10 @@ var Column = struct {
 	ParserVersion string
 	ParserInput   string
 
-	Seeders  string
-	Leechers string
-	Private  string
+	Seeders          string
+	Leechers         string
+	Private          string
+	SeedersUpdatedAt string
 
 	Audio        string
 	BitDepth     string
@@ -408,9 +409,10 @@ var Column = struct {
 	ParserVersion: "parser_version",
 	ParserInput:   "parser_input",
 
-	Seeders:  "seeders",
-	Leechers: "leechers",
-	Private:  "private",
+	Seeders:          "seeders",
+	Leechers:         "leechers",
+	Private:          "private",
+	SeedersUpdatedAt: "seeders_updated_at",
 
 	Audio:        "audio",
 	BitDepth:     "bit_depth",
@@ -754,9 +756,7 @@ var query_upsert_on_conflict_set_cond = func(col, cond string) string {
 	)
 }
 
-var query_upsert_on_conflict = fmt.Sprintf(
-	` ON CONFLICT (%s) DO UPDATE SET %s, %s, %s, %s, %s, %s, %s, %s, %s WHERE %s`,
-	Column.Hash,
+var query_upsert_on_conflict_set = strings.Join([]string{
 	query_upsert_on_conflict_set_cond(Column.TorrentTitle, query_upsert_cond_should_update_source),
 	query_upsert_on_conflict_set_cond(Column.Size, query_upsert_cond_should_update_size),
 	query_upsert_on_conflict_set_cond(Column.Indexer, query_upsert_cond_should_update_indexer),
@@ -766,6 +766,12 @@ var query_upsert_on_conflict = fmt.Sprintf(
 	query_upsert_on_conflict_set_cond(Column.Leechers, query_upsert_cond_new_source_is_dht),
 	query_upsert_on_conflict_set_cond(Column.Private, query_upsert_cond_should_update_private),
 	fmt.Sprintf("%s = %s", Column.UpdatedAt, db.CurrentTimestamp),
+}, ", ")
+
+var query_upsert_on_conflict = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s WHERE %s`,
+	Column.Hash,
+	query_upsert_on_conflict_set,
 	strings.Join([]string{
 		query_upsert_cond_new_source_is_dht,
 		query_upsert_cond_new_source_more_reliable,
@@ -777,6 +783,28 @@ var query_upsert_on_conflict = fmt.Sprintf(
 	}, " OR "),
 )
 
+var query_upsert_cond_changed = func(col, cond string) string {
+	return fmt.Sprintf(`(%s AND ti.%s != EXCLUDED.%s)`, cond, col, col)
+}
+
+// same as query_upsert_on_conflict, but only for the rows with a value
+// actually changed, so that updated_at is not bumped for the same data.
+var query_upsert_changed_on_conflict = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s WHERE %s`,
+	Column.Hash,
+	query_upsert_on_conflict_set,
+	strings.Join([]string{
+		query_upsert_cond_changed(Column.TorrentTitle, query_upsert_cond_should_update_source),
+		query_upsert_cond_changed(Column.Size, query_upsert_cond_should_update_size),
+		query_upsert_cond_should_update_indexer,
+		query_upsert_cond_changed(Column.Source, query_upsert_cond_should_update_source),
+		query_upsert_cond_should_update_category,
+		query_upsert_cond_changed(Column.Seeders, query_upsert_cond_should_update_seeders),
+		query_upsert_cond_changed(Column.Leechers, query_upsert_cond_new_source_is_dht),
+		query_upsert_cond_should_update_private,
+	}, " OR "),
+)
+
 var noTorrentInfo = !config.Feature.HasTorz()
 
 var upsertSkipCount atomic.Int64
@@ -803,11 +831,28 @@ func get_upsert_query(count int) string {
 		query_upsert_on_conflict
 }
 
+func get_upsert_changed_query(count int) string {
+	return query_upsert_before_values +
+		util.RepeatJoin(query_upsert_values_placeholder, count, ",") +
+		query_upsert_changed_on_conflict
+}
+
 func shouldDiscardTorrentTitle(hash, ttitle string) bool {
 	return ttitle == "" || ttitle == hash || strings.HasPrefix(ttitle, "magnet:?") || strings.ToLower(filepath.Ext(ttitle)) == ".exe"
 }
 
 func Upsert(items []TorrentInfoInsertData, category TorrentInfoCategory, discardFileIdx bool) error {
+	return upsert(items, category, discardFileIdx, get_upsert_query)
+}
+
+// UpsertChanged is same as Upsert, except the existing rows are updated only
+// if a value changes. Used for the torrents synced from the peers, so that
+// the same rows are not exported back and forth between them.
+func UpsertChanged(items []TorrentInfoInsertData, category TorrentInfoCategory, discardFileIdx bool) error {
+	return upsert(items, category, discardFileIdx, get_upsert_changed_query)
+}
+
+func upsert(items []TorrentInfoInsertData, category TorrentInfoCategory, discardFileIdx bool, getQuery func(count int) string) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -885,7 +930,7 @@ func Upsert(items []TorrentInfoInsertData, category TorrentInfoCategory, discard
 			continue
 		}
 
-		_, err := db.Exec(get_upsert_query(count), args...)
+		_, err := db.Exec(getQuery(count), args...)
 		if err != nil {
 			log.Error("failed to upsert torrent info", "error", err, "count", count)
 			errs = append(errs, err)
@@ -1347,9 +1392,8 @@ var list_query_columns = strings.Join(
 	",",
 )
 
-var query_list_by_stremid_select = fmt.Sprintf(
-	"SELECT %s, %s(%s('p',ts.%s,'i',ts.%s,'s',ts.%s,'sid',ts.%s,'asid',ts.%s,'src',ts.%s,'vhash',ts.%s,'mi',jsonb(mi))) AS files",
-	list_query_columns,
+var query_list_files_column = fmt.Sprintf(
+	"%s(%s('p',ts.%s,'i',ts.%s,'s',ts.%s,'sid',ts.%s,'asid',ts.%s,'src',ts.%s,'vhash',ts.%s,'mi',jsonb(mi))) AS files",
 	db.FnJSONGroupArray,
 	db.FnJSONObject,
 	ts.Column.Path,
@@ -1361,6 +1405,8 @@ var query_list_by_stremid_select = fmt.Sprintf(
 	ts.Column.VideoHash,
 )
 
+var query_list_by_stremid_select = "SELECT " + list_query_columns + ", " + query_list_files_column
+
 var query_list_by_stremid_after_select = fmt.Sprintf(
 	" FROM %s ti LEFT JOIN %s ts ON ti.%s = ts.%s AND ts.%s != ''",
 	TableName,
@@ -1737,6 +1783,25 @@ func SetMissingCategory(hashesByCategory map[TorrentInfoCategory][]string) {
 	wg.Wait()
 }
 
+var query_set_size = fmt.Sprintf(
+	"UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s != ?",
+	TableName,
+	Column.Size,
+	Column.UpdatedAt,
+	db.CurrentTimestamp,
+	Column.Hash,
+	Column.Size,
+)
+
+// SetSize sets the exact size of the torrent, e.g. from its metadata.
+func SetSize(hash string, size int64) error {
+	if noTorrentInfo || size <= 0 {
+		return nil
+	}
+	_, err := db.Exec(query_set_size, size, hash, size)
+	return err
+}
+
 var query_get_basic_info_by_hash = fmt.Sprintf(
 	"SELECT %s, %s, %s FROM %s WHERE %s IN ",
 	Column.Hash,
@@ -1,6 +1,7 @@
 package torrent_info
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,20 @@ func TestUpsertQuery(t *testing.T) {
 
 	assert.Equal(t, expected_query, get_upsert_query(1))
 }
+
+func TestUpsertChangedQuery(t *testing.T) {
+	query := get_upsert_changed_query(1)
+	set, where, _ := strings.Cut(query, " WHERE ")
+
+	upsertSet, _, _ := strings.Cut(get_upsert_query(1), " WHERE ")
+	assert.Equal(t, upsertSet, set, "same update as upsert")
+
+	assert.Equal(t, "((EXCLUDED.src = 'dht' OR (EXCLUDED.src != 'ato' AND ti.src NOT IN ('dht','tio','ad','dl','rd'))) AND ti.t_title != EXCLUDED.t_title) OR "+
+		"((EXCLUDED.src = 'dht' OR (EXCLUDED.size > 0 AND ti.size < 1)) AND ti.size != EXCLUDED.size) OR "+
+		"(EXCLUDED.indexer NOT IN ('', 'bitmagnet') AND ti.indexer != EXCLUDED.indexer) OR "+
+		"((EXCLUDED.src = 'dht' OR (EXCLUDED.src != 'ato' AND ti.src NOT IN ('dht','tio','ad','dl','rd'))) AND ti.src != EXCLUDED.src) OR "+
+		"(EXCLUDED.category != '' AND ti.category = '') OR "+
+		"((EXCLUDED.src = 'dht' OR (EXCLUDED.seeders > 0 AND ti.seeders != EXCLUDED.seeders)) AND ti.seeders != EXCLUDED.seeders) OR "+
+		"(EXCLUDED.src = 'dht' AND ti.leechers != EXCLUDED.leechers) OR "+
+		"(EXCLUDED.private = 1 AND ti.private = 0)", where)
+}
@@ -0,0 +1,107 @@
+package torrent_info
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+var query_list_hashes_to_scrape = fmt.Sprintf(
+	"SELECT %s FROM %s WHERE %s = %s AND (%s IS NULL OR %s < ?) AND %s ",
+	Column.Hash,
+	TableName,
+	Column.Private,
+	db.BooleanFalse,
+	Column.SeedersUpdatedAt,
+	Column.SeedersUpdatedAt,
+	Column.Hash,
+)
+
+// ListHashesToScrape filters the public torrents whose seeders were not
+// scraped since `staleBefore`.
+func ListHashesToScrape(hashes []string, staleBefore time.Time) ([]string, error) {
+	result := []string{}
+	for cHashes := range slices.Chunk(hashes, 500) {
+		query_in_hashes, args := db.InStringValues(cHashes)
+		args = append([]any{db.Timestamp{Time: staleBefore}}, args...)
+		rows, err := db.Query(query_list_hashes_to_scrape+query_in_hashes, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			result = append(result, hash)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type SeedersUpdate struct {
+	Hash     string
+	Seeders  int
+	Leechers int
+	// only the scrape time is updated if unknown
+	Unknown bool
+}
+
+var query_update_seeders = fmt.Sprintf(
+	"UPDATE %s SET %s = ?, %s = ?, %s = %s WHERE %s = ?",
+	TableName,
+	Column.Seeders,
+	Column.Leechers,
+	Column.SeedersUpdatedAt,
+	db.CurrentTimestamp,
+	Column.Hash,
+)
+
+var query_touch_seeders = fmt.Sprintf(
+	"UPDATE %s SET %s = %s WHERE %s = ?",
+	TableName,
+	Column.SeedersUpdatedAt,
+	db.CurrentTimestamp,
+	Column.Hash,
+)
+
+func UpdateSeeders(items []SeedersUpdate) (err error) {
+	if len(items) == 0 || noTorrentInfo {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	for i := range items {
+		item := &items[i]
+		if item.Unknown {
+			_, err = tx.Exec(query_touch_seeders, item.Hash)
+		} else {
+			_, err = tx.Exec(query_update_seeders, item.Seeders, item.Leechers, item.Hash)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package torrent_metadata
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/tracker"
+	"github.com/anacrolix/dht/v2"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+var ErrMetadataNotFound = errors.New("metadata not found")
+
+// connection to a single peer is abandoned after this duration
+const peerTimeout = 20 * time.Second
+
+// port announced to trackers, incoming connections are not accepted
+const announcePort = 6881
+
+type FetcherConfig struct {
+	// time budget for fetching metadata of a single hash
+	Timeout time.Duration
+	// maximum number of peers tried for a single hash
+	MaxPeers int
+	// number of peers connected to in parallel for a single hash
+	PeerConcurrency int
+	// trackers to announce to, in addition to the ones passed to Fetch
+	Trackers   []string
+	DisableDHT bool
+}
+
+type Fetcher struct {
+	timeout         time.Duration
+	maxPeers        int
+	peerConcurrency int
+	trackers        []string
+	disableDHT      bool
+
+	peerId        [20]byte
+	trackerClient *tracker.Client
+
+	dhtMutex  sync.Mutex
+	dhtServer *dht.Server
+}
+
+func NewFetcher(conf *FetcherConfig) *Fetcher {
+	if conf.Timeout == 0 {
+		conf.Timeout = 1 * time.Minute
+	}
+	if conf.MaxPeers == 0 {
+		conf.MaxPeers = 50
+	}
+	if conf.PeerConcurrency == 0 {
+		conf.PeerConcurrency = 8
+	}
+
+	f := &Fetcher{
+		timeout:         conf.Timeout,
+		maxPeers:        conf.MaxPeers,
+		peerConcurrency: conf.PeerConcurrency,
+		trackers:        conf.Trackers,
+		disableDHT:      conf.DisableDHT,
+		trackerClient: tracker.NewClient(&tracker.ClientConfig{
+			Timeout: 15 * time.Second,
+		}),
+	}
+	copy(f.peerId[:], "-ST0001-")
+	rand.Read(f.peerId[8:])
+	return f
+}
+
+func (f *Fetcher) getDHTServer() (*dht.Server, error) {
+	f.dhtMutex.Lock()
+	defer f.dhtMutex.Unlock()
+
+	if f.dhtServer == nil {
+		s, err := dht.NewServer(dht.NewDefaultServerConfig())
+		if err != nil {
+			return nil, err
+		}
+		go s.TableMaintainer()
+		f.dhtServer = s
+	}
+	return f.dhtServer, nil
+}
+
+// Close stops the DHT server, if it was started.
+func (f *Fetcher) Close() {
+	f.dhtMutex.Lock()
+	defer f.dhtMutex.Unlock()
+
+	if f.dhtServer != nil {
+		f.dhtServer.Close()
+		f.dhtServer = nil
+	}
+}
+
+// discoverPeers sends the peers found from trackers and DHT to the channel,
+// closing it once the sources are exhausted or the context is done.
+func (f *Fetcher) discoverPeers(ctx context.Context, infoHash metainfo.Hash, trackers []string, peers chan<- netip.AddrPort) {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(peers)
+	}()
+
+	send := func(peer netip.AddrPort) bool {
+		select {
+		case peers <- peer:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, trackerUrl := range trackers {
+		wg.Go(func() {
+			res, err := f.trackerClient.Announce(ctx, trackerUrl, infoHash.HexString(), f.peerId, announcePort)
+			if err != nil {
+				log.Debug("failed to announce to tracker", "error", err, "hash", infoHash.HexString(), "tracker", trackerUrl)
+				return
+			}
+			for _, peer := range res.Peers {
+				if !send(peer) {
+					return
+				}
+			}
+		})
+	}
+
+	if f.disableDHT {
+		return
+	}
+
+	s, err := f.getDHTServer()
+	if err != nil {
+		log.Warn("failed to start dht server", "error", err)
+		return
+	}
+	a, err := s.AnnounceTraversal(infoHash)
+	if err != nil {
+		log.Debug("failed to start dht announce", "error", err, "hash", infoHash.HexString())
+		return
+	}
+	defer a.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-a.Peers:
+			if !ok {
+				return
+			}
+			for _, p := range v.Peers {
+				ip, ok := netip.AddrFromSlice(p.IP)
+				if !ok || p.Port <= 0 || p.Port > 65535 {
+					continue
+				}
+				if !send(netip.AddrPortFrom(ip.Unmap(), uint16(p.Port))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Fetch downloads the info dictionary of the hash from the swarm. Peers are
+// discovered using the trackers and DHT.
+func (f *Fetcher) Fetch(ctx context.Context, hash string, trackers ...string) (*metainfo.Info, error) {
+	var infoHash metainfo.Hash
+	if err := infoHash.FromHexString(hash); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	peers := make(chan netip.AddrPort, f.peerConcurrency)
+	go f.discoverPeers(ctx, infoHash, slices.Concat(trackers, f.trackers), peers)
+
+	type result struct {
+		metadata []byte
+		err      error
+	}
+	// buffered, so that abandoned peer connections do not block
+	results := make(chan result, f.peerConcurrency)
+
+	seen := map[netip.AddrPort]struct{}{}
+	pending := 0
+	var lastErr error
+	for peers != nil || pending > 0 {
+		var peerCh <-chan netip.AddrPort
+		if pending < f.peerConcurrency {
+			peerCh = peers
+		}
+
+		select {
+		case peer, ok := <-peerCh:
+			if !ok {
+				peers = nil
+				continue
+			}
+			if _, ok := seen[peer]; ok {
+				continue
+			}
+			seen[peer] = struct{}{}
+			if len(seen) >= f.maxPeers {
+				peers = nil
+			}
+
+			pending++
+			go func() {
+				peerCtx, peerCancel := context.WithTimeout(ctx, peerTimeout)
+				defer peerCancel()
+				metadata, err := fetchFromPeer(peerCtx, peer, infoHash, f.peerId)
+				results <- result{metadata: metadata, err: err}
+			}()
+
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				lastErr = r.err
+				continue
+			}
+			var info metainfo.Info
+			if err := bencode.Unmarshal(r.metadata, &info); err != nil {
+				lastErr = err
+				continue
+			}
+			return &info, nil
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, errors.Join(ErrMetadataNotFound, errors.New("no peers found"))
+	}
+	return nil, errors.Join(ErrMetadataNotFound, lastErr)
+}
@@ -0,0 +1,194 @@
+package torrent_metadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	pp "github.com/anacrolix/torrent/peer_protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMetadata(t *testing.T) ([]byte, metainfo.Hash) {
+	t.Helper()
+	info := metainfo.Info{
+		Name:        "Show.S01.1080p",
+		PieceLength: 256 * 1024,
+		// large enough to need multiple metadata pieces
+		Pieces: bytes.Repeat([]byte{0xab}, 20*2000),
+		Files: []metainfo.FileInfo{
+			{Path: []string{"Show.S01E01.1080p.mkv"}, Length: 100 * 1024 * 1024},
+			{Path: []string{"Show.S01E02.1080p.mkv"}, Length: 150 * 1024 * 1024},
+		},
+	}
+	metadata, err := bencode.Marshal(info)
+	assert.NoError(t, err)
+	return metadata, sha1.Sum(metadata)
+}
+
+type fakePeer struct {
+	metadata []byte
+	infoHash metainfo.Hash
+	// rejects every metadata request
+	reject bool
+}
+
+func (fp *fakePeer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	hs, err := pp.Handshake(context.Background(), conn, &fp.infoHash, [20]byte{1}, pp.NewPeerExtensionBytes(pp.ExtensionBitLtep))
+	if err != nil || !hs.SupportsExtended() {
+		return
+	}
+
+	w := bufio.NewWriter(conn)
+	write := func(msg pp.Message) {
+		b, _ := msg.MarshalBinary()
+		w.Write(b)
+		w.Flush()
+	}
+
+	write(pp.Message{
+		Type:       pp.Extended,
+		ExtendedID: pp.HandshakeExtendedID,
+		ExtendedPayload: bencode.MustMarshal(pp.ExtendedHandshakeMessage{
+			M:            map[pp.ExtensionName]pp.ExtensionNumber{pp.ExtensionNameMetadata: 3},
+			MetadataSize: len(fp.metadata),
+		}),
+	})
+	// unrelated messages are ignored
+	write(pp.Message{Type: pp.Unchoke})
+
+	var peerMetadataId pp.ExtensionNumber
+	decoder := pp.Decoder{R: bufio.NewReader(conn), Pool: piecePool, MaxLength: 256 * 1024}
+	for {
+		var msg pp.Message
+		if err := decoder.Decode(&msg); err != nil {
+			return
+		}
+		if msg.Type != pp.Extended {
+			continue
+		}
+		if msg.ExtendedID == pp.HandshakeExtendedID {
+			var ehs pp.ExtendedHandshakeMessage
+			bencode.Unmarshal(msg.ExtendedPayload, &ehs)
+			peerMetadataId = ehs.M[pp.ExtensionNameMetadata]
+			continue
+		}
+		if msg.ExtendedID != 3 {
+			continue
+		}
+		var req pp.ExtendedMetadataRequestMsg
+		bencode.Unmarshal(msg.ExtendedPayload, &req)
+		res := pp.ExtendedMetadataRequestMsg{
+			Piece:     req.Piece,
+			TotalSize: len(fp.metadata),
+			Type:      pp.DataMetadataExtensionMsgType,
+		}
+		if fp.reject {
+			res.Type = pp.RejectMetadataExtensionMsgType
+			write(pp.Message{Type: pp.Extended, ExtendedID: peerMetadataId, ExtendedPayload: bencode.MustMarshal(res)})
+			continue
+		}
+		start := req.Piece * metadataPieceSize
+		payload := append(bencode.MustMarshal(res), fp.metadata[start:start+res.PieceSize()]...)
+		write(pp.Message{Type: pp.Extended, ExtendedID: peerMetadataId, ExtendedPayload: payload})
+	}
+}
+
+func TestFetchMetadata(t *testing.T) {
+	metadata, infoHash := testMetadata(t)
+	assert.Greater(t, len(metadata), 2*metadataPieceSize)
+
+	for _, tc := range []struct {
+		name     string
+		peer     *fakePeer
+		infoHash metainfo.Hash
+		err      error
+	}{
+		{"ok", &fakePeer{metadata: metadata, infoHash: infoHash}, infoHash, nil},
+		{"rejected", &fakePeer{metadata: metadata, infoHash: infoHash, reject: true}, infoHash, errMetadataRejected},
+		{"mismatch", &fakePeer{metadata: metadata, infoHash: metainfo.Hash{1}}, metainfo.Hash{1}, errMetadataMismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			defer listener.Close()
+			go func() {
+				if conn, err := listener.Accept(); err == nil {
+					tc.peer.serve(conn)
+				}
+			}()
+
+			client, err := net.Dial("tcp", listener.Addr().String())
+			assert.NoError(t, err)
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			client.SetDeadline(time.Now().Add(5 * time.Second))
+
+			result, err := fetchMetadata(ctx, client, tc.infoHash, [20]byte{2})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, metadata, result)
+		})
+	}
+}
+
+func TestFetcherFetch(t *testing.T) {
+	metadata, infoHash := testMetadata(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	peer := &fakePeer{metadata: metadata, infoHash: infoHash}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go peer.serve(conn)
+		}
+	}()
+
+	peerAddr := listener.Addr().(*net.TCPAddr)
+	compactPeer := binary.BigEndian.AppendUint16(peerAddr.IP.To4(), uint16(peerAddr.Port))
+	trackerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peers := ""
+		if r.URL.Query().Get("info_hash") == infoHash.AsString() {
+			// unreachable peer first
+			peers = string([]byte{127, 0, 0, 1, 0, 1}) + string(compactPeer)
+		}
+		w.Write(bencode.MustMarshal(map[string]any{"interval": 1800, "peers": peers}))
+	}))
+	defer trackerServer.Close()
+
+	fetcher := NewFetcher(&FetcherConfig{
+		Timeout:    5 * time.Second,
+		DisableDHT: true,
+	})
+	defer fetcher.Close()
+
+	info, err := fetcher.Fetch(context.Background(), infoHash.HexString(), trackerServer.URL+"/announce")
+	assert.NoError(t, err)
+	assert.Equal(t, "Show.S01.1080p", info.Name)
+	assert.Equal(t, int64(250*1024*1024), info.TotalLength())
+
+	_, err = fetcher.Fetch(context.Background(), strings.Repeat("0", 40), trackerServer.URL+"/announce")
+	assert.ErrorIs(t, err, ErrMetadataNotFound)
+}
@@ -0,0 +1,5 @@
+package torrent_metadata
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("torrent_metadata")
@@ -0,0 +1,193 @@
+package torrent_metadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	pp "github.com/anacrolix/torrent/peer_protocol"
+)
+
+// BEP 9
+const metadataPieceSize = 16 * 1024
+
+// anything larger is not a sensible info dictionary
+const maxMetadataSize = 32 * 1024 * 1024
+
+// id for ut_metadata in our extended handshake
+const localMetadataExtensionId pp.ExtensionNumber = 1
+
+var (
+	errExtensionUnsupported = errors.New("peer does not support extension protocol")
+	errMetadataUnsupported  = errors.New("peer does not support ut_metadata")
+	errMetadataRejected     = errors.New("peer rejected metadata request")
+	errMetadataMismatch     = errors.New("metadata does not match info hash")
+)
+
+var piecePool = &sync.Pool{
+	New: func() any {
+		b := make([]byte, metadataPieceSize)
+		return &b
+	},
+}
+
+// fetchFromPeer downloads the info dictionary from the peer using the
+// extension protocol (BEP 10) and ut_metadata (BEP 9).
+func fetchFromPeer(ctx context.Context, addr netip.AddrPort, infoHash metainfo.Hash, peerId [20]byte) ([]byte, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr.String())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	return fetchMetadata(ctx, conn, infoHash, peerId)
+}
+
+func fetchMetadata(ctx context.Context, conn net.Conn, infoHash metainfo.Hash, peerId [20]byte) ([]byte, error) {
+	hs, err := pp.Handshake(ctx, conn, &infoHash, peerId, pp.NewPeerExtensionBytes(pp.ExtensionBitLtep))
+	if err != nil {
+		return nil, err
+	}
+	if hs.Hash != infoHash {
+		return nil, errors.New("peer responded with different info hash")
+	}
+	if !hs.SupportsExtended() {
+		return nil, errExtensionUnsupported
+	}
+
+	w := bufio.NewWriter(conn)
+	writeMessage := func(msg pp.Message) error {
+		b, err := msg.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	if err := writeMessage(pp.Message{
+		Type:       pp.Extended,
+		ExtendedID: pp.HandshakeExtendedID,
+		ExtendedPayload: bencode.MustMarshal(pp.ExtendedHandshakeMessage{
+			M: map[pp.ExtensionName]pp.ExtensionNumber{
+				pp.ExtensionNameMetadata: localMetadataExtensionId,
+			},
+		}),
+	}); err != nil {
+		return nil, err
+	}
+
+	decoder := pp.Decoder{
+		R:         bufio.NewReader(conn),
+		Pool:      piecePool,
+		MaxLength: 256 * 1024,
+	}
+
+	var metadata []byte
+	var pieceCount, receivedCount int
+	received := []bool{}
+	for {
+		var msg pp.Message
+		if err := decoder.Decode(&msg); err != nil {
+			return nil, err
+		}
+		if msg.Keepalive || msg.Type != pp.Extended {
+			continue
+		}
+
+		switch msg.ExtendedID {
+		case pp.HandshakeExtendedID:
+			if metadata != nil {
+				continue
+			}
+			var ehs pp.ExtendedHandshakeMessage
+			if err := bencode.Unmarshal(msg.ExtendedPayload, &ehs); err != nil {
+				return nil, err
+			}
+			metadataExtensionId, ok := ehs.M[pp.ExtensionNameMetadata]
+			if !ok || metadataExtensionId == pp.ExtensionDeleteNumber {
+				return nil, errMetadataUnsupported
+			}
+			if ehs.MetadataSize <= 0 || ehs.MetadataSize > maxMetadataSize {
+				return nil, errors.New("invalid metadata size")
+			}
+			metadata = make([]byte, ehs.MetadataSize)
+			pieceCount = (ehs.MetadataSize + metadataPieceSize - 1) / metadataPieceSize
+			received = make([]bool, pieceCount)
+			for piece := range pieceCount {
+				if err := writeMessage(pp.MetadataExtensionRequestMsg(metadataExtensionId, piece)); err != nil {
+					return nil, err
+				}
+			}
+
+		case localMetadataExtensionId:
+			if metadata == nil {
+				continue
+			}
+			piece, data, err := decodeMetadataMessage(msg.ExtendedPayload, len(metadata))
+			if err != nil {
+				return nil, err
+			}
+			if received[piece] {
+				continue
+			}
+			copy(metadata[piece*metadataPieceSize:], data)
+			received[piece] = true
+			receivedCount++
+			if receivedCount == pieceCount {
+				if sha1.Sum(metadata) != infoHash {
+					return nil, errMetadataMismatch
+				}
+				return metadata, nil
+			}
+		}
+	}
+}
+
+// decodeMetadataMessage returns the piece and its data from a ut_metadata
+// data message, where the data follows the bencoded dictionary.
+func decodeMetadataMessage(payload []byte, totalSize int) (int, []byte, error) {
+	var msg pp.ExtendedMetadataRequestMsg
+	d := bencode.NewDecoder(bytes.NewReader(payload))
+	if err := d.Decode(&msg); err != nil {
+		return 0, nil, err
+	}
+	switch msg.Type {
+	case pp.DataMetadataExtensionMsgType:
+	case pp.RejectMetadataExtensionMsgType:
+		return 0, nil, errMetadataRejected
+	default:
+		return 0, nil, errors.New("unexpected metadata message type")
+	}
+
+	pieceCount := (totalSize + metadataPieceSize - 1) / metadataPieceSize
+	if msg.Piece < 0 || msg.Piece >= pieceCount {
+		return 0, nil, errors.New("invalid metadata piece")
+	}
+	msg.TotalSize = totalSize
+	pieceSize := msg.PieceSize()
+	if len(payload) < pieceSize {
+		return 0, nil, errors.New("invalid metadata piece size")
+	}
+	data := payload[len(payload)-pieceSize:]
+	return msg.Piece, data, nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func FilesFromTorrentInfo(info *metainfo.Info) (files Files) {
-	for i, f := range info.Files {
+	for i, f := range info.UpvertedFiles() {
 		path := "/" + f.DisplayPath(info)
 		files = append(files, File{
 			Path:   path,
@@ -24,6 +24,11 @@ import (
 )
 
 func handleStoreTorzCheck(w http.ResponseWriter, r *http.Request) {
+	if err := peer_token.AuthorizeOptionalRequest(r, peer_token.ScopeCheckMagnetCache); err != nil {
+		err.Send(w, r)
+		return
+	}
+
 	ctx := storecontext.Get(r)
 
 	queryParams := r.URL.Query()
@@ -76,7 +81,7 @@ func handleStoreTorzCheck(w http.ResponseWriter, r *http.Request) {
 		SId:       sid,
 		LocalOnly: queryParams.Get("local_only") != "",
 	}
-	params.IsTrustedRequest, _ = peer_token.IsValid(peer_token.ExtractFromRequest(r))
+	params.IsTrustedRequest = peer_token.IsTrustedRequest(r, peer_token.ScopeCheckMagnetCache)
 	params.APIKey = ctx.StoreAuthToken
 	data, err := ctx.Store.CheckMagnet(params)
 	if err != nil {
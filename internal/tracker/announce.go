@@ -0,0 +1,160 @@
+package tracker
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+)
+
+const announceNumWant = 50
+
+type AnnounceResponse struct {
+	Peers    []netip.AddrPort
+	Seeders  int
+	Leechers int
+	// interval between announces requested by the tracker
+	Interval time.Duration
+}
+
+// Announce asks the tracker for peers in the swarm of the hash, using the
+// protocol from the scheme of the announce url. The announce is made as a
+// client that is yet to download anything.
+func (c *Client) Announce(ctx context.Context, announceUrl string, hash string, peerId [20]byte, port uint16) (*AnnounceResponse, error) {
+	u, err := url.Parse(announceUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	infoHashes, err := decodeHashes([]string{hash})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return c.announceHTTP(ctx, u, infoHashes[0], peerId, port)
+	case "udp":
+		return c.announceUDP(ctx, u, infoHashes[0], peerId, port)
+	default:
+		return nil, errors.New("unsupported tracker scheme: " + u.Scheme)
+	}
+}
+
+func decodeCompactPeers(b []byte, ipLen int) []netip.AddrPort {
+	peerLen := ipLen + 2
+	peers := make([]netip.AddrPort, 0, len(b)/peerLen)
+	for offset := 0; offset+peerLen <= len(b); offset += peerLen {
+		ip, ok := netip.AddrFromSlice(b[offset : offset+ipLen])
+		if !ok {
+			continue
+		}
+		port := binary.BigEndian.Uint16(b[offset+ipLen : offset+peerLen])
+		if port == 0 {
+			continue
+		}
+		peers = append(peers, netip.AddrPortFrom(ip.Unmap(), port))
+	}
+	return peers
+}
+
+type httpAnnounceResponsePeer struct {
+	IP   string `bencode:"ip"`
+	Port int    `bencode:"port"`
+}
+
+type httpAnnounceResponse struct {
+	FailureReason string `bencode:"failure reason"`
+	Interval      int    `bencode:"interval"`
+	Complete      int    `bencode:"complete"`
+	Incomplete    int    `bencode:"incomplete"`
+	// compact string or list of dictionaries
+	Peers  bencode.Bytes `bencode:"peers"`
+	Peers6 string        `bencode:"peers6"`
+}
+
+func (c *Client) announceHTTP(ctx context.Context, announceUrl *url.URL, infoHash [20]byte, peerId [20]byte, port uint16) (*AnnounceResponse, error) {
+	u := *announceUrl
+
+	// info_hash and peer_id are raw bytes, so url.Values would not keep the existing query as-is
+	var query strings.Builder
+	query.WriteString(u.RawQuery)
+	if query.Len() > 0 {
+		query.WriteByte('&')
+	}
+	query.WriteString("info_hash=" + url.QueryEscape(string(infoHash[:])))
+	query.WriteString("&peer_id=" + url.QueryEscape(string(peerId[:])))
+	query.WriteString("&port=" + strconv.Itoa(int(port)))
+	query.WriteString("&uploaded=0&downloaded=0&left=1&compact=1")
+	query.WriteString("&numwant=" + strconv.Itoa(announceNumWant))
+	u.RawQuery = query.String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status: " + res.Status)
+	}
+
+	var data httpAnnounceResponse
+	if err := bencode.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	if data.FailureReason != "" {
+		return nil, errors.New("tracker failure: " + data.FailureReason)
+	}
+
+	result := &AnnounceResponse{
+		Seeders:  data.Complete,
+		Leechers: data.Incomplete,
+		Interval: time.Duration(data.Interval) * time.Second,
+	}
+	if len(data.Peers) > 0 {
+		if data.Peers[0] == 'l' {
+			var peers []httpAnnounceResponsePeer
+			if err := bencode.Unmarshal(data.Peers, &peers); err != nil {
+				return nil, err
+			}
+			for _, peer := range peers {
+				ip, err := netip.ParseAddr(peer.IP)
+				if err != nil || peer.Port < 1 || peer.Port > 65535 {
+					continue
+				}
+				result.Peers = append(result.Peers, netip.AddrPortFrom(ip.Unmap(), uint16(peer.Port)))
+			}
+		} else {
+			var peers string
+			if err := bencode.Unmarshal(data.Peers, &peers); err != nil {
+				return nil, err
+			}
+			result.Peers = decodeCompactPeers([]byte(peers), 4)
+		}
+	}
+	result.Peers = append(result.Peers, decodeCompactPeers([]byte(data.Peers6), 16)...)
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package tracker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnounceHTTP(t *testing.T) {
+	var peerId [20]byte
+	copy(peerId[:], "-ST0001-abcdefghijkl")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("info_hash") != testDecodeHash(t, testHashA) || query.Get("peer_id") != string(peerId[:]) || query.Get("port") != "6881" {
+			w.Write(bencode.MustMarshal(map[string]string{"failure reason": "invalid request"}))
+			return
+		}
+		if query.Get("passkey") == "dict" {
+			w.Write(bencode.MustMarshal(map[string]any{
+				"interval": 900,
+				"peers": []map[string]any{
+					{"ip": "10.0.0.1", "port": 6881},
+					{"ip": "::1", "port": 6882},
+				},
+			}))
+			return
+		}
+		w.Write(bencode.MustMarshal(map[string]any{
+			"interval":   1800,
+			"complete":   10,
+			"incomplete": 2,
+			"peers":      string([]byte{10, 0, 0, 1, 0x1a, 0xe1, 10, 0, 0, 2, 0x1a, 0xe2}),
+			"peers6":     string(append(netip.MustParseAddr("::1").AsSlice(), 0x1a, 0xe3)),
+		}))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{})
+
+	res, err := client.Announce(context.Background(), server.URL+"/announce", testHashA, peerId, 6881)
+	assert.NoError(t, err)
+	assert.Equal(t, &AnnounceResponse{
+		Peers: []netip.AddrPort{
+			netip.MustParseAddrPort("10.0.0.1:6881"),
+			netip.MustParseAddrPort("10.0.0.2:6882"),
+			netip.MustParseAddrPort("[::1]:6883"),
+		},
+		Seeders:  10,
+		Leechers: 2,
+		Interval: 30 * time.Minute,
+	}, res)
+
+	res, err = client.Announce(context.Background(), server.URL+"/announce?passkey=dict", testHashA, peerId, 6881)
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.AddrPort{
+		netip.MustParseAddrPort("10.0.0.1:6881"),
+		netip.MustParseAddrPort("[::1]:6882"),
+	}, res.Peers)
+
+	_, err = client.Announce(context.Background(), server.URL+"/announce", testHashB, peerId, 6881)
+	assert.ErrorContains(t, err, "invalid request")
+}
+
+func TestAnnounceUDP(t *testing.T) {
+	ft := newFakeUDPTracker(t)
+	announceUrl := "udp://" + ft.conn.LocalAddr().String() + "/announce"
+
+	client := NewClient(&ClientConfig{})
+
+	res, err := client.Announce(context.Background(), announceUrl, testHashA, [20]byte{}, 6881)
+	assert.NoError(t, err)
+	assert.Equal(t, &AnnounceResponse{
+		Peers:    []netip.AddrPort{netip.MustParseAddrPort("10.0.0.1:6881")},
+		Seeders:  10,
+		Leechers: 2,
+		Interval: 30 * time.Minute,
+	}, res)
+}
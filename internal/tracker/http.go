@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+)
+
+// getHTTPScrapeURL converts the announce url to scrape url, as per BEP 48.
+func getHTTPScrapeURL(announceUrl *url.URL) (*url.URL, error) {
+	idx := strings.LastIndex(announceUrl.Path, "/")
+	if idx == -1 || !strings.HasPrefix(announceUrl.Path[idx+1:], "announce") {
+		return nil, ErrScrapeUnsupported
+	}
+	u := *announceUrl
+	u.Path = u.Path[:idx+1] + "scrape" + strings.TrimPrefix(u.Path[idx+1:], "announce")
+	u.RawPath = ""
+	return &u, nil
+}
+
+type httpScrapeResponseFile struct {
+	Complete   int `bencode:"complete"`
+	Downloaded int `bencode:"downloaded"`
+	Incomplete int `bencode:"incomplete"`
+}
+
+type httpScrapeResponse struct {
+	Files         map[string]httpScrapeResponseFile `bencode:"files"`
+	FailureReason string                            `bencode:"failure reason"`
+	Flags         struct {
+		MinRequestInterval int `bencode:"min_request_interval"`
+	} `bencode:"flags"`
+}
+
+func (c *Client) scrapeHTTP(ctx context.Context, announceUrl *url.URL, infoHashes [][20]byte) (*ScrapeResponse, error) {
+	u, err := getHTTPScrapeURL(announceUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// info_hash is raw bytes, so url.Values would not keep the existing query as-is
+	var query strings.Builder
+	query.WriteString(u.RawQuery)
+	for i := range infoHashes {
+		if query.Len() > 0 {
+			query.WriteByte('&')
+		}
+		query.WriteString("info_hash=")
+		query.WriteString(url.QueryEscape(string(infoHashes[i][:])))
+	}
+	u.RawQuery = query.String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 4*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status: " + res.Status)
+	}
+
+	var data httpScrapeResponse
+	if err := bencode.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	if data.FailureReason != "" {
+		return nil, errors.New("tracker failure: " + data.FailureReason)
+	}
+
+	result := &ScrapeResponse{
+		Files:              make(map[string]ScrapeResult, len(data.Files)),
+		MinRequestInterval: time.Duration(data.Flags.MinRequestInterval) * time.Second,
+	}
+	for hash, file := range data.Files {
+		if len(hash) != 20 {
+			continue
+		}
+		result.Files[hex.EncodeToString([]byte(hash))] = ScrapeResult{
+			Seeders:   file.Complete,
+			Leechers:  file.Incomplete,
+			Completed: file.Downloaded,
+		}
+	}
+	return result, nil
+}
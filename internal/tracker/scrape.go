@@ -0,0 +1,104 @@
+package tracker
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxScrapeHashes is the number of hashes that fit in a single UDP scrape
+// request (BEP 15). HTTP trackers are not limited by it, but large requests
+// are commonly rejected.
+const MaxScrapeHashes = 74
+
+var ErrScrapeUnsupported = errors.New("tracker does not support scrape")
+
+type ScrapeResult struct {
+	Seeders   int
+	Leechers  int
+	Completed int
+}
+
+type ScrapeResponse struct {
+	// keyed by lowercase hex hash
+	Files map[string]ScrapeResult
+	// minimum interval between scrapes requested by the tracker
+	MinRequestInterval time.Duration
+}
+
+type ClientConfig struct {
+	HTTPClient *http.Client
+	Timeout    time.Duration
+}
+
+type Client struct {
+	httpClient *http.Client
+	timeout    time.Duration
+
+	udpConnIdMutex  sync.Mutex
+	udpConnIdByHost map[string]udpConnId
+}
+
+func NewClient(conf *ClientConfig) *Client {
+	if conf.Timeout == 0 {
+		conf.Timeout = 15 * time.Second
+	}
+	if conf.HTTPClient == nil {
+		conf.HTTPClient = &http.Client{
+			Timeout: conf.Timeout,
+		}
+	}
+
+	return &Client{
+		httpClient:      conf.HTTPClient,
+		timeout:         conf.Timeout,
+		udpConnIdByHost: map[string]udpConnId{},
+	}
+}
+
+func decodeHashes(hashes []string) ([][20]byte, error) {
+	result := make([][20]byte, len(hashes))
+	for i, hash := range hashes {
+		if len(hash) != 40 {
+			return nil, errors.New("invalid hash: " + hash)
+		}
+		if _, err := hex.Decode(result[i][:], []byte(hash)); err != nil {
+			return nil, errors.New("invalid hash: " + hash)
+		}
+	}
+	return result, nil
+}
+
+// Scrape fetches the swarm stats of the hashes from the tracker, using the
+// protocol from the scheme of the announce url.
+func (c *Client) Scrape(ctx context.Context, announceUrl string, hashes []string) (*ScrapeResponse, error) {
+	u, err := url.Parse(announceUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	infoHashes, err := decodeHashes(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return c.scrapeHTTP(ctx, u, infoHashes)
+	case "udp":
+		return c.scrapeUDP(ctx, u, infoHashes)
+	default:
+		return nil, ErrScrapeUnsupported
+	}
+}
@@ -0,0 +1,240 @@
+package tracker
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testHashA = "0123456789abcdef0123456789abcdef01234567"
+	testHashB = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+type fakeTrackerTorrent struct {
+	seeders   uint32
+	leechers  uint32
+	completed uint32
+}
+
+var fakeTrackerTorrents = map[string]fakeTrackerTorrent{
+	testHashA: {seeders: 10, leechers: 2, completed: 100},
+}
+
+func testDecodeHash(t *testing.T, hash string) string {
+	t.Helper()
+	hashes, err := decodeHashes([]string{hash})
+	assert.NoError(t, err)
+	return string(hashes[0][:])
+}
+
+func TestGetHTTPScrapeURL(t *testing.T) {
+	for _, tc := range []struct {
+		announce string
+		scrape   string
+	}{
+		{"http://example.com/announce", "http://example.com/scrape"},
+		{"http://example.com/x/announce", "http://example.com/x/scrape"},
+		{"http://example.com/announce.php", "http://example.com/scrape.php"},
+		{"http://example.com/announce?passkey=abc", "http://example.com/scrape?passkey=abc"},
+		{"http://example.com/a", ""},
+		{"http://example.com/announce/x", ""},
+	} {
+		t.Run(tc.announce, func(t *testing.T) {
+			u, err := url.Parse(tc.announce)
+			assert.NoError(t, err)
+			scrapeUrl, err := getHTTPScrapeURL(u)
+			if tc.scrape == "" {
+				assert.ErrorIs(t, err, ErrScrapeUnsupported)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.scrape, scrapeUrl.String())
+			}
+		})
+	}
+}
+
+func TestScrapeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scrape" {
+			w.WriteHeader(404)
+			return
+		}
+		files := map[string]httpScrapeResponseFile{}
+		for _, infoHash := range r.URL.Query()["info_hash"] {
+			for hash, torrent := range fakeTrackerTorrents {
+				if testDecodeHash(t, hash) == infoHash {
+					files[infoHash] = httpScrapeResponseFile{
+						Complete:   int(torrent.seeders),
+						Incomplete: int(torrent.leechers),
+						Downloaded: int(torrent.completed),
+					}
+				}
+			}
+		}
+		res := httpScrapeResponse{Files: files}
+		res.Flags.MinRequestInterval = 60
+		w.Write(bencode.MustMarshal(res))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{})
+
+	res, err := client.Scrape(context.Background(), server.URL+"/announce", []string{testHashA, testHashB})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ScrapeResult{
+		testHashA: {Seeders: 10, Leechers: 2, Completed: 100},
+	}, res.Files)
+	assert.Equal(t, 60*time.Second, res.MinRequestInterval)
+
+	_, err = client.Scrape(context.Background(), server.URL+"/a", []string{testHashA})
+	assert.ErrorIs(t, err, ErrScrapeUnsupported)
+}
+
+type fakeUDPTracker struct {
+	conn         net.PacketConn
+	connectCount atomic.Int32
+	// drops the first scrape request, to test retransmission
+	dropFirstScrape atomic.Bool
+}
+
+func newFakeUDPTracker(t *testing.T) *fakeUDPTracker {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &fakeUDPTracker{conn: conn}
+	go tracker.serve()
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return tracker
+}
+
+func (ft *fakeUDPTracker) serve() {
+	const connId uint64 = 0xc0ffee
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := ft.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n < 16 {
+			continue
+		}
+		action := binary.BigEndian.Uint32(buf[8:12])
+		txId := buf[12:16]
+		switch action {
+		case udpActionConnect:
+			if binary.BigEndian.Uint64(buf[0:8]) != udpProtocolId {
+				continue
+			}
+			ft.connectCount.Add(1)
+			res := make([]byte, 16)
+			binary.BigEndian.PutUint32(res[0:4], udpActionConnect)
+			copy(res[4:8], txId)
+			binary.BigEndian.PutUint64(res[8:16], connId)
+			ft.conn.WriteTo(res, addr)
+		case udpActionScrape:
+			if ft.dropFirstScrape.CompareAndSwap(true, false) {
+				continue
+			}
+			if binary.BigEndian.Uint64(buf[0:8]) != connId {
+				res := make([]byte, 8, 32)
+				binary.BigEndian.PutUint32(res[0:4], udpActionError)
+				copy(res[4:8], txId)
+				res = append(res, "invalid connection id"...)
+				ft.conn.WriteTo(res, addr)
+				continue
+			}
+			res := make([]byte, 8)
+			binary.BigEndian.PutUint32(res[0:4], udpActionScrape)
+			copy(res[4:8], txId)
+			for offset := 16; offset+20 <= n; offset += 20 {
+				torrent := fakeTrackerTorrent{}
+				for hash, t := range fakeTrackerTorrents {
+					hashes, _ := decodeHashes([]string{hash})
+					if string(hashes[0][:]) == string(buf[offset:offset+20]) {
+						torrent = t
+					}
+				}
+				res = binary.BigEndian.AppendUint32(res, torrent.seeders)
+				res = binary.BigEndian.AppendUint32(res, torrent.completed)
+				res = binary.BigEndian.AppendUint32(res, torrent.leechers)
+			}
+			ft.conn.WriteTo(res, addr)
+		case udpActionAnnounce:
+			if binary.BigEndian.Uint64(buf[0:8]) != connId || n < 98 {
+				continue
+			}
+			res := make([]byte, 8, 32)
+			binary.BigEndian.PutUint32(res[0:4], udpActionAnnounce)
+			copy(res[4:8], txId)
+			res = binary.BigEndian.AppendUint32(res, 1800)
+			res = binary.BigEndian.AppendUint32(res, 2)
+			res = binary.BigEndian.AppendUint32(res, 10)
+			res = append(res, 10, 0, 0, 1, 0x1a, 0xe1)
+			ft.conn.WriteTo(res, addr)
+		}
+	}
+}
+
+func TestScrapeUDP(t *testing.T) {
+	ft := newFakeUDPTracker(t)
+	announceUrl := "udp://" + ft.conn.LocalAddr().String() + "/announce"
+
+	client := NewClient(&ClientConfig{})
+
+	res, err := client.Scrape(context.Background(), announceUrl, []string{testHashA, testHashB})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ScrapeResult{
+		testHashA: {Seeders: 10, Leechers: 2, Completed: 100},
+		testHashB: {},
+	}, res.Files)
+
+	// connection id is reused
+	_, err = client.Scrape(context.Background(), announceUrl, []string{testHashA})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), ft.connectCount.Load())
+
+	// rejected connection id is dropped
+	host := ft.conn.LocalAddr().String()
+	client.udpConnIdByHost[host] = udpConnId{id: 1, expiresAt: time.Now().Add(time.Minute)}
+	_, err = client.Scrape(context.Background(), announceUrl, []string{testHashA})
+	assert.ErrorContains(t, err, "invalid connection id")
+	_, ok := client.udpConnIdByHost[host]
+	assert.False(t, ok)
+
+	tooMany := make([]string, MaxScrapeHashes+1)
+	for i := range tooMany {
+		tooMany[i] = testHashA
+	}
+	_, err = client.Scrape(context.Background(), announceUrl, tooMany)
+	assert.Error(t, err)
+}
+
+func TestScrapeUDPRetransmit(t *testing.T) {
+	defer func(timeout time.Duration) {
+		udpRetryTimeout = timeout
+	}(udpRetryTimeout)
+	udpRetryTimeout = 100 * time.Millisecond
+
+	ft := newFakeUDPTracker(t)
+	ft.dropFirstScrape.Store(true)
+	announceUrl := "udp://" + ft.conn.LocalAddr().String() + "/announce"
+
+	client := NewClient(&ClientConfig{Timeout: 10 * time.Second})
+
+	res, err := client.Scrape(context.Background(), announceUrl, []string{testHashA})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, res.Files[testHashA].Seeders)
+}
@@ -0,0 +1,219 @@
+package tracker
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BEP 15
+const udpProtocolId uint64 = 0x41727101980
+
+const (
+	udpActionConnect  uint32 = 0
+	udpActionAnnounce uint32 = 1
+	udpActionScrape   uint32 = 2
+	udpActionError    uint32 = 3
+)
+
+const udpConnIdLifetime = 1 * time.Minute
+
+// the request is re-sent if there is no response within this duration,
+// doubling it for each retry.
+var udpRetryTimeout = 5 * time.Second
+
+type udpConnId struct {
+	id        uint64
+	expiresAt time.Time
+}
+
+type udpError struct {
+	message string
+}
+
+func (e *udpError) Error() string {
+	return "tracker error: " + e.message
+}
+
+func udpRoundTrip(ctx context.Context, conn net.Conn, req []byte, txId uint32, action uint32) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	buf := make([]byte, 2048)
+	for attempt := 0; ; attempt++ {
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+		readDeadline := time.Now().Add(udpRetryTimeout << attempt)
+		if !deadline.IsZero() && deadline.Before(readDeadline) {
+			readDeadline = deadline
+		}
+		if err := conn.SetReadDeadline(readDeadline); err != nil {
+			return nil, err
+		}
+
+	read:
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				var nErr net.Error
+				if errors.As(err, &nErr) && nErr.Timeout() && ctx.Err() == nil && (deadline.IsZero() || time.Now().Before(deadline)) {
+					break read
+				}
+				return nil, err
+			}
+			if n < 8 || binary.BigEndian.Uint32(buf[4:8]) != txId {
+				continue
+			}
+			switch binary.BigEndian.Uint32(buf[0:4]) {
+			case action:
+				res := make([]byte, n)
+				copy(res, buf[:n])
+				return res, nil
+			case udpActionError:
+				return nil, &udpError{message: string(buf[8:n])}
+			default:
+				return nil, errors.New("unexpected action in tracker response")
+			}
+		}
+	}
+}
+
+func (c *Client) connectUDP(ctx context.Context, conn net.Conn, host string) (uint64, error) {
+	c.udpConnIdMutex.Lock()
+	connId, ok := c.udpConnIdByHost[host]
+	c.udpConnIdMutex.Unlock()
+	if ok && connId.expiresAt.After(time.Now()) {
+		return connId.id, nil
+	}
+
+	txId := rand.Uint32()
+	req := make([]byte, 16)
+	binary.BigEndian.PutUint64(req[0:8], udpProtocolId)
+	binary.BigEndian.PutUint32(req[8:12], udpActionConnect)
+	binary.BigEndian.PutUint32(req[12:16], txId)
+
+	res, err := udpRoundTrip(ctx, conn, req, txId, udpActionConnect)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) < 16 {
+		return 0, errors.New("invalid connect response from tracker")
+	}
+
+	connId = udpConnId{
+		id:        binary.BigEndian.Uint64(res[8:16]),
+		expiresAt: time.Now().Add(udpConnIdLifetime),
+	}
+	c.udpConnIdMutex.Lock()
+	c.udpConnIdByHost[host] = connId
+	c.udpConnIdMutex.Unlock()
+	return connId.id, nil
+}
+
+// requestUDP sends the request for the action, prefixed with connection id,
+// action and transaction id, and returns the response.
+func (c *Client) requestUDP(ctx context.Context, announceUrl *url.URL, action uint32, payload []byte) (res []byte, remoteAddr net.Addr, err error) {
+	host := announceUrl.Host
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", host)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	connId, err := c.connectUDP(ctx, conn, host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txId := rand.Uint32()
+	req := make([]byte, 16, 16+len(payload))
+	binary.BigEndian.PutUint64(req[0:8], connId)
+	binary.BigEndian.PutUint32(req[8:12], action)
+	binary.BigEndian.PutUint32(req[12:16], txId)
+	req = append(req, payload...)
+
+	res, err = udpRoundTrip(ctx, conn, req, txId, action)
+	if err != nil {
+		if _, ok := err.(*udpError); ok {
+			// connection id may have been rejected
+			c.udpConnIdMutex.Lock()
+			delete(c.udpConnIdByHost, host)
+			c.udpConnIdMutex.Unlock()
+		}
+		return nil, nil, err
+	}
+	return res, conn.RemoteAddr(), nil
+}
+
+func (c *Client) scrapeUDP(ctx context.Context, announceUrl *url.URL, infoHashes [][20]byte) (*ScrapeResponse, error) {
+	if len(infoHashes) > MaxScrapeHashes {
+		return nil, errors.New("too many hashes, expected at most " + strconv.Itoa(MaxScrapeHashes))
+	}
+
+	payload := make([]byte, 20*len(infoHashes))
+	for i := range infoHashes {
+		copy(payload[20*i:], infoHashes[i][:])
+	}
+
+	res, _, err := c.requestUDP(ctx, announceUrl, udpActionScrape, payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 8+12*len(infoHashes) {
+		return nil, errors.New("invalid scrape response from tracker")
+	}
+
+	result := &ScrapeResponse{
+		Files: make(map[string]ScrapeResult, len(infoHashes)),
+	}
+	for i := range infoHashes {
+		offset := 8 + 12*i
+		result.Files[hex.EncodeToString(infoHashes[i][:])] = ScrapeResult{
+			Seeders:   int(binary.BigEndian.Uint32(res[offset : offset+4])),
+			Completed: int(binary.BigEndian.Uint32(res[offset+4 : offset+8])),
+			Leechers:  int(binary.BigEndian.Uint32(res[offset+8 : offset+12])),
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) announceUDP(ctx context.Context, announceUrl *url.URL, infoHash [20]byte, peerId [20]byte, port uint16) (*AnnounceResponse, error) {
+	payload := make([]byte, 0, 82)
+	payload = append(payload, infoHash[:]...)
+	payload = append(payload, peerId[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, 0) // downloaded
+	payload = binary.BigEndian.AppendUint64(payload, 1) // left
+	payload = binary.BigEndian.AppendUint64(payload, 0) // uploaded
+	payload = binary.BigEndian.AppendUint32(payload, 0) // event: none
+	payload = binary.BigEndian.AppendUint32(payload, 0) // ip: sender
+	payload = binary.BigEndian.AppendUint32(payload, rand.Uint32())
+	payload = binary.BigEndian.AppendUint32(payload, announceNumWant)
+	payload = binary.BigEndian.AppendUint16(payload, port)
+
+	res, remoteAddr, err := c.requestUDP(ctx, announceUrl, udpActionAnnounce, payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 20 {
+		return nil, errors.New("invalid announce response from tracker")
+	}
+
+	result := &AnnounceResponse{
+		Interval: time.Duration(binary.BigEndian.Uint32(res[8:12])) * time.Second,
+		Leechers: int(binary.BigEndian.Uint32(res[12:16])),
+		Seeders:  int(binary.BigEndian.Uint32(res[16:20])),
+	}
+	// peers are of the same address family as the tracker
+	ipLen := 4
+	if addr, ok := remoteAddr.(*net.UDPAddr); ok && addr.IP.To4() == nil {
+		ipLen = 16
+	}
+	result.Peers = decodeCompactPeers(res[20:], ipLen)
+	return result, nil
+}
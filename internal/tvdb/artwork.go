@@ -1,5 +1,7 @@
 package tvdb
 
+import "strings"
+
 const ArtworkBaseURL = "https://artworks.thetvdb.com"
 
 type ArtworkType int
@@ -65,3 +67,11 @@ type ExtendedArtwork struct {
 	TagOptions []TagOption `json:"tagOptions"`
 	SeriesId   int         `json:"seriesId,omitempty"`
 }
+
+// GetArtworkURL resolves the relative image paths, e.g. of episodes.
+func GetArtworkURL(image string) string {
+	if strings.HasPrefix(image, "/") {
+		return ArtworkBaseURL + image
+	}
+	return image
+}
@@ -0,0 +1,204 @@
+package tvdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const EpisodeTableName = "tvdb_episode"
+
+type TVDBEpisode struct {
+	Id             int
+	SeriesId       int
+	Season         int
+	Number         int
+	AbsoluteNumber int
+	Name           string
+	Overview       string
+	Aired          string // YYYY-MM-DD
+	Runtime        int
+	Image          string
+	UpdatedAt      db.Timestamp
+}
+
+var EpisodeColumn = struct {
+	Id             string
+	SeriesId       string
+	Season         string
+	Number         string
+	AbsoluteNumber string
+	Name           string
+	Overview       string
+	Aired          string
+	Runtime        string
+	Image          string
+	UpdatedAt      string
+}{
+	Id:             "id",
+	SeriesId:       "series_id",
+	Season:         "season",
+	Number:         "number",
+	AbsoluteNumber: "absolute_number",
+	Name:           "name",
+	Overview:       "overview",
+	Aired:          "aired",
+	Runtime:        "runtime",
+	Image:          "image",
+	UpdatedAt:      "uat",
+}
+
+var EpisodeColumns = []string{
+	EpisodeColumn.Id,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Season,
+	EpisodeColumn.Number,
+	EpisodeColumn.AbsoluteNumber,
+	EpisodeColumn.Name,
+	EpisodeColumn.Overview,
+	EpisodeColumn.Aired,
+	EpisodeColumn.Runtime,
+	EpisodeColumn.Image,
+	EpisodeColumn.UpdatedAt,
+}
+
+func toTVDBEpisodes(seriesId int, episodes []Episode) []TVDBEpisode {
+	items := make([]TVDBEpisode, len(episodes))
+	for i := range episodes {
+		ep := &episodes[i]
+		items[i] = TVDBEpisode{
+			Id:             ep.Id,
+			SeriesId:       seriesId,
+			Season:         ep.SeasonNumber,
+			Number:         ep.Number,
+			AbsoluteNumber: ep.AbsoluteNumber,
+			Name:           ep.Name,
+			Overview:       ep.Overview,
+			Aired:          ep.Aired,
+			Runtime:        ep.Runtime,
+			Image:          ep.Image,
+		}
+	}
+	return items
+}
+
+var query_upsert_episodes_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	EpisodeTableName,
+	db.JoinColumnNames(EpisodeColumns[:len(EpisodeColumns)-1]...),
+)
+var query_upsert_episodes_values_placeholder = "(" + util.RepeatJoin("?", len(EpisodeColumns)-1, ",") + ")"
+var query_upsert_episodes_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s`,
+	EpisodeColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.SeriesId, EpisodeColumn.SeriesId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Season, EpisodeColumn.Season),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Number, EpisodeColumn.Number),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.AbsoluteNumber, EpisodeColumn.AbsoluteNumber),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Name, EpisodeColumn.Name),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Overview, EpisodeColumn.Overview),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Aired, EpisodeColumn.Aired),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Runtime, EpisodeColumn.Runtime),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, EpisodeColumn.Image, EpisodeColumn.Image),
+		fmt.Sprintf(`%s = %s`, EpisodeColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+var query_cleanup_episodes = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ? AND %s NOT IN `,
+	EpisodeTableName,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Id,
+)
+
+// SetEpisodes replaces the stored episodes of the series.
+func SetEpisodes(tx db.Executor, seriesId int, episodes []TVDBEpisode) error {
+	count := len(episodes)
+	if count == 0 {
+		return nil
+	}
+
+	cleanupArgs := make([]any, 1+count)
+	cleanupArgs[0] = seriesId
+	for i := range episodes {
+		cleanupArgs[1+i] = episodes[i].Id
+	}
+	cleanupQuery := query_cleanup_episodes + "(" + util.RepeatJoin("?", count, ",") + ")"
+	if _, err := tx.Exec(cleanupQuery, cleanupArgs...); err != nil {
+		return err
+	}
+
+	columnCount := len(EpisodeColumns) - 1
+	for cEpisodes := range slices.Chunk(episodes, 500) {
+		count := len(cEpisodes)
+
+		query := query_upsert_episodes_before_values +
+			util.RepeatJoin(query_upsert_episodes_values_placeholder, count, ",") +
+			query_upsert_episodes_after_values
+
+		args := make([]any, count*columnCount)
+		for i, ep := range cEpisodes {
+			args[i*columnCount+0] = ep.Id
+			args[i*columnCount+1] = ep.SeriesId
+			args[i*columnCount+2] = ep.Season
+			args[i*columnCount+3] = ep.Number
+			args[i*columnCount+4] = ep.AbsoluteNumber
+			args[i*columnCount+5] = ep.Name
+			args[i*columnCount+6] = ep.Overview
+			args[i*columnCount+7] = ep.Aired
+			args[i*columnCount+8] = ep.Runtime
+			args[i*columnCount+9] = ep.Image
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var query_get_episodes_by_series_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s, %s`,
+	db.JoinColumnNames(EpisodeColumns...),
+	EpisodeTableName,
+	EpisodeColumn.SeriesId,
+	EpisodeColumn.Season,
+	EpisodeColumn.Number,
+)
+
+func GetEpisodesBySeriesId(seriesId int) ([]TVDBEpisode, error) {
+	rows, err := db.Query(query_get_episodes_by_series_id, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	episodes := []TVDBEpisode{}
+	for rows.Next() {
+		ep := TVDBEpisode{}
+		if err := rows.Scan(
+			&ep.Id,
+			&ep.SeriesId,
+			&ep.Season,
+			&ep.Number,
+			&ep.AbsoluteNumber,
+			&ep.Name,
+			&ep.Overview,
+			&ep.Aired,
+			&ep.Runtime,
+			&ep.Image,
+			&ep.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		episodes = append(episodes, ep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
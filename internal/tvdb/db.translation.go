@@ -0,0 +1,175 @@
+package tvdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const TVDBItemTypeEpisode TVDBItemType = "episode"
+
+const TranslationTableName = "tvdb_translation"
+
+type TVDBTranslation struct {
+	ItemId    int
+	ItemType  TVDBItemType
+	Lang      string // ISO 639-2, e.g. eng
+	Name      string
+	Overview  string
+	UpdatedAt db.Timestamp
+}
+
+var TranslationColumn = struct {
+	ItemId    string
+	ItemType  string
+	Lang      string
+	Name      string
+	Overview  string
+	UpdatedAt string
+}{
+	ItemId:    "item_id",
+	ItemType:  "item_type",
+	Lang:      "lang",
+	Name:      "name",
+	Overview:  "overview",
+	UpdatedAt: "uat",
+}
+
+var TranslationColumns = []string{
+	TranslationColumn.ItemId,
+	TranslationColumn.ItemType,
+	TranslationColumn.Lang,
+	TranslationColumn.Name,
+	TranslationColumn.Overview,
+	TranslationColumn.UpdatedAt,
+}
+
+func (t Translations) toTVDBTranslations(itemId int, itemType TVDBItemType) []TVDBTranslation {
+	items := []TVDBTranslation{}
+	idxByLang := map[string]int{}
+	for _, nt := range t.NameTranslations {
+		if nt.Language == "" {
+			continue
+		}
+		idxByLang[nt.Language] = len(items)
+		items = append(items, TVDBTranslation{
+			ItemId:   itemId,
+			ItemType: itemType,
+			Lang:     nt.Language,
+			Name:     nt.Name,
+		})
+	}
+	for _, ot := range t.OverviewTranslations {
+		if ot.Language == "" {
+			continue
+		}
+		if idx, ok := idxByLang[ot.Language]; ok {
+			items[idx].Overview = ot.Overview
+		} else {
+			idxByLang[ot.Language] = len(items)
+			items = append(items, TVDBTranslation{
+				ItemId:   itemId,
+				ItemType: itemType,
+				Lang:     ot.Language,
+				Overview: ot.Overview,
+			})
+		}
+	}
+	return items
+}
+
+var query_upsert_translations_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	TranslationTableName,
+	db.JoinColumnNames(TranslationColumns[:len(TranslationColumns)-1]...),
+)
+var query_upsert_translations_values_placeholder = "(" + util.RepeatJoin("?", len(TranslationColumns)-1, ",") + ")"
+var query_upsert_translations_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s,%s,%s) DO UPDATE SET %s`,
+	TranslationColumn.ItemId,
+	TranslationColumn.ItemType,
+	TranslationColumn.Lang,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, TranslationColumn.Name, TranslationColumn.Name),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, TranslationColumn.Overview, TranslationColumn.Overview),
+		fmt.Sprintf(`%s = %s`, TranslationColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func UpsertTranslations(tx db.Executor, translations []TVDBTranslation) error {
+	columnCount := len(TranslationColumns) - 1
+	for cTranslations := range slices.Chunk(translations, 500) {
+		count := len(cTranslations)
+
+		query := query_upsert_translations_before_values +
+			util.RepeatJoin(query_upsert_translations_values_placeholder, count, ",") +
+			query_upsert_translations_after_values
+
+		args := make([]any, count*columnCount)
+		for i, t := range cTranslations {
+			args[i*columnCount+0] = t.ItemId
+			args[i*columnCount+1] = t.ItemType
+			args[i*columnCount+2] = t.Lang
+			args[i*columnCount+3] = t.Name
+			args[i*columnCount+4] = t.Overview
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var query_get_translations = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s = ? AND %s IN `,
+	db.JoinColumnNames(TranslationColumns...),
+	TranslationTableName,
+	TranslationColumn.ItemType,
+	TranslationColumn.Lang,
+	TranslationColumn.ItemId,
+)
+
+func GetTranslations(itemType TVDBItemType, lang string, ids ...int) (map[int]TVDBTranslation, error) {
+	translationById := make(map[int]TVDBTranslation, len(ids))
+	for cIds := range slices.Chunk(ids, 500) {
+		count := len(cIds)
+
+		query := query_get_translations + "(" + util.RepeatJoin("?", count, ",") + ")"
+		args := make([]any, 2+count)
+		args[0] = itemType
+		args[1] = lang
+		for i := range cIds {
+			args[2+i] = cIds[i]
+		}
+
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			t := TVDBTranslation{}
+			if err := rows.Scan(
+				&t.ItemId,
+				&t.ItemType,
+				&t.Lang,
+				&t.Name,
+				&t.Overview,
+				&t.UpdatedAt,
+			); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			translationById[t.ItemId] = t
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return translationById, nil
+}
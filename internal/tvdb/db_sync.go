@@ -41,6 +41,9 @@ func syncItem(client *APIClient, li *TVDBItem, saveToDB bool) error {
 	key := string(li.Type) + strconv.Itoa(li.Id)
 
 	data, err, _ := syncItemGroup.Do(key, func() (any, error) {
+		var translations []TVDBTranslation
+		var episodes []TVDBEpisode
+
 		switch li.Type {
 		case TVDBItemTypeMovie:
 			log.Debug("fetching movie by id", "id", li.Id)
@@ -61,6 +64,7 @@ func syncItem(client *APIClient, li *TVDBItem, saveToDB bool) error {
 				li.Genres = append(li.Genres, res.Data.Genres[i].Id)
 			}
 			li.IdMap = res.Data.GetIdMap()
+			translations = res.Data.Translations.toTVDBTranslations(li.Id, li.Type)
 		case TVDBItemTypeSeries:
 			log.Debug("fetching series by id", "id", li.Id)
 			res, err := client.FetchSeries(&FetchSeriesParams{
@@ -80,6 +84,8 @@ func syncItem(client *APIClient, li *TVDBItem, saveToDB bool) error {
 				li.Genres = append(li.Genres, res.Data.Genres[i].Id)
 			}
 			li.IdMap = res.Data.GetIdMap()
+			translations = res.Data.Translations.toTVDBTranslations(li.Id, li.Type)
+			episodes = toTVDBEpisodes(li.Id, res.Data.Episodes)
 		}
 
 		li.UpdatedAt = db.Timestamp{Time: time.Now()}
@@ -89,6 +95,12 @@ func syncItem(client *APIClient, li *TVDBItem, saveToDB bool) error {
 			if err := UpsertItems(db.GetDB(), []TVDBItem{item}); err != nil {
 				return nil, err
 			}
+			if err := UpsertTranslations(db.GetDB(), translations); err != nil {
+				return nil, err
+			}
+			if err := SetEpisodes(db.GetDB(), li.Id, episodes); err != nil {
+				return nil, err
+			}
 		}
 		return item, nil
 	})
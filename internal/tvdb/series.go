@@ -208,3 +208,30 @@ func (c APIClient) FetchSeries(params *FetchSeriesParams) (request.APIResponse[E
 	res, err := c.Request("GET", "/series/"+strconv.Itoa(params.Id)+"/extended", params, &response)
 	return request.NewAPIResponse(res, response.Data), err
 }
+
+type SeriesEpisodesData struct {
+	Series   Series    `json:"series"`
+	Episodes []Episode `json:"episodes"`
+}
+
+type FetchSeriesEpisodesParams struct {
+	Ctx
+	Id         int
+	SeasonType string // default, official, dvd, absolute, alternate, regional
+	Lang       string
+	Page       int
+}
+
+// FetchSeriesEpisodes returns the episodes with name and overview translated
+// to Lang, a page holds at most 500 episodes.
+func (c APIClient) FetchSeriesEpisodes(params *FetchSeriesEpisodesParams) (request.APIResponse[SeriesEpisodesData], error) {
+	if params.SeasonType == "" {
+		params.SeasonType = "default"
+	}
+	params.Query = &url.Values{
+		"page": []string{strconv.Itoa(params.Page)},
+	}
+	response := Response[SeriesEpisodesData]{}
+	res, err := c.Request("GET", "/series/"+strconv.Itoa(params.Id)+"/episodes/"+params.SeasonType+"/"+params.Lang, params, &response)
+	return request.NewAPIResponse(res, response.Data), err
+}
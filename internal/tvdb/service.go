@@ -2,7 +2,11 @@ package tvdb
 
 import (
 	"strconv"
+	"time"
 
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
 	"github.com/MunifTanjim/stremthru/internal/meta"
 	"github.com/MunifTanjim/stremthru/internal/util"
@@ -158,3 +162,160 @@ func GetTVDBIdsForIMDBIds(imdbIds []string) (map[string]string, error) {
 
 	return tvdbIdByImdbId, nil
 }
+
+// series synced before episodes were stored, or the ones that failed to sync,
+// are retried at most once in a while.
+var seriesEpisodesSyncAttemptCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+	Lifetime: 12 * time.Hour,
+	Name:     "tvdb:series-episodes:sync-attempt",
+	MaxSize:  4096,
+})
+
+var episodeTranslationSyncAttemptCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+	Lifetime: 12 * time.Hour,
+	Name:     "tvdb:episode-translation:sync-attempt",
+	MaxSize:  4096,
+})
+
+// translateEpisodes applies the stored translations to the episodes, and
+// reports whether every episode has one.
+func translateEpisodes(episodes []TVDBEpisode, lang string) (bool, error) {
+	ids := make([]int, len(episodes))
+	for i := range episodes {
+		ids[i] = episodes[i].Id
+	}
+	translationById, err := GetTranslations(TVDBItemTypeEpisode, lang, ids...)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range episodes {
+		ep := &episodes[i]
+		if t, ok := translationById[ep.Id]; ok {
+			if t.Name != "" {
+				ep.Name = t.Name
+			}
+			if t.Overview != "" {
+				ep.Overview = t.Overview
+			}
+		}
+	}
+
+	return len(translationById) == len(episodes), nil
+}
+
+// syncInBackground runs the sync at most once in a while for the key,
+// whether it succeeds or not.
+func syncInBackground(attemptCache *cache.LRUCache[bool], key string, sync func() error) {
+	if !config.Integration.TVDB.IsEnabled() || attemptCache.Has(key) {
+		return
+	}
+	attemptCache.Add(key, true)
+	syncItemPool.Submit(func() {
+		if err := sync(); err != nil {
+			log.Error("failed to sync in background", "error", err, "key", key)
+		}
+	})
+}
+
+var itemSyncAttemptCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+	Lifetime: 12 * time.Hour,
+	Name:     "tvdb:item:sync-attempt",
+	MaxSize:  4096,
+})
+
+// GetStoredItem returns the stored item, without waiting for the api. The
+// missing or stale item is fetched in background for the later calls.
+func GetStoredItem(itemType TVDBItemType, id int) (*TVDBItem, error) {
+	item, err := GetItemById(itemType, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.IsStale() {
+		syncInBackground(itemSyncAttemptCache, string(itemType)+":"+strconv.Itoa(id), func() error {
+			li := TVDBItem{Id: id, Type: itemType}
+			return syncItem(GetAPIClient(), &li, true)
+		})
+	}
+	return item, nil
+}
+
+// GetStoredSeriesEpisodes returns the stored episodes of the series, with
+// name and overview translated to lang (ISO 639-2) when available, without
+// waiting for the api. The missing episodes and translations are fetched in
+// background for the later calls.
+func GetStoredSeriesEpisodes(seriesId int, lang string) ([]TVDBEpisode, error) {
+	episodes, err := GetEpisodesBySeriesId(seriesId)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strconv.Itoa(seriesId)
+	if len(episodes) == 0 {
+		syncInBackground(seriesEpisodesSyncAttemptCache, key, func() error {
+			li := TVDBItem{Id: seriesId, Type: TVDBItemTypeSeries}
+			return syncItem(GetAPIClient(), &li, true)
+		})
+		return episodes, nil
+	}
+
+	if lang == "" {
+		return episodes, nil
+	}
+
+	isTranslated, err := translateEpisodes(episodes, lang)
+	if err != nil {
+		return nil, err
+	}
+	if !isTranslated {
+		syncInBackground(episodeTranslationSyncAttemptCache, key+":"+lang, func() error {
+			return syncEpisodeTranslations(seriesId, lang)
+		})
+	}
+
+	return episodes, nil
+}
+
+func syncEpisodeTranslations(seriesId int, lang string) error {
+	client := GetAPIClient()
+
+	log.Debug("fetching episode translations", "series_id", seriesId, "lang", lang)
+
+	translations := []TVDBTranslation{}
+	for page := 0; ; page++ {
+		res, err := client.FetchSeriesEpisodes(&FetchSeriesEpisodesParams{
+			Id:   seriesId,
+			Lang: lang,
+			Page: page,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range res.Data.Episodes {
+			ep := &res.Data.Episodes[i]
+			translations = append(translations, TVDBTranslation{
+				ItemId:   ep.Id,
+				ItemType: TVDBItemTypeEpisode,
+				Lang:     lang,
+				Name:     ep.Name,
+				Overview: ep.Overview,
+			})
+		}
+		if len(res.Data.Episodes) < 500 {
+			break
+		}
+	}
+
+	return UpsertTranslations(db.GetDB(), translations)
+}
+
+func GetTranslation(itemType TVDBItemType, id int, lang string) (*TVDBTranslation, error) {
+	translationById, err := GetTranslations(itemType, lang, id)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := translationById[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+package usenet_indexer
+
+import (
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+var fileExtensionRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(?:\.vol\d+[+-]\d+)?\.par2$`),
+	regexp.MustCompile(`(?i)\.part\d+\.rar$`),
+	regexp.MustCompile(`(?i)\.(?:rar|r\d{2,3}|s\d{2}|7z|zip|z\d{2}|\d{3}|nfo|sfv|srr|nzb|jpg|png|txt|srt|sub|idx)$`),
+	regexp.MustCompile(`(?i)\.(?:mkv|mp4|m4v|avi|wmv|mov|ts|m2ts|iso|img)$`),
+}
+
+// trimFileExtensions strips the archive, par2 and media extensions from the
+// name of a file, leaving the part shared by all the files of a release.
+func trimFileExtensions(name string) string {
+	for {
+		trimmed := name
+		for _, re := range fileExtensionRegexes {
+			trimmed = re.ReplaceAllString(trimmed, "")
+		}
+		if trimmed == name || trimmed == "" {
+			return name
+		}
+		name = trimmed
+	}
+}
+
+func getReleaseName(s nzb.Subject) string {
+	name := trimFileExtensions(s.Name)
+	if s.Prefix != "" && !strings.Contains(s.Prefix, name) {
+		if prefix := strings.Trim(s.Prefix, ` -"[]`); prefix != "" {
+			return prefix
+		}
+	}
+	return name
+}
+
+func parseDate(value string) time.Time {
+	if date, err := mail.ParseDate(value); err == nil {
+		return date
+	}
+	return time.Now()
+}
+
+type assembler struct {
+	group    string
+	releases map[string]*Release
+	files    map[string]*File
+}
+
+func newAssembler(group string) *assembler {
+	return &assembler{
+		group:    group,
+		releases: map[string]*Release{},
+		files:    map[string]*File{},
+	}
+}
+
+// add groups the article into its file, and the file into its release.
+// Articles without a segment counter in the subject are not part of a
+// binary post, and are ignored.
+func (a *assembler) add(ov *nntp.ArticleOverview) {
+	s := nzb.ParseSubject(ov.Subject)
+	if s.SegmentCount == 0 || s.SegmentNumber == 0 || s.Name == "" {
+		return
+	}
+
+	date := parseDate(ov.Date)
+
+	fileId := util.MD5Hash(ov.From + "\n" + s.Base)
+	f, ok := a.files[fileId]
+	if !ok {
+		name := getReleaseName(s)
+		releaseId := util.MD5Hash(ov.From + "\n" + strconv.Itoa(s.FileCount) + "\n" + name)
+		r, ok := a.releases[releaseId]
+		if !ok {
+			r = &Release{
+				Id:        releaseId,
+				Name:      name,
+				Poster:    ov.From,
+				GroupName: a.group,
+				FileCount: s.FileCount,
+				Date:      db.Timestamp{Time: date},
+			}
+			a.releases[releaseId] = r
+		}
+		f = &File{
+			Id:           fileId,
+			ReleaseId:    releaseId,
+			Subject:      s.Base,
+			Name:         s.Name,
+			Number:       s.Number,
+			SegmentCount: s.SegmentCount,
+			Poster:       ov.From,
+			Date:         db.Timestamp{Time: date},
+			Groups:       a.group,
+		}
+		a.files[fileId] = f
+	}
+
+	if date.Before(f.Date.Time) {
+		f.Date.Time = date
+	}
+	if r := a.releases[f.ReleaseId]; date.Before(r.Date.Time) {
+		r.Date.Time = date
+	}
+
+	f.Segments = append(f.Segments, Segment{
+		FileId:    fileId,
+		Number:    s.SegmentNumber,
+		Bytes:     ov.Bytes,
+		MessageId: strings.Trim(ov.MessageId, "<>"),
+	})
+}
+
+func (a *assembler) result() (releases []Release, files []File) {
+	releases = make([]Release, 0, len(a.releases))
+	for _, r := range a.releases {
+		releases = append(releases, *r)
+	}
+	slices.SortFunc(releases, func(a, b Release) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	files = make([]File, 0, len(a.files))
+	for _, f := range a.files {
+		files = append(files, *f)
+	}
+	slices.SortFunc(files, func(a, b File) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return releases, files
+}
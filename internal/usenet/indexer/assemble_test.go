@@ -0,0 +1,77 @@
+package usenet_indexer
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimFileExtensions(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Movie.2020.1080p-GRP.part01.rar":      "Movie.2020.1080p-GRP",
+		"Movie.2020.1080p-GRP.r00":             "Movie.2020.1080p-GRP",
+		"Movie.2020.1080p-GRP.vol03+04.par2":   "Movie.2020.1080p-GRP",
+		"Movie.2020.1080p-GRP.par2":            "Movie.2020.1080p-GRP",
+		"Movie.2020.1080p-GRP.7z.001":          "Movie.2020.1080p-GRP",
+		"Show.S01E01.1080p.WEB-DL-GRP.mkv":     "Show.S01E01.1080p.WEB-DL-GRP",
+		"618ee7f37097e26dbb27464aac1243dc":     "618ee7f37097e26dbb27464aac1243dc",
+		"Movie.2020.1080p-GRP.nfo":             "Movie.2020.1080p-GRP",
+		"Movie.2020.1080p.BluRay.x264-GRP.zip": "Movie.2020.1080p.BluRay.x264-GRP",
+	} {
+		assert.Equal(t, expected, trimFileExtensions(name), name)
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	const poster = "poster <poster@example.com>"
+	const date = "Mon, 02 Jan 2006 15:04:05 +0000"
+
+	overviews := []nntp.ArticleOverview{
+		{Number: 1, Subject: `Movie.2020.1080p-GRP [1/2] - "Movie.2020.1080p-GRP.part1.rar" yEnc (1/2)`, From: poster, Date: date, MessageId: "<a1@example>", Bytes: 100},
+		{Number: 2, Subject: `Movie.2020.1080p-GRP [1/2] - "Movie.2020.1080p-GRP.part1.rar" yEnc (2/2)`, From: poster, Date: date, MessageId: "<a2@example>", Bytes: 50},
+		{Number: 3, Subject: `Movie.2020.1080p-GRP [2/2] - "Movie.2020.1080p-GRP.par2" yEnc (1/1)`, From: poster, Date: date, MessageId: "<b1@example>", Bytes: 10},
+		{Number: 4, Subject: `[PRiVATE]-[WtFnZb]-[Show.S01E01.1080p.WEB-DL-GRP.mkv]-[1/1] - "" yEnc 1000 (1/1)`, From: "other", Date: date, MessageId: "<c1@example>", Bytes: 1000},
+		{Number: 5, Subject: `Re: just chatting`, From: "other", Date: date, MessageId: "<d1@example>", Bytes: 1},
+	}
+
+	a := newAssembler("alt.binaries.test")
+	for i := range overviews {
+		a.add(&overviews[i])
+	}
+	releases, files := a.result()
+	require.Len(t, releases, 2)
+	require.Len(t, files, 3)
+
+	byName := map[string]Release{}
+	for _, r := range releases {
+		byName[r.Name] = r
+	}
+	require.Contains(t, byName, "Movie.2020.1080p-GRP")
+	require.Contains(t, byName, "Show.S01E01.1080p.WEB-DL-GRP")
+	movie := byName["Movie.2020.1080p-GRP"]
+	assert.Equal(t, 2, movie.FileCount)
+	assert.Equal(t, "alt.binaries.test", movie.GroupName)
+
+	movieFiles := []File{}
+	for _, f := range files {
+		if f.ReleaseId == movie.Id {
+			movieFiles = append(movieFiles, f)
+		}
+	}
+	require.Len(t, movieFiles, 2)
+
+	blob, err := buildNZB(&movie, movieFiles)
+	require.NoError(t, err)
+
+	doc, err := nzb.ParseBytes(blob)
+	require.NoError(t, err)
+	require.Equal(t, 2, doc.FileCount())
+	assert.Equal(t, "Movie.2020.1080p-GRP", doc.GetMeta("name"))
+	assert.Equal(t, "Movie.2020.1080p-GRP.part1.rar", doc.Files[0].Name())
+	assert.Equal(t, []string{"a1@example", "a2@example"}, doc.Files[0].MessageIds())
+	assert.Equal(t, int64(160), doc.TotalSize())
+	assert.Equal(t, []string{"alt.binaries.test"}, doc.Files[0].Groups)
+}
@@ -0,0 +1,648 @@
+package usenet_indexer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const GroupTableName = "usenet_indexer_group"
+
+// article numbers are per server, so the last number is per provider
+var GroupColumn = struct {
+	ProviderId string
+	Name       string
+	LastNumber string
+	CAt        string
+	UAt        string
+}{
+	ProviderId: "provider_id",
+	Name:       "name",
+	LastNumber: "last_number",
+	CAt:        "cat",
+	UAt:        "uat",
+}
+
+const TableName = "usenet_indexer_release"
+
+type Release struct {
+	Id        string
+	Name      string
+	Poster    string
+	GroupName string
+	FileCount int // from the subject, 0 if unknown
+	Files     int // seen so far
+	Size      int64
+	Date      db.Timestamp
+	Complete  bool
+	Category  int
+	IMDBId    string
+	Season    string
+	Episode   string
+	CAt       db.Timestamp
+	UAt       db.Timestamp
+}
+
+var Column = struct {
+	Id        string
+	Name      string
+	Poster    string
+	GroupName string
+	FileCount string
+	Files     string
+	Size      string
+	Date      string
+	Complete  string
+	Category  string
+	IMDBId    string
+	Season    string
+	Episode   string
+	CAt       string
+	UAt       string
+}{
+	Id:        "id",
+	Name:      "name",
+	Poster:    "poster",
+	GroupName: "group_name",
+	FileCount: "file_count",
+	Files:     "files",
+	Size:      "size",
+	Date:      "date",
+	Complete:  "complete",
+	Category:  "category",
+	IMDBId:    "imdb_id",
+	Season:    "season",
+	Episode:   "episode",
+	CAt:       "cat",
+	UAt:       "uat",
+}
+
+var columns = []string{
+	Column.Id,
+	Column.Name,
+	Column.Poster,
+	Column.GroupName,
+	Column.FileCount,
+	Column.Files,
+	Column.Size,
+	Column.Date,
+	Column.Complete,
+	Column.Category,
+	Column.IMDBId,
+	Column.Season,
+	Column.Episode,
+	Column.CAt,
+	Column.UAt,
+}
+
+const FileTableName = "usenet_indexer_file"
+
+type File struct {
+	Id           string
+	ReleaseId    string
+	Subject      string // without the segment counter
+	Name         string
+	Number       int
+	SegmentCount int
+	Poster       string
+	Date         db.Timestamp
+	Groups       string
+	CAt          db.Timestamp
+
+	Segments []Segment
+}
+
+var FileColumn = struct {
+	Id           string
+	ReleaseId    string
+	Subject      string
+	Name         string
+	Number       string
+	SegmentCount string
+	Poster       string
+	Date         string
+	Groups       string
+	CAt          string
+}{
+	Id:           "id",
+	ReleaseId:    "release_id",
+	Subject:      "subject",
+	Name:         "name",
+	Number:       "number",
+	SegmentCount: "segment_count",
+	Poster:       "poster",
+	Date:         "date",
+	Groups:       "groups",
+	CAt:          "cat",
+}
+
+var fileColumns = []string{
+	FileColumn.Id,
+	FileColumn.ReleaseId,
+	FileColumn.Subject,
+	FileColumn.Name,
+	FileColumn.Number,
+	FileColumn.SegmentCount,
+	FileColumn.Poster,
+	FileColumn.Date,
+	FileColumn.Groups,
+	FileColumn.CAt,
+}
+
+const SegmentTableName = "usenet_indexer_segment"
+
+type Segment struct {
+	FileId    string
+	Number    int
+	Bytes     int64
+	MessageId string
+}
+
+var SegmentColumn = struct {
+	FileId    string
+	Number    string
+	Bytes     string
+	MessageId string
+}{
+	FileId:    "file_id",
+	Number:    "number",
+	Bytes:     "bytes",
+	MessageId: "message_id",
+}
+
+var query_get_last_number = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s = ?`,
+	GroupColumn.LastNumber,
+	GroupTableName,
+	GroupColumn.ProviderId,
+	GroupColumn.Name,
+)
+
+func getLastNumber(providerId, group string) (int64, error) {
+	var lastNumber int64
+	err := db.QueryRow(query_get_last_number, providerId, group).Scan(&lastNumber)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	return lastNumber, nil
+}
+
+var query_set_last_number = fmt.Sprintf(
+	`INSERT INTO %s (%s, %s, %s) VALUES (?, ?, ?) ON CONFLICT (%s, %s) DO UPDATE SET %s = EXCLUDED.%s, %s = %s`,
+	GroupTableName,
+	GroupColumn.ProviderId,
+	GroupColumn.Name,
+	GroupColumn.LastNumber,
+	GroupColumn.ProviderId,
+	GroupColumn.Name,
+	GroupColumn.LastNumber, GroupColumn.LastNumber,
+	GroupColumn.UAt, db.CurrentTimestamp,
+)
+
+func setLastNumber(providerId, group string, lastNumber int64) error {
+	_, err := db.Exec(query_set_last_number, providerId, group, lastNumber)
+	return err
+}
+
+var query_insert_releases_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	TableName,
+	db.JoinColumnNames(Column.Id, Column.Name, Column.Poster, Column.GroupName, Column.FileCount, Column.Date),
+)
+var query_insert_releases_values_placeholder = "(?,?,?,?,?,?)"
+var query_insert_releases_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s = %s`,
+	Column.Id,
+	Column.UAt, db.CurrentTimestamp,
+)
+
+var query_insert_files_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	FileTableName,
+	db.JoinColumnNames(FileColumn.Id, FileColumn.ReleaseId, FileColumn.Subject, FileColumn.Name, FileColumn.Number, FileColumn.SegmentCount, FileColumn.Poster, FileColumn.Date, FileColumn.Groups),
+)
+var query_insert_files_values_placeholder = "(?,?,?,?,?,?,?,?,?)"
+var query_insert_files_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO NOTHING`,
+	FileColumn.Id,
+)
+
+var query_insert_segments_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	SegmentTableName,
+	db.JoinColumnNames(SegmentColumn.FileId, SegmentColumn.Number, SegmentColumn.Bytes, SegmentColumn.MessageId),
+)
+var query_insert_segments_values_placeholder = "(?,?,?,?)"
+var query_insert_segments_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s, %s) DO NOTHING`,
+	SegmentColumn.FileId,
+	SegmentColumn.Number,
+)
+
+func insert(releases []Release, files []File) error {
+	for cReleases := range slices.Chunk(releases, 500) {
+		args := make([]any, 0, len(cReleases)*6)
+		for i := range cReleases {
+			r := &cReleases[i]
+			args = append(args, r.Id, r.Name, r.Poster, r.GroupName, r.FileCount, r.Date)
+		}
+		query := query_insert_releases_before_values + util.RepeatJoin(query_insert_releases_values_placeholder, len(cReleases), ",") + query_insert_releases_after_values
+		if _, err := db.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	segments := []Segment{}
+	for cFiles := range slices.Chunk(files, 500) {
+		args := make([]any, 0, len(cFiles)*9)
+		for i := range cFiles {
+			f := &cFiles[i]
+			args = append(args, f.Id, f.ReleaseId, f.Subject, f.Name, f.Number, f.SegmentCount, f.Poster, f.Date, f.Groups)
+			segments = append(segments, f.Segments...)
+		}
+		query := query_insert_files_before_values + util.RepeatJoin(query_insert_files_values_placeholder, len(cFiles), ",") + query_insert_files_after_values
+		if _, err := db.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	for cSegments := range slices.Chunk(segments, 1000) {
+		args := make([]any, 0, len(cSegments)*4)
+		for i := range cSegments {
+			s := &cSegments[i]
+			args = append(args, s.FileId, s.Number, s.Bytes, s.MessageId)
+		}
+		query := query_insert_segments_before_values + util.RepeatJoin(query_insert_segments_values_placeholder, len(cSegments), ",") + query_insert_segments_after_values
+		if _, err := db.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanRelease(row interface{ Scan(dest ...any) error }) (*Release, error) {
+	r := Release{}
+	if err := row.Scan(
+		&r.Id,
+		&r.Name,
+		&r.Poster,
+		&r.GroupName,
+		&r.FileCount,
+		&r.Files,
+		&r.Size,
+		&r.Date,
+		&r.Complete,
+		&r.Category,
+		&r.IMDBId,
+		&r.Season,
+		&r.Episode,
+		&r.CAt,
+		&r.UAt,
+	); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+var query_get_by_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	db.JoinColumnNames(columns...),
+	TableName,
+	Column.Id,
+)
+
+func GetById(id string) (*Release, error) {
+	r, err := scanRelease(db.QueryRow(query_get_by_id, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+var query_get_by_ids = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s IN `,
+	db.JoinColumnNames(columns...),
+	TableName,
+	Column.Id,
+)
+
+func getByIds(ids []string) ([]Release, error) {
+	releases := []Release{}
+	for cIds := range slices.Chunk(ids, 500) {
+		args := make([]any, len(cIds))
+		for i, id := range cIds {
+			args[i] = id
+		}
+		rows, err := db.Query(query_get_by_ids+"("+util.RepeatJoin("?", len(cIds), ",")+")", args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			r, err := scanRelease(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			releases = append(releases, *r)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return releases, nil
+}
+
+type releaseProgress struct {
+	files    int
+	size     int64
+	complete bool
+}
+
+var query_get_file_progress = fmt.Sprintf(
+	`SELECT f.%s, f.%s, count(s.%s), coalesce(sum(s.%s), 0) FROM %s f LEFT JOIN %s s ON s.%s = f.%s WHERE f.%s IN `,
+	FileColumn.ReleaseId,
+	FileColumn.SegmentCount,
+	SegmentColumn.Number,
+	SegmentColumn.Bytes,
+	FileTableName,
+	SegmentTableName,
+	SegmentColumn.FileId,
+	FileColumn.Id,
+	FileColumn.ReleaseId,
+)
+var query_get_file_progress_group_by = fmt.Sprintf(
+	` GROUP BY f.%s, f.%s, f.%s`,
+	FileColumn.Id,
+	FileColumn.ReleaseId,
+	FileColumn.SegmentCount,
+)
+
+// getProgress sums up the files and segments seen so far for each of the
+// releases. The completeness against the expected file count is checked by
+// the caller.
+func getProgress(releaseIds []string) (map[string]*releaseProgress, error) {
+	progressById := make(map[string]*releaseProgress, len(releaseIds))
+	for cIds := range slices.Chunk(releaseIds, 500) {
+		args := make([]any, len(cIds))
+		for i, id := range cIds {
+			args[i] = id
+		}
+		query := query_get_file_progress + "(" + util.RepeatJoin("?", len(cIds), ",") + ")" + query_get_file_progress_group_by
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var releaseId string
+			var segmentCount, segments int
+			var bytes int64
+			if err := rows.Scan(&releaseId, &segmentCount, &segments, &bytes); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			p, ok := progressById[releaseId]
+			if !ok {
+				p = &releaseProgress{complete: true}
+				progressById[releaseId] = p
+			}
+			p.files++
+			p.size += bytes
+			if segments < segmentCount {
+				p.complete = false
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return progressById, nil
+}
+
+var query_update_progress = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = ?, %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.Files,
+	Column.Size,
+	Column.Complete,
+	Column.UAt, db.CurrentTimestamp,
+	Column.Id,
+)
+
+func updateProgress(r *Release) error {
+	_, err := db.Exec(query_update_progress, r.Files, r.Size, r.Complete, r.Id)
+	return err
+}
+
+var query_set_match = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = ?, %s = ?, %s = ? WHERE %s = ?`,
+	TableName,
+	Column.Category,
+	Column.IMDBId,
+	Column.Season,
+	Column.Episode,
+	Column.Id,
+)
+
+func setMatch(r *Release) error {
+	_, err := db.Exec(query_set_match, r.Category, r.IMDBId, r.Season, r.Episode, r.Id)
+	return err
+}
+
+var query_stale_release_ids = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = %s AND %s < ?`,
+	Column.Id,
+	TableName,
+	Column.Complete, db.BooleanFalse,
+	Column.UAt,
+)
+
+var query_delete_segments_by_release_ids = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s IN (SELECT %s FROM %s WHERE %s IN (%s))`,
+	SegmentTableName,
+	SegmentColumn.FileId,
+	FileColumn.Id,
+	FileTableName,
+	FileColumn.ReleaseId,
+	query_stale_release_ids,
+)
+
+var query_delete_files_by_release_ids = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s IN (%s)`,
+	FileTableName,
+	FileColumn.ReleaseId,
+	query_stale_release_ids,
+)
+
+var query_delete_stale_releases = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = %s AND %s < ?`,
+	TableName,
+	Column.Complete, db.BooleanFalse,
+	Column.UAt,
+)
+
+// deleteStale removes the releases that are still incomplete and have not
+// seen a new article since before.
+func deleteStale(before db.Timestamp) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query_delete_segments_by_release_ids, before); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(query_delete_files_by_release_ids, before); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(query_delete_stale_releases, before)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+var query_get_files_by_release_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s, %s`,
+	db.JoinColumnNames(fileColumns...),
+	FileTableName,
+	FileColumn.ReleaseId,
+	FileColumn.Number,
+	FileColumn.Name,
+)
+
+var query_get_segments_by_file_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s`,
+	db.JoinColumnNames(SegmentColumn.FileId, SegmentColumn.Number, SegmentColumn.Bytes, SegmentColumn.MessageId),
+	SegmentTableName,
+	SegmentColumn.FileId,
+	SegmentColumn.Number,
+)
+
+func getFiles(releaseId string) ([]File, error) {
+	rows, err := db.Query(query_get_files_by_release_id, releaseId)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{}
+	for rows.Next() {
+		f := File{}
+		if err := rows.Scan(&f.Id, &f.ReleaseId, &f.Subject, &f.Name, &f.Number, &f.SegmentCount, &f.Poster, &f.Date, &f.Groups, &f.CAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		f := &files[i]
+		rows, err := db.Query(query_get_segments_by_file_id, f.Id)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			s := Segment{}
+			if err := rows.Scan(&s.FileId, &s.Number, &s.Bytes, &s.MessageId); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			f.Segments = append(f.Segments, s)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type SearchParams struct {
+	Q          string
+	IMDBId     string
+	Season     string
+	Episode    string
+	Categories []int
+	Limit      int
+	Offset     int
+}
+
+var query_search = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = %s`,
+	db.JoinColumnNames(columns...),
+	TableName,
+	Column.Complete, db.BooleanTrue,
+)
+
+func Search(params SearchParams) ([]Release, error) {
+	var query strings.Builder
+	query.WriteString(query_search)
+	args := []any{}
+
+	if params.IMDBId != "" {
+		fmt.Fprintf(&query, " AND %s = ?", Column.IMDBId)
+		args = append(args, params.IMDBId)
+	}
+	if params.Season != "" {
+		fmt.Fprintf(&query, " AND %s = ?", Column.Season)
+		args = append(args, params.Season)
+		if params.Episode != "" {
+			// season packs have no episode
+			fmt.Fprintf(&query, " AND (%s = ? OR %s = '')", Column.Episode, Column.Episode)
+			args = append(args, params.Episode)
+		}
+	}
+	for term := range strings.FieldsSeq(strings.ToLower(params.Q)) {
+		fmt.Fprintf(&query, " AND lower(%s) LIKE ?", Column.Name)
+		args = append(args, "%"+term+"%")
+	}
+	if len(params.Categories) > 0 {
+		fmt.Fprintf(&query, " AND %s IN (%s)", Column.Category, util.RepeatJoin("?", len(params.Categories), ","))
+		for _, cat := range params.Categories {
+			args = append(args, cat)
+		}
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	fmt.Fprintf(&query, " ORDER BY %s DESC LIMIT %d OFFSET %d", Column.Date, limit, max(params.Offset, 0))
+
+	rows, err := db.Query(query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	releases := []Release{}
+	for rows.Next() {
+		r, err := scanRelease(rows)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, *r)
+	}
+	return releases, rows.Err()
+}
@@ -0,0 +1,5 @@
+package usenet_indexer
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("usenet/indexer")
@@ -0,0 +1,49 @@
+package usenet_indexer
+
+import (
+	"strconv"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/newznab"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+// match sets the category, and the imdb id, season and episode of the
+// release, based on its name.
+func match(r *Release) error {
+	pttr, err := util.ParseTorrentTitle(r.Name)
+	if err != nil {
+		return err
+	}
+
+	titleType := imdb_title.SearchTitleTypeMovie
+	r.Category = newznab.CategoryMovies.ID
+	if len(pttr.Seasons) > 0 || len(pttr.Episodes) > 0 {
+		titleType = imdb_title.SearchTitleTypeShow
+		r.Category = newznab.CategoryTV.ID
+		if len(pttr.Seasons) > 0 {
+			r.Season = strconv.Itoa(pttr.Seasons[0])
+		}
+		if len(pttr.Episodes) == 1 {
+			r.Episode = strconv.Itoa(pttr.Episodes[0])
+		}
+	}
+
+	if pttr.Title == "" || !config.Feature.HasIMDBTitle() {
+		return nil
+	}
+
+	year := util.SafeParseInt(pttr.Year, 0)
+	title, err := imdb_title.SearchOne(pttr.Title, titleType, year, false)
+	if err != nil {
+		return err
+	}
+	if title != nil {
+		if titleType == imdb_title.SearchTitleTypeShow && !imdb_title.IMDBTitleType(title.Type).IsShow() {
+			return nil
+		}
+		r.IMDBId = title.TId
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package usenet_indexer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+)
+
+const nzbDoctype = `<!DOCTYPE nzb PUBLIC "-//newzBin//DTD NZB 1.1//EN" "http://www.newzbin.com/DTD/nzb/nzb-1.1.dtd">` + "\n"
+
+func buildNZB(r *Release, files []File) ([]byte, error) {
+	doc := nzb.NZB{
+		Head: &nzb.Head{
+			Meta: []nzb.Meta{{Type: "name", Value: r.Name}},
+		},
+		Files: make([]nzb.File, 0, len(files)),
+	}
+	for i := range files {
+		f := &files[i]
+		file := nzb.File{
+			Poster:   f.Poster,
+			Date:     f.Date.Unix(),
+			Subject:  f.Subject,
+			Groups:   strings.Split(f.Groups, ","),
+			Segments: make([]nzb.Segment, 0, len(f.Segments)),
+		}
+		for _, s := range f.Segments {
+			file.Segments = append(file.Segments, nzb.Segment{
+				Bytes:     s.Bytes,
+				Number:    s.Number,
+				MessageId: s.MessageId,
+			})
+		}
+		doc.Files = append(doc.Files, file)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(nzbDoctype)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetNZBFile generates the nzb file for a complete release. It returns nil
+// if the release is not found.
+func GetNZBFile(id string) (*nzb_info.NZBFile, error) {
+	r, err := GetById(id)
+	if err != nil || r == nil || !r.Complete {
+		return nil, err
+	}
+	files, err := getFiles(r.Id)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := buildNZB(r, files)
+	if err != nil {
+		return nil, err
+	}
+	return &nzb_info.NZBFile{
+		Blob: blob,
+		Name: r.Name + ".nzb",
+		Mod:  r.Date.Time,
+	}, nil
+}
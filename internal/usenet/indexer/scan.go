@@ -0,0 +1,145 @@
+package usenet_indexer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+)
+
+const (
+	overBatchSize = 20000
+	// incomplete releases without new articles for this long are dropped
+	staleAfter = 3 * 24 * time.Hour
+)
+
+// Scan fetches the new article headers of the configured groups, and
+// assembles them into releases. The first scan of a group on a provider
+// starts from the last `config.Newz.ScanBackfill` articles.
+func Scan(ctx context.Context, log *logger.Logger) error {
+	pool, err := usenetmanager.GetPool()
+	if err != nil {
+		return err
+	}
+
+	for _, group := range config.Newz.ScanGroups {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		conn, err := pool.GetConnection(ctx, nil, 9, false)
+		if err != nil {
+			return err
+		}
+		err = scanGroup(conn, group, log)
+		if err != nil {
+			conn.Destroy()
+			log.Error("failed to scan group", "error", err, "group", group)
+			continue
+		}
+		conn.Release()
+	}
+
+	count, err := deleteStale(db.Timestamp{Time: time.Now().Add(-staleAfter)})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Info("deleted stale releases", "count", count)
+	}
+	return nil
+}
+
+func scanGroup(conn *nntp.PooledConnection, group string, log *logger.Logger) error {
+	g, err := conn.Group(group)
+	if err != nil {
+		return err
+	}
+
+	providerId := conn.ProviderId()
+	lastNumber, err := getLastNumber(providerId, group)
+	if err != nil {
+		return err
+	}
+
+	from := lastNumber + 1
+	if lastNumber == 0 || from < g.Low {
+		from = max(g.Low, g.High-int64(config.Newz.ScanBackfill)+1)
+	}
+
+	for from <= g.High {
+		to := min(from+overBatchSize-1, g.High)
+		overviews, err := conn.Over(strconv.FormatInt(from, 10) + "-" + strconv.FormatInt(to, 10))
+		if err != nil {
+			return err
+		}
+
+		a := newAssembler(group)
+		for i := range overviews {
+			a.add(&overviews[i])
+		}
+		releases, files := a.result()
+		if err := insert(releases, files); err != nil {
+			return err
+		}
+		if err := refresh(releases); err != nil {
+			return err
+		}
+		if err := setLastNumber(providerId, group, to); err != nil {
+			return err
+		}
+
+		log.Info("scanned group", "group", group, "provider_id", providerId, "from", from, "to", to, "articles", len(overviews), "releases", len(releases))
+		from = to + 1
+	}
+	return nil
+}
+
+// refresh updates the progress of the releases, and matches the ones that
+// just got complete.
+func refresh(releases []Release) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(releases))
+	for i := range releases {
+		ids[i] = releases[i].Id
+	}
+	progressById, err := getProgress(ids)
+	if err != nil {
+		return err
+	}
+	current, err := getByIds(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range current {
+		r := &current[i]
+		p, ok := progressById[r.Id]
+		if !ok || r.Complete {
+			continue
+		}
+		r.Files = p.files
+		r.Size = p.size
+		r.Complete = p.complete && (r.FileCount == 0 || p.files >= r.FileCount)
+		if err := updateProgress(r); err != nil {
+			return err
+		}
+		if r.Complete {
+			if err := match(r); err != nil {
+				log.Warn("failed to match release", "error", err, "name", r.Name)
+			}
+			if err := setMatch(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
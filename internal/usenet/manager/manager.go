@@ -250,6 +250,11 @@ func (m *Manager) createPoolFromServers(servers []usenet_server.UsenetServer) (*
 			m.log.Warn("failed to decrypt password", "server", s.Name, "error", err)
 			continue
 		}
+		proxy, err := s.GetProxyURL()
+		if err != nil {
+			m.log.Warn("failed to get proxy", "server", s.Name, "error", err)
+			continue
+		}
 
 		providers = append(providers, usenet_pool.ProviderConfig{
 			PoolConfig: nntp.PoolConfig{
@@ -260,6 +265,8 @@ func (m *Manager) createPoolFromServers(servers []usenet_server.UsenetServer) (*
 					Password:      password,
 					TLS:           s.TLS,
 					TLSSkipVerify: s.TLSSkipVerify,
+					Compress:      config.Newz.Flag.NNTPCompress,
+					Proxy:         proxy,
 				},
 				MaxSize: int32(s.MaxConnections),
 			},
@@ -285,6 +292,10 @@ func (m *Manager) createProviderConfig(server *usenet_server.UsenetServer) (*use
 	if err != nil {
 		return nil, err
 	}
+	proxy, err := server.GetProxyURL()
+	if err != nil {
+		return nil, err
+	}
 
 	return &usenet_pool.ProviderConfig{
 		PoolConfig: nntp.PoolConfig{
@@ -295,6 +306,8 @@ func (m *Manager) createProviderConfig(server *usenet_server.UsenetServer) (*use
 				Password:      password,
 				TLS:           server.TLS,
 				TLSSkipVerify: server.TLSSkipVerify,
+				Compress:      config.Newz.Flag.NNTPCompress,
+				Proxy:         proxy,
 			},
 			MaxSize: int32(server.MaxConnections),
 		},
@@ -20,19 +20,31 @@ var (
 )
 
 type subjectParser struct {
-	fileCount      int
-	fileIndexRegex *regexp.Regexp
+	fileCount    int
+	fileCountStr string
 }
 
+// newSubjectParser is cheap, it is called for every article subject: the
+// file index is matched with the shared fileCounterRegex.
 func newSubjectParser(fileCount int) *subjectParser {
-	fileIndexRegex := regexp.MustCompile(`[(\[]\s*(\d+)\s*/\s*0?` + util.IntToString(fileCount) + `\s*[\])]`)
 	p := subjectParser{
-		fileCount:      fileCount,
-		fileIndexRegex: fileIndexRegex,
+		fileCount:    fileCount,
+		fileCountStr: util.IntToString(fileCount),
 	}
 	return &p
 }
 
+// matchFileIndex finds the first file counter with the parser's file count,
+// optionally zero-padded by one digit, e.g. [3/12] or (03/012).
+func (p *subjectParser) matchFileIndex(subject string) []string {
+	for _, matches := range fileCounterRegex.FindAllStringSubmatch(subject, -1) {
+		if count := matches[2]; count == p.fileCountStr || count == "0"+p.fileCountStr {
+			return matches[:2]
+		}
+	}
+	return nil
+}
+
 func (p *subjectParser) Parse(f *File) {
 	subject := f.Subject
 
@@ -102,7 +114,7 @@ func (p *subjectParser) Parse(f *File) {
 	}
 
 	if p.fileCount > 0 && f.number == 0 {
-		if matches := p.fileIndexRegex.FindStringSubmatch(subject); len(matches) == 2 {
+		if matches := p.matchFileIndex(subject); len(matches) == 2 {
 			f.number = util.SafeParseInt(matches[1], 0)
 			subject = strings.TrimSpace(strings.Replace(subject, matches[0], "", 1))
 		}
@@ -112,3 +124,41 @@ func (p *subjectParser) Parse(f *File) {
 		f.name = subject
 	}
 }
+
+var (
+	segmentCounterRegex = regexp.MustCompile(`\((\d+)\s*/\s*(\d+)\)\s*$`)
+	fileCounterRegex    = regexp.MustCompile(`[(\[]\s*(\d+)\s*/\s*(\d+)\s*[\])]`)
+)
+
+// Subject is the parsed subject of a single article of a binary post.
+type Subject struct {
+	Name          string
+	Number        int
+	FileCount     int
+	Prefix        string // text before the file counter
+	Base          string // subject without the segment counter, same for every segment of the file
+	SegmentNumber int
+	SegmentCount  int
+}
+
+func ParseSubject(subject string) Subject {
+	s := Subject{Base: strings.TrimSpace(subject)}
+
+	if loc := segmentCounterRegex.FindStringSubmatchIndex(s.Base); loc != nil {
+		s.SegmentNumber = util.SafeParseInt(s.Base[loc[2]:loc[3]], 0)
+		s.SegmentCount = util.SafeParseInt(s.Base[loc[4]:loc[5]], 0)
+		s.Base = strings.TrimSpace(s.Base[:loc[0]])
+	}
+
+	if loc := fileCounterRegex.FindStringSubmatchIndex(s.Base); loc != nil {
+		s.FileCount = util.SafeParseInt(s.Base[loc[4]:loc[5]], 0)
+		s.Prefix = strings.TrimSpace(s.Base[:loc[0]])
+	}
+
+	f := File{Subject: subject}
+	newSubjectParser(s.FileCount).Parse(&f)
+	s.Name = f.name
+	s.Number = f.number
+
+	return s
+}
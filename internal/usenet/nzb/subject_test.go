@@ -19,6 +19,12 @@ func TestSubjectParser(t *testing.T) {
 			1,
 			"618ee7f37097e26dbb27464aac1243dc",
 		},
+		{
+			5,
+			`"Show.S01E03.mkv" [1/50] yEnc (03/05) (1/10)`,
+			3,
+			"Show.S01E03.mkv",
+		},
 		{
 			0,
 			`[N3wZ] \V7JRL9192688\::bdb28593ae0331ab0d1f5c039390b676 yEnc (1/147)`,
@@ -258,3 +264,48 @@ func TestSubjectParser(t *testing.T) {
 		assert.Equal(t, tc.name, f.name)
 	}
 }
+
+func TestParseSubject(t *testing.T) {
+	for _, tc := range []struct {
+		subject string
+		expect  Subject
+	}{
+		{
+			`[1/52] - "618ee7f37097e26dbb27464aac1243dc" yEnc  524288000 (1/732)`,
+			Subject{
+				Name:          "618ee7f37097e26dbb27464aac1243dc",
+				Number:        1,
+				FileCount:     52,
+				Prefix:        "",
+				Base:          `[1/52] - "618ee7f37097e26dbb27464aac1243dc" yEnc  524288000`,
+				SegmentNumber: 1,
+				SegmentCount:  732,
+			},
+		},
+		{
+			`Some.Movie.2020.1080p.BluRay.x264-GRP [03/25] - "Some.Movie.2020.1080p.BluRay.x264-GRP.part02.rar" yEnc (12/137)`,
+			Subject{
+				Name:          "Some.Movie.2020.1080p.BluRay.x264-GRP.part02.rar",
+				Number:        3,
+				FileCount:     25,
+				Prefix:        "Some.Movie.2020.1080p.BluRay.x264-GRP",
+				Base:          `Some.Movie.2020.1080p.BluRay.x264-GRP [03/25] - "Some.Movie.2020.1080p.BluRay.x264-GRP.part02.rar" yEnc`,
+				SegmentNumber: 12,
+				SegmentCount:  137,
+			},
+		},
+		{
+			`MythBusters.S01E08.Buried.Alive.720p.HEVC.x265.mkv - 076 of 194 - All 15 seasons being seeded yEnc(1/1150)`,
+			Subject{
+				Name:          "MythBusters.S01E08.Buried.Alive.720p.HEVC.x265.mkv",
+				Base:          `MythBusters.S01E08.Buried.Alive.720p.HEVC.x265.mkv - 076 of 194 - All 15 seasons being seeded yEnc`,
+				SegmentNumber: 1,
+				SegmentCount:  1150,
+			},
+		},
+	} {
+		t.Run(tc.subject, func(t *testing.T) {
+			assert.Equal(t, tc.expect, ParseSubject(tc.subject))
+		})
+	}
+}
@@ -27,6 +27,7 @@ var Column = struct {
 	Status         string
 	IndexerId      string
 	InspectionMeta string
+	Health         string
 	CAt            string
 	UAt            string
 }{
@@ -44,6 +45,7 @@ var Column = struct {
 	Status:         "status",
 	IndexerId:      "indexer_id",
 	InspectionMeta: "inspection_meta",
+	Health:         "health",
 	CAt:            "cat",
 	UAt:            "uat",
 }
@@ -63,6 +65,7 @@ var columns = []string{
 	Column.Status,
 	Column.IndexerId,
 	Column.InspectionMeta,
+	Column.Health,
 	Column.CAt,
 	Column.UAt,
 }
@@ -87,6 +90,7 @@ type NZBInfo struct {
 	Status         string
 	IndexerId      sql.NullInt64
 	InspectionMeta db.JSONB[NZBInfoInspectionMeta]
+	Health         db.JSONB[usenet_pool.NZBHealth]
 	CAt            db.Timestamp
 	UAt            db.Timestamp
 }
@@ -156,7 +160,7 @@ var query_get_by_id = fmt.Sprintf(
 func GetById(id string) (*NZBInfo, error) {
 	row := db.QueryRow(query_get_by_id, id)
 	info := NZBInfo{}
-	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.CAt, &info.UAt); err != nil {
+	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.Health, &info.CAt, &info.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -175,7 +179,7 @@ var query_get_by_hash = fmt.Sprintf(
 func GetByHash(hash string) (*NZBInfo, error) {
 	row := db.QueryRow(query_get_by_hash, hash)
 	info := NZBInfo{}
-	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.CAt, &info.UAt); err != nil {
+	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.Health, &info.CAt, &info.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -212,7 +216,7 @@ func GetByHashes(hashes []string) (map[string]*NZBInfo, error) {
 
 	for rows.Next() {
 		info := NZBInfo{}
-		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.CAt, &info.UAt); err != nil {
+		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.Health, &info.CAt, &info.UAt); err != nil {
 			return nil, err
 		}
 		byHash[info.Hash] = &info
@@ -242,7 +246,7 @@ func GetAll() ([]NZBInfo, error) {
 	infos := []NZBInfo{}
 	for rows.Next() {
 		info := NZBInfo{}
-		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.CAt, &info.UAt); err != nil {
+		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.IndexerId, &info.InspectionMeta, &info.Health, &info.CAt, &info.UAt); err != nil {
 			return nil, err
 		}
 		infos = append(infos, info)
@@ -255,6 +259,19 @@ func GetAll() ([]NZBInfo, error) {
 	return infos, nil
 }
 
+var query_set_health = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.Health,
+	Column.UAt, db.CurrentTimestamp,
+	Column.Hash,
+)
+
+func SetHealth(hash string, health *usenet_pool.NZBHealth) error {
+	_, err := db.Exec(query_set_health, db.JSONB[usenet_pool.NZBHealth]{Data: *health}, hash)
+	return err
+}
+
 var query_update_hash = fmt.Sprintf(
 	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
 	TableName,
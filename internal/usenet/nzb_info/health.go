@@ -0,0 +1,92 @@
+package nzb_info
+
+import (
+	"context"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"golang.org/x/sync/singleflight"
+)
+
+// healthCheckTimeout is for the health check of a search result, including
+// the NZB fetch.
+const healthCheckTimeout = 1 * time.Minute
+
+// healthCache keeps the health of the NZBs checked without being processed,
+// e.g. the search results. The health of the processed ones is on nzb_info.
+var healthCache = cache.NewCache[usenet_pool.NZBHealth](&cache.CacheConfig{
+	Name:     "newz:nzb-health",
+	Lifetime: 24 * time.Hour,
+	Persist:  true,
+})
+
+var healthCheckSG singleflight.Group
+
+// GetHealthByHashes returns the health of the NZBs, from nzb_info or the
+// health checks of the unprocessed ones. NZBs not checked yet are missing.
+func GetHealthByHashes(hashes []string) (map[string]*usenet_pool.NZBHealth, error) {
+	infoByHash, err := GetByHashes(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	healthByHash := make(map[string]*usenet_pool.NZBHealth, len(hashes))
+	for _, hash := range hashes {
+		if info, ok := infoByHash[hash]; ok && !info.Health.Null {
+			healthByHash[hash] = &info.Health.Data
+			continue
+		}
+		health := usenet_pool.NZBHealth{}
+		if healthCache.Get(hash, &health) {
+			healthByHash[hash] = &health
+		}
+	}
+	return healthByHash, nil
+}
+
+// CheckHealth fetches the NZB of the link and checks its health, without
+// processing it. The result is kept for GetHealthByHashes.
+func CheckHealth(link string, name string, log *logger.Logger) (*usenet_pool.NZBHealth, error) {
+	hash := util.HashNZBFileLink(link)
+	health, err, _ := healthCheckSG.Do(hash, func() (any, error) {
+		pool, err := usenetmanager.GetPool()
+		if err != nil {
+			return nil, err
+		}
+		if pool == nil {
+			return nil, usenet_pool.ErrNoProvidersAvailable
+		}
+
+		nzbFile, err := FetchNZBFile(link, name, log)
+		if err != nil {
+			return nil, err
+		}
+		nzbDoc, err := nzb.ParseBytes(nzbFile.Blob)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, usenet_pool.NZBHashContextKey, hash)
+
+		health, err := pool.CheckNZBHealth(ctx, nzbDoc, config.Newz.HealthCheckSampleSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := healthCache.Add(hash, *health); err != nil {
+			log.Warn("failed to cache nzb health", "error", err, "hash", hash)
+		}
+		return health, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return health.(*usenet_pool.NZBHealth), nil
+}
@@ -2,6 +2,8 @@ package nzb_info
 
 import (
 	"bytes"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -15,6 +17,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/logger"
 	newznab_indexer "github.com/MunifTanjim/stremthru/internal/newznab/indexer"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"golang.org/x/sync/singleflight"
 )
@@ -81,11 +84,51 @@ func GetCachedNZBFile(hash string) *NZBFile {
 	return nil
 }
 
-var nzbFetchErrCache = cache.NewCache[string](&cache.CacheConfig{
+// FetchError is the non-success response from the nzb link.
+type FetchError struct {
+	StatusCode int
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("failed to fetch nzb: status %d", e.StatusCode)
+}
+
+// IsPermanentFetchError reports whether the nzb link is dead, i.e. fetching
+// it again will not help.
+func IsPermanentFetchError(err error) bool {
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		return false
+	}
+	return fetchErr.StatusCode == http.StatusNotFound || fetchErr.StatusCode == http.StatusGone
+}
+
+type nzbFetchFailure struct {
+	Error      string `json:"error"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+func (f nzbFetchFailure) toError() error {
+	if f.StatusCode != 0 {
+		return fmt.Errorf("cached failure: %w", &FetchError{StatusCode: f.StatusCode})
+	}
+	return fmt.Errorf("cached failure: %s", f.Error)
+}
+
+var nzbFetchErrCache = cache.NewCache[nzbFetchFailure](&cache.CacheConfig{
 	Name:     "newz_nzb_fetch_failure",
 	Lifetime: 5 * time.Minute,
 })
 
+// GetFetchError returns the recent failure of fetching the nzb link, if any.
+func GetFetchError(link string) error {
+	failure := nzbFetchFailure{}
+	if nzbFetchErrCache.Get(util.HashNZBFileLink(link), &failure) {
+		return failure.toError()
+	}
+	return nil
+}
+
 func RehashIfNeeded(info *NZBInfo) error {
 	newHash := util.HashNZBFileLink(info.URL)
 	if info.Hash == newHash {
@@ -136,11 +179,11 @@ func fetchNZBFile(link string, name string, log *logger.Logger, opts *fetchOptio
 		if log != nil {
 			log.Debug("fetch nzb - cache hit", "link", clink)
 		}
-	} else if fetchErr := ""; nzbFetchErrCache.Get(cacheKey, &fetchErr) {
+	} else if failure := (nzbFetchFailure{}); nzbFetchErrCache.Get(cacheKey, &failure) {
 		if log != nil {
 			log.Debug("fetch nzb - cached failure", "link", clink)
 		}
-		return nil, fmt.Errorf("cached failure: %s", fetchErr)
+		return nil, failure.toError()
 	} else {
 		if log != nil {
 			log.Debug("fetch nzb - cache miss", "link", clink)
@@ -154,7 +197,11 @@ func fetchNZBFile(link string, name string, log *logger.Logger, opts *fetchOptio
 					opts.onFetched(file, err, time.Since(startTime))
 				}
 				if err != nil {
-					if cacheErr := nzbFetchErrCache.Add(cacheKey, err.Error()); cacheErr != nil && log != nil {
+					failure := nzbFetchFailure{Error: err.Error()}
+					if fetchErr := (*FetchError)(nil); errors.As(err, &fetchErr) {
+						failure.StatusCode = fetchErr.StatusCode
+					}
+					if cacheErr := nzbFetchErrCache.Add(cacheKey, failure); cacheErr != nil && log != nil {
 						log.Warn("fetch nzb - failed to cache failure", "error", cacheErr, "link", clink)
 					}
 				}
@@ -172,7 +219,7 @@ func fetchNZBFile(link string, name string, log *logger.Logger, opts *fetchOptio
 			defer res.Body.Close()
 
 			if res.StatusCode < 200 || 300 <= res.StatusCode {
-				return nil, fmt.Errorf("failed to fetch nzb: status %d", res.StatusCode)
+				return nil, &FetchError{StatusCode: res.StatusCode}
 			}
 
 			if res.ContentLength > config.Newz.NZBFileMaxSize {
@@ -260,6 +307,59 @@ func CacheNZBFile(hash string, file NZBFile) error {
 	return nzbFileCache.Add(hash, file)
 }
 
+// AddNZBFile caches the uploaded nzb file for the user, and queues it for
+// processing. Returns the queue job id.
+func AddNZBFile(user, filename, name string, blob []byte) (string, error) {
+	nzbDoc, err := nzb.ParseBytes(blob)
+	if err != nil {
+		return "", err
+	}
+
+	nzbId := nzbDoc.HashByFileBoundarySegmentIds()
+	link := config.BaseURL.JoinPath("/v0/newznab/getnzb/", nzbId)
+	linkQuery := link.Query()
+	apikey := util.Base64Encode(user + ":" + config.Auth.GetPassword(user))
+	linkQuery.Set("apikey", apikey)
+	link.RawQuery = linkQuery.Encode()
+
+	if !strings.HasSuffix(filename, ".nzb") {
+		filename += ".nzb"
+	}
+
+	nzbFile := NZBFile{
+		Blob: blob,
+		Name: filename,
+		Link: link.String(),
+		Mod:  time.Now(),
+	}
+
+	hash := util.HashNZBFileLink(nzbFile.Link)
+	if err := CacheNZBFile(hash, nzbFile); err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		name = filename
+	}
+
+	if err := Upsert(&NZBInfo{
+		Id:        nzbId,
+		Hash:      hash,
+		Name:      name,
+		Size:      nzbDoc.TotalSize(),
+		FileCount: nzbDoc.FileCount(),
+		Password:  "",
+		URL:       nzbFile.Link,
+		User:      user,
+		Status:    "queued",
+		IndexerId: sql.NullInt64{Valid: false},
+	}); err != nil {
+		return "", err
+	}
+
+	return QueueJob(user, name, nzbFile.Link, "", 0, "", 0)
+}
+
 func DeleteNZBFile(link string) {
 	cacheKey := util.HashNZBFileLink(link)
 	nzbFileCache.Remove(cacheKey)
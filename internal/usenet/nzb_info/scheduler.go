@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/job"
 	"github.com/MunifTanjim/stremthru/internal/logger"
@@ -110,6 +111,15 @@ var scheduler = job.NewScheduler(&job.SchedulerConfig[JobData]{
 				return err
 			}
 			inspectCtx := context.WithValue(context.Background(), usenet_pool.NZBHashContextKey, hash)
+
+			if sampleSize := config.Newz.HealthCheckSampleSize; sampleSize > 0 {
+				if health, err := pool.CheckNZBHealth(inspectCtx, nzbDoc, sampleSize); err != nil {
+					log.Warn("failed to check nzb health", "error", err)
+				} else if err := SetHealth(hash, health); err != nil {
+					log.Warn("failed to save nzb health", "error", err)
+				}
+			}
+
 			inspectStart := time.Now()
 			content, err := pool.InspectNZBContent(inspectCtx, nzbDoc, password)
 			durationMs := float64(time.Since(inspectStart).Microseconds()) / 1000.0
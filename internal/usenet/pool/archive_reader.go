@@ -0,0 +1,224 @@
+package usenet_pool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+var (
+	_ io.ReaderAt       = (*multiVolumeReader)(nil)
+	_ io.ReadSeekCloser = (*extentsReader)(nil)
+)
+
+// multiVolumeReader reads the volumes of a split archive as one contiguous
+// file. Only one volume is kept open at a time, and reads at the current
+// position do not seek, so sequential reads are served by the read-ahead of
+// the underlying file.
+type multiVolumeReader struct {
+	fsys    fs.FS
+	names   []string
+	offsets []int64 // start offset of each volume
+	sizes   []int64
+	size    int64
+
+	mu     sync.Mutex
+	idx    int // index of the open volume
+	file   fs.File
+	volPos int64
+}
+
+func openMultiVolumeReader(fsys fs.FS, names []string) (*multiVolumeReader, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no volumes")
+	}
+	r := &multiVolumeReader{
+		fsys:    fsys,
+		names:   names,
+		offsets: make([]int64, len(names)),
+		sizes:   make([]int64, len(names)),
+		idx:     -1,
+	}
+	for i, name := range names {
+		fi, err := fs.Stat(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		r.offsets[i] = r.size
+		r.sizes[i] = fi.Size()
+		r.size += fi.Size()
+	}
+	return r, nil
+}
+
+func (r *multiVolumeReader) Size() int64 {
+	return r.size
+}
+
+// Offset returns the start offset of the volume.
+func (r *multiVolumeReader) Offset(volume int) (int64, error) {
+	if volume < 0 || volume >= len(r.offsets) {
+		return 0, fmt.Errorf("volume %d out of range [0, %d)", volume, len(r.offsets))
+	}
+	return r.offsets[volume], nil
+}
+
+func (r *multiVolumeReader) seek(idx int, volOffset int64) error {
+	if r.idx != idx {
+		if r.file != nil {
+			r.file.Close()
+			r.file = nil
+		}
+		f, err := r.fsys.Open(r.names[idx])
+		if err != nil {
+			return err
+		}
+		r.file = f
+		r.idx = idx
+		r.volPos = 0
+	}
+	if r.volPos != volOffset {
+		seeker, ok := r.file.(io.Seeker)
+		if !ok {
+			return fmt.Errorf("volume %s is not seekable", r.names[idx])
+		}
+		if _, err := seeker.Seek(volOffset, io.SeekStart); err != nil {
+			return err
+		}
+		r.volPos = volOffset
+	}
+	return nil
+}
+
+func (r *multiVolumeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for n < len(p) && off < r.size {
+		idx := 0
+		for idx < len(r.offsets)-1 && off >= r.offsets[idx]+r.sizes[idx] {
+			idx++
+		}
+		volOffset := off - r.offsets[idx]
+		if err := r.seek(idx, volOffset); err != nil {
+			return n, err
+		}
+		want := int(min(int64(len(p)-n), r.sizes[idx]-volOffset))
+		m, err := io.ReadFull(r.file, p[n:n+want])
+		n += m
+		off += int64(m)
+		r.volPos += int64(m)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *multiVolumeReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	r.idx = -1
+	return err
+}
+
+type fileExtent struct {
+	offset int64 // in the underlying reader
+	size   int64
+	sparse bool // not recorded, reads as zeros
+}
+
+// extentsReader reads a file stored in one or more extents of the underlying
+// reader.
+type extentsReader struct {
+	r       io.ReaderAt
+	closer  io.Closer
+	extents []fileExtent
+	size    int64
+	pos     int64
+}
+
+func newExtentsReader(r io.ReaderAt, closer io.Closer, extents []fileExtent) *extentsReader {
+	er := &extentsReader{r: r, closer: closer, extents: extents}
+	for _, e := range extents {
+		er.size += e.size
+	}
+	return er
+}
+
+func (er *extentsReader) Read(p []byte) (int, error) {
+	if er.pos >= er.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	start := int64(0)
+	for _, e := range er.extents {
+		if er.pos >= start+e.size {
+			start += e.size
+			continue
+		}
+
+		inner := er.pos - start
+		k := min(int64(len(p)), e.size-inner)
+		if e.sparse {
+			clear(p[:k])
+			er.pos += k
+			return int(k), nil
+		}
+		n, err := er.r.ReadAt(p[:k], e.offset+inner)
+		er.pos += int64(n)
+		if err == io.EOF && int64(n) == k {
+			err = nil
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+func (er *extentsReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = er.pos + offset
+	case io.SeekEnd:
+		pos = er.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	er.pos = pos
+	return pos, nil
+}
+
+func (er *extentsReader) Close() error {
+	if er.closer != nil {
+		return er.closer.Close()
+	}
+	return nil
+}
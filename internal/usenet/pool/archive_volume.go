@@ -16,7 +16,7 @@ type archiveVolume struct {
 type archiveVolumeGroup[T any] struct {
 	BaseName  string   // e.g., "video" for video.part01.rar, video.part02.rar
 	Aliased   bool     // no standard archive extension
-	FileType  FileType // RAR or 7z
+	FileType  FileType // RAR, 7z, ZIP or ISO
 	Files     []T
 	Volumes   []int
 	TotalSize int64
@@ -112,6 +112,22 @@ func GetArchiveBaseName(filename string) (baseName string, fileType FileType) {
 		return filename[:len(filename)-len(matches[0])], FileType7z
 	}
 
+	if matches := zipPartNumberRegex.FindStringSubmatch(lower); len(matches) > 0 {
+		return filename[:len(filename)-len(matches[0])], FileTypeZIP
+	}
+
+	if matches := zipZNumberRegex.FindStringSubmatch(lower); len(matches) > 0 {
+		return filename[:len(filename)-len(matches[0])], FileTypeZIP
+	}
+
+	if matches := zipFirstPartRegex.FindStringSubmatch(lower); len(matches) > 0 {
+		return filename[:len(filename)-len(matches[0])], FileTypeZIP
+	}
+
+	if matches := isoRegex.FindStringSubmatch(lower); len(matches) > 0 {
+		return filename[:len(filename)-len(matches[0])], FileTypeISO
+	}
+
 	return "", FileTypePlain
 }
 
@@ -130,6 +146,11 @@ func isFirstVolume(fileType FileType, vol int) bool {
 		return vol == 0
 	case FileType7z:
 		return vol == 1
+	case FileTypeZIP:
+		// any volume can be used to open the archive
+		return vol == 0 || vol == 1
+	case FileTypeISO:
+		return vol == 0
 	default:
 		return false
 	}
@@ -160,6 +181,10 @@ func getFileVolume[T simpleFile](f T, fileType FileType) int {
 		return GetRARVolumeNumber(name)
 	case FileType7z:
 		return Get7zVolumeNumber(name)
+	case FileTypeZIP:
+		return GetZIPVolumeNumber(name)
+	case FileTypeISO:
+		return 0
 	default:
 		return -1
 	}
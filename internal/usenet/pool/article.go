@@ -0,0 +1,260 @@
+package usenet_pool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	usenet_stats "github.com/MunifTanjim/stremthru/internal/usenet/stats"
+)
+
+// ArticleData is an article as received from a provider.
+type ArticleData struct {
+	Headers textproto.MIMEHeader
+	// dot-stuffed body, including the dot-terminator line
+	Body []byte
+}
+
+// withArticle calls fn with connections from the providers, in the same
+// order used for fetching segments, until it succeeds. The size returned by
+// fn is recorded as fetched bytes.
+func (p *Pool) withArticle(ctx context.Context, messageId string, fn func(conn *nntp.PooledConnection) (int64, error)) error {
+	nzbHash, _ := ctx.Value(NZBHashContextKey).(string)
+
+	errs := []error{}
+	useBackup := false
+	priorities := p.getProviderPriorities(useBackup)
+	priorityIdx := 0
+	currPriority := 0
+	if len(priorities) > 0 {
+		currPriority = priorities[0]
+	}
+
+	var anyArticleNotFound bool
+
+	excluder := newProviderExcluder(len(p.providers))
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		conn, err := p.GetConnection(ctx, excluder, currPriority, useBackup)
+		if errors.Is(err, ErrNoProvidersAvailable) && excluder.triedProviderCount() > 0 {
+			excluder.clearTried()
+			conn, err = p.GetConnection(ctx, excluder, currPriority, useBackup)
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			if errors.Is(err, ErrNoProvidersAvailable) {
+				if priorityIdx+1 < len(priorities) {
+					priorityIdx++
+					currPriority = priorities[priorityIdx]
+					continue
+				}
+				if !useBackup && anyArticleNotFound {
+					useBackup = true
+					priorities = p.getProviderPriorities(useBackup)
+					priorityIdx = 0
+					currPriority = 0
+					if len(priorities) > 0 {
+						currPriority = priorities[0]
+					}
+					continue
+				}
+			}
+			errs = append(errs, err)
+			break
+		}
+
+		providerId := conn.ProviderId()
+
+		fetchStart := time.Now()
+		size, err := fn(conn)
+		if err != nil {
+			errs = append(errs, err)
+			if isArticleNotFoundError(err) {
+				conn.Release()
+				if nzbHash != "" {
+					usenet_stats.Record(usenet_stats.EventNameArticleNotFound, nzbHash, providerId, messageId, 0, 0)
+				}
+				anyArticleNotFound = true
+				excluder.markExcluded(providerId)
+				p.Log.Trace("fetch article - article not found", "message_id", messageId, "provider_id", providerId)
+				continue
+			}
+
+			conn.Destroy()
+			if nzbHash != "" {
+				usenet_stats.Record(usenet_stats.EventNameConnectionError, nzbHash, providerId, messageId, 0, 0)
+			}
+			excluder.markFailed(providerId)
+			excluder.markTried(providerId)
+			p.Log.Warn("fetch article - failed", "error", err, "message_id", messageId, "provider_id", providerId, "failure_count", excluder.failureCount(providerId))
+			continue
+		}
+		fetchDuration := time.Since(fetchStart)
+		conn.Release()
+
+		if nzbHash != "" && size > 0 {
+			usenet_stats.Record(usenet_stats.EventNameSegmentFetched, nzbHash, providerId, messageId, fetchDuration, size)
+		}
+		return nil
+	}
+
+	allArticleNotFound := len(errs) > 0
+	for _, e := range errs {
+		if e != nil && !isArticleNotFoundError(e) && !errors.Is(e, ErrNoProvidersAvailable) {
+			allArticleNotFound = false
+			break
+		}
+	}
+	retryErr := errors.Join(errs...)
+	if allArticleNotFound {
+		return fmt.Errorf("%w: <%s>: %s", ErrArticleNotFound, messageId, retryErr.Error())
+	}
+	return fmt.Errorf("failed to fetch article <%s>: %w", messageId, retryErr)
+}
+
+// cacheArticleBody decodes the raw body and adds it to the segment cache.
+func (p *Pool) cacheArticleBody(messageId string, body []byte) {
+	data, err := NewYEncDecoder(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		p.Log.Trace("fetch article - failed to decode body", "error", err, "message_id", messageId)
+		return
+	}
+	p.segmentCache.Set(messageId, data.ToSegmentData())
+}
+
+// encodeArticleBody returns the dot-stuffed yEnc body for the segment,
+// including the dot-terminator line.
+func encodeArticleBody(sd *SegmentData) ([]byte, error) {
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	w := textproto.NewWriter(bw).DotWriter()
+	if err := sd.EncodeYEnc(w); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StatArticle checks if the article exists, in the segment cache or on any
+// of the providers.
+func (p *Pool) StatArticle(ctx context.Context, messageId string) error {
+	if _, ok := p.segmentCache.Get(messageId); ok {
+		return nil
+	}
+	return p.withArticle(ctx, messageId, func(conn *nntp.PooledConnection) (int64, error) {
+		_, _, err := conn.Stat("<" + messageId + ">")
+		return 0, err
+	})
+}
+
+func (p *Pool) FetchArticle(ctx context.Context, messageId string) (*ArticleData, error) {
+	article := &ArticleData{}
+	err := p.withArticle(ctx, messageId, func(conn *nntp.PooledConnection) (int64, error) {
+		a, err := conn.Article("<" + messageId + ">")
+		if err != nil {
+			return 0, err
+		}
+		defer a.Body.Close()
+		body, err := io.ReadAll(a.Body)
+		if err != nil {
+			return 0, err
+		}
+		article.Headers = a.Headers
+		article.Body = body
+		return int64(len(body)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.cacheArticleBody(messageId, article.Body)
+	return article, nil
+}
+
+func (p *Pool) FetchArticleHead(ctx context.Context, messageId string) (*ArticleData, error) {
+	article := &ArticleData{}
+	err := p.withArticle(ctx, messageId, func(conn *nntp.PooledConnection) (int64, error) {
+		a, err := conn.Head("<" + messageId + ">")
+		if err != nil {
+			return 0, err
+		}
+		article.Headers = a.Headers
+		return 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// FetchArticleBody serves the body from the segment cache if possible, by
+// encoding it back to yEnc.
+func (p *Pool) FetchArticleBody(ctx context.Context, messageId string) (*ArticleData, error) {
+	if cachedData, ok := p.segmentCache.Get(messageId); ok {
+		if body, err := encodeArticleBody(&cachedData); err == nil {
+			p.Log.Trace("fetch article - cache hit", "message_id", messageId)
+			return &ArticleData{Body: body}, nil
+		}
+	}
+
+	article := &ArticleData{}
+	err := p.withArticle(ctx, messageId, func(conn *nntp.PooledConnection) (int64, error) {
+		a, err := conn.Body("<" + messageId + ">")
+		if err != nil {
+			return 0, err
+		}
+		defer a.Body.Close()
+		body, err := io.ReadAll(a.Body)
+		if err != nil {
+			return 0, err
+		}
+		article.Body = body
+		return int64(len(body)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.cacheArticleBody(messageId, article.Body)
+	return article, nil
+}
+
+// SelectGroup selects the newsgroup on a connection from the providers with
+// the highest priority.
+func (p *Pool) SelectGroup(ctx context.Context, name string) (*nntp.SelectedNewsGroup, error) {
+	currPriority := 0
+	if priorities := p.getProviderPriorities(false); len(priorities) > 0 {
+		currPriority = priorities[0]
+	}
+	conn, err := p.GetConnection(ctx, nil, currPriority, false)
+	if err != nil {
+		return nil, err
+	}
+	group, err := conn.Group(name)
+	if err != nil {
+		var nntpErr *nntp.Error
+		if errors.As(err, &nntpErr) && nntpErr.Code == nntp.ErrorCodeNoSuchGroup {
+			conn.Release()
+		} else {
+			conn.Destroy()
+		}
+		return nil, err
+	}
+	conn.Release()
+	return group, nil
+}
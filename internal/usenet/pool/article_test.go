@@ -0,0 +1,94 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchArticleBody(t *testing.T) {
+	testData := []byte("Hello, this is test data")
+	messageId := "test@example.com"
+
+	// Server 1: article not found
+	server1 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	setupServerArticleNotFound(server1, messageId)
+	server1.Start(t)
+
+	// Server 2 (backup): has the article
+	server2 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	setupServerWithSegment(t, server2, messageId, testData)
+	server2.Start(t)
+
+	mockCache := newMockSegmentCache()
+	usenetPool := &Pool{
+		Log: logger.Scoped("test/usenet/pool"),
+		providers: []*providerPool{
+			{Pool: nntptest.NewPool(t, server1, &nntp.PoolConfig{}), priority: 0},
+			{Pool: nntptest.NewPool(t, server2, &nntp.PoolConfig{}), priority: 0, isBackup: true},
+		},
+		segmentCache: mockCache,
+	}
+
+	article, err := usenetPool.FetchArticleBody(t.Context(), messageId)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(article.Body, []byte("\r\n.\r\n")))
+
+	data, err := NewYEncDecoder(bytes.NewReader(article.Body)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, testData, data.body)
+
+	cached, ok := mockCache.Get(messageId)
+	require.True(t, ok)
+	assert.Equal(t, testData, cached.Body)
+	assert.Equal(t, "test.bin", cached.FileName)
+
+	t.Run("served from cache", func(t *testing.T) {
+		article, err := usenetPool.FetchArticleBody(t.Context(), messageId)
+		require.NoError(t, err)
+		assert.Equal(t, 1, countBodyRequests(server2, messageId))
+
+		data, err := NewYEncDecoder(bytes.NewReader(article.Body)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, testData, data.body)
+		assert.Equal(t, "test.bin", data.header.FileName)
+		assert.Equal(t, int64(1), data.header.PartNumber)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server1.SetResponse("BODY <missing@example.com>", "430 No Such Article Found")
+		server2.SetResponse("BODY <missing@example.com>", "430 No Such Article Found")
+
+		_, err := usenetPool.FetchArticleBody(t.Context(), "missing@example.com")
+		assert.True(t, errors.Is(err, ErrArticleNotFound))
+	})
+}
+
+func TestStatArticle(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("STAT <exists@example.com>", "223 0 <exists@example.com>")
+	server.SetResponse("STAT <missing@example.com>", "430 No Such Article Found")
+	server.Start(t)
+
+	mockCache := newMockSegmentCache()
+	mockCache.Prepopulate("cached@example.com", SegmentData{Body: []byte("data")})
+
+	usenetPool := &Pool{
+		Log: logger.Scoped("test/usenet/pool"),
+		providers: []*providerPool{
+			{Pool: nntptest.NewPool(t, server, &nntp.PoolConfig{}), priority: 0},
+		},
+		segmentCache: mockCache,
+	}
+
+	assert.NoError(t, usenetPool.StatArticle(t.Context(), "exists@example.com"))
+	assert.NoError(t, usenetPool.StatArticle(t.Context(), "cached@example.com"))
+	assert.False(t, server.GetRequestCommands().HasCommand("STAT <cached@example.com>"))
+	assert.True(t, errors.Is(usenetPool.StatArticle(t.Context(), "missing@example.com"), ErrArticleNotFound))
+}
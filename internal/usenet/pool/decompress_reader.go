@@ -0,0 +1,433 @@
+package usenet_pool
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+)
+
+var _ io.ReadSeekCloser = (*decompressReader)(nil)
+
+type decompressCheckpoint struct {
+	owner    *decompressReader
+	r        io.ReadCloser
+	offset   int64
+	lastUsed int64
+	// offset where a follower is parked
+	target int64
+}
+
+// decompressCheckpointPool caps the checkpoints across all the readers, each
+// of them holds a decoder in memory, up to the dictionary size of the
+// archive. Followers count as checkpoints.
+type decompressCheckpointPool struct {
+	max       int
+	followers int
+	// followers are advanced in steps of this size
+	step int64
+
+	mu          sync.Mutex
+	count       int
+	tick        int64
+	checkpoints []*decompressCheckpoint // parked, of all readers
+}
+
+func newDecompressCheckpointPool(max int, followers int) *decompressCheckpointPool {
+	return &decompressCheckpointPool{max: max, followers: followers, step: 8 * 1024 * 1024}
+}
+
+var decompressCheckpoints = newDecompressCheckpointPool(config.Newz.DecompressCheckpoints, 3)
+
+func (p *decompressCheckpointPool) nextTick() int64 {
+	p.tick++
+	return p.tick
+}
+
+// reserve returns the number of slots reserved, at most n, without evicting.
+func (p *decompressCheckpointPool) reserve(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n = max(min(n, p.max-p.count), 0)
+	p.count += n
+	return n
+}
+
+func (p *decompressCheckpointPool) release(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.count -= n
+}
+
+// remove must be called with the lock held, the slot is released.
+func (p *decompressCheckpointPool) remove(c *decompressCheckpoint) {
+	dr := c.owner
+	if idx := slices.Index(dr.checkpoints, c); idx >= 0 {
+		dr.checkpoints = slices.Delete(dr.checkpoints, idx, idx+1)
+	}
+	if idx := slices.Index(p.checkpoints, c); idx >= 0 {
+		p.checkpoints = slices.Delete(p.checkpoints, idx, idx+1)
+	}
+	p.count--
+}
+
+// park keeps the decoder as a checkpoint of its reader. If reserved, the
+// slot of the decoder is reserved already, otherwise the least recently
+// used checkpoint is evicted if there is no free slot.
+func (p *decompressCheckpointPool) park(c *decompressCheckpoint, reserved bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dr := c.owner
+	drop := func() {
+		c.r.Close()
+		if reserved {
+			p.count--
+		}
+	}
+	search := func() int {
+		idx, _ := slices.BinarySearchFunc(dr.checkpoints, c.offset, func(c *decompressCheckpoint, offset int64) int {
+			return cmp.Compare(c.offset, offset)
+		})
+		return idx
+	}
+
+	// cheaper to decompress again than to hold a decoder
+	if c.offset < dr.interval || p.max <= 0 {
+		drop()
+		return
+	}
+
+	idx := search()
+	if idx > 0 && c.offset-dr.checkpoints[idx-1].offset < dr.interval {
+		drop()
+		return
+	}
+	if idx < len(dr.checkpoints) && dr.checkpoints[idx].offset-c.offset < dr.interval {
+		old := dr.checkpoints[idx]
+		old.r.Close()
+		p.remove(old)
+	}
+
+	if !reserved {
+		if p.count >= p.max {
+			// all the slots are held by followers
+			if len(p.checkpoints) == 0 {
+				c.r.Close()
+				return
+			}
+			lru := p.checkpoints[0]
+			for _, c := range p.checkpoints[1:] {
+				if c.lastUsed < lru.lastUsed {
+					lru = c
+				}
+			}
+			lru.r.Close()
+			p.remove(lru)
+		}
+		p.count++
+	}
+
+	c.lastUsed = p.nextTick()
+	dr.checkpoints = slices.Insert(dr.checkpoints, search(), c)
+	p.checkpoints = append(p.checkpoints, c)
+}
+
+// take removes the nearest checkpoint of the reader at or before the offset,
+// if it is after the given offset.
+func (p *decompressCheckpointPool) take(dr *decompressReader, offset int64, after int64) *decompressCheckpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *decompressCheckpoint
+	for _, c := range dr.checkpoints {
+		if c.offset > offset {
+			break
+		}
+		best = c
+	}
+	if best == nil || best.offset <= after {
+		return nil
+	}
+	p.remove(best)
+	return best
+}
+
+func (p *decompressCheckpointPool) closeAll(dr *decompressReader) []error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for len(dr.checkpoints) > 0 {
+		c := dr.checkpoints[0]
+		if err := c.r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		p.remove(c)
+	}
+	return errs
+}
+
+// decompressReader makes a compressed archive entry seekable. The decoders
+// only run forward from the start of the entry, and their state can not be
+// copied, so the checkpoints are the decoders themselves: when a seek moves
+// away from the current decoder, it is parked at its offset instead of being
+// closed. A read resumes from the nearest checkpoint at or before the
+// position, and only decompresses from the start when there is none.
+//
+// A decoder started from the start of the entry is accompanied by followers,
+// more decoders advanced along with it, in parallel, till their targets
+// spread over the distance to the position it is started for, or over the
+// entry for reading from the start. They are parked there as checkpoints, so
+// the distance decompressed once is not decompressed from the start again.
+//
+// Checkpoints are kept at least interval apart, the pool caps them across
+// all the readers, evicting the least recently used.
+type decompressReader struct {
+	open     func() (io.ReadCloser, error)
+	size     int64
+	interval int64
+	pool     *decompressCheckpointPool
+
+	mu        sync.Mutex
+	cur       *decompressCheckpoint
+	followers []*decompressCheckpoint
+	pos       int64
+	// guarded by pool.mu, sorted by offset
+	checkpoints []*decompressCheckpoint
+}
+
+func newDecompressReader(open func() (io.ReadCloser, error), size int64, interval int64, pool *decompressCheckpointPool) *decompressReader {
+	return &decompressReader{
+		open:     open,
+		size:     size,
+		interval: max(interval, 1),
+		pool:     pool,
+	}
+}
+
+// startFollowers starts the followers for a decoder started from the start
+// of the entry, to read at the offset.
+func (dr *decompressReader) startFollowers(offset int64) {
+	horizon := dr.size
+	if offset >= 2*dr.interval {
+		horizon = offset
+	}
+	n := min(dr.pool.followers, int(horizon/dr.interval)-1)
+	if n <= 0 {
+		return
+	}
+	n = dr.pool.reserve(n)
+	if n == 0 {
+		return
+	}
+
+	dr.pool.mu.Lock()
+	targets := make([]int64, 0, n)
+	for i := 1; i <= n; i++ {
+		target := horizon * int64(i) / int64(n+1)
+		if !slices.ContainsFunc(dr.checkpoints, func(c *decompressCheckpoint) bool {
+			return max(c.offset-target, target-c.offset) < dr.interval
+		}) {
+			targets = append(targets, target)
+		}
+	}
+	dr.pool.mu.Unlock()
+
+	for _, target := range targets {
+		r, err := dr.open()
+		if err != nil {
+			break
+		}
+		dr.followers = append(dr.followers, &decompressCheckpoint{owner: dr, r: r, target: target})
+	}
+	dr.pool.release(n - len(dr.followers))
+}
+
+// advanceFollowers advances the followers to the offset, in parallel with
+// advance, and parks the ones that reach their targets.
+func (dr *decompressReader) advanceFollowers(offset int64, advance func()) {
+	var wg sync.WaitGroup
+	errs := make([]error, len(dr.followers))
+	for i, f := range dr.followers {
+		if skip := min(offset, f.target) - f.offset; skip > 0 {
+			wg.Go(func() {
+				n, err := io.CopyN(io.Discard, f.r, skip)
+				f.offset += n
+				errs[i] = err
+			})
+		}
+	}
+	if advance != nil {
+		advance()
+	}
+	wg.Wait()
+
+	followers := dr.followers[:0]
+	for i, f := range dr.followers {
+		switch {
+		case errs[i] != nil:
+			f.r.Close()
+			dr.pool.release(1)
+		case f.offset >= f.target:
+			dr.pool.park(f, true)
+		default:
+			followers = append(followers, f)
+		}
+	}
+	clear(dr.followers[len(followers):])
+	dr.followers = followers
+}
+
+// detach ends the current decoder, closing it or parking it, the followers
+// are parked where they are.
+func (dr *decompressReader) detach(park bool) {
+	if dr.cur != nil {
+		if park {
+			dr.pool.park(dr.cur, false)
+		} else {
+			dr.cur.r.Close()
+		}
+		dr.cur = nil
+	}
+	for _, f := range dr.followers {
+		dr.pool.park(f, true)
+	}
+	dr.followers = nil
+}
+
+// resume positions the current decoder at the offset.
+func (dr *decompressReader) resume(offset int64) error {
+	after := int64(-1)
+	if dr.cur != nil && dr.cur.offset <= offset {
+		after = dr.cur.offset
+	}
+	if best := dr.pool.take(dr, offset, after); best != nil {
+		dr.detach(true)
+		dr.cur = best
+	} else if after < 0 {
+		dr.detach(true)
+		r, err := dr.open()
+		if err != nil {
+			return err
+		}
+		dr.cur = &decompressCheckpoint{owner: dr, r: r}
+		dr.startFollowers(offset)
+	}
+
+	for dr.cur.offset < offset {
+		step := offset - dr.cur.offset
+		if len(dr.followers) > 0 {
+			step = min(step, dr.pool.step)
+		}
+		var err error
+		dr.advanceFollowers(dr.cur.offset+step, func() {
+			var n int64
+			n, err = io.CopyN(io.Discard, dr.cur.r, step)
+			dr.cur.offset += n
+		})
+		if err != nil {
+			dr.detach(false)
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (dr *decompressReader) Read(p []byte) (int, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	if dr.pos >= dr.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if dr.cur == nil || dr.cur.offset != dr.pos {
+		if err := dr.resume(dr.pos); err != nil {
+			return 0, err
+		}
+	}
+
+	p = p[:min(int64(len(p)), dr.size-dr.pos)]
+	n, err := dr.cur.r.Read(p)
+	dr.cur.offset += int64(n)
+	dr.pos += int64(n)
+	if err != nil {
+		dr.detach(false)
+		if err == io.EOF {
+			if dr.pos < dr.size {
+				return n, io.ErrUnexpectedEOF
+			}
+			if n > 0 {
+				return n, nil
+			}
+		}
+		return n, err
+	}
+
+	if len(dr.followers) > 0 {
+		lag := dr.cur.offset
+		for _, f := range dr.followers {
+			lag = min(lag, f.offset)
+		}
+		if dr.cur.offset-lag >= dr.pool.step {
+			dr.advanceFollowers(dr.cur.offset, nil)
+		}
+	}
+	return n, nil
+}
+
+func (dr *decompressReader) Seek(offset int64, whence int) (int64, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = dr.pos + offset
+	case io.SeekEnd:
+		pos = dr.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	dr.pos = pos
+	return pos, nil
+}
+
+func (dr *decompressReader) Close() error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var errs []error
+	if dr.cur != nil {
+		if err := dr.cur.r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		dr.cur = nil
+	}
+	for _, f := range dr.followers {
+		if err := f.r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	dr.pool.release(len(dr.followers))
+	dr.followers = nil
+	errs = append(errs, dr.pool.closeAll(dr)...)
+	return errors.Join(errs...)
+}
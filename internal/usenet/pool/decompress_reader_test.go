@@ -0,0 +1,146 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompressReader(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range data {
+		data[i] = byte(rng.IntN(16))
+	}
+
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestSpeed)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	opens := 0
+	open := func() (io.ReadCloser, error) {
+		opens++
+		return flate.NewReader(bytes.NewReader(compressed.Bytes())), nil
+	}
+
+	const interval = 64 * 1024
+
+	readAt := func(t *testing.T, r *decompressReader, offset int64, n int) {
+		t.Helper()
+		_, err := r.Seek(offset, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, n)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, data[offset:offset+int64(n)], buf)
+	}
+
+	newPool := func(max int, followers int) *decompressCheckpointPool {
+		pool := newDecompressCheckpointPool(max, followers)
+		pool.step = 16 * 1024
+		return pool
+	}
+
+	offsets := func(r *decompressReader) []int64 {
+		offsets := make([]int64, len(r.checkpoints))
+		for i, c := range r.checkpoints {
+			offsets[i] = c.offset
+		}
+		return offsets
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		opens = 0
+		r := newDecompressReader(open, int64(len(data)), interval, newPool(0, 3))
+		defer r.Close()
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+		assert.Equal(t, 1, opens)
+	})
+
+	t.Run("sequential checkpoints", func(t *testing.T) {
+		opens = 0
+		r := newDecompressReader(open, int64(len(data)), interval, newPool(4, 3))
+		defer r.Close()
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+		assert.Equal(t, 4, opens)
+		assert.Equal(t, []int64{256 * 1024, 512 * 1024, 768 * 1024}, offsets(r))
+
+		// resumes from the checkpoint at 512K, not from the start
+		readAt(t, r, 600*1024, 1000)
+		assert.Equal(t, 4, opens)
+		assert.Equal(t, int64(600*1024+1000), r.cur.offset)
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		opens = 0
+		r := newDecompressReader(open, int64(len(data)), interval, newPool(4, 3))
+		defer r.Close()
+
+		readAt(t, r, 900*1024, 1000)
+		assert.Equal(t, 4, opens)
+		assert.Equal(t, []int64{225 * 1024, 450 * 1024, 675 * 1024}, offsets(r))
+
+		// resumes from the checkpoint at 450K, the decoder at the end is parked
+		readAt(t, r, 500*1024, 1000)
+		assert.Equal(t, 4, opens)
+		assert.Equal(t, int64(500*1024+1000), r.cur.offset)
+		assert.Equal(t, []int64{225 * 1024, 675 * 1024, 900*1024 + 1000}, offsets(r))
+
+		readAt(t, r, 950*1024, 1000)
+		assert.Equal(t, 4, opens)
+		assert.Equal(t, int64(950*1024+1000), r.cur.offset)
+	})
+
+	t.Run("max checkpoints", func(t *testing.T) {
+		opens = 0
+		pool := newPool(2, 3)
+		a := newDecompressReader(open, int64(len(data)), interval, pool)
+		b := newDecompressReader(open, int64(len(data)), interval, pool)
+
+		readAt(t, a, 900*1024, 1000)
+		assert.Equal(t, 3, opens)
+		assert.Equal(t, []int64{300 * 1024, 600 * 1024}, offsets(a))
+
+		// no slot left for followers
+		readAt(t, b, 100*1024, 1000)
+		assert.Equal(t, 4, opens)
+		assert.Empty(t, b.checkpoints)
+
+		// the least recently used checkpoint of the other reader is evicted
+		readAt(t, b, 0, 1000)
+		assert.Equal(t, []int64{600 * 1024}, offsets(a))
+		assert.Equal(t, []int64{100*1024 + 1000}, offsets(b))
+		assert.Equal(t, 2, pool.count)
+
+		require.NoError(t, a.Close())
+		require.NoError(t, b.Close())
+		assert.Equal(t, 0, pool.count)
+		assert.Empty(t, pool.checkpoints)
+	})
+
+	t.Run("checkpoint interval", func(t *testing.T) {
+		opens = 0
+		r := newDecompressReader(open, int64(len(data)), interval, newPool(4, 0))
+		defer r.Close()
+
+		readAt(t, r, 500*1024, 1000)
+		readAt(t, r, 0, 560*1024)
+		readAt(t, r, 0, 1000)
+		require.Len(t, r.checkpoints, 1)
+		assert.Equal(t, int64(500*1024+1000), r.checkpoints[0].offset)
+	})
+}
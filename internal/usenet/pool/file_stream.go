@@ -49,6 +49,9 @@ type FileStream struct {
 
 	pool       *Pool
 	bufferSize int64
+	readAhead  *readAhead
+	// state of the read-ahead saved by the earlier stream of the file
+	readAheadState readAheadState
 
 	mu     sync.Mutex
 	ctx    context.Context
@@ -88,6 +91,18 @@ func NewFileStream(
 		segmentSizeRatio = float64(fileSize) / float64(totalSegmentBytes)
 	}
 
+	var ra *readAhead
+	if config.Newz.StreamReadAhead > 0 {
+		// enough to keep all the connections of the stream busy
+		minSize := avgSegmentSize * int64(config.Newz.MaxConnectionPerStream*max(config.Newz.NNTPPipelineDepth, 1))
+		ra = newReadAhead(minSize, bufferSize, config.Newz.StreamReadAhead)
+	}
+
+	var raState readAheadState
+	if ra != nil && file.SegmentCount() > 0 && readAheadStateCache.Get(file.Segments[0].MessageId, &raState) {
+		ra.resume(raState.Rate)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	return &FileStream{
@@ -96,8 +111,10 @@ func NewFileStream(
 		avgSegmentSize:   avgSegmentSize,
 		segmentSizeRatio: segmentSizeRatio,
 
-		pool:       pool,
-		bufferSize: bufferSize,
+		pool:           pool,
+		bufferSize:     bufferSize,
+		readAhead:      ra,
+		readAheadState: raState,
 
 		ctx:    ctx,
 		cancel: cancel,
@@ -117,7 +134,7 @@ func (s *FileStream) Read(p []byte) (n int, err error) {
 	}
 
 	if s.stream == nil {
-		stream, err := s.createSegmentsStream(s.position, s.bufferSize)
+		stream, err := s.createSegmentsStream(s.position, s.bufferSize, s.readAhead)
 		if err != nil {
 			return 0, err
 		}
@@ -136,7 +153,7 @@ func (s *FileStream) ReadAt(p []byte, off int64) (n int, err error) {
 
 	// Use at least the requested read size as buffer, plus one extra segment for overhead
 	bufferSize := int64(len(p)) + s.avgSegmentSize
-	stream, err := s.createSegmentsStream(off, bufferSize)
+	stream, err := s.createSegmentsStream(off, bufferSize, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -176,6 +193,10 @@ func (s *FileStream) Seek(offset int64, whence int) (int64, error) {
 			s.stream.Close()
 			s.stream = nil
 		}
+		if s.readAhead != nil && !s.readAheadState.isContinuation(newPos, s.readAhead.minSize) {
+			s.readAhead.seeked()
+		}
+		s.readAheadState = readAheadState{}
 		s.position = newPos
 	}
 
@@ -196,6 +217,12 @@ func (s *FileStream) Close() error {
 
 	s.closed = true
 
+	if s.readAhead != nil && s.file.SegmentCount() > 0 {
+		if rate := s.readAhead.getRate(); rate > 0 {
+			readAheadStateCache.Add(s.file.Segments[0].MessageId, readAheadState{Rate: rate, Position: s.position})
+		}
+	}
+
 	s.cancel()
 	if s.stream != nil {
 		return s.stream.Close()
@@ -203,11 +230,11 @@ func (s *FileStream) Close() error {
 	return nil
 }
 
-func (s *FileStream) createSegmentsStream(startPos int64, bufferSize int64) (*SegmentsStream, error) {
+func (s *FileStream) createSegmentsStream(startPos int64, bufferSize int64, readAhead *readAhead) (*SegmentsStream, error) {
 	fileLog.Trace("create segments stream - start", "position", startPos)
 
 	if startPos == 0 {
-		return NewSegmentsStream(s.ctx, s.pool, s.file.Segments, s.file.Groups, bufferSize), nil
+		return newSegmentsStream(s.ctx, s.pool, s.file.Segments, s.file.Groups, bufferSize, readAhead), nil
 	}
 
 	result, err := s.interpolationSearch(startPos)
@@ -217,7 +244,7 @@ func (s *FileStream) createSegmentsStream(startPos int64, bufferSize int64) (*Se
 
 	fileLog.Trace("create segments stream - found segment", "segment_idx", result.SegmentIndex, "byte_range", fmt.Sprintf("[%d, %d)", result.ByteRange.Start, result.ByteRange.End))
 
-	stream := NewSegmentsStream(s.ctx, s.pool, s.file.Segments[result.SegmentIndex:], s.file.Groups, bufferSize)
+	stream := newSegmentsStream(s.ctx, s.pool, s.file.Segments[result.SegmentIndex:], s.file.Groups, bufferSize, readAhead)
 
 	skipBytes := startPos - result.ByteRange.Start
 	if skipBytes > 0 {
@@ -19,6 +19,8 @@ const (
 	FileTypeRAR
 	FileType7z
 	FileTypePAR2
+	FileTypeZIP
+	FileTypeISO
 )
 
 func (ft FileType) String() string {
@@ -31,6 +33,10 @@ func (ft FileType) String() string {
 		return "7z"
 	case FileTypePAR2:
 		return "par2"
+	case FileTypeZIP:
+		return "zip"
+	case FileTypeISO:
+		return "iso"
 	default:
 		return "unknown"
 	}
@@ -41,6 +47,16 @@ var (
 	magicBytesRAR5 = []byte{0x52, 0x61, 0x72, 0x21, 0x1A, 0x07, 0x01, 0x00}
 	magicBytes7Zip = []byte{0x37, 0x7A, 0xBC, 0xAF, 0x27, 0x1C}
 	magicBytesPAR2 = []byte{0x50, 0x41, 0x52, 0x32, 0x00, 0x50, 0x4B, 0x54}
+	magicBytesZIP  = []byte{0x50, 0x4B, 0x03, 0x04}
+	// first volume of a spanned zip archive
+	magicBytesZIPSpanned = []byte{0x50, 0x4B, 0x07, 0x08}
+)
+
+// volume descriptors of a disc image start at sector 16, with the standard
+// identifier at offset 1
+var (
+	isoStandardIdentifierISO9660 = []byte("CD001")
+	isoStandardIdentifierUDF     = []byte("BEA01")
 )
 
 // RAR patterns: .rar, .r00, .r01, .part01.rar
@@ -49,6 +65,11 @@ var rarRegex = regexp.MustCompile(`(?i)\.r(ar|\d+)$`)
 // 7z patterns: .7z, .7z.001, .7z.002
 var sevenZipRegex = regexp.MustCompile(`(?i)\.7z(\.\d+)?$`)
 
+// ZIP patterns: .zip, .z01, .z02, .zip.001
+var zipRegex = regexp.MustCompile(`(?i)\.(zip|z\d+|zip\.\d+)$`)
+
+var isoRegex = regexp.MustCompile(`(?i)\.iso$`)
+
 var par2Regex = regexp.MustCompile(`(?i)\.par2$`)
 
 func DetectArchiveFileTypeByExtension(filename string) FileType {
@@ -58,6 +79,12 @@ func DetectArchiveFileTypeByExtension(filename string) FileType {
 	if sevenZipRegex.MatchString(filename) {
 		return FileType7z
 	}
+	if zipRegex.MatchString(filename) {
+		return FileTypeZIP
+	}
+	if isoRegex.MatchString(filename) {
+		return FileTypeISO
+	}
 	return FileTypePlain
 }
 
@@ -87,6 +114,16 @@ func DetectFileType(fileBytes []byte, filename string) FileType {
 			ftLog.Trace("file type - detected", "filename", filename, "type", FileTypePAR2, "method", "magic_bytes")
 			return FileTypePAR2
 		}
+
+		if bytes.HasPrefix(fileBytes, magicBytesZIP) || bytes.HasPrefix(fileBytes, magicBytesZIPSpanned) {
+			ftLog.Trace("file type - detected", "filename", filename, "type", FileTypeZIP, "method", "magic_bytes")
+			return FileTypeZIP
+		}
+
+		if isDiscImage(fileBytes) {
+			ftLog.Trace("file type - detected", "filename", filename, "type", FileTypeISO, "method", "magic_bytes")
+			return FileTypeISO
+		}
 	}
 
 	if ft := DetectFileTypeByExtension(filename); ft != FileTypePlain {
@@ -98,6 +135,23 @@ func DetectFileType(fileBytes []byte, filename string) FileType {
 	return FileTypePlain
 }
 
+func isDiscImage(fileBytes []byte) bool {
+	// ISO9660 has its primary volume descriptor at sector 16, while UDF
+	// starts its volume recognition sequence there, possibly after the
+	// ISO9660 descriptors of a bridge image.
+	for sector := 16; sector < 19; sector++ {
+		offset := sector*isoSectorSize + 1
+		if len(fileBytes) < offset+5 {
+			break
+		}
+		id := fileBytes[offset : offset+5]
+		if bytes.Equal(id, isoStandardIdentifierISO9660) || bytes.Equal(id, isoStandardIdentifierUDF) {
+			return true
+		}
+	}
+	return false
+}
+
 var isVideoFile = func() func(filename string) bool {
 	videoExtensions := map[string]struct{}{
 		".mkv":  {},
@@ -150,7 +204,7 @@ func GetContentType(filename string) string {
 
 func IsArchiveFile(filename string) bool {
 	switch ft := DetectArchiveFileTypeByExtension(filename); ft {
-	case FileType7z, FileTypeRAR:
+	case FileType7z, FileTypeRAR, FileTypeZIP, FileTypeISO:
 		return true
 	default:
 		return false
@@ -187,3 +241,12 @@ func GenerateRARVolumeName(base string, volume int, oldNaming bool) string {
 func Generate7zVolumeName(base string, volume int) string {
 	return fmt.Sprintf("%s.7z.%03d", base, volume+1)
 }
+
+// GenerateZIPVolumeName generates a ZIP volume filename.
+// Volume 0: {base}.zip, Volume 1: {base}.zip.001, etc.
+func GenerateZIPVolumeName(base string, volume int) string {
+	if volume <= 0 {
+		return fmt.Sprintf("%s.zip", base)
+	}
+	return fmt.Sprintf("%s.zip.%03d", base, volume)
+}
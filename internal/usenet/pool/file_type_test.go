@@ -31,6 +31,19 @@ func TestFileTypeDetection(t *testing.T) {
 			ft := DetectFileType(data, "recovery.par2")
 			assert.Equal(t, FileTypePAR2, ft)
 		})
+
+		t.Run("ZIP", func(t *testing.T) {
+			data := append(append([]byte{}, magicBytesZIP...), make([]byte, 1000)...)
+			ft := DetectFileType(data, "archive.bin")
+			assert.Equal(t, FileTypeZIP, ft)
+		})
+
+		t.Run("ISO", func(t *testing.T) {
+			data := make([]byte, 19*2048)
+			copy(data[16*2048+1:], isoStandardIdentifierISO9660)
+			ft := DetectFileType(data, "disc.bin")
+			assert.Equal(t, FileTypeISO, ft)
+		})
 	})
 
 	t.Run("ExtensionBased", func(t *testing.T) {
@@ -52,6 +65,10 @@ func TestFileTypeDetection(t *testing.T) {
 			{"archive.7z", FileType7z},
 			{"archive.7z.001", FileType7z},
 			{"archive.7z.002", FileType7z},
+			{"archive.zip", FileTypeZIP},
+			{"archive.z01", FileTypeZIP},
+			{"archive.zip.001", FileTypeZIP},
+			{"disc.iso", FileTypeISO},
 			{"recovery.par2", FileTypePAR2},
 			{"recovery.vol00+01.par2", FileTypePAR2},
 			{"recovery.vol01-03.par2", FileTypePAR2},
@@ -0,0 +1,204 @@
+package usenet_pool
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+)
+
+type NZBProviderHealth struct {
+	Id        string `json:"id"`
+	IsBackup  bool   `json:"is_backup"`
+	Checked   int    `json:"checked"`
+	Available int    `json:"available"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (h *NZBProviderHealth) Availability() float64 {
+	if h.Checked == 0 {
+		return 0
+	}
+	return float64(h.Available) / float64(h.Checked)
+}
+
+// NZBSegmentHealth is the availability of a sampled segment.
+type NZBSegmentHealth struct {
+	// index of the file in the NZB
+	File   int `json:"f"`
+	Number int `json:"n"`
+	// availability on each provider, in the order of NZBHealth.Providers
+	Available []bool `json:"a"`
+}
+
+func (h *NZBSegmentHealth) IsAvailable() bool {
+	for _, available := range h.Available {
+		if available {
+			return true
+		}
+	}
+	return false
+}
+
+// NZBHealth is the estimated article availability of an NZB, based on STAT
+// for a sample of its segments.
+type NZBHealth struct {
+	SegmentCount int `json:"segment_count"`
+	SampleCount  int `json:"sample_count"`
+	// fraction of sampled segments available on at least one provider
+	Completeness float64             `json:"completeness"`
+	Providers    []NZBProviderHealth `json:"providers"`
+	Segments     []NZBSegmentHealth  `json:"segments,omitempty"`
+}
+
+func (h *NZBHealth) IsComplete() bool {
+	return h.Completeness >= 1
+}
+
+type nzbSegmentSample struct {
+	file    int
+	segment *nzb.Segment
+}
+
+// sampleNZBSegments picks up to `size` segments across all files. The first
+// and last segment of each file are always preferred, as those are needed
+// for inspection, and the rest are picked uniformly at random.
+func sampleNZBSegments(nzbDoc *nzb.NZB, size int) (samples []nzbSegmentSample, total int) {
+	boundaries := []nzbSegmentSample{}
+	others := []nzbSegmentSample{}
+	for i := range nzbDoc.Files {
+		f := &nzbDoc.Files[i]
+		for j := range f.Segments {
+			sample := nzbSegmentSample{file: i, segment: &f.Segments[j]}
+			if j == 0 || j == len(f.Segments)-1 {
+				boundaries = append(boundaries, sample)
+			} else {
+				others = append(others, sample)
+			}
+		}
+	}
+	total = len(boundaries) + len(others)
+
+	if size <= 0 || total <= size {
+		return append(boundaries, others...), total
+	}
+
+	if len(boundaries) >= size {
+		rand.Shuffle(len(boundaries), func(i, j int) {
+			boundaries[i], boundaries[j] = boundaries[j], boundaries[i]
+		})
+		return boundaries[:size], total
+	}
+
+	samples = boundaries
+	for _, idx := range rand.Perm(len(others))[:size-len(boundaries)] {
+		samples = append(samples, others[idx])
+	}
+	return samples, total
+}
+
+// CheckNZBHealth issues pipelined STAT for a sample of `sampleSize` segments
+// of the NZB against every online provider.
+func (p *Pool) CheckNZBHealth(ctx context.Context, nzbDoc *nzb.NZB, sampleSize int) (*NZBHealth, error) {
+	segments, total := sampleNZBSegments(nzbDoc, sampleSize)
+
+	health := &NZBHealth{
+		SegmentCount: total,
+		SampleCount:  len(segments),
+		Providers:    []NZBProviderHealth{},
+	}
+	if len(segments) == 0 {
+		return health, nil
+	}
+
+	p.providersMutex.RLock()
+	providers := make([]*providerPool, 0, len(p.providers))
+	for _, provider := range p.providers {
+		if provider.IsOnline() {
+			providers = append(providers, provider)
+		}
+	}
+	p.providersMutex.RUnlock()
+
+	if len(providers) == 0 {
+		return nil, ErrNoProvidersAvailable
+	}
+
+	specs := make([]string, len(segments))
+	for i, sample := range segments {
+		specs[i] = "<" + sample.segment.MessageId + ">"
+	}
+
+	health.Providers = make([]NZBProviderHealth, len(providers))
+	availableBySegment := make([][]bool, len(providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Go(func() {
+			ph := &health.Providers[i]
+			ph.Id = provider.Id()
+			ph.IsBackup = provider.isBackup
+
+			conn, err := provider.Acquire(ctx)
+			if err != nil {
+				ph.Error = err.Error()
+				return
+			}
+
+			results, err := conn.StatPipelined(specs)
+			if err != nil {
+				conn.Destroy()
+				ph.Error = err.Error()
+				p.Log.Debug("check nzb health - failed to stat", "error", err, "provider_id", ph.Id)
+				return
+			}
+			conn.Release()
+
+			available := make([]bool, len(results))
+			for j := range results {
+				ph.Checked++
+				if results[j].Err == nil {
+					ph.Available++
+					available[j] = true
+				} else if !isArticleNotFoundError(results[j].Err) {
+					p.Log.Trace("check nzb health - unexpected stat error", "error", results[j].Err, "provider_id", ph.Id)
+				}
+			}
+			availableBySegment[i] = available
+		})
+	}
+	wg.Wait()
+
+	checked := false
+	errs := []error{}
+	for i := range health.Providers {
+		if health.Providers[i].Error != "" {
+			errs = append(errs, errors.New(health.Providers[i].Id+": "+health.Providers[i].Error))
+		} else {
+			checked = true
+		}
+	}
+	if !checked {
+		return nil, errors.Join(errs...)
+	}
+
+	availableCount := 0
+	health.Segments = make([]NZBSegmentHealth, len(segments))
+	for j, sample := range segments {
+		sh := &health.Segments[j]
+		sh.File = sample.file
+		sh.Number = sample.segment.Number
+		sh.Available = make([]bool, len(providers))
+		for i := range availableBySegment {
+			sh.Available[i] = availableBySegment[i] != nil && j < len(availableBySegment[i]) && availableBySegment[i][j]
+		}
+		if sh.IsAvailable() {
+			availableCount++
+		}
+	}
+	health.Completeness = float64(availableCount) / float64(len(segments))
+
+	return health, nil
+}
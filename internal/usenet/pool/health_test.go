@@ -0,0 +1,111 @@
+package usenet_pool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNZB(fileSegmentCounts ...int) *nzb.NZB {
+	doc := &nzb.NZB{}
+	for i, count := range fileSegmentCounts {
+		f := nzb.File{}
+		for j := range count {
+			f.Segments = append(f.Segments, nzb.Segment{
+				Number:    j + 1,
+				MessageId: fmt.Sprintf("f%d-s%d@example.com", i, j),
+			})
+		}
+		doc.Files = append(doc.Files, f)
+	}
+	return doc
+}
+
+func TestSampleNZBSegments(t *testing.T) {
+	t.Run("all segments when within size", func(t *testing.T) {
+		samples, total := sampleNZBSegments(newTestNZB(3, 2), 10)
+		assert.Equal(t, 5, total)
+		assert.Len(t, samples, 5)
+	})
+
+	t.Run("boundaries preferred", func(t *testing.T) {
+		doc := newTestNZB(50, 50)
+		samples, total := sampleNZBSegments(doc, 10)
+		assert.Equal(t, 100, total)
+		assert.Len(t, samples, 10)
+
+		ids := map[string]struct{}{}
+		for _, s := range samples {
+			ids[s.segment.MessageId] = struct{}{}
+		}
+		assert.Len(t, ids, 10, "samples should be distinct")
+		for _, id := range []string{"f0-s0@example.com", "f0-s49@example.com", "f1-s0@example.com", "f1-s49@example.com"} {
+			assert.Contains(t, ids, id)
+		}
+	})
+
+	t.Run("boundaries capped to size", func(t *testing.T) {
+		samples, total := sampleNZBSegments(newTestNZB(2, 2, 2, 2), 3)
+		assert.Equal(t, 8, total)
+		assert.Len(t, samples, 3)
+	})
+}
+
+func TestCheckNZBHealth(t *testing.T) {
+	doc := newTestNZB(4)
+
+	// Server 1: has all but the last segment
+	server1 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	for _, s := range doc.Files[0].Segments[:3] {
+		server1.SetResponse("STAT <"+s.MessageId+">", "223 0 <"+s.MessageId+">")
+	}
+	server1.SetResponse("STAT <f0-s3@example.com>", "430 No Such Article Found")
+	server1.Start(t)
+
+	// Server 2: only has the last segment
+	server2 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server2.SetResponse("STAT *", "430 No Such Article Found")
+	server2.SetResponse("STAT <f0-s3@example.com>", "223 0 <f0-s3@example.com>")
+	server2.Start(t)
+
+	usenetPool := &Pool{
+		Log: logger.Scoped("test/usenet/pool"),
+		providers: []*providerPool{
+			{Pool: nntptest.NewPool(t, server1, &nntp.PoolConfig{}), priority: 0},
+			{Pool: nntptest.NewPool(t, server2, &nntp.PoolConfig{}), priority: 0, isBackup: true},
+		},
+		segmentCache: getNoopSegmentCache(),
+	}
+
+	health, err := usenetPool.CheckNZBHealth(t.Context(), doc, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, health.SegmentCount)
+	assert.Equal(t, 4, health.SampleCount)
+	assert.Equal(t, 1.0, health.Completeness)
+	assert.True(t, health.IsComplete())
+
+	require.Len(t, health.Providers, 2)
+	assert.Equal(t, 4, health.Providers[0].Checked)
+	assert.Equal(t, 3, health.Providers[0].Available)
+	assert.Equal(t, 0.75, health.Providers[0].Availability())
+	assert.False(t, health.Providers[0].IsBackup)
+	assert.Equal(t, 1, health.Providers[1].Available)
+	assert.True(t, health.Providers[1].IsBackup)
+
+	require.Len(t, health.Segments, 4)
+	for _, sh := range health.Segments {
+		assert.Equal(t, 0, sh.File)
+		if sh.Number == 4 {
+			assert.Equal(t, []bool{false, true}, sh.Available)
+		} else {
+			assert.Equal(t, []bool{true, false}, sh.Available)
+		}
+	}
+}
@@ -126,6 +126,10 @@ func (f *nzbArchiveFile) Volume() int {
 		return GetRARVolumeNumber(name)
 	case FileType7z:
 		return Get7zVolumeNumber(name)
+	case FileTypeZIP:
+		return GetZIPVolumeNumber(name)
+	case FileTypeISO:
+		return 0
 	default:
 		return -1
 	}
@@ -243,7 +247,7 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 		detectedType := DetectFileType(firstBytes, filename)
 
 		isParity := detectedType == FileTypePAR2
-		isArchive := detectedType == FileTypeRAR || detectedType == FileType7z
+		isArchive := detectedType == FileTypeRAR || detectedType == FileType7z || detectedType == FileTypeZIP || detectedType == FileTypeISO
 		isVideoByExt := isVideoFile(filename)
 
 		entry := &NZBContentFile{
@@ -423,6 +427,10 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 					syntheticName = GenerateRARVolumeName(group.BaseName, vol, oldRARNaming)
 				case FileType7z:
 					syntheticName = Generate7zVolumeName(group.BaseName, vol)
+				case FileTypeZIP:
+					syntheticName = GenerateZIPVolumeName(group.BaseName, vol)
+				case FileTypeISO:
+					syntheticName = group.BaseName + ".iso"
 				}
 				aliases[syntheticName] = f.Name()
 				if isFirstVolume(group.FileType, vol) {
@@ -478,6 +486,10 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 			archive = NewRARArchive(ufs, archiveName)
 		case FileType7z:
 			archive = NewSevenZipArchive(ufs.toAfero(), archiveName)
+		case FileTypeZIP:
+			archive = NewZIPArchive(ufs, archiveName)
+		case FileTypeISO:
+			archive = NewISOArchive(ufs, archiveName)
 		}
 
 		if err := archive.Open(password); err != nil {
@@ -605,6 +617,10 @@ func (p *Pool) inspectArchiveFiles(files []ArchiveFile, password string) []NZBCo
 			innerArchive = NewRARArchive(afs, filepath.Base(name))
 		case FileType7z:
 			innerArchive = NewSevenZipArchive(afs.toAfero(), filepath.Base(name))
+		case FileTypeZIP:
+			innerArchive = NewZIPArchive(afs, filepath.Base(name))
+		case FileTypeISO:
+			innerArchive = NewISOArchive(afs, filepath.Base(name))
 		default:
 			afs.Close()
 			result = append(result, entry)
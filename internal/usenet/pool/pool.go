@@ -478,6 +478,101 @@ func (p *Pool) fetchSegment(ctx context.Context, segment *nzb.Segment, groups []
 	return result.(*SegmentData), nil
 }
 
+// fetchSegmentsPipelined fetches the segments with pipelined BODY over a
+// single connection. The ones that could not be fetched that way are fetched
+// again with fetchSegment in parallel, which takes care of retrying with other
+// providers.
+func (p *Pool) fetchSegmentsPipelined(ctx context.Context, segments []*nzb.Segment, groups []string) ([]*SegmentData, []error) {
+	results := make([]*SegmentData, len(segments))
+	errs := make([]error, len(segments))
+
+	uncached := make([]int, 0, len(segments))
+	for i, segment := range segments {
+		if cachedData, ok := p.segmentCache.Get(segment.MessageId); ok {
+			results[i] = &cachedData
+		} else {
+			uncached = append(uncached, i)
+		}
+	}
+
+	if len(uncached) > 1 {
+		p.pipelineSegments(ctx, segments, uncached, results)
+	}
+
+	var wg sync.WaitGroup
+	for _, i := range uncached {
+		if results[i] == nil {
+			wg.Go(func() {
+				results[i], errs[i] = p.fetchSegment(ctx, segments[i], groups)
+			})
+		}
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+func (p *Pool) pipelineSegments(ctx context.Context, segments []*nzb.Segment, indices []int, results []*SegmentData) {
+	currPriority := 0
+	if priorities := p.getProviderPriorities(false); len(priorities) > 0 {
+		currPriority = priorities[0]
+	}
+	conn, err := p.GetConnection(ctx, nil, currPriority, false)
+	if err != nil {
+		p.Log.Trace("fetch segments - failed to get connection", "error", err, "segment_count", len(indices))
+		return
+	}
+	providerId := conn.ProviderId()
+	nzbHash, _ := ctx.Value(NZBHashContextKey).(string)
+
+	specs := make([]string, len(indices))
+	for i, idx := range indices {
+		specs[i] = "<" + segments[idx].MessageId + ">"
+	}
+
+	fetchStart := time.Now()
+	err = conn.BodyPipelined(specs, func(i int, article *nntp.Article, err error) error {
+		segment := segments[indices[i]]
+		defer func() {
+			fetchStart = time.Now()
+		}()
+
+		if err != nil {
+			p.Log.Trace("fetch segments - failed to get body", "error", err, "segment_num", segment.Number, "message_id", segment.MessageId, "provider_id", providerId)
+			return ctx.Err()
+		}
+
+		data, err := NewYEncDecoder(article.Body).ReadAll()
+		if err != nil {
+			p.Log.Trace("fetch segments - failed to decode", "error", err, "segment_num", segment.Number, "message_id", segment.MessageId, "provider_id", providerId)
+			return ctx.Err()
+		}
+		fetchDuration := time.Since(fetchStart)
+
+		segmentData := data.ToSegmentData()
+		bodySize := segment.Bytes
+		if bodySize == 0 {
+			bodySize = int64(len(segmentData.Body))
+		}
+		if nzbHash != "" {
+			usenet_stats.Record(usenet_stats.EventNameSegmentFetched, nzbHash, providerId, segment.MessageId, fetchDuration, bodySize)
+		}
+
+		p.segmentCache.Set(segment.MessageId, segmentData)
+		results[indices[i]] = &segmentData
+
+		return ctx.Err()
+	})
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		conn.Destroy()
+		p.Log.Warn("fetch segments - failed to pipeline", "error", err, "segment_count", len(indices), "provider_id", providerId)
+		return
+	}
+	conn.Release()
+
+	p.Log.Debug("fetch segments - pipelined", "segment_count", len(indices), "provider_id", providerId)
+}
+
 func (p *Pool) Close() {
 	p.providersMutex.Lock()
 	defer p.providersMutex.Unlock()
@@ -602,3 +602,54 @@ func TestFetchSegment_NoProvidersConfigured(t *testing.T) {
 	require.Error(t, err, "Should return error when no providers configured")
 	assert.ErrorIs(t, err, ErrNoProvidersConfigured, "Should be ErrNoProvidersConfigured")
 }
+
+func TestFetchSegmentsPipelined(t *testing.T) {
+	testData := [][]byte{
+		[]byte("first segment data"),
+		[]byte("second segment data"),
+		[]byte("third segment data"),
+	}
+	messageIds := []string{"p1@example.com", "p2@example.com", "p3@example.com"}
+
+	// Server 1 (P0): missing the second segment
+	server1 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	setupServerWithSegment(t, server1, messageIds[0], testData[0])
+	setupServerArticleNotFound(server1, messageIds[1])
+	setupServerWithSegment(t, server1, messageIds[2], testData[2])
+	server1.ExpectPipelined("BODY", 3)
+	server1.Start(t)
+
+	// Server 2 (backup): has the second segment
+	server2 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	setupServerWithSegment(t, server2, messageIds[1], testData[1])
+	server2.Start(t)
+
+	pool1 := nntptest.NewPool(t, server1, &nntp.PoolConfig{})
+	pool2 := nntptest.NewPool(t, server2, &nntp.PoolConfig{})
+
+	usenetPool := &Pool{
+		Log: logger.Scoped("test/usenet/pool"),
+		providers: []*providerPool{
+			{Pool: pool1, priority: 0, isBackup: false},
+			{Pool: pool2, priority: 0, isBackup: true},
+		},
+		segmentCache: getNoopSegmentCache(),
+	}
+
+	segments := make([]*nzb.Segment, len(messageIds))
+	for i, messageId := range messageIds {
+		segments[i] = &nzb.Segment{MessageId: messageId, Bytes: int64(len(testData[i])), Number: i + 1}
+	}
+
+	datas, errs := usenetPool.fetchSegmentsPipelined(t.Context(), segments, []string{"alt.test"})
+	for i := range segments {
+		require.NoError(t, errs[i], "segment %d", i)
+		assert.Equal(t, testData[i], datas[i].Body, "segment %d", i)
+	}
+
+	// Second segment is retried on the same server before the backup
+	assert.Equal(t, 1, countBodyRequests(server1, messageIds[0]))
+	assert.Equal(t, 2, countBodyRequests(server1, messageIds[1]))
+	assert.Equal(t, 1, countBodyRequests(server1, messageIds[2]))
+	assert.Equal(t, 1, countBodyRequests(server2, messageIds[1]))
+}
@@ -0,0 +1,129 @@
+package usenet_pool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+)
+
+const (
+	readAheadSampleInterval = 2 * time.Second
+	// weight of the latest sample in the smoothed rate
+	readAheadRateSmoothing = 0.3
+)
+
+// readAhead sizes the buffer of a stream from the rate at which the player
+// consumes it, so that about `duration` of playback is fetched ahead. It
+// starts small and shrinks back on seek, so that the connections are not
+// spent on data the player may never ask for.
+type readAhead struct {
+	minSize  int64
+	maxSize  int64
+	duration time.Duration
+
+	mu          sync.Mutex
+	rate        float64 // bytes per second
+	sampleStart time.Time
+	sampleBytes int64
+
+	size atomic.Int64
+}
+
+func newReadAhead(minSize, maxSize int64, duration time.Duration) *readAhead {
+	minSize = max(min(minSize, maxSize), 1)
+	ra := &readAhead{
+		minSize:  minSize,
+		maxSize:  maxSize,
+		duration: duration,
+	}
+	ra.size.Store(minSize)
+	return ra
+}
+
+func (ra *readAhead) Size() int64 {
+	return ra.size.Load()
+}
+
+func (ra *readAhead) consumed(n int) {
+	ra.record(int64(n), time.Now())
+}
+
+func (ra *readAhead) record(n int64, now time.Time) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	if ra.sampleStart.IsZero() {
+		ra.sampleStart = now
+	}
+	ra.sampleBytes += n
+
+	elapsed := now.Sub(ra.sampleStart)
+	if elapsed < readAheadSampleInterval {
+		return
+	}
+
+	rate := float64(ra.sampleBytes) / elapsed.Seconds()
+	if ra.rate == 0 {
+		ra.rate = rate
+	} else {
+		ra.rate = readAheadRateSmoothing*rate + (1-readAheadRateSmoothing)*ra.rate
+	}
+	ra.sampleStart = now
+	ra.sampleBytes = 0
+
+	size := int64(ra.rate * ra.duration.Seconds())
+	ra.size.Store(min(max(size, ra.minSize), ra.maxSize))
+}
+
+// resume continues with the rate measured by an earlier stream of the same
+// file.
+func (ra *readAhead) resume(rate float64) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	if rate <= 0 {
+		return
+	}
+	ra.rate = rate
+	size := int64(ra.rate * ra.duration.Seconds())
+	ra.size.Store(min(max(size, ra.minSize), ra.maxSize))
+}
+
+func (ra *readAhead) getRate() float64 {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+	return ra.rate
+}
+
+// seeked shrinks the read-ahead back to the minimum. The measured rate is
+// kept, so it grows back with the next sample if playback continues.
+func (ra *readAhead) seeked() {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	ra.sampleStart = time.Time{}
+	ra.sampleBytes = 0
+	ra.size.Store(ra.minSize)
+}
+
+// readAheadState is the read-ahead of a file kept across its streams, so
+// that the window is not reset by every range request of the player.
+type readAheadState struct {
+	Rate     float64 `json:"r"`
+	Position int64   `json:"p"`
+}
+
+// readAheadStateCache is keyed by the message id of the first segment of the
+// file.
+var readAheadStateCache = cache.NewCache[readAheadState](&cache.CacheConfig{
+	Name:     "newz:read-ahead",
+	Lifetime: 30 * time.Minute,
+})
+
+// isContinuation reports whether reading from pos continues the reads ended
+// at the saved position, i.e. it is not a seek.
+func (state readAheadState) isContinuation(pos int64, tolerance int64) bool {
+	return state.Rate > 0 && max(pos-state.Position, state.Position-pos) <= tolerance
+}
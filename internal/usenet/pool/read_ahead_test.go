@@ -0,0 +1,73 @@
+package usenet_pool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAhead(t *testing.T) {
+	const mb = 1024 * 1024
+
+	ra := newReadAhead(4*mb, 100*mb, 10*time.Second)
+	assert.Equal(t, int64(4*mb), ra.Size(), "starts at min size")
+
+	now := time.Now()
+	ra.record(0, now)
+	ra.record(2*mb, now.Add(1*time.Second))
+	assert.Equal(t, int64(4*mb), ra.Size(), "unchanged within sample interval")
+
+	// 2MB/s for 10s
+	ra.record(2*mb, now.Add(2*time.Second))
+	assert.Equal(t, int64(20*mb), ra.Size())
+
+	// 20MB/s, capped at max
+	ra.record(40*mb, now.Add(4*time.Second))
+	ra.record(40*mb, now.Add(6*time.Second))
+	ra.record(40*mb, now.Add(8*time.Second))
+	ra.record(40*mb, now.Add(10*time.Second))
+	ra.record(40*mb, now.Add(12*time.Second))
+	assert.Equal(t, int64(100*mb), ra.Size())
+
+	ra.seeked()
+	assert.Equal(t, int64(4*mb), ra.Size(), "shrinks on seek")
+
+	t.Run("min size capped to max size", func(t *testing.T) {
+		ra := newReadAhead(10*mb, 5*mb, 10*time.Second)
+		assert.Equal(t, int64(5*mb), ra.Size())
+	})
+}
+
+func TestReadAheadResume(t *testing.T) {
+	const mb = 1024 * 1024
+
+	ra := newReadAhead(4*mb, 100*mb, 10*time.Second)
+	ra.resume(2 * mb)
+	assert.Equal(t, int64(20*mb), ra.Size(), "sized from the resumed rate")
+	assert.Equal(t, float64(2*mb), ra.getRate())
+
+	ra.resume(0)
+	assert.Equal(t, int64(20*mb), ra.Size(), "unchanged without rate")
+}
+
+func TestReadAheadStateIsContinuation(t *testing.T) {
+	const mb = 1024 * 1024
+
+	for _, tc := range []struct {
+		name   string
+		state  readAheadState
+		pos    int64
+		result bool
+	}{
+		{"no state", readAheadState{}, 0, false},
+		{"at saved position", readAheadState{Rate: mb, Position: 50 * mb}, 50 * mb, true},
+		{"near saved position", readAheadState{Rate: mb, Position: 50 * mb}, 48 * mb, true},
+		{"far after saved position", readAheadState{Rate: mb, Position: 50 * mb}, 60 * mb, false},
+		{"far before saved position", readAheadState{Rate: mb, Position: 50 * mb}, 10 * mb, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.result, tc.state.isContinuation(tc.pos, 4*mb))
+		})
+	}
+}
@@ -11,6 +11,11 @@ type SegmentData struct {
 	ByteRange ByteRange
 	FileSize  int64
 	Size      int64
+
+	// yEnc header of the article, for encoding it back
+	FileName   string
+	PartNumber int64
+	TotalParts int64
 }
 
 func (sd SegmentData) CacheSize() int64 {
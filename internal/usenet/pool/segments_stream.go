@@ -38,8 +38,10 @@ type SegmentsStream struct {
 	dataChan chan *SegmentData
 	errChan  chan error
 
-	bufferCond          *sync.Cond   // signals when buffer space available
+	bufferCond          *sync.Cond // signals when buffer space available
+	bufferSize          int64
 	bufferSizeRemaining atomic.Int64 // remaining buffer space
+	readAhead           *readAhead   // limits the used buffer space, if set
 
 	mu       sync.Mutex
 	currData []byte // Current segment's remaining data
@@ -55,6 +57,17 @@ func NewSegmentsStream(
 	segments []nzb.Segment,
 	groups []string,
 	bufferSize int64,
+) *SegmentsStream {
+	return newSegmentsStream(ctx, pool, segments, groups, bufferSize, nil)
+}
+
+func newSegmentsStream(
+	ctx context.Context,
+	pool *Pool,
+	segments []nzb.Segment,
+	groups []string,
+	bufferSize int64,
+	readAhead *readAhead,
 ) *SegmentsStream {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -69,6 +82,8 @@ func NewSegmentsStream(
 		dataChan:    make(chan *SegmentData, workerCount*2),
 		errChan:     make(chan error, 1),
 		bufferCond:  sync.NewCond(&sync.Mutex{}),
+		bufferSize:  bufferSize,
+		readAhead:   readAhead,
 		workerCount: workerCount,
 	}
 	s.bufferSizeRemaining.Store(bufferSize)
@@ -120,7 +135,7 @@ func (s *SegmentsStream) startSegmentFetchDispatcher(segmentChan chan<- segmentW
 		segment := &s.segments[idx]
 
 		s.bufferCond.L.Lock()
-		for s.bufferSizeRemaining.Load() <= 0 && s.ctx.Err() == nil {
+		for s.isBufferFull() && s.ctx.Err() == nil {
 			segmentLog.Trace("segments stream - waiting for buffer space", "segment_num", segment.Number)
 			s.bufferCond.Wait()
 		}
@@ -139,7 +154,18 @@ func (s *SegmentsStream) startSegmentFetchDispatcher(segmentChan chan<- segmentW
 	}
 }
 
+func (s *SegmentsStream) isBufferFull() bool {
+	remaining := s.bufferSizeRemaining.Load()
+	if s.readAhead != nil {
+		return s.bufferSize-remaining >= s.readAhead.Size()
+	}
+	return remaining <= 0
+}
+
 func (s *SegmentsStream) startFetcher(segmentChan <-chan segmentWithIdx, resultChan chan<- segmentResult) {
+	pipelineDepth := max(config.Newz.NNTPPipelineDepth, 1)
+	batch := make([]segmentWithIdx, 0, pipelineDepth)
+
 	for segmentWithIdx := range segmentChan {
 		select {
 		case <-s.ctx.Done():
@@ -147,22 +173,58 @@ func (s *SegmentsStream) startFetcher(segmentChan <-chan segmentWithIdx, resultC
 		default:
 		}
 
-		data, err := s.pool.fetchSegment(s.ctx, segmentWithIdx.Segment, s.groups)
-		if data != nil {
-			if adjustment := segmentWithIdx.Bytes - data.Size; adjustment != 0 {
-				s.bufferSizeRemaining.Add(adjustment)
-				s.bufferCond.Signal()
+		// take the segments that are already dispatched, for pipelining
+		batch = append(batch[:0], segmentWithIdx)
+	collect:
+		for len(batch) < pipelineDepth {
+			select {
+			case next, ok := <-segmentChan:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
 			}
 		}
 
-		select {
-		case resultChan <- segmentResult{idx: segmentWithIdx.idx, data: data, err: err}:
-		case <-s.ctx.Done():
-			return
+		if len(batch) == 1 {
+			data, err := s.pool.fetchSegment(s.ctx, segmentWithIdx.Segment, s.groups)
+			if !s.sendResult(resultChan, segmentWithIdx, data, err) {
+				return
+			}
+			continue
+		}
+
+		segments := make([]*nzb.Segment, len(batch))
+		for i := range batch {
+			segments[i] = batch[i].Segment
+		}
+		datas, errs := s.pool.fetchSegmentsPipelined(s.ctx, segments, s.groups)
+		for i := range batch {
+			if !s.sendResult(resultChan, batch[i], datas[i], errs[i]) {
+				return
+			}
 		}
 	}
 }
 
+func (s *SegmentsStream) sendResult(resultChan chan<- segmentResult, segmentWithIdx segmentWithIdx, data *SegmentData, err error) bool {
+	if data != nil {
+		if adjustment := segmentWithIdx.Bytes - data.Size; adjustment != 0 {
+			s.bufferSizeRemaining.Add(adjustment)
+			s.bufferCond.Signal()
+		}
+	}
+
+	select {
+	case resultChan <- segmentResult{idx: segmentWithIdx.idx, data: data, err: err}:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
 func (s *SegmentsStream) startSegmentResultCollector(resultCh <-chan segmentResult) {
 	defer close(s.dataChan)
 
@@ -222,6 +284,12 @@ func (s *SegmentsStream) Read(p []byte) (n int, err error) {
 		return 0, io.EOF
 	}
 
+	if s.readAhead != nil {
+		defer func() {
+			s.readAhead.consumed(n)
+		}()
+	}
+
 	for n < len(p) {
 		select {
 		case err := <-s.errChan:
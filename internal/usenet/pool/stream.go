@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -69,6 +70,10 @@ func (p *Pool) streamFile(
 		return p.streamRARFile(ctx, nzbDoc, config)
 	case FileType7z:
 		return p.stream7zFile(ctx, nzbDoc, config)
+	case FileTypeZIP:
+		return p.streamZIPFile(ctx, nzbDoc, config)
+	case FileTypeISO:
+		return p.streamISOFile(ctx, nzbDoc, file, config)
 	default:
 		return nil, fmt.Errorf("unsupported file type: %s", fileType)
 	}
@@ -224,6 +229,10 @@ func (p *Pool) tryStreamNestedArchiveGroup(group *archiveVolumeGroup[ArchiveFile
 		innerArchive = NewRARArchive(afs, filepath.Base(group.Files[0].Name()))
 	case FileType7z:
 		innerArchive = NewSevenZipArchive(afs.toAfero(), filepath.Base(group.Files[0].Name()))
+	case FileTypeZIP:
+		innerArchive = NewZIPArchive(afs, filepath.Base(group.Files[0].Name()))
+	case FileTypeISO:
+		innerArchive = NewISOArchive(afs, filepath.Base(group.Files[0].Name()))
 	default:
 		afs.Close()
 		return nil, fmt.Errorf("unsupported inner archive type: %s", group.FileType)
@@ -316,6 +325,41 @@ func (p *Pool) stream7zFile(
 	return p.streamArchiveFile(archive, FileType7z)
 }
 
+func (p *Pool) streamZIPFile(
+	ctx context.Context,
+	nzbDoc *nzb.NZB,
+	config *StreamConfig,
+) (*Stream, error) {
+	ufs := NewUsenetFS(ctx, &UsenetFSConfig{
+		NZB:               nzbDoc,
+		Pool:              p,
+		SegmentBufferSize: config.SegmentBufferSize,
+	})
+	archive := NewUsenetZIPArchive(ufs)
+	if err := archive.Open(config.Password); err != nil {
+		return nil, err
+	}
+	return p.streamArchiveFile(archive, FileTypeZIP)
+}
+
+func (p *Pool) streamISOFile(
+	ctx context.Context,
+	nzbDoc *nzb.NZB,
+	file *nzb.File,
+	config *StreamConfig,
+) (*Stream, error) {
+	ufs := NewUsenetFS(ctx, &UsenetFSConfig{
+		NZB:               nzbDoc,
+		Pool:              p,
+		SegmentBufferSize: config.SegmentBufferSize,
+	})
+	archive := NewISOArchive(ufs, file.Name())
+	if err := archive.Open(config.Password); err != nil {
+		return nil, err
+	}
+	return p.streamArchiveFile(archive, FileTypeISO)
+}
+
 func (p *Pool) StreamLargestFile(
 	ctx context.Context,
 	nzbDoc *nzb.NZB,
@@ -358,7 +402,7 @@ func (p *Pool) streamTargetFromArchive(
 		return nil, fmt.Errorf("failed to get archive files: %w", err)
 	}
 
-	targetName := strings.Trim(targetParts[0], "/")
+	targetName := resolveArchiveTargetName(files, strings.Trim(targetParts[0], "/"))
 	remainingParts := targetParts[1:]
 
 	for _, f := range files {
@@ -421,6 +465,10 @@ func (p *Pool) streamTargetFromArchive(
 			innerArchive = NewRARArchive(afs, filepath.Base(archiveFiles[0].Name()))
 		case FileType7z:
 			innerArchive = NewSevenZipArchive(afs.toAfero(), filepath.Base(archiveFiles[0].Name()))
+		case FileTypeZIP:
+			innerArchive = NewZIPArchive(afs, filepath.Base(archiveFiles[0].Name()))
+		case FileTypeISO:
+			innerArchive = NewISOArchive(afs, filepath.Base(archiveFiles[0].Name()))
 		default:
 			afs.Close()
 			return nil, fmt.Errorf("unsupported inner archive type: %s", archiveFileType)
@@ -460,6 +508,25 @@ func (p *Pool) streamTargetFromArchive(
 	return nil, fmt.Errorf("no file matching '%s' found in archive", targetName)
 }
 
+// resolveArchiveTargetName resolves the base name of a file nested in a
+// directory of the archive (e.g. `BDMV/STREAM/00000.m2ts` of a disc image)
+// to its full name, for callers that flatten the directories.
+func resolveArchiveTargetName(files []ArchiveFile, name string) string {
+	match := ""
+	for _, f := range files {
+		if strings.EqualFold(f.Name(), name) {
+			return name
+		}
+		if match == "" && strings.EqualFold(path.Base(f.Name()), name) {
+			match = f.Name()
+		}
+	}
+	if match != "" {
+		return match
+	}
+	return name
+}
+
 func findFileByName(nzbDoc *nzb.NZB, contentFiles []NZBContentFile, name string) (*nzb.File, *NZBContentFile) {
 	var file *nzb.File
 	var contentFile *NZBContentFile
@@ -554,6 +621,10 @@ func (p *Pool) StreamByContentPath(
 		archive = NewRARArchive(ufs, name)
 	case FileType7z:
 		archive = NewSevenZipArchive(ufs.toAfero(), name)
+	case FileTypeZIP:
+		archive = NewZIPArchive(ufs, name)
+	case FileTypeISO:
+		archive = NewISOArchive(ufs, name)
 	default:
 		return nil, fmt.Errorf("file '%s' is not an archive", name)
 	}
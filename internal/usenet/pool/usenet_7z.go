@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strconv"
 
+	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/bodgit/sevenzip"
 	"github.com/spf13/afero"
 )
@@ -55,7 +56,8 @@ func (usa *SevenZipArchive) Close() error {
 func (usa *SevenZipArchive) GetFiles() ([]ArchiveFile, error) {
 	if usa.files == nil {
 		iter := usa.r.Iter()
-		files := []ArchiveFile{}
+		files := []*Usenet7zFile{}
+		compressedFilesByStream := map[int]int{}
 		for iter.Next() {
 			entry := iter.Entry()
 			file := &Usenet7zFile{
@@ -64,19 +66,38 @@ func (usa *SevenZipArchive) GetFiles() ([]ArchiveFile, error) {
 				unPackedSize: entry.Size(),
 				packedSize:   entry.CompressedSize,
 			}
+			if file.unPackedSize > 0 && entry.IsCompressed() {
+				compressedFilesByStream[entry.Stream]++
+			}
 			files = append(files, file)
 		}
 		if err := iter.Err(); err != nil {
 			return nil, err
 		}
-		usa.files = files
+		usa.files = make([]ArchiveFile, len(files))
+		for i, file := range files {
+			// compressed files sharing the stream are solid, reading one
+			// needs decoding the ones before it in the stream
+			file.solid = file.unPackedSize > 0 && file.IsCompressed() && compressedFilesByStream[file.Stream] > 1
+			usa.files[i] = file
+		}
 	}
 	return usa.files, nil
 }
 
+// IsStreamable reports whether the archive is not solid, same as RAR.
+// Compressed entries are decompressed on the fly.
 func (usa *SevenZipArchive) IsStreamable() (bool, error) {
-	iter := usa.r.Iter()
-	return !iter.HasCompression, nil
+	files, err := usa.GetFiles()
+	if err != nil {
+		return false, err
+	}
+	for _, f := range files {
+		if !f.IsStreamable() {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 type Usenet7zFile struct {
@@ -84,6 +105,7 @@ type Usenet7zFile struct {
 	name         string
 	unPackedSize int64
 	packedSize   int64
+	solid        bool
 }
 
 func (f *Usenet7zFile) Name() string {
@@ -98,11 +120,16 @@ func (f *Usenet7zFile) PackedSize() int64 {
 	return f.packedSize
 }
 
+// IsStreamable reports whether the file can be read without decoding the
+// files before it. Compressed files are decompressed on the fly.
 func (f *Usenet7zFile) IsStreamable() bool {
-	return !f.ArchiveEntry.IsCompressed()
+	return !f.solid
 }
 
 func (f *Usenet7zFile) Open() (io.ReadSeekCloser, error) {
+	if f.ArchiveEntry.IsCompressed() {
+		return newDecompressReader(f.ArchiveEntry.Open, f.unPackedSize, config.Newz.DecompressCheckpointInterval, decompressCheckpoints), nil
+	}
 	r, err := f.ArchiveEntry.Open()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,692 @@
+package usenet_pool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	_ Archive     = (*ISOArchive)(nil)
+	_ ArchiveFile = (*UsenetISOFile)(nil)
+)
+
+const (
+	isoSectorSize = 2048
+	isoMaxDepth   = 16
+	isoMaxFiles   = 10000
+	// directories are read whole
+	isoMaxDirectorySize = 16 * 1024 * 1024
+)
+
+var errISOInvalid = errors.New("iso: invalid disc image")
+
+// ISOArchive reads the file tree of a disc image. UDF (used by Blu-ray and
+// DVD) is preferred, falling back to ISO9660 with Joliet names if present.
+type ISOArchive struct {
+	fs    fs.FS
+	name  string
+	r     *multiVolumeReader
+	files []ArchiveFile
+}
+
+func (ia *ISOArchive) Open(password string) error {
+	r, err := openMultiVolumeReader(ia.fs, []string{ia.name})
+	if err != nil {
+		return err
+	}
+	ia.r = r
+	return nil
+}
+
+func (ia *ISOArchive) Close() error {
+	var errs []error
+	if ia.r != nil {
+		if err := ia.r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		ia.r = nil
+	}
+	if c, ok := ia.fs.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ia *ISOArchive) IsStreamable() (bool, error) {
+	return true, nil
+}
+
+func (ia *ISOArchive) GetFiles() ([]ArchiveFile, error) {
+	if ia.files == nil {
+		if ia.r == nil {
+			return nil, errors.New("iso: image not open")
+		}
+
+		isUDF, err := hasUDFVolume(ia.r)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []isoEntry
+		if isUDF {
+			entries, err = readUDFEntries(ia.r)
+		} else {
+			entries, err = readISO9660Entries(ia.r)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		files := make([]ArchiveFile, len(entries))
+		for i := range entries {
+			files[i] = &UsenetISOFile{a: ia, isoEntry: entries[i]}
+		}
+		ia.files = files
+	}
+	return ia.files, nil
+}
+
+type isoEntry struct {
+	name    string
+	size    int64
+	extents []fileExtent
+}
+
+type UsenetISOFile struct {
+	isoEntry
+	a *ISOArchive
+}
+
+func (uif *UsenetISOFile) Name() string {
+	return uif.name
+}
+
+func (uif *UsenetISOFile) Size() int64 {
+	return uif.size
+}
+
+func (uif *UsenetISOFile) PackedSize() int64 {
+	return uif.size
+}
+
+func (uif *UsenetISOFile) IsStreamable() bool {
+	return true
+}
+
+func (uif *UsenetISOFile) Open() (io.ReadSeekCloser, error) {
+	r, err := openMultiVolumeReader(uif.a.fs, []string{uif.a.name})
+	if err != nil {
+		return nil, err
+	}
+	return newExtentsReader(r, r, uif.extents), nil
+}
+
+func readISOSectors(r io.ReaderAt, sector int64, count int) ([]byte, error) {
+	buf := make([]byte, count*isoSectorSize)
+	if _, err := r.ReadAt(buf, sector*isoSectorSize); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errISOInvalid
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// hasUDFVolume checks the volume recognition sequence for an NSR descriptor.
+func hasUDFVolume(r io.ReaderAt) (bool, error) {
+	for sector := int64(16); sector < 16+64; sector++ {
+		vd, err := readISOSectors(r, sector, 1)
+		if err != nil {
+			if err == errISOInvalid {
+				return false, nil
+			}
+			return false, err
+		}
+		switch string(vd[1:6]) {
+		case "NSR02", "NSR03":
+			return true, nil
+		case "BEA01", "TEA01", "BOOT2", "CD001", "CDW02":
+			continue
+		default:
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+func readISO9660Entries(r io.ReaderAt) ([]isoEntry, error) {
+	var root []byte
+	joliet := false
+
+descriptors:
+	for sector := int64(16); sector < 16+64; sector++ {
+		vd, err := readISOSectors(r, sector, 1)
+		if err != nil {
+			return nil, err
+		}
+		if string(vd[1:6]) != string(isoStandardIdentifierISO9660) {
+			break
+		}
+		switch vd[0] {
+		case 1: // primary volume descriptor
+			if root == nil {
+				root = vd[156:190]
+			}
+		case 2: // supplementary volume descriptor
+			if vd[88] == '%' && vd[89] == '/' && (vd[90] == '@' || vd[90] == 'C' || vd[90] == 'E') {
+				root = vd[156:190]
+				joliet = true
+			}
+		case 255: // terminator
+			break descriptors
+		}
+	}
+	if root == nil {
+		return nil, errISOInvalid
+	}
+
+	w := &iso9660Walker{r: r, joliet: joliet}
+	if err := w.walk(binary.LittleEndian.Uint32(root[2:]), binary.LittleEndian.Uint32(root[10:]), "", 0); err != nil {
+		return nil, err
+	}
+	return w.entries, nil
+}
+
+type iso9660Walker struct {
+	r       io.ReaderAt
+	joliet  bool
+	entries []isoEntry
+}
+
+func (w *iso9660Walker) decodeName(b []byte) string {
+	var name string
+	if w.joliet {
+		name = decodeUTF16BE(b)
+	} else {
+		name = string(b)
+	}
+	if i := strings.IndexByte(name, ';'); i >= 0 {
+		name = name[:i]
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+func (w *iso9660Walker) walk(extent, size uint32, dir string, depth int) error {
+	if depth > isoMaxDepth || size > isoMaxDirectorySize {
+		return nil
+	}
+
+	data := make([]byte, size)
+	if _, err := w.r.ReadAt(data, int64(extent)*isoSectorSize); err != nil {
+		return err
+	}
+
+	// a file larger than 4GB is recorded in multiple directory records
+	continued := false
+	for off := 0; off < len(data); {
+		recLen := int(data[off])
+		if recLen == 0 {
+			// records do not cross sectors
+			off = (off/isoSectorSize + 1) * isoSectorSize
+			continue
+		}
+		if recLen < 34 || off+recLen > len(data) {
+			break
+		}
+		rec := data[off : off+recLen]
+		off += recLen
+
+		nameLen := int(rec[32])
+		if 33+nameLen > recLen {
+			continue
+		}
+		rawName := rec[33 : 33+nameLen]
+		if nameLen == 1 && (rawName[0] == 0 || rawName[0] == 1) {
+			continue
+		}
+
+		lba := binary.LittleEndian.Uint32(rec[2:])
+		length := binary.LittleEndian.Uint32(rec[10:])
+		flags := rec[25]
+		name := path.Join(dir, w.decodeName(rawName))
+
+		if flags&0x02 != 0 {
+			if err := w.walk(lba, length, name, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ext := fileExtent{
+			offset: (int64(lba) + int64(rec[1])) * isoSectorSize,
+			size:   int64(length),
+		}
+		if continued && len(w.entries) > 0 && w.entries[len(w.entries)-1].name == name {
+			last := &w.entries[len(w.entries)-1]
+			last.extents = append(last.extents, ext)
+			last.size += ext.size
+		} else if len(w.entries) < isoMaxFiles {
+			w.entries = append(w.entries, isoEntry{name: name, size: ext.size, extents: []fileExtent{ext}})
+		}
+		continued = flags&0x80 != 0
+	}
+	return nil
+}
+
+func decodeUTF16BE(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// decodeDString decodes the OSTA compressed unicode of UDF.
+func decodeDString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	switch b[0] {
+	case 8:
+		r := make([]rune, len(b)-1)
+		for i, c := range b[1:] {
+			r[i] = rune(c)
+		}
+		return string(r)
+	case 16:
+		return decodeUTF16BE(b[1:])
+	default:
+		return string(b[1:])
+	}
+}
+
+const (
+	udfTagAnchorVolumeDescriptorPointer = 2
+	udfTagPartitionDescriptor           = 5
+	udfTagLogicalVolumeDescriptor       = 6
+	udfTagTerminatingDescriptor         = 8
+	udfTagFileSetDescriptor             = 256
+	udfTagFileIdentifierDescriptor      = 257
+	udfTagFileEntry                     = 261
+	udfTagExtendedFileEntry             = 266
+
+	udfFileTypeDirectory = 4
+
+	udfFileCharacteristicDirectory = 0x02
+	udfFileCharacteristicDeleted   = 0x04
+	udfFileCharacteristicParent    = 0x08
+)
+
+type udfPartitionMap struct {
+	start    int64        // of the physical partition
+	metadata []fileExtent // of the metadata file, for metadata partition
+}
+
+type udfAllocation struct {
+	lbn     uint32
+	length  uint32
+	partRef int // -1 for the partition of the file entry
+	sparse  bool
+}
+
+type udfFileEntry struct {
+	fileType       uint8
+	size           int64
+	embedded       []byte
+	embeddedOffset int64 // in the file entry
+	allocations    []udfAllocation
+}
+
+type udfReader struct {
+	r          io.ReaderAt
+	blockSize  int64
+	partitions []udfPartitionMap
+	entries    []isoEntry
+}
+
+func readUDFEntries(r io.ReaderAt) ([]isoEntry, error) {
+	avdp, err := readISOSectors(r, 256, 1)
+	if err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint16(avdp) != udfTagAnchorVolumeDescriptorPointer {
+		return nil, errISOInvalid
+	}
+	vdsLength := binary.LittleEndian.Uint32(avdp[16:])
+	vdsLocation := binary.LittleEndian.Uint32(avdp[20:])
+
+	partitionStarts := map[uint16]int64{}
+	var lvd []byte
+	for i := range min(int64(vdsLength)/isoSectorSize, 64) {
+		d, err := readISOSectors(r, int64(vdsLocation)+i, 1)
+		if err != nil {
+			return nil, err
+		}
+		tag := binary.LittleEndian.Uint16(d)
+		if tag == udfTagTerminatingDescriptor {
+			break
+		}
+		switch tag {
+		case udfTagPartitionDescriptor:
+			number := binary.LittleEndian.Uint16(d[22:])
+			if _, ok := partitionStarts[number]; !ok {
+				partitionStarts[number] = int64(binary.LittleEndian.Uint32(d[188:]))
+			}
+		case udfTagLogicalVolumeDescriptor:
+			if lvd == nil {
+				lvd = d
+			}
+		}
+	}
+	if lvd == nil {
+		return nil, errISOInvalid
+	}
+
+	u := &udfReader{
+		r:         r,
+		blockSize: int64(binary.LittleEndian.Uint32(lvd[212:])),
+	}
+	// a power of two, large enough for the descriptors
+	if u.blockSize < 512 || u.blockSize > 65536 || u.blockSize&(u.blockSize-1) != 0 {
+		return nil, errISOInvalid
+	}
+
+	mapTableLen := int(binary.LittleEndian.Uint32(lvd[264:]))
+	mapCount := int(binary.LittleEndian.Uint32(lvd[268:]))
+	maps := lvd[440:min(440+mapTableLen, len(lvd))]
+	for range mapCount {
+		if len(maps) < 2 || len(maps) < int(maps[1]) || maps[1] == 0 {
+			return nil, errISOInvalid
+		}
+		m := maps[:maps[1]]
+		maps = maps[maps[1]:]
+
+		switch m[0] {
+		case 1:
+			if len(m) < 6 {
+				return nil, errISOInvalid
+			}
+			start, ok := partitionStarts[binary.LittleEndian.Uint16(m[4:])]
+			if !ok {
+				return nil, errISOInvalid
+			}
+			u.partitions = append(u.partitions, udfPartitionMap{start: start * u.blockSize})
+		case 2:
+			if len(m) < 44 {
+				return nil, errISOInvalid
+			}
+			ident := strings.TrimRight(string(m[5:28]), "\x00")
+			start, ok := partitionStarts[binary.LittleEndian.Uint16(m[38:])]
+			if !ok {
+				return nil, errISOInvalid
+			}
+			pm := udfPartitionMap{start: start * u.blockSize}
+			switch ident {
+			case "*UDF Sparable Partition":
+				// sparing only matters for rewritable media
+			case "*UDF Metadata Partition":
+				metadata, err := u.readMetadataFile(pm.start, binary.LittleEndian.Uint32(m[40:]))
+				if err != nil {
+					return nil, err
+				}
+				pm.metadata = metadata
+			default:
+				return nil, fmt.Errorf("iso: unsupported udf partition: %s", ident)
+			}
+			u.partitions = append(u.partitions, pm)
+		default:
+			return nil, errISOInvalid
+		}
+	}
+
+	// file set descriptor location, as long_ad
+	fsdLbn := binary.LittleEndian.Uint32(lvd[252:])
+	fsdPartRef := int(binary.LittleEndian.Uint16(lvd[256:]))
+	fsd, _, err := u.readBlock(fsdPartRef, fsdLbn)
+	if err != nil {
+		return nil, err
+	}
+	if len(fsd) < 410 || binary.LittleEndian.Uint16(fsd) != udfTagFileSetDescriptor {
+		return nil, errISOInvalid
+	}
+	rootLbn := binary.LittleEndian.Uint32(fsd[404:])
+	rootPartRef := int(binary.LittleEndian.Uint16(fsd[408:]))
+
+	if err := u.walk(rootPartRef, rootLbn, "", 0); err != nil {
+		return nil, err
+	}
+	return u.entries, nil
+}
+
+// address resolves the logical block of the partition to the offset in the
+// image.
+func (u *udfReader) address(partRef int, lbn uint32) (int64, error) {
+	if partRef < 0 || partRef >= len(u.partitions) {
+		return 0, errISOInvalid
+	}
+	pm := &u.partitions[partRef]
+	offset := int64(lbn) * u.blockSize
+	if pm.metadata == nil {
+		return pm.start + offset, nil
+	}
+	for _, e := range pm.metadata {
+		if offset < e.size {
+			return e.offset + offset, nil
+		}
+		offset -= e.size
+	}
+	return 0, errISOInvalid
+}
+
+func (u *udfReader) readBlock(partRef int, lbn uint32) ([]byte, int64, error) {
+	addr, err := u.address(partRef, lbn)
+	if err != nil {
+		return nil, 0, err
+	}
+	buf := make([]byte, u.blockSize)
+	if _, err := u.r.ReadAt(buf, addr); err != nil {
+		return nil, 0, err
+	}
+	return buf, addr, nil
+}
+
+func (u *udfReader) readMetadataFile(partitionStart int64, lbn uint32) ([]fileExtent, error) {
+	buf := make([]byte, u.blockSize)
+	if _, err := u.r.ReadAt(buf, partitionStart+int64(lbn)*u.blockSize); err != nil {
+		return nil, err
+	}
+	fe, err := parseUDFFileEntry(buf)
+	if err != nil {
+		return nil, err
+	}
+	extents := make([]fileExtent, 0, len(fe.allocations))
+	for _, a := range fe.allocations {
+		extents = append(extents, fileExtent{
+			offset: partitionStart + int64(a.lbn)*u.blockSize,
+			size:   int64(a.length),
+		})
+	}
+	return extents, nil
+}
+
+func parseUDFFileEntry(d []byte) (*udfFileEntry, error) {
+	if len(d) < 2 {
+		return nil, errISOInvalid
+	}
+	var eaLenOffset, adOffset int
+	switch binary.LittleEndian.Uint16(d) {
+	case udfTagFileEntry:
+		eaLenOffset, adOffset = 168, 176
+	case udfTagExtendedFileEntry:
+		eaLenOffset, adOffset = 208, 216
+	default:
+		return nil, errISOInvalid
+	}
+	if len(d) < adOffset {
+		return nil, errISOInvalid
+	}
+
+	fe := &udfFileEntry{
+		fileType: d[27],
+		size:     int64(binary.LittleEndian.Uint64(d[56:])),
+	}
+	eaLen := int(binary.LittleEndian.Uint32(d[eaLenOffset:]))
+	adLen := int(binary.LittleEndian.Uint32(d[eaLenOffset+4:]))
+	if adOffset+eaLen+adLen > len(d) {
+		return nil, errISOInvalid
+	}
+	ads := d[adOffset+eaLen : adOffset+eaLen+adLen]
+
+	switch allocType := binary.LittleEndian.Uint16(d[34:]) & 0x7; allocType {
+	case 0, 1: // short_ad, long_ad
+		adSize := 8
+		if allocType == 1 {
+			adSize = 16
+		}
+		for ; len(ads) >= adSize; ads = ads[adSize:] {
+			length := binary.LittleEndian.Uint32(ads)
+			if length == 0 {
+				break
+			}
+			kind := length >> 30
+			if kind == 3 {
+				return nil, errors.New("iso: udf allocation extent continuation is not supported")
+			}
+			a := udfAllocation{
+				length:  length & 0x3FFFFFFF,
+				lbn:     binary.LittleEndian.Uint32(ads[4:]),
+				partRef: -1,
+				sparse:  kind != 0,
+			}
+			if allocType == 1 {
+				a.partRef = int(binary.LittleEndian.Uint16(ads[8:]))
+			}
+			fe.allocations = append(fe.allocations, a)
+		}
+	case 3: // embedded
+		fe.embedded = ads
+		fe.embeddedOffset = int64(adOffset + eaLen)
+	default:
+		return nil, errISOInvalid
+	}
+	return fe, nil
+}
+
+func (u *udfReader) extents(fe *udfFileEntry, partRef int) ([]fileExtent, error) {
+	extents := make([]fileExtent, 0, len(fe.allocations))
+	remaining := fe.size
+	for _, a := range fe.allocations {
+		if remaining <= 0 {
+			break
+		}
+		e := fileExtent{size: min(int64(a.length), remaining), sparse: a.sparse}
+		if !a.sparse {
+			ref := a.partRef
+			if ref < 0 {
+				ref = partRef
+			}
+			offset, err := u.address(ref, a.lbn)
+			if err != nil {
+				return nil, err
+			}
+			e.offset = offset
+		}
+		extents = append(extents, e)
+		remaining -= e.size
+	}
+	return extents, nil
+}
+
+func (u *udfReader) walk(partRef int, lbn uint32, dir string, depth int) error {
+	if depth > isoMaxDepth {
+		return nil
+	}
+
+	block, _, err := u.readBlock(partRef, lbn)
+	if err != nil {
+		return err
+	}
+	fe, err := parseUDFFileEntry(block)
+	if err != nil {
+		return err
+	}
+	if fe.fileType != udfFileTypeDirectory || fe.size > isoMaxDirectorySize {
+		return nil
+	}
+
+	data := fe.embedded
+	if data != nil {
+		data = data[:min(int64(len(data)), fe.size)]
+	} else {
+		extents, err := u.extents(fe, partRef)
+		if err != nil {
+			return err
+		}
+		data = make([]byte, fe.size)
+		if _, err := io.ReadFull(newExtentsReader(u.r, nil, extents), data); err != nil {
+			return err
+		}
+	}
+
+	for len(data) >= 38 {
+		if binary.LittleEndian.Uint16(data) != udfTagFileIdentifierDescriptor {
+			break
+		}
+		characteristics := data[18]
+		nameLen := int(data[19])
+		icbLbn := binary.LittleEndian.Uint32(data[24:])
+		icbPartRef := int(binary.LittleEndian.Uint16(data[28:]))
+		iuLen := int(binary.LittleEndian.Uint16(data[36:]))
+		fidLen := (38 + iuLen + nameLen + 3) &^ 3
+		if 38+iuLen+nameLen > len(data) {
+			break
+		}
+		name := decodeDString(data[38+iuLen : 38+iuLen+nameLen])
+		data = data[min(fidLen, len(data)):]
+
+		if characteristics&(udfFileCharacteristicParent|udfFileCharacteristicDeleted) != 0 {
+			continue
+		}
+
+		name = path.Join(dir, name)
+		if characteristics&udfFileCharacteristicDirectory != 0 {
+			if err := u.walk(icbPartRef, icbLbn, name, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(u.entries) >= isoMaxFiles {
+			continue
+		}
+		block, addr, err := u.readBlock(icbPartRef, icbLbn)
+		if err != nil {
+			return err
+		}
+		child, err := parseUDFFileEntry(block)
+		if err != nil {
+			return err
+		}
+		entry := isoEntry{name: name, size: child.size}
+		if child.embedded != nil {
+			entry.size = min(entry.size, int64(len(child.embedded)))
+			entry.extents = []fileExtent{{offset: addr + child.embeddedOffset, size: entry.size}}
+		} else if entry.extents, err = u.extents(child, icbPartRef); err != nil {
+			return err
+		}
+		u.entries = append(u.entries, entry)
+	}
+	return nil
+}
+
+func NewISOArchive(fs fs.FS, name string) *ISOArchive {
+	return &ISOArchive{fs: fs, name: name}
+}
@@ -0,0 +1,264 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDiscImage struct {
+	data []byte
+}
+
+func newTestDiscImage(sectors int) *testDiscImage {
+	return &testDiscImage{data: make([]byte, sectors*isoSectorSize)}
+}
+
+func (img *testDiscImage) sector(n int) []byte {
+	return img.data[n*isoSectorSize : (n+1)*isoSectorSize]
+}
+
+func isoDirectoryRecord(name string, lba, size uint32, flags byte) []byte {
+	recLen := 33 + len(name)
+	if recLen%2 == 1 {
+		recLen++
+	}
+	rec := make([]byte, recLen)
+	rec[0] = byte(recLen)
+	binary.LittleEndian.PutUint32(rec[2:], lba)
+	binary.BigEndian.PutUint32(rec[6:], lba)
+	binary.LittleEndian.PutUint32(rec[10:], size)
+	binary.BigEndian.PutUint32(rec[14:], size)
+	rec[25] = flags
+	rec[32] = byte(len(name))
+	copy(rec[33:], name)
+	return rec
+}
+
+func createTestISO9660Image(video []byte) []byte {
+	img := newTestDiscImage(23 + len(video)/isoSectorSize + 1)
+
+	pvd := img.sector(16)
+	pvd[0] = 1
+	copy(pvd[1:], isoStandardIdentifierISO9660)
+	copy(pvd[156:], isoDirectoryRecord("\x00", 18, isoSectorSize, 0x02))
+
+	terminator := img.sector(17)
+	terminator[0] = 255
+	copy(terminator[1:], isoStandardIdentifierISO9660)
+
+	root := bytes.Join([][]byte{
+		isoDirectoryRecord("\x00", 18, isoSectorSize, 0x02),
+		isoDirectoryRecord("\x01", 18, isoSectorSize, 0x02),
+		isoDirectoryRecord("BDMV", 19, isoSectorSize, 0x02),
+		isoDirectoryRecord("README.TXT;1", 21, 5, 0),
+	}, nil)
+	copy(img.sector(18), root)
+
+	bdmv := bytes.Join([][]byte{
+		isoDirectoryRecord("\x00", 19, isoSectorSize, 0x02),
+		isoDirectoryRecord("\x01", 18, isoSectorSize, 0x02),
+		isoDirectoryRecord("00000.M2TS;1", 22, uint32(len(video)), 0),
+	}, nil)
+	copy(img.sector(19), bdmv)
+
+	copy(img.sector(21), "hello")
+	copy(img.data[22*isoSectorSize:], video)
+
+	return img.data
+}
+
+func udfFileIdentifier(name string, characteristics byte, lbn uint32) []byte {
+	var nameBytes []byte
+	if name != "" {
+		nameBytes = append([]byte{8}, name...)
+	}
+	fidLen := (38 + len(nameBytes) + 3) &^ 3
+	fid := make([]byte, fidLen)
+	binary.LittleEndian.PutUint16(fid, udfTagFileIdentifierDescriptor)
+	fid[18] = characteristics
+	fid[19] = byte(len(nameBytes))
+	binary.LittleEndian.PutUint32(fid[20:], isoSectorSize)
+	binary.LittleEndian.PutUint32(fid[24:], lbn)
+	copy(fid[38:], nameBytes)
+	return fid
+}
+
+func createTestUDFImage(video []byte) []byte {
+	const partitionStart = 300
+	img := newTestDiscImage(partitionStart + 20 + len(video)/isoSectorSize + 1)
+	block := func(lbn int) []byte {
+		return img.sector(partitionStart + lbn)
+	}
+
+	copy(img.sector(16)[1:], "BEA01")
+	copy(img.sector(17)[1:], "NSR02")
+	copy(img.sector(18)[1:], "TEA01")
+
+	avdp := img.sector(256)
+	binary.LittleEndian.PutUint16(avdp, udfTagAnchorVolumeDescriptorPointer)
+	binary.LittleEndian.PutUint32(avdp[16:], 3*isoSectorSize)
+	binary.LittleEndian.PutUint32(avdp[20:], 32)
+
+	pd := img.sector(32)
+	binary.LittleEndian.PutUint16(pd, udfTagPartitionDescriptor)
+	binary.LittleEndian.PutUint16(pd[22:], 0)
+	binary.LittleEndian.PutUint32(pd[188:], partitionStart)
+
+	lvd := img.sector(33)
+	binary.LittleEndian.PutUint16(lvd, udfTagLogicalVolumeDescriptor)
+	binary.LittleEndian.PutUint32(lvd[212:], isoSectorSize)
+	binary.LittleEndian.PutUint32(lvd[248:], isoSectorSize)
+	binary.LittleEndian.PutUint32(lvd[252:], 0)
+	binary.LittleEndian.PutUint32(lvd[264:], 6)
+	binary.LittleEndian.PutUint32(lvd[268:], 1)
+	copy(lvd[440:], []byte{1, 6, 1, 0, 0, 0})
+
+	binary.LittleEndian.PutUint16(img.sector(34), udfTagTerminatingDescriptor)
+
+	fsd := block(0)
+	binary.LittleEndian.PutUint16(fsd, udfTagFileSetDescriptor)
+	binary.LittleEndian.PutUint32(fsd[404:], 1)
+
+	// root directory, with short_ad
+	rootFids := bytes.Join([][]byte{
+		udfFileIdentifier("", udfFileCharacteristicParent|udfFileCharacteristicDirectory, 1),
+		udfFileIdentifier("BDMV", udfFileCharacteristicDirectory, 3),
+		udfFileIdentifier("README.TXT", 0, 5),
+	}, nil)
+	root := block(1)
+	binary.LittleEndian.PutUint16(root, udfTagFileEntry)
+	root[27] = udfFileTypeDirectory
+	binary.LittleEndian.PutUint64(root[56:], uint64(len(rootFids)))
+	binary.LittleEndian.PutUint32(root[172:], 8)
+	binary.LittleEndian.PutUint32(root[176:], uint32(len(rootFids)))
+	binary.LittleEndian.PutUint32(root[180:], 2)
+	copy(block(2), rootFids)
+
+	// BDMV directory, with embedded data
+	bdmvFids := bytes.Join([][]byte{
+		udfFileIdentifier("", udfFileCharacteristicParent|udfFileCharacteristicDirectory, 1),
+		udfFileIdentifier("00000.m2ts", 0, 4),
+	}, nil)
+	bdmv := block(3)
+	binary.LittleEndian.PutUint16(bdmv, udfTagExtendedFileEntry)
+	bdmv[27] = udfFileTypeDirectory
+	binary.LittleEndian.PutUint16(bdmv[34:], 3)
+	binary.LittleEndian.PutUint64(bdmv[56:], uint64(len(bdmvFids)))
+	binary.LittleEndian.PutUint32(bdmv[212:], uint32(len(bdmvFids)))
+	copy(bdmv[216:], bdmvFids)
+
+	// video file, in two extents
+	file := block(4)
+	binary.LittleEndian.PutUint16(file, udfTagFileEntry)
+	file[27] = 5
+	binary.LittleEndian.PutUint64(file[56:], uint64(len(video)))
+	binary.LittleEndian.PutUint32(file[172:], 16)
+	binary.LittleEndian.PutUint32(file[176:], 2*isoSectorSize)
+	binary.LittleEndian.PutUint32(file[180:], 10)
+	binary.LittleEndian.PutUint32(file[184:], uint32(len(video)-2*isoSectorSize))
+	binary.LittleEndian.PutUint32(file[188:], 20)
+	copy(img.data[(partitionStart+10)*isoSectorSize:], video[:2*isoSectorSize])
+	copy(img.data[(partitionStart+20)*isoSectorSize:], video[2*isoSectorSize:])
+
+	// text file, with embedded data
+	readme := block(5)
+	binary.LittleEndian.PutUint16(readme, udfTagFileEntry)
+	readme[27] = 5
+	binary.LittleEndian.PutUint16(readme[34:], 3)
+	binary.LittleEndian.PutUint64(readme[56:], 5)
+	binary.LittleEndian.PutUint32(readme[172:], 5)
+	copy(readme[176:], "hello")
+
+	return img.data
+}
+
+func TestISOArchive(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789abcdef"), 600)
+
+	for _, tc := range []struct {
+		name  string
+		image []byte
+	}{
+		{"ISO9660", createTestISO9660Image(video)},
+		{"UDF", createTestUDFImage(video)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, FileTypeISO, DetectFileType(tc.image[:min(len(tc.image), 64*1024)], "obfuscated"))
+
+			fsys := fstest.MapFS{"disc.iso": {Data: tc.image}}
+			archive := NewISOArchive(fsys, "disc.iso")
+			require.NoError(t, archive.Open(""))
+			defer archive.Close()
+
+			files, err := archive.GetFiles()
+			require.NoError(t, err)
+
+			byName := map[string]ArchiveFile{}
+			for _, f := range files {
+				byName[f.Name()] = f
+			}
+			require.Len(t, byName, 2)
+
+			var videoFile, readmeFile ArchiveFile
+			for name, f := range byName {
+				switch name {
+				case "BDMV/00000.M2TS", "BDMV/00000.m2ts":
+					videoFile = f
+				case "README.TXT":
+					readmeFile = f
+				}
+			}
+			require.NotNil(t, videoFile)
+			require.NotNil(t, readmeFile)
+			assert.Equal(t, int64(len(video)), videoFile.Size())
+
+			r, err := videoFile.Open()
+			require.NoError(t, err)
+			defer r.Close()
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, video, data)
+
+			// across the extents
+			_, err = r.Seek(2*isoSectorSize-10, io.SeekStart)
+			require.NoError(t, err)
+			buf := make([]byte, 20)
+			_, err = io.ReadFull(r, buf)
+			require.NoError(t, err)
+			assert.Equal(t, video[2*isoSectorSize-10:2*isoSectorSize+10], buf)
+
+			r, err = readmeFile.Open()
+			require.NoError(t, err)
+			defer r.Close()
+			data, err = io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("hello"), data)
+		})
+	}
+}
+
+func TestUDFInvalid(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789abcdef"), 600)
+
+	for _, blockSize := range []uint32{1, 1000, 1 << 20} {
+		image := createTestUDFImage(video)
+		binary.LittleEndian.PutUint32(image[33*isoSectorSize+212:], blockSize)
+		_, err := readUDFEntries(bytes.NewReader(image))
+		assert.ErrorIs(t, err, errISOInvalid, "block size %d", blockSize)
+	}
+
+	for _, tag := range []uint16{udfTagFileEntry, udfTagExtendedFileEntry} {
+		for _, size := range []int{0, 1, 2, 40, 175} {
+			d := make([]byte, max(size, 2))
+			binary.LittleEndian.PutUint16(d, tag)
+			_, err := parseUDFFileEntry(d[:size])
+			assert.ErrorIs(t, err, errISOInvalid, "tag %d, size %d", tag, size)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strconv"
 
+	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/nwaples/rardecode/v2"
 )
 
@@ -135,6 +136,12 @@ func (urf *UsenetRARFile) Open() (io.ReadSeekCloser, error) {
 	if err := urf.a.open(); err != nil {
 		return nil, err
 	}
+	if urf.isCompressed {
+		open := func() (io.ReadCloser, error) {
+			return urf.a.r.Open(urf.name)
+		}
+		return newDecompressReader(open, urf.unPackedSize, config.Newz.DecompressCheckpointInterval, decompressCheckpoints), nil
+	}
 	r, err := urf.a.r.Open(urf.name)
 	if err != nil {
 		return nil, err
@@ -150,8 +157,10 @@ func (urf *UsenetRARFile) Size() int64 {
 	return urf.unPackedSize
 }
 
+// IsStreamable reports whether the file can be read without decoding the
+// files before it. Compressed files are decompressed on the fly.
 func (urf *UsenetRARFile) IsStreamable() bool {
-	return !urf.solid && !urf.isCompressed
+	return !urf.solid
 }
 
 // .part01.rar format
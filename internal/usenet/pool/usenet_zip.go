@@ -0,0 +1,453 @@
+package usenet_pool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	_ Archive     = (*ZIPArchive)(nil)
+	_ ArchiveFile = (*UsenetZIPFile)(nil)
+)
+
+const (
+	zipLocalFileHeaderSignature          = 0x04034b50
+	zipCentralDirectorySignature         = 0x02014b50
+	zipEndOfCentralDirSignature          = 0x06054b50
+	zip64EndOfCentralDirSignature        = 0x06064b50
+	zip64EndOfCentralDirLocatorSignature = 0x07064b50
+
+	zipLocalFileHeaderLen          = 30
+	zipCentralDirectoryHeaderLen   = 46
+	zipEndOfCentralDirLen          = 22
+	zip64EndOfCentralDirLen        = 56
+	zip64EndOfCentralDirLocatorLen = 20
+	zipMaxCommentLen               = 65535
+	zipMaxCentralDirectorySize     = 64 * 1024 * 1024
+
+	zipMethodStore   = 0
+	zipFlagEncrypted = 0x1
+	zip64ExtraId     = 0x0001
+)
+
+var (
+	errZIPEndOfCentralDirNotFound = errors.New("zip: end of central directory not found")
+	errZIPInvalidHeader           = errors.New("zip: invalid header")
+)
+
+// ZIPArchive reads the central directory of a (possibly multi-volume) zip
+// archive. Both the spanned (`.z01`, `.z02`, ..., `.zip`) and the split
+// (`.zip.001`, `.zip.002`, ...) volumes are supported, along with zip64.
+//
+// Only the stored entries are streamable.
+type ZIPArchive struct {
+	fs      fs.FS
+	name    string
+	volumes []string
+	r       *multiVolumeReader
+	files   []ArchiveFile
+}
+
+func (za *ZIPArchive) Open(password string) error {
+	volumes, err := getZIPVolumeNames(za.fs, za.name)
+	if err != nil {
+		return err
+	}
+	r, err := openMultiVolumeReader(za.fs, volumes)
+	if err != nil {
+		return err
+	}
+	za.volumes = volumes
+	za.r = r
+	return nil
+}
+
+func (za *ZIPArchive) Close() error {
+	var errs []error
+	if za.r != nil {
+		if err := za.r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		za.r = nil
+	}
+	if c, ok := za.fs.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (za *ZIPArchive) IsStreamable() (bool, error) {
+	files, err := za.GetFiles()
+	if err != nil {
+		return false, err
+	}
+	for _, f := range files {
+		if f.IsStreamable() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (za *ZIPArchive) GetFiles() ([]ArchiveFile, error) {
+	if za.files == nil {
+		if za.r == nil {
+			return nil, errors.New("zip: archive not open")
+		}
+		files, err := za.readCentralDirectory()
+		if err != nil {
+			return nil, err
+		}
+		za.files = files
+	}
+	return za.files, nil
+}
+
+type zipDirectoryEnd struct {
+	diskNumber uint32
+	diskCount  uint32
+	dirDisk    uint32
+	dirCount   uint64
+	dirSize    uint64
+	dirOffset  uint64
+}
+
+func (za *ZIPArchive) readDirectoryEnd() (*zipDirectoryEnd, error) {
+	size := za.r.Size()
+	tailLen := min(size, zipEndOfCentralDirLen+zipMaxCommentLen)
+	tail := make([]byte, tailLen)
+	if _, err := za.r.ReadAt(tail, size-tailLen); err != nil {
+		return nil, err
+	}
+
+	pos := -1
+	for i := len(tail) - zipEndOfCentralDirLen; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) == zipEndOfCentralDirSignature {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil, errZIPEndOfCentralDirNotFound
+	}
+
+	b := tail[pos:]
+	d := &zipDirectoryEnd{
+		diskNumber: uint32(binary.LittleEndian.Uint16(b[4:])),
+		dirDisk:    uint32(binary.LittleEndian.Uint16(b[6:])),
+		dirCount:   uint64(binary.LittleEndian.Uint16(b[10:])),
+		dirSize:    uint64(binary.LittleEndian.Uint32(b[12:])),
+		dirOffset:  uint64(binary.LittleEndian.Uint32(b[16:])),
+	}
+	d.diskCount = d.diskNumber + 1
+
+	isZip64 := d.diskNumber == 0xFFFF || d.dirDisk == 0xFFFF || d.dirCount == 0xFFFF || d.dirSize == 0xFFFFFFFF || d.dirOffset == 0xFFFFFFFF
+	locPos := pos - zip64EndOfCentralDirLocatorLen
+	if !isZip64 || locPos < 0 || binary.LittleEndian.Uint32(tail[locPos:]) != zip64EndOfCentralDirLocatorSignature {
+		return d, nil
+	}
+
+	loc := tail[locPos:]
+	recDisk := binary.LittleEndian.Uint32(loc[4:])
+	recOffset := binary.LittleEndian.Uint64(loc[8:])
+	d.diskCount = binary.LittleEndian.Uint32(loc[16:])
+
+	recPos, err := za.offset(d, recDisk, recOffset)
+	if err != nil {
+		return nil, err
+	}
+	rec := make([]byte, zip64EndOfCentralDirLen)
+	if _, err := za.r.ReadAt(rec, recPos); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(rec) != zip64EndOfCentralDirSignature {
+		return nil, errZIPInvalidHeader
+	}
+	d.diskNumber = binary.LittleEndian.Uint32(rec[16:])
+	d.dirDisk = binary.LittleEndian.Uint32(rec[20:])
+	d.dirCount = binary.LittleEndian.Uint64(rec[32:])
+	d.dirSize = binary.LittleEndian.Uint64(rec[40:])
+	d.dirOffset = binary.LittleEndian.Uint64(rec[48:])
+	return d, nil
+}
+
+// offset resolves the offset on the disk to the offset in the volumes. The
+// offsets of a spanned archive are relative to the disk (volume), while the
+// split archive is a single disk cut into volumes.
+func (za *ZIPArchive) offset(d *zipDirectoryEnd, disk uint32, offset uint64) (int64, error) {
+	if d.diskCount <= 1 {
+		return int64(offset), nil
+	}
+	if int(d.diskCount) != len(za.volumes) {
+		return 0, fmt.Errorf("zip: expected %d volumes, found %d", d.diskCount, len(za.volumes))
+	}
+	start, err := za.r.Offset(int(disk))
+	if err != nil {
+		return 0, err
+	}
+	return start + int64(offset), nil
+}
+
+func (za *ZIPArchive) readCentralDirectory() ([]ArchiveFile, error) {
+	d, err := za.readDirectoryEnd()
+	if err != nil {
+		return nil, err
+	}
+	if d.dirSize > zipMaxCentralDirectorySize {
+		return nil, fmt.Errorf("zip: central directory too large: %d", d.dirSize)
+	}
+
+	dirPos, err := za.offset(d, d.dirDisk, d.dirOffset)
+	if err != nil {
+		return nil, err
+	}
+	dir := make([]byte, d.dirSize)
+	if _, err := za.r.ReadAt(dir, dirPos); err != nil {
+		return nil, err
+	}
+
+	files := []ArchiveFile{}
+	for len(dir) >= zipCentralDirectoryHeaderLen {
+		if binary.LittleEndian.Uint32(dir) != zipCentralDirectorySignature {
+			return nil, errZIPInvalidHeader
+		}
+		flags := binary.LittleEndian.Uint16(dir[8:])
+		method := binary.LittleEndian.Uint16(dir[10:])
+		packedSize := uint64(binary.LittleEndian.Uint32(dir[20:]))
+		size := uint64(binary.LittleEndian.Uint32(dir[24:]))
+		nameLen := int(binary.LittleEndian.Uint16(dir[28:]))
+		extraLen := int(binary.LittleEndian.Uint16(dir[30:]))
+		commentLen := int(binary.LittleEndian.Uint16(dir[32:]))
+		disk := uint32(binary.LittleEndian.Uint16(dir[34:]))
+		headerOffset := uint64(binary.LittleEndian.Uint32(dir[42:]))
+
+		entryLen := zipCentralDirectoryHeaderLen + nameLen + extraLen + commentLen
+		if len(dir) < entryLen {
+			return nil, errZIPInvalidHeader
+		}
+		name := string(dir[zipCentralDirectoryHeaderLen : zipCentralDirectoryHeaderLen+nameLen])
+		extra := dir[zipCentralDirectoryHeaderLen+nameLen : zipCentralDirectoryHeaderLen+nameLen+extraLen]
+		dir = dir[entryLen:]
+
+		// zip64 extra field has the values that overflowed, in this order
+		for len(extra) >= 4 {
+			id := binary.LittleEndian.Uint16(extra)
+			fieldLen := int(binary.LittleEndian.Uint16(extra[2:]))
+			if len(extra) < 4+fieldLen {
+				break
+			}
+			field := extra[4 : 4+fieldLen]
+			extra = extra[4+fieldLen:]
+			if id != zip64ExtraId {
+				continue
+			}
+			if size == 0xFFFFFFFF && len(field) >= 8 {
+				size = binary.LittleEndian.Uint64(field)
+				field = field[8:]
+			}
+			if packedSize == 0xFFFFFFFF && len(field) >= 8 {
+				packedSize = binary.LittleEndian.Uint64(field)
+				field = field[8:]
+			}
+			if headerOffset == 0xFFFFFFFF && len(field) >= 8 {
+				headerOffset = binary.LittleEndian.Uint64(field)
+				field = field[8:]
+			}
+			if disk == 0xFFFF && len(field) >= 4 {
+				disk = binary.LittleEndian.Uint32(field)
+			}
+		}
+
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+
+		headerPos, err := za.offset(d, disk, headerOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, &UsenetZIPFile{
+			a:            za,
+			name:         name,
+			unPackedSize: int64(size),
+			packedSize:   int64(packedSize),
+			headerOffset: headerPos,
+			isCompressed: method != zipMethodStore,
+			isEncrypted:  flags&zipFlagEncrypted != 0,
+		})
+	}
+	return files, nil
+}
+
+type UsenetZIPFile struct {
+	a            *ZIPArchive
+	name         string
+	unPackedSize int64
+	packedSize   int64
+	headerOffset int64
+	isCompressed bool
+	isEncrypted  bool
+}
+
+func (uzf *UsenetZIPFile) Name() string {
+	return uzf.name
+}
+
+func (uzf *UsenetZIPFile) Size() int64 {
+	return uzf.unPackedSize
+}
+
+func (uzf *UsenetZIPFile) PackedSize() int64 {
+	return uzf.packedSize
+}
+
+func (uzf *UsenetZIPFile) IsStreamable() bool {
+	return !uzf.isCompressed && !uzf.isEncrypted
+}
+
+func (uzf *UsenetZIPFile) Open() (io.ReadSeekCloser, error) {
+	if !uzf.IsStreamable() {
+		return nil, fmt.Errorf("zip: %s is not stored", uzf.name)
+	}
+
+	r, err := openMultiVolumeReader(uzf.a.fs, uzf.a.volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, zipLocalFileHeaderLen)
+	if _, err := r.ReadAt(header, uzf.headerOffset); err != nil {
+		r.Close()
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(header) != zipLocalFileHeaderSignature {
+		r.Close()
+		return nil, errZIPInvalidHeader
+	}
+	nameLen := int64(binary.LittleEndian.Uint16(header[26:]))
+	extraLen := int64(binary.LittleEndian.Uint16(header[28:]))
+	dataOffset := uzf.headerOffset + zipLocalFileHeaderLen + nameLen + extraLen
+
+	return newExtentsReader(r, r, []fileExtent{{offset: dataOffset, size: uzf.unPackedSize}}), nil
+}
+
+// .zip.001, .zip.002 format
+var zipPartNumberRegex = regexp.MustCompile(`(?i)\.zip\.(\d+)$`)
+
+// .z01, .z02 format (.zip is the last part)
+var zipZNumberRegex = regexp.MustCompile(`(?i)\.z(\d+)$`)
+
+// .zip
+var zipFirstPartRegex = regexp.MustCompile(`(?i)\.zip$`)
+
+func GetZIPVolumeNumber(filename string) int {
+	if matches := zipPartNumberRegex.FindStringSubmatch(filename); len(matches) > 1 {
+		n, _ := strconv.Atoi(matches[1])
+		return n
+	}
+
+	if matches := zipZNumberRegex.FindStringSubmatch(filename); len(matches) > 1 {
+		n, _ := strconv.Atoi(matches[1])
+		return n
+	}
+
+	if zipFirstPartRegex.MatchString(filename) {
+		return 0
+	}
+
+	return -1
+}
+
+// getZIPVolumeNames returns the names of all the volumes of the zip archive,
+// in order, from the name of any one of them.
+func getZIPVolumeNames(fsys fs.FS, name string) ([]string, error) {
+	exists := func(name string) bool {
+		_, err := fs.Stat(fsys, name)
+		return err == nil
+	}
+
+	if m := zipPartNumberRegex.FindStringSubmatchIndex(name); m != nil {
+		prefix := name[:m[2]]
+		width := m[3] - m[2]
+		volumes := []string{}
+		for i := 1; ; i++ {
+			volume := fmt.Sprintf("%s%0*d", prefix, width, i)
+			if !exists(volume) {
+				break
+			}
+			volumes = append(volumes, volume)
+		}
+		if len(volumes) == 0 {
+			return []string{name}, nil
+		}
+		return volumes, nil
+	}
+
+	base, ext := name, ".zip"
+	if m := zipZNumberRegex.FindStringIndex(name); m != nil {
+		base = name[:m[0]]
+		if name[m[0]+1] == 'Z' {
+			ext = ".ZIP"
+		}
+	} else if m := zipFirstPartRegex.FindStringIndex(name); m != nil {
+		base, ext = name[:m[0]], name[m[0]:]
+	}
+
+	volumes := []string{}
+	for i := 1; ; i++ {
+		volume := fmt.Sprintf("%s%s%02d", base, ext[:2], i)
+		if !exists(volume) {
+			break
+		}
+		volumes = append(volumes, volume)
+	}
+
+	last := base + ext
+	if !exists(last) {
+		if len(volumes) == 0 {
+			return []string{name}, nil
+		}
+		return nil, fmt.Errorf("zip: last volume %s not found", last)
+	}
+	return append(volumes, last), nil
+}
+
+func NewUsenetZIPArchive(ufs *UsenetFS) *ZIPArchive {
+	volumes := []archiveVolume{}
+	for i := range ufs.nzb.Files {
+		file := &ufs.nzb.Files[i]
+		name := file.Name()
+		n := GetZIPVolumeNumber(name)
+		if n < 0 {
+			continue
+		}
+		volumes = append(volumes, archiveVolume{n: n, name: name})
+	}
+	slices.SortStableFunc(volumes, func(a, b archiveVolume) int {
+		return a.n - b.n
+	})
+
+	var firstVolume string
+	if len(volumes) > 0 {
+		firstVolume = volumes[0].name
+	}
+
+	return &ZIPArchive{
+		fs:   ufs,
+		name: firstVolume,
+	}
+}
+
+func NewZIPArchive(fs fs.FS, name string) *ZIPArchive {
+	return &ZIPArchive{fs: fs, name: name}
+}
@@ -0,0 +1,175 @@
+package usenet_pool
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestZIP(t *testing.T, files map[string][]byte, method uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		require.NoError(t, err)
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// createTestSpannedZIP creates a two disk spanned archive with a single
+// stored file, where the offsets are relative to the disk.
+func createTestSpannedZIP(name string, data []byte) (first []byte, last []byte) {
+	le := binary.LittleEndian
+
+	var disk0 bytes.Buffer
+	disk0.Write(magicBytesZIPSpanned)
+	headerOffset := disk0.Len()
+	header := make([]byte, zipLocalFileHeaderLen)
+	le.PutUint32(header, zipLocalFileHeaderSignature)
+	le.PutUint32(header[14:], crc32.ChecksumIEEE(data))
+	le.PutUint32(header[18:], uint32(len(data)))
+	le.PutUint32(header[22:], uint32(len(data)))
+	le.PutUint16(header[26:], uint16(len(name)))
+	disk0.Write(header)
+	disk0.WriteString(name)
+	disk0.Write(data)
+
+	var disk1 bytes.Buffer
+	entry := make([]byte, zipCentralDirectoryHeaderLen)
+	le.PutUint32(entry, zipCentralDirectorySignature)
+	le.PutUint32(entry[16:], crc32.ChecksumIEEE(data))
+	le.PutUint32(entry[20:], uint32(len(data)))
+	le.PutUint32(entry[24:], uint32(len(data)))
+	le.PutUint16(entry[28:], uint16(len(name)))
+	le.PutUint16(entry[34:], 0) // disk
+	le.PutUint32(entry[42:], uint32(headerOffset))
+	disk1.Write(entry)
+	disk1.WriteString(name)
+
+	end := make([]byte, zipEndOfCentralDirLen)
+	le.PutUint32(end, zipEndOfCentralDirSignature)
+	le.PutUint16(end[4:], 1) // this disk
+	le.PutUint16(end[6:], 1) // directory disk
+	le.PutUint16(end[8:], 1)
+	le.PutUint16(end[10:], 1)
+	le.PutUint32(end[12:], uint32(disk1.Len()))
+	le.PutUint32(end[16:], 0)
+	disk1.Write(end)
+
+	return disk0.Bytes(), disk1.Bytes()
+}
+
+func TestZIPArchive(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789abcdef"), 8*1024)
+	readme := []byte("not a video")
+
+	assertVideo := func(t *testing.T, files []ArchiveFile) {
+		t.Helper()
+		var f ArchiveFile
+		for _, file := range files {
+			if file.Name() == "video.mkv" {
+				f = file
+			}
+		}
+		require.NotNil(t, f)
+		assert.True(t, f.IsStreamable())
+		assert.Equal(t, int64(len(video)), f.Size())
+
+		r, err := f.Open()
+		require.NoError(t, err)
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, video, data)
+
+		_, err = r.Seek(1000, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 100)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, video[1000:1100], buf)
+	}
+
+	t.Run("single volume", func(t *testing.T) {
+		stored := createTestZIP(t, map[string][]byte{"video.mkv": video}, zip.Store)
+		deflated := createTestZIP(t, map[string][]byte{"readme.txt": readme}, zip.Deflate)
+		fsys := fstest.MapFS{
+			"stored.zip":   {Data: stored},
+			"deflated.zip": {Data: deflated},
+		}
+
+		archive := NewZIPArchive(fsys, "stored.zip")
+		require.NoError(t, archive.Open(""))
+		defer archive.Close()
+		files, err := archive.GetFiles()
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assertVideo(t, files)
+
+		archive = NewZIPArchive(fsys, "deflated.zip")
+		require.NoError(t, archive.Open(""))
+		defer archive.Close()
+		streamable, err := archive.IsStreamable()
+		require.NoError(t, err)
+		assert.False(t, streamable)
+	})
+
+	t.Run("split volumes", func(t *testing.T) {
+		data := createTestZIP(t, map[string][]byte{"video.mkv": video, "readme.txt": readme}, zip.Store)
+		third := len(data) / 3
+		fsys := fstest.MapFS{
+			"movie.zip.001": {Data: data[:third]},
+			"movie.zip.002": {Data: data[third : 2*third]},
+			"movie.zip.003": {Data: data[2*third:]},
+		}
+
+		archive := NewZIPArchive(fsys, "movie.zip.001")
+		require.NoError(t, archive.Open(""))
+		defer archive.Close()
+		files, err := archive.GetFiles()
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+		assertVideo(t, files)
+	})
+
+	t.Run("spanned volumes", func(t *testing.T) {
+		first, last := createTestSpannedZIP("video.mkv", video)
+		fsys := fstest.MapFS{
+			"movie.z01": {Data: first},
+			"movie.zip": {Data: last},
+		}
+
+		archive := NewZIPArchive(fsys, "movie.zip")
+		require.NoError(t, archive.Open(""))
+		defer archive.Close()
+		files, err := archive.GetFiles()
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assertVideo(t, files)
+	})
+}
+
+func TestGetZIPVolumeNumber(t *testing.T) {
+	for name, expected := range map[string]int{
+		"movie.zip":     0,
+		"movie.z01":     1,
+		"movie.Z12":     12,
+		"movie.zip.001": 1,
+		"movie.zip.010": 10,
+		"movie.mkv":     -1,
+	} {
+		assert.Equal(t, expected, GetZIPVolumeNumber(name), name)
+	}
+}
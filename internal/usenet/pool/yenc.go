@@ -1,6 +1,7 @@
 package usenet_pool
 
 import (
+	"errors"
 	"io"
 
 	"github.com/MunifTanjim/stremthru/internal/logger"
@@ -113,6 +114,10 @@ func (d *YEncDecodedData) ToSegmentData() SegmentData {
 		ByteRange: d.header.ByteRange(),
 		FileSize:  d.header.FileSize,
 		Size:      d.header.PartSize,
+
+		FileName:   d.header.FileName,
+		PartNumber: d.header.PartNumber,
+		TotalParts: d.header.TotalParts,
 	}
 }
 
@@ -147,3 +152,27 @@ func (d *YEncDecoder) ReadAll() (*YEncDecodedData, error) {
 		body:   body,
 	}, nil
 }
+
+var errSegmentDataNotEncodable = errors.New("segment data is missing yenc header")
+
+// EncodeYEnc writes the segment back as yEnc encoded article body.
+func (sd *SegmentData) EncodeYEnc(w io.Writer) error {
+	if sd.FileName == "" || sd.PartNumber <= 0 {
+		return errSegmentDataNotEncodable
+	}
+	encoder, err := rapidyenc.NewEncoder(w, rapidyenc.Meta{
+		FileName:   sd.FileName,
+		FileSize:   sd.FileSize,
+		PartNumber: sd.PartNumber,
+		TotalParts: max(sd.TotalParts, sd.PartNumber),
+		Offset:     sd.ByteRange.Start,
+		PartSize:   int64(len(sd.Body)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := encoder.Write(sd.Body); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
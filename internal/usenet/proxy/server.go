@@ -0,0 +1,162 @@
+// Package usenet_proxy serves the usenet pool over NNTP, so that other
+// downloaders (e.g. SABnzbd, NZBGet) can use StremThru as their upstream.
+//
+// Only the commands needed for downloading articles are supported:
+//   - AUTHINFO USER/PASS
+//   - CAPABILITIES, MODE READER, DATE, QUIT
+//   - GROUP
+//   - ARTICLE, HEAD, BODY, STAT (by message-id)
+//
+// Reference: RFC 3977 (NNTP)
+// https://tools.ietf.org/html/rfc3977
+package usenet_proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+)
+
+var log = logger.Scoped("usenet/proxy")
+
+// StatsNZBHash is recorded as NZB hash in the usenet server stats, for the
+// articles fetched through the proxy.
+const StatsNZBHash = "nntp_proxy"
+
+var ErrServerClosed = errors.New("usenet/proxy: server closed")
+
+type Config struct {
+	Addr         string
+	GetPool      func() (*usenet_pool.Pool, error)
+	Authenticate func(user, pass string) bool
+}
+
+type Server struct {
+	addr         string
+	getPool      func() (*usenet_pool.Pool, error)
+	authenticate func(user, pass string) bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewServer(conf *Config) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		addr:         conf.Addr,
+		getPool:      conf.GetPool,
+		authenticate: conf.Authenticate,
+		ctx:          ctx,
+		cancel:       cancel,
+		conns:        map[net.Conn]struct{}{},
+	}
+}
+
+func (s *Server) Listen() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		listener.Close()
+		return ErrServerClosed
+	}
+	s.listener = listener
+	return nil
+}
+
+// Addr returns the listener's address, available after Listen.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Server) Serve() error {
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+	if listener == nil {
+		return errors.New("usenet/proxy: server is not listening")
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Go(func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+			}()
+			newSession(s, conn).serve()
+		})
+	}
+}
+
+func (s *Server) ListenAndServe() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Close stops the listener, and closes all the client connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.cancel()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,171 @@
+package usenet_proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+	"github.com/mnightingale/rapidyenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeYenc(t *testing.T, data []byte) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder, err := rapidyenc.NewEncoder(&buf, rapidyenc.Meta{
+		FileName:   "test.bin",
+		FileSize:   int64(len(data)),
+		PartNumber: 1,
+		TotalParts: 1,
+		PartSize:   int64(len(data)),
+	})
+	require.NoError(t, err)
+	_, err = encoder.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, encoder.Close())
+	return strings.Split(strings.TrimSpace(buf.String()), "\r\n")
+}
+
+func startProxy(t *testing.T, upstreams ...*nntptest.Server) *Server {
+	t.Helper()
+
+	providers := make([]usenet_pool.ProviderConfig, len(upstreams))
+	for i, upstream := range upstreams {
+		providers[i] = usenet_pool.ProviderConfig{
+			PoolConfig: nntp.PoolConfig{
+				ConnectionConfig: nntp.ConnectionConfig{
+					Host: upstream.Host(),
+					Port: upstream.Port(),
+				},
+				MaxSize: 2,
+			},
+			Priority: i,
+		}
+	}
+	pool, err := usenet_pool.NewPool(&usenet_pool.Config{Providers: providers})
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	server := NewServer(&Config{
+		Addr: "127.0.0.1:0",
+		GetPool: func() (*usenet_pool.Pool, error) {
+			return pool, nil
+		},
+		Authenticate: func(user, pass string) bool {
+			return user == "user" && pass == "pass"
+		},
+	})
+	require.NoError(t, server.Listen())
+	go server.Serve()
+	t.Cleanup(func() {
+		server.Close()
+	})
+	return server
+}
+
+func newClient(t *testing.T, server *Server, username, password string) *nntp.Client {
+	t.Helper()
+	addr := server.Addr().(*net.TCPAddr)
+	return nntp.NewClient(&nntp.ClientConfig{
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		Username: username,
+		Password: password,
+	})
+}
+
+func TestServer(t *testing.T) {
+	testData := []byte("Hello, this is test data")
+
+	// Upstream 1: does not have the article
+	upstream1 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	upstream1.SetResponse("BODY *", "430 No Such Article Found")
+	upstream1.SetResponse("STAT *", "430 No Such Article Found")
+	upstream1.SetResponse("HEAD *", "430 No Such Article Found")
+	upstream1.SetResponse("GROUP alt.binaries.test", "211 10 1 10 alt.binaries.test")
+	upstream1.Start(t)
+
+	// Upstream 2: has the article
+	upstream2 := nntptest.NewServer(t, "200 NNTP Service Ready")
+	upstream2.SetResponse("BODY <a@example.com>", "222 0 <a@example.com>", encodeYenc(t, testData))
+	upstream2.SetResponse("STAT <a@example.com>", "223 0 <a@example.com>")
+	upstream2.SetResponse("HEAD <a@example.com>", "221 0 <a@example.com>", []string{
+		"Message-ID: <a@example.com>",
+		"Subject: test.bin",
+	})
+	upstream2.SetResponse("BODY *", "430 No Such Article Found")
+	upstream2.SetResponse("STAT *", "430 No Such Article Found")
+	upstream2.Start(t)
+
+	server := startProxy(t, upstream1, upstream2)
+
+	t.Run("authentication required", func(t *testing.T) {
+		client := newClient(t, server, "", "")
+		require.NoError(t, client.Connect())
+		defer client.Close()
+
+		_, _, err := client.Stat("<a@example.com>")
+		var nntpErr *nntp.Error
+		require.True(t, errors.As(err, &nntpErr))
+		assert.Equal(t, nntp.ErrorCodeAuthRequired, nntpErr.Code)
+	})
+
+	t.Run("authentication failed", func(t *testing.T) {
+		client := newClient(t, server, "user", "wrong")
+		err := client.Connect()
+		var nntpErr *nntp.Error
+		require.True(t, errors.As(err, &nntpErr))
+		assert.Equal(t, nntp.ErrorCodeAuthentication, nntpErr.Code)
+	})
+
+	client := newClient(t, server, "user", "pass")
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	t.Run("GROUP", func(t *testing.T) {
+		group, err := client.Group("alt.binaries.test")
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), group.Number)
+	})
+
+	t.Run("BODY", func(t *testing.T) {
+		article, err := client.Body("<a@example.com>")
+		require.NoError(t, err)
+		body, err := io.ReadAll(article.Body)
+		require.NoError(t, err)
+		article.Body.Close()
+
+		data, err := usenet_pool.NewYEncDecoder(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, testData, data.ToSegmentData().Body)
+	})
+
+	t.Run("STAT", func(t *testing.T) {
+		_, messageId, err := client.Stat("<a@example.com>")
+		require.NoError(t, err)
+		assert.Equal(t, "<a@example.com>", messageId)
+
+		_, _, err = client.Stat("<missing@example.com>")
+		var nntpErr *nntp.Error
+		require.True(t, errors.As(err, &nntpErr))
+		assert.Equal(t, nntp.ErrorCodeNoSuchArticle, nntpErr.Code)
+	})
+
+	t.Run("HEAD", func(t *testing.T) {
+		article, err := client.Head("<a@example.com>")
+		require.NoError(t, err)
+		assert.Equal(t, "test.bin", article.Headers.Get("Subject"))
+	})
+
+	t.Run("by article number", func(t *testing.T) {
+		_, _, err := client.Stat("1")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,260 @@
+package usenet_proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+)
+
+// idleTimeout is how long a client can stay idle between commands.
+const idleTimeout = 10 * time.Minute
+
+const (
+	commandAuthInfo nntp.Command = "AUTHINFO"
+	commandMode     nntp.Command = "MODE"
+)
+
+type session struct {
+	s    *Server
+	conn net.Conn
+	tc   *textproto.Conn
+
+	user        string
+	pendingUser string
+	authed      bool
+}
+
+func newSession(s *Server, conn net.Conn) *session {
+	return &session{
+		s:    s,
+		conn: conn,
+		tc:   textproto.NewConn(conn),
+	}
+}
+
+func (ss *session) reply(code int, message string) error {
+	return ss.tc.PrintfLine("%d %s", code, message)
+}
+
+func (ss *session) serve() {
+	if err := ss.reply(nntp.StatusPostingNotAllowed, "StremThru NNTP Service Ready, posting prohibited"); err != nil {
+		return
+	}
+
+	for {
+		ss.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		line, err := ss.tc.ReadLine()
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if err := ss.reply(nntp.StatusSyntaxError, "Syntax error"); err != nil {
+				return
+			}
+			continue
+		}
+
+		cmd, args := nntp.Command(strings.ToUpper(fields[0])), fields[1:]
+		if cmd == nntp.CommandQuit {
+			ss.reply(nntp.StatusClosingConnection, "Bye")
+			return
+		}
+
+		if err := ss.handle(cmd, args); err != nil {
+			log.Debug("failed to write response", "error", err, "user", ss.user)
+			return
+		}
+	}
+}
+
+func (ss *session) handle(cmd nntp.Command, args []string) error {
+	switch cmd {
+	case commandAuthInfo:
+		return ss.handleAuthInfo(args)
+	case nntp.CommandCapabilities:
+		return ss.handleCapabilities()
+	case commandMode:
+		if len(args) == 1 && strings.EqualFold(args[0], "READER") {
+			return ss.reply(nntp.StatusPostingNotAllowed, "Posting prohibited")
+		}
+		return ss.reply(nntp.StatusSyntaxError, "Syntax error")
+	case nntp.CommandDate:
+		return ss.reply(nntp.StatusServerDateAndTime, time.Now().UTC().Format("20060102150405"))
+	}
+
+	if !ss.authed {
+		return ss.reply(nntp.StatusAuthenticationRequired, "Authentication required")
+	}
+
+	switch cmd {
+	case nntp.CommandGroup:
+		return ss.handleGroup(args)
+	case nntp.CommandArticle, nntp.CommandHead, nntp.CommandBody, nntp.CommandStat:
+		return ss.handleArticle(cmd, args)
+	}
+
+	return ss.reply(nntp.StatusUnknownCommand, "Unknown command")
+}
+
+func (ss *session) handleAuthInfo(args []string) error {
+	if len(args) != 2 {
+		return ss.reply(nntp.StatusSyntaxError, "Syntax error")
+	}
+	if ss.authed {
+		return ss.reply(nntp.StatusCommandNotPermitted, "Already authenticated")
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "USER":
+		ss.pendingUser = args[1]
+		return ss.reply(nntp.StatusPasswordRequired, "Password required")
+	case "PASS":
+		if ss.pendingUser == "" {
+			return ss.reply(nntp.StatusAuthenticationOutOfSequence, "Authentication commands issued out of sequence")
+		}
+		user := ss.pendingUser
+		ss.pendingUser = ""
+		if ss.s.authenticate == nil || !ss.s.authenticate(user, args[1]) {
+			log.Info("authentication failed", "user", user, "remote_addr", ss.conn.RemoteAddr().String())
+			return ss.reply(nntp.StatusAuthenticationRejected, "Authentication failed")
+		}
+		ss.user = user
+		ss.authed = true
+		log.Debug("authenticated", "user", user, "remote_addr", ss.conn.RemoteAddr().String())
+		return ss.reply(nntp.StatusAuthAccepted, "Authentication accepted")
+	}
+
+	return ss.reply(nntp.StatusSyntaxError, "Syntax error")
+}
+
+func (ss *session) handleCapabilities() error {
+	lines := []string{"VERSION 2", "IMPLEMENTATION StremThru", "READER"}
+	if !ss.authed {
+		lines = append(lines, "AUTHINFO USER")
+	}
+	if err := ss.reply(nntp.StatusCapabilityList, "Capability list follows"); err != nil {
+		return err
+	}
+	w := ss.tc.DotWriter()
+	for _, line := range lines {
+		if _, err := w.Write([]byte(line + "\r\n")); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func (ss *session) context() context.Context {
+	return context.WithValue(ss.s.ctx, usenet_pool.NZBHashContextKey, StatsNZBHash)
+}
+
+func (ss *session) handleGroup(args []string) error {
+	if len(args) != 1 {
+		return ss.reply(nntp.StatusSyntaxError, "Syntax error")
+	}
+
+	pool, err := ss.s.getPool()
+	if err != nil {
+		log.Error("failed to get pool", "error", err)
+		return ss.reply(nntp.StatusInternalFault, "Internal fault")
+	}
+
+	group, err := pool.SelectGroup(ss.context(), args[0])
+	if err != nil {
+		var nntpErr *nntp.Error
+		if errors.As(err, &nntpErr) && nntpErr.Code == nntp.ErrorCodeNoSuchGroup {
+			return ss.reply(nntp.StatusNoSuchGroup, "No such newsgroup")
+		}
+		log.Warn("failed to select group", "error", err, "group", args[0])
+		return ss.reply(nntp.StatusInternalFault, "Internal fault")
+	}
+
+	return ss.tc.PrintfLine("%d %d %d %d %s", nntp.StatusGroupSelected, group.Number, group.Low, group.High, args[0])
+}
+
+func (ss *session) handleArticle(cmd nntp.Command, args []string) error {
+	if len(args) != 1 || !strings.HasPrefix(args[0], "<") || !strings.HasSuffix(args[0], ">") {
+		// articles are looked up across providers, so the article numbers
+		// of a group are meaningless here.
+		return ss.reply(nntp.StatusFeatureNotSupported, "Only message-id lookups are supported")
+	}
+	spec := args[0]
+	messageId := strings.TrimSuffix(strings.TrimPrefix(spec, "<"), ">")
+
+	pool, err := ss.s.getPool()
+	if err != nil {
+		log.Error("failed to get pool", "error", err)
+		return ss.reply(nntp.StatusInternalFault, "Internal fault")
+	}
+
+	ctx := ss.context()
+
+	var article *usenet_pool.ArticleData
+	switch cmd {
+	case nntp.CommandArticle:
+		article, err = pool.FetchArticle(ctx, messageId)
+	case nntp.CommandHead:
+		article, err = pool.FetchArticleHead(ctx, messageId)
+	case nntp.CommandBody:
+		article, err = pool.FetchArticleBody(ctx, messageId)
+	case nntp.CommandStat:
+		err = pool.StatArticle(ctx, messageId)
+	}
+	if err != nil {
+		if errors.Is(err, usenet_pool.ErrArticleNotFound) {
+			return ss.reply(nntp.StatusNoSuchArticleNumber, "No such article")
+		}
+		log.Warn("failed to fetch article", "error", err, "command", cmd, "message_id", messageId)
+		return ss.reply(nntp.StatusInternalFault, "Internal fault")
+	}
+
+	w := ss.tc.W
+	switch cmd {
+	case nntp.CommandArticle:
+		if err := ss.reply(nntp.StatusArticle, "0 "+spec); err != nil {
+			return err
+		}
+		writeHeaders(w, article.Headers)
+		w.WriteString("\r\n")
+		w.Write(article.Body)
+	case nntp.CommandHead:
+		if err := ss.reply(nntp.StatusArticleHeaders, "0 "+spec); err != nil {
+			return err
+		}
+		writeHeaders(w, article.Headers)
+		w.WriteString(".\r\n")
+	case nntp.CommandBody:
+		if err := ss.reply(nntp.StatusArticleBody, "0 "+spec); err != nil {
+			return err
+		}
+		w.Write(article.Body)
+	case nntp.CommandStat:
+		return ss.reply(nntp.StatusArticleExists, "0 "+spec)
+	}
+	return w.Flush()
+}
+
+// writeHeaders writes the headers sorted by key, since the original order is
+// not preserved.
+func writeHeaders(w io.StringWriter, headers textproto.MIMEHeader) {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		for _, value := range headers[key] {
+			w.WriteString(key + ": " + value + "\r\n")
+		}
+	}
+}
@@ -20,6 +20,10 @@ func isExtensionAllowed(filename string, isArchive bool) bool {
 			ext = ".7z"
 		case usenet_pool.FileTypeRAR:
 			ext = ".rar"
+		case usenet_pool.FileTypeZIP:
+			ext = ".zip"
+		case usenet_pool.FileTypeISO:
+			ext = ".iso"
 		}
 	}
 	return config.WebDAVFileExtFilter.Has(ext)
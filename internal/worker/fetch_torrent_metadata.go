@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/MunifTanjim/stremthru/internal/torrent_metadata"
+	ts "github.com/MunifTanjim/stremthru/internal/torrent_stream"
+	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
+)
+
+// hashes whose metadata could not be fetched are not retried till this
+// duration passes.
+var torrentMetadataFailedCache = cache.NewLRUCache[bool](&cache.CacheConfig{
+	Name:     "worker:fetch-torrent-metadata:failed",
+	Lifetime: 24 * time.Hour,
+	MaxSize:  50_000,
+})
+
+func InitFetchTorrentMetadataWorker(conf *WorkerConfig) *Worker {
+	fetcher := torrent_metadata.NewFetcher(&torrent_metadata.FetcherConfig{
+		Timeout:  config.Torz.MetadataFetchTimeout,
+		MaxPeers: config.Torz.MetadataFetchMaxPeers,
+		Trackers: config.Torz.ScrapeTrackers,
+	})
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, config.Torz.MetadataFetchConcurrency)
+		var fetchedCount, failedCount atomic.Int32
+
+		worker_queue.MetadataFetcherQueue.Process(func(item worker_queue.MetadataFetcherQueueItem) error {
+			hash := item.Hash
+			if torrentMetadataFailedCache.Has(hash) {
+				return nil
+			}
+
+			filesByHash, err := ts.GetFilesByHashes([]string{hash})
+			if err != nil {
+				return err
+			}
+			if len(filesByHash[hash]) > 0 {
+				return nil
+			}
+
+			sem <- struct{}{}
+			wg.Go(func() {
+				defer func() { <-sem }()
+
+				start := time.Now()
+				info, err := fetcher.Fetch(context.Background(), hash)
+				if err != nil {
+					log.Debug("failed to fetch metadata", "error", core.PackError(err), "hash", hash, "duration", time.Since(start))
+					torrentMetadataFailedCache.Add(hash, true)
+					failedCount.Add(1)
+					return
+				}
+
+				files := ts.FilesFromTorrentInfo(info)
+				items := make([]ts.InsertData, len(files))
+				for i := range files {
+					items[i] = ts.InsertData{Hash: hash, File: files[i]}
+				}
+				if err := ts.Record(items, false); err != nil {
+					log.Error("failed to record files", "error", core.PackError(err), "hash", hash)
+					return
+				}
+				if err := torrent_info.SetSize(hash, info.TotalLength()); err != nil {
+					log.Error("failed to set size", "error", core.PackError(err), "hash", hash)
+				}
+				log.Debug("fetched metadata", "hash", hash, "file_count", len(files), "duration", time.Since(start))
+				fetchedCount.Add(1)
+			})
+			return nil
+		})
+
+		wg.Wait()
+
+		if count := fetchedCount.Load() + failedCount.Load(); count > 0 {
+			log.Info("fetched torrent metadata", "count", fetchedCount.Load(), "failed_count", failedCount.Load())
+		}
+
+		return nil
+	}
+
+	return NewWorker(conf)
+}
@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"context"
+
+	usenet_indexer "github.com/MunifTanjim/stremthru/internal/usenet/indexer"
+)
+
+func InitIndexUsenetGroupsWorker(conf *WorkerConfig) *Worker {
+	conf.Executor = func(w *Worker) error {
+		return usenet_indexer.Scan(context.Background(), w.Log)
+	}
+
+	return NewWorker(conf)
+}
@@ -5,8 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/MunifTanjim/stremthru/core"
-	"github.com/MunifTanjim/stremthru/internal/buddy"
 	"github.com/MunifTanjim/stremthru/internal/magnet_cache"
 	"github.com/MunifTanjim/stremthru/internal/peer"
 	"github.com/MunifTanjim/stremthru/internal/shared"
@@ -49,7 +47,7 @@ func InitMagnetCachePullerWorker(conf *WorkerConfig) *Worker {
 			}
 
 			for i, cHashes := range slices.Collect(slices.Chunk(hashes, 500)) {
-				if buddy.Peer.IsHaltedCheckMagnet() {
+				if peer.Federation.IsHaltedCheckMagnet() {
 					time.Sleep(15 * time.Second)
 				}
 
@@ -59,47 +57,39 @@ func InitMagnetCachePullerWorker(conf *WorkerConfig) *Worker {
 				storeToken := storeTokens[i%len(storeTokens)]
 				clientIp := clientIps[i%len(clientIps)]
 
-				params := &peer.CheckMagnetParams{
+				res := peer.Federation.CheckMagnet(&peer.FederationCheckMagnetParams{
 					StoreName:  s.GetName(),
 					StoreToken: storeToken,
-				}
-				params.Magnets = cHashes
-				params.ClientIP = clientIp
-				params.SId = sid
-				start := time.Now()
-				res, err := buddy.Peer.CheckMagnet(params)
-				duration := time.Since(start)
-				if duration.Seconds() > 10 {
-					Peer.HaltCheckMagnet()
-				}
-				if err != nil {
-					w.Log.Error("failed partially to check magnet", "store.name", s.GetName(), "error", core.PackError(err), "duration", duration)
-				} else {
-					w.Log.Info("check magnet", "store.name", s.GetName(), "hash_count", len(cHashes), "duration", duration)
-					for _, item := range res.Data.Items {
-						files := torrent_stream.Files{}
-						if item.Status == store.MagnetStatusCached {
-							cached[item.Hash] = true
-							seenByName := map[string]bool{}
-							for _, f := range item.Files {
-								if _, seen := seenByName[f.Name]; seen {
-									w.Log.Info("found duplicate file", "hash", item.Hash, "filename", f.Name)
-									continue
-								}
-								seenByName[f.Name] = true
-								files = append(files, torrent_stream.File{
-									Idx:       f.Idx,
-									Path:      f.Path,
-									Name:      f.Name,
-									Size:      f.Size,
-									Source:    f.Source,
-									VideoHash: f.VideoHash,
-									MediaInfo: f.MediaInfo,
-								})
+					Hashes:     cHashes,
+					ClientIP:   clientIp,
+					SId:        sid,
+				})
+				for _, item := range res.Items {
+					if res.Untrusted[item.Hash] {
+						continue
+					}
+					files := torrent_stream.Files{}
+					if item.Status == store.MagnetStatusCached {
+						cached[item.Hash] = true
+						seenByName := map[string]bool{}
+						for _, f := range item.Files {
+							if _, seen := seenByName[f.Name]; seen {
+								w.Log.Info("found duplicate file", "hash", item.Hash, "filename", f.Name)
+								continue
 							}
+							seenByName[f.Name] = true
+							files = append(files, torrent_stream.File{
+								Idx:       f.Idx,
+								Path:      f.Path,
+								Name:      f.Name,
+								Size:      f.Size,
+								Source:    f.Source,
+								VideoHash: f.VideoHash,
+								MediaInfo: f.MediaInfo,
+							})
 						}
-						filesByHash[item.Hash] = files
 					}
+					filesByHash[item.Hash] = files
 				}
 
 				magnet_cache.BulkTouch(s.GetName().Code(), filesByHash, cached, false)
@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/MunifTanjim/stremthru/internal/tracker"
+	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
+)
+
+// tracker is skipped for this duration after a failed scrape
+const trackerScrapeFailureBackoff = 10 * time.Minute
+
+// requested minimum interval upto this duration is waited for, otherwise the
+// tracker is skipped till the next run.
+const trackerScrapeMaxWait = 1 * time.Minute
+
+type trackerScrapeState struct {
+	url      string
+	hostname string
+	limiter  *ratelimit.Limiter
+	nextAt   time.Time
+}
+
+func newTrackerScrapeState(trackerUrl string) (*trackerScrapeState, error) {
+	u, err := url.Parse(trackerUrl)
+	if err != nil {
+		return nil, err
+	}
+	rateLimit := config.Torz.GetScrapeRateLimit(u.Hostname())
+	limiter, err := ratelimit.NewLimiter(&ratelimit.RateLimitConfig{
+		Limit:  rateLimit.Limit,
+		Window: rateLimit.Window.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &trackerScrapeState{
+		url:      trackerUrl,
+		hostname: u.Hostname(),
+		limiter:  limiter,
+	}, nil
+}
+
+func InitScrapeTrackersWorker(conf *WorkerConfig) *Worker {
+	client := tracker.NewClient(&tracker.ClientConfig{})
+
+	states := []*trackerScrapeState{}
+	for _, trackerUrl := range config.Torz.ScrapeTrackers {
+		state, err := newTrackerScrapeState(trackerUrl)
+		if err != nil {
+			panic(err)
+		}
+		states = append(states, state)
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		worker_queue.TrackerScraperQueue.ProcessGroup(func(_ string, items []worker_queue.TrackerScraperQueueItem) error {
+			hashes := make([]string, len(items))
+			for i := range items {
+				hashes[i] = items[i].Hash
+			}
+
+			hashes, err := torrent_info.ListHashesToScrape(hashes, time.Now().Add(-config.Torz.ScrapeStaleTime))
+			if err != nil {
+				return err
+			}
+			if len(hashes) == 0 {
+				return nil
+			}
+
+			resultByHash := map[string]tracker.ScrapeResult{}
+			answered := map[string]struct{}{}
+			for _, state := range states {
+				for cHashes := range slices.Chunk(hashes, tracker.MaxScrapeHashes) {
+					if wait := time.Until(state.nextAt); wait > trackerScrapeMaxWait {
+						log.Debug("skipping tracker", "tracker", state.hostname, "next_at", state.nextAt)
+						break
+					} else if wait > 0 {
+						time.Sleep(wait)
+					}
+
+					if err := state.limiter.Wait("tracker-scrape:" + state.hostname); err != nil {
+						return err
+					}
+
+					start := time.Now()
+					res, err := client.Scrape(context.Background(), state.url, cHashes)
+					if err != nil {
+						if errors.Is(err, tracker.ErrScrapeUnsupported) {
+							log.Warn("tracker does not support scrape", "tracker", state.hostname)
+							state.nextAt = time.Now().Add(24 * time.Hour)
+						} else {
+							log.Error("failed to scrape tracker", "error", core.PackError(err), "tracker", state.hostname, "duration", time.Since(start))
+							state.nextAt = time.Now().Add(trackerScrapeFailureBackoff)
+						}
+						break
+					}
+					log.Debug("scraped tracker", "tracker", state.hostname, "count", len(cHashes), "duration", time.Since(start))
+					if res.MinRequestInterval > 0 {
+						state.nextAt = time.Now().Add(res.MinRequestInterval)
+					}
+
+					for _, hash := range cHashes {
+						answered[hash] = struct{}{}
+					}
+					for hash, result := range res.Files {
+						if prev, ok := resultByHash[hash]; !ok || result.Seeders > prev.Seeders || (result.Seeders == prev.Seeders && result.Leechers > prev.Leechers) {
+							resultByHash[hash] = result
+						}
+					}
+				}
+			}
+
+			updates := []torrent_info.SeedersUpdate{}
+			for _, hash := range hashes {
+				if result, ok := resultByHash[hash]; ok && result.Seeders+result.Leechers+result.Completed > 0 {
+					updates = append(updates, torrent_info.SeedersUpdate{
+						Hash:     hash,
+						Seeders:  result.Seeders,
+						Leechers: result.Leechers,
+					})
+				} else if _, ok := answered[hash]; ok {
+					updates = append(updates, torrent_info.SeedersUpdate{
+						Hash:    hash,
+						Unknown: true,
+					})
+				}
+			}
+			if err := torrent_info.UpdateSeeders(updates); err != nil {
+				return err
+			}
+			log.Info("updated seeders", "count", len(updates), "total_count", len(hashes), "tracker_count", len(states))
+			return nil
+		})
+
+		return nil
+	}
+
+	return NewWorker(conf)
+}
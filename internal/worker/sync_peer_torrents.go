@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/imdb_torrent"
+	"github.com/MunifTanjim/stremthru/internal/kv"
+	"github.com/MunifTanjim/stremthru/internal/peer"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+)
+
+var syncPeerTorrentsCursor = kv.NewKVStore[string](&kv.KVStoreConfig{
+	Type: "job:sync-peer-torrents:cursor",
+})
+
+func ResetSyncPeerTorrentsCursor() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if running_worker.sync_peer_torrents {
+		return ErrInProgress
+	}
+	for _, p := range peer.Federation.SyncPeers() {
+		if err := syncPeerTorrentsCursor.Del(p.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func InitSyncPeerTorrentsWorker(conf *WorkerConfig) *Worker {
+	limit := 2000
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		for _, p := range peer.Federation.SyncPeers() {
+			cursor := ""
+			if err := syncPeerTorrentsCursor.GetValue(p.Name, &cursor); err != nil {
+				return err
+			}
+
+			totalCount := 0
+			hasMore := true
+			for hasMore {
+				changes, err := p.ListTorrentChanges(cursor, limit)
+				if err != nil {
+					log.Error("failed to list torrent changes", "error", core.PackError(err), "peer.name", p.Name)
+					break
+				}
+
+				if err := torrent_info.UpsertChanged(changes.Torrents, "", false); err != nil {
+					return err
+				}
+				if err := imdb_torrent.InsertMissing(changes.IMDBTorrents); err != nil {
+					return err
+				}
+				if err := anidb.UpsertChangedTorrents(changes.AniDBTorrents); err != nil {
+					return err
+				}
+
+				count := len(changes.Torrents) + len(changes.IMDBTorrents) + len(changes.AniDBTorrents)
+				totalCount += count
+				log.Info("synced torrent changes", "peer.name", p.Name, "torrent_count", len(changes.Torrents), "imdb_count", len(changes.IMDBTorrents), "anidb_count", len(changes.AniDBTorrents), "total_count", totalCount)
+
+				if nextCursor := changes.Cursor.String(); nextCursor != cursor {
+					if err := syncPeerTorrentsCursor.Set(p.Name, nextCursor); err != nil {
+						return err
+					}
+					cursor = nextCursor
+					log.Debug("stored cursor", "peer.name", p.Name, "cursor", cursor)
+				}
+
+				hasMore = changes.HasMore
+			}
+		}
+
+		return nil
+	}
+
+	return NewWorker(conf)
+}
@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anilist"
+	anilist_account "github.com/MunifTanjim/stremthru/internal/anilist/account"
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_anilist"
+)
+
+type anilistAnimeTracker struct {
+	client *anilist.APIClient
+	userId int
+}
+
+func (t anilistAnimeTracker) GetListEntries() (map[int]animeTrackerEntry, error) {
+	entries, err := t.client.GetAnimeListEntries(t.userId)
+	if err != nil {
+		return nil, err
+	}
+	entryById := make(map[int]animeTrackerEntry, len(entries))
+	for _, e := range entries {
+		entryById[e.MediaId] = animeTrackerEntry{
+			Id:        e.MediaId,
+			Progress:  e.Progress,
+			Episodes:  e.Episodes,
+			UpdatedAt: e.UpdatedAt,
+		}
+	}
+	return entryById, nil
+}
+
+func (t anilistAnimeTracker) GetEpisodeCount(ids []int) (map[int]int, error) {
+	return t.client.FetchAnimeEpisodeCount(ids)
+}
+
+func (t anilistAnimeTracker) GetIdMaps(ids []int) ([]anime.AnimeIdMap, error) {
+	return anime.GetIdMapsForAniList(ids)
+}
+
+func (t anilistAnimeTracker) GetId(idMap *anime.AnimeIdMap) int {
+	id, _ := strconv.Atoi(idMap.AniList)
+	return id
+}
+
+func (t anilistAnimeTracker) SaveProgress(id, progress int, completed bool) error {
+	status := anilist.MediaListStatusCurrent
+	if completed {
+		status = anilist.MediaListStatusCompleted
+	}
+	_, err := t.client.SaveMediaListEntry(&anilist.SaveMediaListEntryParams{
+		MediaId:  id,
+		Status:   status,
+		Progress: progress,
+	})
+	return err
+}
+
+func InitSyncStremioAniListWorker(conf *WorkerConfig) *Worker {
+	syncWatched := func(link *sync_stremio_anilist.SyncStremioAniListLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"anilist_account_id", link.AniListAccountId,
+		)
+
+		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+		if err != nil || stremioAccount == nil {
+			return fmt.Errorf("stremio account not found: %w", err)
+		}
+
+		anilistAccount, err := anilist_account.GetById(link.AniListAccountId)
+		if err != nil || anilistAccount == nil {
+			return fmt.Errorf("anilist account not found: %w", err)
+		}
+
+		stremioToken, err := stremioAccount.GetValidToken()
+		if err != nil {
+			return err
+		}
+
+		userId, err := strconv.Atoi(anilistAccount.Id)
+		if err != nil {
+			return err
+		}
+
+		ctx := &syncStremioAnimeCtx{
+			now:           time.Now(),
+			log:           log,
+			stremioClient: stremio_api.NewClient(&stremio_api.ClientConfig{}),
+			stremioToken:  stremioToken,
+			tracker: anilistAnimeTracker{
+				client: anilist.GetAPIClient(anilistAccount.OAuthTokenId),
+				userId: userId,
+			},
+		}
+		if link.SyncState.Watched.LastSyncedAt != nil {
+			ctx.startAt = *link.SyncState.Watched.LastSyncedAt
+		}
+		ctx.isFullSync = ctx.startAt.IsZero()
+
+		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", ctx.startAt)
+
+		if err := fetchAnimeStremioItems(ctx); err != nil {
+			return err
+		}
+
+		log.Debug("fetched stremio items", "count", len(ctx.stremioItems))
+
+		if ctx.trackerEntries, err = ctx.tracker.GetListEntries(); err != nil {
+			return err
+		}
+
+		log.Debug("fetched anilist entries", "count", len(ctx.trackerEntries))
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToAniList() {
+			count, err := syncAnimeFromStremioToTracker(ctx)
+			if err != nil {
+				log.Error("failed to sync from stremio to anilist", "error", err)
+				return err
+			}
+			log.Debug("synced from stremio to anilist", "count", count)
+		}
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToStremio() {
+			count, err := syncAnimeFromTrackerToStremio(ctx)
+			if err != nil {
+				log.Error("failed to sync from anilist to stremio", "error", err)
+				return err
+			}
+			log.Debug("synced from anilist to stremio", "count", count)
+		}
+
+		link.SyncState.Watched.LastSyncedAt = &ctx.now
+		sync_stremio_anilist.SetSyncState(link.StremioAccountId, link.AniListAccountId, link.SyncState)
+		return nil
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		links, err := sync_stremio_anilist.GetAll()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if !link.SyncConfig.Watched.Direction.IsDisabled() {
+				if err := syncWatched(&link, log); err != nil {
+					log.Error("failed to sync link", "error", err,
+						"stremio_account_id", link.StremioAccountId,
+						"anilist_account_id", link.AniListAccountId,
+					)
+				}
+			}
+		}
+
+		return nil
+	}
+	return NewWorker(conf)
+}
@@ -0,0 +1,532 @@
+package worker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/stremio/cinemeta"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+)
+
+type animeTrackerEntry struct {
+	Id        int
+	Progress  int
+	Episodes  int
+	UpdatedAt time.Time
+}
+
+// animeTracker is the common surface of anime list services (AniList, MAL)
+// used by the stremio <-> tracker watched sync.
+type animeTracker interface {
+	GetListEntries() (map[int]animeTrackerEntry, error)
+	GetEpisodeCount(ids []int) (map[int]int, error)
+	GetIdMaps(ids []int) ([]anime.AnimeIdMap, error)
+	GetId(idMap *anime.AnimeIdMap) int
+	SaveProgress(id, progress int, completed bool) error
+}
+
+type animeStremioProgress struct {
+	Progress    int
+	Completed   bool
+	LastWatched time.Time
+}
+
+type animeStremioItemRef struct {
+	item    *stremio_api.LibraryItem
+	idType  string // kitsu / imdb / tvdb
+	id      string
+	season  int
+	episode int
+}
+
+type syncStremioAnimeCtx struct {
+	now        time.Time
+	log        *logger.Logger
+	isFullSync bool
+	startAt    time.Time
+
+	stremioClient *stremio_api.Client
+	stremioToken  string
+	stremioItems  []stremio_api.LibraryItem
+
+	tracker        animeTracker
+	trackerEntries map[int]animeTrackerEntry
+}
+
+func isAnimeCandidateStremioId(id string) bool {
+	return strings.HasPrefix(id, "kitsu:") || strings.HasPrefix(id, "tt") || strings.HasPrefix(id, "tvdb:")
+}
+
+// parseAnimeStremioItemRef resolves the last watched video of a library item,
+// using the anchor of the watched bitfield for series.
+func parseAnimeStremioItemRef(item *stremio_api.LibraryItem) *animeStremioItemRef {
+	ref := &animeStremioItemRef{item: item}
+
+	videoId := item.Id
+	if item.Type == "series" {
+		if item.State.Watched == "" {
+			return nil
+		}
+		wf := stremio_watched_bitfield.WatchedField{}
+		if err := wf.UnmarshalText([]byte(item.State.Watched)); err != nil {
+			return nil
+		}
+		videoId = wf.AnchorVideo
+	} else if item.State.TimesWatched == 0 {
+		return nil
+	}
+
+	parts := strings.Split(videoId, ":")
+	switch {
+	case parts[0] == "kitsu" && len(parts) >= 2:
+		ref.idType, ref.id = "kitsu", parts[1]
+		if len(parts) == 3 {
+			ref.episode = util.SafeParseInt(parts[2], 0)
+		}
+	case parts[0] == "tvdb" && len(parts) >= 2:
+		ref.idType, ref.id = "tvdb", parts[1]
+		if len(parts) == 4 {
+			ref.season, ref.episode = util.SafeParseInt(parts[2], 0), util.SafeParseInt(parts[3], 0)
+		}
+	case strings.HasPrefix(parts[0], "tt"):
+		ref.idType, ref.id = "imdb", parts[0]
+		if len(parts) == 3 {
+			ref.season, ref.episode = util.SafeParseInt(parts[1], 0), util.SafeParseInt(parts[2], 0)
+		}
+	default:
+		return nil
+	}
+
+	if item.Type == "series" && ref.episode < 1 {
+		return nil
+	}
+	return ref
+}
+
+func getRegularTVDBEpisodeMaps(anidbId string) (anidb.AniDBTVDBEpisodeMaps, error) {
+	result, err := anidb.GetTVDBEpisodeMaps(anidbId, false)
+	if err != nil {
+		return nil, err
+	}
+	maps := anidb.AniDBTVDBEpisodeMaps{}
+	for _, m := range result.Val() {
+		if m.IsAniDBRegularSeason() && m.TVDBSeason > 0 {
+			maps = append(maps, m)
+		}
+	}
+	return maps, nil
+}
+
+// resolveAniDBEpisode maps a tvdb season/episode to the episode of the anidb
+// entry described by maps. If the whole entry airs before the given episode,
+// isBefore is true.
+func resolveAniDBEpisode(maps anidb.AniDBTVDBEpisodeMaps, season, episode int) (anidbEpisode int, isBefore bool) {
+	if len(maps) == 0 {
+		return 0, false
+	}
+	isBefore = true
+	for _, m := range maps {
+		if m.TVDBSeason == season {
+			for anidbEp, tvdbEps := range m.Map {
+				for _, tvdbEp := range tvdbEps {
+					if tvdbEp == episode {
+						return anidbEp, false
+					}
+				}
+			}
+			ep := episode - m.Offset
+			if ep >= 1 && (m.Start == 0 || ep >= m.Start) && (m.End == 0 || ep <= m.End) {
+				return ep, false
+			}
+			if m.End == 0 || episode <= m.TVDBEpisodeEnd() {
+				isBefore = false
+			}
+		} else if m.TVDBSeason > season {
+			isBefore = false
+		}
+	}
+	return 0, isBefore
+}
+
+func getAnimeStremioProgress(ctx *syncStremioAnimeCtx) (map[int]*animeStremioProgress, error) {
+	refs := []animeStremioItemRef{}
+	idsByType := map[string][]string{}
+	for i := range ctx.stremioItems {
+		if ref := parseAnimeStremioItemRef(&ctx.stremioItems[i]); ref != nil {
+			refs = append(refs, *ref)
+			idsByType[ref.idType] = append(idsByType[ref.idType], ref.id)
+		}
+	}
+
+	idMapsByKey := map[string][]anime.AnimeIdMap{}
+	for idType, ids := range idsByType {
+		var idMaps []anime.AnimeIdMap
+		var err error
+		switch idType {
+		case "kitsu":
+			idMaps, err = anime.GetIdMapsForKitsu(ids)
+		case "imdb":
+			idMaps, err = anime.GetIdMapsForIMDB(ids)
+		case "tvdb":
+			idMaps, err = anime.GetIdMapsForTVDB(ids)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, idMap := range idMaps {
+			var key string
+			switch idType {
+			case "kitsu":
+				key = idType + ":" + idMap.Kitsu
+			case "imdb":
+				key = idType + ":" + idMap.IMDB
+			case "tvdb":
+				key = idType + ":" + idMap.TVDB
+			}
+			idMapsByKey[key] = append(idMapsByKey[key], idMap)
+		}
+	}
+
+	progressById := map[int]*animeStremioProgress{}
+	setProgress := func(id, progress int, completed bool, lastWatched time.Time) {
+		if id == 0 {
+			return
+		}
+		p, ok := progressById[id]
+		if !ok {
+			p = &animeStremioProgress{}
+			progressById[id] = p
+		}
+		p.Progress = max(p.Progress, progress)
+		p.Completed = p.Completed || completed
+		if lastWatched.After(p.LastWatched) {
+			p.LastWatched = lastWatched
+		}
+	}
+
+	for _, ref := range refs {
+		idMaps := idMapsByKey[ref.idType+":"+ref.id]
+		if len(idMaps) == 0 {
+			continue
+		}
+		lastWatched := ref.item.State.LastWatched
+
+		if ref.item.Type == "movie" {
+			for i := range idMaps {
+				if len(idMaps) == 1 || idMaps[i].Type == anime.AnimeIdMapTypeMovie {
+					setProgress(ctx.tracker.GetId(&idMaps[i]), 1, true, lastWatched)
+				}
+			}
+			continue
+		}
+
+		if ref.idType == "kitsu" {
+			setProgress(ctx.tracker.GetId(&idMaps[0]), ref.episode, false, lastWatched)
+			continue
+		}
+
+		for i := range idMaps {
+			idMap := &idMaps[i]
+			if idMap.AniDB == "" {
+				continue
+			}
+			maps, err := getRegularTVDBEpisodeMaps(idMap.AniDB)
+			if err != nil {
+				return nil, err
+			}
+			anidbEpisode, isBefore := resolveAniDBEpisode(maps, ref.season, ref.episode)
+			if anidbEpisode > 0 || isBefore {
+				setProgress(ctx.tracker.GetId(idMap), anidbEpisode, isBefore, lastWatched)
+			}
+		}
+	}
+
+	return progressById, nil
+}
+
+func syncAnimeFromStremioToTracker(ctx *syncStremioAnimeCtx) (int, error) {
+	progressById, err := getAnimeStremioProgress(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	missingEpisodeCountIds := []int{}
+	for id, p := range progressById {
+		if entry, ok := ctx.trackerEntries[id]; (!ok || entry.Episodes == 0) && p.Completed {
+			missingEpisodeCountIds = append(missingEpisodeCountIds, id)
+		}
+	}
+	episodeCountById := map[int]int{}
+	if len(missingEpisodeCountIds) > 0 {
+		if episodeCountById, err = ctx.tracker.GetEpisodeCount(missingEpisodeCountIds); err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for id, p := range progressById {
+		entry, hasEntry := ctx.trackerEntries[id]
+		episodes := entry.Episodes
+		if episodes == 0 {
+			episodes = episodeCountById[id]
+		}
+
+		progress := p.Progress
+		if p.Completed {
+			progress = max(progress, episodes)
+		}
+		if progress == 0 || (hasEntry && entry.Progress >= progress) {
+			continue
+		}
+		if episodes > 0 {
+			progress = min(progress, episodes)
+		}
+
+		if err := ctx.tracker.SaveProgress(id, progress, episodes > 0 && progress >= episodes); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func markKitsuStremioItemWatched(item *stremio_api.LibraryItem, kitsuId string, progress, episodes int) (bool, error) {
+	if item.Type == "movie" {
+		if item.State.TimesWatched > 0 {
+			return false, nil
+		}
+		item.State.TimesWatched = 1
+		return true, nil
+	}
+
+	length := max(progress, episodes)
+	if item.State.Watched != "" {
+		wf := stremio_watched_bitfield.WatchedField{}
+		if err := wf.UnmarshalText([]byte(item.State.Watched)); err == nil {
+			length = max(length, wf.AnchorLength)
+		}
+	}
+	videoIds := make([]string, length)
+	for i := range length {
+		videoIds[i] = "kitsu:" + kitsuId + ":" + util.IntToString(i+1)
+	}
+
+	return setStremioItemVideosWatched(item, videoIds, videoIds[:progress])
+}
+
+func markIMDBStremioItemWatched(item *stremio_api.LibraryItem, anidbId string, progress int) (bool, error) {
+	if item.Type == "movie" {
+		if item.State.TimesWatched > 0 {
+			return false, nil
+		}
+		item.State.TimesWatched = 1
+		return true, nil
+	}
+
+	maps, err := getRegularTVDBEpisodeMaps(anidbId)
+	if err != nil || len(maps) == 0 {
+		return false, err
+	}
+
+	watchedVideoIds := []string{}
+	for ep := 1; ep <= progress; ep++ {
+		m := maps.GetByAnidbEpisode(ep)
+		if m == nil {
+			continue
+		}
+		if tvdbEpisode := m.GetTMDBEpisode(ep); tvdbEpisode > 0 {
+			watchedVideoIds = append(watchedVideoIds, item.Id+":"+util.IntToString(m.TVDBSeason)+":"+util.IntToString(tvdbEpisode))
+		}
+	}
+	if len(watchedVideoIds) == 0 {
+		return false, nil
+	}
+
+	meta, err := cinemeta.FetchMeta("series", item.Id)
+	if err != nil {
+		return false, err
+	}
+	videoIds := make([]string, len(meta.Videos))
+	for i := range meta.Videos {
+		videoIds[i] = meta.Videos[i].Id
+	}
+
+	return setStremioItemVideosWatched(item, videoIds, watchedVideoIds)
+}
+
+func setStremioItemVideosWatched(item *stremio_api.LibraryItem, videoIds, watchedVideoIds []string) (bool, error) {
+	var wbf *stremio_watched_bitfield.WatchedBitField
+	if item.State.Watched != "" {
+		var err error
+		if wbf, err = stremio_watched_bitfield.NewWatchedBitFieldFromString(item.State.Watched, videoIds); err != nil {
+			return false, err
+		}
+	} else {
+		wbf = stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8WithValues(nil, len(videoIds)), videoIds)
+	}
+
+	needsUpdate := false
+	for _, videoId := range watchedVideoIds {
+		if !wbf.GetVideo(videoId) {
+			wbf.SetVideo(videoId, true)
+			needsUpdate = true
+		}
+	}
+	if !needsUpdate {
+		return false, nil
+	}
+
+	watchedStr, err := wbf.String()
+	if err != nil {
+		return false, err
+	}
+	item.State.Watched = watchedStr
+	if videoId := wbf.GetNextUnwatchedVideoId(); videoId != item.State.VideoId {
+		item.State.VideoId = videoId
+		item.State.TimeOffset = 0
+	}
+	return true, nil
+}
+
+func syncAnimeFromTrackerToStremio(ctx *syncStremioAnimeCtx) (int, error) {
+	entryIds := []int{}
+	for id, entry := range ctx.trackerEntries {
+		if entry.Progress == 0 {
+			continue
+		}
+		if !ctx.isFullSync && !entry.UpdatedAt.After(ctx.startAt) {
+			continue
+		}
+		entryIds = append(entryIds, id)
+	}
+	if len(entryIds) == 0 {
+		return 0, nil
+	}
+
+	idMaps, err := ctx.tracker.GetIdMaps(entryIds)
+	if err != nil {
+		return 0, err
+	}
+
+	stremioItemById := map[string]stremio_api.LibraryItem{}
+	for _, item := range ctx.stremioItems {
+		stremioItemById[item.Id] = item
+	}
+	if !ctx.isFullSync {
+		var idsToFetch []string
+		for i := range idMaps {
+			idMap := &idMaps[i]
+			if idMap.Kitsu != "" {
+				if _, ok := stremioItemById["kitsu:"+idMap.Kitsu]; !ok {
+					idsToFetch = append(idsToFetch, "kitsu:"+idMap.Kitsu)
+				}
+			}
+			if idMap.IMDB != "" {
+				if _, ok := stremioItemById[idMap.IMDB]; !ok {
+					idsToFetch = append(idsToFetch, idMap.IMDB)
+				}
+			}
+		}
+		if len(idsToFetch) > 0 {
+			res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+				Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+				Ids: idsToFetch,
+			})
+			if err != nil {
+				return 0, err
+			}
+			for _, item := range res.Data {
+				stremioItemById[item.Id] = item
+			}
+		}
+	}
+
+	itemsToUpdate := []stremio_api.LibraryItem{}
+	updatedIdx := map[string]int{}
+	for i := range idMaps {
+		idMap := &idMaps[i]
+		entry, ok := ctx.trackerEntries[ctx.tracker.GetId(idMap)]
+		if !ok {
+			continue
+		}
+
+		var item stremio_api.LibraryItem
+		var updated bool
+		if item, ok = stremioItemById["kitsu:"+idMap.Kitsu]; idMap.Kitsu != "" && ok && !item.Removed {
+			if updated, err = markKitsuStremioItemWatched(&item, idMap.Kitsu, entry.Progress, entry.Episodes); err != nil {
+				return 0, err
+			}
+		} else if item, ok = stremioItemById[idMap.IMDB]; idMap.IMDB != "" && idMap.AniDB != "" && ok && !item.Removed {
+			if updated, err = markIMDBStremioItemWatched(&item, idMap.AniDB, entry.Progress); err != nil {
+				ctx.log.Warn("failed to mark stremio item watched", "error", err, "id", item.Id)
+				continue
+			}
+		}
+		if !updated {
+			continue
+		}
+
+		item.MTime = stremio_api.JSONTime{Time: ctx.now}
+		if entry.UpdatedAt.After(item.State.LastWatched) {
+			item.State.LastWatched = entry.UpdatedAt
+		}
+		// multiple anime entries can point to the same imdb series
+		stremioItemById[item.Id] = item
+		if idx, ok := updatedIdx[item.Id]; ok {
+			itemsToUpdate[idx] = item
+		} else {
+			updatedIdx[item.Id] = len(itemsToUpdate)
+			itemsToUpdate = append(itemsToUpdate, item)
+		}
+	}
+
+	if len(itemsToUpdate) == 0 {
+		return 0, nil
+	}
+
+	_, err = ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+		Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+		Changes: itemsToUpdate,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(itemsToUpdate), nil
+}
+
+func fetchAnimeStremioItems(ctx *syncStremioAnimeCtx) error {
+	var stremioItemIds []string
+	if !ctx.isFullSync {
+		tsRes, err := ctx.stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken}})
+		if err != nil {
+			return err
+		}
+		for _, ts := range tsRes.Data {
+			if isAnimeCandidateStremioId(ts.Id) && ts.ModifiedAt.After(ctx.startAt) {
+				stremioItemIds = append(stremioItemIds, ts.Id)
+			}
+		}
+		if len(stremioItemIds) == 0 {
+			return nil
+		}
+	}
+
+	res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+		Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+		Ids: stremioItemIds,
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range res.Data {
+		if isAnimeCandidateStremioId(item.Id) && !item.Removed {
+			ctx.stremioItems = append(ctx.stremioItems, item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/anidb"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAniDBEpisode(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		maps          anidb.AniDBTVDBEpisodeMaps
+		season        int
+		episode       int
+		anidbEpisode  int
+		isBeforeWatch bool
+	}{
+		{
+			name:         "same season",
+			maps:         anidb.AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1}},
+			season:       1,
+			episode:      5,
+			anidbEpisode: 5,
+		},
+		{
+			name:         "split cour with offset",
+			maps:         anidb.AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Start: 1, End: 12, Offset: 12}},
+			season:       1,
+			episode:      15,
+			anidbEpisode: 3,
+		},
+		{
+			name:          "earlier cour of same season",
+			maps:          anidb.AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1, Start: 1, End: 12}},
+			season:        1,
+			episode:       15,
+			isBeforeWatch: true,
+		},
+		{
+			name:          "earlier season",
+			maps:          anidb.AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 1}},
+			season:        2,
+			episode:       1,
+			isBeforeWatch: true,
+		},
+		{
+			name:   "later season",
+			maps:   anidb.AniDBTVDBEpisodeMaps{{AniDBSeason: 1, TVDBSeason: 3}},
+			season: 2,
+		},
+		{
+			name: "explicit map",
+			maps: anidb.AniDBTVDBEpisodeMaps{{
+				AniDBSeason: 1,
+				TVDBSeason:  1,
+				Start:       1,
+				End:         2,
+				Map:         anidb.AniDBTVDBEpisodeMapMap{3: {7}},
+			}},
+			season:       1,
+			episode:      7,
+			anidbEpisode: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			anidbEpisode, isBefore := resolveAniDBEpisode(tc.maps, tc.season, tc.episode)
+			assert.Equal(t, tc.anidbEpisode, anidbEpisode)
+			assert.Equal(t, tc.isBeforeWatch, isBefore)
+		})
+	}
+}
+
+func TestParseAnimeStremioItemRef(t *testing.T) {
+	watched := func(videoIds []string, watchedCount int) string {
+		wbf := stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8WithValues(nil, len(videoIds)), videoIds)
+		for i := range watchedCount {
+			wbf.Set(i, true)
+		}
+		str, err := wbf.String()
+		if err != nil {
+			panic(err)
+		}
+		return str
+	}
+
+	ref := parseAnimeStremioItemRef(&stremio_api.LibraryItem{
+		Id:   "kitsu:11",
+		Type: "series",
+		State: stremio_api.LibraryItemState{
+			Watched: watched([]string{"kitsu:11:1", "kitsu:11:2", "kitsu:11:3"}, 2),
+		},
+	})
+	if assert.NotNil(t, ref) {
+		assert.Equal(t, "kitsu", ref.idType)
+		assert.Equal(t, "11", ref.id)
+		assert.Equal(t, 2, ref.episode)
+	}
+
+	ref = parseAnimeStremioItemRef(&stremio_api.LibraryItem{
+		Id:   "tt0388629",
+		Type: "series",
+		State: stremio_api.LibraryItemState{
+			Watched: watched([]string{"tt0388629:1:1", "tt0388629:2:1", "tt0388629:2:2"}, 3),
+		},
+	})
+	if assert.NotNil(t, ref) {
+		assert.Equal(t, "imdb", ref.idType)
+		assert.Equal(t, "tt0388629", ref.id)
+		assert.Equal(t, 2, ref.season)
+		assert.Equal(t, 2, ref.episode)
+	}
+
+	ref = parseAnimeStremioItemRef(&stremio_api.LibraryItem{
+		Id:    "tt0245429",
+		Type:  "movie",
+		State: stremio_api.LibraryItemState{TimesWatched: 1},
+	})
+	if assert.NotNil(t, ref) {
+		assert.Equal(t, "imdb", ref.idType)
+	}
+
+	assert.Nil(t, parseAnimeStremioItemRef(&stremio_api.LibraryItem{
+		Id:   "tt0245429",
+		Type: "movie",
+	}))
+}
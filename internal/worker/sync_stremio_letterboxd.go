@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	letterboxd_account "github.com/MunifTanjim/stremthru/internal/letterboxd/account"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_letterboxd"
+)
+
+func InitSyncStremioLetterboxdWorker(conf *WorkerConfig) *Worker {
+	syncDiary := func(link *sync_stremio_letterboxd.SyncStremioLetterboxdLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"letterboxd_account_id", link.LetterboxdAccountId,
+		)
+
+		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+		if err != nil || stremioAccount == nil {
+			return fmt.Errorf("stremio account not found: %w", err)
+		}
+
+		letterboxdAccount, err := letterboxd_account.GetById(link.LetterboxdAccountId)
+		if err != nil || letterboxdAccount == nil {
+			return fmt.Errorf("letterboxd account not found: %w", err)
+		}
+
+		stremioToken, err := stremioAccount.GetValidToken()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		entries, err := link.GetPendingDiaryEntries(stremioToken)
+		if err != nil {
+			return err
+		}
+
+		log.Debug("found pending diary entries", "count", len(entries))
+
+		count, err := sync_stremio_letterboxd.LogDiaryEntries(letterboxdAccount.OAuthTokenId, entries)
+		link.SetTimesWatched(entries[:count])
+		if err != nil {
+			log.Error("failed to log diary entries", "error", err, "logged", count)
+			if count > 0 {
+				// retried from the failed entry on next sync
+				lastSyncedAt := entries[count].WatchedAt.Add(-time.Nanosecond)
+				link.SyncState.Watched.LastSyncedAt = &lastSyncedAt
+			}
+			sync_stremio_letterboxd.SetSyncState(link.StremioAccountId, link.LetterboxdAccountId, link.SyncState)
+			return err
+		}
+
+		if count > 0 {
+			log.Info("logged diary entries", "count", count)
+		}
+
+		link.SyncState.Watched.LastSyncedAt = &now
+		sync_stremio_letterboxd.SetSyncState(link.StremioAccountId, link.LetterboxdAccountId, link.SyncState)
+		return nil
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		links, err := sync_stremio_letterboxd.GetAll()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if link.SyncConfig.Watched.Direction.ShouldSyncToLetterboxd() {
+				if err := syncDiary(&link, log); err != nil {
+					log.Error("failed to sync link", "error", err,
+						"stremio_account_id", link.StremioAccountId,
+						"letterboxd_account_id", link.LetterboxdAccountId,
+					)
+				}
+			}
+		}
+
+		return nil
+	}
+	return NewWorker(conf)
+}
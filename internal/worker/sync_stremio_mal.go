@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/mal"
+	mal_account "github.com/MunifTanjim/stremthru/internal/mal/account"
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_mal"
+)
+
+type malAnimeTracker struct {
+	client *mal.APIClient
+}
+
+func (t malAnimeTracker) GetListEntries() (map[int]animeTrackerEntry, error) {
+	res, err := t.client.GetMyAnimeList(&mal.GetMyAnimeListParams{})
+	if err != nil {
+		return nil, err
+	}
+	entryById := make(map[int]animeTrackerEntry, len(res.Data))
+	for _, item := range res.Data {
+		entryById[item.Node.Id] = animeTrackerEntry{
+			Id:        item.Node.Id,
+			Progress:  item.ListStatus.NumEpisodesWatched,
+			Episodes:  item.Node.NumEpisodes,
+			UpdatedAt: item.ListStatus.UpdatedAt,
+		}
+	}
+	return entryById, nil
+}
+
+func (t malAnimeTracker) GetEpisodeCount(ids []int) (map[int]int, error) {
+	countById := make(map[int]int, len(ids))
+	for _, id := range ids {
+		res, err := t.client.GetAnime(&mal.GetAnimeParams{Id: id})
+		if err != nil {
+			return nil, err
+		}
+		countById[id] = res.Data.NumEpisodes
+	}
+	return countById, nil
+}
+
+func (t malAnimeTracker) GetIdMaps(ids []int) ([]anime.AnimeIdMap, error) {
+	return anime.GetIdMapsForMAL(ids)
+}
+
+func (t malAnimeTracker) GetId(idMap *anime.AnimeIdMap) int {
+	id, _ := strconv.Atoi(idMap.MAL)
+	return id
+}
+
+func (t malAnimeTracker) SaveProgress(id, progress int, completed bool) error {
+	status := mal.AnimeListStatusWatching
+	if completed {
+		status = mal.AnimeListStatusCompleted
+	}
+	_, err := t.client.UpdateMyListStatus(&mal.UpdateMyListStatusParams{
+		AnimeId:            id,
+		Status:             status,
+		NumWatchedEpisodes: progress,
+	})
+	return err
+}
+
+func InitSyncStremioMALWorker(conf *WorkerConfig) *Worker {
+	syncWatched := func(link *sync_stremio_mal.SyncStremioMALLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"mal_account_id", link.MALAccountId,
+		)
+
+		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+		if err != nil || stremioAccount == nil {
+			return fmt.Errorf("stremio account not found: %w", err)
+		}
+
+		malAccount, err := mal_account.GetById(link.MALAccountId)
+		if err != nil || malAccount == nil {
+			return fmt.Errorf("mal account not found: %w", err)
+		}
+
+		stremioToken, err := stremioAccount.GetValidToken()
+		if err != nil {
+			return err
+		}
+
+		ctx := &syncStremioAnimeCtx{
+			now:           time.Now(),
+			log:           log,
+			stremioClient: stremio_api.NewClient(&stremio_api.ClientConfig{}),
+			stremioToken:  stremioToken,
+			tracker: malAnimeTracker{
+				client: mal.GetAPIClient(malAccount.OAuthTokenId),
+			},
+		}
+		if link.SyncState.Watched.LastSyncedAt != nil {
+			ctx.startAt = *link.SyncState.Watched.LastSyncedAt
+		}
+		ctx.isFullSync = ctx.startAt.IsZero()
+
+		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", ctx.startAt)
+
+		if err := fetchAnimeStremioItems(ctx); err != nil {
+			return err
+		}
+
+		log.Debug("fetched stremio items", "count", len(ctx.stremioItems))
+
+		if ctx.trackerEntries, err = ctx.tracker.GetListEntries(); err != nil {
+			return err
+		}
+
+		log.Debug("fetched mal entries", "count", len(ctx.trackerEntries))
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToMAL() {
+			count, err := syncAnimeFromStremioToTracker(ctx)
+			if err != nil {
+				log.Error("failed to sync from stremio to mal", "error", err)
+				return err
+			}
+			log.Debug("synced from stremio to mal", "count", count)
+		}
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToStremio() {
+			count, err := syncAnimeFromTrackerToStremio(ctx)
+			if err != nil {
+				log.Error("failed to sync from mal to stremio", "error", err)
+				return err
+			}
+			log.Debug("synced from mal to stremio", "count", count)
+		}
+
+		link.SyncState.Watched.LastSyncedAt = &ctx.now
+		sync_stremio_mal.SetSyncState(link.StremioAccountId, link.MALAccountId, link.SyncState)
+		return nil
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		links, err := sync_stremio_mal.GetAll()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if !link.SyncConfig.Watched.Direction.IsDisabled() {
+				if err := syncWatched(&link, log); err != nil {
+					log.Error("failed to sync link", "error", err,
+						"stremio_account_id", link.StremioAccountId,
+						"mal_account_id", link.MALAccountId,
+					)
+				}
+			}
+		}
+
+		return nil
+	}
+	return NewWorker(conf)
+}
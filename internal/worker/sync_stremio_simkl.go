@@ -0,0 +1,534 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
+	simkl_account "github.com/MunifTanjim/stremthru/internal/simkl/account"
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/stremio/cinemeta"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_simkl"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/stremio"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+)
+
+func InitSyncStremioSimklWorker(conf *WorkerConfig) *Worker {
+	type Ctx struct {
+		now        time.Time
+		log        *logger.Logger
+		isFullSync bool
+		startAt    time.Time
+
+		stremioClient *stremio_api.Client
+		stremioToken  string
+		stremioMovies []stremio_api.LibraryItem
+		stremioSeries []stremio_api.LibraryItem
+
+		simklClient *simkl.APIClient
+		simklMovies []simkl.AllItemsItem
+		simklShows  []simkl.AllItemsItem
+		// anime are addressed by imdb id too, but simkl numbers their episodes
+		// per anime entry, so those are only used to avoid duplicate history.
+		simklAnime []simkl.AllItemsItem
+	}
+
+	createLibraryItem := func(ctx *Ctx, meta stremio.Meta, state stremio_api.LibraryItemState) stremio_api.LibraryItem {
+		return stremio_api.LibraryItem{
+			Id:          meta.Id,
+			Type:        string(meta.Type),
+			Name:        meta.Name,
+			Poster:      meta.Poster,
+			PosterShape: meta.PosterShape,
+			Background:  meta.Background,
+			Logo:        meta.Logo,
+			Year:        meta.ReleaseInfo,
+			State:       state,
+			Removed:     false,
+			Temp:        false,
+			CTime:       stremio_api.JSONTime{Time: ctx.now},
+			MTime:       stremio_api.JSONTime{Time: ctx.now},
+		}
+	}
+
+	getSimklWatchedEpisodesByImdbId := func(items ...[]simkl.AllItemsItem) map[string]*util.Set[string] {
+		watchedByImdbId := map[string]*util.Set[string]{}
+		for _, list := range items {
+			for i := range list {
+				item := &list[i]
+				if item.Show == nil || item.Show.Ids.IMDB == "" {
+					continue
+				}
+				imdbId := item.Show.Ids.IMDB
+				if watchedByImdbId[imdbId] == nil {
+					watchedByImdbId[imdbId] = util.NewSet[string]()
+				}
+				for _, season := range item.Seasons {
+					for _, episode := range season.Episodes {
+						watchedByImdbId[imdbId].Add(fmt.Sprintf("%d:%d", season.Number, episode.Number))
+					}
+				}
+			}
+		}
+		return watchedByImdbId
+	}
+
+	syncMovieFromStremioToSimkl := func(ctx *Ctx) error {
+		simklWatchedImdbIds := util.NewSet[string]()
+		for _, item := range ctx.simklMovies {
+			simklWatchedImdbIds.Add(item.Movie.Ids.IMDB)
+		}
+
+		var moviesToAdd []simkl.SyncHistoryItem
+		for _, item := range ctx.stremioMovies {
+			if item.State.TimesWatched == 0 || simklWatchedImdbIds.Has(item.Id) {
+				continue
+			}
+			moviesToAdd = append(moviesToAdd, simkl.SyncHistoryItem{
+				Ids:       simkl.ItemIds{IMDB: item.Id},
+				WatchedAt: &item.State.LastWatched,
+			})
+		}
+
+		if len(moviesToAdd) == 0 {
+			return nil
+		}
+
+		_, err := ctx.simklClient.AddToHistory(&simkl.AddToHistoryParams{
+			Movies: moviesToAdd,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced movies from stremio to simkl", "count", len(moviesToAdd))
+		return nil
+	}
+
+	syncSeriesFromStremioToSimkl := func(ctx *Ctx) error {
+		simklWatchedByImdbId := getSimklWatchedEpisodesByImdbId(ctx.simklShows, ctx.simklAnime)
+
+		var showsToAdd []simkl.SyncHistoryItem
+		for _, item := range ctx.stremioSeries {
+			if item.State.Watched == "" {
+				continue
+			}
+
+			meta, err := cinemeta.FetchMeta("series", item.Id)
+			if err != nil {
+				return err
+			}
+			var videoIds []string
+			for _, video := range meta.Videos {
+				videoIds = append(videoIds, video.Id)
+			}
+			wbf, err := stremio_watched_bitfield.NewWatchedBitFieldFromString(item.State.Watched, videoIds)
+			if err != nil {
+				continue
+			}
+
+			watched := simklWatchedByImdbId[item.Id]
+			episodesBySeason := map[int][]simkl.SyncHistoryEpisode{}
+			for _, videoId := range videoIds {
+				if !wbf.GetVideo(videoId) {
+					continue
+				}
+				parts := strings.Split(videoId, ":")
+				if len(parts) < 3 {
+					continue
+				}
+				season, episode := util.SafeParseInt(parts[1], 0), util.SafeParseInt(parts[2], 0)
+				if season < 1 || episode < 1 {
+					continue
+				}
+				if watched != nil && watched.Has(fmt.Sprintf("%d:%d", season, episode)) {
+					continue
+				}
+				episodesBySeason[season] = append(episodesBySeason[season], simkl.SyncHistoryEpisode{
+					Number: episode,
+				})
+			}
+
+			if len(episodesBySeason) == 0 {
+				continue
+			}
+
+			seasons := make([]simkl.SyncHistorySeason, 0, len(episodesBySeason))
+			for season, episodes := range episodesBySeason {
+				seasons = append(seasons, simkl.SyncHistorySeason{
+					Number:   season,
+					Episodes: episodes,
+				})
+			}
+			showsToAdd = append(showsToAdd, simkl.SyncHistoryItem{
+				Ids:     simkl.ItemIds{IMDB: item.Id},
+				Seasons: seasons,
+			})
+		}
+
+		if len(showsToAdd) == 0 {
+			return nil
+		}
+
+		_, err := ctx.simklClient.AddToHistory(&simkl.AddToHistoryParams{
+			Shows: showsToAdd,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced series from stremio to simkl", "count", len(showsToAdd))
+		return nil
+	}
+
+	getStremioItemsByImdbId := func(ctx *Ctx, itemType string, items []stremio_api.LibraryItem, imdbIds []string) (map[string]stremio_api.LibraryItem, error) {
+		stremioItemByImdbId := map[string]stremio_api.LibraryItem{}
+		for _, item := range items {
+			stremioItemByImdbId[item.Id] = item
+		}
+
+		if ctx.isFullSync {
+			return stremioItemByImdbId, nil
+		}
+
+		var idsToFetch []string
+		for _, imdbId := range imdbIds {
+			if _, exists := stremioItemByImdbId[imdbId]; !exists {
+				idsToFetch = append(idsToFetch, imdbId)
+			}
+		}
+		if len(idsToFetch) == 0 {
+			return stremioItemByImdbId, nil
+		}
+
+		res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+			Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Ids: idsToFetch,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range res.Data {
+			if item.Type == itemType {
+				stremioItemByImdbId[item.Id] = item
+			}
+		}
+		return stremioItemByImdbId, nil
+	}
+
+	syncMovieFromSimklToStremio := func(ctx *Ctx) error {
+		var simklMovies []simkl.AllItemsItem
+		var imdbIds []string
+		for _, item := range ctx.simklMovies {
+			if !ctx.isFullSync && !item.LastWatchedAt.After(ctx.startAt) {
+				continue
+			}
+			simklMovies = append(simklMovies, item)
+			imdbIds = append(imdbIds, item.Movie.Ids.IMDB)
+		}
+
+		if len(simklMovies) == 0 {
+			return nil
+		}
+
+		stremioItemByImdbId, err := getStremioItemsByImdbId(ctx, "movie", ctx.stremioMovies, imdbIds)
+		if err != nil {
+			return err
+		}
+
+		var itemsToUpdate []stremio_api.LibraryItem
+		for _, item := range simklMovies {
+			imdbId := item.Movie.Ids.IMDB
+			libraryItem, ok := stremioItemByImdbId[imdbId]
+			if ok {
+				if libraryItem.State.TimesWatched > 0 {
+					continue
+				}
+				libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+			} else {
+				meta, err := cinemeta.FetchMeta("movie", imdbId)
+				if err != nil {
+					return err
+				}
+				libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+			}
+			libraryItem.State.TimesWatched = 1
+			if item.LastWatchedAt.After(libraryItem.State.LastWatched) {
+				libraryItem.State.LastWatched = item.LastWatchedAt.Time
+			}
+			itemsToUpdate = append(itemsToUpdate, libraryItem)
+		}
+
+		if len(itemsToUpdate) == 0 {
+			return nil
+		}
+
+		_, err = ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+			Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Changes: itemsToUpdate,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced movies from simkl to stremio", "count", len(itemsToUpdate))
+		return nil
+	}
+
+	syncSeriesFromSimklToStremio := func(ctx *Ctx) error {
+		var simklShows []simkl.AllItemsItem
+		var imdbIds []string
+		for _, item := range ctx.simklShows {
+			if !ctx.isFullSync && !item.LastWatchedAt.After(ctx.startAt) {
+				continue
+			}
+			simklShows = append(simklShows, item)
+			imdbIds = append(imdbIds, item.Show.Ids.IMDB)
+		}
+
+		if len(simklShows) == 0 {
+			return nil
+		}
+
+		stremioItemByImdbId, err := getStremioItemsByImdbId(ctx, "series", ctx.stremioSeries, imdbIds)
+		if err != nil {
+			return err
+		}
+
+		var itemsToUpdate []stremio_api.LibraryItem
+		for _, item := range simklShows {
+			imdbId := item.Show.Ids.IMDB
+			meta, err := cinemeta.FetchMeta("series", imdbId)
+			if err != nil {
+				return err
+			}
+			var videoIds []string
+			for _, video := range meta.Videos {
+				videoIds = append(videoIds, video.Id)
+			}
+
+			libraryItem, exists := stremioItemByImdbId[imdbId]
+			var wbf *stremio_watched_bitfield.WatchedBitField
+			if exists && libraryItem.State.Watched != "" {
+				if wbf, err = stremio_watched_bitfield.NewWatchedBitFieldFromString(libraryItem.State.Watched, videoIds); err != nil {
+					return err
+				}
+			} else {
+				wbf = stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8WithValues(nil, len(videoIds)), videoIds)
+			}
+
+			needsUpdate := false
+			var lastWatched time.Time
+			for _, season := range item.Seasons {
+				for _, episode := range season.Episodes {
+					videoId := fmt.Sprintf("%s:%d:%d", imdbId, season.Number, episode.Number)
+					if !wbf.GetVideo(videoId) {
+						wbf.SetVideo(videoId, true)
+						needsUpdate = true
+						if episode.WatchedAt.After(lastWatched) {
+							lastWatched = episode.WatchedAt.Time
+						}
+					}
+				}
+			}
+
+			if !needsUpdate {
+				continue
+			}
+
+			watchedStr, err := wbf.String()
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+			} else {
+				libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+			}
+			libraryItem.State.Watched = watchedStr
+			if lastWatched.After(libraryItem.State.LastWatched) {
+				libraryItem.State.LastWatched = lastWatched
+			}
+			if videoId := wbf.GetNextUnwatchedVideoId(); videoId != libraryItem.State.VideoId {
+				libraryItem.State.VideoId = videoId
+				libraryItem.State.TimeOffset = 0
+			}
+			itemsToUpdate = append(itemsToUpdate, libraryItem)
+		}
+
+		if len(itemsToUpdate) == 0 {
+			return nil
+		}
+
+		_, err = ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+			Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Changes: itemsToUpdate,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced series from simkl to stremio", "count", len(itemsToUpdate))
+		return nil
+	}
+
+	syncWatched := func(link *sync_stremio_simkl.SyncStremioSimklLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"simkl_account_id", link.SimklAccountId,
+		)
+
+		ctx := &Ctx{
+			log: log,
+		}
+
+		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+		if err != nil || stremioAccount == nil {
+			return fmt.Errorf("stremio account not found: %w", err)
+		}
+
+		simklAccount, err := simkl_account.GetById(link.SimklAccountId)
+		if err != nil || simklAccount == nil {
+			return fmt.Errorf("simkl account not found: %w", err)
+		}
+
+		stremioToken, err := stremioAccount.GetValidToken()
+		if err != nil {
+			return err
+		}
+		ctx.stremioToken = stremioToken
+
+		ctx.stremioClient = stremio_api.NewClient(&stremio_api.ClientConfig{})
+
+		ctx.simklClient = simkl.GetAPIClient(simklAccount.OAuthTokenId)
+
+		ctx.now = time.Now()
+
+		if link.SyncState.Watched.LastSyncedAt != nil {
+			ctx.startAt = *link.SyncState.Watched.LastSyncedAt
+		}
+
+		ctx.isFullSync = ctx.startAt.IsZero()
+
+		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", ctx.startAt)
+
+		var stremioItemIds []string
+		if !ctx.isFullSync {
+			tsRes, err := ctx.stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{Ctx: stremio_api.Ctx{APIKey: stremioToken}})
+			if err != nil {
+				return err
+			}
+			for _, ts := range tsRes.Data {
+				if !strings.HasPrefix(ts.Id, "tt") {
+					continue
+				}
+				if ts.ModifiedAt.After(ctx.startAt) {
+					stremioItemIds = append(stremioItemIds, ts.Id)
+				}
+			}
+		}
+
+		if ctx.isFullSync || len(stremioItemIds) > 0 {
+			stremioLibItemsRes, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+				Ctx: stremio_api.Ctx{APIKey: stremioToken},
+				Ids: stremioItemIds,
+			})
+			if err != nil {
+				return err
+			}
+			for _, item := range stremioLibItemsRes.Data {
+				if !strings.HasPrefix(item.Id, "tt") {
+					continue
+				}
+				switch item.Type {
+				case "movie":
+					ctx.stremioMovies = append(ctx.stremioMovies, item)
+				case "series":
+					ctx.stremioSeries = append(ctx.stremioSeries, item)
+				}
+			}
+		}
+
+		log.Debug("fetched stremio items", "movies", len(ctx.stremioMovies), "series", len(ctx.stremioSeries))
+
+		// the full watched state is needed to avoid adding duplicate history
+		simklRes, err := ctx.simklClient.GetAllItems(&simkl.GetAllItemsParams{
+			EpisodeWatchedAt: true,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range simklRes.Data.Movies {
+			if item.Movie == nil || item.Movie.Ids.IMDB == "" || item.Status != simkl.ItemStatusCompleted {
+				continue
+			}
+			ctx.simklMovies = append(ctx.simklMovies, item)
+		}
+		for _, item := range simklRes.Data.Shows {
+			if item.Show == nil || item.Show.Ids.IMDB == "" || len(item.Seasons) == 0 {
+				continue
+			}
+			ctx.simklShows = append(ctx.simklShows, item)
+		}
+		for _, item := range simklRes.Data.Anime {
+			if item.Show == nil || item.Show.Ids.IMDB == "" || len(item.Seasons) == 0 {
+				continue
+			}
+			ctx.simklAnime = append(ctx.simklAnime, item)
+		}
+
+		log.Debug("fetched simkl items", "simkl_movies", len(ctx.simklMovies), "simkl_shows", len(ctx.simklShows), "simkl_anime", len(ctx.simklAnime))
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToSimkl() {
+			if err := syncMovieFromStremioToSimkl(ctx); err != nil {
+				log.Error("failed to sync movies from stremio to simkl", "error", err)
+				return err
+			}
+			if err := syncSeriesFromStremioToSimkl(ctx); err != nil {
+				log.Error("failed to sync series from stremio to simkl", "error", err)
+				return err
+			}
+		}
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToStremio() {
+			if err := syncMovieFromSimklToStremio(ctx); err != nil {
+				log.Error("failed to sync movies from simkl to stremio", "error", err)
+				return err
+			}
+			if err := syncSeriesFromSimklToStremio(ctx); err != nil {
+				log.Error("failed to sync series from simkl to stremio", "error", err)
+				return err
+			}
+		}
+
+		link.SyncState.Watched.LastSyncedAt = &ctx.now
+		sync_stremio_simkl.SetSyncState(link.StremioAccountId, link.SimklAccountId, link.SyncState)
+		return nil
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		links, err := sync_stremio_simkl.GetAll()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if !link.SyncConfig.Watched.Direction.IsDisabled() {
+				err := syncWatched(&link, log)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+	return NewWorker(conf)
+}
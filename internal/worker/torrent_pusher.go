@@ -17,7 +17,7 @@ var TorrentPusherQueue = IdQueue{
 		sid, _, _ = strings.Cut(sid, ":")
 		return sid
 	},
-	disabled: !config.HasPeer || config.PeerAuthToken == "" || !config.Feature.HasTorz(),
+	disabled: !peer.Federation.CanPush() || !config.Feature.HasTorz(),
 }
 
 var Peer = peer.NewAPIClient(&peer.APIClientConfig{
@@ -35,11 +35,8 @@ func InitPushTorrentsWorker(conf *WorkerConfig) *Worker {
 			if sidOk && tOk && t.Before(time.Now()) {
 				if tss.ShouldPush(sid) {
 					if data, err := torrent_info.ListByStremId(sid, false); err == nil {
-						params := &peer.PushTorrentsParams{
-							Items: data.Items,
-						}
 						start := time.Now()
-						if _, err := Peer.PushTorrents(params); err != nil {
+						if err := peer.Federation.PushTorrents(data.Items); err != nil {
 							log.Error("failed to push torrents", "error", core.PackError(err), "duration", time.Since(start), "count", data.TotalItems)
 						} else {
 							log.Info("pushed torrents", "duration", time.Since(start), "count", data.TotalItems)
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/job_log"
 	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/peer"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
 	"github.com/madflojo/tasks"
@@ -16,6 +18,14 @@ import (
 
 var ErrInProgress = errors.New("worker is in progress")
 
+// set by RunWorker, the named worker is run once instead of being scheduled
+var runOnce struct {
+	name     string
+	found    bool
+	executed bool
+	err      error
+}
+
 var mutex sync.Mutex
 var running_worker struct {
 	sync_anidb_titles           bool
@@ -28,6 +38,7 @@ var running_worker struct {
 	sync_animeapi               bool
 	sync_anidb_tvdb_episode_map bool
 	sync_manami_anime_database  bool
+	sync_peer_torrents          bool
 }
 
 type Worker struct {
@@ -117,9 +128,33 @@ var WorkerDetailsById = map[string]*WorkerDetail{
 	"sync-stremio-trakt": {
 		Title: "Sync Stremio-Trakt",
 	},
+	"sync-stremio-anilist": {
+		Title: "Sync Stremio-AniList",
+	},
+	"sync-stremio-mal": {
+		Title: "Sync Stremio-MAL",
+	},
+	"sync-stremio-simkl": {
+		Title: "Sync Stremio-Simkl",
+	},
+	"sync-stremio-letterboxd": {
+		Title: "Sync Stremio-Letterboxd",
+	},
 	"sync-stremio-stremio": {
 		Title: "Sync Stremio-Stremio",
 	},
+	"index-usenet-groups": {
+		Title: "Index Usenet Groups",
+	},
+	"sync-peer-torrents": {
+		Title: "Sync Peer Torrents",
+	},
+	"scrape-trackers": {
+		Title: "Scrape Trackers",
+	},
+	"fetch-torrent-metadata": {
+		Title: "Fetch Torrent Metadata",
+	},
 }
 
 func NewWorker(conf *WorkerConfig) *Worker {
@@ -139,6 +174,11 @@ func NewWorker(conf *WorkerConfig) *Worker {
 		return nil
 	}
 
+	isRunOnce := runOnce.name != ""
+	if isRunOnce && runOnce.name != conf.Name {
+		return nil
+	}
+
 	if conf.Log == nil {
 		conf.Log = logger.Scoped("worker/" + conf.Name)
 	}
@@ -196,7 +236,7 @@ func NewWorker(conf *WorkerConfig) *Worker {
 				worker.onEnd()
 			}()
 
-			if worker.shouldSkip != nil && worker.shouldSkip() {
+			if !isRunOnce && worker.shouldSkip != nil && worker.shouldSkip() {
 				log.Trace("skipping")
 				return nil
 			}
@@ -251,7 +291,7 @@ func NewWorker(conf *WorkerConfig) *Worker {
 							log.Error("failed to set last job status", "error", err, "jobId", tjob.Id, "status", "failed")
 						}
 					case "done":
-						if !util.HasDurationPassedSince(tjob.CreatedAt, conf.Interval) {
+						if !isRunOnce && !util.HasDurationPassedSince(tjob.CreatedAt, conf.Interval) {
 							log.Info("already done", "jobId", tjob.Id, "status", status)
 							return nil
 						}
@@ -294,6 +334,7 @@ func NewWorker(conf *WorkerConfig) *Worker {
 				}
 			}()
 
+			runOnce.executed = isRunOnce
 			if err = conf.Executor(worker); err != nil {
 				return err
 			}
@@ -328,6 +369,20 @@ func NewWorker(conf *WorkerConfig) *Worker {
 		panic(err)
 	}
 
+	if isRunOnce {
+		task, err := worker.scheduler.Lookup(id)
+		worker.scheduler.Stop()
+		if err != nil {
+			panic(err)
+		}
+		runOnce.found = true
+		if err := task.TaskFunc(); err != nil {
+			task.ErrFunc(err)
+			runOnce.err = err
+		}
+		return nil
+	}
+
 	log.Info("Started Worker", "id", id)
 
 	if conf.RunAtStartupAfter != 0 {
@@ -342,6 +397,35 @@ func NewWorker(conf *WorkerConfig) *Worker {
 	return worker
 }
 
+// RunWorker runs the worker once in the current process, ignoring its
+// schedule.
+func RunWorker(name string) error {
+	if _, ok := WorkerDetailsById[name]; !ok {
+		return fmt.Errorf("unknown worker: %s", name)
+	}
+
+	runOnce.name = name
+	defer func() {
+		runOnce.name = ""
+		runOnce.found = false
+		runOnce.executed = false
+		runOnce.err = nil
+	}()
+
+	InitWorkers()
+
+	if !runOnce.found {
+		return fmt.Errorf("worker is disabled: %s", name)
+	}
+	if runOnce.err != nil {
+		return runOnce.err
+	}
+	if !runOnce.executed {
+		return ErrInProgress
+	}
+	return nil
+}
+
 func InitWorkers() func() {
 	workers := []*Worker{}
 
@@ -516,6 +600,42 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitScrapeTrackersWorker(&WorkerConfig{
+		Disabled:          worker_queue.TrackerScraperQueue.Disabled,
+		Name:              "scrape-trackers",
+		Interval:          config.Torz.ScrapeInterval,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldSkip: func() bool {
+			return worker_queue.TrackerScraperQueue.IsEmpty()
+		},
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
+	if worker := InitFetchTorrentMetadataWorker(&WorkerConfig{
+		Disabled:          worker_queue.MetadataFetcherQueue.Disabled,
+		Name:              "fetch-torrent-metadata",
+		Interval:          5 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldSkip: func() bool {
+			return worker_queue.MetadataFetcherQueue.IsEmpty()
+		},
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	if worker := InitMagnetCachePullerWorker(&WorkerConfig{
 		Disabled: worker_queue.MagnetCachePullerQueue.Disabled,
 		Name:     "pull-magnet-cache",
@@ -778,6 +898,42 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitSyncPeerTorrentsWorker(&WorkerConfig{
+		Disabled:          len(peer.Federation.SyncPeers()) == 0 || !config.Feature.HasTorz(),
+		Name:              "sync-peer-torrents",
+		Interval:          15 * time.Minute,
+		RunAtStartupAfter: 120 * time.Second,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if running_worker.sync_imdb {
+				return true, "sync_imdb is running"
+			}
+
+			if running_worker.sync_dmm_hashlist {
+				return true, "sync_dmm_hashlist is running"
+			}
+
+			return false, ""
+		},
+		OnStart: func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			running_worker.sync_peer_torrents = true
+		},
+		OnEnd: func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			running_worker.sync_peer_torrents = false
+		},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	if worker := InitSyncAnimeToshoWorker(&WorkerConfig{
 		Disabled:          !config.Feature.IsEnabled("anime"),
 		Name:              "sync-animetosho",
@@ -846,6 +1002,66 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitSyncStremioAniListWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasSync() || !config.Feature.IsEnabled(config.FeatureAnime) || !config.Integration.AniList.IsEnabled(),
+		Name:              "sync-stremio-anilist",
+		Interval:          30 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
+	if worker := InitSyncStremioMALWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasSync() || !config.Feature.IsEnabled(config.FeatureAnime) || !config.Integration.MAL.IsEnabled(),
+		Name:              "sync-stremio-mal",
+		Interval:          30 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
+	if worker := InitSyncStremioSimklWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasSync() || !config.Integration.Simkl.IsEnabled(),
+		Name:              "sync-stremio-simkl",
+		Interval:          30 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
+	if worker := InitSyncStremioLetterboxdWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasSync() || !config.Integration.Letterboxd.IsEnabled(),
+		Name:              "sync-stremio-letterboxd",
+		Interval:          30 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	if worker := InitSyncStremioStremioWorker(&WorkerConfig{
 		Disabled:          !config.Feature.HasSync(),
 		Name:              "sync-stremio-stremio",
@@ -861,6 +1077,21 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitIndexUsenetGroupsWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasNewz() || len(config.Newz.ScanGroups) == 0,
+		Name:              "index-usenet-groups",
+		Interval:          config.Newz.ScanInterval,
+		RunAtStartupAfter: 2 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	return func() {
 		for _, worker := range workers {
 			worker.scheduler.Stop()
@@ -0,0 +1,25 @@
+package worker_queue
+
+import (
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+)
+
+type MetadataFetcherQueueItem struct {
+	Hash string
+}
+
+var MetadataFetcherQueue = WorkerQueue[MetadataFetcherQueueItem]{
+	debounceTime: 1 * time.Minute,
+	getKey: func(item MetadataFetcherQueueItem) string {
+		return item.Hash
+	},
+	getGroupKey: func(item MetadataFetcherQueueItem) string {
+		return ""
+	},
+	transform: func(item *MetadataFetcherQueueItem) *MetadataFetcherQueueItem {
+		return item
+	},
+	Disabled: config.Torz.MetadataFetchConcurrency == 0 || !config.Feature.HasTorz(),
+}
@@ -0,0 +1,25 @@
+package worker_queue
+
+import (
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+)
+
+type TrackerScraperQueueItem struct {
+	Hash string
+}
+
+var TrackerScraperQueue = WorkerQueue[TrackerScraperQueueItem]{
+	debounceTime: 1 * time.Minute,
+	getKey: func(item TrackerScraperQueueItem) string {
+		return item.Hash
+	},
+	getGroupKey: func(item TrackerScraperQueueItem) string {
+		return ""
+	},
+	transform: func(item *TrackerScraperQueueItem) *TrackerScraperQueueItem {
+		return item
+	},
+	Disabled: len(config.Torz.ScrapeTrackers) == 0 || !config.Feature.HasTorz(),
+}
@@ -0,0 +1,7 @@
+package zilean
+
+import (
+	"github.com/MunifTanjim/stremthru/internal/logger"
+)
+
+var log = logger.Scoped("zilean")
@@ -0,0 +1,129 @@
+package zilean
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/peer_token"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+// getQuery returns the query params with lowercase keys, Zilean clients are
+// not consistent about the casing.
+func getQuery(r *http.Request) url.Values {
+	query := url.Values{}
+	for key, values := range r.URL.Query() {
+		key = strings.ToLower(key)
+		query[key] = append(query[key], values...)
+	}
+	return query
+}
+
+type DMMSearchPayload struct {
+	QueryText string `json:"queryText"`
+}
+
+func handleDMMSearch(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodPost) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	payload := &DMMSearchPayload{}
+	if err := shared.ReadRequestBodyJSON(r, payload); err != nil {
+		shared.SendError(w, r, err)
+		return
+	}
+
+	items, err := Search(payload.QueryText)
+	if err != nil {
+		shared.ErrorInternalServerError(r, "failed to search").WithCause(err).Send(w, r)
+		return
+	}
+	shared.SendJSON(w, r, 200, items)
+}
+
+func handleDMMFiltered(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	query := getQuery(r)
+	params := &FilteredParams{
+		Query:      query.Get("query"),
+		Season:     util.SafeParseInt(query.Get("season"), 0),
+		Episode:    util.SafeParseInt(query.Get("episode"), 0),
+		Year:       util.SafeParseInt(query.Get("year"), 0),
+		Language:   query.Get("language"),
+		Resolution: query.Get("resolution"),
+		ImdbId:     query.Get("imdbid"),
+		Category:   query.Get("category"),
+	}
+	if params.ImdbId != "" && !strings.HasPrefix(params.ImdbId, "tt") {
+		shared.ErrorBadRequest(r, "invalid imdb id").Send(w, r)
+		return
+	}
+
+	items, err := SearchFiltered(params)
+	if err != nil {
+		shared.ErrorInternalServerError(r, "failed to search").WithCause(err).Send(w, r)
+		return
+	}
+	shared.SendJSON(w, r, 200, items)
+}
+
+func handleIMDBSearch(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) && !shared.IsMethod(r, http.MethodPost) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	query := getQuery(r)
+	items, err := SearchImdb(query.Get("query"), util.SafeParseInt(query.Get("year"), 0), query.Get("category"))
+	if err != nil {
+		shared.ErrorInternalServerError(r, "failed to search").WithCause(err).Send(w, r)
+		return
+	}
+	shared.SendJSON(w, r, 200, items)
+}
+
+func handleHealthcheckPing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(200)
+	w.Write([]byte("[" + time.Now().UTC().Format(time.RFC1123) + "]: Pong!"))
+}
+
+func withPeerToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		server.GetReqCtx(r).RedactURLPathValues(r, "token")
+
+		if server.IsMaintenanceActive() {
+			server.ErrorServiceUnavailable(r).Send(w, r)
+			return
+		}
+
+		if err := peer_token.AuthorizeRequest(r, r.PathValue("token"), peer_token.ScopeZilean); err != nil {
+			err.Send(w, r)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func AddEndpoints(mux *http.ServeMux) {
+	if !config.Feature.HasTorz() {
+		return
+	}
+
+	mux.HandleFunc("/v0/zilean/{token}/dmm/search", withPeerToken(handleDMMSearch))
+	mux.HandleFunc("/v0/zilean/{token}/dmm/filtered", withPeerToken(handleDMMFiltered))
+	mux.HandleFunc("/v0/zilean/{token}/imdb/search", withPeerToken(handleIMDBSearch))
+	mux.HandleFunc("/v0/zilean/{token}/healthchecks/ping", withPeerToken(handleHealthcheckPing))
+}
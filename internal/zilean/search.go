@@ -0,0 +1,371 @@
+package zilean
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/imdb_torrent"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+)
+
+// maximum number of torrents returned for a search
+const maxSearchResults = 500
+
+const maxImdbSearchResults = 10
+
+type ImdbFile struct {
+	ImdbId   string `json:"imdb_id"`
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Adult    bool   `json:"adult"`
+	Year     int    `json:"year"`
+}
+
+func newImdbFile(title *imdb_title.IMDBTitle) ImdbFile {
+	return ImdbFile{
+		ImdbId:   title.TId,
+		Category: title.Type,
+		Title:    title.Title,
+		Adult:    title.IsAdult,
+		Year:     title.Year,
+	}
+}
+
+// TorrentInfo is the torrent shape returned by Zilean.
+type TorrentInfo struct {
+	InfoHash        string    `json:"info_hash"`
+	RawTitle        string    `json:"raw_title"`
+	ParsedTitle     string    `json:"parsed_title"`
+	NormalizedTitle string    `json:"normalized_title"`
+	Category        string    `json:"category"`
+	Size            string    `json:"size"`
+	Trash           bool      `json:"trash"`
+	Adult           bool      `json:"adult"`
+	Year            int       `json:"year,omitempty"`
+	Resolution      string    `json:"resolution"`
+	Seasons         []int     `json:"seasons"`
+	Episodes        []int     `json:"episodes"`
+	Complete        bool      `json:"complete"`
+	Volumes         []int     `json:"volumes"`
+	Languages       []string  `json:"languages"`
+	Quality         string    `json:"quality,omitempty"`
+	HDR             []string  `json:"hdr"`
+	Codec           string    `json:"codec,omitempty"`
+	Audio           []string  `json:"audio"`
+	Channels        []string  `json:"channels"`
+	Dubbed          bool      `json:"dubbed"`
+	Subbed          bool      `json:"subbed"`
+	Date            string    `json:"date,omitempty"`
+	Group           string    `json:"group,omitempty"`
+	Edition         string    `json:"edition,omitempty"`
+	BitDepth        string    `json:"bit_depth,omitempty"`
+	Network         string    `json:"network,omitempty"`
+	Extended        bool      `json:"extended"`
+	Converted       bool      `json:"converted"`
+	Hardcoded       bool      `json:"hardcoded"`
+	Region          string    `json:"region,omitempty"`
+	ThreeD          bool      `json:"_3d"`
+	Site            string    `json:"site,omitempty"`
+	Proper          bool      `json:"proper"`
+	Repack          bool      `json:"repack"`
+	Retail          bool      `json:"retail"`
+	Upscaled        bool      `json:"upscaled"`
+	Remastered      bool      `json:"remastered"`
+	Unrated         bool      `json:"unrated"`
+	Documentary     bool      `json:"documentary"`
+	EpisodeCode     string    `json:"episode_code,omitempty"`
+	Container       string    `json:"container,omitempty"`
+	Extension       string    `json:"extension,omitempty"`
+	ImdbId          string    `json:"imdb_id,omitempty"`
+	Imdb            *ImdbFile `json:"imdb,omitempty"`
+	IngestedAt      string    `json:"ingested_at"`
+}
+
+var normalizeTitleRegex = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+func normalizeTitle(title string) string {
+	return strings.TrimSpace(normalizeTitleRegex.ReplaceAllString(strings.ToLower(title), " "))
+}
+
+func toCategory(category torrent_info.TorrentInfoCategory) string {
+	switch category {
+	case torrent_info.TorrentInfoCategoryMovie:
+		return "movie"
+	case torrent_info.TorrentInfoCategorySeries:
+		return "tvSeries"
+	case torrent_info.TorrentInfoCategoryXXX:
+		return "xxx"
+	default:
+		return ""
+	}
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func newTorrentInfo(ti *torrent_info.TorrentInfo, imdb *ImdbFile) TorrentInfo {
+	t := TorrentInfo{
+		InfoHash:        ti.Hash,
+		RawTitle:        ti.TorrentTitle,
+		ParsedTitle:     ti.Title,
+		NormalizedTitle: normalizeTitle(ti.Title),
+		Category:        toCategory(ti.Category),
+		Size:            strconv.FormatInt(max(ti.Size, 0), 10),
+		Adult:           ti.Category == torrent_info.TorrentInfoCategoryXXX,
+		Year:            ti.Year,
+		Resolution:      ti.Resolution,
+		Seasons:         nonNil([]int(ti.Seasons)),
+		Episodes:        nonNil([]int(ti.Episodes)),
+		Complete:        ti.Complete,
+		Volumes:         nonNil([]int(ti.Volumes)),
+		Languages:       nonNil([]string(ti.Languages)),
+		Quality:         ti.Quality,
+		HDR:             nonNil([]string(ti.HDR)),
+		Codec:           ti.Codec,
+		Audio:           nonNil([]string(ti.Audio)),
+		Channels:        nonNil([]string(ti.Channels)),
+		Dubbed:          ti.Dubbed,
+		Subbed:          ti.Subbed,
+		Date:            ti.Date.String(),
+		Group:           ti.Group,
+		Edition:         ti.Edition,
+		BitDepth:        ti.BitDepth,
+		Network:         ti.Network,
+		Extended:        ti.Extended,
+		Converted:       ti.Convert,
+		Hardcoded:       ti.Hardcoded,
+		Region:          ti.Region,
+		ThreeD:          ti.ThreeD != "",
+		Site:            ti.Site,
+		Proper:          ti.Proper,
+		Repack:          ti.Repack,
+		Retail:          ti.Retail,
+		Upscaled:        ti.Upscaled,
+		Remastered:      ti.Remastered,
+		Unrated:         ti.Unrated,
+		Documentary:     ti.Documentary,
+		EpisodeCode:     ti.EpisodeCode,
+		Container:       ti.Container,
+		Extension:       ti.Extension,
+		IngestedAt:      ti.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if imdb != nil {
+		t.ImdbId = imdb.ImdbId
+		t.Imdb = imdb
+		if imdb.Adult {
+			t.Adult = true
+		}
+		if t.Category == "" {
+			t.Category = imdb.Category
+		}
+	}
+	return t
+}
+
+// toTitleQuery converts free text to a glob for torrent_info.ListHashesByTitleQuery,
+// matching the words in order with anything in between.
+func toTitleQuery(text string) string {
+	words := strings.Fields(normalizeTitle(text))
+	if len(words) == 0 {
+		return ""
+	}
+	return "*" + strings.Join(words, "*") + "*"
+}
+
+type FilteredParams struct {
+	Query      string
+	Season     int
+	Episode    int
+	Year       int
+	Language   string
+	Resolution string
+	ImdbId     string
+	Category   string
+}
+
+func (p *FilteredParams) matches(ti *torrent_info.TorrentInfo) bool {
+	if p.Season > 0 && len(ti.Seasons) > 0 && !slices.Contains(ti.Seasons, p.Season) {
+		return false
+	}
+	// season packs have no episodes
+	if p.Episode > 0 && len(ti.Episodes) > 0 && !slices.Contains(ti.Episodes, p.Episode) {
+		return false
+	}
+	if p.Year > 0 && ti.Year > 0 {
+		if ti.YearEnd > 0 {
+			if p.Year < ti.Year || p.Year > ti.YearEnd {
+				return false
+			}
+		} else if ti.Year != p.Year {
+			return false
+		}
+	}
+	if p.Language != "" && !slices.ContainsFunc(ti.Languages, func(lang string) bool {
+		return strings.EqualFold(lang, p.Language)
+	}) {
+		return false
+	}
+	if p.Resolution != "" && !strings.EqualFold(ti.Resolution, p.Resolution) {
+		return false
+	}
+	if p.Category != "" {
+		category := toCategory(ti.Category)
+		if category != "" && !strings.EqualFold(category, p.Category) {
+			return false
+		}
+	}
+	return true
+}
+
+func getImdbFileByHash(hashes []string) (map[string]*ImdbFile, error) {
+	byHash := map[string]*ImdbFile{}
+	if len(hashes) == 0 {
+		return byHash, nil
+	}
+
+	mappings, err := imdb_torrent.GetMappingsByHashes(hashes, "", 4*len(hashes), true, false)
+	if err != nil {
+		return nil, err
+	}
+	tids := []string{}
+	tidByHash := map[string]string{}
+	for i := range mappings {
+		m := &mappings[i]
+		if _, ok := tidByHash[m.Hash]; ok {
+			continue
+		}
+		tidByHash[m.Hash] = m.TId
+		if !slices.Contains(tids, m.TId) {
+			tids = append(tids, m.TId)
+		}
+	}
+	if len(tids) == 0 {
+		return byHash, nil
+	}
+
+	titles, err := imdb_title.ListByIds(tids)
+	if err != nil {
+		return nil, err
+	}
+	fileByTId := make(map[string]*ImdbFile, len(titles))
+	for i := range titles {
+		file := newImdbFile(&titles[i])
+		fileByTId[file.ImdbId] = &file
+	}
+	for hash, tid := range tidByHash {
+		if file, ok := fileByTId[tid]; ok {
+			byHash[hash] = file
+		}
+	}
+	return byHash, nil
+}
+
+func getTorrentInfos(hashes []string, params *FilteredParams) ([]TorrentInfo, error) {
+	if len(hashes) > maxSearchResults {
+		hashes = hashes[:maxSearchResults]
+	}
+
+	tInfoByHash, err := torrent_info.GetByHashes(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	imdbByHash, err := getImdbFileByHash(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]TorrentInfo, 0, len(tInfoByHash))
+	for _, hash := range hashes {
+		tInfo, ok := tInfoByHash[hash]
+		if !ok || tInfo.Private {
+			continue
+		}
+		if err := tInfo.Parse(); err != nil {
+			log.Warn("failed to parse torrent title", "error", err, "hash", hash)
+			continue
+		}
+		if params != nil && !params.matches(&tInfo) {
+			continue
+		}
+		items = append(items, newTorrentInfo(&tInfo, imdbByHash[hash]))
+	}
+	return items, nil
+}
+
+// Search lists torrents with title matching the text.
+func Search(queryText string) ([]TorrentInfo, error) {
+	query := toTitleQuery(queryText)
+	if query == "" {
+		return []TorrentInfo{}, nil
+	}
+	hashes, err := torrent_info.ListHashesByTitleQuery(query, maxSearchResults)
+	if err != nil {
+		return nil, err
+	}
+	return getTorrentInfos(hashes, nil)
+}
+
+// SearchFiltered lists torrents for the imdb id, or the title query, that
+// match the filters.
+func SearchFiltered(params *FilteredParams) ([]TorrentInfo, error) {
+	var hashes []string
+	var err error
+	if params.ImdbId != "" {
+		sid := params.ImdbId
+		if params.Season > 0 && params.Episode > 0 {
+			sid += ":" + strconv.Itoa(params.Season) + ":" + strconv.Itoa(params.Episode)
+		}
+		hashes, err = torrent_info.ListHashesByStremId(sid)
+	} else if query := toTitleQuery(params.Query); query != "" {
+		hashes, err = torrent_info.ListHashesByTitleQuery(query, maxSearchResults)
+	} else {
+		return []TorrentInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getTorrentInfos(hashes, params)
+}
+
+// SearchImdb lists imdb titles matching the query.
+func SearchImdb(query string, year int, category string) ([]ImdbFile, error) {
+	titleType := imdb_title.SearchTitleTypeUnknown
+	switch strings.ToLower(category) {
+	case "movie":
+		titleType = imdb_title.SearchTitleTypeMovie
+	case "tvseries", "tvminiseries", "show", "series":
+		titleType = imdb_title.SearchTitleTypeShow
+	}
+
+	tids, err := imdb_title.SearchIds(query, titleType, year, false, maxImdbSearchResults)
+	if err != nil {
+		return nil, err
+	}
+	files := []ImdbFile{}
+	if len(tids) == 0 {
+		return files, nil
+	}
+
+	titles, err := imdb_title.ListByIds(tids)
+	if err != nil {
+		return nil, err
+	}
+	titleByTId := make(map[string]*imdb_title.IMDBTitle, len(titles))
+	for i := range titles {
+		titleByTId[titles[i].TId] = &titles[i]
+	}
+	for _, tid := range tids {
+		if title, ok := titleByTId[tid]; ok {
+			files = append(files, newImdbFile(title))
+		}
+	}
+	return files, nil
+}
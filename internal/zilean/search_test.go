@@ -0,0 +1,61 @@
+package zilean
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToTitleQuery(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		query string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"The Matrix", "*the*matrix*"},
+		{"Spider-Man: No Way Home", "*spider*man*no*way*home*"},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.query, toTitleQuery(tc.text))
+		})
+	}
+}
+
+func TestFilteredParamsMatches(t *testing.T) {
+	ti := &torrent_info.TorrentInfo{
+		Category:   torrent_info.TorrentInfoCategorySeries,
+		Year:       2011,
+		Resolution: "1080p",
+		Seasons:    torrent_info.CommaSeperatedInt{1},
+		Episodes:   torrent_info.CommaSeperatedInt{1, 2},
+		Languages:  torrent_info.CommaSeperatedString{"en"},
+	}
+	pack := &torrent_info.TorrentInfo{
+		Seasons: torrent_info.CommaSeperatedInt{1},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		params FilteredParams
+		ti     *torrent_info.TorrentInfo
+		match  bool
+	}{
+		{"empty", FilteredParams{}, ti, true},
+		{"episode", FilteredParams{Season: 1, Episode: 2}, ti, true},
+		{"other season", FilteredParams{Season: 2}, ti, false},
+		{"other episode", FilteredParams{Season: 1, Episode: 3}, ti, false},
+		{"season pack", FilteredParams{Season: 1, Episode: 3}, pack, true},
+		{"year", FilteredParams{Year: 2012}, ti, false},
+		{"language", FilteredParams{Language: "EN"}, ti, true},
+		{"other language", FilteredParams{Language: "fr"}, ti, false},
+		{"resolution", FilteredParams{Resolution: "720p"}, ti, false},
+		{"category", FilteredParams{Category: "tvSeries"}, ti, true},
+		{"other category", FilteredParams{Category: "movie"}, ti, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.match, tc.params.matches(tc.ti))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -10,21 +12,31 @@ import (
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/cli"
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/db_migrator"
 	"github.com/MunifTanjim/stremthru/internal/endpoint"
 	"github.com/MunifTanjim/stremthru/internal/job"
 	newznab_indexer "github.com/MunifTanjim/stremthru/internal/newznab/indexer"
 	newznab_stats "github.com/MunifTanjim/stremthru/internal/newznab/stats"
+	"github.com/MunifTanjim/stremthru/internal/peer_token"
 	"github.com/MunifTanjim/stremthru/internal/posthog"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+	usenet_proxy "github.com/MunifTanjim/stremthru/internal/usenet/proxy"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/internal/worker"
 	"github.com/MunifTanjim/stremthru/store"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:], &cli.Config{
+			RunSchemaMigration: RunSchemaMigration,
+		}))
+	}
+
 	config.PrintConfig(&config.AppState{
 		StoreNames: util.SliceMapToString(store.StoreNames),
 	})
@@ -35,6 +47,13 @@ func main() {
 	database := db.Open()
 	defer db.Close()
 	db.Ping()
+	if err := db_migrator.FinishSwitch(database); err != nil {
+		if errors.Is(err, db.ErrDatabaseSwitched) {
+			db.Close()
+			restart()
+		}
+		log.Fatalf("database switch: %v\n", err)
+	}
 	RunSchemaMigration(database.URI, database)
 
 	if err := newznab_indexer.LoadIndexerIDByHostnameMap(); err != nil {
@@ -47,6 +66,12 @@ func main() {
 	newznab_stats.InitBackgroundJob()
 	defer newznab_stats.CleanupBackgroundJob()
 
+	defer func() {
+		if err := peer_token.FlushUsage(); err != nil {
+			log.Printf("failed to flush peer token usage: %v", err)
+		}
+	}()
+
 	stopWorkers := worker.InitWorkers()
 	defer stopWorkers()
 
@@ -68,6 +93,7 @@ func main() {
 	endpoint.AddTorznabEndpoints(mux)
 	endpoint.AddNewznabEndpoints(mux)
 	endpoint.AddExperimentEndpoints(mux)
+	endpoint.AddAdminEndpoints(mux)
 	endpoint.AddEndpoints(mux)
 
 	handler := shared.RootServerContext(mux)
@@ -88,6 +114,24 @@ func main() {
 		}
 	}()
 
+	if config.Feature.HasNewz() && config.Newz.NNTPListenAddr != "" {
+		nntpServer := usenet_proxy.NewServer(&usenet_proxy.Config{
+			Addr:    config.Newz.NNTPListenAddr,
+			GetPool: usenetmanager.GetPool,
+			Authenticate: func(user, pass string) bool {
+				return pass != "" && subtle.ConstantTimeCompare([]byte(config.Auth.GetPassword(user)), []byte(pass)) == 1
+			},
+		})
+		defer nntpServer.Close()
+
+		go func() {
+			log.Println("stremthru nntp listening on " + config.Newz.NNTPListenAddr)
+			if err := nntpServer.ListenAndServe(); err != nil && err != usenet_proxy.ErrServerClosed {
+				log.Fatalf("failed to start stremthru nntp: %v", err)
+			}
+		}()
+	}
+
 	sig := <-quit
 	log.Printf("received signal: %v, shutting down...", sig)
 
@@ -98,3 +142,16 @@ func main() {
 		log.Printf("server shutdown error: %v", err)
 	}
 }
+
+// restart replaces the process with a fresh one, with the same arguments
+// and environment.
+func restart() {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("failed to restart: %v\n", err)
+	}
+	log.Println("restarting stremthru")
+	if err := syscall.Exec(executable, os.Args, os.Environ()); err != nil {
+		log.Fatalf("failed to restart: %v\n", err)
+	}
+}
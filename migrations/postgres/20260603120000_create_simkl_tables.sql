@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."simkl_list" (
+    "id" text NOT NULL,
+    "user_id" text NOT NULL,
+    "name" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."simkl_item" (
+    "id" int NOT NULL,
+    "type" text NOT NULL,
+    "title" text NOT NULL,
+    "year" int NOT NULL,
+    "poster" text NOT NULL,
+    "imdb_id" text NOT NULL,
+    "tmdb_id" text NOT NULL,
+    "tvdb_id" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."simkl_list_item" (
+    "list_id" text NOT NULL,
+    "item_id" int NOT NULL,
+    "idx" int NOT NULL,
+    PRIMARY KEY ("list_id", "item_id")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."simkl_list_item";
+DROP TABLE IF EXISTS "public"."simkl_item";
+DROP TABLE IF EXISTS "public"."simkl_list";
+-- +goose StatementEnd
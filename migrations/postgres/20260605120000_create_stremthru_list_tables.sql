@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."stremthru_list" (
+    "id" text NOT NULL,
+    "name" text NOT NULL,
+    "description" text NOT NULL DEFAULT '',
+    "owner" text NOT NULL,
+    "secret" text NOT NULL,
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "stremthru_list_idx_owner" ON "public"."stremthru_list" ("owner");
+
+CREATE TABLE IF NOT EXISTS "public"."stremthru_list_item" (
+    "list_id" text NOT NULL,
+    "id" text NOT NULL,
+    "type" text NOT NULL,
+    "name" text NOT NULL DEFAULT '',
+    "poster" text NOT NULL DEFAULT '',
+    "year" int NOT NULL DEFAULT 0,
+    "idx" int NOT NULL,
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("list_id", "id")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."stremthru_list_item";
+DROP INDEX IF EXISTS "public"."stremthru_list_idx_owner";
+DROP TABLE IF EXISTS "public"."stremthru_list";
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."usenet_server"
+  ADD COLUMN "proxy" text NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."usenet_server"
+  DROP COLUMN IF EXISTS "proxy";
+-- +goose StatementEnd
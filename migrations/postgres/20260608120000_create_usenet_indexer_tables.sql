@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_group" (
+    "name" text NOT NULL,
+    "last_number" bigint NOT NULL DEFAULT 0,
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_release" (
+    "id" text NOT NULL,
+    "name" text NOT NULL,
+    "poster" text NOT NULL DEFAULT '',
+    "group_name" text NOT NULL DEFAULT '',
+    "file_count" integer NOT NULL DEFAULT 0,
+    "files" integer NOT NULL DEFAULT 0,
+    "size" bigint NOT NULL DEFAULT 0,
+    "date" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "complete" boolean NOT NULL DEFAULT false,
+    "category" integer NOT NULL DEFAULT 0,
+    "imdb_id" text NOT NULL DEFAULT '',
+    "season" text NOT NULL DEFAULT '',
+    "episode" text NOT NULL DEFAULT '',
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "usenet_indexer_release_idx_imdb_id" ON "public"."usenet_indexer_release" ("imdb_id");
+CREATE INDEX IF NOT EXISTS "usenet_indexer_release_idx_complete_date" ON "public"."usenet_indexer_release" ("complete", "date");
+
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_file" (
+    "id" text NOT NULL,
+    "release_id" text NOT NULL,
+    "subject" text NOT NULL,
+    "name" text NOT NULL DEFAULT '',
+    "number" integer NOT NULL DEFAULT 0,
+    "segment_count" integer NOT NULL DEFAULT 0,
+    "poster" text NOT NULL DEFAULT '',
+    "date" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "groups" text NOT NULL DEFAULT '',
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "usenet_indexer_file_idx_release_id" ON "public"."usenet_indexer_file" ("release_id");
+
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_segment" (
+    "file_id" text NOT NULL,
+    "number" integer NOT NULL,
+    "bytes" bigint NOT NULL DEFAULT 0,
+    "message_id" text NOT NULL,
+    PRIMARY KEY ("file_id", "number")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."usenet_indexer_segment";
+DROP INDEX IF EXISTS "public"."usenet_indexer_file_idx_release_id";
+DROP TABLE IF EXISTS "public"."usenet_indexer_file";
+DROP INDEX IF EXISTS "public"."usenet_indexer_release_idx_complete_date";
+DROP INDEX IF EXISTS "public"."usenet_indexer_release_idx_imdb_id";
+DROP TABLE IF EXISTS "public"."usenet_indexer_release";
+DROP TABLE IF EXISTS "public"."usenet_indexer_group";
+-- +goose StatementEnd
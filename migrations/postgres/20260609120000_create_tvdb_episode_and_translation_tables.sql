@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."tvdb_episode" (
+    "id" int NOT NULL,
+    "series_id" int NOT NULL,
+    "season" int NOT NULL,
+    "number" int NOT NULL,
+    "absolute_number" int NOT NULL,
+    "name" text NOT NULL,
+    "overview" text NOT NULL,
+    "aired" text NOT NULL,
+    "runtime" int NOT NULL,
+    "image" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "tvdb_episode_idx_series_id" ON "public"."tvdb_episode" ("series_id");
+
+CREATE TABLE IF NOT EXISTS "public"."tvdb_translation" (
+    "item_id" int NOT NULL,
+    "item_type" text NOT NULL,
+    "lang" text NOT NULL,
+    "name" text NOT NULL,
+    "overview" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("item_id", "item_type", "lang")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."tvdb_translation";
+DROP INDEX IF EXISTS "tvdb_episode_idx_series_id";
+DROP TABLE IF EXISTS "public"."tvdb_episode";
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."torrent_info"
+  ADD COLUMN "seeders_updated_at" timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."torrent_info"
+  DROP COLUMN IF EXISTS "seeders_updated_at";
+-- +goose StatementEnd
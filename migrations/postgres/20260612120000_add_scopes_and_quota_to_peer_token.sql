@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."peer_token"
+  ADD COLUMN "scopes" text NOT NULL DEFAULT ',read-torrents,push-torrents,check-magnet-cache,zilean,',
+  ADD COLUMN "rate_limit_config_id" text NULL,
+  ADD COLUMN "expires_at" timestamptz,
+  ADD COLUMN "last_used_at" timestamptz,
+  ADD COLUMN "usage_count" bigint NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."peer_token"
+  DROP COLUMN IF EXISTS "usage_count",
+  DROP COLUMN IF EXISTS "last_used_at",
+  DROP COLUMN IF EXISTS "expires_at",
+  DROP COLUMN IF EXISTS "rate_limit_config_id",
+  DROP COLUMN IF EXISTS "scopes";
+-- +goose StatementEnd
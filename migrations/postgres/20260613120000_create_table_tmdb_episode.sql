@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."tmdb_episode" (
+    "id" int NOT NULL,
+    "series_id" int NOT NULL,
+    "season" int NOT NULL,
+    "number" int NOT NULL,
+    "name" text NOT NULL,
+    "overview" text NOT NULL,
+    "air_date" text NOT NULL,
+    "runtime" int NOT NULL,
+    "still" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "tmdb_episode_idx_series_id" ON "public"."tmdb_episode" ("series_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "tmdb_episode_idx_series_id";
+DROP TABLE IF EXISTS "public"."tmdb_episode";
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- article numbers are per server, the last numbers saved without the
+-- provider are dropped, the groups are scanned again from the backfill
+DROP TABLE IF EXISTS "public"."usenet_indexer_group";
+
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_group" (
+    "provider_id" text NOT NULL,
+    "name" text NOT NULL,
+    "last_number" bigint NOT NULL DEFAULT 0,
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("provider_id", "name")
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE "public"."usenet_indexer_group" RENAME TO "_usenet_indexer_group_new";
+
+CREATE TABLE IF NOT EXISTS "public"."usenet_indexer_group" (
+    "name" text NOT NULL,
+    "last_number" bigint NOT NULL DEFAULT 0,
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("name")
+);
+
+INSERT INTO "public"."usenet_indexer_group" ("name", "last_number", "cat", "uat")
+  SELECT "name", max("last_number"), min("cat"), max("uat")
+  FROM "public"."_usenet_indexer_group_new"
+  GROUP BY "name";
+
+DROP TABLE "public"."_usenet_indexer_group_new";
+
+-- +goose StatementEnd
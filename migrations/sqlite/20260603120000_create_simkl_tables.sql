@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `simkl_list` (
+    `id` varchar NOT NULL,
+    `user_id` varchar NOT NULL,
+    `name` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `simkl_item` (
+    `id` int NOT NULL,
+    `type` varchar NOT NULL,
+    `title` varchar NOT NULL,
+    `year` int NOT NULL,
+    `poster` varchar NOT NULL,
+    `imdb_id` varchar NOT NULL,
+    `tmdb_id` varchar NOT NULL,
+    `tvdb_id` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `simkl_list_item` (
+    `list_id` varchar NOT NULL,
+    `item_id` int NOT NULL,
+    `idx` int NOT NULL,
+    PRIMARY KEY (`list_id`, `item_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `simkl_list_item`;
+DROP TABLE IF EXISTS `simkl_item`;
+DROP TABLE IF EXISTS `simkl_list`;
+-- +goose StatementEnd
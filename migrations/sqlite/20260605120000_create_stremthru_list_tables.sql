@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `stremthru_list` (
+    `id` varchar NOT NULL,
+    `name` varchar NOT NULL,
+    `description` varchar NOT NULL DEFAULT '',
+    `owner` varchar NOT NULL,
+    `secret` varchar NOT NULL,
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `stremthru_list_idx_owner` ON `stremthru_list` (`owner`);
+
+CREATE TABLE IF NOT EXISTS `stremthru_list_item` (
+    `list_id` varchar NOT NULL,
+    `id` varchar NOT NULL,
+    `type` varchar NOT NULL,
+    `name` varchar NOT NULL DEFAULT '',
+    `poster` varchar NOT NULL DEFAULT '',
+    `year` int NOT NULL DEFAULT 0,
+    `idx` int NOT NULL,
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`list_id`, `id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `stremthru_list_item`;
+DROP INDEX IF EXISTS `stremthru_list_idx_owner`;
+DROP TABLE IF EXISTS `stremthru_list`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `nzb_info` ADD COLUMN `health` jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `nzb_info` DROP COLUMN `health`;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `usenet_server`
+  ADD COLUMN `proxy` varchar NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `usenet_server`
+  DROP COLUMN `proxy`;
+-- +goose StatementEnd
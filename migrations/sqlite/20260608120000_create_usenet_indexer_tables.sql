@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `usenet_indexer_group` (
+    `name` varchar NOT NULL,
+    `last_number` int NOT NULL DEFAULT 0,
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `usenet_indexer_release` (
+    `id` varchar NOT NULL,
+    `name` varchar NOT NULL,
+    `poster` varchar NOT NULL DEFAULT '',
+    `group_name` varchar NOT NULL DEFAULT '',
+    `file_count` integer NOT NULL DEFAULT 0,
+    `files` integer NOT NULL DEFAULT 0,
+    `size` int NOT NULL DEFAULT 0,
+    `date` datetime NOT NULL DEFAULT (unixepoch()),
+    `complete` boolean NOT NULL DEFAULT false,
+    `category` integer NOT NULL DEFAULT 0,
+    `imdb_id` varchar NOT NULL DEFAULT '',
+    `season` varchar NOT NULL DEFAULT '',
+    `episode` varchar NOT NULL DEFAULT '',
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `usenet_indexer_release_idx_imdb_id` ON `usenet_indexer_release` (`imdb_id`);
+CREATE INDEX IF NOT EXISTS `usenet_indexer_release_idx_complete_date` ON `usenet_indexer_release` (`complete`, `date`);
+
+CREATE TABLE IF NOT EXISTS `usenet_indexer_file` (
+    `id` varchar NOT NULL,
+    `release_id` varchar NOT NULL,
+    `subject` varchar NOT NULL,
+    `name` varchar NOT NULL DEFAULT '',
+    `number` integer NOT NULL DEFAULT 0,
+    `segment_count` integer NOT NULL DEFAULT 0,
+    `poster` varchar NOT NULL DEFAULT '',
+    `date` datetime NOT NULL DEFAULT (unixepoch()),
+    `groups` varchar NOT NULL DEFAULT '',
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `usenet_indexer_file_idx_release_id` ON `usenet_indexer_file` (`release_id`);
+
+CREATE TABLE IF NOT EXISTS `usenet_indexer_segment` (
+    `file_id` varchar NOT NULL,
+    `number` integer NOT NULL,
+    `bytes` int NOT NULL DEFAULT 0,
+    `message_id` varchar NOT NULL,
+    PRIMARY KEY (`file_id`, `number`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `usenet_indexer_segment`;
+DROP INDEX IF EXISTS `usenet_indexer_file_idx_release_id`;
+DROP TABLE IF EXISTS `usenet_indexer_file`;
+DROP INDEX IF EXISTS `usenet_indexer_release_idx_complete_date`;
+DROP INDEX IF EXISTS `usenet_indexer_release_idx_imdb_id`;
+DROP TABLE IF EXISTS `usenet_indexer_release`;
+DROP TABLE IF EXISTS `usenet_indexer_group`;
+-- +goose StatementEnd
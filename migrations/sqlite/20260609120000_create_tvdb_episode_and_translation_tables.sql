@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `tvdb_episode` (
+    `id` int NOT NULL,
+    `series_id` int NOT NULL,
+    `season` int NOT NULL,
+    `number` int NOT NULL,
+    `absolute_number` int NOT NULL,
+    `name` varchar NOT NULL,
+    `overview` varchar NOT NULL,
+    `aired` varchar NOT NULL,
+    `runtime` int NOT NULL,
+    `image` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `tvdb_episode_idx_series_id` ON `tvdb_episode` (`series_id`);
+
+CREATE TABLE IF NOT EXISTS `tvdb_translation` (
+    `item_id` int NOT NULL,
+    `item_type` varchar NOT NULL,
+    `lang` varchar NOT NULL,
+    `name` varchar NOT NULL,
+    `overview` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`item_id`, `item_type`, `lang`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `tvdb_translation`;
+DROP INDEX IF EXISTS `tvdb_episode_idx_series_id`;
+DROP TABLE IF EXISTS `tvdb_episode`;
+-- +goose StatementEnd
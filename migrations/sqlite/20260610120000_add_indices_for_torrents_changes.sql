@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS `torrent_info_idx_updated_at_hash` ON `torrent_info` (`updated_at`, `hash`);
+CREATE INDEX IF NOT EXISTS `imdb_torrent_idx_uat` ON `imdb_torrent` (`uat`);
+CREATE INDEX IF NOT EXISTS `anidb_torrent_idx_uat` ON `anidb_torrent` (`uat`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS `anidb_torrent_idx_uat`;
+DROP INDEX IF EXISTS `imdb_torrent_idx_uat`;
+DROP INDEX IF EXISTS `torrent_info_idx_updated_at_hash`;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `torrent_info`
+  ADD COLUMN `seeders_updated_at` datetime;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `torrent_info`
+  DROP COLUMN `seeders_updated_at`;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `peer_token`
+  ADD COLUMN `scopes` varchar NOT NULL DEFAULT ',read-torrents,push-torrents,check-magnet-cache,zilean,';
+ALTER TABLE `peer_token`
+  ADD COLUMN `rate_limit_config_id` varchar NULL;
+ALTER TABLE `peer_token`
+  ADD COLUMN `expires_at` datetime;
+ALTER TABLE `peer_token`
+  ADD COLUMN `last_used_at` datetime;
+ALTER TABLE `peer_token`
+  ADD COLUMN `usage_count` int NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `peer_token`
+  DROP COLUMN `usage_count`;
+ALTER TABLE `peer_token`
+  DROP COLUMN `last_used_at`;
+ALTER TABLE `peer_token`
+  DROP COLUMN `expires_at`;
+ALTER TABLE `peer_token`
+  DROP COLUMN `rate_limit_config_id`;
+ALTER TABLE `peer_token`
+  DROP COLUMN `scopes`;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `tmdb_episode` (
+    `id` int NOT NULL,
+    `series_id` int NOT NULL,
+    `season` int NOT NULL,
+    `number` int NOT NULL,
+    `name` varchar NOT NULL,
+    `overview` varchar NOT NULL,
+    `air_date` varchar NOT NULL,
+    `runtime` int NOT NULL,
+    `still` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `tmdb_episode_idx_series_id` ON `tmdb_episode` (`series_id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS `tmdb_episode_idx_series_id`;
+DROP TABLE IF EXISTS `tmdb_episode`;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- article numbers are per server, the last numbers saved without the
+-- provider are dropped, the groups are scanned again from the backfill
+DROP TABLE IF EXISTS `usenet_indexer_group`;
+
+CREATE TABLE IF NOT EXISTS `usenet_indexer_group` (
+    `provider_id` varchar NOT NULL,
+    `name` varchar NOT NULL,
+    `last_number` int NOT NULL DEFAULT 0,
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`provider_id`, `name`)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE `usenet_indexer_group` RENAME TO `_usenet_indexer_group_new`;
+
+CREATE TABLE IF NOT EXISTS `usenet_indexer_group` (
+    `name` varchar NOT NULL,
+    `last_number` int NOT NULL DEFAULT 0,
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (`name`)
+);
+
+INSERT INTO `usenet_indexer_group` (`name`, `last_number`, `cat`, `uat`)
+  SELECT `name`, max(`last_number`), min(`cat`), max(`uat`)
+  FROM `_usenet_indexer_group_new`
+  GROUP BY `name`;
+
+DROP TABLE `_usenet_indexer_group_new`;
+
+-- +goose StatementEnd
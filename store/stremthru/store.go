@@ -131,7 +131,7 @@ func (c *StoreClient) CheckNewz(params *store.CheckNewzParams) (*store.CheckNewz
 		}
 		if info, ok := infoByHash[hash]; ok && info.Streamable {
 			item.Status = store.NewzStatusCached
-			item.Files = flattenContentFiles(info.ContentFiles.Data, "")
+			item.Files = FlattenContentFiles(info.ContentFiles.Data, "")
 		}
 		items[i] = item
 	}
@@ -183,7 +183,9 @@ func (c *StoreClient) AddNewz(params *store.AddNewzParams) (*store.AddNewzData,
 	return data, nil
 }
 
-func flattenContentFiles(files []usenet_pool.NZBContentFile, parentPath string) []store.NewzFile {
+// FlattenContentFiles lists the streamable files of the content, with the
+// paths to stream them by.
+func FlattenContentFiles(files []usenet_pool.NZBContentFile, parentPath string) []store.NewzFile {
 	var result []store.NewzFile
 	for _, f := range files {
 		fileName := f.Name
@@ -210,7 +212,7 @@ func flattenContentFiles(files []usenet_pool.NZBContentFile, parentPath string)
 					Size: f.Size,
 				})
 			}
-			result = append(result, flattenContentFiles(f.Files, filePath)...)
+			result = append(result, FlattenContentFiles(f.Files, filePath)...)
 		} else {
 			if f.Size == 0 || !f.Streamable {
 				continue
@@ -318,7 +320,7 @@ func (c *StoreClient) GetNewz(params *store.GetNewzParams) (*store.GetNewzData,
 		data.AddedAt = info.CAt.Time
 
 		if info.Streamable {
-			files := flattenContentFiles(info.ContentFiles.Data, "")
+			files := FlattenContentFiles(info.ContentFiles.Data, "")
 			for i := range files {
 				file := &files[i]
 				file.Link = LockedFileLink("").Create(info.Hash, file.Path)